@@ -1,10 +1,12 @@
 package main
 
 import (
+	"errors"
 	"log/slog"
 	"os"
 
 	"github.com/trezorg/k8spodsmetrics/internal/adapters/stdin"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"github.com/urfave/cli/v2"
 )
 
@@ -26,6 +28,11 @@ func main() {
 	}
 
 	if err := run(os.Args); err != nil {
+		// Partial results are rendered along with their warnings already.
+		var partialErr *partial.Error
+		if errors.As(err, &partialErr) {
+			os.Exit(partial.ExitCode)
+		}
 		slog.Error("command failed", "error", err)
 		os.Exit(1)
 	}
@@ -1,31 +1,50 @@
 package stdin
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 
 	metricstable "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/table/metricsresources"
 	nodestable "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/table/noderesources"
 	"github.com/trezorg/k8spodsmetrics/internal/alert"
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
+	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
 	"github.com/trezorg/k8spodsmetrics/internal/output"
+	"github.com/trezorg/k8spodsmetrics/internal/recommendations"
+	"github.com/trezorg/k8spodsmetrics/internal/recording"
 	"github.com/trezorg/k8spodsmetrics/internal/resources"
 	metricssorting "github.com/trezorg/k8spodsmetrics/internal/sorting/metricsresources"
+	namespacessorting "github.com/trezorg/k8spodsmetrics/internal/sorting/namespaces"
 	nodesorting "github.com/trezorg/k8spodsmetrics/internal/sorting/noderesources"
+	workloadssorting "github.com/trezorg/k8spodsmetrics/internal/sorting/workloads"
 	"github.com/trezorg/k8spodsmetrics/internal/tableview"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	"github.com/urfave/cli/v2"
 )
 
 type actionFlags struct {
-	reverseSet     bool
-	watchSet       bool
-	watchPeriodSet bool
-	timeoutSet     bool
-	outputSet      bool
-	tableViewSet   bool
-	alertSet       bool
-	columnsSet     bool
-	sortingSet     bool
-	resourcesSet   bool
-	resources      []string
+	reverseSet           bool
+	watchSet             bool
+	watchPeriodSet       bool
+	timeoutSet           bool
+	outputSet            bool
+	tableViewSet         bool
+	alertSet             bool
+	metricsSourceSet     bool
+	metricsMaxAgeSet     bool
+	watchHistorySet      bool
+	columnsSet           bool
+	sortingSet           bool
+	resourcesSet         bool
+	resources            []string
+	includeTerminatedSet bool
+	groupByQOSSet        bool
+	schedulableOnlySet   bool
+	insecureSkipTLSSet   bool
+	retriesSet           bool
+	asGroups             []string
 }
 
 func loadConfigBefore(cfg *commonConfig) func(*cli.Context) error {
@@ -38,17 +57,26 @@ func loadConfigBefore(cfg *commonConfig) func(*cli.Context) error {
 
 func parseActionFlags(c *cli.Context) actionFlags {
 	return actionFlags{
-		reverseSet:     c.IsSet("reverse"),
-		watchSet:       c.IsSet("watch"),
-		watchPeriodSet: c.IsSet("watch-period"),
-		timeoutSet:     c.IsSet("timeout"),
-		outputSet:      c.IsSet("output"),
-		tableViewSet:   c.IsSet("table-view"),
-		alertSet:       c.IsSet("alert"),
-		columnsSet:     c.IsSet("columns"),
-		sortingSet:     c.IsSet("sorting"),
-		resourcesSet:   c.IsSet(flagNameResources),
-		resources:      c.StringSlice(flagNameResources),
+		reverseSet:           c.IsSet("reverse"),
+		watchSet:             c.IsSet("watch"),
+		watchPeriodSet:       c.IsSet("watch-period"),
+		timeoutSet:           c.IsSet("timeout"),
+		outputSet:            c.IsSet("output"),
+		tableViewSet:         c.IsSet("table-view"),
+		alertSet:             c.IsSet("alert"),
+		metricsSourceSet:     c.IsSet(flagNameMetricsSource),
+		metricsMaxAgeSet:     c.IsSet(flagNameMetricsMaxAge),
+		watchHistorySet:      c.IsSet(flagNameWatchHistory),
+		columnsSet:           c.IsSet("columns"),
+		sortingSet:           c.IsSet("sorting"),
+		resourcesSet:         c.IsSet(flagNameResources),
+		resources:            c.StringSlice(flagNameResources),
+		includeTerminatedSet: c.IsSet(flagNameIncludeTerminated),
+		groupByQOSSet:        c.IsSet(flagNameGroupByQOS),
+		schedulableOnlySet:   c.IsSet(flagNameSchedulableOnly),
+		insecureSkipTLSSet:   c.IsSet(flagNameInsecureSkipTLS),
+		retriesSet:           c.IsSet(flagNameRetries),
+		asGroups:             c.StringSlice(flagNameAsGroup),
 	}
 }
 
@@ -63,12 +91,25 @@ func resolveCommonConfig(cfg commonConfig, flags actionFlags) commonConfig {
 	if !flags.tableViewSet {
 		mergeCandidate.TableView = ""
 	}
+	if !flags.metricsSourceSet {
+		mergeCandidate.MetricsSource = ""
+	}
+	if !flags.metricsMaxAgeSet {
+		mergeCandidate.MetricsMaxAge = 0
+	}
 	if !flags.watchPeriodSet {
 		mergeCandidate.WatchPeriod = 0
 	}
 	if !flags.columnsSet {
 		mergeCandidate.Columns = nil
 	}
+	if !flags.retriesSet {
+		mergeCandidate.Retries = 0
+	}
+	if !flags.watchHistorySet {
+		mergeCandidate.WatchHistory = 0
+	}
+	mergeCandidate.Connection.AsGroups = flags.asGroups
 
 	mergedCommon := applyCommonConfig(&mergeCandidate, cfg.fileConfig, flags.watchSet, flags.timeoutSet)
 	if mergedCommon.Output == "" {
@@ -86,22 +127,59 @@ func resolveCommonConfig(cfg commonConfig, flags actionFlags) commonConfig {
 	if mergedCommon.WatchPeriod == 0 {
 		mergedCommon.WatchPeriod = defaultWatchPeriodSeconds
 	}
+	if mergedCommon.MetricsSource == "" {
+		mergedCommon.MetricsSource = string(metricssource.MetricsServer)
+	}
+	// An explicit zero, from the flag or the file, disables the staleness
+	// check and must survive the merge.
+	metricsMaxAge := uintOr(mergedCommon.MetricsMaxAge, defaultMetricsMaxAgeSeconds)
+	if flags.metricsMaxAgeSet {
+		metricsMaxAge = cfg.MetricsMaxAge
+	}
+	// An explicit zero hides the trends and must survive the merge.
+	watchHistory := uintOr(mergedCommon.WatchHistory, defaultWatchHistory)
+	if flags.watchHistorySet {
+		watchHistory = cfg.WatchHistory
+	}
+	// An explicit zero disables retries and must survive the merge.
+	retries := uintOr(mergedCommon.Retry.Retries, retry.DefaultRetries)
+	if flags.retriesSet {
+		retries = cfg.Retries
+	}
+	mergedCommon.Retry.Retries = nil
+	if flags.insecureSkipTLSSet {
+		mergedCommon.InsecureSkipTLSVerify = cfg.Connection.InsecureSkipTLSVerify
+	}
 
 	return commonConfig{
-		ConfigFile:   cfg.ConfigFile,
-		KubeConfig:   mergedCommon.KubeConfig,
-		KubeContext:  mergedCommon.KubeContext,
-		Output:       mergedCommon.Output,
-		TableView:    mergedCommon.TableView,
-		Alert:        mergedCommon.Alert,
-		WatchPeriod:  mergedCommon.WatchPeriod,
-		WatchMetrics: mergedCommon.WatchMetrics,
-		Columns:      mergedCommon.Columns,
-		Timeout:      mergedCommon.Timeout,
-		fileConfig:   cfg.fileConfig,
+		ConfigFile:    cfg.ConfigFile,
+		KubeConfig:    mergedCommon.KubeConfig,
+		KubeContext:   mergedCommon.KubeContext,
+		Output:        mergedCommon.Output,
+		TableView:     mergedCommon.TableView,
+		Alert:         mergedCommon.Alert,
+		MetricsSource: mergedCommon.MetricsSource,
+		MetricsMaxAge: metricsMaxAge,
+		WatchPeriod:   mergedCommon.WatchPeriod,
+		WatchMetrics:  mergedCommon.WatchMetrics,
+		WatchHistory:  watchHistory,
+		Columns:       mergedCommon.Columns,
+		Timeout:       mergedCommon.Timeout,
+		Prometheus:    mergedCommon.Prometheus,
+		Retries:       retries,
+		Retry:         mergedCommon.Retry,
+		Connection:    mergedCommon.Connection,
+		fileConfig:    cfg.fileConfig,
 	}
 }
 
+func uintOr(value *uint, fallback uint) uint {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
+
 func mergedResources(cliResources []string, configResources []string) []string {
 	if len(cliResources) == 0 && len(configResources) > 0 {
 		return configResources
@@ -122,11 +200,16 @@ func validateTableViewColumns(view tableview.View, cols []string) error {
 func resolveSummaryActionConfig(c *cli.Context, cfg commonConfig) summaryConfig {
 	flags := parseActionFlags(c)
 	resolved := summaryConfig{
-		Name:      c.String(flagNameName),
-		Label:     c.String("label"),
-		Sorting:   c.String("sorting"),
-		Reverse:   c.Bool("reverse"),
-		Resources: flags.resources,
+		Name:              c.String(flagNameName),
+		Label:             c.String("label"),
+		Sorting:           c.String("sorting"),
+		Reverse:           c.Bool("reverse"),
+		Resources:         flags.resources,
+		IncludeTerminated: c.Bool(flagNameIncludeTerminated),
+		GroupByQOS:        c.Bool(flagNameGroupByQOS),
+		SchedulableOnly:   c.Bool(flagNameSchedulableOnly),
+		GroupByLabel:      c.String(flagNameGroupByLabel),
+		Record:            c.String(flagNameRecord),
 	}
 	resolved.commonConfig = resolveCommonConfig(cfg, flags)
 	if flags.columnsSet {
@@ -139,11 +222,15 @@ func resolveSummaryActionConfig(c *cli.Context, cfg commonConfig) summaryConfig
 		resolved.Resources = nil
 	}
 
-	mergedSummary := applySummaryConfig(&resolved, resolved.fileConfig, flags.reverseSet)
+	mergedSummary := applySummaryConfig(&resolved, resolved.fileConfig, flags)
 	resolved.Name = mergedSummary.Name
 	resolved.Label = mergedSummary.Label
 	resolved.Sorting = mergedSummary.Sorting
 	resolved.Reverse = mergedSummary.Reverse
+	resolved.IncludeTerminated = mergedSummary.IncludeTerminated
+	resolved.GroupByQOS = mergedSummary.GroupByQOS
+	resolved.SchedulableOnly = mergedSummary.SchedulableOnly
+	resolved.GroupByLabel = mergedSummary.GroupByLabel
 	if resolved.Sorting == "" {
 		resolved.Sorting = string(nodesorting.Name)
 	}
@@ -159,13 +246,18 @@ func resolveSummaryActionConfig(c *cli.Context, cfg commonConfig) summaryConfig
 func resolvePodsActionConfig(c *cli.Context, cfg commonConfig) podConfig {
 	flags := parseActionFlags(c)
 	resolved := podConfig{
-		Namespaces:    c.StringSlice(flagNameNamespace),
-		Label:         c.String("label"),
-		FieldSelector: c.String("field-selector"),
-		Sorting:       c.String("sorting"),
-		Reverse:       c.Bool("reverse"),
-		Nodes:         c.StringSlice("node"),
-		Resources:     flags.resources,
+		Namespaces:     c.StringSlice(flagNameNamespace),
+		Label:          c.String("label"),
+		FieldSelector:  c.String("field-selector"),
+		Sorting:        c.String("sorting"),
+		Reverse:        c.Bool("reverse"),
+		Nodes:          c.StringSlice("node"),
+		Resources:      flags.resources,
+		QOSClasses:     c.StringSlice("qos"),
+		AllNamespaces:  c.Bool(flagNameAllNamespaces),
+		PartialResults: c.Bool(flagNamePartialResults),
+		Efficiency:     c.Bool(flagNameEfficiency),
+		Record:         c.String(flagNameRecord),
 	}
 	resolved.commonConfig = resolveCommonConfig(cfg, flags)
 	if flags.columnsSet {
@@ -185,6 +277,7 @@ func resolvePodsActionConfig(c *cli.Context, cfg commonConfig) podConfig {
 	resolved.Nodes = mergedPods.Nodes
 	resolved.Sorting = mergedPods.Sorting
 	resolved.Reverse = mergedPods.Reverse
+	resolved.QOSClasses = mergedPods.QOS
 	if resolved.Sorting == "" {
 		resolved.Sorting = string(metricssorting.Namespace)
 	}
@@ -197,6 +290,119 @@ func resolvePodsActionConfig(c *cli.Context, cfg commonConfig) podConfig {
 	return resolved
 }
 
+func resolveWorkloadsActionConfig(c *cli.Context, cfg commonConfig) workloadConfig {
+	flags := parseActionFlags(c)
+	resolved := workloadConfig{
+		Namespaces:    c.StringSlice(flagNameNamespace),
+		Label:         c.String("label"),
+		FieldSelector: c.String("field-selector"),
+		Sorting:       c.String("sorting"),
+		Reverse:       c.Bool("reverse"),
+		Nodes:         c.StringSlice("node"),
+		Resources:     flags.resources,
+		AllNamespaces: c.Bool(flagNameAllNamespaces),
+	}
+	resolved.commonConfig = resolveCommonConfig(cfg, flags)
+	if flags.columnsSet {
+		resolved.Columns = c.StringSlice("columns")
+	}
+	if !flags.sortingSet {
+		resolved.Sorting = ""
+	}
+	if !flags.resourcesSet {
+		resolved.Resources = nil
+	}
+
+	mergedWorkloads := applyWorkloadsConfig(&resolved, resolved.fileConfig, flags.reverseSet)
+	resolved.Namespaces = mergedWorkloads.Namespaces
+	resolved.Label = mergedWorkloads.Label
+	resolved.FieldSelector = mergedWorkloads.FieldSelector
+	resolved.Nodes = mergedWorkloads.Nodes
+	resolved.Sorting = mergedWorkloads.Sorting
+	resolved.Reverse = mergedWorkloads.Reverse
+	if resolved.Sorting == "" {
+		resolved.Sorting = string(workloadssorting.Namespace)
+	}
+	resourcesFromCLI := []string(nil)
+	if flags.resourcesSet {
+		resourcesFromCLI = flags.resources
+	}
+	resolved.Resources = mergedResources(resourcesFromCLI, mergedWorkloads.Resources)
+
+	return resolved
+}
+
+func resolveNamespacesActionConfig(c *cli.Context, cfg commonConfig) namespaceConfig {
+	flags := parseActionFlags(c)
+	resolved := namespaceConfig{
+		Namespaces:    c.StringSlice(flagNameNamespace),
+		Label:         c.String("label"),
+		FieldSelector: c.String("field-selector"),
+		Sorting:       c.String("sorting"),
+		Reverse:       c.Bool("reverse"),
+		Resources:     flags.resources,
+	}
+	resolved.commonConfig = resolveCommonConfig(cfg, flags)
+	if flags.columnsSet {
+		resolved.Columns = c.StringSlice("columns")
+	}
+	if !flags.sortingSet {
+		resolved.Sorting = ""
+	}
+	if !flags.resourcesSet {
+		resolved.Resources = nil
+	}
+
+	mergedNamespaces := applyNamespacesConfig(&resolved, resolved.fileConfig, flags.reverseSet)
+	resolved.Namespaces = mergedNamespaces.Namespaces
+	resolved.Label = mergedNamespaces.Label
+	resolved.FieldSelector = mergedNamespaces.FieldSelector
+	resolved.Sorting = mergedNamespaces.Sorting
+	resolved.Reverse = mergedNamespaces.Reverse
+	if resolved.Sorting == "" {
+		resolved.Sorting = string(namespacessorting.Name)
+	}
+	resourcesFromCLI := []string(nil)
+	if flags.resourcesSet {
+		resourcesFromCLI = flags.resources
+	}
+	resolved.Resources = mergedResources(resourcesFromCLI, mergedNamespaces.Resources)
+
+	return resolved
+}
+
+func resolveRecommendActionConfig(c *cli.Context, cfg commonConfig) recommendConfig {
+	flags := parseActionFlags(c)
+	resolved := recommendConfig{
+		Namespaces:          c.StringSlice(flagNameNamespace),
+		Label:               c.String("label"),
+		FieldSelector:       c.String("field-selector"),
+		Nodes:               c.StringSlice("node"),
+		Duration:            c.String(flagNameDuration),
+		Interval:            c.String(flagNameInterval),
+		CPURequestPolicy:    c.String(flagNameCPURequestPolicy),
+		CPULimitPolicy:      c.String(flagNameCPULimitPolicy),
+		MemoryRequestPolicy: c.String(flagNameMemoryRequestPolicy),
+		MemoryLimitPolicy:   c.String(flagNameMemoryLimitPolicy),
+		AllNamespaces:       c.Bool(flagNameAllNamespaces),
+	}
+	resolved.commonConfig = resolveCommonConfig(cfg, flags)
+
+	mergedRecommend := applyRecommendConfig(&resolved, resolved.fileConfig)
+	resolved.Namespaces = mergedRecommend.Namespaces
+	resolved.Label = mergedRecommend.Label
+	resolved.FieldSelector = mergedRecommend.FieldSelector
+	resolved.Nodes = mergedRecommend.Nodes
+	resolved.Duration = cmp.Or(mergedRecommend.Duration, recommendations.DefaultDuration.String())
+	resolved.Interval = cmp.Or(mergedRecommend.Interval, recommendations.DefaultInterval.String())
+	resolved.CPURequestPolicy = cmp.Or(mergedRecommend.CPURequestPolicy, recommendations.DefaultRequestPolicy)
+	resolved.CPULimitPolicy = cmp.Or(mergedRecommend.CPULimitPolicy, recommendations.DefaultLimitPolicy)
+	resolved.MemoryRequestPolicy = cmp.Or(mergedRecommend.MemoryRequestPolicy, recommendations.DefaultRequestPolicy)
+	resolved.MemoryLimitPolicy = cmp.Or(mergedRecommend.MemoryLimitPolicy, recommendations.DefaultLimitPolicy)
+
+	return resolved
+}
+
 func runSummaryAction(c *cli.Context, cfg commonConfig) error {
 	summaryActionConfig := resolveSummaryActionConfig(c, cfg)
 
@@ -224,21 +430,31 @@ func runSummaryAction(c *cli.Context, cfg commonConfig) error {
 		tableview.View(summaryActionConfig.TableView),
 		outputResources,
 		nodeCols,
+		summaryActionConfig.GroupByLabel,
 	)
+	recorder, err := recording.Open(summaryActionConfig.Record)
+	if err != nil {
+		return err
+	}
 	if summaryActionConfig.WatchMetrics {
-		return summaryWatch(
+		err = summaryWatch(
 			&summaryCfg,
+			recorder,
 			summaryWatchRenderer(
 				output.Output(summaryActionConfig.Output),
 				tableview.View(summaryActionConfig.TableView),
 				outputResources,
 				nodeCols,
+				summaryActionConfig.GroupByLabel,
+				summaryActionConfig.WatchHistory,
 			),
 			outputProcessor,
 		)
+	} else {
+		err = summary(&summaryCfg, recorder.Nodes(outputProcessor))
 	}
 
-	return summary(&summaryCfg, outputProcessor)
+	return errors.Join(err, recorder.Close())
 }
 
 func runPodsAction(c *cli.Context, cfg commonConfig) error {
@@ -268,19 +484,298 @@ func runPodsAction(c *cli.Context, cfg commonConfig) error {
 		tableview.View(podActionConfig.TableView),
 		outputResources,
 		podCols,
+		podActionConfig.Efficiency,
 	)
+	recorder, err := recording.Open(podActionConfig.Record)
+	if err != nil {
+		return err
+	}
 	if podActionConfig.WatchMetrics {
-		return podsWatch(
+		err = podsWatch(
 			&podCfg,
+			recorder,
+			podsWatchRenderer(
+				output.Output(podActionConfig.Output),
+				tableview.View(podActionConfig.TableView),
+				outputResources,
+				podCols,
+				podActionConfig.WatchHistory,
+				podActionConfig.Efficiency,
+			),
+			outputProcessor,
+		)
+	} else {
+		err = pods(&podCfg, recorder.Pods(outputProcessor, podCfg.RequestedNamespaces))
+	}
+
+	return errors.Join(err, recorder.Close())
+}
+
+func runWorkloadsAction(c *cli.Context, cfg commonConfig) error {
+	workloadActionConfig := resolveWorkloadsActionConfig(c, cfg)
+
+	if err := workloadActionConfig.Validate(); err != nil {
+		return err
+	}
+
+	outputResources := resources.FromStrings(workloadActionConfig.Resources...)
+	if err := validateTableViewColumns(tableview.View(workloadActionConfig.TableView), workloadActionConfig.Columns); err != nil {
+		return err
+	}
+	workloadCols, err := parseColumnsForOutput(
+		output.Output(workloadActionConfig.Output),
+		workloadActionConfig.Columns,
+		metricstable.ParseColumns,
+		metricstable.ValidateColumns,
+	)
+	if err != nil {
+		return err
+	}
+
+	workloadCfg := workloadsConfig(workloadActionConfig)
+	outputProcessor := workloadsOutputProcessor(
+		output.Output(workloadActionConfig.Output),
+		tableview.View(workloadActionConfig.TableView),
+		outputResources,
+		workloadCols,
+	)
+	if workloadActionConfig.WatchMetrics {
+		return workloadsWatch(
+			&workloadCfg,
+			workloadsWatchRenderer(
+				output.Output(workloadActionConfig.Output),
+				tableview.View(workloadActionConfig.TableView),
+				outputResources,
+				workloadCols,
+			),
+			outputProcessor,
+		)
+	}
+
+	return workloadsRequest(&workloadCfg, outputProcessor)
+}
+
+func runNamespacesAction(c *cli.Context, cfg commonConfig) error {
+	namespaceActionConfig := resolveNamespacesActionConfig(c, cfg)
+
+	if err := namespaceActionConfig.Validate(); err != nil {
+		return err
+	}
+
+	outputResources := resources.FromStrings(namespaceActionConfig.Resources...)
+	if err := validateTableViewColumns(tableview.View(namespaceActionConfig.TableView), namespaceActionConfig.Columns); err != nil {
+		return err
+	}
+	namespaceCols, err := parseColumnsForOutput(
+		output.Output(namespaceActionConfig.Output),
+		namespaceActionConfig.Columns,
+		metricstable.ParseColumns,
+		metricstable.ValidateColumns,
+	)
+	if err != nil {
+		return err
+	}
+
+	namespaceCfg := namespacesConfig(namespaceActionConfig)
+	outputProcessor := namespacesOutputProcessor(
+		output.Output(namespaceActionConfig.Output),
+		tableview.View(namespaceActionConfig.TableView),
+		outputResources,
+		namespaceCols,
+	)
+	if namespaceActionConfig.WatchMetrics {
+		return namespacesWatch(
+			&namespaceCfg,
+			namespacesWatchRenderer(
+				output.Output(namespaceActionConfig.Output),
+				tableview.View(namespaceActionConfig.TableView),
+				outputResources,
+				namespaceCols,
+			),
+			outputProcessor,
+		)
+	}
+
+	return namespacesRequest(&namespaceCfg, outputProcessor)
+}
+
+func runRecommendAction(c *cli.Context, cfg commonConfig) error {
+	recommendActionConfig := resolveRecommendActionConfig(c, cfg)
+
+	if err := recommendActionConfig.Validate(); err != nil {
+		return err
+	}
+
+	recommendCfg := recommendationsConfig(recommendActionConfig)
+	return recommend(&recommendCfg, recommendOutputProcessor(output.Output(recommendActionConfig.Output)))
+}
+
+func resolveDiffActionConfig(c *cli.Context, cfg commonConfig) diffConfig {
+	flags := parseActionFlags(c)
+	resolved := diffConfig{
+		Before:           c.Args().Get(0),
+		After:            c.Args().Get(1),
+		BeforeAt:         c.String(flagNameBeforeAt),
+		AfterAt:          c.String(flagNameAfterAt),
+		Namespaces:       c.StringSlice(flagNameNamespace),
+		CPUThreshold:     c.String(flagNameCPUThreshold),
+		MemoryThreshold:  c.String(flagNameMemoryThreshold),
+		PercentThreshold: c.Float64(flagNamePercentThreshold),
+	}
+	resolved.commonConfig = resolveCommonConfig(cfg, flags)
+	return resolved
+}
+
+func runDiffAction(c *cli.Context, cfg commonConfig) error {
+	if c.Args().Len() > 2 {
+		return fmt.Errorf("diff compares two recordings, got %d arguments", c.Args().Len())
+	}
+	diffActionConfig := resolveDiffActionConfig(c, cfg)
+	if err := diffActionConfig.Validate(); err != nil {
+		return err
+	}
+
+	diffCfg := diffServiceConfig(diffActionConfig)
+	return diffRequest(&diffCfg, diffOutputProcessor(output.Output(diffActionConfig.Output)))
+}
+
+func resolveReplayConfig(c *cli.Context) replayConfig {
+	return replayConfig{
+		File:  c.Args().First(),
+		At:    c.String(flagNameAt),
+		Speed: c.Float64(flagNameSpeed),
+	}
+}
+
+func runReplayPodsAction(c *cli.Context, cfg commonConfig) error {
+	replayCfg := resolveReplayConfig(c)
+	if err := replayCfg.Validate(); err != nil {
+		return err
+	}
+	podActionConfig := resolvePodsActionConfig(c, cfg)
+	if err := podActionConfig.Validate(); err != nil {
+		return err
+	}
+
+	outputResources := resources.FromStrings(podActionConfig.Resources...)
+	if err := validateTableViewColumns(tableview.View(podActionConfig.TableView), podActionConfig.Columns); err != nil {
+		return err
+	}
+	podCols, err := parseColumnsForOutput(
+		output.Output(podActionConfig.Output),
+		podActionConfig.Columns,
+		metricstable.ParseColumns,
+		metricstable.ValidateColumns,
+	)
+	if err != nil {
+		return err
+	}
+
+	podCfg := metricsResourcesConfig(podActionConfig)
+	if err := podCfg.Validate(); err != nil {
+		return err
+	}
+	snapshots, err := replayCfg.snapshots(recording.Pods, podActionConfig.WatchMetrics)
+	if err != nil {
+		return err
+	}
+	outputProcessor := podsOutputProcessor(
+		output.Output(podActionConfig.Output),
+		tableview.View(podActionConfig.TableView),
+		outputResources,
+		podCols,
+		podActionConfig.Efficiency,
+	)
+	if podActionConfig.WatchMetrics {
+		return podsReplayWatch(
+			recording.Play(snapshots, replayCfg.Speed, func(snapshot recording.Snapshot) (metricsresources.PodMetricsResourceList, error) {
+				return podCfg.Refine(snapshot.PodList(), snapshot.Timestamp), nil
+			}),
 			podsWatchRenderer(
 				output.Output(podActionConfig.Output),
 				tableview.View(podActionConfig.TableView),
 				outputResources,
 				podCols,
+				podActionConfig.WatchHistory,
+				podActionConfig.Efficiency,
 			),
 			outputProcessor,
 		)
 	}
 
-	return pods(&podCfg, outputProcessor)
+	outputProcessor.Success(podCfg.Refine(snapshots[0].PodList(), snapshots[0].Timestamp), snapshots[0].Warnings)
+	return nil
+}
+
+func runReplaySummaryAction(c *cli.Context, cfg commonConfig) error {
+	replayCfg := resolveReplayConfig(c)
+	if err := replayCfg.Validate(); err != nil {
+		return err
+	}
+	summaryActionConfig := resolveSummaryActionConfig(c, cfg)
+	if err := summaryActionConfig.Validate(); err != nil {
+		return err
+	}
+
+	outputResources := resources.FromStrings(summaryActionConfig.Resources...)
+	if err := validateTableViewColumns(tableview.View(summaryActionConfig.TableView), summaryActionConfig.Columns); err != nil {
+		return err
+	}
+	nodeCols, err := parseColumnsForOutput(
+		output.Output(summaryActionConfig.Output),
+		summaryActionConfig.Columns,
+		nodestable.ParseColumns,
+		nodestable.ValidateColumns,
+	)
+	if err != nil {
+		return err
+	}
+
+	summaryCfg := nodeResourcesConfig(summaryActionConfig)
+	if err := summaryCfg.Validate(); err != nil {
+		return err
+	}
+	// Refining no nodes checks the label selector before any output.
+	if _, err := summaryCfg.Refine(nil); err != nil {
+		return err
+	}
+	snapshots, err := replayCfg.snapshots(recording.Nodes, summaryActionConfig.WatchMetrics)
+	if err != nil {
+		return err
+	}
+	if summaryActionConfig.WatchMetrics {
+		return summaryReplayWatch(
+			recording.Play(snapshots, replayCfg.Speed, func(snapshot recording.Snapshot) (noderesources.NodeResourceList, error) {
+				return summaryCfg.Refine(snapshot.NodeList())
+			}),
+			summaryWatchRenderer(
+				output.Output(summaryActionConfig.Output),
+				tableview.View(summaryActionConfig.TableView),
+				outputResources,
+				nodeCols,
+				summaryActionConfig.GroupByLabel,
+				summaryActionConfig.WatchHistory,
+			),
+			summaryOutputProcessor(
+				output.Output(summaryActionConfig.Output),
+				tableview.View(summaryActionConfig.TableView),
+				outputResources,
+				nodeCols,
+				summaryActionConfig.GroupByLabel,
+			),
+		)
+	}
+
+	list, err := summaryCfg.Refine(snapshots[0].NodeList())
+	if err != nil {
+		return err
+	}
+	summaryOutputProcessor(
+		output.Output(summaryActionConfig.Output),
+		tableview.View(summaryActionConfig.TableView),
+		outputResources,
+		nodeCols,
+		summaryActionConfig.GroupByLabel,
+	).Success(list)
+	return nil
 }
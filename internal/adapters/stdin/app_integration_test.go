@@ -1,11 +1,20 @@
 package stdin
 
 import (
+	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/internal/recording"
+	podresources "github.com/trezorg/k8spodsmetrics/pkg/pods"
 )
 
 func TestNewAppRunConfigRegression(t *testing.T) {
@@ -68,6 +77,30 @@ func TestNewAppRunConfigRegression(t *testing.T) {
 		require.ErrorContains(t, err, "sorting should be one of")
 	})
 
+	t.Run("workloads file sorting is used when sorting flag is omitted", func(t *testing.T) {
+		configPath := writeConfigFile(t, "workloads:\n  sorting: node\n")
+
+		err := runApp(t, "--config", configPath, "workloads")
+
+		require.ErrorContains(t, err, "sorting should be one of")
+	})
+
+	t.Run("namespaces file sorting is used when sorting flag is omitted", func(t *testing.T) {
+		configPath := writeConfigFile(t, "namespaces:\n  sorting: namespace\n")
+
+		err := runApp(t, "--config", configPath, "namespaces")
+
+		require.ErrorContains(t, err, "sorting should be one of")
+	})
+
+	t.Run("recommend file policy is used when policy flag is omitted", func(t *testing.T) {
+		configPath := writeConfigFile(t, "recommend:\n  cpu-limit-policy: p75\n")
+
+		err := runApp(t, "--config", configPath, "recommend")
+
+		require.ErrorContains(t, err, "cpu-limit-policy: invalid policy")
+	})
+
 	t.Run("file resources are used when resources flag is omitted", func(t *testing.T) {
 		configPath := writeConfigFile(t, "summary:\n  resources:\n    - invalid\n")
 
@@ -168,6 +201,137 @@ func TestNewAppRunConfigRegression(t *testing.T) {
 	})
 }
 
+func TestNewAppReplay(t *testing.T) {
+	recordingPath := filepath.Join(t.TempDir(), "recording.jsonl")
+	recorder, err := recording.Open(recordingPath)
+	require.NoError(t, err)
+	first := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, names := range [][]string{{"web-1"}, {"web-2", "web-1", "db-1"}} {
+		list := metricsresources.PodMetricsResourceList{}
+		for _, name := range names {
+			list = append(list, metricsresources.PodMetricsResource{
+				PodResource: podresources.PodResource{NamespaceName: podresources.NamespaceName{Namespace: "default", Name: name}},
+			})
+		}
+		recorder.Record(recording.NewPodSnapshot(first.Add(time.Duration(i)*time.Minute), nil, list, nil))
+	}
+	recorder.Record(recording.NewNodeSnapshot(first, noderesources.NodeResourceList{{Name: "node-1"}}))
+	require.NoError(t, recorder.Close())
+
+	replayedPods := func(t *testing.T, args ...string) []string {
+		t.Helper()
+		output := captureStdout(t, func() {
+			require.NoError(t, runApp(t, append([]string{"--output", "json", "replay", "pods"}, args...)...))
+		})
+		var envelope metricsresources.PodMetricsResourceOutputEnvelope
+		require.NoError(t, json.Unmarshal([]byte(output), &envelope))
+		names := make([]string, 0, len(envelope.Items))
+		for _, pod := range envelope.Items {
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+
+	t.Run("last snapshot is sorted", func(t *testing.T) {
+		require.Equal(t, []string{"db-1", "web-1", "web-2"}, replayedPods(t, "--sorting", "name", recordingPath))
+	})
+
+	t.Run("snapshot at time", func(t *testing.T) {
+		require.Equal(t, []string{"web-1"}, replayedPods(t, "--at", "2026-03-01T12:00:30Z", recordingPath))
+	})
+
+	t.Run("nodes are replayed by summary", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			require.NoError(t, runApp(t, "--output", "json", "replay", "summary", recordingPath))
+		})
+		require.Contains(t, output, `"name": "node-1"`)
+	})
+
+	t.Run("missing recording", func(t *testing.T) {
+		err := runApp(t, "replay", "pods", filepath.Join(t.TempDir(), "missing.jsonl"))
+		require.ErrorContains(t, err, "cannot open recording")
+	})
+
+	t.Run("recording file is required", func(t *testing.T) {
+		err := runApp(t, "replay", "summary")
+		require.ErrorContains(t, err, "replay needs the recording file")
+	})
+}
+
+func TestNewAppDiff(t *testing.T) {
+	first := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pod := func(name string, cpu int64) metricsresources.PodMetricsResource {
+		return metricsresources.PodMetricsResource{
+			PodResource: podresources.PodResource{
+				NamespaceName: podresources.NamespaceName{Namespace: "default", Name: name},
+				Containers: []podresources.ContainerResource{
+					{Name: "app", Requests: podresources.Resource{CPU: cpu}},
+				},
+			},
+		}
+	}
+	writeRecording := func(t *testing.T, snapshot recording.Snapshot) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "recording.jsonl")
+		recorder, err := recording.Open(path)
+		require.NoError(t, err)
+		recorder.Record(snapshot)
+		require.NoError(t, recorder.Close())
+		return path
+	}
+	before := writeRecording(t, recording.NewPodSnapshot(first, nil, metricsresources.PodMetricsResourceList{pod("web", 100)}, nil))
+	after := writeRecording(t, recording.NewPodSnapshot(first.Add(time.Hour), nil, metricsresources.PodMetricsResourceList{
+		pod("web", 200),
+		pod("db", 500),
+	}, nil))
+
+	t.Run("json report", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			require.NoError(t, runApp(t, "--output", "json", "diff", before, after))
+		})
+		var report diff.Report
+		require.NoError(t, json.Unmarshal([]byte(output), &report))
+		require.Len(t, report.Pods, 2)
+		require.Equal(t, "db", report.Pods[0].Name)
+		require.Equal(t, diff.Added, report.Pods[0].Change)
+		require.Equal(t, "web", report.Pods[1].Name)
+		require.Equal(t, &diff.Value{Before: 100, After: 200, Delta: 100}, report.Pods[1].Containers[0].Requests.CPU)
+	})
+
+	t.Run("text report", func(t *testing.T) {
+		output := captureStdout(t, func() {
+			require.NoError(t, runApp(t, "--output", "text", "diff", "--namespace", "default", before, after))
+		})
+		require.Contains(t, output, "changed pod default/web container app: cpu request 100 → 200 (+100)")
+	})
+
+	t.Run("before recording is required", func(t *testing.T) {
+		err := runApp(t, "diff")
+		require.ErrorContains(t, err, "diff needs the before recording")
+	})
+
+	t.Run("at most two recordings", func(t *testing.T) {
+		err := runApp(t, "diff", before, after, after)
+		require.ErrorContains(t, err, "got 3 arguments")
+	})
+}
+
+func captureStdout(t *testing.T, run func()) string {
+	t.Helper()
+
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = w
+	run()
+	require.NoError(t, w.Close())
+	os.Stdout = old
+
+	output, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(output)
+}
+
 func runApp(t *testing.T, args ...string) error {
 	t.Helper()
 
@@ -1,6 +1,7 @@
 package stdin
 
 import (
+	"context"
 	"io"
 
 	metricsjson "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/json/metricsresources"
@@ -18,25 +19,61 @@ import (
 	nodestext "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/text/noderesources"
 	nodesyaml "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/yaml/noderesources"
 
+	namespacesjson "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/json/namespaces"
+	workloadsjson "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/json/workloads"
+	namespacesscreen "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/screen/namespaces"
+	workloadsscreen "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/screen/workloads"
+	namespacestable "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/table/namespaces"
+	workloadstable "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/table/workloads"
+	namespacestext "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/text/namespaces"
+	workloadstext "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/text/workloads"
+	namespacesyaml "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/yaml/namespaces"
+	workloadsyaml "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/yaml/workloads"
+
+	recommendationsjson "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/json/recommendations"
+	recommendationstable "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/table/recommendations"
+	recommendationstext "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/text/recommendations"
+	recommendationsyaml "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/yaml/recommendations"
+
+	diffjson "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/json/diff"
+	difftable "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/table/diff"
+	difftext "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/text/diff"
+	diffyaml "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/yaml/diff"
+
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+	"github.com/trezorg/k8spodsmetrics/internal/history"
 	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
 	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
 	"github.com/trezorg/k8spodsmetrics/internal/output"
+	"github.com/trezorg/k8spodsmetrics/internal/recommendations"
+	"github.com/trezorg/k8spodsmetrics/internal/recording"
+	"github.com/trezorg/k8spodsmetrics/internal/serviceorchestration"
 	"github.com/trezorg/k8spodsmetrics/internal/tableview"
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"github.com/urfave/cli/v2"
 )
 
 type commonConfig struct {
-	ConfigFile   string
-	KubeConfig   string
-	KubeContext  string
-	Output       string
-	TableView    string
-	Alert        string
-	WatchPeriod  uint
-	WatchMetrics bool
-	Columns      []string
-	Timeout      uint
-	fileConfig   *config.Config
+	ConfigFile    string
+	KubeConfig    string
+	KubeContext   string
+	Output        string
+	TableView     string
+	Alert         string
+	MetricsSource string
+	MetricsMaxAge uint
+	WatchPeriod   uint
+	WatchMetrics  bool
+	WatchHistory  uint
+	Columns       []string
+	Timeout       uint
+	Prometheus    config.Prometheus
+	Retries       uint
+	Retry         config.Retry
+	Connection    config.Connection
+	fileConfig    *config.Config
 }
 
 type podConfig struct {
@@ -46,8 +83,18 @@ type podConfig struct {
 	Nodes         []string
 	Sorting       string
 	Resources     []string
+	QOSClasses    []string
+	// Record is the file results are appended to, empty records nothing.
+	Record string
 	commonConfig
-	Reverse bool
+	Reverse       bool
+	AllNamespaces bool
+	// PartialResults renders the pods that could be listed when some
+	// namespaces, nodes or clusters fail.
+	PartialResults bool
+	// Efficiency adds usage relative to requests and limits and the idle
+	// requested resources to tables.
+	Efficiency bool
 }
 
 type summaryConfig struct {
@@ -55,6 +102,70 @@ type summaryConfig struct {
 	Label     string
 	Sorting   string
 	Resources []string
+	// Record is the file results are appended to, empty records nothing.
+	Record string
+	commonConfig
+	Reverse           bool
+	IncludeTerminated bool
+	GroupByQOS        bool
+	SchedulableOnly   bool
+	GroupByLabel      string
+}
+
+type workloadConfig struct {
+	Namespaces    []string
+	Label         string
+	FieldSelector string
+	Nodes         []string
+	Sorting       string
+	Resources     []string
+	commonConfig
+	Reverse       bool
+	AllNamespaces bool
+}
+
+type recommendConfig struct {
+	Namespaces          []string
+	Label               string
+	FieldSelector       string
+	Nodes               []string
+	Duration            string
+	Interval            string
+	CPURequestPolicy    string
+	CPULimitPolicy      string
+	MemoryRequestPolicy string
+	MemoryLimitPolicy   string
+	commonConfig
+	AllNamespaces bool
+}
+
+// replayConfig selects the recording and the snapshots to replay.
+type replayConfig struct {
+	File  string
+	At    string
+	Speed float64
+}
+
+// diffConfig selects the recordings and the snapshots to compare. An empty
+// After compares with the live cluster.
+type diffConfig struct {
+	Before           string
+	BeforeAt         string
+	After            string
+	AfterAt          string
+	Namespaces       []string
+	CPUThreshold     string
+	MemoryThreshold  string
+	PercentThreshold float64
+	commonConfig
+}
+
+type namespaceConfig struct {
+	Namespaces    []string
+	Label         string
+	FieldSelector string
+	Sorting       string
+	Resources     []string
 	commonConfig
 	Reverse bool
 }
@@ -83,9 +194,63 @@ type SummaryWatcher interface {
 
 type PodsWatcher interface {
 	ProcessWatch(metricsresources.SuccessProcessor, metricsresources.ErrorProcessor) error
+	RequestedNamespaces() []string
+}
+
+type WorkloadsProcessor interface {
+	Process(workloads.SuccessProcessor) error
 }
 
-func summaryOutputProcessor(out output.Output, view tableview.View, res resources.Resources, cols []columns.Column) SummaryOutputProcessor {
+type WorkloadsOutputProcessor interface {
+	workloads.SuccessProcessor
+	workloads.ErrorProcessor
+}
+
+type WorkloadsWatcher interface {
+	ProcessWatch(workloads.SuccessProcessor, workloads.ErrorProcessor) error
+}
+
+type NamespacesProcessor interface {
+	Process(namespaces.SuccessProcessor) error
+}
+
+type NamespacesOutputProcessor interface {
+	namespaces.SuccessProcessor
+	namespaces.ErrorProcessor
+}
+
+type NamespacesWatcher interface {
+	ProcessWatch(namespaces.SuccessProcessor, namespaces.ErrorProcessor) error
+}
+
+type RecommendProcessor interface {
+	Process(recommendations.SuccessProcessor) error
+}
+
+type RecommendOutputProcessor interface {
+	recommendations.SuccessProcessor
+	recommendations.ErrorProcessor
+}
+
+type DiffProcessor interface {
+	Process(diff.SuccessProcessor) error
+}
+
+type DiffOutputProcessor interface {
+	diff.SuccessProcessor
+	diff.ErrorProcessor
+}
+
+func summaryOutputProcessor(
+	out output.Output,
+	view tableview.View,
+	res resources.Resources,
+	cols []columns.Column,
+	groupByLabel string,
+) SummaryOutputProcessor {
+	if groupByLabel != "" {
+		return summaryGroupsOutputProcessor(out, view, res, cols, groupByLabel)
+	}
 	switch out {
 	case output.Table:
 		if view == tableview.Compact {
@@ -102,12 +267,44 @@ func summaryOutputProcessor(out output.Output, view tableview.View, res resource
 	return nodestable.ToTable(res, cols)
 }
 
+// summaryGroupsOutputProcessor renders nodes grouped by the value of label.
+func summaryGroupsOutputProcessor(
+	out output.Output,
+	view tableview.View,
+	res resources.Resources,
+	cols []columns.Column,
+	label string,
+) SummaryOutputProcessor {
+	switch out {
+	case output.Table:
+		if view == tableview.Compact {
+			return nodestable.ToCompactGroupsTable(res, label)
+		}
+		return nodestable.ToGroupsTable(res, cols, label)
+	case output.JSON:
+		return nodesjson.ToGroups(label)
+	case output.Yaml:
+		return nodesyaml.ToGroups(label)
+	case output.Text:
+		return nodestext.ToGroups(label)
+	}
+	return nodestable.ToGroupsTable(res, cols, label)
+}
+
 func summaryWatchRenderer(
 	out output.Output,
 	view tableview.View,
 	res resources.Resources,
 	cols []columns.Column,
+	groupByLabel string,
+	watchHistory uint,
 ) func(io.Writer, noderesources.NodeResourceList) {
+	if out == output.Table && view == tableview.Compact && watchHistory > 0 {
+		return nodestable.ToCompactTrendWriter(res, groupByLabel, history.New(int(watchHistory)))
+	}
+	if groupByLabel != "" {
+		return summaryGroupsWatchRenderer(out, view, res, cols, groupByLabel)
+	}
 	switch out {
 	case output.Table:
 		if view == tableview.Compact {
@@ -124,13 +321,42 @@ func summaryWatchRenderer(
 	return nodestable.ToWriter(res, cols)
 }
 
-func podsOutputProcessor(out output.Output, view tableview.View, res resources.Resources, cols []columns.Column) PodsOutputProcessor {
+func summaryGroupsWatchRenderer(
+	out output.Output,
+	view tableview.View,
+	res resources.Resources,
+	cols []columns.Column,
+	label string,
+) func(io.Writer, noderesources.NodeResourceList) {
 	switch out {
 	case output.Table:
 		if view == tableview.Compact {
-			return metricstable.ToCompactTable(res)
+			return nodestable.ToCompactGroupsWriter(res, label)
 		}
-		return metricstable.ToTable(res, cols)
+		return nodestable.ToGroupsWriter(res, cols, label)
+	case output.JSON:
+		return nodesjson.ToGroupsWriter(label)
+	case output.Yaml:
+		return nodesyaml.ToGroupsWriter(label)
+	case output.Text:
+		return nodestext.ToGroupsWriter(label)
+	}
+	return nodestable.ToGroupsWriter(res, cols, label)
+}
+
+func podsOutputProcessor(
+	out output.Output,
+	view tableview.View,
+	res resources.Resources,
+	cols []columns.Column,
+	efficiency bool,
+) PodsOutputProcessor {
+	switch out {
+	case output.Table:
+		if view == tableview.Compact {
+			return metricstable.ToCompactTable(res, efficiency)
+		}
+		return metricstable.ToTable(res, cols, efficiency)
 	case output.JSON:
 		return metricsjson.JSON(metricsjson.Print)
 	case output.Yaml:
@@ -138,7 +364,7 @@ func podsOutputProcessor(out output.Output, view tableview.View, res resources.R
 	case output.Text:
 		return metricstext.Text(metricstext.Print)
 	}
-	return metricstable.ToTable(res, cols)
+	return metricstable.ToTable(res, cols, efficiency)
 }
 
 func podsWatchRenderer(
@@ -146,13 +372,18 @@ func podsWatchRenderer(
 	view tableview.View,
 	res resources.Resources,
 	cols []columns.Column,
-) func(io.Writer, metricsresources.PodMetricsResourceList) {
+	watchHistory uint,
+	efficiency bool,
+) func(io.Writer, metricsresources.PodMetricsResourceList, partial.Warnings) {
 	switch out {
 	case output.Table:
+		if view == tableview.Compact && watchHistory > 0 {
+			return metricstable.ToCompactTrendWriter(res, history.New(int(watchHistory)), efficiency)
+		}
 		if view == tableview.Compact {
-			return metricstable.ToCompactWriter(res)
+			return metricstable.ToCompactWriter(res, efficiency)
 		}
-		return metricstable.ToWriter(res, cols)
+		return metricstable.ToWriter(res, cols, efficiency)
 	case output.JSON:
 		return metricsjson.PrintTo
 	case output.Yaml:
@@ -160,7 +391,125 @@ func podsWatchRenderer(
 	case output.Text:
 		return metricstext.PrintTo
 	}
-	return metricstable.ToWriter(res, cols)
+	return metricstable.ToWriter(res, cols, efficiency)
+}
+
+func workloadsOutputProcessor(
+	out output.Output,
+	view tableview.View,
+	res resources.Resources,
+	cols []columns.Column,
+) WorkloadsOutputProcessor {
+	switch out {
+	case output.Table:
+		if view == tableview.Compact {
+			return workloadstable.ToCompactTable(res)
+		}
+		return workloadstable.ToTable(res, cols)
+	case output.JSON:
+		return workloadsjson.JSON(workloadsjson.Print)
+	case output.Yaml:
+		return workloadsyaml.Yaml(workloadsyaml.Print)
+	case output.Text:
+		return workloadstext.Text(workloadstext.Print)
+	}
+	return workloadstable.ToTable(res, cols)
+}
+
+func workloadsWatchRenderer(
+	out output.Output,
+	view tableview.View,
+	res resources.Resources,
+	cols []columns.Column,
+) func(io.Writer, workloads.WorkloadList) {
+	switch out {
+	case output.Table:
+		if view == tableview.Compact {
+			return workloadstable.ToCompactWriter(res)
+		}
+		return workloadstable.ToWriter(res, cols)
+	case output.JSON:
+		return workloadsjson.PrintTo
+	case output.Yaml:
+		return workloadsyaml.PrintTo
+	case output.Text:
+		return workloadstext.PrintTo
+	}
+	return workloadstable.ToWriter(res, cols)
+}
+
+func namespacesOutputProcessor(
+	out output.Output,
+	view tableview.View,
+	res resources.Resources,
+	cols []columns.Column,
+) NamespacesOutputProcessor {
+	switch out {
+	case output.Table:
+		if view == tableview.Compact {
+			return namespacestable.ToCompactTable(res)
+		}
+		return namespacestable.ToTable(res, cols)
+	case output.JSON:
+		return namespacesjson.JSON(namespacesjson.Print)
+	case output.Yaml:
+		return namespacesyaml.Yaml(namespacesyaml.Print)
+	case output.Text:
+		return namespacestext.Text(namespacestext.Print)
+	}
+	return namespacestable.ToTable(res, cols)
+}
+
+func namespacesWatchRenderer(
+	out output.Output,
+	view tableview.View,
+	res resources.Resources,
+	cols []columns.Column,
+) func(io.Writer, namespaces.NamespaceResourceList) {
+	switch out {
+	case output.Table:
+		if view == tableview.Compact {
+			return namespacestable.ToCompactWriter(res)
+		}
+		return namespacestable.ToWriter(res, cols)
+	case output.JSON:
+		return namespacesjson.PrintTo
+	case output.Yaml:
+		return namespacesyaml.PrintTo
+	case output.Text:
+		return namespacestext.PrintTo
+	}
+	return namespacestable.ToWriter(res, cols)
+}
+
+// recommendOutputProcessor renders recommendations. Text output prints only
+// the patches.
+func recommendOutputProcessor(out output.Output) RecommendOutputProcessor {
+	switch out {
+	case output.Table:
+		return recommendationstable.ToTable()
+	case output.JSON:
+		return recommendationsjson.JSON(recommendationsjson.Print)
+	case output.Yaml:
+		return recommendationsyaml.Yaml(recommendationsyaml.Print)
+	case output.Text:
+		return recommendationstext.Text(recommendationstext.Print)
+	}
+	return recommendationstable.ToTable()
+}
+
+func diffOutputProcessor(out output.Output) DiffOutputProcessor {
+	switch out {
+	case output.Table:
+		return difftable.ToTable()
+	case output.JSON:
+		return diffjson.JSON(diffjson.Print)
+	case output.Yaml:
+		return diffyaml.Yaml(diffyaml.Print)
+	case output.Text:
+		return difftext.Text(difftext.Print)
+	}
+	return difftable.ToTable()
 }
 
 func parseColumnsForOutput(
@@ -187,10 +536,14 @@ func summary(processor SummaryProcessor, successProcessor noderesources.SuccessP
 
 func summaryWatch(
 	processor SummaryWatcher,
+	recorder *recording.Recorder,
 	successRenderer func(io.Writer, noderesources.NodeResourceList),
 	errorProcessor noderesources.ErrorProcessor,
 ) error {
-	return processor.ProcessWatch(nodesscreen.NewScreenSuccessWriter(successRenderer), nodesscreen.NewScreenErrorWriter(errorProcessor))
+	return processor.ProcessWatch(
+		recorder.Nodes(nodesscreen.NewScreenSuccessWriter(successRenderer)),
+		nodesscreen.NewScreenErrorWriter(errorProcessor),
+	)
 }
 
 func pods(processor PodsProcessor, successProcessor metricsresources.SuccessProcessor) error {
@@ -199,10 +552,87 @@ func pods(processor PodsProcessor, successProcessor metricsresources.SuccessProc
 
 func podsWatch(
 	processor PodsWatcher,
-	successRenderer func(io.Writer, metricsresources.PodMetricsResourceList),
+	recorder *recording.Recorder,
+	successRenderer func(io.Writer, metricsresources.PodMetricsResourceList, partial.Warnings),
 	errorProcessor metricsresources.ErrorProcessor,
 ) error {
-	return processor.ProcessWatch(metricsscreen.NewScreenSuccessWriter(successRenderer), metricsscreen.NewScreenErrorWriter(errorProcessor))
+	return processor.ProcessWatch(
+		recorder.Pods(metricsscreen.NewScreenSuccessWriter(successRenderer), processor.RequestedNamespaces),
+		metricsscreen.NewScreenErrorWriter(errorProcessor),
+	)
+}
+
+// podsReplayWatch renders recorded pods on the watch screen as they are
+// played back.
+func podsReplayWatch(
+	watch func(context.Context) <-chan metricsresources.WatchResponse,
+	successRenderer func(io.Writer, metricsresources.PodMetricsResourceList, partial.Warnings),
+	errorProcessor metricsresources.ErrorProcessor,
+) error {
+	return serviceorchestration.ProcessPartialWatch(
+		noPrepare,
+		watch,
+		metricsscreen.NewScreenSuccessWriter(successRenderer).Success,
+		metricsscreen.NewScreenErrorWriter(errorProcessor).Error,
+	)
+}
+
+// summaryReplayWatch renders recorded nodes on the watch screen as they are
+// played back.
+func summaryReplayWatch(
+	watch func(context.Context) <-chan noderesources.WatchResponse,
+	successRenderer func(io.Writer, noderesources.NodeResourceList),
+	errorProcessor noderesources.ErrorProcessor,
+) error {
+	return serviceorchestration.ProcessWatch(
+		noPrepare,
+		watch,
+		nodesscreen.NewScreenSuccessWriter(successRenderer).Success,
+		nodesscreen.NewScreenErrorWriter(errorProcessor).Error,
+	)
+}
+
+// noPrepare is the preparation of a replay, which needs no cluster.
+func noPrepare() error {
+	return nil
+}
+
+func workloadsRequest(processor WorkloadsProcessor, successProcessor workloads.SuccessProcessor) error {
+	return processor.Process(successProcessor)
+}
+
+func workloadsWatch(
+	processor WorkloadsWatcher,
+	successRenderer func(io.Writer, workloads.WorkloadList),
+	errorProcessor workloads.ErrorProcessor,
+) error {
+	return processor.ProcessWatch(
+		workloadsscreen.NewScreenSuccessWriter(successRenderer),
+		workloadsscreen.NewScreenErrorWriter(errorProcessor),
+	)
+}
+
+func recommend(processor RecommendProcessor, successProcessor recommendations.SuccessProcessor) error {
+	return processor.Process(successProcessor)
+}
+
+func diffRequest(processor DiffProcessor, successProcessor diff.SuccessProcessor) error {
+	return processor.Process(successProcessor)
+}
+
+func namespacesRequest(processor NamespacesProcessor, successProcessor namespaces.SuccessProcessor) error {
+	return processor.Process(successProcessor)
+}
+
+func namespacesWatch(
+	processor NamespacesWatcher,
+	successRenderer func(io.Writer, namespaces.NamespaceResourceList),
+	errorProcessor namespaces.ErrorProcessor,
+) error {
+	return processor.ProcessWatch(
+		namespacesscreen.NewScreenSuccessWriter(successRenderer),
+		namespacesscreen.NewScreenErrorWriter(errorProcessor),
+	)
 }
 
 func loadFileConfig(configFile string) (*config.Config, error) {
@@ -221,15 +651,21 @@ func applyCommonConfig(cfg *commonConfig, fileConfig *config.Config, watchMetric
 	}
 
 	merged := config.Common{
-		KubeConfig:   cfg.KubeConfig,
-		KubeContext:  cfg.KubeContext,
-		Output:       cfg.Output,
-		TableView:    cfg.TableView,
-		Alert:        cfg.Alert,
-		WatchPeriod:  cfg.WatchPeriod,
-		WatchMetrics: cfg.WatchMetrics,
-		Columns:      cfg.Columns,
-		Timeout:      timeout,
+		KubeConfig:    cfg.KubeConfig,
+		KubeContext:   cfg.KubeContext,
+		Output:        cfg.Output,
+		TableView:     cfg.TableView,
+		Alert:         cfg.Alert,
+		MetricsSource: cfg.MetricsSource,
+		MetricsMaxAge: setUint(cfg.MetricsMaxAge),
+		WatchPeriod:   cfg.WatchPeriod,
+		WatchMetrics:  cfg.WatchMetrics,
+		WatchHistory:  setUint(cfg.WatchHistory),
+		Columns:       cfg.Columns,
+		Timeout:       timeout,
+		Prometheus:    cfg.Prometheus,
+		Retry:         config.Retry{Retries: setUint(cfg.Retries), Delay: cfg.Retry.Delay, MaxDelay: cfg.Retry.MaxDelay},
+		Connection:    cfg.Connection,
 	}
 	if fileConfig != nil {
 		fileConfig.MergeCommon(&merged)
@@ -246,6 +682,15 @@ func applyCommonConfig(cfg *commonConfig, fileConfig *config.Config, watchMetric
 	return merged
 }
 
+// setUint returns nil for a zero CLI value so the file value is used. An
+// explicit zero flag is applied after the merge.
+func setUint(value uint) *uint {
+	if value == 0 {
+		return nil
+	}
+	return &value
+}
+
 // applyPodsConfig merges file config with CLI pods command config values.
 // CLI values take precedence over file config for string and slice types.
 func applyPodsConfig(podCfg *podConfig, fileConfig *config.Config, reverseSet bool) config.Pods {
@@ -257,6 +702,7 @@ func applyPodsConfig(podCfg *podConfig, fileConfig *config.Config, reverseSet bo
 		Sorting:       podCfg.Sorting,
 		Reverse:       podCfg.Reverse,
 		Resources:     podCfg.Resources,
+		QOS:           podCfg.QOSClasses,
 	}
 	if fileConfig != nil {
 		fileConfig.MergePods(&merged)
@@ -269,20 +715,96 @@ func applyPodsConfig(podCfg *podConfig, fileConfig *config.Config, reverseSet bo
 
 // applySummaryConfig merges file config with CLI summary command config values.
 // CLI values take precedence over file config for string and slice types.
-func applySummaryConfig(summaryCfg *summaryConfig, fileConfig *config.Config, reverseSet bool) config.Summary {
+// Boolean values are taken from the CLI only when their flags are explicitly set.
+func applySummaryConfig(summaryCfg *summaryConfig, fileConfig *config.Config, flags actionFlags) config.Summary {
 	merged := config.Summary{
-		Name:      summaryCfg.Name,
-		Label:     summaryCfg.Label,
-		Sorting:   summaryCfg.Sorting,
-		Reverse:   summaryCfg.Reverse,
-		Resources: summaryCfg.Resources,
+		Name:              summaryCfg.Name,
+		Label:             summaryCfg.Label,
+		Sorting:           summaryCfg.Sorting,
+		Reverse:           summaryCfg.Reverse,
+		Resources:         summaryCfg.Resources,
+		IncludeTerminated: summaryCfg.IncludeTerminated,
+		GroupByQOS:        summaryCfg.GroupByQOS,
+		SchedulableOnly:   summaryCfg.SchedulableOnly,
+		GroupByLabel:      summaryCfg.GroupByLabel,
 	}
 	if fileConfig != nil {
 		fileConfig.MergeSummary(&merged)
 	}
-	if reverseSet {
+	if flags.reverseSet {
 		merged.Reverse = summaryCfg.Reverse
 	}
+	if flags.includeTerminatedSet {
+		merged.IncludeTerminated = summaryCfg.IncludeTerminated
+	}
+	if flags.groupByQOSSet {
+		merged.GroupByQOS = summaryCfg.GroupByQOS
+	}
+	if flags.schedulableOnlySet {
+		merged.SchedulableOnly = summaryCfg.SchedulableOnly
+	}
+	return merged
+}
+
+// applyWorkloadsConfig merges file config with CLI workloads command config values.
+// CLI values take precedence over file config for string and slice types.
+func applyWorkloadsConfig(workloadCfg *workloadConfig, fileConfig *config.Config, reverseSet bool) config.Workloads {
+	merged := config.Workloads{
+		Namespaces:    workloadCfg.Namespaces,
+		Label:         workloadCfg.Label,
+		FieldSelector: workloadCfg.FieldSelector,
+		Nodes:         workloadCfg.Nodes,
+		Sorting:       workloadCfg.Sorting,
+		Reverse:       workloadCfg.Reverse,
+		Resources:     workloadCfg.Resources,
+	}
+	if fileConfig != nil {
+		fileConfig.MergeWorkloads(&merged)
+	}
+	if reverseSet {
+		merged.Reverse = workloadCfg.Reverse
+	}
+	return merged
+}
+
+// applyNamespacesConfig merges file config with CLI namespaces command config values.
+// CLI values take precedence over file config for string and slice types.
+func applyNamespacesConfig(namespaceCfg *namespaceConfig, fileConfig *config.Config, reverseSet bool) config.Namespaces {
+	merged := config.Namespaces{
+		Namespaces:    namespaceCfg.Namespaces,
+		Label:         namespaceCfg.Label,
+		FieldSelector: namespaceCfg.FieldSelector,
+		Sorting:       namespaceCfg.Sorting,
+		Reverse:       namespaceCfg.Reverse,
+		Resources:     namespaceCfg.Resources,
+	}
+	if fileConfig != nil {
+		fileConfig.MergeNamespaces(&merged)
+	}
+	if reverseSet {
+		merged.Reverse = namespaceCfg.Reverse
+	}
+	return merged
+}
+
+// applyRecommendConfig merges file config with CLI recommend command config values.
+// CLI values take precedence over file config.
+func applyRecommendConfig(recommendCfg *recommendConfig, fileConfig *config.Config) config.Recommend {
+	merged := config.Recommend{
+		Namespaces:          recommendCfg.Namespaces,
+		Label:               recommendCfg.Label,
+		FieldSelector:       recommendCfg.FieldSelector,
+		Nodes:               recommendCfg.Nodes,
+		Duration:            recommendCfg.Duration,
+		Interval:            recommendCfg.Interval,
+		CPURequestPolicy:    recommendCfg.CPURequestPolicy,
+		CPULimitPolicy:      recommendCfg.CPULimitPolicy,
+		MemoryRequestPolicy: recommendCfg.MemoryRequestPolicy,
+		MemoryLimitPolicy:   recommendCfg.MemoryLimitPolicy,
+	}
+	if fileConfig != nil {
+		fileConfig.MergeRecommend(&merged)
+	}
 	return merged
 }
 
@@ -322,6 +844,72 @@ func NewApp(version string) *cli.App {
 			},
 			Flags: podsFlags(),
 		},
+		{
+			Name:    "workloads",
+			Aliases: []string{"w"},
+			Before:  loadConfigBefore(&cfg),
+			Action: func(c *cli.Context) error {
+				return runWorkloadsAction(c, cfg)
+			},
+			Flags: workloadsFlags(),
+		},
+		{
+			Name:    "namespaces",
+			Aliases: []string{"ns"},
+			Before:  loadConfigBefore(&cfg),
+			Action: func(c *cli.Context) error {
+				return runNamespacesAction(c, cfg)
+			},
+			Flags: namespacesFlags(),
+		},
+		{
+			Name:  "replay",
+			Usage: "Replay results recorded with --record through filters, sorting, alerts and outputs, --watch plays them back in time",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "pods",
+					Aliases:   []string{"p"},
+					Usage:     "Replay pods recorded by the pods command",
+					ArgsUsage: "<recording>",
+					Before:    loadConfigBefore(&cfg),
+					Action: func(c *cli.Context) error {
+						return runReplayPodsAction(c, cfg)
+					},
+					Flags: replayPodsFlags(),
+				},
+				{
+					Name:      "summary",
+					Aliases:   []string{"s"},
+					Usage:     "Replay nodes recorded by the summary command",
+					ArgsUsage: "<recording>",
+					Before:    loadConfigBefore(&cfg),
+					Action: func(c *cli.Context) error {
+						return runReplaySummaryAction(c, cfg)
+					},
+					Flags: replaySummaryFlags(),
+				},
+			},
+		},
+		{
+			Name:    "recommend",
+			Aliases: []string{"rec"},
+			Before:  loadConfigBefore(&cfg),
+			Action: func(c *cli.Context) error {
+				return runRecommendAction(c, cfg)
+			},
+			Flags: recommendFlags(),
+		},
+		{
+			Name:      "diff",
+			Aliases:   []string{"d"},
+			Usage:     "Report pods and nodes added, removed or changed between two recordings, or between a recording and the live cluster",
+			ArgsUsage: "<before> [<after>]",
+			Before:    loadConfigBefore(&cfg),
+			Action: func(c *cli.Context) error {
+				return runDiffAction(c, cfg)
+			},
+			Flags: diffFlags(),
+		},
 	}
 	app.Flags = commonFlags(&cfg)
 	return app
@@ -8,8 +8,10 @@ import (
 
 	"github.com/trezorg/k8spodsmetrics/internal/columns"
 	"github.com/trezorg/k8spodsmetrics/internal/config"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
 	"github.com/trezorg/k8spodsmetrics/internal/output"
 	"github.com/trezorg/k8spodsmetrics/internal/tableview"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	"github.com/urfave/cli/v2"
 )
 
@@ -73,6 +75,76 @@ func TestPodsFlagsResourcesNaming(t *testing.T) {
 	require.Contains(t, resourcesFlag.Aliases, "res")
 }
 
+func TestRecordFlag(t *testing.T) {
+	require.Contains(t, flagNames(podsFlags()), flagNameRecord)
+	require.Contains(t, flagNames(summaryFlags()), flagNameRecord)
+	require.NotContains(t, flagNames(workloadsFlags()), flagNameRecord)
+}
+
+func TestReplayFlags(t *testing.T) {
+	require.Equal(
+		t,
+		[]string{flagNameNamespace, "node", "qos", "sorting", "reverse", flagNameResources, flagNameEfficiency, flagNameAt, flagNameSpeed},
+		flagNames(replayPodsFlags()),
+	)
+	require.Equal(
+		t,
+		[]string{"label", flagNameName, "sorting", "reverse", flagNameResources, flagNameSchedulableOnly, flagNameGroupByLabel, flagNameAt, flagNameSpeed},
+		flagNames(replaySummaryFlags()),
+	)
+}
+
+func TestDiffFlags(t *testing.T) {
+	require.Equal(
+		t,
+		[]string{
+			flagNameNamespace,
+			flagNameBeforeAt,
+			flagNameAfterAt,
+			flagNameCPUThreshold,
+			flagNameMemoryThreshold,
+			flagNamePercentThreshold,
+		},
+		flagNames(diffFlags()),
+	)
+}
+
+func flagNames(flags []cli.Flag) []string {
+	result := make([]string, 0, len(flags))
+	for _, flag := range flags {
+		result = append(result, flag.Names()[0])
+	}
+	return result
+}
+
+func TestWorkloadsFlagsSortingDefault(t *testing.T) {
+	var sortingFlag *cli.StringFlag
+	for _, flag := range workloadsFlags() {
+		if f, ok := flag.(*cli.StringFlag); ok && f.Name == "sorting" {
+			sortingFlag = f
+			break
+		}
+	}
+
+	require.NotNil(t, sortingFlag)
+	require.Equal(t, "namespace", sortingFlag.Value)
+	require.Contains(t, sortingFlag.Usage, "replicas")
+}
+
+func TestNamespacesFlagsSortingDefault(t *testing.T) {
+	var sortingFlag *cli.StringFlag
+	for _, flag := range namespacesFlags() {
+		if f, ok := flag.(*cli.StringFlag); ok && f.Name == "sorting" {
+			sortingFlag = f
+			break
+		}
+	}
+
+	require.NotNil(t, sortingFlag)
+	require.Equal(t, "name", sortingFlag.Value)
+	require.Contains(t, sortingFlag.Usage, "quota_pods")
+}
+
 func TestSummaryFlagsResourcesNaming(t *testing.T) {
 	flags := summaryFlags()
 
@@ -233,17 +305,19 @@ func TestApplyCommonConfig(t *testing.T) {
 func TestResolveCommonConfig(t *testing.T) {
 	t.Run("uses file-backed defaults when flags are omitted", func(t *testing.T) {
 		cfg := commonConfig{
-			Output:      string(output.Table),
-			TableView:   string(tableview.Expanded),
-			Alert:       "none",
-			WatchPeriod: defaultWatchPeriodSeconds,
-			Columns:     []string{"used"},
+			Output:        string(output.Table),
+			TableView:     string(tableview.Expanded),
+			Alert:         "none",
+			MetricsSource: string(metricssource.MetricsServer),
+			WatchPeriod:   defaultWatchPeriodSeconds,
+			Columns:       []string{"used"},
 			fileConfig: &config.Config{Common: config.Common{
-				Output:      string(output.JSON),
-				TableView:   string(tableview.Compact),
-				Alert:       "cpu",
-				WatchPeriod: 12,
-				Columns:     []string{"limit"},
+				Output:        string(output.JSON),
+				TableView:     string(tableview.Compact),
+				Alert:         "cpu",
+				MetricsSource: string(metricssource.Kubelet),
+				WatchPeriod:   12,
+				Columns:       []string{"limit"},
 			}},
 		}
 
@@ -252,37 +326,42 @@ func TestResolveCommonConfig(t *testing.T) {
 		require.Equal(t, string(output.JSON), resolved.Output)
 		require.Equal(t, string(tableview.Compact), resolved.TableView)
 		require.Equal(t, "cpu", resolved.Alert)
+		require.Equal(t, string(metricssource.Kubelet), resolved.MetricsSource)
 		require.Equal(t, uint(12), resolved.WatchPeriod)
 		require.Equal(t, []string{"limit"}, resolved.Columns)
 	})
 
 	t.Run("keeps cli values when flags are explicitly set", func(t *testing.T) {
 		cfg := commonConfig{
-			Output:      string(output.Table),
-			TableView:   string(tableview.Expanded),
-			Alert:       "none",
-			WatchPeriod: defaultWatchPeriodSeconds,
-			Columns:     []string{"used"},
+			Output:        string(output.Table),
+			TableView:     string(tableview.Expanded),
+			Alert:         "none",
+			MetricsSource: string(metricssource.MetricsServer),
+			WatchPeriod:   defaultWatchPeriodSeconds,
+			Columns:       []string{"used"},
 			fileConfig: &config.Config{Common: config.Common{
-				Output:      string(output.JSON),
-				TableView:   string(tableview.Compact),
-				Alert:       "cpu",
-				WatchPeriod: 12,
-				Columns:     []string{"limit"},
+				Output:        string(output.JSON),
+				TableView:     string(tableview.Compact),
+				Alert:         "cpu",
+				MetricsSource: string(metricssource.Kubelet),
+				WatchPeriod:   12,
+				Columns:       []string{"limit"},
 			}},
 		}
 
 		resolved := resolveCommonConfig(cfg, actionFlags{
-			outputSet:      true,
-			tableViewSet:   true,
-			alertSet:       true,
-			watchPeriodSet: true,
-			columnsSet:     true,
+			outputSet:        true,
+			tableViewSet:     true,
+			alertSet:         true,
+			metricsSourceSet: true,
+			watchPeriodSet:   true,
+			columnsSet:       true,
 		})
 
 		require.Equal(t, string(output.Table), resolved.Output)
 		require.Equal(t, string(tableview.Expanded), resolved.TableView)
 		require.Equal(t, "none", resolved.Alert)
+		require.Equal(t, string(metricssource.MetricsServer), resolved.MetricsSource)
 		require.Equal(t, uint(defaultWatchPeriodSeconds), resolved.WatchPeriod)
 		require.Equal(t, []string{"used"}, resolved.Columns)
 	})
@@ -290,6 +369,84 @@ func TestResolveCommonConfig(t *testing.T) {
 	t.Run("defaults table view to compact when unset", func(t *testing.T) {
 		resolved := resolveCommonConfig(commonConfig{}, actionFlags{})
 		require.Equal(t, string(tableview.Compact), resolved.TableView)
+		require.Equal(t, string(metricssource.MetricsServer), resolved.MetricsSource)
+		require.Equal(t, uint(defaultMetricsMaxAgeSeconds), resolved.MetricsMaxAge)
+	})
+
+	t.Run("metrics max age comes from file unless set", func(t *testing.T) {
+		fileConfig := &config.Config{Common: config.Common{MetricsMaxAge: new(uint(300))}}
+
+		resolved := resolveCommonConfig(commonConfig{MetricsMaxAge: defaultMetricsMaxAgeSeconds, fileConfig: fileConfig}, actionFlags{})
+		require.Equal(t, uint(300), resolved.MetricsMaxAge)
+
+		resolved = resolveCommonConfig(commonConfig{MetricsMaxAge: 0, fileConfig: fileConfig}, actionFlags{metricsMaxAgeSet: true})
+		require.Equal(t, uint(0), resolved.MetricsMaxAge)
+	})
+
+	t.Run("zero metrics max age and watch history from file are kept", func(t *testing.T) {
+		fileConfig := &config.Config{Common: config.Common{MetricsMaxAge: new(uint(0)), WatchHistory: new(uint(0))}}
+
+		resolved := resolveCommonConfig(commonConfig{
+			MetricsMaxAge: defaultMetricsMaxAgeSeconds,
+			WatchHistory:  defaultWatchHistory,
+			fileConfig:    fileConfig,
+		}, actionFlags{})
+		require.Zero(t, resolved.MetricsMaxAge)
+		require.Zero(t, resolved.WatchHistory)
+	})
+
+	t.Run("watch history comes from file unless set", func(t *testing.T) {
+		resolved := resolveCommonConfig(commonConfig{WatchHistory: defaultWatchHistory}, actionFlags{})
+		require.Equal(t, uint(defaultWatchHistory), resolved.WatchHistory)
+
+		fileConfig := &config.Config{Common: config.Common{WatchHistory: new(uint(40))}}
+		resolved = resolveCommonConfig(commonConfig{WatchHistory: defaultWatchHistory, fileConfig: fileConfig}, actionFlags{})
+		require.Equal(t, uint(40), resolved.WatchHistory)
+
+		resolved = resolveCommonConfig(commonConfig{WatchHistory: 0, fileConfig: fileConfig}, actionFlags{watchHistorySet: true})
+		require.Zero(t, resolved.WatchHistory)
+	})
+
+	t.Run("retries come from file unless set", func(t *testing.T) {
+		resolved := resolveCommonConfig(commonConfig{Retries: retry.DefaultRetries}, actionFlags{})
+		require.Equal(t, uint(retry.DefaultRetries), resolved.Retries)
+
+		fileConfig := &config.Config{Common: config.Common{Retry: config.Retry{Retries: new(uint(5)), Delay: "1s"}}}
+		resolved = resolveCommonConfig(commonConfig{Retries: retry.DefaultRetries, fileConfig: fileConfig}, actionFlags{})
+		require.Equal(t, uint(5), resolved.Retries)
+		require.Equal(t, config.Retry{Delay: "1s"}, resolved.Retry)
+
+		resolved = resolveCommonConfig(commonConfig{fileConfig: fileConfig}, actionFlags{retriesSet: true})
+		require.Zero(t, resolved.Retries)
+
+		disabled := &config.Config{Common: config.Common{Retry: config.Retry{Retries: new(uint(0))}}}
+		resolved = resolveCommonConfig(commonConfig{Retries: retry.DefaultRetries, fileConfig: disabled}, actionFlags{})
+		require.Zero(t, resolved.Retries, "a zero in the file disables retries")
+	})
+
+	t.Run("connection overrides come from file unless set", func(t *testing.T) {
+		fileConfig := &config.Config{Common: config.Common{Connection: config.Connection{
+			Server:                "https://file:6443",
+			As:                    "file-user",
+			AsGroups:              []string{"file-group"},
+			InsecureSkipTLSVerify: true,
+			ClientQPS:             50,
+		}}}
+
+		resolved := resolveCommonConfig(commonConfig{fileConfig: fileConfig}, actionFlags{})
+		require.Equal(t, "https://file:6443", resolved.Connection.Server)
+		require.Equal(t, "file-user", resolved.Connection.As)
+		require.Equal(t, []string{"file-group"}, resolved.Connection.AsGroups)
+		require.True(t, resolved.Connection.InsecureSkipTLSVerify)
+		require.Equal(t, float32(50), resolved.Connection.ClientQPS)
+
+		resolved = resolveCommonConfig(commonConfig{
+			Connection: config.Connection{As: "jane"},
+			fileConfig: fileConfig,
+		}, actionFlags{insecureSkipTLSSet: true, asGroups: []string{"developers"}})
+		require.Equal(t, "jane", resolved.Connection.As)
+		require.Equal(t, []string{"developers"}, resolved.Connection.AsGroups)
+		require.False(t, resolved.Connection.InsecureSkipTLSVerify)
 	})
 
 	t.Run("cli columns imply expanded when table view is not explicitly set", func(t *testing.T) {
@@ -329,6 +486,76 @@ func TestApplyPodsConfig(t *testing.T) {
 		merged := applyPodsConfig(cfg, fileCfg, true)
 		require.False(t, merged.Reverse)
 	})
+
+	t.Run("uses file qos when flag is empty", func(t *testing.T) {
+		cfg := &podConfig{}
+		fileCfg := &config.Config{Pods: config.Pods{QOS: []string{"besteffort"}}}
+
+		merged := applyPodsConfig(cfg, fileCfg, false)
+		require.Equal(t, []string{"besteffort"}, merged.QOS)
+	})
+}
+
+func TestApplyWorkloadsConfig(t *testing.T) {
+	t.Run("uses file values when cli values are empty", func(t *testing.T) {
+		cfg := &workloadConfig{}
+		fileCfg := &config.Config{Workloads: config.Workloads{
+			Namespaces: config.StringOrSlice{"apps"},
+			Sorting:    "replicas",
+			Reverse:    true,
+		}}
+
+		merged := applyWorkloadsConfig(cfg, fileCfg, false)
+		require.Equal(t, config.StringOrSlice{"apps"}, merged.Namespaces)
+		require.Equal(t, "replicas", merged.Sorting)
+		require.True(t, merged.Reverse)
+	})
+
+	t.Run("keeps cli reverse when flag is explicitly set", func(t *testing.T) {
+		cfg := &workloadConfig{Reverse: false}
+		fileCfg := &config.Config{Workloads: config.Workloads{Reverse: true}}
+
+		merged := applyWorkloadsConfig(cfg, fileCfg, true)
+		require.False(t, merged.Reverse)
+	})
+}
+
+func TestApplyRecommendConfig(t *testing.T) {
+	cfg := &recommendConfig{Duration: "5m"}
+	fileCfg := &config.Config{Recommend: config.Recommend{
+		Namespaces:     config.StringOrSlice{"apps"},
+		Duration:       "1h",
+		CPULimitPolicy: "none",
+	}}
+
+	merged := applyRecommendConfig(cfg, fileCfg)
+	require.Equal(t, config.StringOrSlice{"apps"}, merged.Namespaces)
+	require.Equal(t, "5m", merged.Duration)
+	require.Equal(t, "none", merged.CPULimitPolicy)
+}
+
+func TestApplyNamespacesConfig(t *testing.T) {
+	t.Run("uses file values when cli values are empty", func(t *testing.T) {
+		cfg := &namespaceConfig{}
+		fileCfg := &config.Config{Namespaces: config.Namespaces{
+			Namespaces: config.StringOrSlice{"team-a", "team-b"},
+			Sorting:    "quota_request_cpu",
+			Reverse:    true,
+		}}
+
+		merged := applyNamespacesConfig(cfg, fileCfg, false)
+		require.Equal(t, config.StringOrSlice{"team-a", "team-b"}, merged.Namespaces)
+		require.Equal(t, "quota_request_cpu", merged.Sorting)
+		require.True(t, merged.Reverse)
+	})
+
+	t.Run("keeps cli reverse when flag is explicitly set", func(t *testing.T) {
+		cfg := &namespaceConfig{Reverse: false}
+		fileCfg := &config.Config{Namespaces: config.Namespaces{Reverse: true}}
+
+		merged := applyNamespacesConfig(cfg, fileCfg, true)
+		require.False(t, merged.Reverse)
+	})
 }
 
 func TestApplySummaryConfig(t *testing.T) {
@@ -336,7 +563,7 @@ func TestApplySummaryConfig(t *testing.T) {
 		cfg := &summaryConfig{Reverse: false}
 		fileCfg := &config.Config{Summary: config.Summary{Reverse: true}}
 
-		merged := applySummaryConfig(cfg, fileCfg, false)
+		merged := applySummaryConfig(cfg, fileCfg, actionFlags{})
 		require.True(t, merged.Reverse)
 	})
 
@@ -344,7 +571,60 @@ func TestApplySummaryConfig(t *testing.T) {
 		cfg := &summaryConfig{Reverse: false}
 		fileCfg := &config.Config{Summary: config.Summary{Reverse: true}}
 
-		merged := applySummaryConfig(cfg, fileCfg, true)
+		merged := applySummaryConfig(cfg, fileCfg, actionFlags{reverseSet: true})
 		require.False(t, merged.Reverse)
 	})
+
+	t.Run("uses file include-terminated when flag is not explicitly set", func(t *testing.T) {
+		cfg := &summaryConfig{}
+		fileCfg := &config.Config{Summary: config.Summary{IncludeTerminated: true}}
+
+		merged := applySummaryConfig(cfg, fileCfg, actionFlags{})
+		require.True(t, merged.IncludeTerminated)
+	})
+
+	t.Run("keeps cli include-terminated when flag is explicitly set", func(t *testing.T) {
+		cfg := &summaryConfig{}
+		fileCfg := &config.Config{Summary: config.Summary{IncludeTerminated: true}}
+
+		merged := applySummaryConfig(cfg, fileCfg, actionFlags{includeTerminatedSet: true})
+		require.False(t, merged.IncludeTerminated)
+	})
+
+	t.Run("uses file group-by-qos when flag is not explicitly set", func(t *testing.T) {
+		cfg := &summaryConfig{}
+		fileCfg := &config.Config{Summary: config.Summary{GroupByQOS: true}}
+
+		merged := applySummaryConfig(cfg, fileCfg, actionFlags{})
+		require.True(t, merged.GroupByQOS)
+	})
+
+	t.Run("keeps cli group-by-qos when flag is explicitly set", func(t *testing.T) {
+		cfg := &summaryConfig{}
+		fileCfg := &config.Config{Summary: config.Summary{GroupByQOS: true}}
+
+		merged := applySummaryConfig(cfg, fileCfg, actionFlags{groupByQOSSet: true})
+		require.False(t, merged.GroupByQOS)
+	})
+
+	t.Run("uses file schedulable-only when flag is not explicitly set", func(t *testing.T) {
+		cfg := &summaryConfig{}
+		fileCfg := &config.Config{Summary: config.Summary{SchedulableOnly: true}}
+
+		merged := applySummaryConfig(cfg, fileCfg, actionFlags{})
+		require.True(t, merged.SchedulableOnly)
+
+		merged = applySummaryConfig(cfg, fileCfg, actionFlags{schedulableOnlySet: true})
+		require.False(t, merged.SchedulableOnly)
+	})
+
+	t.Run("cli group-by-label takes precedence over file", func(t *testing.T) {
+		fileCfg := &config.Config{Summary: config.Summary{GroupByLabel: "topology.kubernetes.io/zone"}}
+
+		merged := applySummaryConfig(&summaryConfig{}, fileCfg, actionFlags{})
+		require.Equal(t, "topology.kubernetes.io/zone", merged.GroupByLabel)
+
+		merged = applySummaryConfig(&summaryConfig{GroupByLabel: "node.kubernetes.io/instance-type"}, fileCfg, actionFlags{})
+		require.Equal(t, "node.kubernetes.io/instance-type", merged.GroupByLabel)
+	})
 }
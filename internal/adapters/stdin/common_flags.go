@@ -4,18 +4,42 @@ import (
 	"fmt"
 
 	"github.com/trezorg/k8spodsmetrics/internal/alert"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
 	"github.com/trezorg/k8spodsmetrics/internal/output"
 	"github.com/trezorg/k8spodsmetrics/internal/tableview"
+	"github.com/trezorg/k8spodsmetrics/pkg/prometheus"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	"github.com/urfave/cli/v2"
 )
 
 const (
 	defaultWatchPeriodSeconds = 5
 	defaultTimeoutSeconds     = 30
+	// metrics-server scrapes every 15 to 60 seconds, older metrics point at a
+	// stuck metrics-server or kubelet.
+	defaultMetricsMaxAgeSeconds = 180
+	// defaultWatchHistory samples span 100 seconds at the default watch period.
+	defaultWatchHistory = 20
 
-	flagNameName      = "name"
-	flagNameNamespace = "namespace"
-	flagNameResources = "resources"
+	flagNameName              = "name"
+	flagNameNamespace         = "namespace"
+	flagNameResources         = "resources"
+	flagNameIncludeTerminated = "include-terminated"
+	flagNameGroupByQOS        = "group-by-qos"
+	flagNameSchedulableOnly   = "schedulable-only"
+	flagNameGroupByLabel      = "group-by-label"
+	flagNameMetricsSource     = "metrics-source"
+	flagNamePrometheusURL     = "prometheus-url"
+	flagNamePrometheusWindow  = "prometheus-rate-window"
+	flagNameMetricsMaxAge     = "metrics-max-age"
+	flagNameAllNamespaces     = "all-namespaces"
+	flagNamePartialResults    = "partial-results"
+	flagNameEfficiency        = "efficiency"
+	flagNameAsGroup           = "as-group"
+	flagNameInsecureSkipTLS   = "insecure-skip-tls-verify"
+	flagNameRetries           = "retries"
+	flagNameRecord            = "record"
+	flagNameWatchHistory      = "watch-history"
 )
 
 func commonFlags(config *commonConfig) []cli.Flag {
@@ -37,9 +61,49 @@ func commonFlags(config *commonConfig) []cli.Flag {
 			Name:        "context",
 			Aliases:     []string{"c"},
 			Value:       "",
-			Usage:       "K8S config context",
+			Usage:       "K8S config context, or comma separated contexts and globs such as prod-* for pods and summary",
 			Destination: &config.KubeContext,
 		},
+		&cli.StringFlag{
+			Name:        "server",
+			Value:       "",
+			Usage:       "Address and port of the Kubernetes API server, overrides the kubeconfig",
+			Destination: &config.Connection.Server,
+		},
+		&cli.StringFlag{
+			Name:        "token",
+			Value:       "",
+			Usage:       "Bearer token for authentication to the API server",
+			Destination: &config.Connection.Token,
+		},
+		&cli.StringFlag{
+			Name:        "as",
+			Value:       "",
+			Usage:       "Username to impersonate for the operation",
+			Destination: &config.Connection.As,
+		},
+		&cli.StringSliceFlag{
+			Name:  flagNameAsGroup,
+			Usage: "Group to impersonate for the operation, repeat for several groups",
+		},
+		&cli.StringFlag{
+			Name:        "certificate-authority",
+			Value:       "",
+			Usage:       "Path to a cert file for the certificate authority",
+			Destination: &config.Connection.CertificateAuthority,
+		},
+		&cli.BoolFlag{
+			Name:        flagNameInsecureSkipTLS,
+			Value:       false,
+			Usage:       "Do not check the server certificate, this makes HTTPS connections insecure",
+			Destination: &config.Connection.InsecureSkipTLSVerify,
+		},
+		&cli.StringFlag{
+			Name:        "request-timeout",
+			Value:       "",
+			Usage:       "Timeout of a single API request such as 10s or 1m, a bare number is seconds and 0 waits forever",
+			Destination: &config.Connection.RequestTimeout,
+		},
 		&cli.StringFlag{
 			Name:    "loglevel",
 			Aliases: []string{"level"},
@@ -50,7 +114,7 @@ func commonFlags(config *commonConfig) []cli.Flag {
 			Name:        "alert",
 			Aliases:     []string{"a", "alerts"},
 			Value:       string(alert.None),
-			Usage:       fmt.Sprintf("Alert format. [%s]", alert.StringListDefault()),
+			Usage:       fmt.Sprintf("Alert format. [%s] or an extended resource name", alert.StringListDefault()),
 			Destination: &config.Alert,
 			Action: func(_ *cli.Context, value string) error {
 				if err := alert.Valid(alert.Alert(value)); err != nil {
@@ -74,6 +138,12 @@ func commonFlags(config *commonConfig) []cli.Flag {
 			Usage:       "Watch period",
 			Destination: &config.WatchPeriod,
 		},
+		&cli.UintFlag{
+			Name:        flagNameWatchHistory,
+			Value:       defaultWatchHistory,
+			Usage:       "Samples of CPU and memory usage shown as sparklines in compact watch tables, 0 hides them",
+			Destination: &config.WatchHistory,
+		},
 		&cli.StringFlag{
 			Name:        "output",
 			Aliases:     []string{"o"},
@@ -113,5 +183,63 @@ func commonFlags(config *commonConfig) []cli.Flag {
 			Usage:       "Timeout in seconds for Kubernetes API calls",
 			Destination: &config.Timeout,
 		},
+		&cli.UintFlag{
+			Name:        flagNameRetries,
+			Value:       retry.DefaultRetries,
+			Usage:       "Retries of API requests failing with throttling, unavailable servers or dropped connections, 0 disables retries",
+			Destination: &config.Retries,
+		},
+		&cli.StringFlag{
+			Name:        "retry-delay",
+			Value:       "",
+			Usage:       fmt.Sprintf("First retry delay, doubled on every retry with jitter (default: %s)", retry.DefaultDelay),
+			Destination: &config.Retry.Delay,
+		},
+		&cli.StringFlag{
+			Name:        "retry-max-delay",
+			Value:       "",
+			Usage:       fmt.Sprintf("Longest retry delay, also capping a Retry-After sent by the server (default: %s)", retry.DefaultMaxDelay),
+			Destination: &config.Retry.MaxDelay,
+		},
+		&cli.StringFlag{
+			Name:        flagNameMetricsSource,
+			Value:       string(metricssource.MetricsServer),
+			Usage:       fmt.Sprintf("Usage metrics source. [%s]", metricssource.StringListDefault()),
+			Destination: &config.MetricsSource,
+			Action: func(_ *cli.Context, value string) error {
+				if err := metricssource.Valid(metricssource.Source(value)); err != nil {
+					return err
+				}
+				config.MetricsSource = value
+				return nil
+			},
+		},
+		&cli.UintFlag{
+			Name:        flagNameMetricsMaxAge,
+			Value:       defaultMetricsMaxAgeSeconds,
+			Usage:       "Age in seconds after which metrics are marked stale",
+			Destination: &config.MetricsMaxAge,
+		},
+		&cli.StringFlag{
+			Name:        flagNamePrometheusURL,
+			Value:       "",
+			Usage:       "Prometheus base URL for the prometheus metrics source",
+			Destination: &config.Prometheus.URL,
+		},
+		&cli.StringFlag{
+			Name:        flagNamePrometheusWindow,
+			Value:       "",
+			Usage:       fmt.Sprintf("Prometheus CPU rate window (default: %s)", prometheus.DefaultWindow),
+			Destination: &config.Prometheus.RateWindow,
+		},
+	}
+}
+
+// recordFlag records the results of the pods and summary commands.
+func recordFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  flagNameRecord,
+		Value: "",
+		Usage: "Append every result, once or on every watch tick, to a file as JSON lines to replay later",
 	}
 }
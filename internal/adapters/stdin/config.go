@@ -2,15 +2,32 @@ package stdin
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/trezorg/k8spodsmetrics/internal/alert"
+	"github.com/trezorg/k8spodsmetrics/internal/config"
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
 	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
 	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
 	"github.com/trezorg/k8spodsmetrics/internal/output"
+	"github.com/trezorg/k8spodsmetrics/internal/qos"
+	"github.com/trezorg/k8spodsmetrics/internal/recommendations"
+	"github.com/trezorg/k8spodsmetrics/internal/recording"
 	"github.com/trezorg/k8spodsmetrics/internal/resources"
 	metricssorting "github.com/trezorg/k8spodsmetrics/internal/sorting/metricsresources"
+	namespacessorting "github.com/trezorg/k8spodsmetrics/internal/sorting/namespaces"
 	nodesorting "github.com/trezorg/k8spodsmetrics/internal/sorting/noderesources"
+	workloadssorting "github.com/trezorg/k8spodsmetrics/internal/sorting/workloads"
 	"github.com/trezorg/k8spodsmetrics/internal/tableview"
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+	"github.com/trezorg/k8spodsmetrics/pkg/client"
+	"github.com/trezorg/k8spodsmetrics/pkg/prometheus"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func (c *commonConfig) Validate() error {
@@ -27,9 +44,107 @@ func (c *commonConfig) Validate() error {
 	if err := tableview.Valid(view); err != nil {
 		return err
 	}
+	if err := c.metricsSourceConfig().Validate(); err != nil {
+		return err
+	}
+	if err := validateConnection(c.Connection); err != nil {
+		return err
+	}
+	if err := validateRetry(c.Retry); err != nil {
+		return err
+	}
 	return alert.Valid(alert.Alert(c.Alert))
 }
 
+// clientOverrides expects a validated config.
+func (c *commonConfig) clientOverrides() client.Overrides {
+	requestTimeout, _ := parseRequestTimeout(c.Connection.RequestTimeout)
+	return client.Overrides{
+		Server:                c.Connection.Server,
+		Token:                 c.Connection.Token,
+		Impersonate:           c.Connection.As,
+		ImpersonateGroups:     c.Connection.AsGroups,
+		CertificateAuthority:  c.Connection.CertificateAuthority,
+		InsecureSkipTLSVerify: c.Connection.InsecureSkipTLSVerify,
+		RequestTimeout:        requestTimeout,
+		QPS:                   c.Connection.ClientQPS,
+		Burst:                 c.Connection.ClientBurst,
+	}
+}
+
+func (c *commonConfig) metricsSourceConfig() metricssource.Config {
+	return metricssource.Config{
+		Source:        metricssource.Source(c.MetricsSource),
+		PrometheusURL: c.Prometheus.URL,
+		PrometheusQueries: prometheus.Queries{
+			Window:     c.Prometheus.RateWindow,
+			PodCPU:     c.Prometheus.PodCPUQuery,
+			PodMemory:  c.Prometheus.PodMemoryQuery,
+			NodeCPU:    c.Prometheus.NodeCPUQuery,
+			NodeMemory: c.Prometheus.NodeMemoryQuery,
+		},
+	}
+}
+
+// retryPolicy expects a validated config. Empty delays use the defaults.
+func (c *commonConfig) retryPolicy() retry.Policy {
+	policy := retry.DefaultPolicy()
+	policy.Retries = int(c.Retries) //nolint:gosec // a retry count fits into int
+	if delay, _ := time.ParseDuration(c.Retry.Delay); delay > 0 {
+		policy.Delay = delay
+	}
+	if maxDelay, _ := time.ParseDuration(c.Retry.MaxDelay); maxDelay > 0 {
+		policy.MaxDelay = maxDelay
+	}
+	return policy
+}
+
+func validateRetry(r config.Retry) error {
+	if err := validateRetryDelay("retry delay", r.Delay); err != nil {
+		return err
+	}
+	return validateRetryDelay("retry max delay", r.MaxDelay)
+}
+
+func validateRetryDelay(name string, value string) error {
+	if value == "" {
+		return nil
+	}
+	if delay, err := time.ParseDuration(value); err != nil || delay <= 0 {
+		return fmt.Errorf("invalid %s %q, expected a positive duration such as 250ms", name, value)
+	}
+	return nil
+}
+
+func validateConnection(c config.Connection) error {
+	if _, err := parseRequestTimeout(c.RequestTimeout); err != nil {
+		return err
+	}
+	if c.ClientQPS < 0 {
+		return fmt.Errorf("client qps must not be negative, got %g", c.ClientQPS)
+	}
+	if c.ClientBurst < 0 {
+		return fmt.Errorf("client burst must not be negative, got %d", c.ClientBurst)
+	}
+	return nil
+}
+
+// parseRequestTimeout reads a request timeout the way kubectl does: a
+// duration such as 10s, or a bare number of seconds.
+func parseRequestTimeout(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid request timeout %q, expected a duration such as 10s or a number of seconds", value)
+	}
+	return timeout, nil
+}
+
 func (c *podConfig) Validate() error {
 	if err := c.commonConfig.Validate(); err != nil {
 		return err
@@ -38,10 +153,15 @@ func (c *podConfig) Validate() error {
 		return err
 	}
 	outputResources := resources.FromStrings(c.Resources...)
-	if err := resources.Valid(outputResources...); err != nil {
+	if err := resources.ValidForPods(outputResources...); err != nil {
 		return err
 	}
 	c.Resources = resources.ToStrings(outputResources...)
+	qosClasses := qos.FromStrings(c.QOSClasses...)
+	if err := qos.Valid(qosClasses...); err != nil {
+		return err
+	}
+	c.QOSClasses = qos.ToStrings(qosClasses...)
 	return nil
 }
 
@@ -60,32 +180,314 @@ func (c *summaryConfig) Validate() error {
 	return nil
 }
 
+func (c *workloadConfig) Validate() error {
+	if err := c.commonConfig.Validate(); err != nil {
+		return err
+	}
+	if err := workloadssorting.Valid(workloadssorting.Sorting(c.Sorting)); err != nil {
+		return err
+	}
+	outputResources := resources.FromStrings(c.Resources...)
+	if err := resources.ValidForPods(outputResources...); err != nil {
+		return err
+	}
+	c.Resources = resources.ToStrings(outputResources...)
+	return nil
+}
+
+func (c *namespaceConfig) Validate() error {
+	if err := c.commonConfig.Validate(); err != nil {
+		return err
+	}
+	if err := namespacessorting.Valid(namespacessorting.Sorting(c.Sorting)); err != nil {
+		return err
+	}
+	outputResources := resources.FromStrings(c.Resources...)
+	if err := resources.Valid(outputResources...); err != nil {
+		return err
+	}
+	c.Resources = resources.ToStrings(outputResources...)
+	return nil
+}
+
+// Validate checks the sampling durations and policies, which are parsed
+// again by recommendationsConfig.
+func (c *recommendConfig) Validate() error {
+	if err := c.commonConfig.Validate(); err != nil {
+		return err
+	}
+	if _, err := parseSamplingDuration("duration", c.Duration); err != nil {
+		return err
+	}
+	if _, err := parseSamplingDuration("interval", c.Interval); err != nil {
+		return err
+	}
+	_, err := c.policies()
+	return err
+}
+
+func parseSamplingDuration(name string, value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid sampling %s %q, expected a positive duration such as 30s", name, value)
+	}
+	return duration, nil
+}
+
+func (c *recommendConfig) policies() (recommendations.Policies, error) {
+	var policies recommendations.Policies
+	for _, policy := range []struct {
+		name   string
+		value  string
+		target *recommendations.Policy
+	}{
+		{name: flagNameCPURequestPolicy, value: c.CPURequestPolicy, target: &policies.CPURequest},
+		{name: flagNameCPULimitPolicy, value: c.CPULimitPolicy, target: &policies.CPULimit},
+		{name: flagNameMemoryRequestPolicy, value: c.MemoryRequestPolicy, target: &policies.MemoryRequest},
+		{name: flagNameMemoryLimitPolicy, value: c.MemoryLimitPolicy, target: &policies.MemoryLimit},
+	} {
+		parsed, err := recommendations.ParsePolicy(policy.value)
+		if err != nil {
+			return recommendations.Policies{}, fmt.Errorf("%s: %w", policy.name, err)
+		}
+		*policy.target = parsed
+	}
+	return policies, nil
+}
+
 func metricsResourcesConfig(c podConfig) metricsresources.Config {
 	return metricsresources.Config{
+		KubeConfig:     c.KubeConfig,
+		KubeContext:    c.KubeContext,
+		Overrides:      c.clientOverrides(),
+		Namespaces:     c.Namespaces,
+		Label:          c.Label,
+		FieldSelector:  c.FieldSelector,
+		Nodes:          c.Nodes,
+		AllNamespaces:  c.AllNamespaces,
+		PartialResults: c.PartialResults,
+		QOSClasses:     c.QOSClasses,
+		Sorting:        c.Sorting,
+		Reverse:        c.Reverse,
+		Alert:          c.Alert,
+		MetricsSource:  c.metricsSourceConfig(),
+		MetricsMaxAge:  time.Duration(c.MetricsMaxAge) * time.Second,
+		WatchPeriod:    c.WatchPeriod,
+		Timeout:        c.Timeout,
+		Retry:          c.retryPolicy(),
+	}
+}
+
+func nodeResourcesConfig(c summaryConfig) noderesources.Config {
+	return noderesources.Config{
+		KubeConfig:        c.KubeConfig,
+		KubeContext:       c.KubeContext,
+		Overrides:         c.clientOverrides(),
+		Label:             c.Label,
+		Name:              c.Name,
+		Sorting:           c.Sorting,
+		Reverse:           c.Reverse,
+		Alert:             c.Alert,
+		MetricsSource:     c.metricsSourceConfig(),
+		MetricsMaxAge:     time.Duration(c.MetricsMaxAge) * time.Second,
+		WatchPeriod:       c.WatchPeriod,
+		Timeout:           c.Timeout,
+		Retry:             c.retryPolicy(),
+		IncludeTerminated: c.IncludeTerminated,
+		GroupByQOS:        c.GroupByQOS,
+		SchedulableOnly:   c.SchedulableOnly,
+	}
+}
+
+func workloadsConfig(c workloadConfig) workloads.Config {
+	return workloads.Config{
 		KubeConfig:    c.KubeConfig,
 		KubeContext:   c.KubeContext,
+		Overrides:     c.clientOverrides(),
 		Namespaces:    c.Namespaces,
+		AllNamespaces: c.AllNamespaces,
 		Label:         c.Label,
 		FieldSelector: c.FieldSelector,
 		Nodes:         c.Nodes,
 		Sorting:       c.Sorting,
 		Reverse:       c.Reverse,
 		Alert:         c.Alert,
+		MetricsSource: c.metricsSourceConfig(),
 		WatchPeriod:   c.WatchPeriod,
 		Timeout:       c.Timeout,
+		Retry:         c.retryPolicy(),
 	}
 }
 
-func nodeResourcesConfig(c summaryConfig) noderesources.Config {
-	return noderesources.Config{
-		KubeConfig:  c.KubeConfig,
-		KubeContext: c.KubeContext,
-		Label:       c.Label,
-		Name:        c.Name,
-		Sorting:     c.Sorting,
-		Reverse:     c.Reverse,
-		Alert:       c.Alert,
-		WatchPeriod: c.WatchPeriod,
-		Timeout:     c.Timeout,
+func namespacesConfig(c namespaceConfig) namespaces.Config {
+	return namespaces.Config{
+		KubeConfig:    c.KubeConfig,
+		KubeContext:   c.KubeContext,
+		Overrides:     c.clientOverrides(),
+		Namespaces:    c.Namespaces,
+		Label:         c.Label,
+		FieldSelector: c.FieldSelector,
+		Sorting:       c.Sorting,
+		Reverse:       c.Reverse,
+		Alert:         c.Alert,
+		MetricsSource: c.metricsSourceConfig(),
+		WatchPeriod:   c.WatchPeriod,
+		Timeout:       c.Timeout,
+		Retry:         c.retryPolicy(),
+	}
+}
+
+// recommendationsConfig expects a validated config.
+func recommendationsConfig(c recommendConfig) recommendations.Config {
+	duration, _ := parseSamplingDuration("duration", c.Duration)
+	interval, _ := parseSamplingDuration("interval", c.Interval)
+	policies, _ := c.policies()
+	return recommendations.Config{
+		KubeConfig:    c.KubeConfig,
+		KubeContext:   c.KubeContext,
+		Overrides:     c.clientOverrides(),
+		Namespaces:    c.Namespaces,
+		AllNamespaces: c.AllNamespaces,
+		Label:         c.Label,
+		FieldSelector: c.FieldSelector,
+		Nodes:         c.Nodes,
+		MetricsSource: c.metricsSourceConfig(),
+		Duration:      duration,
+		Interval:      interval,
+		Policies:      policies,
+		Timeout:       c.Timeout,
+		Retry:         c.retryPolicy(),
+	}
+}
+
+func (c replayConfig) Validate() error {
+	if c.File == "" {
+		return errors.New("replay needs the recording file as an argument")
+	}
+	if _, err := c.at(); err != nil {
+		return err
+	}
+	if c.Speed <= 0 {
+		return fmt.Errorf("replay speed must be greater than 0, got %g", c.Speed)
+	}
+	return nil
+}
+
+func (c replayConfig) at() (time.Time, error) {
+	return parseSnapshotTime("replay", c.At)
+}
+
+// parseSnapshotTime parses the RFC 3339 time snapshots are selected at, an
+// empty value selects the last snapshot.
+func parseSnapshotTime(name string, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s time %q, expected an RFC 3339 time such as 2026-01-02T15:04:05Z", name, value)
+	}
+	return at, nil
+}
+
+// snapshots reads the snapshots of kind to render, all of them from At on
+// for a watch playback and only the one at At otherwise. It expects a
+// validated config.
+func (c replayConfig) snapshots(kind recording.Kind, watch bool) ([]recording.Snapshot, error) {
+	snapshots, err := recording.ReadFile(c.File, kind)
+	if err != nil {
+		return nil, err
+	}
+	at, _ := c.at()
+	if watch {
+		return recording.From(snapshots, at)
+	}
+	snapshot, err := recording.At(snapshots, at)
+	if err != nil {
+		return nil, err
+	}
+	return []recording.Snapshot{snapshot}, nil
+}
+
+// Validate checks the recordings, times and thresholds, which are parsed
+// again by diffServiceConfig.
+func (c *diffConfig) Validate() error {
+	if err := c.commonConfig.Validate(); err != nil {
+		return err
+	}
+	if c.Before == "" {
+		return errors.New("diff needs the before recording as the first argument")
+	}
+	if _, err := parseSnapshotTime(flagNameBeforeAt, c.BeforeAt); err != nil {
+		return err
+	}
+	if _, err := parseSnapshotTime(flagNameAfterAt, c.AfterAt); err != nil {
+		return err
+	}
+	if c.After == "" && c.AfterAt != "" {
+		return fmt.Errorf("--%s needs the after recording as the second argument", flagNameAfterAt)
+	}
+	_, err := c.thresholds()
+	return err
+}
+
+func (c *diffConfig) thresholds() (diff.Thresholds, error) {
+	cpuThreshold, err := parseThreshold(flagNameCPUThreshold, c.CPUThreshold)
+	if err != nil {
+		return diff.Thresholds{}, err
+	}
+	memoryThreshold, err := parseThreshold(flagNameMemoryThreshold, c.MemoryThreshold)
+	if err != nil {
+		return diff.Thresholds{}, err
+	}
+	if c.PercentThreshold < 0 {
+		return diff.Thresholds{}, fmt.Errorf("%s must not be negative, got %g", flagNamePercentThreshold, c.PercentThreshold)
+	}
+	return diff.Thresholds{
+		CPU:     cpuThreshold.MilliValue(),
+		Memory:  memoryThreshold.Value(),
+		Percent: c.PercentThreshold,
+	}, nil
+}
+
+func parseThreshold(name string, value string) (resource.Quantity, error) {
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil || quantity.Sign() < 0 {
+		return resource.Quantity{}, fmt.Errorf("invalid %s %q, expected a non-negative quantity such as 10m or 16Mi", name, value)
+	}
+	return quantity, nil
+}
+
+// diffServiceConfig expects a validated config.
+func diffServiceConfig(c diffConfig) diff.Config {
+	beforeAt, _ := parseSnapshotTime(flagNameBeforeAt, c.BeforeAt)
+	afterAt, _ := parseSnapshotTime(flagNameAfterAt, c.AfterAt)
+	thresholds, _ := c.thresholds()
+	return diff.Config{
+		Before:     c.Before,
+		BeforeAt:   beforeAt,
+		After:      c.After,
+		AfterAt:    afterAt,
+		Namespaces: c.Namespaces,
+		Thresholds: thresholds,
+		Pods: metricsresources.Config{
+			KubeConfig:     c.KubeConfig,
+			KubeContext:    c.KubeContext,
+			Overrides:      c.clientOverrides(),
+			PartialResults: true,
+			MetricsSource:  c.metricsSourceConfig(),
+			MetricsMaxAge:  time.Duration(c.MetricsMaxAge) * time.Second,
+			Timeout:        c.Timeout,
+			Retry:          c.retryPolicy(),
+		},
+		Nodes: noderesources.Config{
+			KubeConfig:    c.KubeConfig,
+			KubeContext:   c.KubeContext,
+			Overrides:     c.clientOverrides(),
+			MetricsSource: c.metricsSourceConfig(),
+			MetricsMaxAge: time.Duration(c.MetricsMaxAge) * time.Second,
+			Timeout:       c.Timeout,
+			Retry:         c.retryPolicy(),
+		},
 	}
 }
@@ -3,9 +3,12 @@ package stdin
 import (
 	"flag"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/trezorg/k8spodsmetrics/internal/config"
+	"github.com/trezorg/k8spodsmetrics/pkg/client"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	"github.com/urfave/cli/v2"
 )
 
@@ -72,6 +75,108 @@ func TestCommonConfigValidate(t *testing.T) {
 
 		require.ErrorContains(t, cfg.Validate(), "table view should be one of")
 	})
+
+	t.Run("invalid metrics source", func(t *testing.T) {
+		cfg := commonConfig{
+			Output:        "table",
+			Alert:         "none",
+			MetricsSource: "invalid",
+			WatchPeriod:   5,
+		}
+
+		require.ErrorContains(t, cfg.Validate(), "metrics source should be one of")
+	})
+
+	t.Run("prometheus metrics source", func(t *testing.T) {
+		cfg := commonConfig{
+			Output:        "table",
+			Alert:         "none",
+			MetricsSource: "prometheus",
+			WatchPeriod:   5,
+		}
+		require.ErrorContains(t, cfg.Validate(), "prometheus url is required")
+
+		cfg.Prometheus = config.Prometheus{URL: "http://prometheus:9090", RateWindow: "five"}
+		require.ErrorContains(t, cfg.Validate(), "prometheus rate window")
+
+		cfg.Prometheus.RateWindow = "2m"
+		require.NoError(t, cfg.Validate())
+		source := cfg.metricsSourceConfig()
+		require.Equal(t, "http://prometheus:9090", source.PrometheusURL)
+		require.Equal(t, "2m", source.PrometheusQueries.Window)
+	})
+}
+
+func TestCommonConfigValidateConnection(t *testing.T) {
+	tests := []struct {
+		name       string
+		connection config.Connection
+		err        string
+	}{
+		{name: "empty", connection: config.Connection{}},
+		{name: "duration timeout", connection: config.Connection{RequestTimeout: "1m30s"}},
+		{name: "seconds timeout", connection: config.Connection{RequestTimeout: "0"}},
+		{name: "invalid timeout", connection: config.Connection{RequestTimeout: "soon"}, err: `invalid request timeout "soon"`},
+		{name: "negative timeout", connection: config.Connection{RequestTimeout: "-1s"}, err: `invalid request timeout "-1s"`},
+		{name: "negative qps", connection: config.Connection{ClientQPS: -1}, err: "client qps must not be negative"},
+		{name: "negative burst", connection: config.Connection{ClientBurst: -1}, err: "client burst must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := commonConfig{Output: "table", Alert: "none", Connection: tt.connection}
+			err := cfg.Validate()
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCommonConfigRetryPolicy(t *testing.T) {
+	t.Run("defaults delays", func(t *testing.T) {
+		cfg := commonConfig{Retries: 5}
+		require.NoError(t, validateRetry(cfg.Retry))
+		require.Equal(t, retry.Policy{Retries: 5, Delay: retry.DefaultDelay, MaxDelay: retry.DefaultMaxDelay}, cfg.retryPolicy())
+	})
+
+	t.Run("parses delays", func(t *testing.T) {
+		cfg := commonConfig{Retry: config.Retry{Delay: "100ms", MaxDelay: "2s"}}
+		require.NoError(t, validateRetry(cfg.Retry))
+		require.Equal(t, retry.Policy{Delay: 100 * time.Millisecond, MaxDelay: 2 * time.Second}, cfg.retryPolicy())
+	})
+
+	t.Run("rejects invalid delays", func(t *testing.T) {
+		cfg := commonConfig{Output: "table", Alert: "none", Retry: config.Retry{MaxDelay: "0s"}}
+		require.ErrorContains(t, cfg.Validate(), `invalid retry max delay "0s"`)
+	})
+}
+
+func TestCommonConfigClientOverrides(t *testing.T) {
+	cfg := commonConfig{Connection: config.Connection{
+		Server:                "https://10.0.0.1:6443",
+		Token:                 "secret",
+		As:                    "jane",
+		AsGroups:              []string{"developers"},
+		CertificateAuthority:  "/etc/ca.crt",
+		InsecureSkipTLSVerify: true,
+		RequestTimeout:        "15",
+		ClientQPS:             50,
+		ClientBurst:           100,
+	}}
+
+	require.Equal(t, client.Overrides{
+		Server:                "https://10.0.0.1:6443",
+		Token:                 "secret",
+		Impersonate:           "jane",
+		ImpersonateGroups:     []string{"developers"},
+		CertificateAuthority:  "/etc/ca.crt",
+		InsecureSkipTLSVerify: true,
+		RequestTimeout:        15 * time.Second,
+		QPS:                   50,
+		Burst:                 100,
+	}, cfg.clientOverrides())
 }
 
 func TestPodConfigValidate(t *testing.T) {
@@ -106,6 +211,210 @@ func TestPodConfigValidate(t *testing.T) {
 
 		require.ErrorContains(t, cfg.Validate(), "invalid resource")
 	})
+
+	t.Run("invalid qos", func(t *testing.T) {
+		cfg := podConfig{
+			Sorting:    "namespace",
+			Resources:  []string{"all"},
+			QOSClasses: []string{"premium"},
+			commonConfig: commonConfig{
+				Output:      "table",
+				Alert:       "none",
+				WatchPeriod: 5,
+			},
+		}
+
+		require.ErrorContains(t, cfg.Validate(), "qos should be one of")
+	})
+
+	t.Run("normalizes qos", func(t *testing.T) {
+		cfg := podConfig{
+			Sorting:    "qos",
+			Resources:  []string{"all"},
+			QOSClasses: []string{"BestEffort", "besteffort", "Burstable"},
+			commonConfig: commonConfig{
+				Output:      "table",
+				Alert:       "none",
+				WatchPeriod: 5,
+			},
+		}
+
+		require.NoError(t, cfg.Validate())
+		require.Equal(t, []string{"besteffort", "burstable"}, cfg.QOSClasses)
+	})
+}
+
+func TestWorkloadConfigValidate(t *testing.T) {
+	t.Run("invalid sorting", func(t *testing.T) {
+		cfg := workloadConfig{
+			Sorting:   "node",
+			Resources: []string{"all"},
+			commonConfig: commonConfig{
+				Output:      "table",
+				Alert:       "none",
+				WatchPeriod: 5,
+			},
+		}
+
+		require.ErrorContains(t, cfg.Validate(), "sorting should be one of")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		cfg := workloadConfig{
+			Sorting:   "replicas",
+			Resources: []string{"cpu"},
+			commonConfig: commonConfig{
+				Output:      "json",
+				Alert:       "cpu",
+				WatchPeriod: 5,
+			},
+		}
+
+		require.NoError(t, cfg.Validate())
+	})
+}
+
+func TestRecommendConfigValidate(t *testing.T) {
+	valid := func() recommendConfig {
+		return recommendConfig{
+			Duration:            "10m",
+			Interval:            "30s",
+			CPURequestPolicy:    "p90",
+			CPULimitPolicy:      "none",
+			MemoryRequestPolicy: "p99",
+			MemoryLimitPolicy:   "max*1.2",
+			commonConfig:        commonConfig{Output: "table", Alert: "none"},
+		}
+	}
+
+	t.Run("valid config", func(t *testing.T) {
+		cfg := valid()
+		require.NoError(t, cfg.Validate())
+
+		result := recommendationsConfig(cfg)
+		require.Equal(t, 10*time.Minute, result.Duration)
+		require.Equal(t, 30*time.Second, result.Interval)
+		require.Equal(t, "none", result.Policies.CPULimit.String())
+		require.Equal(t, "max*1.2", result.Policies.MemoryLimit.String())
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		cfg := valid()
+		cfg.Interval = "0s"
+		require.ErrorContains(t, cfg.Validate(), "invalid sampling interval")
+	})
+
+	t.Run("invalid policy names the flag", func(t *testing.T) {
+		cfg := valid()
+		cfg.MemoryRequestPolicy = "avg"
+		require.ErrorContains(t, cfg.Validate(), "memory-request-policy: invalid policy")
+	})
+}
+
+func TestReplayConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  replayConfig
+		err  string
+	}{
+		{name: "valid config", cfg: replayConfig{File: "recording.jsonl", Speed: 1}},
+		{name: "valid time", cfg: replayConfig{File: "recording.jsonl", At: "2026-03-01T12:00:00Z", Speed: 0.5}},
+		{name: "missing file", cfg: replayConfig{Speed: 1}, err: "replay needs the recording file"},
+		{name: "invalid time", cfg: replayConfig{File: "recording.jsonl", At: "12:00", Speed: 1}, err: "invalid replay time"},
+		{name: "zero speed", cfg: replayConfig{File: "recording.jsonl"}, err: "replay speed must be greater than 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDiffConfigValidate(t *testing.T) {
+	valid := func() diffConfig {
+		return diffConfig{
+			Before:           "before.jsonl",
+			CPUThreshold:     "10m",
+			MemoryThreshold:  "16Mi",
+			PercentThreshold: 10,
+			commonConfig:     commonConfig{Output: "table", Alert: "none"},
+		}
+	}
+
+	t.Run("valid config", func(t *testing.T) {
+		cfg := valid()
+		cfg.After = "after.jsonl"
+		cfg.BeforeAt = "2026-03-01T12:00:00Z"
+		cfg.CPUThreshold = "0.5"
+		require.NoError(t, cfg.Validate())
+
+		result := diffServiceConfig(cfg)
+		require.Equal(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), result.BeforeAt)
+		require.True(t, result.AfterAt.IsZero())
+		require.Equal(t, int64(500), result.Thresholds.CPU)
+		require.Equal(t, int64(16<<20), result.Thresholds.Memory)
+		require.InDelta(t, 10, result.Thresholds.Percent, 0)
+		require.True(t, result.Pods.PartialResults)
+	})
+
+	tests := []struct {
+		name   string
+		modify func(*diffConfig)
+		err    string
+	}{
+		{name: "missing before", modify: func(c *diffConfig) { c.Before = "" }, err: "diff needs the before recording"},
+		{name: "invalid before time", modify: func(c *diffConfig) { c.BeforeAt = "yesterday" }, err: "invalid before-at time"},
+		{
+			name:   "after time without after recording",
+			modify: func(c *diffConfig) { c.AfterAt = "2026-03-01T12:00:00Z" },
+			err:    "--after-at needs the after recording",
+		},
+		{name: "invalid cpu threshold", modify: func(c *diffConfig) { c.CPUThreshold = "lots" }, err: "invalid cpu-threshold"},
+		{name: "negative memory threshold", modify: func(c *diffConfig) { c.MemoryThreshold = "-1Mi" }, err: "invalid memory-threshold"},
+		{name: "negative percent", modify: func(c *diffConfig) { c.PercentThreshold = -1 }, err: "percent-threshold must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(&cfg)
+			require.ErrorContains(t, cfg.Validate(), tt.err)
+		})
+	}
+}
+
+func TestNamespaceConfigValidate(t *testing.T) {
+	t.Run("invalid sorting", func(t *testing.T) {
+		cfg := namespaceConfig{
+			Sorting:   "namespace",
+			Resources: []string{"all"},
+			commonConfig: commonConfig{
+				Output:      "table",
+				Alert:       "none",
+				WatchPeriod: 5,
+			},
+		}
+
+		require.ErrorContains(t, cfg.Validate(), "sorting should be one of")
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		cfg := namespaceConfig{
+			Sorting:   "quota_pods",
+			Resources: []string{"cpu", "pods"},
+			commonConfig: commonConfig{
+				Output:      "yaml",
+				Alert:       "pods",
+				WatchPeriod: 5,
+			},
+		}
+
+		require.NoError(t, cfg.Validate())
+	})
 }
 
 func TestSummaryConfigValidate(t *testing.T) {
@@ -0,0 +1,52 @@
+package stdin
+
+import (
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	flagNameBeforeAt         = "before-at"
+	flagNameAfterAt          = "after-at"
+	flagNameCPUThreshold     = "cpu-threshold"
+	flagNameMemoryThreshold  = "memory-threshold"
+	flagNamePercentThreshold = "percent-threshold"
+
+	defaultCPUThreshold    = "10m"
+	defaultMemoryThreshold = "16Mi"
+)
+
+func diffFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    flagNameNamespace,
+			Aliases: []string{"n"},
+			Usage:   "K8S namespace(s) of the compared pods, the namespaces the recording requested by default",
+		},
+		&cli.StringFlag{
+			Name:  flagNameBeforeAt,
+			Value: "",
+			Usage: "Compare the last snapshots of the before recording taken at or before this RFC 3339 time. The last snapshots by default",
+		},
+		&cli.StringFlag{
+			Name:  flagNameAfterAt,
+			Value: "",
+			Usage: "Compare the last snapshots of the after recording taken at or before this RFC 3339 time. The last snapshots by default",
+		},
+		&cli.StringFlag{
+			Name:  flagNameCPUThreshold,
+			Value: defaultCPUThreshold,
+			Usage: "Ignore CPU usage changes smaller than this quantity",
+		},
+		&cli.StringFlag{
+			Name:  flagNameMemoryThreshold,
+			Value: defaultMemoryThreshold,
+			Usage: "Ignore memory usage changes smaller than this quantity",
+		},
+		&cli.Float64Flag{
+			Name:  flagNamePercentThreshold,
+			Value: diff.DefaultPercentThreshold,
+			Usage: "Ignore usage changes smaller than this percent of the before value",
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package stdin
+
+import (
+	"fmt"
+
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	namespacessorting "github.com/trezorg/k8spodsmetrics/internal/sorting/namespaces"
+	"github.com/urfave/cli/v2"
+)
+
+func namespacesFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    flagNameNamespace,
+			Aliases: []string{"n"},
+			Usage:   "K8S namespace(s)",
+		},
+		&cli.StringFlag{
+			Name:    "label",
+			Aliases: []string{"l"},
+			Value:   "",
+			Usage:   "K8S pod label",
+		},
+		&cli.StringFlag{
+			Name:    "field-selector",
+			Aliases: []string{"f"},
+			Value:   "",
+			Usage:   "K8S pod field selector",
+		},
+		&cli.StringFlag{
+			Name:    "sorting",
+			Aliases: []string{"s"},
+			Value:   string(namespacessorting.Name),
+			Usage:   fmt.Sprintf("Sorting. [%s]", namespacessorting.StringListDefault()),
+			Action: func(_ *cli.Context, value string) error {
+				return namespacessorting.Valid(namespacessorting.Sorting(value))
+			},
+		},
+		&cli.BoolFlag{
+			Name:    "reverse",
+			Aliases: []string{"r"},
+			Value:   false,
+			Usage:   "Reverse sort",
+		},
+		&cli.StringSliceFlag{
+			Name:    flagNameResources,
+			Aliases: []string{"res", "resource"},
+			Value:   cli.NewStringSlice(string(resources.All)),
+			Usage:   fmt.Sprintf("Resources. [%s]. Namespaces show cpu, memory and pods only", resources.StringListDefault()),
+			Action: func(_ *cli.Context, value []string) error {
+				outputResources := resources.FromStrings(value...)
+				return resources.Valid(outputResources...)
+			},
+		},
+	}
+}
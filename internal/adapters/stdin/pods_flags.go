@@ -3,8 +3,10 @@ package stdin
 import (
 	"fmt"
 
+	"github.com/trezorg/k8spodsmetrics/internal/qos"
 	"github.com/trezorg/k8spodsmetrics/internal/resources"
 	metricssorting "github.com/trezorg/k8spodsmetrics/internal/sorting/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"github.com/urfave/cli/v2"
 )
 
@@ -13,7 +15,18 @@ func podsFlags() []cli.Flag {
 		&cli.StringSliceFlag{
 			Name:    flagNameNamespace,
 			Aliases: []string{"n"},
-			Usage:   "K8S namespace(s)",
+			Usage:   "K8S namespace(s), the namespace of the context by default",
+		},
+		&cli.BoolFlag{
+			Name:    flagNameAllNamespaces,
+			Aliases: []string{"A"},
+			Value:   false,
+			Usage:   "Query all namespaces when --namespace is not set, even if the context names a namespace",
+		},
+		&cli.BoolFlag{
+			Name:  flagNamePartialResults,
+			Value: false,
+			Usage: fmt.Sprintf("Render the pods that could be listed when some namespaces, nodes or clusters fail, warn about the failed ones and exit with code %d", partial.ExitCode),
 		},
 		&cli.StringFlag{
 			Name:    "label",
@@ -32,11 +45,18 @@ func podsFlags() []cli.Flag {
 			Aliases: []string{"nd", "nodes"},
 			Usage:   "K8S node names",
 		},
+		&cli.StringSliceFlag{
+			Name:  "qos",
+			Usage: fmt.Sprintf("QoS classes. [%s]", qos.StringListDefault()),
+			Action: func(_ *cli.Context, value []string) error {
+				return qos.Valid(qos.FromStrings(value...)...)
+			},
+		},
 		&cli.StringFlag{
 			Name:    "sorting",
 			Aliases: []string{"s"},
 			Value:   string(metricssorting.Namespace),
-			Usage:   fmt.Sprintf("Sorting. [%s]", metricssorting.StringListDefault()),
+			Usage:   fmt.Sprintf("Sorting. [%s] or <request|limit>_<extended resource>", metricssorting.StringListDefault()),
 			Action: func(_ *cli.Context, value string) error {
 				return metricssorting.Valid(metricssorting.Sorting(value))
 			},
@@ -51,11 +71,17 @@ func podsFlags() []cli.Flag {
 			Name:    flagNameResources,
 			Aliases: []string{"res", "resource"},
 			Value:   cli.NewStringSlice(string(resources.All)),
-			Usage:   fmt.Sprintf("Resources. [%s]", resources.StringListDefault()),
+			Usage:   fmt.Sprintf("Resources. [%s] or an extended resource name, e.g. nvidia.com/gpu", resources.PodStringListDefault()),
 			Action: func(_ *cli.Context, value []string) error {
 				outputResources := resources.FromStrings(value...)
-				return resources.Valid(outputResources...)
+				return resources.ValidForPods(outputResources...)
 			},
 		},
+		&cli.BoolFlag{
+			Name:  flagNameEfficiency,
+			Value: false,
+			Usage: "Add CPU and memory usage as a percentage of requests and limits and the idle requested resources to tables, with the idle resources of every namespace",
+		},
+		recordFlag(),
 	}
 }
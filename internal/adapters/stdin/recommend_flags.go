@@ -0,0 +1,89 @@
+package stdin
+
+import (
+	"fmt"
+
+	"github.com/trezorg/k8spodsmetrics/internal/recommendations"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	flagNameDuration            = "duration"
+	flagNameInterval            = "interval"
+	flagNameCPURequestPolicy    = "cpu-request-policy"
+	flagNameCPULimitPolicy      = "cpu-limit-policy"
+	flagNameMemoryRequestPolicy = "memory-request-policy"
+	flagNameMemoryLimitPolicy   = "memory-limit-policy"
+)
+
+func recommendFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    flagNameNamespace,
+			Aliases: []string{"n"},
+			Usage:   "K8S namespace(s), the namespace of the context by default",
+		},
+		&cli.BoolFlag{
+			Name:    flagNameAllNamespaces,
+			Aliases: []string{"A"},
+			Value:   false,
+			Usage:   "Query all namespaces when --namespace is not set, even if the context names a namespace",
+		},
+		&cli.StringFlag{
+			Name:    "label",
+			Aliases: []string{"l"},
+			Value:   "",
+			Usage:   "K8S pod label",
+		},
+		&cli.StringFlag{
+			Name:    "field-selector",
+			Aliases: []string{"f"},
+			Value:   "",
+			Usage:   "K8S pod field selector",
+		},
+		&cli.StringSliceFlag{
+			Name:    "node",
+			Aliases: []string{"nd", "nodes"},
+			Usage:   "K8S node names",
+		},
+		&cli.StringFlag{
+			Name:  flagNameDuration,
+			Value: "",
+			Usage: fmt.Sprintf("How long usage is sampled for, such as 30m or 1h (default: %s)", recommendations.DefaultDuration),
+		},
+		&cli.StringFlag{
+			Name:  flagNameInterval,
+			Value: "",
+			Usage: fmt.Sprintf("Time between usage samples (default: %s)", recommendations.DefaultInterval),
+		},
+		&cli.StringFlag{
+			Name:  flagNameCPURequestPolicy,
+			Value: "",
+			Usage: policyUsage("CPU request", recommendations.DefaultRequestPolicy),
+		},
+		&cli.StringFlag{
+			Name:  flagNameCPULimitPolicy,
+			Value: "",
+			Usage: policyUsage("CPU limit", recommendations.DefaultLimitPolicy),
+		},
+		&cli.StringFlag{
+			Name:  flagNameMemoryRequestPolicy,
+			Value: "",
+			Usage: policyUsage("Memory request", recommendations.DefaultRequestPolicy),
+		},
+		&cli.StringFlag{
+			Name:  flagNameMemoryLimitPolicy,
+			Value: "",
+			Usage: policyUsage("Memory limit", recommendations.DefaultLimitPolicy),
+		},
+	}
+}
+
+func policyUsage(value string, defaultPolicy string) string {
+	return fmt.Sprintf(
+		"%s policy: a usage statistic [%s] optionally multiplied as in max*1.2 (default: %s)",
+		value,
+		recommendations.StringListDefault(),
+		defaultPolicy,
+	)
+}
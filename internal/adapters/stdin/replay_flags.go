@@ -0,0 +1,63 @@
+package stdin
+
+import (
+	"slices"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	flagNameAt    = "at"
+	flagNameSpeed = "speed"
+
+	defaultReplaySpeed = 1
+)
+
+// replayPodsFlags are the pods flags that apply to recorded pods. Label and
+// field selectors were applied by the API when the pods were recorded.
+func replayPodsFlags() []cli.Flag {
+	flags := flagsNamed(podsFlags(), flagNameNamespace, "node", "qos", "sorting", "reverse", flagNameResources, flagNameEfficiency)
+	return append(flags, replayFlags()...)
+}
+
+// replaySummaryFlags are the summary flags that apply to recorded nodes.
+// Terminated pods and QoS classes were accounted when the nodes were recorded.
+func replaySummaryFlags() []cli.Flag {
+	flags := flagsNamed(
+		summaryFlags(),
+		"label",
+		flagNameName,
+		"sorting",
+		"reverse",
+		flagNameResources,
+		flagNameSchedulableOnly,
+		flagNameGroupByLabel,
+	)
+	return append(flags, replayFlags()...)
+}
+
+func replayFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  flagNameAt,
+			Value: "",
+			Usage: "Render the last snapshot taken at or before this RFC 3339 time, or start the watch playback there. The last snapshot by default",
+		},
+		&cli.Float64Flag{
+			Name:  flagNameSpeed,
+			Value: defaultReplaySpeed,
+			Usage: "Watch playback speed, 2 plays twice as fast as recorded",
+		},
+	}
+}
+
+// flagsNamed returns the flags named one of names in their order in flags.
+func flagsNamed(flags []cli.Flag, names ...string) []cli.Flag {
+	var result []cli.Flag
+	for _, flag := range flags {
+		if slices.Contains(names, flag.Names()[0]) {
+			result = append(result, flag)
+		}
+	}
+	return result
+}
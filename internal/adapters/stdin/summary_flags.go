@@ -26,7 +26,7 @@ func summaryFlags() []cli.Flag {
 			Name:    "sorting",
 			Aliases: []string{"s"},
 			Value:   string(nodesorting.Name),
-			Usage:   fmt.Sprintf("Sorting. [%s]", nodesorting.StringListDefault()),
+			Usage:   fmt.Sprintf("Sorting. [%s] or <total|allocatable|request|limit|available>_<extended resource>", nodesorting.StringListDefault()),
 			Action: func(_ *cli.Context, value string) error {
 				return nodesorting.Valid(nodesorting.Sorting(value))
 			},
@@ -41,11 +41,31 @@ func summaryFlags() []cli.Flag {
 			Name:    flagNameResources,
 			Aliases: []string{"res", "resource"},
 			Value:   cli.NewStringSlice(string(resources.All)),
-			Usage:   fmt.Sprintf("Resources. [%s]", resources.StringListDefault()),
+			Usage:   fmt.Sprintf("Resources. [%s] or an extended resource name, e.g. nvidia.com/gpu", resources.StringListDefault()),
 			Action: func(_ *cli.Context, value []string) error {
 				outputResources := resources.FromStrings(value...)
 				return resources.Valid(outputResources...)
 			},
 		},
+		&cli.BoolFlag{
+			Name:  flagNameIncludeTerminated,
+			Value: false,
+			Usage: "Count Succeeded and Failed pods in node requests and limits",
+		},
+		&cli.BoolFlag{
+			Name:  flagNameGroupByQOS,
+			Value: false,
+			Usage: "Break node requests and limits down by pod QoS class",
+		},
+		&cli.BoolFlag{
+			Name:  flagNameSchedulableOnly,
+			Value: false,
+			Usage: "Show only nodes new pods can be scheduled on: Ready, not cordoned and without NoSchedule or NoExecute taints",
+		},
+		&cli.StringFlag{
+			Name:  flagNameGroupByLabel,
+			Usage: "Aggregate nodes by the value of a label key, e.g. topology.kubernetes.io/zone, with per-group subtotals",
+		},
+		recordFlag(),
 	}
 }
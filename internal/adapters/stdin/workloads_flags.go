@@ -0,0 +1,67 @@
+package stdin
+
+import (
+	"fmt"
+
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	workloadssorting "github.com/trezorg/k8spodsmetrics/internal/sorting/workloads"
+	"github.com/urfave/cli/v2"
+)
+
+func workloadsFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    flagNameNamespace,
+			Aliases: []string{"n"},
+			Usage:   "K8S namespace(s), the namespace of the context by default",
+		},
+		&cli.BoolFlag{
+			Name:    flagNameAllNamespaces,
+			Aliases: []string{"A"},
+			Value:   false,
+			Usage:   "Query all namespaces when --namespace is not set, even if the context names a namespace",
+		},
+		&cli.StringFlag{
+			Name:    "label",
+			Aliases: []string{"l"},
+			Value:   "",
+			Usage:   "K8S pod label",
+		},
+		&cli.StringFlag{
+			Name:    "field-selector",
+			Aliases: []string{"f"},
+			Value:   "",
+			Usage:   "K8S pod field selector",
+		},
+		&cli.StringSliceFlag{
+			Name:    "node",
+			Aliases: []string{"nd", "nodes"},
+			Usage:   "K8S node names",
+		},
+		&cli.StringFlag{
+			Name:    "sorting",
+			Aliases: []string{"s"},
+			Value:   string(workloadssorting.Namespace),
+			Usage:   fmt.Sprintf("Sorting. [%s]", workloadssorting.StringListDefault()),
+			Action: func(_ *cli.Context, value string) error {
+				return workloadssorting.Valid(workloadssorting.Sorting(value))
+			},
+		},
+		&cli.BoolFlag{
+			Name:    "reverse",
+			Aliases: []string{"r"},
+			Value:   false,
+			Usage:   "Reverse sort",
+		},
+		&cli.StringSliceFlag{
+			Name:    flagNameResources,
+			Aliases: []string{"res", "resource"},
+			Value:   cli.NewStringSlice(string(resources.All)),
+			Usage:   fmt.Sprintf("Resources. [%s]. Workloads show cpu and memory only", resources.PodStringListDefault()),
+			Action: func(_ *cli.Context, value []string) error {
+				outputResources := resources.FromStrings(value...)
+				return resources.ValidForPods(outputResources...)
+			},
+		},
+	}
+}
@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"strconv"
+	"strings"
+
+	escapes "github.com/snugfox/ansi-escapes"
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+	"github.com/trezorg/k8spodsmetrics/internal/humanize"
+)
+
+const (
+	unset        = "-"
+	changedArrow = " → "
+)
+
+// Formatter renders the values of one added, removed or changed object.
+type Formatter struct {
+	change diff.Change
+}
+
+func New(change diff.Change) Formatter {
+	return Formatter{change: change}
+}
+
+func CPU(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
+func Memory(value int64) string {
+	return humanize.Bytes(value)
+}
+
+func Count(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
+// PodName returns the kind and name of a workload, the name of a pod without
+// a controller.
+func PodName(pod diff.Pod) string {
+	if pod.Kind == "" {
+		return pod.Name
+	}
+	return pod.Kind + "/" + pod.Name
+}
+
+// Name returns name prefixed with its cluster when the diff spans clusters.
+func Name(cluster string, names ...string) string {
+	if cluster != "" {
+		names = append([]string{cluster}, names...)
+	}
+	return strings.Join(names, "/")
+}
+
+// ChangeString returns the change coloured green for added, red for removed
+// and yellow for changed objects.
+func (f Formatter) ChangeString() string {
+	return f.colored(string(f.change))
+}
+
+// ValueString returns the value of an added or removed object and
+// before → after (delta) of a changed one, unset when it did not change.
+func (f Formatter) ValueString(value *diff.Value, format func(int64) string) string {
+	return f.colored(f.plainValue(value, format))
+}
+
+// PlainValueString is ValueString without colours.
+func (f Formatter) PlainValueString(value *diff.Value, format func(int64) string) string {
+	return f.plainValue(value, format)
+}
+
+func (f Formatter) plainValue(value *diff.Value, format func(int64) string) string {
+	switch {
+	case value == nil:
+		return unset
+	case f.change == diff.Added:
+		return format(value.After)
+	case f.change == diff.Removed:
+		return format(value.Before)
+	}
+	return format(value.Before) + changedArrow + format(value.After) + " (" + delta(value.Delta, format) + ")"
+}
+
+func delta(value int64, format func(int64) string) string {
+	if value > 0 {
+		return "+" + format(value)
+	}
+	return format(value)
+}
+
+func (f Formatter) colored(value string) string {
+	if value == unset {
+		return value
+	}
+	switch f.change {
+	case diff.Added:
+		return colored(value, escapes.TextColorGreen)
+	case diff.Removed:
+		return colored(value, escapes.TextColorRed)
+	case diff.Changed:
+		return colored(value, escapes.TextColorYellow)
+	}
+	return value
+}
+
+func colored(value, color string) string {
+	return color + value + escapes.ColorReset
+}
@@ -0,0 +1,48 @@
+package diff
+
+import (
+	"testing"
+
+	escapes "github.com/snugfox/ansi-escapes"
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+)
+
+func TestValueString(t *testing.T) {
+	tests := []struct {
+		name   string
+		change diff.Change
+		value  *diff.Value
+		format func(int64) string
+		want   string
+	}{
+		{name: "unchanged", change: diff.Changed, format: CPU, want: "-"},
+		{name: "added", change: diff.Added, value: &diff.Value{After: 100, Delta: 100}, format: CPU, want: "100"},
+		{name: "removed", change: diff.Removed, value: &diff.Value{Before: 64 << 20, Delta: -64 << 20}, format: Memory, want: "64MiB"},
+		{name: "increased", change: diff.Changed, value: &diff.Value{Before: 100, After: 250, Delta: 150}, format: CPU, want: "100 → 250 (+150)"},
+		{
+			name:   "decreased",
+			change: diff.Changed,
+			value:  &diff.Value{Before: 64 << 20, After: 32 << 20, Delta: -32 << 20},
+			format: Memory,
+			want:   "64MiB → 32MiB (-32MiB)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, New(tt.change).PlainValueString(tt.value, tt.format))
+		})
+	}
+}
+
+func TestColors(t *testing.T) {
+	require.Equal(t, escapes.TextColorGreen+"added"+escapes.ColorReset, New(diff.Added).ChangeString())
+	require.Equal(t, escapes.TextColorRed+"removed"+escapes.ColorReset, New(diff.Removed).ChangeString())
+	require.Equal(t, escapes.TextColorYellow+"1 → 2 (+1)"+escapes.ColorReset, New(diff.Changed).ValueString(&diff.Value{Before: 1, After: 2, Delta: 1}, CPU))
+	require.Equal(t, "-", New(diff.Changed).ValueString(nil, CPU))
+}
+
+func TestName(t *testing.T) {
+	require.Equal(t, "default/web", Name("", "default", "web"))
+	require.Equal(t, "east/node-1", Name("east", "node-1"))
+}
@@ -0,0 +1,102 @@
+package metricsresources
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/trezorg/k8spodsmetrics/internal/humanize"
+	servicemetricsresources "github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+)
+
+const (
+	millicoresPerCore = 1000
+	bytesPerGiB       = 1 << 30
+)
+
+// EfficiencyFormatter formats the usage of a container or pod relative to
+// its requests and limits. Cells are empty when usage is unknown.
+type EfficiencyFormatter struct {
+	efficiency  servicemetricsresources.Efficiency
+	known       bool
+	unavailable bool
+}
+
+// Efficiency returns the formatter of the usage relative to requests and
+// limits.
+func (f ContainerFormatter) Efficiency() EfficiencyFormatter {
+	efficiency, known := f.resource.Efficiency()
+	return EfficiencyFormatter{efficiency: efficiency, known: known, unavailable: f.resource.MetricsUnavailable}
+}
+
+// NewTotalEfficiency formats the efficiency of summed pods from the pods
+// waste counts, those with usage: the idle resources of waste do not let the
+// pods using more than requested offset the idle ones, and the percentages
+// leave out the requests of pods without usage.
+func NewTotalEfficiency(waste servicemetricsresources.Waste) EfficiencyFormatter {
+	efficiency, known := waste.Efficiency()
+	return EfficiencyFormatter{efficiency: efficiency, known: known}
+}
+
+func (f EfficiencyFormatter) CPURequestPercent() string {
+	return f.percent(f.efficiency.CPURequestPercent)
+}
+
+func (f EfficiencyFormatter) CPULimitPercent() string {
+	return f.percent(f.efficiency.CPULimitPercent)
+}
+
+func (f EfficiencyFormatter) MemoryRequestPercent() string {
+	return f.percent(f.efficiency.MemoryRequestPercent)
+}
+
+func (f EfficiencyFormatter) MemoryLimitPercent() string {
+	return f.percent(f.efficiency.MemoryLimitPercent)
+}
+
+// CPUIdle formats the idle requested CPU in millicores.
+func (f EfficiencyFormatter) CPUIdle() string {
+	return f.value(func() string { return fmt.Sprintf("%d", f.efficiency.CPUIdle) })
+}
+
+func (f EfficiencyFormatter) MemoryIdle() string {
+	return f.value(func() string { return humanize.Bytes(f.efficiency.MemoryIdle) })
+}
+
+// CPUCompactString formats request%/limit%/idle of CPU.
+func (f EfficiencyFormatter) CPUCompactString() string {
+	return compactTriple(f.CPURequestPercent(), f.CPULimitPercent(), f.CPUIdle())
+}
+
+// MemoryCompactString formats request%/limit%/idle of memory.
+func (f EfficiencyFormatter) MemoryCompactString() string {
+	return compactTriple(f.MemoryRequestPercent(), f.MemoryLimitPercent(), f.MemoryIdle())
+}
+
+func (f EfficiencyFormatter) percent(value *float64) string {
+	return f.value(func() string {
+		if value == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*value, 'f', -1, 64) + "%"
+	})
+}
+
+func (f EfficiencyFormatter) value(format func() string) string {
+	if f.unavailable {
+		return unavailable
+	}
+	if !f.known {
+		return ""
+	}
+	return format()
+}
+
+// CoresString formats millicores as CPU cores, e.g. 1250 as "1.25".
+func CoresString(millicores int64) string {
+	return strconv.FormatFloat(float64(millicores)/millicoresPerCore, 'f', 2, 64)
+}
+
+// GiBString formats bytes as GiB, e.g. 1.5GiB as "1.50".
+func GiBString(bytes int64) string {
+	return strconv.FormatFloat(float64(bytes)/bytesPerGiB, 'f', 2, 64)
+}
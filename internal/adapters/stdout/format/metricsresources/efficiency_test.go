@@ -0,0 +1,75 @@
+package metricsresources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	servicemetricsresources "github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+)
+
+func TestEfficiencyFormatter(t *testing.T) {
+	container := servicemetricsresources.ContainerMetricsResource{
+		Requests: servicemetricsresources.MetricsResource{CPURequest: 200, CPUUsed: 50, MemoryRequest: 2048, MemoryUsed: 1024},
+		Limits:   servicemetricsresources.MetricsResource{CPURequest: 300, CPUUsed: 50, MemoryUsed: 1024},
+	}
+
+	efficiency := NewContainer(container).Efficiency()
+
+	require.Equal(t, "25%", efficiency.CPURequestPercent())
+	require.Equal(t, "16.7%", efficiency.CPULimitPercent())
+	require.Equal(t, "150", efficiency.CPUIdle())
+	require.Equal(t, "50%", efficiency.MemoryRequestPercent())
+	require.Empty(t, efficiency.MemoryLimitPercent(), "memory is not limited")
+	require.Equal(t, "1KiB", efficiency.MemoryIdle())
+	require.Equal(t, "25%/16.7%/150", efficiency.CPUCompactString())
+	require.Equal(t, "50%/-/1KiB", efficiency.MemoryCompactString())
+}
+
+func TestEfficiencyFormatterUnknownUsage(t *testing.T) {
+	unknown := servicemetricsresources.ContainerMetricsResource{
+		Requests: servicemetricsresources.MetricsResource{CPURequest: 200, CPUUsed: unset, MemoryUsed: unset},
+	}
+	require.Equal(t, "-/-/-", NewContainer(unknown).Efficiency().CPUCompactString())
+
+	unknown.MetricsUnavailable = true
+	require.Equal(t, "n/a/n/a/n/a", NewContainer(unknown).Efficiency().CPUCompactString())
+}
+
+func TestNewTotalEfficiency(t *testing.T) {
+	pod := func(name string, cpuRequest, cpuUsed int64, withMetrics bool) servicemetricsresources.PodMetricsResource {
+		resource := servicemetricsresources.PodMetricsResource{
+			PodResource: pods.PodResource{
+				NamespaceName: pods.NamespaceName{Name: name, Namespace: "default"},
+				NodeName:      "node-1",
+				Containers:    []pods.ContainerResource{{Name: "app", Requests: pods.Resource{CPU: cpuRequest, Memory: 1024}}},
+			},
+		}
+		if withMetrics {
+			resource.PodMetric = podmetrics.PodMetric{
+				Name:       name,
+				Namespace:  "default",
+				Containers: []podmetrics.ContainerMetric{{Name: "app", Metric: podmetrics.Metric{CPU: cpuUsed, Memory: 512}}},
+			}
+		}
+		return resource
+	}
+	waste := servicemetricsresources.Waste{}
+	waste.Add(pod("busy", 200, 300, true))
+	waste.Add(pod("idle", 200, 100, true))
+	waste.Add(pod("pending", 1000, 0, false))
+
+	efficiency := NewTotalEfficiency(waste)
+	require.Equal(t, "100%", efficiency.CPURequestPercent(), "pods without usage are left out")
+	require.Equal(t, "100", efficiency.CPUIdle(), "pods above their request do not offset idle ones")
+	require.Equal(t, "50%", efficiency.MemoryRequestPercent())
+
+	require.Empty(t, NewTotalEfficiency(servicemetricsresources.Waste{}).CPURequestPercent())
+}
+
+func TestCoresAndGiBStrings(t *testing.T) {
+	require.Equal(t, "1.25", CoresString(1250))
+	require.Equal(t, "0.00", CoresString(0))
+	require.Equal(t, "1.50", GiBString(3<<29))
+}
@@ -2,14 +2,22 @@ package metricsresources
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
 	escapes "github.com/snugfox/ansi-escapes"
 	"github.com/trezorg/k8spodsmetrics/internal/humanize"
 	servicemetricsresources "github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"k8s.io/apimachinery/pkg/util/duration"
 )
 
-const unset = int64(-1)
+const (
+	unset = int64(-1)
+	// unavailable stands for usage while the metrics API is unavailable.
+	unavailable = "n/a"
+)
 
 type MetricsFormatter struct {
 	resource servicemetricsresources.MetricsResource
@@ -116,13 +124,14 @@ func (f MetricsFormatter) StringWithColor(alertColor string) string {
 	}
 	if f.resource.MemoryUsed == unset && f.resource.CPUUsed == unset {
 		return fmt.Sprintf(
-			"CPU=%d, Memory=%s",
+			"CPU=%d, Memory=%s%s",
 			f.resource.CPURequest,
 			humanize.Bytes(f.resource.MemoryRequest),
+			f.extendedSuffix(),
 		)
 	}
 	return fmt.Sprintf(
-		"CPU=%d/%s%d%s, Memory=%s/%s%s%s",
+		"CPU=%d/%s%d%s, Memory=%s/%s%s%s%s",
 		f.resource.CPURequest,
 		cpuStartColor,
 		f.resource.CPUUsed,
@@ -131,9 +140,23 @@ func (f MetricsFormatter) StringWithColor(alertColor string) string {
 		memoryStartColor,
 		humanize.Bytes(f.resource.MemoryUsed),
 		memoryEndColor,
+		f.extendedSuffix(),
 	)
 }
 
+func (f MetricsFormatter) extendedSuffix() string {
+	var builder strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(f.resource.Extended)) {
+		_, _ = fmt.Fprintf(&builder, ", %s=%s", name, f.ExtendedString(name))
+	}
+	return builder.String()
+}
+
+// ExtendedString formats the requested (or limited) amount of an extended resource.
+func (f MetricsFormatter) ExtendedString(name string) string {
+	return humanize.Quantity(name, f.resource.Extended[name])
+}
+
 func (f MetricsFormatter) String() string {
 	return f.StringWithColor(escapes.TextColorRed)
 }
@@ -146,6 +169,15 @@ func (f MetricsFormatter) StorageEphemeralString() string {
 	return humanize.Bytes(f.resource.StorageEphemeralUsed)
 }
 
+// MemoryRSSString formats the resident set size, reported by the kubelet
+// metrics source only. It is empty when unknown.
+func (f MetricsFormatter) MemoryRSSString() string {
+	if f.resource.MemoryRSSUsed == unset || f.resource.MemoryRSSUsed == 0 {
+		return ""
+	}
+	return humanize.Bytes(f.resource.MemoryRSSUsed)
+}
+
 func (f MetricsFormatter) StorageRequestString() string {
 	return humanize.Bytes(f.resource.StorageRequest)
 }
@@ -162,10 +194,47 @@ func NewContainer(resource servicemetricsresources.ContainerMetricsResource) Con
 	return ContainerFormatter{resource: resource}
 }
 
+// NewClusterTotal formats the subtotal of a cluster the way a container is
+// formatted, named after the cluster.
+func NewClusterTotal(total servicemetricsresources.ClusterTotal) ContainerFormatter {
+	metrics := func(resource servicemetricsresources.Resource) servicemetricsresources.MetricsResource {
+		return servicemetricsresources.MetricsResource{
+			CPURequest:    resource.CPU,
+			MemoryRequest: resource.Memory,
+			CPUUsed:       total.Used.CPU,
+			MemoryUsed:    total.Used.Memory,
+			Extended:      resource.Extended,
+		}
+	}
+	return NewContainer(servicemetricsresources.ContainerMetricsResource{
+		Name:     total.Cluster,
+		Requests: metrics(total.Requests),
+		Limits:   metrics(total.Limits),
+	})
+}
+
 func (f ContainerFormatter) Name() string {
 	return f.resource.Name
 }
 
+// RestartsString shows the restart count followed by the reason and age of
+// the last termination before now, e.g. "3 (OOMKilled 5m ago)".
+func (f ContainerFormatter) RestartsString(now time.Time) string {
+	restarts := fmt.Sprintf("%d", f.resource.Restarts)
+	last := f.resource.LastTermination
+	if last == nil {
+		return restarts
+	}
+	reason := last.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("exit code %d", last.ExitCode)
+	}
+	if last.FinishedAt.IsZero() {
+		return fmt.Sprintf("%s (%s)", restarts, reason)
+	}
+	return fmt.Sprintf("%s (%s %s ago)", restarts, reason, duration.HumanDuration(now.Sub(last.FinishedAt)))
+}
+
 func (f ContainerFormatter) Requests() MetricsFormatter {
 	return NewMetrics(f.resource.Requests)
 }
@@ -175,6 +244,9 @@ func (f ContainerFormatter) Limits() MetricsFormatter {
 }
 
 func (f ContainerFormatter) MemoryUsed() string {
+	if f.resource.MetricsUnavailable {
+		return unavailable
+	}
 	if f.resource.Limits.MemoryAlert() {
 		return NewMetrics(f.resource.Limits).MemoryUsedString(escapes.TextColorRed)
 	}
@@ -182,6 +254,9 @@ func (f ContainerFormatter) MemoryUsed() string {
 }
 
 func (f ContainerFormatter) CPUUsed() string {
+	if f.resource.MetricsUnavailable {
+		return unavailable
+	}
 	if f.resource.Limits.CPUAlert() {
 		return NewMetrics(f.resource.Limits).CPUUsedString(escapes.TextColorRed)
 	}
@@ -189,10 +264,16 @@ func (f ContainerFormatter) CPUUsed() string {
 }
 
 func (f ContainerFormatter) StorageUsed() string {
+	if f.resource.MetricsUnavailable {
+		return unavailable
+	}
 	return NewMetrics(f.resource.Requests).StorageString()
 }
 
 func (f ContainerFormatter) StorageEphemeralUsed() string {
+	if f.resource.MetricsUnavailable {
+		return unavailable
+	}
 	return NewMetrics(f.resource.Requests).StorageEphemeralString()
 }
 
@@ -228,15 +309,20 @@ func (f ContainerFormatter) StorageEphemeralCompactString() string {
 	)
 }
 
+// ExtendedCompactString formats request/limit of an extended resource.
+func (f ContainerFormatter) ExtendedCompactString(name string) string {
+	return strings.Join([]string{f.Requests().ExtendedString(name), f.Limits().ExtendedString(name)}, "/")
+}
+
 func (f ContainerFormatter) storageUsedCompactValue() string {
-	if f.resource.Requests.StorageUsed == unset {
+	if f.resource.Requests.StorageUsed == unset && !f.resource.MetricsUnavailable {
 		return ""
 	}
 	return f.StorageUsed()
 }
 
 func (f ContainerFormatter) storageEphemeralUsedCompactValue() string {
-	if f.resource.Requests.StorageEphemeralUsed == unset {
+	if f.resource.Requests.StorageEphemeralUsed == unset && !f.resource.MetricsUnavailable {
 		return ""
 	}
 	return f.StorageEphemeralUsed()
@@ -252,3 +338,38 @@ func fallbackEmpty(value string) string {
 	}
 	return value
 }
+
+// PodName returns the pod name noting missing or stale metrics.
+func PodName(resource servicemetricsresources.PodMetricsResource) string {
+	switch {
+	case resource.MetricsMissing():
+		return resource.PodResource.Name + " (no metrics)"
+	case resource.MetricsStale:
+		return resource.PodResource.Name + " (stale metrics)"
+	default:
+		return resource.PodResource.Name
+	}
+}
+
+// MetricsString describes when the pod metrics were collected relative to when
+// the pod was listed, empty when the source reports no timestamp.
+func MetricsString(resource servicemetricsresources.PodMetricsResource) string {
+	if resource.MetricsUnavailable {
+		return "unavailable"
+	}
+	if resource.MetricsMissing() {
+		return "missing"
+	}
+	timestamp := resource.PodMetric.Timestamp
+	if timestamp.IsZero() {
+		return ""
+	}
+	result := fmt.Sprintf("collected %s ago", duration.HumanDuration(resource.ObservedAt().Sub(timestamp)))
+	if resource.PodMetric.Window > 0 {
+		result += fmt.Sprintf(" over %s", resource.PodMetric.Window)
+	}
+	if resource.MetricsStale {
+		result = "stale, " + result
+	}
+	return result
+}
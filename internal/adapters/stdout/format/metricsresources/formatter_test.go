@@ -2,10 +2,13 @@ package metricsresources
 
 import (
 	"testing"
+	"time"
 
 	escapes "github.com/snugfox/ansi-escapes"
 	"github.com/stretchr/testify/require"
 	servicemetricsresources "github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
 )
 
 func TestMetricsFormatterStringWithColor(t *testing.T) {
@@ -23,6 +26,12 @@ func TestMetricsFormatterStringWithColor(t *testing.T) {
 	require.Contains(t, formatted, escapes.ColorReset)
 }
 
+func TestMetricsFormatterMemoryRSSString(t *testing.T) {
+	require.Equal(t, "3KiB", NewMetrics(servicemetricsresources.MetricsResource{MemoryRSSUsed: 3072}).MemoryRSSString())
+	require.Empty(t, NewMetrics(servicemetricsresources.MetricsResource{MemoryRSSUsed: unset}).MemoryRSSString())
+	require.Empty(t, NewMetrics(servicemetricsresources.MetricsResource{}).MemoryRSSString())
+}
+
 func TestContainerFormatterUsedStrings(t *testing.T) {
 	container := servicemetricsresources.ContainerMetricsResource{
 		Requests: servicemetricsresources.MetricsResource{
@@ -107,3 +116,94 @@ func TestContainerFormatterCompactStringsWithUnsetUsed(t *testing.T) {
 	require.Equal(t, "2KiB/-/4KiB", formatter.StorageCompactString())
 	require.Equal(t, "4KiB/-/8KiB", formatter.StorageEphemeralCompactString())
 }
+
+func TestExtendedStrings(t *testing.T) {
+	container := servicemetricsresources.ContainerMetricsResource{
+		Requests: servicemetricsresources.MetricsResource{
+			CPUUsed:    unset,
+			MemoryUsed: unset,
+			Extended:   map[string]int64{"nvidia.com/gpu": 1, "hugepages-2Mi": 2 * 1024 * 1024},
+		},
+		Limits: servicemetricsresources.MetricsResource{
+			Extended: map[string]int64{"nvidia.com/gpu": 2},
+		},
+	}
+
+	formatter := NewContainer(container)
+	require.Equal(t, "1/2", formatter.ExtendedCompactString("nvidia.com/gpu"))
+	require.Equal(t, "2MiB/0B", formatter.ExtendedCompactString("hugepages-2Mi"))
+	require.Equal(t, "CPU=0, Memory=0B, hugepages-2Mi=2MiB, nvidia.com/gpu=1", formatter.Requests().String())
+}
+
+func TestContainerFormatterRestartsString(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name      string
+		container servicemetricsresources.ContainerMetricsResource
+		expected  string
+	}{
+		{
+			name:      "no restarts",
+			container: servicemetricsresources.ContainerMetricsResource{},
+			expected:  "0",
+		},
+		{
+			name: "oom killed",
+			container: servicemetricsresources.ContainerMetricsResource{
+				Restarts:        3,
+				LastTermination: &pods.Termination{Reason: pods.OOMKilled, ExitCode: 137, FinishedAt: now.Add(-5 * time.Minute)},
+			},
+			expected: "3 (OOMKilled 5m ago)",
+		},
+		{
+			name: "exit code without reason and time",
+			container: servicemetricsresources.ContainerMetricsResource{
+				Restarts:        1,
+				LastTermination: &pods.Termination{ExitCode: 2},
+			},
+			expected: "1 (exit code 2)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, NewContainer(tc.container).RestartsString(now))
+		})
+	}
+}
+
+func TestPodName(t *testing.T) {
+	resource := servicemetricsresources.PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Name: "web", Namespace: "default"},
+			NodeName:      "node-1",
+		},
+	}
+	require.Equal(t, "web (no metrics)", PodName(resource))
+
+	resource.PodMetric = podmetrics.PodMetric{Name: "web", Namespace: "default"}
+	require.Equal(t, "web", PodName(resource))
+
+	resource.MetricsStale = true
+	require.Equal(t, "web (stale metrics)", PodName(resource))
+}
+
+func TestMetricsString(t *testing.T) {
+	resource := servicemetricsresources.PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Name: "web", Namespace: "default"},
+			NodeName:      "node-1",
+		},
+	}
+	require.Equal(t, "missing", MetricsString(resource))
+
+	resource.PodMetric = podmetrics.PodMetric{Name: "web", Namespace: "default"}
+	require.Empty(t, MetricsString(resource), "sources without timestamps")
+
+	resource.PodMetric.Timestamp = time.Now().Add(-5 * time.Minute)
+	resource.PodMetric.Window = 15 * time.Second
+	resource.MetricsStale = true
+	require.Equal(t, "stale, collected 5m ago over 15s", MetricsString(resource))
+
+	resource.ListedAt = resource.PodMetric.Timestamp.Add(time.Minute)
+	require.Equal(t, "stale, collected 60s ago over 15s", MetricsString(resource), "measured from the listing time")
+}
@@ -0,0 +1,183 @@
+package namespaces
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	escapes "github.com/snugfox/ansi-escapes"
+	"github.com/trezorg/k8spodsmetrics/internal/humanize"
+	servicenamespaces "github.com/trezorg/k8spodsmetrics/internal/namespaces"
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+)
+
+type Formatter struct {
+	resource servicenamespaces.NamespaceResource
+}
+
+func New(resource servicenamespaces.NamespaceResource) Formatter {
+	return Formatter{resource: resource}
+}
+
+func cpu(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
+func memory(value int64) string {
+	return humanize.Bytes(value)
+}
+
+func (f Formatter) hasMetrics() bool {
+	return f.resource.MetricsMissing < f.resource.Pods
+}
+
+// PodsString returns the pod count annotated with the pods metrics-server
+// reported nothing for.
+func (f Formatter) PodsString() string {
+	if f.resource.MetricsMissing == 0 {
+		return strconv.FormatInt(f.resource.Pods, 10)
+	}
+	return fmt.Sprintf("%d (%d no metrics)", f.resource.Pods, f.resource.MetricsMissing)
+}
+
+func (f Formatter) CPURequestString() string {
+	return cpu(f.resource.CPURequest)
+}
+
+func (f Formatter) CPULimitString() string {
+	return cpu(f.resource.CPULimit)
+}
+
+func (f Formatter) CPUUsedString() string {
+	if !f.hasMetrics() {
+		return ""
+	}
+	return colored(cpu(f.resource.CPUUsed), escapes.TextColorRed, f.resource.IsCPUUsedAlerted())
+}
+
+func (f Formatter) MemoryRequestString() string {
+	return memory(f.resource.MemoryRequest)
+}
+
+func (f Formatter) MemoryLimitString() string {
+	return memory(f.resource.MemoryLimit)
+}
+
+func (f Formatter) MemoryUsedString() string {
+	if !f.hasMetrics() {
+		return ""
+	}
+	return colored(memory(f.resource.MemoryUsed), escapes.TextColorRed, f.resource.IsMemoryUsedAlerted())
+}
+
+func (f Formatter) CPURequestQuotaString() string {
+	return quotaString(f.resource.Quota.RequestsCPU, cpu, f.resource.IsCPURequestAlerted())
+}
+
+func (f Formatter) CPULimitQuotaString() string {
+	return quotaString(f.resource.Quota.LimitsCPU, cpu, f.resource.IsCPULimitAlerted())
+}
+
+func (f Formatter) MemoryRequestQuotaString() string {
+	return quotaString(f.resource.Quota.RequestsMemory, memory, f.resource.IsMemoryRequestAlerted())
+}
+
+func (f Formatter) MemoryLimitQuotaString() string {
+	return quotaString(f.resource.Quota.LimitsMemory, memory, f.resource.IsMemoryLimitAlerted())
+}
+
+func (f Formatter) PodsQuotaString() string {
+	return quotaString(f.resource.Quota.Pods, cpu, f.resource.IsPodsAlerted())
+}
+
+// CPUCompactString returns request/used/limit totals.
+func (f Formatter) CPUCompactString() string {
+	return compactTriple(f.CPURequestString(), f.CPUUsedString(), f.CPULimitString())
+}
+
+func (f Formatter) MemoryCompactString() string {
+	return compactTriple(f.MemoryRequestString(), f.MemoryUsedString(), f.MemoryLimitString())
+}
+
+// CPUQuotaCompactString returns the requests and limits quota utilisation.
+func (f Formatter) CPUQuotaCompactString() string {
+	return compactPair(percentString(f.resource.Quota.RequestsCPU), percentString(f.resource.Quota.LimitsCPU))
+}
+
+func (f Formatter) MemoryQuotaCompactString() string {
+	return compactPair(percentString(f.resource.Quota.RequestsMemory), percentString(f.resource.Quota.LimitsMemory))
+}
+
+func (f Formatter) PodsTemplate() string {
+	return withQuota(f.PodsString(), "Quota", f.PodsQuotaString())
+}
+
+func (f Formatter) CPUTemplate() string {
+	return f.template(
+		f.CPURequestString(),
+		f.CPULimitString(),
+		f.CPUUsedString(),
+		f.CPURequestQuotaString(),
+		f.CPULimitQuotaString(),
+	)
+}
+
+func (f Formatter) MemoryTemplate() string {
+	return f.template(
+		f.MemoryRequestString(),
+		f.MemoryLimitString(),
+		f.MemoryUsedString(),
+		f.MemoryRequestQuotaString(),
+		f.MemoryLimitQuotaString(),
+	)
+}
+
+func (f Formatter) template(request, limit, used, requestsQuota, limitsQuota string) string {
+	result := fmt.Sprintf("Requests=%s, Limits=%s", request, limit)
+	if f.hasMetrics() {
+		result += ", Used=" + used
+	}
+	result = withQuota(result, "Requests Quota", requestsQuota)
+	return withQuota(result, "Limits Quota", limitsQuota)
+}
+
+func withQuota(value, label, quota string) string {
+	if quota == "" {
+		return value
+	}
+	return fmt.Sprintf("%s, %s=%s", value, label, quota)
+}
+
+// quotaString returns used/hard (percent%), or an empty string without a quota.
+func quotaString(quota *quotas.Quota, format func(int64) string, alerted bool) string {
+	if quota == nil {
+		return ""
+	}
+	return colored(
+		fmt.Sprintf("%s/%s (%s)", format(quota.Used), format(quota.Hard), percentString(quota)),
+		escapes.TextColorRed,
+		alerted,
+	)
+}
+
+func percentString(quota *quotas.Quota) string {
+	if quota == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(quota.Percent, 'f', -1, 64) + "%"
+}
+
+func colored(value, color string, alerted bool) string {
+	if !alerted {
+		return value
+	}
+	return color + value + escapes.ColorReset
+}
+
+func compactTriple(first, second, third string) string {
+	return strings.Join([]string{first, second, third}, "/")
+}
+
+func compactPair(first, second string) string {
+	return strings.Join([]string{first, second}, "/")
+}
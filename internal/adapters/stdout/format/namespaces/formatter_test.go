@@ -0,0 +1,68 @@
+package namespaces
+
+import (
+	"testing"
+
+	escapes "github.com/snugfox/ansi-escapes"
+	"github.com/stretchr/testify/require"
+	servicenamespaces "github.com/trezorg/k8spodsmetrics/internal/namespaces"
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+)
+
+func quota(hard, used int64) *quotas.Quota {
+	result := quotas.NewQuota(hard, used)
+	return &result
+}
+
+func testNamespace() servicenamespaces.NamespaceResource {
+	return servicenamespaces.NamespaceResource{
+		Name:          "team-a",
+		Pods:          3,
+		CPURequest:    1500,
+		CPULimit:      3000,
+		CPUUsed:       200,
+		MemoryRequest: 1024,
+		MemoryLimit:   2048,
+		MemoryUsed:    512,
+		Quota: quotas.NamespaceQuota{
+			RequestsCPU: quota(2000, 1500),
+			Pods:        quota(10, 3),
+		},
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	formatter := New(testNamespace())
+
+	require.Equal(t, "3", formatter.PodsString())
+	require.Equal(t, "1500/200/3000", formatter.CPUCompactString())
+	require.Equal(t, "1KiB/512B/2KiB", formatter.MemoryCompactString())
+	require.Equal(t, "1500/2000 (75%)", formatter.CPURequestQuotaString())
+	require.Empty(t, formatter.CPULimitQuotaString())
+	require.Equal(t, "75%/-", formatter.CPUQuotaCompactString())
+	require.Equal(t, "-/-", formatter.MemoryQuotaCompactString())
+	require.Equal(t, "3, Quota=3/10 (30%)", formatter.PodsTemplate())
+	require.Equal(t,
+		"Requests=1500, Limits=3000, Used=200, Requests Quota=1500/2000 (75%)",
+		formatter.CPUTemplate(),
+	)
+	require.Equal(t, "Requests=1KiB, Limits=2KiB, Used=512B", formatter.MemoryTemplate())
+}
+
+func TestFormatterAlertsQuota(t *testing.T) {
+	resource := testNamespace()
+	resource.Quota.LimitsCPU = quota(3000, 3000)
+	formatter := New(resource)
+
+	require.Equal(t, escapes.TextColorRed+"3000/3000 (100%)"+escapes.ColorReset, formatter.CPULimitQuotaString())
+}
+
+func TestFormatterWithoutMetrics(t *testing.T) {
+	resource := testNamespace()
+	resource.MetricsMissing = 3
+	formatter := New(resource)
+
+	require.Equal(t, "3 (3 no metrics)", formatter.PodsString())
+	require.Empty(t, formatter.CPUUsedString())
+	require.NotContains(t, formatter.CPUTemplate(), "Used=")
+}
@@ -2,13 +2,18 @@ package noderesources
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	escapes "github.com/snugfox/ansi-escapes"
 	"github.com/trezorg/k8spodsmetrics/internal/humanize"
 	servicenoderesources "github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"k8s.io/apimachinery/pkg/util/duration"
 )
 
+// unavailable stands for usage while the metrics API is unavailable.
+const unavailable = "n/a"
+
 type Formatter struct {
 	resource servicenoderesources.NodeResource
 }
@@ -17,6 +22,73 @@ func New(resource servicenoderesources.NodeResource) Formatter {
 	return Formatter{resource: resource}
 }
 
+// NameString returns the node name annotated with the number of terminated
+// pods left out of the node totals.
+func (f Formatter) NameString() string {
+	if f.resource.ExcludedTerminatedPods == 0 {
+		return f.resource.Name
+	}
+	return fmt.Sprintf("%s (%d terminated excluded)", f.resource.Name, f.resource.ExcludedTerminatedPods)
+}
+
+// StatusString returns kubectl style status markers of the node, e.g.
+// Ready,SchedulingDisabled. Nodes that are not Ready or are under pressure are
+// colored red, other nodes new pods cannot land on or lacking fresh metrics
+// yellow.
+func (f Formatter) StatusString() string {
+	markers := []string{"Ready"}
+	if !f.resource.Ready {
+		markers[0] = "NotReady"
+	}
+	if f.resource.Unschedulable {
+		markers = append(markers, "SchedulingDisabled")
+	}
+	markers = append(markers, f.resource.Conditions...)
+	if f.resource.IsTainted() {
+		markers = append(markers, "Tainted")
+	}
+	if f.resource.MetricsMissing {
+		markers = append(markers, "NoMetrics")
+	}
+	if f.resource.MetricsStale {
+		markers = append(markers, "StaleMetrics")
+	}
+	if f.resource.MetricsUnavailable {
+		markers = append(markers, "MetricsUnavailable")
+	}
+	status := strings.Join(markers, ",")
+	if !f.resource.Ready || len(f.resource.Conditions) > 0 {
+		return colored(status, escapes.TextColorRed, true)
+	}
+	warn := !f.resource.Schedulable || f.resource.MetricsMissing || f.resource.MetricsStale || f.resource.MetricsUnavailable
+	return colored(status, escapes.TextColorYellow, warn)
+}
+
+// MetricsString describes when the node metrics were collected relative to
+// when the node was listed, empty when the source reports no timestamp.
+func (f Formatter) MetricsString() string {
+	if f.resource.MetricsTimestamp == nil {
+		return ""
+	}
+	result := fmt.Sprintf("collected %s ago", duration.HumanDuration(f.resource.ObservedAt().Sub(*f.resource.MetricsTimestamp)))
+	if f.resource.MetricsWindow != "" {
+		result += " over " + f.resource.MetricsWindow
+	}
+	if f.resource.MetricsStale {
+		result = "stale, " + result
+	}
+	return result
+}
+
+// TaintsString lists node taints as key=value:effect.
+func (f Formatter) TaintsString() string {
+	taints := make([]string, 0, len(f.resource.Taints))
+	for _, taint := range f.resource.Taints {
+		taints = append(taints, taint.String())
+	}
+	return strings.Join(taints, ", ")
+}
+
 func (f Formatter) MemoryTemplate() string {
 	memoryRequestStartColor := ""
 	memoryRequestEndColor := ""
@@ -33,7 +105,7 @@ func (f Formatter) MemoryTemplate() string {
 	return fmt.Sprintf(
 		"Node=%s/%s, Requests=%s%s%s, Limits=%s%s%s",
 		humanize.Bytes(f.resource.Memory),
-		humanize.Bytes(f.resource.UsedMemory),
+		f.MemoryNodeUsedString(),
 		memoryRequestStartColor,
 		humanize.Bytes(f.resource.MemoryRequest),
 		memoryRequestEndColor,
@@ -89,6 +161,9 @@ func (f Formatter) MemoryAvailableString() string {
 }
 
 func (f Formatter) MemoryFreeString() string {
+	if f.resource.MetricsUnavailable {
+		return unavailable
+	}
 	memoryFreeStartColor := ""
 	memoryFreeEndColor := ""
 	if f.resource.FreeMemory == 0 {
@@ -108,6 +183,9 @@ func (f Formatter) MemoryNodeString() string {
 }
 
 func (f Formatter) MemoryNodeUsedString() string {
+	if f.resource.MetricsUnavailable {
+		return unavailable
+	}
 	return humanize.Bytes(f.resource.UsedMemory)
 }
 
@@ -133,9 +211,9 @@ func (f Formatter) CPUTemplate() string {
 		cpuLimitEndColor = escapes.ColorReset
 	}
 	return fmt.Sprintf(
-		"Node=%d/%d, Requests=%s%d%s, Limits=%s%d%s",
+		"Node=%d/%s, Requests=%s%d%s, Limits=%s%d%s",
 		f.resource.CPU,
-		f.resource.UsedCPU,
+		f.CPUUsedString(),
 		cpuRequestStartColor,
 		f.resource.CPURequest,
 		cpuRequestEndColor,
@@ -190,7 +268,17 @@ func (f Formatter) CPUAvailableString() string {
 	)
 }
 
+func (f Formatter) CPUUsedString() string {
+	if f.resource.MetricsUnavailable {
+		return unavailable
+	}
+	return strconv.FormatInt(f.resource.UsedCPU, 10)
+}
+
 func (f Formatter) CPUFreeString() string {
+	if f.resource.MetricsUnavailable {
+		return unavailable
+	}
 	cpuFreeStartColor := ""
 	cpuFreeEndColor := ""
 	if f.resource.FreeCPU == 0 {
@@ -214,10 +302,16 @@ func (f Formatter) StorageAllocatableString() string {
 }
 
 func (f Formatter) StorageFreeString() string {
+	if f.resource.MetricsUnavailable {
+		return unavailable
+	}
 	return humanize.Bytes(f.resource.FreeStorage)
 }
 
 func (f Formatter) StorageUsedString() string {
+	if f.resource.MetricsUnavailable {
+		return unavailable
+	}
 	usedStorageStartColor := ""
 	usedStorageEndColor := ""
 	if f.resource.IsStorageAlerted() {
@@ -241,10 +335,16 @@ func (f Formatter) StorageAllocatableEphemeralString() string {
 }
 
 func (f Formatter) StorageFreeEphemeralString() string {
+	if f.resource.MetricsUnavailable {
+		return unavailable
+	}
 	return humanize.Bytes(f.resource.FreeStorageEphemeral)
 }
 
 func (f Formatter) StorageUsedEphemeralString() string {
+	if f.resource.MetricsUnavailable {
+		return unavailable
+	}
 	usedStorageStartColor := ""
 	usedStorageEndColor := ""
 	if f.resource.IsStorageEphemeralAlerted() {
@@ -262,7 +362,7 @@ func (f Formatter) StorageUsedEphemeralString() string {
 func (f Formatter) CPUCapacityCompactString() string {
 	return compactTriple(
 		fmt.Sprintf("%d", f.resource.AllocatableCPU),
-		fmt.Sprintf("%d", f.resource.UsedCPU),
+		f.CPUUsedString(),
 		f.CPUFreeString(),
 	)
 }
@@ -299,6 +399,77 @@ func (f Formatter) StorageEphemeralCapacityCompactString() string {
 	)
 }
 
+// PodsTemplate describes pod slots of the node for the text output.
+func (f Formatter) PodsTemplate() string {
+	return fmt.Sprintf(
+		"Allocatable=%s, Running=%s, Free=%s",
+		f.PodsAllocatableString(),
+		f.PodsRunningString(),
+		f.PodsFreeString(),
+	)
+}
+
+func (f Formatter) PodsAllocatableString() string {
+	return strconv.FormatInt(f.resource.AllocatablePods, 10)
+}
+
+func (f Formatter) PodsRunningString() string {
+	return colored(strconv.FormatInt(f.resource.RunningPods, 10), escapes.TextColorYellow, f.resource.IsPodsAlerted())
+}
+
+func (f Formatter) PodsFreeString() string {
+	return colored(strconv.FormatInt(f.resource.FreePods, 10), escapes.TextColorRed, f.resource.IsPodsAlerted())
+}
+
+func (f Formatter) PodsCompactString() string {
+	return compactTriple(f.PodsAllocatableString(), f.PodsRunningString(), f.PodsFreeString())
+}
+
+// ExtendedTemplate describes an extended resource for the text output.
+func (f Formatter) ExtendedTemplate(name string) string {
+	return fmt.Sprintf(
+		"Node=%s/%s, Requests=%s, Limits=%s",
+		f.ExtendedTotalString(name),
+		f.ExtendedAllocatableString(name),
+		f.ExtendedRequestString(name),
+		f.ExtendedLimitString(name),
+	)
+}
+
+func (f Formatter) ExtendedTotalString(name string) string {
+	return humanize.Quantity(name, f.resource.Extended[name].Capacity)
+}
+
+func (f Formatter) ExtendedAllocatableString(name string) string {
+	return humanize.Quantity(name, f.resource.Extended[name].Allocatable)
+}
+
+func (f Formatter) ExtendedRequestString(name string) string {
+	resource := f.resource.Extended[name]
+	return colored(humanize.Quantity(name, resource.Request), escapes.TextColorYellow, resource.IsRequestAlerted())
+}
+
+func (f Formatter) ExtendedLimitString(name string) string {
+	resource := f.resource.Extended[name]
+	return colored(humanize.Quantity(name, resource.Limit), escapes.TextColorRed, resource.IsLimitAlerted())
+}
+
+func (f Formatter) ExtendedAvailableString(name string) string {
+	resource := f.resource.Extended[name]
+	return colored(humanize.Quantity(name, resource.Available), escapes.TextColorRed, resource.Available <= 0 && resource.Capacity > 0)
+}
+
+func (f Formatter) ExtendedCompactString(name string) string {
+	return compactTriple(f.ExtendedAllocatableString(name), f.ExtendedRequestString(name), f.ExtendedLimitString(name))
+}
+
+func colored(value, color string, alerted bool) string {
+	if !alerted {
+		return value
+	}
+	return color + value + escapes.ColorReset
+}
+
 func compactTriple(first, second, third string) string {
 	return strings.Join([]string{first, second, third}, "/")
 }
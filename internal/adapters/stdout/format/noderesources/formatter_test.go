@@ -2,12 +2,24 @@ package noderesources
 
 import (
 	"testing"
+	"time"
 
 	escapes "github.com/snugfox/ansi-escapes"
 	"github.com/stretchr/testify/require"
 	servicenoderesources "github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/pkg/nodes"
+	v1 "k8s.io/api/core/v1"
 )
 
+func TestFormatterNameString(t *testing.T) {
+	require.Equal(t, "node1", New(servicenoderesources.NodeResource{Name: "node1"}).NameString())
+	require.Equal(
+		t,
+		"node1 (2 terminated excluded)",
+		New(servicenoderesources.NodeResource{Name: "node1", ExcludedTerminatedPods: 2}).NameString(),
+	)
+}
+
 func TestFormatterMemoryTemplate(t *testing.T) {
 	resource := servicenoderesources.NodeResource{
 		Memory:        1024,
@@ -39,6 +51,20 @@ func TestFormatterStorageUsedString(t *testing.T) {
 	})
 }
 
+func TestFormatterPodsStrings(t *testing.T) {
+	t.Run("non alerted", func(t *testing.T) {
+		resource := servicenoderesources.NodeResource{AllocatablePods: 110, RunningPods: 40, FreePods: 70}
+		require.Equal(t, "Allocatable=110, Running=40, Free=70", New(resource).PodsTemplate())
+		require.Equal(t, "110/40/70", New(resource).PodsCompactString())
+	})
+
+	t.Run("alerted", func(t *testing.T) {
+		resource := servicenoderesources.NodeResource{AllocatablePods: 10, RunningPods: 10}
+		require.Contains(t, New(resource).PodsRunningString(), escapes.TextColorYellow)
+		require.Contains(t, New(resource).PodsFreeString(), escapes.TextColorRed)
+	})
+}
+
 func TestFormatterCompactCapacityStrings(t *testing.T) {
 	resource := servicenoderesources.NodeResource{
 		AllocatableCPU:              3900,
@@ -76,3 +102,90 @@ func TestFormatterCompactDemandStrings(t *testing.T) {
 	require.Equal(t, "2200/6000", formatter.CPUDemandCompactString())
 	require.Equal(t, "8KiB/16KiB", formatter.MemoryDemandCompactString())
 }
+
+func TestFormatterExtendedStrings(t *testing.T) {
+	resource := servicenoderesources.NodeResource{
+		Extended: map[string]servicenoderesources.ExtendedResource{
+			"nvidia.com/gpu": {Capacity: 4, Allocatable: 3, Request: 4, Limit: 4, Available: -1},
+			"hugepages-2Mi":  {Capacity: 1024 * 1024 * 1024, Allocatable: 1024 * 1024 * 1024, Request: 2 * 1024 * 1024},
+		},
+	}
+
+	formatter := New(resource)
+	require.Equal(t, "1GiB/2MiB/0B", formatter.ExtendedCompactString("hugepages-2Mi"))
+	require.Contains(t, formatter.ExtendedRequestString("nvidia.com/gpu"), escapes.TextColorYellow)
+	require.Contains(t, formatter.ExtendedLimitString("nvidia.com/gpu"), escapes.TextColorRed)
+	require.Contains(t, formatter.ExtendedAvailableString("nvidia.com/gpu"), escapes.TextColorRed)
+	require.Equal(t, "Node=1GiB/1GiB, Requests=2MiB, Limits=0B", formatter.ExtendedTemplate("hugepages-2Mi"))
+	require.Equal(t, "0", formatter.ExtendedTotalString("example.com/missing"))
+}
+
+func TestFormatterStatusString(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource servicenoderesources.NodeResource
+		expected string
+	}{
+		{
+			name:     "schedulable",
+			resource: servicenoderesources.NodeResource{Ready: true, Schedulable: true},
+			expected: "Ready",
+		},
+		{
+			name:     "cordoned",
+			resource: servicenoderesources.NodeResource{Ready: true, Unschedulable: true},
+			expected: escapes.TextColorYellow + "Ready,SchedulingDisabled" + escapes.ColorReset,
+		},
+		{
+			name: "tainted",
+			resource: servicenoderesources.NodeResource{
+				Ready:  true,
+				Taints: []nodes.Taint{{Key: "dedicated", Effect: v1.TaintEffectNoSchedule}},
+			},
+			expected: escapes.TextColorYellow + "Ready,Tainted" + escapes.ColorReset,
+		},
+		{
+			name:     "missing metrics",
+			resource: servicenoderesources.NodeResource{Ready: true, Schedulable: true, MetricsMissing: true},
+			expected: escapes.TextColorYellow + "Ready,NoMetrics" + escapes.ColorReset,
+		},
+		{
+			name:     "stale metrics",
+			resource: servicenoderesources.NodeResource{Ready: true, Schedulable: true, MetricsStale: true},
+			expected: escapes.TextColorYellow + "Ready,StaleMetrics" + escapes.ColorReset,
+		},
+		{
+			name:     "not ready under pressure",
+			resource: servicenoderesources.NodeResource{Conditions: []string{"MemoryPressure", "DiskPressure"}},
+			expected: escapes.TextColorRed + "NotReady,MemoryPressure,DiskPressure" + escapes.ColorReset,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, New(tt.resource).StatusString())
+		})
+	}
+}
+
+func TestFormatterTaintsString(t *testing.T) {
+	resource := servicenoderesources.NodeResource{Taints: []nodes.Taint{
+		{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule},
+		{Key: "spot", Effect: v1.TaintEffectPreferNoSchedule},
+	}}
+	require.Equal(t, "dedicated=gpu:NoSchedule, spot:PreferNoSchedule", New(resource).TaintsString())
+	require.Empty(t, New(servicenoderesources.NodeResource{}).TaintsString())
+}
+
+func TestFormatterMetricsString(t *testing.T) {
+	require.Empty(t, New(servicenoderesources.NodeResource{}).MetricsString())
+
+	collected := time.Now().Add(-2 * time.Minute)
+	resource := servicenoderesources.NodeResource{MetricsTimestamp: &collected, MetricsWindow: "30s"}
+	require.Equal(t, "collected 2m ago over 30s", New(resource).MetricsString())
+
+	resource.MetricsStale = true
+	require.Equal(t, "stale, collected 2m ago over 30s", New(resource).MetricsString())
+
+	resource.ListedAt = collected.Add(time.Minute)
+	require.Equal(t, "stale, collected 60s ago over 30s", New(resource).MetricsString(), "measured from the listing time")
+}
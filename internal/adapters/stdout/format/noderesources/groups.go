@@ -0,0 +1,38 @@
+package noderesources
+
+import (
+	"fmt"
+
+	servicenoderesources "github.com/trezorg/k8spodsmetrics/internal/noderesources"
+)
+
+// GroupValueString returns the label value of a node group. Nodes missing the
+// label are grouped under <none>. Groups of a cluster alone are named after
+// the cluster.
+func GroupValueString(group servicenoderesources.NodeResourceGroup) string {
+	if group.Label == "" && group.Cluster != "" {
+		return group.Cluster
+	}
+	if group.Value == "" {
+		return "<none>"
+	}
+	return group.Value
+}
+
+// GroupNodesString describes how many nodes a group holds and how many of
+// them are schedulable.
+func GroupNodesString(group servicenoderesources.NodeResourceGroup) string {
+	return nodesString(group.Nodes, group.SchedulableNodes)
+}
+
+// ClusterNodesString is GroupNodesString for the subtotal of a cluster.
+func ClusterNodesString(total servicenoderesources.ClusterTotal) string {
+	return nodesString(total.Nodes, total.SchedulableNodes)
+}
+
+func nodesString(nodes, schedulable int) string {
+	if schedulable == nodes {
+		return fmt.Sprintf("%d nodes", nodes)
+	}
+	return fmt.Sprintf("%d nodes, %d schedulable", nodes, schedulable)
+}
@@ -0,0 +1,27 @@
+package noderesources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	servicenoderesources "github.com/trezorg/k8spodsmetrics/internal/noderesources"
+)
+
+func TestGroupValueString(t *testing.T) {
+	require.Equal(t, "eu-west-1a", GroupValueString(servicenoderesources.NodeResourceGroup{Value: "eu-west-1a"}))
+	require.Equal(t, "<none>", GroupValueString(servicenoderesources.NodeResourceGroup{}))
+}
+
+func TestGroupNodesString(t *testing.T) {
+	require.Equal(t, "3 nodes", GroupNodesString(servicenoderesources.NodeResourceGroup{Nodes: 3, SchedulableNodes: 3}))
+	require.Equal(t, "3 nodes, 1 schedulable", GroupNodesString(servicenoderesources.NodeResourceGroup{Nodes: 3, SchedulableNodes: 1}))
+}
+
+func TestGroupValueStringCluster(t *testing.T) {
+	require.Equal(t, "prod-eu", GroupValueString(servicenoderesources.NodeResourceGroup{Cluster: "prod-eu"}))
+	require.Equal(t, "a", GroupValueString(servicenoderesources.NodeResourceGroup{Cluster: "prod-eu", Label: "zone", Value: "a"}))
+}
+
+func TestClusterNodesString(t *testing.T) {
+	require.Equal(t, "2 nodes, 1 schedulable", ClusterNodesString(servicenoderesources.ClusterTotal{Nodes: 2, SchedulableNodes: 1}))
+}
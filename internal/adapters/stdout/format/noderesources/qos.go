@@ -0,0 +1,66 @@
+package noderesources
+
+import (
+	"fmt"
+
+	"github.com/trezorg/k8spodsmetrics/internal/humanize"
+	servicenoderesources "github.com/trezorg/k8spodsmetrics/internal/noderesources"
+)
+
+// QOSFormatter formats the share of a node taken by pods of one QoS class.
+// Requests and limits are shown with their percentage of node allocatable.
+type QOSFormatter struct {
+	node  servicenoderesources.NodeResource
+	group servicenoderesources.QOSResource
+}
+
+func NewQOS(node servicenoderesources.NodeResource, group servicenoderesources.QOSResource) QOSFormatter {
+	return QOSFormatter{node: node, group: group}
+}
+
+func (f QOSFormatter) NameString() string {
+	return fmt.Sprintf("%s (%d pods)", f.group.Class, f.group.Pods)
+}
+
+func (f QOSFormatter) CPURequestString() string {
+	return withPercent(fmt.Sprintf("%d", f.group.CPURequest), f.group.CPURequest, f.node.AllocatableCPU)
+}
+
+func (f QOSFormatter) CPULimitString() string {
+	return withPercent(fmt.Sprintf("%d", f.group.CPULimit), f.group.CPULimit, f.node.AllocatableCPU)
+}
+
+func (f QOSFormatter) MemoryRequestString() string {
+	return withPercent(humanize.Bytes(f.group.MemoryRequest), f.group.MemoryRequest, f.node.AllocatableMemory)
+}
+
+func (f QOSFormatter) MemoryLimitString() string {
+	return withPercent(humanize.Bytes(f.group.MemoryLimit), f.group.MemoryLimit, f.node.AllocatableMemory)
+}
+
+func (f QOSFormatter) CPUDemandCompactString() string {
+	return compactPair(f.CPURequestString(), f.CPULimitString())
+}
+
+func (f QOSFormatter) MemoryDemandCompactString() string {
+	return compactPair(f.MemoryRequestString(), f.MemoryLimitString())
+}
+
+// Template describes the QoS class share for the text output.
+func (f QOSFormatter) Template() string {
+	return fmt.Sprintf(
+		"Pods=%d, CPU Requests=%s, CPU Limits=%s, Memory Requests=%s, Memory Limits=%s",
+		f.group.Pods,
+		f.CPURequestString(),
+		f.CPULimitString(),
+		f.MemoryRequestString(),
+		f.MemoryLimitString(),
+	)
+}
+
+func withPercent(value string, amount, allocatable int64) string {
+	if allocatable <= 0 {
+		return value
+	}
+	return fmt.Sprintf("%s (%d%%)", value, amount*100/allocatable)
+}
@@ -0,0 +1,39 @@
+package noderesources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	servicenoderesources "github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestQOSFormatter(t *testing.T) {
+	node := servicenoderesources.NodeResource{AllocatableCPU: 4000, AllocatableMemory: 8192}
+	group := servicenoderesources.QOSResource{
+		Class:         v1.PodQOSBurstable,
+		Pods:          3,
+		CPURequest:    1000,
+		CPULimit:      2000,
+		MemoryRequest: 2048,
+		MemoryLimit:   4096,
+	}
+	formatter := NewQOS(node, group)
+
+	require.Equal(t, "Burstable (3 pods)", formatter.NameString())
+	require.Equal(t, "1000 (25%)/2000 (50%)", formatter.CPUDemandCompactString())
+	require.Equal(t, "2KiB (25%)/4KiB (50%)", formatter.MemoryDemandCompactString())
+	require.Equal(
+		t,
+		"Pods=3, CPU Requests=1000 (25%), CPU Limits=2000 (50%), Memory Requests=2KiB (25%), Memory Limits=4KiB (50%)",
+		formatter.Template(),
+	)
+}
+
+func TestQOSFormatterWithoutAllocatable(t *testing.T) {
+	group := servicenoderesources.QOSResource{Class: v1.PodQOSBestEffort, CPURequest: 100}
+	formatter := NewQOS(servicenoderesources.NodeResource{}, group)
+
+	require.Equal(t, "100", formatter.CPURequestString())
+	require.Equal(t, "0B", formatter.MemoryLimitString())
+}
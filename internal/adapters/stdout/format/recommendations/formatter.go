@@ -0,0 +1,126 @@
+package recommendations
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/trezorg/k8spodsmetrics/internal/humanize"
+	servicerecommendations "github.com/trezorg/k8spodsmetrics/internal/recommendations"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	unset        = "-"
+	patchIndent  = 2
+	changedArrow = " → "
+)
+
+type Formatter struct {
+	recommendation servicerecommendations.Recommendation
+}
+
+func New(recommendation servicerecommendations.Recommendation) Formatter {
+	return Formatter{recommendation: recommendation}
+}
+
+func cpu(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
+func memory(value int64) string {
+	return humanize.Bytes(value)
+}
+
+// NameString returns the workload as kind/name.
+func (f Formatter) NameString() string {
+	return f.recommendation.Kind + "/" + f.recommendation.Workload
+}
+
+// ContainerString returns the container name annotated with its type for
+// init and sidecar containers.
+func (f Formatter) ContainerString() string {
+	if f.recommendation.Type == "" {
+		return f.recommendation.Container
+	}
+	return fmt.Sprintf("%s (%s)", f.recommendation.Container, f.recommendation.Type)
+}
+
+func (f Formatter) SamplesString() string {
+	return strconv.Itoa(f.recommendation.Samples)
+}
+
+// CPUUsageString returns p50/p90/p99/max.
+func (f Formatter) CPUUsageString() string {
+	return percentilesString(f.recommendation.Usage.CPU, cpu)
+}
+
+func (f Formatter) MemoryUsageString() string {
+	return percentilesString(f.recommendation.Usage.Memory, memory)
+}
+
+// CPURequestString returns the current and the recommended request.
+func (f Formatter) CPURequestString() string {
+	return change(f.recommendation.Current.Requests.CPU, f.recommendation.Recommended.Requests.CPU, cpu)
+}
+
+func (f Formatter) CPULimitString() string {
+	return change(f.recommendation.Current.Limits.CPU, f.recommendation.Recommended.Limits.CPU, cpu)
+}
+
+func (f Formatter) MemoryRequestString() string {
+	return change(f.recommendation.Current.Requests.Memory, f.recommendation.Recommended.Requests.Memory, memory)
+}
+
+func (f Formatter) MemoryLimitString() string {
+	return change(f.recommendation.Current.Limits.Memory, f.recommendation.Recommended.Limits.Memory, memory)
+}
+
+func percentilesString(percentiles servicerecommendations.Percentiles, format func(int64) string) string {
+	return strings.Join([]string{
+		format(percentiles.P50),
+		format(percentiles.P90),
+		format(percentiles.P99),
+		format(percentiles.Max),
+	}, "/")
+}
+
+// change returns current → recommended. A value the policy leaves out is
+// kept as is.
+func change(current, recommended int64, format func(int64) string) string {
+	if recommended == 0 {
+		return valueOrUnset(current, format)
+	}
+	return valueOrUnset(current, format) + changedArrow + format(recommended)
+}
+
+func valueOrUnset(value int64, format func(int64) string) string {
+	if value == 0 {
+		return unset
+	}
+	return format(value)
+}
+
+// PatchFileName suggests a file name to store patch in.
+func PatchFileName(patch servicerecommendations.Patch) string {
+	return strings.ToLower(strings.Join([]string{patch.Namespace, patch.Kind, patch.Name}, "-")) + ".yaml"
+}
+
+// PatchesTo writes patches as a YAML stream, each document preceded by the
+// kubectl command applying it once saved to its own file.
+func PatchesTo(w io.Writer, patches []servicerecommendations.Patch) {
+	var buffer bytes.Buffer
+	for _, patch := range patches {
+		_, _ = fmt.Fprintf(&buffer, "---\n# %s\n", patch.Command(PatchFileName(patch)))
+		enc := yaml.NewEncoder(&buffer)
+		enc.SetIndent(patchIndent)
+		if err := enc.Encode(patch.Patch); err != nil {
+			slog.Error("failed to encode recommendation patch as yaml", "error", err)
+		}
+		_ = enc.Close()
+	}
+	_, _ = io.WriteString(w, buffer.String())
+}
@@ -0,0 +1,66 @@
+package recommendations
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	servicerecommendations "github.com/trezorg/k8spodsmetrics/internal/recommendations"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+)
+
+func testRecommendation() servicerecommendations.Recommendation {
+	return servicerecommendations.Recommendation{
+		Namespace: "default",
+		Kind:      "Deployment",
+		Workload:  "web",
+		Container: "proxy",
+		Type:      pods.SidecarContainer,
+		Samples:   20,
+		Usage: servicerecommendations.Usage{
+			CPU:    servicerecommendations.Percentiles{P50: 10, P90: 20, P99: 30, Max: 40},
+			Memory: servicerecommendations.Percentiles{P50: 1 << 20, P90: 2 << 20, P99: 3 << 20, Max: 4 << 20},
+		},
+		Current: servicerecommendations.Resources{
+			Requests: servicerecommendations.Values{CPU: 100, Memory: 64 << 20},
+		},
+		Recommended: servicerecommendations.Resources{
+			Requests: servicerecommendations.Values{CPU: 20, Memory: 2 << 20},
+			Limits:   servicerecommendations.Values{Memory: 5 << 20},
+		},
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	formatter := New(testRecommendation())
+
+	require.Equal(t, "Deployment/web", formatter.NameString())
+	require.Equal(t, "proxy (sidecar)", formatter.ContainerString())
+	require.Equal(t, "20", formatter.SamplesString())
+	require.Equal(t, "10/20/30/40", formatter.CPUUsageString())
+	require.Equal(t, "1MiB/2MiB/3MiB/4MiB", formatter.MemoryUsageString())
+	require.Equal(t, "100 → 20", formatter.CPURequestString())
+	require.Equal(t, "-", formatter.CPULimitString())
+	require.Equal(t, "64MiB → 2MiB", formatter.MemoryRequestString())
+	require.Equal(t, "- → 5MiB", formatter.MemoryLimitString())
+}
+
+func TestPatchesTo(t *testing.T) {
+	var buf bytes.Buffer
+	PatchesTo(&buf, servicerecommendations.RecommendationList{testRecommendation()}.Patches())
+
+	require.Equal(t, `---
+# kubectl -n default patch deployment web --type strategic --patch-file default-deployment-web.yaml
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: proxy
+          resources:
+            requests:
+              cpu: 20m
+              memory: 2Mi
+            limits:
+              memory: 5Mi
+`, buf.String())
+}
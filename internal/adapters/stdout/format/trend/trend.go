@@ -0,0 +1,77 @@
+// Package trend renders usage history as unicode sparklines with an
+// indicator of where usage is heading.
+package trend
+
+import (
+	"slices"
+
+	"github.com/trezorg/k8spodsmetrics/internal/history"
+)
+
+const (
+	Rising  = "↑"
+	Falling = "↓"
+	Steady  = "→"
+
+	// tolerancePercent is the change between the last two samples still
+	// shown as steady.
+	tolerancePercent = 5
+	unknownBar       = " "
+)
+
+var bars = []rune("▁▂▃▄▅▆▇█")
+
+// String returns the sparkline of values followed by the trend indicator,
+// empty without samples.
+func String(values []int64) string {
+	sparkline := Sparkline(values)
+	indicator := Indicator(values)
+	if indicator == "" {
+		return sparkline
+	}
+	return sparkline + " " + indicator
+}
+
+// Sparkline draws a bar per sample scaled from zero to the largest sample,
+// so bars of the same height mean the same usage. Unknown samples are left
+// blank.
+func Sparkline(values []int64) string {
+	highest := int64(0)
+	for _, value := range values {
+		highest = max(highest, value)
+	}
+	result := make([]rune, 0, len(values))
+	for _, value := range values {
+		switch {
+		case value == history.Unknown:
+			result = append(result, []rune(unknownBar)...)
+		case highest == 0:
+			result = append(result, bars[0])
+		default:
+			result = append(result, bars[value*int64(len(bars)-1)/highest])
+		}
+	}
+	return string(result)
+}
+
+// Indicator compares the last two known samples: rising or falling when they
+// differ by more than 5 percent, steady otherwise. It is empty with fewer
+// than two known samples.
+func Indicator(values []int64) string {
+	known := slices.DeleteFunc(slices.Clone(values), func(value int64) bool { return value == history.Unknown })
+	if len(known) < 2 {
+		return ""
+	}
+	previous, last := known[len(known)-2], known[len(known)-1]
+	delta := last - previous
+	if delta < 0 {
+		delta = -delta
+	}
+	if delta == 0 || delta*100 <= previous*tolerancePercent {
+		return Steady
+	}
+	if last > previous {
+		return Rising
+	}
+	return Falling
+}
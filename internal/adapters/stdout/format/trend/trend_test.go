@@ -0,0 +1,55 @@
+package trend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/internal/history"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int64
+		want   string
+	}{
+		{name: "empty", want: ""},
+		{name: "scaled from zero", values: []int64{0, 50, 100}, want: "▁▄█"},
+		{name: "all zero", values: []int64{0, 0}, want: "▁▁"},
+		{name: "flat", values: []int64{70, 70}, want: "██"},
+		{name: "unknown samples are blank", values: []int64{history.Unknown, 10, history.Unknown, 20}, want: " ▄ █"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Sparkline(tt.values))
+		})
+	}
+}
+
+func TestIndicator(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int64
+		want   string
+	}{
+		{name: "single sample", values: []int64{10}, want: ""},
+		{name: "rising", values: []int64{10, 100, 120}, want: Rising},
+		{name: "falling", values: []int64{100, 80}, want: Falling},
+		{name: "within tolerance", values: []int64{100, 104}, want: Steady},
+		{name: "unchanged", values: []int64{0, 0}, want: Steady},
+		{name: "from zero", values: []int64{0, 1}, want: Rising},
+		{name: "skips unknown samples", values: []int64{100, history.Unknown, 50, history.Unknown}, want: Falling},
+		{name: "one known sample", values: []int64{history.Unknown, 50}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Indicator(tt.values))
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	require.Equal(t, "▁█ ↑", String([]int64{10, 80}))
+	require.Equal(t, "█", String([]int64{80}))
+	require.Empty(t, String(nil))
+}
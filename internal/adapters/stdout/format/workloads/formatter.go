@@ -0,0 +1,165 @@
+package workloads
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	escapes "github.com/snugfox/ansi-escapes"
+	alerts "github.com/trezorg/k8spodsmetrics/internal/alert"
+	"github.com/trezorg/k8spodsmetrics/internal/humanize"
+	serviceworkloads "github.com/trezorg/k8spodsmetrics/internal/workloads"
+)
+
+type Formatter struct {
+	workload serviceworkloads.Workload
+}
+
+func New(workload serviceworkloads.Workload) Formatter {
+	return Formatter{workload: workload}
+}
+
+func cpu(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
+func memory(value int64) string {
+	return humanize.Bytes(value)
+}
+
+// NameString returns the workload as kind/name.
+func (f Formatter) NameString() string {
+	return f.workload.Kind + "/" + f.workload.Name
+}
+
+// ReplicasString returns the replica count annotated with the replicas
+// metrics-server reported nothing for.
+func (f Formatter) ReplicasString() string {
+	if f.workload.MetricsMissing == 0 {
+		return strconv.Itoa(f.workload.Replicas)
+	}
+	return fmt.Sprintf("%d (%d no metrics)", f.workload.Replicas, f.workload.MetricsMissing)
+}
+
+func (f Formatter) CPURequestString() string {
+	return cpu(f.workload.Requests.CPU.Total)
+}
+
+func (f Formatter) CPURequestStatsString() string {
+	return statsString(f.workload.Requests.CPU, cpu)
+}
+
+func (f Formatter) CPULimitString() string {
+	return cpu(f.workload.Limits.CPU.Total)
+}
+
+func (f Formatter) CPULimitStatsString() string {
+	return statsString(f.workload.Limits.CPU, cpu)
+}
+
+func (f Formatter) CPUUsedString() string {
+	if !f.workload.HasMetrics() {
+		return ""
+	}
+	return colored(cpu(f.workload.Used.CPU.Total), escapes.TextColorRed, f.workload.IsAlerted(alerts.CPU))
+}
+
+func (f Formatter) CPUUsedStatsString() string {
+	if !f.workload.HasMetrics() {
+		return ""
+	}
+	return statsString(f.workload.Used.CPU, cpu)
+}
+
+func (f Formatter) MemoryRequestString() string {
+	return memory(f.workload.Requests.Memory.Total)
+}
+
+func (f Formatter) MemoryRequestStatsString() string {
+	return statsString(f.workload.Requests.Memory, memory)
+}
+
+func (f Formatter) MemoryLimitString() string {
+	return memory(f.workload.Limits.Memory.Total)
+}
+
+func (f Formatter) MemoryLimitStatsString() string {
+	return statsString(f.workload.Limits.Memory, memory)
+}
+
+func (f Formatter) MemoryUsedString() string {
+	if !f.workload.HasMetrics() {
+		return ""
+	}
+	return colored(memory(f.workload.Used.Memory.Total), escapes.TextColorRed, f.workload.IsAlerted(alerts.Memory))
+}
+
+func (f Formatter) MemoryUsedStatsString() string {
+	if !f.workload.HasMetrics() {
+		return ""
+	}
+	return statsString(f.workload.Used.Memory, memory)
+}
+
+// CPUCompactString returns request/used/limit totals.
+func (f Formatter) CPUCompactString() string {
+	return compactTriple(f.CPURequestString(), f.CPUUsedString(), f.CPULimitString())
+}
+
+func (f Formatter) MemoryCompactString() string {
+	return compactTriple(f.MemoryRequestString(), f.MemoryUsedString(), f.MemoryLimitString())
+}
+
+func (f Formatter) CPUTemplate() string {
+	return template(
+		f.workload.Requests.CPU,
+		f.workload.Limits.CPU,
+		f.workload.Used.CPU,
+		f.workload.HasMetrics(),
+		cpu,
+	)
+}
+
+func (f Formatter) MemoryTemplate() string {
+	return template(
+		f.workload.Requests.Memory,
+		f.workload.Limits.Memory,
+		f.workload.Used.Memory,
+		f.workload.HasMetrics(),
+		memory,
+	)
+}
+
+// statsString returns the per-replica average, minimum and maximum.
+func statsString(stats serviceworkloads.Stats, format func(int64) string) string {
+	return compactTriple(format(stats.Average), format(stats.Min), format(stats.Max))
+}
+
+func template(requests, limits, used serviceworkloads.Stats, hasMetrics bool, format func(int64) string) string {
+	result := fmt.Sprintf("Requests=%s, Limits=%s", statsTemplate(requests, format), statsTemplate(limits, format))
+	if hasMetrics {
+		result += fmt.Sprintf(", Used=%s", statsTemplate(used, format))
+	}
+	return result
+}
+
+func statsTemplate(stats serviceworkloads.Stats, format func(int64) string) string {
+	return fmt.Sprintf(
+		"%s (avg=%s, min=%s, max=%s)",
+		format(stats.Total),
+		format(stats.Average),
+		format(stats.Min),
+		format(stats.Max),
+	)
+}
+
+func colored(value, color string, alerted bool) string {
+	if !alerted {
+		return value
+	}
+	return color + value + escapes.ColorReset
+}
+
+func compactTriple(first, second, third string) string {
+	return strings.Join([]string{first, second, third}, "/")
+}
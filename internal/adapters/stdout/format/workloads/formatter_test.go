@@ -0,0 +1,59 @@
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	serviceworkloads "github.com/trezorg/k8spodsmetrics/internal/workloads"
+)
+
+func testWorkload() serviceworkloads.Workload {
+	return serviceworkloads.Workload{
+		Namespace: "default",
+		Kind:      "Deployment",
+		Name:      "web",
+		Replicas:  2,
+		Requests: serviceworkloads.Resources{
+			CPU:    serviceworkloads.Stats{Total: 300, Average: 150, Min: 100, Max: 200},
+			Memory: serviceworkloads.Stats{Total: 2048, Average: 1024, Min: 1024, Max: 1024},
+		},
+		Limits: serviceworkloads.Resources{
+			CPU: serviceworkloads.Stats{Total: 600, Average: 300, Min: 200, Max: 400},
+		},
+		Used: serviceworkloads.Resources{
+			CPU: serviceworkloads.Stats{Total: 50, Average: 25, Min: 10, Max: 40},
+		},
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	formatter := New(testWorkload())
+
+	require.Equal(t, "Deployment/web", formatter.NameString())
+	require.Equal(t, "2", formatter.ReplicasString())
+	require.Equal(t, "300", formatter.CPURequestString())
+	require.Equal(t, "150/100/200", formatter.CPURequestStatsString())
+	require.Equal(t, "600", formatter.CPULimitString())
+	require.Equal(t, "50", formatter.CPUUsedString())
+	require.Equal(t, "25/10/40", formatter.CPUUsedStatsString())
+	require.Equal(t, "2KiB", formatter.MemoryRequestString())
+	require.Equal(t, "1KiB/1KiB/1KiB", formatter.MemoryRequestStatsString())
+	require.Equal(t, "300/50/600", formatter.CPUCompactString())
+	require.Equal(t,
+		"Requests=300 (avg=150, min=100, max=200), Limits=600 (avg=300, min=200, max=400), Used=50 (avg=25, min=10, max=40)",
+		formatter.CPUTemplate(),
+	)
+}
+
+func TestFormatterWithoutMetrics(t *testing.T) {
+	workload := testWorkload()
+	workload.MetricsMissing = 2
+	formatter := New(workload)
+
+	require.Equal(t, "2 (2 no metrics)", formatter.ReplicasString())
+	require.Empty(t, formatter.CPUUsedString())
+	require.Empty(t, formatter.CPUUsedStatsString())
+	require.Empty(t, formatter.MemoryUsedString())
+	require.Equal(t, "300//600", formatter.CPUCompactString())
+	require.NotContains(t, formatter.MemoryTemplate(), "Used=")
+}
@@ -0,0 +1,36 @@
+package diff
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+	"log/slog"
+)
+
+type JSON func(report diff.Report)
+
+func Print(report diff.Report) {
+	PrintTo(os.Stdout, report)
+}
+
+func PrintTo(w io.Writer, report diff.Report) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(report); err != nil {
+		slog.Error("failed to encode diff as json", "error", err)
+	}
+}
+
+func (JSON) SuccessTo(w io.Writer, report diff.Report) {
+	PrintTo(w, report)
+}
+
+func (j JSON) Success(report diff.Report) {
+	j(report)
+}
+
+func (JSON) Error(err error) {
+	slog.Error("json diff output failed", "error", err)
+}
@@ -0,0 +1,51 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+)
+
+func TestPrintTo(t *testing.T) {
+	report := diff.Report{
+		PodsCompared: true,
+		Pods: []diff.Pod{{
+			Namespace: "default",
+			Name:      "web",
+			Change:    diff.Changed,
+			Containers: []diff.Container{{
+				Name:   "app",
+				Change: diff.Changed,
+				Limits: diff.Resources{Memory: &diff.Value{Before: 128, After: 256, Delta: 128}},
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, report)
+
+	var decoded diff.Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, report, decoded)
+	require.NotContains(t, buf.String(), `"requests"`)
+	require.NotContains(t, buf.String(), `"nodes"`)
+}
+
+func TestJSON_Success(t *testing.T) {
+	called := false
+	formatter := JSON(func(diff.Report) { called = true })
+	formatter.Success(diff.Report{})
+	require.True(t, called)
+}
+
+func TestJSON_Error(t *testing.T) {
+	formatter := JSON(Print)
+	require.NotPanics(t, func() {
+		formatter.Error(errors.New("test error"))
+	})
+}
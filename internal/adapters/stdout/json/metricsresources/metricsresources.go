@@ -6,29 +6,30 @@ import (
 	"os"
 
 	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"log/slog"
 )
 
-type JSON func(list metricsresources.PodMetricsResourceList)
+type JSON func(list metricsresources.PodMetricsResourceList, warnings partial.Warnings)
 
-func Print(list metricsresources.PodMetricsResourceList) {
-	PrintTo(os.Stdout, list)
+func Print(list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	PrintTo(os.Stdout, list, warnings)
 }
 
-func PrintTo(w io.Writer, list metricsresources.PodMetricsResourceList) {
+func PrintTo(w io.Writer, list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "    ")
-	if err := enc.Encode(list); err != nil {
+	if err := enc.Encode(list.Envelope(warnings)); err != nil {
 		slog.Error("failed to encode metrics resources as json", "error", err)
 	}
 }
 
-func (JSON) SuccessTo(w io.Writer, list metricsresources.PodMetricsResourceList) {
-	PrintTo(w, list)
+func (JSON) SuccessTo(w io.Writer, list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	PrintTo(w, list, warnings)
 }
 
-func (j JSON) Success(list metricsresources.PodMetricsResourceList) {
-	j(list)
+func (j JSON) Success(list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	j(list, warnings)
 }
 
 func (JSON) Error(err error) {
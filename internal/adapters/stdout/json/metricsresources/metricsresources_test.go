@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
 )
@@ -37,7 +38,7 @@ func TestPrint(t *testing.T) {
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 
-		Print(list)
+		Print(list, nil)
 
 		w.Close()
 		os.Stdout = old
@@ -60,7 +61,7 @@ func TestPrint(t *testing.T) {
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 
-		Print(list)
+		Print(list, nil)
 
 		w.Close()
 		os.Stdout = old
@@ -76,6 +77,21 @@ func TestPrint(t *testing.T) {
 	})
 }
 
+func TestPrintToPartialResults(t *testing.T) {
+	var buf bytes.Buffer
+	PrintTo(&buf, metricsresources.PodMetricsResourceList{}, partial.Warnings{
+		{Scope: partial.Namespace, Name: "team-b", Message: "forbidden"},
+	})
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, []any{map[string]any{"scope": "namespace", "name": "team-b", "message": "forbidden"}}, decoded["errors"])
+
+	buf.Reset()
+	PrintTo(&buf, metricsresources.PodMetricsResourceList{}, nil)
+	require.NotContains(t, buf.String(), "errors")
+}
+
 func TestJSON_Success(t *testing.T) {
 	t.Run("calls Print", func(t *testing.T) {
 		list := metricsresources.PodMetricsResourceList{
@@ -99,7 +115,7 @@ func TestJSON_Success(t *testing.T) {
 		os.Stdout = w
 
 		formatter := JSON(Print)
-		formatter.Success(list)
+		formatter.Success(list, nil)
 
 		w.Close()
 		os.Stdout = old
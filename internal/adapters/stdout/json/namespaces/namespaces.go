@@ -0,0 +1,37 @@
+package namespaces
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
+	"log/slog"
+)
+
+type JSON func(list namespaces.NamespaceResourceList)
+
+func Print(list namespaces.NamespaceResourceList) {
+	PrintTo(os.Stdout, list)
+}
+
+func PrintTo(w io.Writer, list namespaces.NamespaceResourceList) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	envelope := namespaces.NamespaceResourceListEnvelope{Items: list}
+	if err := enc.Encode(envelope); err != nil {
+		slog.Error("failed to encode namespaces as json", "error", err)
+	}
+}
+
+func (JSON) SuccessTo(w io.Writer, list namespaces.NamespaceResourceList) {
+	PrintTo(w, list)
+}
+
+func (j JSON) Success(list namespaces.NamespaceResourceList) {
+	j(list)
+}
+
+func (JSON) Error(err error) {
+	slog.Error("json namespaces output failed", "error", err)
+}
@@ -0,0 +1,49 @@
+package namespaces
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+)
+
+func TestPrintTo(t *testing.T) {
+	t.Run("prints valid JSON", func(t *testing.T) {
+		podsQuota := quotas.NewQuota(10, 4)
+		list := namespaces.NamespaceResourceList{
+			{Name: "team-a", Pods: 4, CPURequest: 400, Quota: quotas.NamespaceQuota{Pods: &podsQuota}},
+		}
+
+		var buf bytes.Buffer
+		PrintTo(&buf, list)
+		require.Contains(t, buf.String(), `"percent": 40`)
+
+		var decoded namespaces.NamespaceResourceListEnvelope
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		require.Len(t, decoded.Items, 1)
+		require.Equal(t, "team-a", decoded.Items[0].Name)
+		require.Equal(t, &podsQuota, decoded.Items[0].Quota.Pods)
+		require.Nil(t, decoded.Items[0].Quota.RequestsCPU)
+	})
+
+	t.Run("prints empty list", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintTo(&buf, namespaces.NamespaceResourceList{})
+
+		var decoded namespaces.NamespaceResourceListEnvelope
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		require.Empty(t, decoded.Items)
+	})
+}
+
+func TestJSON_Error(t *testing.T) {
+	formatter := JSON(Print)
+	require.NotPanics(t, func() {
+		formatter.Error(errors.New("test error"))
+	})
+}
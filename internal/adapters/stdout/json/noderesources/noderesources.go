@@ -18,12 +18,35 @@ func Print(list noderesources.NodeResourceList) {
 func PrintTo(w io.Writer, list noderesources.NodeResourceList) {
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "    ")
-	envelope := noderesources.NodeResourceListEnvelope{Items: list}
+	envelope := noderesources.NewNodeResourceListEnvelope(list)
 	if err := enc.Encode(envelope); err != nil {
 		slog.Error("failed to encode node resources as json", "error", err)
 	}
 }
 
+// ToGroups prints nodes grouped by the value of label.
+func ToGroups(label string) JSON {
+	return JSON(func(list noderesources.NodeResourceList) {
+		PrintGroupsTo(os.Stdout, list, label)
+	})
+}
+
+// ToGroupsWriter is the writer form of ToGroups used in watch mode.
+func ToGroupsWriter(label string) func(io.Writer, noderesources.NodeResourceList) {
+	return func(w io.Writer, list noderesources.NodeResourceList) {
+		PrintGroupsTo(w, list, label)
+	}
+}
+
+func PrintGroupsTo(w io.Writer, list noderesources.NodeResourceList, label string) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	envelope := noderesources.NewNodeResourceGroupListEnvelope(list, label)
+	if err := enc.Encode(envelope); err != nil {
+		slog.Error("failed to encode node resource groups as json", "error", err)
+	}
+}
+
 func (JSON) SuccessTo(w io.Writer, list noderesources.NodeResourceList) {
 	PrintTo(w, list)
 }
@@ -67,6 +67,23 @@ func TestPrint(t *testing.T) {
 	})
 }
 
+func TestPrintToClusters(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{Cluster: "prod-us", Name: "node-1", AllocatableCPU: 1000},
+		{Cluster: "prod-eu", Name: "node-1", AllocatableCPU: 2000},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list)
+
+	var decoded noderesources.NodeResourceListEnvelope
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, "prod-us", decoded.Items[0].Cluster)
+	require.Len(t, decoded.Clusters, 2)
+	require.Equal(t, int64(2000), decoded.Clusters[1].Total.AllocatableCPU)
+	require.Equal(t, int64(3000), decoded.Total.Total.AllocatableCPU)
+}
+
 func TestJSON_Success(t *testing.T) {
 	t.Run("calls Print", func(t *testing.T) {
 		list := noderesources.NodeResourceList{
@@ -107,3 +124,25 @@ func TestJSON_Error(t *testing.T) {
 		})
 	})
 }
+
+func TestPrintGroupsTo(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{Name: "node-1", AllocatableCPU: 1000, Labels: map[string]string{"zone": "a"}},
+		{Name: "node-2", AllocatableCPU: 2000, Labels: map[string]string{"zone": "a"}},
+		{Name: "node-3", AllocatableCPU: 4000, Labels: map[string]string{"zone": "b"}},
+	}
+
+	var buf bytes.Buffer
+	ToGroupsWriter("zone")(&buf, list)
+
+	var decoded noderesources.NodeResourceGroupListEnvelope
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded.Groups, 2)
+	require.Equal(t, "zone", decoded.Groups[0].Label)
+	require.Equal(t, "a", decoded.Groups[0].Value)
+	require.Equal(t, 2, decoded.Groups[0].Nodes)
+	require.Equal(t, int64(3000), decoded.Groups[0].Total.AllocatableCPU)
+	require.Len(t, decoded.Groups[0].Items, 2)
+	require.Equal(t, "b", decoded.Groups[1].Value)
+	require.NotContains(t, buf.String(), "labels")
+}
@@ -0,0 +1,37 @@
+package recommendations
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/trezorg/k8spodsmetrics/internal/recommendations"
+	"log/slog"
+)
+
+type JSON func(list recommendations.RecommendationList)
+
+func Print(list recommendations.RecommendationList) {
+	PrintTo(os.Stdout, list)
+}
+
+func PrintTo(w io.Writer, list recommendations.RecommendationList) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	envelope := recommendations.NewRecommendationListEnvelope(list)
+	if err := enc.Encode(envelope); err != nil {
+		slog.Error("failed to encode recommendations as json", "error", err)
+	}
+}
+
+func (JSON) SuccessTo(w io.Writer, list recommendations.RecommendationList) {
+	PrintTo(w, list)
+}
+
+func (j JSON) Success(list recommendations.RecommendationList) {
+	j(list)
+}
+
+func (JSON) Error(err error) {
+	slog.Error("json recommendations output failed", "error", err)
+}
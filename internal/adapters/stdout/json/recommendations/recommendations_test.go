@@ -0,0 +1,57 @@
+package recommendations
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/recommendations"
+)
+
+func TestPrintTo(t *testing.T) {
+	list := recommendations.RecommendationList{
+		{
+			Namespace:   "default",
+			Kind:        "StatefulSet",
+			Workload:    "db",
+			Container:   "db",
+			Samples:     2,
+			Recommended: recommendations.Resources{Requests: recommendations.Values{CPU: 250}},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list)
+
+	var decoded struct {
+		Items   recommendations.RecommendationList `json:"items"`
+		Patches []struct {
+			Kind  string         `json:"kind"`
+			Name  string         `json:"name"`
+			Patch map[string]any `json:"patch"`
+		} `json:"patches"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded.Items, 1)
+	require.Equal(t, int64(250), decoded.Items[0].Recommended.Requests.CPU)
+	require.Len(t, decoded.Patches, 1)
+	require.Equal(t, "StatefulSet", decoded.Patches[0].Kind)
+	require.Contains(t, decoded.Patches[0].Patch, "spec")
+}
+
+func TestJSON_Success(t *testing.T) {
+	called := false
+	formatter := JSON(func(recommendations.RecommendationList) { called = true })
+	formatter.Success(recommendations.RecommendationList{})
+	require.True(t, called)
+}
+
+func TestJSON_Error(t *testing.T) {
+	formatter := JSON(Print)
+	require.NotPanics(t, func() {
+		formatter.Error(errors.New("test error"))
+	})
+}
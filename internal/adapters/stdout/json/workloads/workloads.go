@@ -0,0 +1,37 @@
+package workloads
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+	"log/slog"
+)
+
+type JSON func(list workloads.WorkloadList)
+
+func Print(list workloads.WorkloadList) {
+	PrintTo(os.Stdout, list)
+}
+
+func PrintTo(w io.Writer, list workloads.WorkloadList) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	envelope := workloads.WorkloadListEnvelope{Items: list}
+	if err := enc.Encode(envelope); err != nil {
+		slog.Error("failed to encode workloads as json", "error", err)
+	}
+}
+
+func (JSON) SuccessTo(w io.Writer, list workloads.WorkloadList) {
+	PrintTo(w, list)
+}
+
+func (j JSON) Success(list workloads.WorkloadList) {
+	j(list)
+}
+
+func (JSON) Error(err error) {
+	slog.Error("json workloads output failed", "error", err)
+}
@@ -0,0 +1,58 @@
+package workloads
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+)
+
+func TestPrintTo(t *testing.T) {
+	t.Run("prints valid JSON", func(t *testing.T) {
+		list := workloads.WorkloadList{
+			{
+				Namespace: "default",
+				Kind:      "Deployment",
+				Name:      "web",
+				Replicas:  2,
+				Requests:  workloads.Resources{CPU: workloads.Stats{Total: 200, Average: 100, Min: 100, Max: 100}},
+			},
+		}
+
+		var buf bytes.Buffer
+		PrintTo(&buf, list)
+
+		var decoded workloads.WorkloadListEnvelope
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		require.Len(t, decoded.Items, 1)
+		require.Equal(t, "web", decoded.Items[0].Name)
+		require.Equal(t, int64(100), decoded.Items[0].Requests.CPU.Average)
+	})
+
+	t.Run("prints empty list", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintTo(&buf, workloads.WorkloadList{})
+
+		var decoded workloads.WorkloadListEnvelope
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		require.Empty(t, decoded.Items)
+	})
+}
+
+func TestJSON_Success(t *testing.T) {
+	called := false
+	formatter := JSON(func(workloads.WorkloadList) { called = true })
+	formatter.Success(workloads.WorkloadList{})
+	require.True(t, called)
+}
+
+func TestJSON_Error(t *testing.T) {
+	formatter := JSON(Print)
+	require.NotPanics(t, func() {
+		formatter.Error(errors.New("test error"))
+	})
+}
@@ -5,21 +5,34 @@ import (
 
 	"github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/screenutil"
 	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 )
 
-type ScreenSuccessWriter func(list metricsresources.PodMetricsResourceList)
+type ScreenSuccessWriter func(list metricsresources.PodMetricsResourceList, warnings partial.Warnings)
 type ScreenErrorWriter func(err error)
 
-func NewScreenSuccessWriter(writer func(io.Writer, metricsresources.PodMetricsResourceList)) ScreenSuccessWriter {
-	return ScreenSuccessWriter(screenutil.WrapScreenSuccess(writer))
+type partialList struct {
+	list     metricsresources.PodMetricsResourceList
+	warnings partial.Warnings
+}
+
+func NewScreenSuccessWriter(
+	writer func(io.Writer, metricsresources.PodMetricsResourceList, partial.Warnings),
+) ScreenSuccessWriter {
+	write := screenutil.WrapScreenSuccess(func(w io.Writer, value partialList) {
+		writer(w, value.list, value.warnings)
+	})
+	return func(list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+		write(partialList{list: list, warnings: warnings})
+	}
 }
 
 func NewScreenErrorWriter(writer metricsresources.ErrorProcessor) ScreenErrorWriter {
 	return ScreenErrorWriter(screenutil.WrapScreenError(writer.Error))
 }
 
-func (s ScreenSuccessWriter) Success(list metricsresources.PodMetricsResourceList) {
-	s(list)
+func (s ScreenSuccessWriter) Success(list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	s(list, warnings)
 }
 
 func (s ScreenErrorWriter) Error(err error) {
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
 )
@@ -23,7 +24,7 @@ func (m *mockErrorProcessor) Error(err error) {
 func TestNewScreenSuccessWriter(t *testing.T) {
 	t.Run("creates success writer", func(t *testing.T) {
 		called := false
-		writer := NewScreenSuccessWriter(func(_ io.Writer, _ metricsresources.PodMetricsResourceList) {
+		writer := NewScreenSuccessWriter(func(_ io.Writer, _ metricsresources.PodMetricsResourceList, _ partial.Warnings) {
 			called = true
 		})
 		require.NotNil(t, writer)
@@ -44,7 +45,7 @@ func TestNewScreenSuccessWriter(t *testing.T) {
 			},
 		}
 
-		writer.Success(list)
+		writer.Success(list, nil)
 		require.True(t, called)
 	})
 }
@@ -64,10 +65,10 @@ func TestNewScreenErrorWriter(t *testing.T) {
 func TestScreenSuccessWriter_Success(t *testing.T) {
 	t.Run("calls underlying writer", func(t *testing.T) {
 		called := false
-		writer := NewScreenSuccessWriter(func(_ io.Writer, _ metricsresources.PodMetricsResourceList) {
+		writer := NewScreenSuccessWriter(func(_ io.Writer, _ metricsresources.PodMetricsResourceList, _ partial.Warnings) {
 			called = true
 		})
-		writer.Success(metricsresources.PodMetricsResourceList{})
+		writer.Success(metricsresources.PodMetricsResourceList{}, nil)
 		require.True(t, called)
 	})
 }
@@ -0,0 +1,27 @@
+package namespaces
+
+import (
+	"io"
+
+	"github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/screenutil"
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
+)
+
+type ScreenSuccessWriter func(list namespaces.NamespaceResourceList)
+type ScreenErrorWriter func(err error)
+
+func NewScreenSuccessWriter(writer func(io.Writer, namespaces.NamespaceResourceList)) ScreenSuccessWriter {
+	return ScreenSuccessWriter(screenutil.WrapScreenSuccess(writer))
+}
+
+func NewScreenErrorWriter(writer namespaces.ErrorProcessor) ScreenErrorWriter {
+	return ScreenErrorWriter(screenutil.WrapScreenError(writer.Error))
+}
+
+func (s ScreenSuccessWriter) Success(list namespaces.NamespaceResourceList) {
+	s(list)
+}
+
+func (s ScreenErrorWriter) Error(err error) {
+	s(err)
+}
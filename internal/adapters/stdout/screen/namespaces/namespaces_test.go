@@ -0,0 +1,35 @@
+package namespaces
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
+)
+
+type mockErrorProcessor struct {
+	called bool
+}
+
+func (m *mockErrorProcessor) Error(error) {
+	m.called = true
+}
+
+func TestScreenSuccessWriter_Success(t *testing.T) {
+	called := false
+	writer := NewScreenSuccessWriter(func(_ io.Writer, _ namespaces.NamespaceResourceList) {
+		called = true
+	})
+	writer.Success(namespaces.NamespaceResourceList{{Name: "team-a"}})
+	require.True(t, called)
+}
+
+func TestScreenErrorWriter_Error(t *testing.T) {
+	mock := &mockErrorProcessor{}
+	writer := NewScreenErrorWriter(mock)
+	writer.Error(errors.New("test error"))
+	require.True(t, mock.called)
+}
@@ -0,0 +1,27 @@
+package workloads
+
+import (
+	"io"
+
+	"github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/screenutil"
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+)
+
+type ScreenSuccessWriter func(list workloads.WorkloadList)
+type ScreenErrorWriter func(err error)
+
+func NewScreenSuccessWriter(writer func(io.Writer, workloads.WorkloadList)) ScreenSuccessWriter {
+	return ScreenSuccessWriter(screenutil.WrapScreenSuccess(writer))
+}
+
+func NewScreenErrorWriter(writer workloads.ErrorProcessor) ScreenErrorWriter {
+	return ScreenErrorWriter(screenutil.WrapScreenError(writer.Error))
+}
+
+func (s ScreenSuccessWriter) Success(list workloads.WorkloadList) {
+	s(list)
+}
+
+func (s ScreenErrorWriter) Error(err error) {
+	s(err)
+}
@@ -0,0 +1,35 @@
+package workloads
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+)
+
+type mockErrorProcessor struct {
+	called bool
+}
+
+func (m *mockErrorProcessor) Error(error) {
+	m.called = true
+}
+
+func TestScreenSuccessWriter_Success(t *testing.T) {
+	called := false
+	writer := NewScreenSuccessWriter(func(_ io.Writer, _ workloads.WorkloadList) {
+		called = true
+	})
+	writer.Success(workloads.WorkloadList{{Name: "web"}})
+	require.True(t, called)
+}
+
+func TestScreenErrorWriter_Error(t *testing.T) {
+	mock := &mockErrorProcessor{}
+	writer := NewScreenErrorWriter(mock)
+	writer.Error(errors.New("test error"))
+	require.True(t, mock.called)
+}
@@ -0,0 +1,159 @@
+package diff
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	formatdiff "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/diff"
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+)
+
+const (
+	podNameColumns  = 4
+	podColumns      = 11
+	nodeNameColumns = 2
+	nodeColumns     = 10
+)
+
+type Table func(report diff.Report)
+
+func ToTable() Table {
+	return Table(func(report diff.Report) {
+		PrintTo(os.Stdout, report)
+	})
+}
+
+// PrintTo renders a table of changed pod containers and a table of changed
+// nodes, coloured by change.
+func PrintTo(w io.Writer, report diff.Report) {
+	if report.Empty() {
+		_, _ = io.WriteString(w, "no changes\n")
+		return
+	}
+	if len(report.Pods) > 0 {
+		t := newTable(w, podNameColumns, podColumns)
+		t.AppendHeader(podsHeaderRow())
+		for _, pod := range report.Pods {
+			for _, row := range podRows(pod) {
+				t.AppendRow(row)
+			}
+		}
+		t.Render()
+	}
+	if len(report.Nodes) > 0 {
+		if len(report.Pods) > 0 {
+			_, _ = io.WriteString(w, "\n")
+		}
+		t := newTable(w, nodeNameColumns, nodeColumns)
+		t.AppendHeader(nodesHeaderRow())
+		for _, node := range report.Nodes {
+			t.AppendRow(nodeRow(node))
+		}
+		t.Render()
+	}
+}
+
+func podsHeaderRow() table.Row {
+	return table.Row{
+		"CHANGE",
+		"NAMESPACE",
+		"WORKLOAD",
+		"CONTAINER",
+		"REPLICAS",
+		"CPU REQ",
+		"CPU LIM",
+		"CPU USED",
+		"MEM REQ",
+		"MEM LIM",
+		"MEM USED",
+	}
+}
+
+func nodesHeaderRow() table.Row {
+	return table.Row{
+		"CHANGE",
+		"NODE",
+		"CPU ALLOC",
+		"CPU REQ",
+		"CPU LIM",
+		"CPU USED",
+		"MEM ALLOC",
+		"MEM REQ",
+		"MEM LIM",
+		"MEM USED",
+	}
+}
+
+// podRows returns a row for every container of the pod or workload, one
+// without containers gets a single row.
+func podRows(pod diff.Pod) []table.Row {
+	namespace := formatdiff.Name(pod.Cluster, pod.Namespace)
+	name := formatdiff.PodName(pod)
+	replicas := formatdiff.New(pod.Change).ValueString(pod.Replicas, formatdiff.Count)
+	if len(pod.Containers) == 0 {
+		formatter := formatdiff.New(pod.Change)
+		return []table.Row{{formatter.ChangeString(), namespace, name, "", replicas, "-", "-", "-", "-", "-", "-"}}
+	}
+	rows := make([]table.Row, 0, len(pod.Containers))
+	for _, container := range pod.Containers {
+		formatter := formatdiff.New(container.Change)
+		rows = append(rows, table.Row{
+			formatter.ChangeString(),
+			namespace,
+			name,
+			container.Name,
+			replicas,
+			formatter.ValueString(container.Requests.CPU, formatdiff.CPU),
+			formatter.ValueString(container.Limits.CPU, formatdiff.CPU),
+			formatter.ValueString(container.Used.CPU, formatdiff.CPU),
+			formatter.ValueString(container.Requests.Memory, formatdiff.Memory),
+			formatter.ValueString(container.Limits.Memory, formatdiff.Memory),
+			formatter.ValueString(container.Used.Memory, formatdiff.Memory),
+		})
+	}
+	return rows
+}
+
+func nodeRow(node diff.Node) table.Row {
+	formatter := formatdiff.New(node.Change)
+	return table.Row{
+		formatter.ChangeString(),
+		formatdiff.Name(node.Cluster, node.Name),
+		formatter.ValueString(node.Allocatable.CPU, formatdiff.CPU),
+		formatter.ValueString(node.Requests.CPU, formatdiff.CPU),
+		formatter.ValueString(node.Limits.CPU, formatdiff.CPU),
+		formatter.ValueString(node.Used.CPU, formatdiff.CPU),
+		formatter.ValueString(node.Allocatable.Memory, formatdiff.Memory),
+		formatter.ValueString(node.Requests.Memory, formatdiff.Memory),
+		formatter.ValueString(node.Limits.Memory, formatdiff.Memory),
+		formatter.ValueString(node.Used.Memory, formatdiff.Memory),
+	}
+}
+
+// newTable aligns the name columns left and the value columns right.
+func newTable(w io.Writer, nameColumns, columns int) table.Writer {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.SetStyle(table.StyleLight)
+	configs := make([]table.ColumnConfig, 0, columns)
+	for number := 1; number <= columns; number++ {
+		align := text.AlignRight
+		if number <= nameColumns {
+			align = text.AlignLeft
+		}
+		configs = append(configs, table.ColumnConfig{Number: number, Align: align})
+	}
+	t.SetColumnConfigs(configs)
+	return t
+}
+
+func (s Table) Success(report diff.Report) {
+	s(report)
+}
+
+func (Table) Error(err error) {
+	slog.Error("table diff output failed", "error", err)
+}
@@ -0,0 +1,77 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+)
+
+func testReport() diff.Report {
+	return diff.Report{
+		PodsCompared:  true,
+		NodesCompared: true,
+		Pods: []diff.Pod{
+			{
+				Cluster:   "east",
+				Namespace: "default",
+				Name:      "web",
+				Change:    diff.Changed,
+				Containers: []diff.Container{{
+					Name:     "app",
+					Change:   diff.Changed,
+					Requests: diff.Resources{CPU: &diff.Value{Before: 100, After: 200, Delta: 100}},
+				}},
+			},
+			{Namespace: "default", Name: "empty", Change: diff.Removed},
+			{
+				Namespace: "default",
+				Kind:      "Deployment",
+				Name:      "api",
+				Change:    diff.Changed,
+				Replicas:  &diff.Value{Before: 3, After: 2, Delta: -1},
+			},
+		},
+		Nodes: []diff.Node{{
+			Name:        "node-2",
+			Change:      diff.Added,
+			Allocatable: diff.Resources{Memory: &diff.Value{After: 8 << 30, Delta: 8 << 30}},
+		}},
+	}
+}
+
+func TestPrintTo(t *testing.T) {
+	t.Run("renders pods and nodes", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintTo(&buf, testReport())
+
+		output := buf.String()
+		require.Contains(t, output, "CONTAINER")
+		require.Contains(t, output, "east/default")
+		require.Contains(t, output, "100 → 200 (+100)")
+		require.Contains(t, output, "empty")
+		require.Contains(t, output, "MEM ALLOC")
+		require.Contains(t, output, "8GiB")
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintTo(&buf, diff.Report{PodsCompared: true})
+
+		require.Equal(t, "no changes\n", buf.String())
+	})
+}
+
+func TestPodRows(t *testing.T) {
+	rows := podRows(testReport().Pods[1])
+
+	require.Len(t, rows, 1)
+	require.Equal(t, "empty", rows[0][2])
+	require.Equal(t, "-", rows[0][4])
+
+	rows = podRows(testReport().Pods[2])
+	require.Len(t, rows, 1)
+	require.Equal(t, "Deployment/api", rows[0][2])
+	require.Contains(t, rows[0][4], "3 → 2 (-1)")
+}
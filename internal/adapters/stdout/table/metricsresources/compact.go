@@ -7,60 +7,120 @@ import (
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	formatmetricsresources "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/history"
 	servicemetricsresources "github.com/trezorg/k8spodsmetrics/internal/metricsresources"
 	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 )
 
 const (
 	compactNamespaceColumn   = 1
 	compactPodColumn         = 2
 	compactNodeColumn        = 3
-	compactFirstMetricColumn = 4
-	compactSecondMetricCol   = 5
-	compactThirdMetricCol    = 6
-	maxCompactColumns        = 7
+	compactQOSColumn         = 4
+	compactRestartsColumn    = 5
+	compactFirstMetricColumn = 6
+	compactSecondMetricCol   = 7
+	compactThirdMetricCol    = 8
+	maxCompactColumns        = 9
 )
 
-func ToCompactTable(outputResources resources.Resources) Table {
-	return Table(func(list servicemetricsresources.PodMetricsResourceList) {
-		PrintCompactTo(os.Stdout, list, outputResources)
+func ToCompactTable(outputResources resources.Resources, efficiency bool) Table {
+	return Table(func(list servicemetricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+		printWarnings(os.Stdout, warnings)
+		printCompact(os.Stdout, list, outputResources, nil, efficiency)
 	})
 }
 
-func ToCompactWriter(outputResources resources.Resources) func(io.Writer, servicemetricsresources.PodMetricsResourceList) {
-	return func(w io.Writer, list servicemetricsresources.PodMetricsResourceList) {
-		PrintCompactTo(w, list, outputResources)
+func ToCompactWriter(
+	outputResources resources.Resources,
+	efficiency bool,
+) func(io.Writer, servicemetricsresources.PodMetricsResourceList, partial.Warnings) {
+	return func(w io.Writer, list servicemetricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+		printWarnings(w, warnings)
+		printCompact(w, list, outputResources, nil, efficiency)
+	}
+}
+
+// ToCompactTrendWriter renders the compact table with sparklines of the
+// recent CPU and memory usage of every pod. Every call adds the pods to
+// usage, so it is meant to render watch ticks.
+func ToCompactTrendWriter(
+	outputResources resources.Resources,
+	usage *history.History,
+	efficiency bool,
+) func(io.Writer, servicemetricsresources.PodMetricsResourceList, partial.Warnings) {
+	return func(w io.Writer, list servicemetricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+		usage.Update(podSamples(list))
+		printWarnings(w, warnings)
+		printCompact(w, list, outputResources, usage, efficiency)
 	}
 }
 
 func PrintCompactTo(w io.Writer, list servicemetricsresources.PodMetricsResourceList, outputResources resources.Resources) {
+	printCompact(w, list, outputResources, nil, false)
+}
+
+// printCompact adds trend columns when usage is set, and efficiency columns
+// with the waste of namespaces under the table when efficiency is set.
+func printCompact(
+	w io.Writer,
+	list servicemetricsresources.PodMetricsResourceList,
+	outputResources resources.Resources,
+	usage *history.History,
+	efficiency bool,
+) {
+	printMetricsUnavailable(w, list)
+	groups, withCluster := clusterGroups(list)
 	t := table.NewWriter()
 	t.SetOutputMirror(w)
-	configureCompactTable(t)
-	t.AppendHeader(compactHeaderRow(outputResources))
+	trends := compactTrends{usage: usage, outputResources: outputResources}
+	efficiencies := compactEfficiency{enabled: efficiency, outputResources: outputResources}
+	configureCompactTable(t, len(outputResources.Extended())+efficiencies.columns()+trends.columns(), withCluster)
+	t.AppendHeader(clusterRow(withCluster, "CLUSTER", trends.header(efficiencies.header(compactHeaderRow(outputResources)))))
 
 	total := servicemetricsresources.ContainerMetricsResource{}
+	totalWaste := servicemetricsresources.Waste{}
 	rendered := 0
-	for _, resource := range list {
-		containers := resource.ContainersMetrics()
-		if len(containers) == 0 {
-			continue
+	for _, group := range groups {
+		subtotal := servicemetricsresources.ContainerMetricsResource{}
+		subtotalWaste := servicemetricsresources.Waste{}
+		for _, resource := range group {
+			containers := resource.ContainersMetrics()
+			if len(containers) == 0 {
+				continue
+			}
+			aggregated := aggregatePodContainers(resource)
+			row := efficiencies.pod(aggregated, compactPodRow(resource, aggregated, outputResources))
+			t.AppendRow(clusterRow(withCluster, resource.Cluster, trends.pod(resource, row)))
+			accumulatePodTotal(&subtotal, aggregated)
+			accumulatePodTotal(&total, aggregated)
+			subtotalWaste.Add(resource)
+			totalWaste.Add(resource)
+			rendered++
+		}
+		if withCluster {
+			row := efficiencies.total(subtotalWaste, compactTotalRow(subtotal, outputResources))
+			row[0] = "SUBTOTAL " + group[0].Cluster
+			t.AppendRow(clusterRow(withCluster, group[0].Cluster, trends.blank(row)))
+			t.AppendSeparator()
 		}
-		aggregated := aggregatePodContainers(resource)
-		t.AppendRow(compactPodRow(resource, aggregated, outputResources))
-		accumulatePodTotal(&total, aggregated)
-		rendered++
 	}
 
 	if rendered > 1 {
-		t.AppendFooter(compactTotalRow(total, outputResources))
+		row := efficiencies.total(totalWaste, compactTotalRow(total, outputResources))
+		t.AppendFooter(clusterRow(withCluster, "", trends.blank(row)))
 	}
 
 	t.Render()
+	printMissingMetrics(w, list)
+	if efficiency {
+		printWaste(w, list)
+	}
 }
 
 func compactHeaderRow(outputResources resources.Resources) table.Row {
-	row := table.Row{"NAMESPACE", "POD", "NODE"}
+	row := table.Row{"NAMESPACE", "POD", "NODE", "QOS", "RESTARTS"}
 	if outputResources.IsCPU() {
 		row = append(row, "CPU(req/used/lim)")
 	}
@@ -70,6 +130,9 @@ func compactHeaderRow(outputResources resources.Resources) table.Row {
 	if outputResources.IsStorage() {
 		row = append(row, "STO(req/used/lim)", "EPH(req/used/lim)")
 	}
+	for _, name := range outputResources.Extended() {
+		row = append(row, name+"(req/lim)")
+	}
 	return row
 }
 
@@ -81,8 +144,10 @@ func compactPodRow(
 	formatter := formatmetricsresources.NewContainer(aggregated)
 	row := table.Row{
 		resource.PodResource.Namespace,
-		resource.PodResource.Name,
+		formatmetricsresources.PodName(resource),
 		resource.NodeName,
+		string(resource.QOSClass),
+		formatter.RestartsString(resource.ObservedAt()),
 	}
 	if outputResources.IsCPU() {
 		row = append(row, formatter.CPUCompactString())
@@ -93,12 +158,15 @@ func compactPodRow(
 	if outputResources.IsStorage() {
 		row = append(row, formatter.StorageCompactString(), formatter.StorageEphemeralCompactString())
 	}
+	for _, name := range outputResources.Extended() {
+		row = append(row, formatter.ExtendedCompactString(name))
+	}
 	return row
 }
 
 func compactTotalRow(total servicemetricsresources.ContainerMetricsResource, outputResources resources.Resources) table.Row {
 	formatter := formatmetricsresources.NewContainer(total)
-	row := table.Row{"TOTAL", "", ""}
+	row := table.Row{"TOTAL", "", "", "", ""}
 	if outputResources.IsCPU() {
 		row = append(row, formatter.CPUCompactString())
 	}
@@ -108,30 +176,14 @@ func compactTotalRow(total servicemetricsresources.ContainerMetricsResource, out
 	if outputResources.IsStorage() {
 		row = append(row, formatter.StorageCompactString(), formatter.StorageEphemeralCompactString())
 	}
+	for _, name := range outputResources.Extended() {
+		row = append(row, formatter.ExtendedCompactString(name))
+	}
 	return row
 }
 
 func aggregatePodContainers(resource servicemetricsresources.PodMetricsResource) servicemetricsresources.ContainerMetricsResource {
-	var aggregated servicemetricsresources.ContainerMetricsResource
-	for _, container := range resource.ContainersMetrics() {
-		aggregated.Requests.CPURequest += container.Requests.CPURequest
-		aggregated.Requests.MemoryRequest += container.Requests.MemoryRequest
-		aggregated.Requests.StorageRequest += container.Requests.StorageRequest
-		aggregated.Requests.StorageEphemeralRequest += container.Requests.StorageEphemeralRequest
-		aggregated.Limits.CPURequest += container.Limits.CPURequest
-		aggregated.Limits.MemoryRequest += container.Limits.MemoryRequest
-		aggregated.Limits.StorageRequest += container.Limits.StorageRequest
-		aggregated.Limits.StorageEphemeralRequest += container.Limits.StorageEphemeralRequest
-		aggregated.Requests.CPUUsed += container.Requests.CPUUsed
-		aggregated.Requests.MemoryUsed += container.Requests.MemoryUsed
-		aggregated.Requests.StorageUsed += container.Requests.StorageUsed
-		aggregated.Requests.StorageEphemeralUsed += container.Requests.StorageEphemeralUsed
-		aggregated.Limits.CPUUsed += container.Limits.CPUUsed
-		aggregated.Limits.MemoryUsed += container.Limits.MemoryUsed
-		aggregated.Limits.StorageUsed += container.Limits.StorageUsed
-		aggregated.Limits.StorageEphemeralUsed += container.Limits.StorageEphemeralUsed
-	}
-	return aggregated
+	return resource.PodMetrics()
 }
 
 func accumulatePodTotal(total *servicemetricsresources.ContainerMetricsResource, aggregated servicemetricsresources.ContainerMetricsResource) {
@@ -143,27 +195,35 @@ func accumulatePodTotal(total *servicemetricsresources.ContainerMetricsResource,
 	total.Limits.MemoryRequest += aggregated.Limits.MemoryRequest
 	total.Limits.StorageRequest += aggregated.Limits.StorageRequest
 	total.Limits.StorageEphemeralRequest += aggregated.Limits.StorageEphemeralRequest
-	total.Requests.CPUUsed += aggregated.Requests.CPUUsed
-	total.Requests.MemoryUsed += aggregated.Requests.MemoryUsed
-	total.Requests.StorageUsed += aggregated.Requests.StorageUsed
-	total.Requests.StorageEphemeralUsed += aggregated.Requests.StorageEphemeralUsed
-	total.Limits.CPUUsed += aggregated.Limits.CPUUsed
-	total.Limits.MemoryUsed += aggregated.Limits.MemoryUsed
-	total.Limits.StorageUsed += aggregated.Limits.StorageUsed
-	total.Limits.StorageEphemeralUsed += aggregated.Limits.StorageEphemeralUsed
+	total.Requests.Extended = addExtended(total.Requests.Extended, aggregated.Requests.Extended)
+	total.Limits.Extended = addExtended(total.Limits.Extended, aggregated.Limits.Extended)
+	total.Requests.CPUUsed += usedOrZero(aggregated.Requests.CPUUsed)
+	total.Requests.MemoryUsed += usedOrZero(aggregated.Requests.MemoryUsed)
+	total.Requests.StorageUsed += usedOrZero(aggregated.Requests.StorageUsed)
+	total.Requests.StorageEphemeralUsed += usedOrZero(aggregated.Requests.StorageEphemeralUsed)
+	total.Limits.CPUUsed += usedOrZero(aggregated.Limits.CPUUsed)
+	total.Limits.MemoryUsed += usedOrZero(aggregated.Limits.MemoryUsed)
+	total.Limits.StorageUsed += usedOrZero(aggregated.Limits.StorageUsed)
+	total.Limits.StorageEphemeralUsed += usedOrZero(aggregated.Limits.StorageEphemeralUsed)
 }
 
-func configureCompactTable(t table.Writer) {
+func configureCompactTable(t table.Writer, extendedColumns int, withCluster bool) {
 	applyTableStyle(t)
-	t.SetColumnConfigs([]table.ColumnConfig{
+	configs := []table.ColumnConfig{
 		{Number: compactNamespaceColumn, Align: text.AlignLeft},
 		{Number: compactPodColumn, Align: text.AlignLeft},
 		{Number: compactNodeColumn, Align: text.AlignLeft},
+		{Number: compactQOSColumn, Align: text.AlignLeft},
+		{Number: compactRestartsColumn, Align: text.AlignLeft},
 		{Number: compactFirstMetricColumn, Align: text.AlignRight},
 		{Number: compactSecondMetricCol, Align: text.AlignRight},
 		{Number: compactThirdMetricCol, Align: text.AlignRight},
 		{Number: maxCompactColumns, Align: text.AlignRight},
-	})
+	}
+	for number := maxCompactColumns + 1; number <= maxCompactColumns+extendedColumns; number++ {
+		configs = append(configs, table.ColumnConfig{Number: number, Align: text.AlignRight})
+	}
+	t.SetColumnConfigs(clusterColumnConfigs(withCluster, configs))
 }
 
 func applyTableStyle(t table.Writer) {
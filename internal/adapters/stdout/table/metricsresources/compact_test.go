@@ -2,19 +2,22 @@ package metricsresources
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	servicemetricsresources "github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
 	"github.com/trezorg/k8spodsmetrics/internal/resources"
 	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	v1 "k8s.io/api/core/v1"
 )
 
 func TestCompactHeaderRow(t *testing.T) {
 	row := compactHeaderRow(resources.Resources{resources.All})
-	require.Equal(t, []any{"NAMESPACE", "POD", "NODE", "CPU(req/used/lim)", "MEM(req/used/lim)", "STO(req/used/lim)", "EPH(req/used/lim)"}, []any(row))
+	require.Equal(t, []any{"NAMESPACE", "POD", "NODE", "QOS", "RESTARTS", "CPU(req/used/lim)", "MEM(req/used/lim)", "STO(req/used/lim)", "EPH(req/used/lim)"}, []any(row))
 }
 
 func TestAggregatePodContainers(t *testing.T) {
@@ -37,10 +40,12 @@ func TestCompactPodRow(t *testing.T) {
 	require.Equal(t, "default", row[0])
 	require.Equal(t, "api-server", row[1])
 	require.Equal(t, "node-a", row[2])
-	require.Contains(t, row[3], "300/")
-	require.Contains(t, row[3], "/700")
-	require.Contains(t, row[4], "3.8KiB/")
-	require.Contains(t, row[4], "/6KiB")
+	require.Equal(t, "Burstable", row[3])
+	require.Equal(t, "0", row[4])
+	require.Contains(t, row[5], "300/")
+	require.Contains(t, row[5], "/700")
+	require.Contains(t, row[6], "3.8KiB/")
+	require.Contains(t, row[6], "/6KiB")
 }
 
 func TestPrintCompactToOmitsContainerRows(t *testing.T) {
@@ -65,6 +70,20 @@ func TestPrintCompactToIncludesTotalFooter(t *testing.T) {
 	require.Contains(t, output, "350/310/800")
 }
 
+func TestPrintCompactToClusters(t *testing.T) {
+	us, eu, euDNS := testCompactPodResource(), testSecondCompactPodResource(), testSecondCompactPodResource()
+	us.Cluster, eu.Cluster, euDNS.Cluster = "prod-us", "prod-eu", "prod-eu"
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, servicemetricsresources.PodMetricsResourceList{us, eu, euDNS}, resources.Resources{resources.CPU})
+
+	output := buf.String()
+	require.Regexp(t, `│ CLUSTER +│ NAMESPACE +│ POD +│`, output)
+	require.Regexp(t, `│ prod-us +│ default +│ api-server +│`, output)
+	require.Regexp(t, `│ prod-us +│ SUBTOTAL prod-us +│ +│ +│ +│ +│ +300/270/700 │`, output)
+	require.Regexp(t, `│ prod-eu +│ SUBTOTAL prod-eu +│ +│ +│ +│ +│ +100/80/200 │`, output)
+	require.Contains(t, output, "400/350/900")
+}
+
 func TestPrintCompactToRespectsResources(t *testing.T) {
 	var buf bytes.Buffer
 	PrintCompactTo(&buf, servicemetricsresources.PodMetricsResourceList{testCompactPodResource()}, resources.Resources{resources.Memory})
@@ -94,11 +113,44 @@ func TestPrintCompactToDoesNotTruncateIdentityColumns(t *testing.T) {
 	require.NotContains(t, output, "...")
 }
 
+func TestPrintCompactToExtendedResources(t *testing.T) {
+	first := testCompactPodResource()
+	first.PodResource.Containers[0].Requests.Extended = map[string]int64{"nvidia.com/gpu": 1}
+	first.PodResource.Containers[0].Limits.Extended = map[string]int64{"nvidia.com/gpu": 1}
+	second := testSecondCompactPodResource()
+	second.PodResource.Containers[0].Requests.Extended = map[string]int64{"nvidia.com/gpu": 2}
+	second.PodResource.Containers[0].Limits.Extended = map[string]int64{"nvidia.com/gpu": 2}
+
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, servicemetricsresources.PodMetricsResourceList{first, second}, resources.Resources{resources.CPU, "nvidia.com/gpu"})
+
+	output := buf.String()
+	require.Contains(t, output, "NVIDIA.COM/GPU(REQ/LIM)")
+	require.Contains(t, output, "1/1")
+	require.Contains(t, output, "2/2")
+	require.Contains(t, output, "3/3")
+}
+
+func TestPrintCompactToMetricsUnavailable(t *testing.T) {
+	pod := testCompactPodResource()
+	pod.PodMetric = podmetrics.PodMetric{}
+	pod.MetricsUnavailable = true
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, servicemetricsresources.PodMetricsResourceList{pod}, resources.Resources{resources.CPU, resources.Memory})
+
+	output := buf.String()
+	require.True(t, strings.HasPrefix(output, metricssource.UnavailableWarning+"\n"))
+	require.Contains(t, output, "300/n/a/700")
+	require.NotContains(t, output, "no metrics")
+	require.NotContains(t, output, "without metrics")
+}
+
 func testCompactPodResource() servicemetricsresources.PodMetricsResource {
 	return servicemetricsresources.PodMetricsResource{
 		PodResource: pods.PodResource{
 			NamespaceName: pods.NamespaceName{Name: "api-server", Namespace: "default"},
 			NodeName:      "node-a",
+			QOSClass:      v1.PodQOSBurstable,
 			Containers: []pods.ContainerResource{{
 				Name:     "frontend",
 				Requests: pods.Resource{CPU: 100, Memory: 1024, Storage: 2048, StorageEphemeral: 4096},
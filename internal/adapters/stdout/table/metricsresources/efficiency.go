@@ -0,0 +1,103 @@
+package metricsresources
+
+import (
+	"io"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	formatmetricsresources "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/metricsresources"
+	servicemetricsresources "github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+)
+
+// efficiencyColumns is the number of expanded efficiency columns of a
+// resource: used of request, used of limit and idle.
+const efficiencyColumns = 3
+
+// compactEfficiency appends the CPU and memory efficiency columns to compact
+// rows. When disabled rows are left as they are.
+type compactEfficiency struct {
+	enabled         bool
+	outputResources resources.Resources
+}
+
+func (c compactEfficiency) columns() int {
+	if !c.enabled {
+		return 0
+	}
+	columns := 0
+	if c.outputResources.IsCPU() {
+		columns++
+	}
+	if c.outputResources.IsMemory() {
+		columns++
+	}
+	return columns
+}
+
+func (c compactEfficiency) header(row table.Row) table.Row {
+	return c.append(row, "CPU EFF(req/lim/idle)", "MEM EFF(req/lim/idle)")
+}
+
+func (c compactEfficiency) pod(aggregated servicemetricsresources.ContainerMetricsResource, row table.Row) table.Row {
+	efficiency := formatmetricsresources.NewContainer(aggregated).Efficiency()
+	return c.append(row, efficiency.CPUCompactString(), efficiency.MemoryCompactString())
+}
+
+func (c compactEfficiency) total(waste servicemetricsresources.Waste, row table.Row) table.Row {
+	efficiency := formatmetricsresources.NewTotalEfficiency(waste)
+	return c.append(row, efficiency.CPUCompactString(), efficiency.MemoryCompactString())
+}
+
+func (c compactEfficiency) append(row table.Row, cpu, memory string) table.Row {
+	if !c.enabled {
+		return row
+	}
+	if c.outputResources.IsCPU() {
+		row = append(row, cpu)
+	}
+	if c.outputResources.IsMemory() {
+		row = append(row, memory)
+	}
+	return row
+}
+
+// printWaste writes the idle CPU cores and memory GiB of every namespace
+// under the table, with cluster subtotals when several clusters are queried.
+func printWaste(w io.Writer, list servicemetricsresources.PodMetricsResourceList) {
+	summary := list.WasteSummary()
+	if summary.Total.Pods == 0 {
+		return
+	}
+	withCluster := len(summary.Clusters) > 0
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	applyTableStyle(t)
+	t.SetColumnConfigs(clusterColumnConfigs(withCluster, []table.ColumnConfig{
+		{Number: 1, Align: text.AlignLeft},
+		{Number: 2, Align: text.AlignRight},
+		{Number: 3, Align: text.AlignRight},
+		{Number: 4, Align: text.AlignRight},
+	}))
+	t.AppendHeader(clusterRow(withCluster, "CLUSTER", table.Row{"NAMESPACE", "PODS", "IDLE CPU(cores)", "IDLE MEM(GiB)"}))
+	for _, waste := range summary.Namespaces {
+		t.AppendRow(clusterRow(withCluster, waste.Cluster, wasteRow(waste.Namespace, waste)))
+	}
+	if withCluster {
+		t.AppendSeparator()
+		for _, waste := range summary.Clusters {
+			t.AppendRow(clusterRow(withCluster, waste.Cluster, wasteRow("SUBTOTAL "+waste.Cluster, waste)))
+		}
+	}
+	t.AppendFooter(clusterRow(withCluster, "", wasteRow("TOTAL", summary.Total)))
+	t.Render()
+}
+
+func wasteRow(name string, waste servicemetricsresources.Waste) table.Row {
+	return table.Row{
+		name,
+		waste.Pods,
+		formatmetricsresources.CoresString(waste.CPUIdle),
+		formatmetricsresources.GiBString(waste.MemoryIdle),
+	}
+}
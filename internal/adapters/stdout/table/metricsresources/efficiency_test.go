@@ -0,0 +1,102 @@
+package metricsresources
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/stretchr/testify/require"
+
+	servicemetricsresources "github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+)
+
+func TestCompactEfficiencyAppend(t *testing.T) {
+	row := table.Row{"ns", "pod"}
+	tests := []struct {
+		name       string
+		efficiency compactEfficiency
+		want       table.Row
+	}{
+		{
+			name:       "cpu and memory",
+			efficiency: compactEfficiency{enabled: true, outputResources: resources.Resources{resources.All}},
+			want:       table.Row{"ns", "pod", "c", "m"},
+		},
+		{
+			name:       "memory only",
+			efficiency: compactEfficiency{enabled: true, outputResources: resources.Resources{resources.Memory}},
+			want:       table.Row{"ns", "pod", "m"},
+		},
+		{
+			name:       "disabled",
+			efficiency: compactEfficiency{outputResources: resources.Resources{resources.All}},
+			want:       row,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.efficiency.append(append(table.Row{}, row...), "c", "m"))
+		})
+	}
+}
+
+func TestToCompactWriterEfficiency(t *testing.T) {
+	var buf bytes.Buffer
+	list := servicemetricsresources.PodMetricsResourceList{testCompactPodResource(), testSecondCompactPodResource()}
+	ToCompactWriter(resources.Resources{resources.CPU, resources.Memory}, true)(&buf, list, nil)
+
+	output := buf.String()
+	require.Contains(t, output, "CPU EFF(REQ/LIM/IDLE)")
+	require.Contains(t, output, "MEM EFF(REQ/LIM/IDLE)")
+	require.Contains(t, output, "90%/38.6%/30")
+	require.Contains(t, output, "80%/40%/10")
+	require.Contains(t, output, "88.6%/38.8%/40", "totals are efficiencies of the sums")
+	require.Contains(t, output, "IDLE CPU(CORES)")
+	require.Regexp(t, `default +│ +1 │ +0.03 │ +0.00`, output)
+	require.Regexp(t, `TOTAL +│ +2 │ 0.04 +│`, output)
+}
+
+func TestPrintCompactToHasNoEfficiency(t *testing.T) {
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, servicemetricsresources.PodMetricsResourceList{testCompactPodResource()}, resources.Resources{resources.CPU})
+
+	require.NotContains(t, buf.String(), "EFF")
+	require.NotContains(t, buf.String(), "IDLE")
+}
+
+func TestToWriterEfficiency(t *testing.T) {
+	var buf bytes.Buffer
+	ToWriter(resources.Resources{resources.CPU}, nil, true)(&buf, servicemetricsresources.PodMetricsResourceList{testCompactPodResource()}, nil)
+
+	output := buf.String()
+	require.Contains(t, output, "CPU USED/REQUEST")
+	require.Contains(t, output, "CPU USED/LIMIT")
+	require.Contains(t, output, "CPU IDLE")
+	require.NotContains(t, output, "MEMORY IDLE")
+	require.Regexp(t, `120% │ +60% │ +0 │`, output, "frontend uses more than it requests")
+	require.Regexp(t, `150 │ +75% │ +30% │ +50 │`, output)
+	require.Contains(t, output, "IDLE MEM(GIB)")
+}
+
+func TestPrintWasteClusters(t *testing.T) {
+	us, eu := testCompactPodResource(), testSecondCompactPodResource()
+	us.Cluster, eu.Cluster = "prod-us", "prod-eu"
+
+	var buf bytes.Buffer
+	printWaste(&buf, servicemetricsresources.PodMetricsResourceList{us, eu})
+
+	output := buf.String()
+	require.Regexp(t, `prod-us +│ default +│ +1 │ +0.03`, output)
+	require.Contains(t, output, "SUBTOTAL prod-eu")
+	require.Regexp(t, `TOTAL +│ +2 │ 0.04`, output)
+}
+
+func TestPrintWasteWithoutUsage(t *testing.T) {
+	var buf bytes.Buffer
+	pod := testCompactPodResource()
+	pod.MetricsUnavailable = true
+	printWaste(&buf, servicemetricsresources.PodMetricsResourceList{pod})
+
+	require.Empty(t, buf.String())
+}
@@ -1,8 +1,11 @@
 package metricsresources
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"strings"
+	"time"
 
 	"log/slog"
 
@@ -11,23 +14,30 @@ import (
 	formatmetricsresources "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/metricsresources"
 	"github.com/trezorg/k8spodsmetrics/internal/columns"
 	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
 	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 )
 
 const (
 	expandedPodColumnContainer = 1
 	expandedPodColumnNamespace = 2
 	expandedPodColumnNode      = 3
-	expandedPodFirstMetricCol  = 4
-	expandedPodMaxMetricCol    = 15
+	expandedPodColumnQOS       = 4
+	expandedPodColumnRestarts  = 5
+	expandedPodFirstMetricCol  = 6
+	expandedPodMaxMetricCol    = 17
 )
 
-type Table func(list metricsresources.PodMetricsResourceList)
+type Table func(list metricsresources.PodMetricsResourceList, warnings partial.Warnings)
 
 type ColumnSet struct {
 	Request bool
 	Limit   bool
 	Used    bool
+	// Efficiency adds CPU and memory usage relative to requests and limits
+	// and the idle requested amount.
+	Efficiency bool
 }
 
 func newColumnSet(cols []columns.Column) ColumnSet {
@@ -61,9 +71,26 @@ func (cs ColumnSet) appendResourceHeaderRow(result table.Row, label string) tabl
 	if cs.Used {
 		result = append(result, label+" Used")
 	}
+	if cs.Efficiency {
+		result = append(result, label+" Used/Request", label+" Used/Limit", label+" Idle")
+	}
 	return result
 }
 
+func (cs ColumnSet) efficiencyColumnsCount(outputResources resources.Resources) int {
+	if !cs.Efficiency {
+		return 0
+	}
+	count := 0
+	if outputResources.IsCPU() {
+		count += efficiencyColumns
+	}
+	if outputResources.IsMemory() {
+		count += efficiencyColumns
+	}
+	return count
+}
+
 func (cs ColumnSet) appendStorageHeaderRow(result table.Row) table.Row {
 	if cs.Request {
 		result = append(result, "Storage Request")
@@ -87,9 +114,9 @@ func (cs ColumnSet) appendStorageHeaderRow(result table.Row) table.Row {
 }
 
 func (cs ColumnSet) headerFooterRow(outputResources resources.Resources, columnNames ...string) table.Row {
-	maxColumsNames := 3
+	maxColumsNames := 5
 	if len(columnNames) > maxColumsNames {
-		columnNames = columnNames[:3]
+		columnNames = columnNames[:maxColumsNames]
 	}
 	result := table.Row{}
 	for _, column := range columnNames {
@@ -107,6 +134,49 @@ func (cs ColumnSet) headerFooterRow(outputResources resources.Resources, columnN
 	if outputResources.IsStorage() {
 		result = cs.appendStorageHeaderRow(result)
 	}
+	for _, name := range outputResources.Extended() {
+		result = cs.appendExtendedHeaderRow(result, name)
+	}
+	return result
+}
+
+// appendExtendedHeaderRow adds request and limit columns of an extended
+// resource. Metrics server reports no usage for them.
+func (cs ColumnSet) appendExtendedHeaderRow(result table.Row, name string) table.Row {
+	if cs.Request {
+		result = append(result, name+" Request")
+	}
+	if cs.Limit {
+		result = append(result, name+" Limit")
+	}
+	return result
+}
+
+func (cs ColumnSet) extendedColumnsCount(outputResources resources.Resources) int {
+	perResource := 0
+	if cs.Request {
+		perResource++
+	}
+	if cs.Limit {
+		perResource++
+	}
+	return perResource * len(outputResources.Extended())
+}
+
+func (cs ColumnSet) appendExtendedColumns(
+	result table.Row,
+	resource metricsresources.ContainerMetricsResource,
+	outputResources resources.Resources,
+) table.Row {
+	containerFormatter := formatmetricsresources.NewContainer(resource)
+	for _, name := range outputResources.Extended() {
+		if cs.Request {
+			result = append(result, containerFormatter.Requests().ExtendedString(name))
+		}
+		if cs.Limit {
+			result = append(result, containerFormatter.Limits().ExtendedString(name))
+		}
+	}
 	return result
 }
 
@@ -121,6 +191,10 @@ func (cs ColumnSet) appendCPUColumns(result table.Row, container metricsresource
 	if cs.Used {
 		result = append(result, containerFormatter.CPUUsed())
 	}
+	if cs.Efficiency {
+		efficiency := containerFormatter.Efficiency()
+		result = append(result, efficiency.CPURequestPercent(), efficiency.CPULimitPercent(), efficiency.CPUIdle())
+	}
 	return result
 }
 
@@ -135,6 +209,10 @@ func (cs ColumnSet) appendMemoryColumns(result table.Row, container metricsresou
 	if cs.Used {
 		result = append(result, containerFormatter.MemoryUsed())
 	}
+	if cs.Efficiency {
+		efficiency := containerFormatter.Efficiency()
+		result = append(result, efficiency.MemoryRequestPercent(), efficiency.MemoryLimitPercent(), efficiency.MemoryIdle())
+	}
 	return result
 }
 
@@ -162,49 +240,36 @@ func (cs ColumnSet) appendStorageColumns(result table.Row, container metricsreso
 }
 
 func (cs ColumnSet) dataRow(resource metricsresources.PodMetricsResource, outputResources resources.Resources) table.Row {
-	result := table.Row{resource.PodResource.Name, resource.PodResource.Namespace, resource.NodeName}
-	containers := resource.ContainersMetrics()
-	if len(containers) == 0 {
+	pod := resource.PodMetrics()
+	result := table.Row{
+		formatmetricsresources.PodName(resource),
+		resource.PodResource.Namespace,
+		resource.NodeName,
+		string(resource.QOSClass),
+		formatmetricsresources.NewContainer(pod).RestartsString(resource.ObservedAt()),
+	}
+	if len(resource.ContainersMetrics()) == 0 {
 		return result
 	}
-	container := containers[0]
 
 	if outputResources.IsCPU() {
-		for _, cn := range containers[1:] {
-			container.Requests.CPURequest += cn.Requests.CPURequest
-			container.Limits.CPURequest += cn.Limits.CPURequest
-			container.Requests.CPUUsed += cn.Requests.CPUUsed
-			container.Limits.CPUUsed += cn.Limits.CPUUsed
-		}
-		result = cs.appendCPUColumns(result, container)
+		result = cs.appendCPUColumns(result, pod)
 	}
 	if outputResources.IsMemory() {
-		for _, cn := range containers[1:] {
-			container.Requests.MemoryRequest += cn.Requests.MemoryRequest
-			container.Limits.MemoryRequest += cn.Limits.MemoryRequest
-			container.Requests.MemoryUsed += cn.Requests.MemoryUsed
-			container.Limits.MemoryUsed += cn.Limits.MemoryUsed
-		}
-		result = cs.appendMemoryColumns(result, container)
+		result = cs.appendMemoryColumns(result, pod)
 	}
 	if outputResources.IsStorage() {
-		for _, cn := range containers[1:] {
-			container.Requests.StorageRequest += cn.Requests.StorageRequest
-			container.Limits.StorageRequest += cn.Limits.StorageRequest
-			container.Requests.StorageUsed += cn.Requests.StorageUsed
-			container.Limits.StorageUsed += cn.Limits.StorageUsed
-			container.Requests.StorageEphemeralRequest += cn.Requests.StorageEphemeralRequest
-			container.Limits.StorageEphemeralRequest += cn.Limits.StorageEphemeralRequest
-			container.Requests.StorageEphemeralUsed += cn.Requests.StorageEphemeralUsed
-			container.Limits.StorageEphemeralUsed += cn.Limits.StorageEphemeralUsed
-		}
-		result = cs.appendStorageColumns(result, container)
+		result = cs.appendStorageColumns(result, pod)
 	}
-	return result
+	return cs.appendExtendedColumns(result, pod, outputResources)
 }
 
-func (cs ColumnSet) containerRow(container metricsresources.ContainerMetricsResource, outputResources resources.Resources) table.Row {
-	result := table.Row{"└─ " + container.Name, "", ""}
+func (cs ColumnSet) containerRow(
+	container metricsresources.ContainerMetricsResource,
+	now time.Time,
+	outputResources resources.Resources,
+) table.Row {
+	result := table.Row{"└─ " + containerLabel(container), "", "", "", formatmetricsresources.NewContainer(container).RestartsString(now)}
 
 	if outputResources.IsCPU() {
 		result = cs.appendCPUColumns(result, container)
@@ -215,11 +280,18 @@ func (cs ColumnSet) containerRow(container metricsresources.ContainerMetricsReso
 	if outputResources.IsStorage() {
 		result = cs.appendStorageColumns(result, container)
 	}
-	return result
+	return cs.appendExtendedColumns(result, container, outputResources)
 }
 
-func (cs ColumnSet) totalRow(outputResources resources.Resources, total metricsresources.ContainerMetricsResource) table.Row {
-	totalRow := table.Row{"", "", ""}
+// totalRow sums the pods. Idle cells hold the waste of the pods rather than
+// the requests left over the summed usage.
+func (cs ColumnSet) totalRow(
+	outputResources resources.Resources,
+	total metricsresources.ContainerMetricsResource,
+	waste metricsresources.Waste,
+) table.Row {
+	totalRow := table.Row{"", "", "", "", ""}
+	efficiency := formatmetricsresources.NewTotalEfficiency(waste)
 	if outputResources.IsCPU() {
 		if cs.Request {
 			totalRow = append(totalRow, formatmetricsresources.NewMetrics(total.Requests).CPURequestString())
@@ -230,6 +302,9 @@ func (cs ColumnSet) totalRow(outputResources resources.Resources, total metricsr
 		if cs.Used {
 			totalRow = append(totalRow, formatmetricsresources.NewMetrics(total.Requests).CPUUsedString(""))
 		}
+		if cs.Efficiency {
+			totalRow = append(totalRow, efficiency.CPURequestPercent(), efficiency.CPULimitPercent(), efficiency.CPUIdle())
+		}
 	}
 	if outputResources.IsMemory() {
 		if cs.Request {
@@ -241,6 +316,9 @@ func (cs ColumnSet) totalRow(outputResources resources.Resources, total metricsr
 		if cs.Used {
 			totalRow = append(totalRow, formatmetricsresources.NewMetrics(total.Requests).MemoryUsedString(""))
 		}
+		if cs.Efficiency {
+			totalRow = append(totalRow, efficiency.MemoryRequestPercent(), efficiency.MemoryLimitPercent(), efficiency.MemoryIdle())
+		}
 	}
 	if outputResources.IsStorage() {
 		if cs.Request {
@@ -262,15 +340,57 @@ func (cs ColumnSet) totalRow(outputResources resources.Resources, total metricsr
 			totalRow = append(totalRow, formatmetricsresources.NewMetrics(total.Requests).StorageEphemeralString())
 		}
 	}
-	return totalRow
+	return cs.appendExtendedColumns(totalRow, total, outputResources)
+}
+
+func containerLabel(container metricsresources.ContainerMetricsResource) string {
+	var notes []string
+	if container.Type != "" {
+		notes = append(notes, string(container.Type))
+	}
+	// Running is the expected state and left out to keep the column narrow.
+	if container.State != "" && container.State != "Running" {
+		notes = append(notes, container.State)
+	}
+	if container.MetricsMissing {
+		notes = append(notes, "no metrics")
+	}
+	if container.SpecMissing {
+		notes = append(notes, "not in spec")
+	}
+	if len(notes) == 0 {
+		return container.Name
+	}
+	return fmt.Sprintf("%s (%s)", container.Name, strings.Join(notes, ", "))
+}
+
+// addExtended adds extended resource values into total, allocating it when needed.
+func addExtended(total, values map[string]int64) map[string]int64 {
+	for name, value := range values {
+		if total == nil {
+			total = make(map[string]int64, len(values))
+		}
+		total[name] += value
+	}
+	return total
+}
+
+func usedOrZero(value int64) int64 {
+	if value < 0 {
+		return 0
+	}
+	return value
 }
 
 func ToTable(
 	outputResources resources.Resources,
 	cols []columns.Column,
+	efficiency bool,
 ) Table {
 	cs := newColumnSet(cols)
-	return Table(func(list metricsresources.PodMetricsResourceList) {
+	cs.Efficiency = efficiency
+	return Table(func(list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+		printWarnings(os.Stdout, warnings)
 		PrintTo(os.Stdout, list, outputResources, cs)
 	})
 }
@@ -278,9 +398,12 @@ func ToTable(
 func ToWriter(
 	outputResources resources.Resources,
 	cols []columns.Column,
-) func(io.Writer, metricsresources.PodMetricsResourceList) {
+	efficiency bool,
+) func(io.Writer, metricsresources.PodMetricsResourceList, partial.Warnings) {
 	cs := newColumnSet(cols)
-	return func(w io.Writer, list metricsresources.PodMetricsResourceList) {
+	cs.Efficiency = efficiency
+	return func(w io.Writer, list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+		printWarnings(w, warnings)
 		PrintTo(w, list, outputResources, cs)
 	}
 }
@@ -299,58 +422,86 @@ func PrintTo(
 	outputResources resources.Resources,
 	cs ColumnSet,
 ) {
+	printMetricsUnavailable(w, list)
+	groups, withCluster := clusterGroups(list)
 	t := table.NewWriter()
 	t.SetOutputMirror(w)
-	configureExpandedTable(t)
-	t.AppendHeader(cs.headerFooterRow(outputResources, "Pod/Container", "Namespace", "Node"))
+	configureExpandedTable(t, cs.extendedColumnsCount(outputResources)+cs.efficiencyColumnsCount(outputResources), withCluster)
+	header := cs.headerFooterRow(outputResources, "Pod/Container", "Namespace", "Node", "QoS", "Restarts")
+	t.AppendHeader(clusterRow(withCluster, "Cluster", header))
 
 	total := metricsresources.ContainerMetricsResource{}
-
-	for _, resource := range list {
-		containers := resource.ContainersMetrics()
-		if len(containers) == 0 {
-			continue
+	totalWaste := metricsresources.Waste{}
+
+	for _, group := range groups {
+		subtotal := metricsresources.ContainerMetricsResource{}
+		subtotalWaste := metricsresources.Waste{}
+		for _, resource := range group {
+			containers := resource.ContainersMetrics()
+			if len(containers) == 0 {
+				continue
+			}
+
+			t.AppendRow(clusterRow(withCluster, resource.Cluster, cs.dataRow(resource, outputResources)))
+
+			for _, container := range containers {
+				t.AppendRow(clusterRow(withCluster, "", cs.containerRow(container, resource.ObservedAt(), outputResources)))
+			}
+
+			t.AppendSeparator()
+
+			pod := resource.PodMetrics()
+			accumulatePodTotal(&subtotal, pod)
+			accumulatePodTotal(&total, pod)
+			subtotalWaste.Add(resource)
+			totalWaste.Add(resource)
 		}
-
-		t.AppendRow(cs.dataRow(resource, outputResources))
-
-		for _, container := range containers {
-			t.AppendRow(cs.containerRow(container, outputResources))
+		if withCluster {
+			row := cs.totalRow(outputResources, subtotal, subtotalWaste)
+			row[0] = "Subtotal " + group[0].Cluster
+			t.AppendRow(clusterRow(withCluster, group[0].Cluster, row))
+			t.AppendSeparator()
 		}
+	}
 
-		t.AppendSeparator()
-
-		// Calculate totals
-		for _, container := range containers {
-			total.Requests.CPURequest += container.Requests.CPURequest
-			total.Requests.MemoryRequest += container.Requests.MemoryRequest
-			total.Requests.StorageRequest += container.Requests.StorageRequest
-			total.Requests.StorageEphemeralRequest += container.Requests.StorageEphemeralRequest
+	t.AppendRow(clusterRow(withCluster, "", cs.headerFooterRow(outputResources, "Total")))
+	t.AppendSeparator()
+	t.AppendFooter(clusterRow(withCluster, "", cs.totalRow(outputResources, total, totalWaste)))
+	t.Render()
+	printMissingMetrics(w, list)
+	if cs.Efficiency {
+		printWaste(w, list)
+	}
+}
 
-			total.Limits.CPURequest += container.Limits.CPURequest
-			total.Limits.MemoryRequest += container.Limits.MemoryRequest
-			total.Limits.StorageRequest += container.Limits.StorageRequest
-			total.Limits.StorageEphemeralRequest += container.Limits.StorageEphemeralRequest
+// printWarnings writes the banner of partial results above the table.
+func printWarnings(w io.Writer, warnings partial.Warnings) {
+	if banner := warnings.String(); banner != "" {
+		_, _ = fmt.Fprintln(w, banner)
+	}
+}
 
-			total.Requests.CPUUsed += container.Requests.CPUUsed
-			total.Requests.MemoryUsed += container.Requests.MemoryUsed
-			total.Requests.StorageUsed += container.Requests.StorageUsed
-			total.Requests.StorageEphemeralUsed += container.Requests.StorageEphemeralUsed
-		}
+// printMetricsUnavailable writes the warning of pods listed without usage
+// above the table.
+func printMetricsUnavailable(w io.Writer, list metricsresources.PodMetricsResourceList) {
+	if list.MetricsUnavailable() {
+		_, _ = fmt.Fprintln(w, metricssource.UnavailableWarning)
 	}
+}
 
-	t.AppendRow(cs.headerFooterRow(outputResources, "Total"))
-	t.AppendSeparator()
-	t.AppendFooter(cs.totalRow(outputResources, total))
-	t.Render()
+// printMissingMetrics writes the summary of pods without metrics under the table.
+func printMissingMetrics(w io.Writer, list metricsresources.PodMetricsResourceList) {
+	if missing := list.MissingMetrics().String(); missing != "" {
+		_, _ = fmt.Fprintln(w, missing)
+	}
 }
 
-func configureExpandedTable(t table.Writer) {
+func configureExpandedTable(t table.Writer, extendedColumns int, withCluster bool) {
 	applyTableStyle(t)
-	t.SetColumnConfigs(expandedColumnConfigs())
+	t.SetColumnConfigs(clusterColumnConfigs(withCluster, expandedColumnConfigs(extendedColumns)))
 }
 
-func expandedColumnConfigs() []table.ColumnConfig {
+func expandedColumnConfigs(extendedColumns int) []table.ColumnConfig {
 	configs := []table.ColumnConfig{
 		{
 			Number:      expandedPodColumnContainer,
@@ -370,8 +521,20 @@ func expandedColumnConfigs() []table.ColumnConfig {
 			AlignHeader: text.AlignLeft,
 			AlignFooter: text.AlignLeft,
 		},
+		{
+			Number:      expandedPodColumnQOS,
+			Align:       text.AlignLeft,
+			AlignHeader: text.AlignLeft,
+			AlignFooter: text.AlignLeft,
+		},
+		{
+			Number:      expandedPodColumnRestarts,
+			Align:       text.AlignLeft,
+			AlignHeader: text.AlignLeft,
+			AlignFooter: text.AlignLeft,
+		},
 	}
-	for number := expandedPodFirstMetricCol; number <= expandedPodMaxMetricCol; number++ {
+	for number := expandedPodFirstMetricCol; number <= expandedPodMaxMetricCol+extendedColumns; number++ {
 		configs = append(configs, table.ColumnConfig{
 			Number:      number,
 			Align:       text.AlignRight,
@@ -382,8 +545,8 @@ func expandedColumnConfigs() []table.ColumnConfig {
 	return configs
 }
 
-func (s Table) Success(list metricsresources.PodMetricsResourceList) {
-	s(list)
+func (s Table) Success(list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	s(list, warnings)
 }
 
 func (Table) Error(err error) {
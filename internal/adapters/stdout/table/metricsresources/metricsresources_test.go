@@ -2,9 +2,11 @@ package metricsresources
 
 import (
 	"bytes"
+	"io"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -12,8 +14,10 @@ import (
 	"github.com/trezorg/k8spodsmetrics/internal/columns"
 	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
 	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	v1 "k8s.io/api/core/v1"
 )
 
 func TestHeaderFooter(t *testing.T) {
@@ -21,35 +25,39 @@ func TestHeaderFooter(t *testing.T) {
 		outputResources := resources.Resources{resources.CPU}
 		cs := newColumnSet(nil)
 		result := cs.headerFooterRow(outputResources, "Test")
-		require.Len(t, result, 6)
+		require.Len(t, result, 8)
 		require.Equal(t, "Test", result[0])
 		require.Equal(t, "", result[1])
 		require.Equal(t, "", result[2])
-		require.Equal(t, "CPU Request", result[3])
-		require.Equal(t, "CPU Limit", result[4])
-		require.Equal(t, "CPU Used", result[5])
+		require.Equal(t, "", result[3])
+		require.Equal(t, "", result[4])
+		require.Equal(t, "CPU Request", result[5])
+		require.Equal(t, "CPU Limit", result[6])
+		require.Equal(t, "CPU Used", result[7])
 	})
 
 	t.Run("with Memory only", func(t *testing.T) {
 		outputResources := resources.Resources{resources.Memory}
 		cs := newColumnSet(nil)
 		result := cs.headerFooterRow(outputResources, "Test")
-		require.Len(t, result, 6)
+		require.Len(t, result, 8)
 		require.Equal(t, "Test", result[0])
 		require.Equal(t, "", result[1])
 		require.Equal(t, "", result[2])
-		require.Equal(t, "Memory Request", result[3])
-		require.Equal(t, "Memory Limit", result[4])
-		require.Equal(t, "Memory Used", result[5])
+		require.Equal(t, "", result[3])
+		require.Equal(t, "", result[4])
+		require.Equal(t, "Memory Request", result[5])
+		require.Equal(t, "Memory Limit", result[6])
+		require.Equal(t, "Memory Used", result[7])
 	})
 
 	t.Run("with all resources", func(t *testing.T) {
 		outputResources := resources.Resources{resources.All}
 		cs := newColumnSet(nil)
 		result := cs.headerFooterRow(outputResources, "Test")
-		require.Len(t, result, 15)
+		require.Len(t, result, 17)
 		require.Equal(t, "Test", result[0])
-		require.Equal(t, "CPU Request", result[3])
+		require.Equal(t, "CPU Request", result[5])
 	})
 }
 
@@ -67,8 +75,8 @@ func TestContainerRow(t *testing.T) {
 				CPUUsed:    1500,
 			},
 		}
-		result := cs.containerRow(container, outputResources)
-		require.Len(t, result, 6)
+		result := cs.containerRow(container, time.Now(), outputResources)
+		require.Len(t, result, 8)
 		require.Equal(t, "└─ container-1", result[0])
 	})
 
@@ -85,10 +93,55 @@ func TestContainerRow(t *testing.T) {
 				MemoryUsed:    1024 * 1024 * 150,
 			},
 		}
-		result := cs.containerRow(container, outputResources)
-		require.Len(t, result, 6)
+		result := cs.containerRow(container, time.Now(), outputResources)
+		require.Len(t, result, 8)
 		require.Equal(t, "└─ container-1", result[0])
 	})
+
+	t.Run("with sidecar container", func(t *testing.T) {
+		outputResources := resources.Resources{resources.CPU}
+		cs := newColumnSet(nil)
+		container := metricsresources.ContainerMetricsResource{
+			Name: "istio-proxy",
+			Type: pods.SidecarContainer,
+		}
+		result := cs.containerRow(container, time.Now(), outputResources)
+		require.Equal(t, "└─ istio-proxy (sidecar)", result[0])
+	})
+
+	t.Run("with missing metrics and missing spec markers", func(t *testing.T) {
+		outputResources := resources.Resources{resources.CPU}
+		cs := newColumnSet(nil)
+		result := cs.containerRow(metricsresources.ContainerMetricsResource{
+			Name:           "istio-proxy",
+			Type:           pods.SidecarContainer,
+			MetricsMissing: true,
+		}, time.Now(), outputResources)
+		require.Equal(t, "└─ istio-proxy (sidecar, no metrics)", result[0])
+
+		result = cs.containerRow(metricsresources.ContainerMetricsResource{
+			Name:        "debugger",
+			SpecMissing: true,
+		}, time.Now(), outputResources)
+		require.Equal(t, "└─ debugger (not in spec)", result[0])
+	})
+
+	t.Run("with restarts and waiting state", func(t *testing.T) {
+		outputResources := resources.Resources{resources.CPU}
+		cs := newColumnSet(nil)
+		result := cs.containerRow(metricsresources.ContainerMetricsResource{
+			Name:            "app",
+			Restarts:        4,
+			State:           "CrashLoopBackOff",
+			LastTermination: &pods.Termination{Reason: pods.OOMKilled, ExitCode: 137},
+		}, time.Now(), outputResources)
+		require.Equal(t, "└─ app (CrashLoopBackOff)", result[0])
+		require.Equal(t, "4 (OOMKilled)", result[4])
+
+		result = cs.containerRow(metricsresources.ContainerMetricsResource{Name: "app", State: "Running"}, time.Now(), outputResources)
+		require.Equal(t, "└─ app", result[0])
+		require.Equal(t, "0", result[4])
+	})
 }
 
 func TestRow(t *testing.T) {
@@ -102,6 +155,7 @@ func TestRow(t *testing.T) {
 					Namespace: "default",
 				},
 				NodeName: "node-1",
+				QOSClass: v1.PodQOSBurstable,
 				Containers: []pods.ContainerResource{
 					{
 						Name: "container-1",
@@ -128,10 +182,11 @@ func TestRow(t *testing.T) {
 			},
 		}
 		result := cs.dataRow(resource, outputResources)
-		require.Len(t, result, 6)
+		require.Len(t, result, 8)
 		require.Equal(t, "test-pod", result[0])
 		require.Equal(t, "default", result[1])
 		require.Equal(t, "node-1", result[2])
+		require.Equal(t, "Burstable", result[3])
 	})
 
 	t.Run("uses pod resource identity when pod metric identity is empty", func(t *testing.T) {
@@ -152,7 +207,7 @@ func TestRow(t *testing.T) {
 		}
 
 		result := cs.dataRow(resource, outputResources)
-		require.Equal(t, "system-kube-state-metrics-7d4fb49747-7n7b9", result[0])
+		require.Equal(t, "system-kube-state-metrics-7d4fb49747-7n7b9 (no metrics)", result[0])
 		require.Equal(t, "kube-system", result[1])
 		require.Equal(t, "pool-dev-54704", result[2])
 	})
@@ -161,23 +216,23 @@ func TestRow(t *testing.T) {
 func TestToTable(t *testing.T) {
 	t.Run("creates table function with empty columns", func(t *testing.T) {
 		outputResources := resources.Resources{resources.CPU, resources.Memory}
-		tableFunc := ToTable(outputResources, nil)
+		tableFunc := ToTable(outputResources, nil, false)
 		require.NotNil(t, tableFunc)
 
 		list := metricsresources.PodMetricsResourceList{}
 		require.NotPanics(t, func() {
-			tableFunc(list)
+			tableFunc(list, nil)
 		})
 	})
 
 	t.Run("creates table function with filtered columns", func(t *testing.T) {
 		outputResources := resources.Resources{resources.CPU, resources.Memory}
-		tableFunc := ToTable(outputResources, []columns.Column{columns.Used})
+		tableFunc := ToTable(outputResources, []columns.Column{columns.Used}, false)
 		require.NotNil(t, tableFunc)
 
 		list := metricsresources.PodMetricsResourceList{}
 		require.NotPanics(t, func() {
-			tableFunc(list)
+			tableFunc(list, nil)
 		})
 	})
 }
@@ -185,19 +240,19 @@ func TestToTable(t *testing.T) {
 func TestTable_Success(t *testing.T) {
 	t.Run("calls table function", func(t *testing.T) {
 		outputResources := resources.Resources{resources.CPU, resources.Memory}
-		tableFunc := ToTable(outputResources, nil)
+		tableFunc := ToTable(outputResources, nil, false)
 		require.NotNil(t, tableFunc)
 
 		list := metricsresources.PodMetricsResourceList{}
 		require.NotPanics(t, func() {
-			tableFunc.Success(list)
+			tableFunc.Success(list, nil)
 		})
 	})
 }
 
 func TestTable_Error(t *testing.T) {
 	t.Run("logs error without panicking", func(t *testing.T) {
-		tableFunc := ToTable(resources.Resources{}, nil)
+		tableFunc := ToTable(resources.Resources{}, nil, false)
 		require.NotPanics(t, func() {
 			tableFunc.Error(nil)
 		})
@@ -265,20 +320,80 @@ func TestPrintToExpandedSingleHeaderAndTotalFooter(t *testing.T) {
 	require.Equal(t, 1, strings.Count(cleanOutput, "CPU REQUEST"))
 	require.Equal(t, 1, strings.Count(cleanOutput, "CPU LIMIT"))
 	require.Equal(t, 1, strings.Count(cleanOutput, "CPU USED"))
-	require.Regexp(t, regexp.MustCompile(`(?s)│ Total         │           │      │ CPU Request │ CPU Limit │ CPU Used │\n├[-┼┤├─]+\n│               │           │      │           0 │         0 │        0 │`), cleanOutput)
+	require.Regexp(t, regexp.MustCompile(`(?s)│ Total         │           │      │     │          │ CPU Request │ CPU Limit │ CPU Used │\n├[-┼┤├─]+\n│               │           │      │     │          │           0 │         0 │        0 │`), cleanOutput)
+}
+
+func TestToWriterPartialResultsBanner(t *testing.T) {
+	warnings := partial.Warnings{{Scope: partial.Namespace, Name: "team-b", Message: "forbidden"}}
+	for name, writer := range map[string]func(io.Writer, metricsresources.PodMetricsResourceList, partial.Warnings){
+		"expanded": ToWriter(resources.Resources{resources.CPU}, nil, false),
+		"compact":  ToCompactWriter(resources.Resources{resources.CPU}, false),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer(&buf, metricsresources.PodMetricsResourceList{}, warnings)
+			require.True(t, strings.HasPrefix(buf.String(), "WARNING: partial results, 1 scope failed:\n  namespace team-b: forbidden\n"))
+
+			buf.Reset()
+			writer(&buf, metricsresources.PodMetricsResourceList{}, nil)
+			require.NotContains(t, buf.String(), "WARNING")
+		})
+	}
+}
+
+func TestPrintToExpandedClusters(t *testing.T) {
+	us, eu := testCompactPodResource(), testSecondCompactPodResource()
+	us.Cluster, eu.Cluster = "prod-us", "prod-eu"
+	var buf bytes.Buffer
+	PrintTo(&buf, metricsresources.PodMetricsResourceList{us, eu}, resources.Resources{resources.CPU}, ColumnSet{Request: true})
+
+	output := regexp.MustCompile(`\x1b\[[0-9;]*m`).ReplaceAllString(buf.String(), "")
+	require.Regexp(t, `│ CLUSTER +│ POD/CONTAINER +│ NAMESPACE +│`, output)
+	require.Regexp(t, `│ prod-us +│ api-server +│ default +│`, output)
+	require.Regexp(t, `│ prod-us +│ Subtotal prod-us +│ +│ +│ +│ +│ +300 │`, output)
+	require.Regexp(t, `│ prod-eu +│ Subtotal prod-eu +│ +│ +│ +│ +│ +50 │`, output)
+	require.Regexp(t, `│ +│ +│ +│ +│ +│ +│ +350 │`, output)
+}
+
+func TestPrintToExpandedExtendedResources(t *testing.T) {
+	resource := metricsresources.PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Name: "trainer", Namespace: "ml"},
+			Containers: []pods.ContainerResource{{
+				Name:     "cuda",
+				Requests: pods.Resource{Extended: map[string]int64{"nvidia.com/gpu": 2}},
+				Limits:   pods.Resource{Extended: map[string]int64{"nvidia.com/gpu": 2}},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, metricsresources.PodMetricsResourceList{resource}, resources.Resources{"nvidia.com/gpu"}, ColumnSet{Request: true, Limit: true, Used: true})
+
+	output := buf.String()
+	require.Contains(t, output, "NVIDIA.COM/GPU REQUEST")
+	require.Contains(t, output, "NVIDIA.COM/GPU LIMIT")
+	require.NotContains(t, output, "NVIDIA.COM/GPU USED")
+	require.Regexp(t, regexp.MustCompile(`│ trainer +│ ml +│ +│ +│ 0 +│ +2 │ +2 │`), output)
+}
+
+func TestExpandedColumnConfigsExtended(t *testing.T) {
+	configs := expandedColumnConfigs(2)
+	require.Len(t, configs, expandedPodMaxMetricCol+2)
+	require.Equal(t, text.AlignRight, configs[len(configs)-1].Align)
 }
 
 func TestExpandedColumnConfigs(t *testing.T) {
-	configs := expandedColumnConfigs()
+	configs := expandedColumnConfigs(0)
 	require.Len(t, configs, expandedPodMaxMetricCol)
 
-	for _, config := range configs[:3] {
+	for _, config := range configs[:5] {
 		require.Equal(t, text.AlignLeft, config.Align)
 		require.Equal(t, text.AlignLeft, config.AlignHeader)
 		require.Equal(t, text.AlignLeft, config.AlignFooter)
 	}
 
-	for _, config := range configs[3:] {
+	for _, config := range configs[5:] {
 		require.Equal(t, text.AlignRight, config.Align)
 		require.Equal(t, text.AlignRight, config.AlignHeader)
 		require.Equal(t, text.AlignRight, config.AlignFooter)
@@ -288,10 +403,12 @@ func TestExpandedColumnConfigs(t *testing.T) {
 func TestColumnSetTotalRowStorageColumns(t *testing.T) {
 	t.Run("uses empty leading columns for footer values", func(t *testing.T) {
 		cs := newColumnSet(nil)
-		row := cs.totalRow(resources.Resources{resources.CPU}, metricsresources.ContainerMetricsResource{})
+		row := cs.totalRow(resources.Resources{resources.CPU}, metricsresources.ContainerMetricsResource{}, metricsresources.Waste{})
 		require.Equal(t, "", row[0])
 		require.Equal(t, "", row[1])
 		require.Equal(t, "", row[2])
+		require.Equal(t, "", row[3])
+		require.Equal(t, "", row[4])
 	})
 
 	t.Run("storage request and limit totals use request fields", func(t *testing.T) {
@@ -312,12 +429,12 @@ func TestColumnSetTotalRowStorageColumns(t *testing.T) {
 			},
 		}
 
-		row := cs.totalRow(outputResources, total)
-		require.Len(t, row, 7)
-		require.Equal(t, "1KiB", row[3])
-		require.Equal(t, "2KiB", row[4])
-		require.Equal(t, "4KiB", row[5])
-		require.Equal(t, "8KiB", row[6])
+		row := cs.totalRow(outputResources, total, metricsresources.Waste{})
+		require.Len(t, row, 9)
+		require.Equal(t, "1KiB", row[5])
+		require.Equal(t, "2KiB", row[6])
+		require.Equal(t, "4KiB", row[7])
+		require.Equal(t, "8KiB", row[8])
 	})
 
 	t.Run("storage used totals use used fields", func(t *testing.T) {
@@ -330,9 +447,9 @@ func TestColumnSetTotalRowStorageColumns(t *testing.T) {
 			},
 		}
 
-		row := cs.totalRow(outputResources, total)
-		require.Len(t, row, 5)
-		require.Equal(t, "3KiB", row[3])
-		require.Equal(t, "5KiB", row[4])
+		row := cs.totalRow(outputResources, total, metricsresources.Waste{})
+		require.Len(t, row, 7)
+		require.Equal(t, "3KiB", row[5])
+		require.Equal(t, "5KiB", row[6])
 	})
 }
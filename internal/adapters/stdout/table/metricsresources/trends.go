@@ -0,0 +1,83 @@
+package metricsresources
+
+import (
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/trend"
+	"github.com/trezorg/k8spodsmetrics/internal/history"
+	servicemetricsresources "github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+)
+
+// compactTrends inserts a trend column after the CPU and the memory column of
+// compact rows. Without usage history rows are left as they are.
+type compactTrends struct {
+	usage           *history.History
+	outputResources resources.Resources
+}
+
+func (c compactTrends) columns() int {
+	if c.usage == nil {
+		return 0
+	}
+	columns := 0
+	if c.outputResources.IsCPU() {
+		columns++
+	}
+	if c.outputResources.IsMemory() {
+		columns++
+	}
+	return columns
+}
+
+func (c compactTrends) header(row table.Row) table.Row {
+	return c.insert(row, "CPU TREND", "MEM TREND")
+}
+
+func (c compactTrends) pod(resource servicemetricsresources.PodMetricsResource, row table.Row) table.Row {
+	if c.usage == nil {
+		return row
+	}
+	key := podKey(resource)
+	return c.insert(row, trend.String(c.usage.CPU(key)), trend.String(c.usage.Memory(key)))
+}
+
+func (c compactTrends) blank(row table.Row) table.Row {
+	return c.insert(row, "", "")
+}
+
+func (c compactTrends) insert(row table.Row, cpu, memory string) table.Row {
+	if c.usage == nil {
+		return row
+	}
+	// The metric columns follow the identity columns up to RESTARTS.
+	index := compactRestartsColumn
+	result := append(table.Row{}, row[:index]...)
+	if c.outputResources.IsCPU() {
+		result = append(result, row[index], cpu)
+		index++
+	}
+	if c.outputResources.IsMemory() {
+		result = append(result, row[index], memory)
+		index++
+	}
+	return append(result, row[index:]...)
+}
+
+// podSamples returns the usage of every pod keyed by cluster, namespace and
+// name, unknown for pods without metrics.
+func podSamples(list servicemetricsresources.PodMetricsResourceList) map[string]history.Sample {
+	samples := make(map[string]history.Sample, len(list))
+	for _, resource := range list {
+		sample := history.Sample{CPU: history.Unknown, Memory: history.Unknown}
+		if resource.HasMetrics() {
+			aggregated := resource.PodMetrics()
+			sample = history.Sample{CPU: aggregated.Requests.CPUUsed, Memory: aggregated.Requests.MemoryUsed}
+		}
+		samples[podKey(resource)] = sample
+	}
+	return samples
+}
+
+func podKey(resource servicemetricsresources.PodMetricsResource) string {
+	return resource.Cluster + "/" + resource.PodResource.Namespace + "/" + resource.PodResource.Name
+}
@@ -0,0 +1,87 @@
+package metricsresources
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/history"
+	servicemetricsresources "github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+)
+
+func TestCompactTrendsInsert(t *testing.T) {
+	row := table.Row{"ns", "pod", "node", "qos", "0", "cpu", "mem", "sto"}
+	tests := []struct {
+		name            string
+		outputResources resources.Resources
+		row             table.Row
+		want            table.Row
+	}{
+		{
+			name:            "cpu and memory",
+			outputResources: resources.Resources{resources.All},
+			row:             row,
+			want:            table.Row{"ns", "pod", "node", "qos", "0", "cpu", "c", "mem", "m", "sto"},
+		},
+		{
+			name:            "memory only",
+			outputResources: resources.Resources{resources.Memory},
+			row:             table.Row{"ns", "pod", "node", "qos", "0", "mem"},
+			want:            table.Row{"ns", "pod", "node", "qos", "0", "mem", "m"},
+		},
+		{
+			name:            "storage only",
+			outputResources: resources.Resources{resources.Storage},
+			row:             table.Row{"ns", "pod", "node", "qos", "0", "sto"},
+			want:            table.Row{"ns", "pod", "node", "qos", "0", "sto"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trends := compactTrends{usage: history.New(3), outputResources: tt.outputResources}
+			require.Equal(t, tt.want, trends.insert(tt.row, "c", "m"))
+		})
+	}
+
+	require.Equal(t, row, compactTrends{outputResources: resources.Resources{resources.All}}.insert(row, "c", "m"))
+}
+
+func TestPodSamples(t *testing.T) {
+	withoutMetrics := testSecondCompactPodResource()
+	withoutMetrics.PodMetric = podmetrics.PodMetric{}
+
+	samples := podSamples(servicemetricsresources.PodMetricsResourceList{testCompactPodResource(), withoutMetrics})
+
+	require.Equal(t, history.Sample{CPU: 270, Memory: 3072}, samples["/default/api-server"])
+	require.Equal(t, history.Sample{CPU: history.Unknown, Memory: history.Unknown}, samples["/kube-system/dns"])
+}
+
+func TestToCompactTrendWriter(t *testing.T) {
+	usage := history.New(5)
+	write := ToCompactTrendWriter(resources.Resources{resources.CPU, resources.Memory}, usage, false)
+	list := servicemetricsresources.PodMetricsResourceList{testCompactPodResource(), testSecondCompactPodResource()}
+
+	write(&bytes.Buffer{}, list, nil)
+	busier := testCompactPodResource()
+	busier.PodMetric.Containers[0].Metric.CPU = 400
+	var buf bytes.Buffer
+	write(&buf, servicemetricsresources.PodMetricsResourceList{busier, testSecondCompactPodResource()}, nil)
+
+	output := buf.String()
+	require.Contains(t, output, "CPU TREND")
+	require.Contains(t, output, "MEM TREND")
+	require.Contains(t, output, "▄█ ↑")
+	require.Contains(t, output, "██ →")
+	require.Contains(t, output, "TOTAL")
+}
+
+func TestPrintCompactToHasNoTrends(t *testing.T) {
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, servicemetricsresources.PodMetricsResourceList{testCompactPodResource()}, resources.Resources{resources.CPU})
+
+	require.NotContains(t, buf.String(), "TREND")
+}
@@ -0,0 +1,105 @@
+package namespaces
+
+import (
+	"io"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	formatnamespaces "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/namespaces"
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+)
+
+const (
+	compactNamespaceColumn = 1
+	compactPodsColumn      = 2
+	maxCompactColumns      = 7
+)
+
+func ToCompactTable(outputResources resources.Resources) Table {
+	return Table(func(list namespaces.NamespaceResourceList) {
+		PrintCompactTo(os.Stdout, list, outputResources)
+	})
+}
+
+func ToCompactWriter(outputResources resources.Resources) func(io.Writer, namespaces.NamespaceResourceList) {
+	return func(w io.Writer, list namespaces.NamespaceResourceList) {
+		PrintCompactTo(w, list, outputResources)
+	}
+}
+
+func PrintCompactTo(w io.Writer, list namespaces.NamespaceResourceList, outputResources resources.Resources) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	configureCompactTable(t)
+	t.AppendHeader(compactHeaderRow(outputResources))
+
+	var total namespaces.NamespaceResource
+	for _, resource := range list {
+		t.AppendRow(compactNamespaceRow(resource, outputResources))
+		accumulateTotal(&total, resource)
+	}
+
+	if len(list) > 1 {
+		t.AppendFooter(compactTotalRow(total, outputResources))
+	}
+
+	t.Render()
+}
+
+func compactHeaderRow(outputResources resources.Resources) table.Row {
+	row := table.Row{"NAMESPACE", "PODS"}
+	if outputResources.IsCPU() {
+		row = append(row, "CPU(req/used/lim)", "CPU QUOTA(req/lim)")
+	}
+	if outputResources.IsMemory() {
+		row = append(row, "MEM(req/used/lim)", "MEM QUOTA(req/lim)")
+	}
+	if outputResources.IsPods() {
+		row = append(row, "PODS QUOTA")
+	}
+	return row
+}
+
+func compactNamespaceRow(resource namespaces.NamespaceResource, outputResources resources.Resources) table.Row {
+	formatter := formatnamespaces.New(resource)
+	row := table.Row{resource.Name, formatter.PodsString()}
+	if outputResources.IsCPU() {
+		row = append(row, formatter.CPUCompactString(), formatter.CPUQuotaCompactString())
+	}
+	if outputResources.IsMemory() {
+		row = append(row, formatter.MemoryCompactString(), formatter.MemoryQuotaCompactString())
+	}
+	if outputResources.IsPods() {
+		row = append(row, formatter.PodsQuotaString())
+	}
+	return row
+}
+
+func compactTotalRow(total namespaces.NamespaceResource, outputResources resources.Resources) table.Row {
+	formatter := formatnamespaces.New(total)
+	row := table.Row{"TOTAL", formatter.PodsString()}
+	if outputResources.IsCPU() {
+		row = append(row, formatter.CPUCompactString(), "")
+	}
+	if outputResources.IsMemory() {
+		row = append(row, formatter.MemoryCompactString(), "")
+	}
+	if outputResources.IsPods() {
+		row = append(row, "")
+	}
+	return row
+}
+
+func configureCompactTable(t table.Writer) {
+	applyTableStyle(t)
+	configs := []table.ColumnConfig{
+		{Number: compactNamespaceColumn, Align: text.AlignLeft},
+		{Number: compactPodsColumn, Align: text.AlignRight},
+	}
+	for number := compactPodsColumn + 1; number <= maxCompactColumns; number++ {
+		configs = append(configs, table.ColumnConfig{Number: number, Align: text.AlignRight})
+	}
+	t.SetColumnConfigs(configs)
+}
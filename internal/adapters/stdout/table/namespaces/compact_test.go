@@ -0,0 +1,41 @@
+package namespaces
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+)
+
+func TestCompactHeaderRow(t *testing.T) {
+	row := compactHeaderRow(resources.Resources{resources.All})
+	require.Equal(t, []any{"NAMESPACE", "PODS", "CPU(req/used/lim)", "CPU QUOTA(req/lim)", "MEM(req/used/lim)", "MEM QUOTA(req/lim)", "PODS QUOTA"}, []any(row))
+}
+
+func TestCompactNamespaceRow(t *testing.T) {
+	row := compactNamespaceRow(testNamespaces()[0], resources.Resources{resources.CPU, resources.Pods})
+	require.Equal(t, []any{"team-a", "2", "300/50/600", "30%/-", "2/10 (20%)"}, []any(row))
+}
+
+func TestPrintCompactToIncludesTotalFooter(t *testing.T) {
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, testNamespaces(), resources.Resources{resources.CPU})
+
+	output := buf.String()
+	require.Contains(t, output, "CPU QUOTA(REQ/LIM)")
+	require.Contains(t, output, "TOTAL")
+	require.Contains(t, output, "400/50/600")
+	require.NotContains(t, output, "MEM(REQ/USED/LIM)")
+}
+
+func TestPrintCompactToSingleNamespaceHasNoFooter(t *testing.T) {
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, namespaces.NamespaceResourceList{testNamespaces()[0]}, resources.Resources{resources.Memory})
+
+	output := buf.String()
+	require.NotContains(t, output, "TOTAL")
+	require.Contains(t, output, "2KiB/0B/0B")
+}
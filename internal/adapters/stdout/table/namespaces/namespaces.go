@@ -0,0 +1,212 @@
+package namespaces
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	formatnamespaces "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/namespaces"
+	"github.com/trezorg/k8spodsmetrics/internal/columns"
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+)
+
+const (
+	expandedNamespaceColumn = 1
+	expandedPodsColumn      = 2
+	expandedFirstMetric     = 3
+	expandedMaxMetric       = 13
+	quotaSuffix             = " Quota"
+)
+
+type Table func(list namespaces.NamespaceResourceList)
+
+type ColumnSet struct {
+	Request bool
+	Limit   bool
+	Used    bool
+}
+
+func newColumnSet(cols []columns.Column) ColumnSet {
+	if len(cols) == 0 {
+		return ColumnSet{Request: true, Limit: true, Used: true}
+	}
+	cs := ColumnSet{}
+	for _, col := range cols {
+		//nolint:exhaustive // Total, Allocatable, Available, Free are node-only columns
+		switch col {
+		case columns.Request:
+			cs.Request = true
+		case columns.Limit:
+			cs.Limit = true
+		case columns.Used:
+			cs.Used = true
+		default:
+			// Node-only columns (Total, Allocatable, Available, Free) ignored for namespaces
+		}
+	}
+	return cs
+}
+
+// appendResourceHeader adds the requests and limits quota next to their totals.
+func (cs ColumnSet) appendResourceHeader(result table.Row, label string) table.Row {
+	if cs.Request {
+		result = append(result, label+" Request", label+" Request"+quotaSuffix)
+	}
+	if cs.Limit {
+		result = append(result, label+" Limit", label+" Limit"+quotaSuffix)
+	}
+	if cs.Used {
+		result = append(result, label+" Used")
+	}
+	return result
+}
+
+func (cs ColumnSet) headerFooterRow(outputResources resources.Resources, firstColumn string) table.Row {
+	result := table.Row{firstColumn, "Pods"}
+	if outputResources.IsCPU() {
+		result = cs.appendResourceHeader(result, "CPU")
+	}
+	if outputResources.IsMemory() {
+		result = cs.appendResourceHeader(result, "Memory")
+	}
+	if outputResources.IsPods() {
+		result = append(result, "Pods"+quotaSuffix)
+	}
+	return result
+}
+
+func (cs ColumnSet) appendCPUColumns(result table.Row, formatter formatnamespaces.Formatter, total bool) table.Row {
+	if cs.Request {
+		result = append(result, formatter.CPURequestString(), quotaOrEmpty(formatter.CPURequestQuotaString, total))
+	}
+	if cs.Limit {
+		result = append(result, formatter.CPULimitString(), quotaOrEmpty(formatter.CPULimitQuotaString, total))
+	}
+	if cs.Used {
+		result = append(result, formatter.CPUUsedString())
+	}
+	return result
+}
+
+func (cs ColumnSet) appendMemoryColumns(result table.Row, formatter formatnamespaces.Formatter, total bool) table.Row {
+	if cs.Request {
+		result = append(result, formatter.MemoryRequestString(), quotaOrEmpty(formatter.MemoryRequestQuotaString, total))
+	}
+	if cs.Limit {
+		result = append(result, formatter.MemoryLimitString(), quotaOrEmpty(formatter.MemoryLimitQuotaString, total))
+	}
+	if cs.Used {
+		result = append(result, formatter.MemoryUsedString())
+	}
+	return result
+}
+
+func (cs ColumnSet) row(
+	firstColumn string,
+	resource namespaces.NamespaceResource,
+	outputResources resources.Resources,
+	total bool,
+) table.Row {
+	formatter := formatnamespaces.New(resource)
+	result := table.Row{firstColumn, formatter.PodsString()}
+	if outputResources.IsCPU() {
+		result = cs.appendCPUColumns(result, formatter, total)
+	}
+	if outputResources.IsMemory() {
+		result = cs.appendMemoryColumns(result, formatter, total)
+	}
+	if outputResources.IsPods() {
+		result = append(result, quotaOrEmpty(formatter.PodsQuotaString, total))
+	}
+	return result
+}
+
+func (cs ColumnSet) dataRow(resource namespaces.NamespaceResource, outputResources resources.Resources) table.Row {
+	return cs.row(resource.Name, resource, outputResources, false)
+}
+
+// totalRow shows summed totals. Quotas are per namespace and are left empty.
+func (cs ColumnSet) totalRow(total namespaces.NamespaceResource, outputResources resources.Resources) table.Row {
+	return cs.row("", total, outputResources, true)
+}
+
+func quotaOrEmpty(quota func() string, total bool) string {
+	if total {
+		return ""
+	}
+	return quota()
+}
+
+func ToTable(outputResources resources.Resources, cols []columns.Column) Table {
+	cs := newColumnSet(cols)
+	return Table(func(list namespaces.NamespaceResourceList) {
+		PrintTo(os.Stdout, list, outputResources, cs)
+	})
+}
+
+func ToWriter(outputResources resources.Resources, cols []columns.Column) func(io.Writer, namespaces.NamespaceResourceList) {
+	cs := newColumnSet(cols)
+	return func(w io.Writer, list namespaces.NamespaceResourceList) {
+		PrintTo(w, list, outputResources, cs)
+	}
+}
+
+func PrintTo(w io.Writer, list namespaces.NamespaceResourceList, outputResources resources.Resources, cs ColumnSet) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	configureExpandedTable(t)
+	t.AppendHeader(cs.headerFooterRow(outputResources, "Namespace"))
+	var total namespaces.NamespaceResource
+	for _, resource := range list {
+		t.AppendRow(cs.dataRow(resource, outputResources))
+		t.AppendSeparator()
+		accumulateTotal(&total, resource)
+	}
+	t.AppendRow(cs.headerFooterRow(outputResources, "Total"))
+	t.AppendSeparator()
+	t.AppendFooter(cs.totalRow(total, outputResources))
+	t.Render()
+}
+
+func accumulateTotal(total *namespaces.NamespaceResource, resource namespaces.NamespaceResource) {
+	total.Pods += resource.Pods
+	total.MetricsMissing += resource.MetricsMissing
+	total.CPURequest += resource.CPURequest
+	total.CPULimit += resource.CPULimit
+	total.CPUUsed += resource.CPUUsed
+	total.MemoryRequest += resource.MemoryRequest
+	total.MemoryLimit += resource.MemoryLimit
+	total.MemoryUsed += resource.MemoryUsed
+}
+
+func configureExpandedTable(t table.Writer) {
+	applyTableStyle(t)
+	configs := []table.ColumnConfig{
+		{Number: expandedNamespaceColumn, Align: text.AlignLeft, AlignHeader: text.AlignLeft, AlignFooter: text.AlignLeft},
+		{Number: expandedPodsColumn, Align: text.AlignRight, AlignHeader: text.AlignRight, AlignFooter: text.AlignRight},
+	}
+	for number := expandedFirstMetric; number <= expandedMaxMetric; number++ {
+		configs = append(configs, table.ColumnConfig{
+			Number:      number,
+			Align:       text.AlignRight,
+			AlignHeader: text.AlignRight,
+			AlignFooter: text.AlignRight,
+		})
+	}
+	t.SetColumnConfigs(configs)
+}
+
+func applyTableStyle(t table.Writer) {
+	t.SetStyle(table.StyleLight)
+}
+
+func (s Table) Success(list namespaces.NamespaceResourceList) {
+	s(list)
+}
+
+func (Table) Error(err error) {
+	slog.Error("table namespaces output failed", "error", err)
+}
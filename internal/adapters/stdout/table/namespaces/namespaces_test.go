@@ -0,0 +1,82 @@
+package namespaces
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/columns"
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+)
+
+func quota(hard, used int64) *quotas.Quota {
+	result := quotas.NewQuota(hard, used)
+	return &result
+}
+
+func testNamespaces() namespaces.NamespaceResourceList {
+	return namespaces.NamespaceResourceList{
+		{
+			Name:          "team-a",
+			Pods:          2,
+			CPURequest:    300,
+			CPULimit:      600,
+			CPUUsed:       50,
+			MemoryRequest: 2048,
+			Quota: quotas.NamespaceQuota{
+				RequestsCPU: quota(1000, 300),
+				Pods:        quota(10, 2),
+			},
+		},
+		{
+			Name:           "team-b",
+			Pods:           1,
+			MetricsMissing: 1,
+			CPURequest:     100,
+		},
+	}
+}
+
+func TestHeaderFooterRow(t *testing.T) {
+	cs := newColumnSet(nil)
+	row := cs.headerFooterRow(resources.Resources{resources.All}, "Namespace")
+	require.Len(t, row, expandedMaxMetric)
+	require.Equal(t, "CPU Request", row[2])
+	require.Equal(t, "CPU Request Quota", row[3])
+	require.Equal(t, "Pods Quota", row[12])
+
+	cs = newColumnSet([]columns.Column{columns.Used})
+	row = cs.headerFooterRow(resources.Resources{resources.Memory}, "Namespace")
+	require.Equal(t, []any{"Namespace", "Pods", "Memory Used"}, []any(row))
+}
+
+func TestDataRow(t *testing.T) {
+	cs := newColumnSet(nil)
+	row := cs.dataRow(testNamespaces()[0], resources.Resources{resources.CPU})
+	require.Equal(t, []any{"team-a", "2", "300", "300/1000 (30%)", "600", "", "50"}, []any(row))
+
+	row = cs.dataRow(testNamespaces()[1], resources.Resources{resources.CPU})
+	require.Equal(t, "1 (1 no metrics)", row[1])
+	require.Empty(t, row[6])
+}
+
+func TestTotalRowOmitsQuotas(t *testing.T) {
+	cs := newColumnSet([]columns.Column{columns.Request})
+	row := cs.totalRow(testNamespaces()[0], resources.Resources{resources.CPU, resources.Pods})
+	require.Equal(t, []any{"", "2", "300", "", ""}, []any(row))
+}
+
+func TestPrintTo(t *testing.T) {
+	var buf bytes.Buffer
+	PrintTo(&buf, testNamespaces(), resources.Resources{resources.CPU}, newColumnSet([]columns.Column{columns.Request}))
+
+	output := buf.String()
+	require.Contains(t, output, "CPU REQUEST QUOTA")
+	require.Contains(t, output, "team-a")
+	require.Contains(t, output, "team-b")
+	require.Contains(t, output, "400")
+	require.NotContains(t, output, "CPU Limit")
+}
@@ -0,0 +1,55 @@
+package noderesources
+
+import (
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
+)
+
+// clusterGroups groups nodes by cluster when they were read from several
+// contexts and keeps them in one group otherwise.
+func clusterGroups(list noderesources.NodeResourceList) (noderesources.NodeResourceGroupList, bool) {
+	if list.Clustered() {
+		return noderesources.GroupByLabel(list, ""), true
+	}
+	return noderesources.NodeResourceGroupList{{Items: list}}, false
+}
+
+// clustered reports whether the groups hold nodes of several contexts, which
+// adds a leading cluster column to the table.
+func clustered(groups noderesources.NodeResourceGroupList) bool {
+	for _, group := range groups {
+		if group.Cluster != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterRow prepends the cluster cell to row when the table has a cluster column.
+func clusterRow(withCluster bool, cluster string, row table.Row) table.Row {
+	if !withCluster {
+		return row
+	}
+	return append(table.Row{cluster}, row...)
+}
+
+// clusterColumnConfigs shifts configs right past the cluster column when the
+// table has one.
+func clusterColumnConfigs(withCluster bool, configs []table.ColumnConfig) []table.ColumnConfig {
+	if !withCluster {
+		return configs
+	}
+	result := make([]table.ColumnConfig, 0, len(configs)+1)
+	result = append(result, table.ColumnConfig{
+		Number:      1,
+		Align:       text.AlignLeft,
+		AlignHeader: text.AlignLeft,
+		AlignFooter: text.AlignLeft,
+	})
+	for _, config := range configs {
+		config.Number++
+		result = append(result, config)
+	}
+	return result
+}
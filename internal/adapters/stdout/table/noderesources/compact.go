@@ -1,24 +1,28 @@
 package noderesources
 
 import (
+	"fmt"
 	"io"
 	"os"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	formatnoderesources "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/noderesources"
+	"github.com/trezorg/k8spodsmetrics/internal/history"
 	servicenoderesources "github.com/trezorg/k8spodsmetrics/internal/noderesources"
 	"github.com/trezorg/k8spodsmetrics/internal/resources"
 )
 
 const (
 	compactNameColumn   = 1
-	compactFirstMetric  = 2
-	compactSecondMetric = 3
-	compactThirdMetric  = 4
-	compactFourthMetric = 5
-	compactFifthMetric  = 6
-	maxCompactColumns   = 7
+	compactStatusColumn = 2
+	compactFirstMetric  = 3
+	compactSecondMetric = 4
+	compactThirdMetric  = 5
+	compactFourthMetric = 6
+	compactFifthMetric  = 7
+	compactSixthMetric  = 8
+	maxCompactColumns   = 9
 )
 
 func ToCompactTable(outputResources resources.Resources) Table {
@@ -33,29 +37,106 @@ func ToCompactWriter(outputResources resources.Resources) func(io.Writer, servic
 	}
 }
 
+// ToCompactGroupsTable prints nodes grouped by the value of label with a
+// subtotal row after every group.
+func ToCompactGroupsTable(outputResources resources.Resources, label string) Table {
+	return Table(func(list servicenoderesources.NodeResourceList) {
+		PrintCompactGroupsTo(os.Stdout, list, outputResources, label)
+	})
+}
+
+func ToCompactGroupsWriter(outputResources resources.Resources, label string) func(io.Writer, servicenoderesources.NodeResourceList) {
+	return func(w io.Writer, list servicenoderesources.NodeResourceList) {
+		PrintCompactGroupsTo(w, list, outputResources, label)
+	}
+}
+
+// ToCompactTrendWriter renders the compact table with sparklines of the
+// recent CPU and memory usage of every node, grouped by label when label is
+// set. Every call adds the nodes to usage, so it is meant to render watch
+// ticks.
+func ToCompactTrendWriter(
+	outputResources resources.Resources,
+	label string,
+	usage *history.History,
+) func(io.Writer, servicenoderesources.NodeResourceList) {
+	return func(w io.Writer, list servicenoderesources.NodeResourceList) {
+		usage.Update(nodeSamples(list))
+		printMetricsUnavailable(w, list)
+		if label != "" {
+			printCompact(w, servicenoderesources.GroupByLabel(list, label), outputResources, true, usage)
+			return
+		}
+		groups, subtotals := clusterGroups(list)
+		printCompact(w, groups, outputResources, subtotals, usage)
+	}
+}
+
 func PrintCompactTo(w io.Writer, list servicenoderesources.NodeResourceList, outputResources resources.Resources) {
+	printMetricsUnavailable(w, list)
+	groups, subtotals := clusterGroups(list)
+	printCompact(w, groups, outputResources, subtotals, nil)
+}
+
+func PrintCompactGroupsTo(w io.Writer, list servicenoderesources.NodeResourceList, outputResources resources.Resources, label string) {
+	printMetricsUnavailable(w, list)
+	printCompact(w, servicenoderesources.GroupByLabel(list, label), outputResources, true, nil)
+}
+
+// printCompact adds trend columns when usage is set.
+func printCompact(
+	w io.Writer,
+	groups servicenoderesources.NodeResourceGroupList,
+	outputResources resources.Resources,
+	subtotals bool,
+	usage *history.History,
+) {
+	withCluster := clustered(groups)
 	t := table.NewWriter()
 	t.SetOutputMirror(w)
-	configureCompactTable(t)
-	t.AppendHeader(compactHeaderRow(outputResources))
+	trends := compactTrends{usage: usage, outputResources: outputResources}
+	configureCompactTable(t, len(outputResources.Extended())+trends.columns(), withCluster)
+	t.AppendHeader(clusterRow(withCluster, "CLUSTER", trends.header(compactHeaderRow(outputResources))))
+	if subtotals && len(groups) > 0 && groups[0].Label != "" {
+		t.SetTitle(fmt.Sprintf("Grouped by %s", groups[0].Label))
+	}
 
-	var total servicenoderesources.NodeResource
-	rendered := 0
-	for _, resource := range list {
-		t.AppendRow(compactNodeRow(resource, outputResources))
-		accumulateTotal(&total, resource)
-		rendered++
+	var total, schedulable servicenoderesources.NodeResource
+	rendered, schedulableNodes := 0, 0
+	for _, group := range groups {
+		for _, resource := range group.Items {
+			t.AppendRow(clusterRow(withCluster, resource.Cluster, trends.node(resource, compactNodeRow(resource, outputResources))))
+			for _, qos := range resource.QOS {
+				t.AppendRow(clusterRow(withCluster, "", trends.blank(compactQOSRow(resource, qos, outputResources))))
+			}
+			total.Add(resource)
+			rendered++
+			if resource.Schedulable {
+				schedulable.Add(resource)
+				schedulableNodes++
+			}
+		}
+		if subtotals {
+			label := "SUBTOTAL " + formatnoderesources.GroupValueString(group)
+			row := compactTotalRow(label, formatnoderesources.GroupNodesString(group), group.Total, outputResources)
+			t.AppendRow(clusterRow(withCluster, group.Cluster, trends.blank(row)))
+			t.AppendSeparator()
+		}
 	}
 
 	if rendered > 1 {
-		t.AppendFooter(compactTotalRow(total, outputResources))
+		t.AppendFooter(clusterRow(withCluster, "", trends.blank(compactTotalRow("TOTAL", "", total, outputResources))))
+	}
+	if rendered > 1 && schedulableNodes < rendered {
+		row := compactTotalRow("SCHEDULABLE", schedulableNote(schedulableNodes, rendered), schedulable, outputResources)
+		t.AppendFooter(clusterRow(withCluster, "", trends.blank(row)))
 	}
 
 	t.Render()
 }
 
 func compactHeaderRow(outputResources resources.Resources) table.Row {
-	row := table.Row{"NAME"}
+	row := table.Row{"NAME", "STATUS"}
 	if outputResources.IsCPU() {
 		row = append(row, "CPU(alloc/used/free)", "CPU(req/lim)")
 	}
@@ -65,12 +146,18 @@ func compactHeaderRow(outputResources resources.Resources) table.Row {
 	if outputResources.IsStorage() {
 		row = append(row, "STO(alloc/used/free)", "EPH(alloc/used/free)")
 	}
+	if outputResources.IsPods() {
+		row = append(row, "PODS(alloc/run/free)")
+	}
+	for _, name := range outputResources.Extended() {
+		row = append(row, name+"(alloc/req/lim)")
+	}
 	return row
 }
 
 func compactNodeRow(resource servicenoderesources.NodeResource, outputResources resources.Resources) table.Row {
 	formatter := formatnoderesources.New(resource)
-	row := table.Row{resource.Name}
+	row := table.Row{formatter.NameString(), formatter.StatusString()}
 	if outputResources.IsCPU() {
 		row = append(row, formatter.CPUCapacityCompactString(), formatter.CPUDemandCompactString())
 	}
@@ -80,12 +167,39 @@ func compactNodeRow(resource servicenoderesources.NodeResource, outputResources
 	if outputResources.IsStorage() {
 		row = append(row, formatter.StorageCapacityCompactString(), formatter.StorageEphemeralCapacityCompactString())
 	}
+	if outputResources.IsPods() {
+		row = append(row, formatter.PodsCompactString())
+	}
+	for _, name := range outputResources.Extended() {
+		row = append(row, formatter.ExtendedCompactString(name))
+	}
+	return row
+}
+
+// compactQOSRow renders the QoS class share of a node under its row. Only
+// request and limit cells are filled.
+func compactQOSRow(
+	resource servicenoderesources.NodeResource,
+	group servicenoderesources.QOSResource,
+	outputResources resources.Resources,
+) table.Row {
+	formatter := formatnoderesources.NewQOS(resource, group)
+	row := table.Row{"└─ " + formatter.NameString(), ""}
+	if outputResources.IsCPU() {
+		row = append(row, "", formatter.CPUDemandCompactString())
+	}
+	if outputResources.IsMemory() {
+		row = append(row, "", formatter.MemoryDemandCompactString())
+	}
+	for width := len(compactHeaderRow(outputResources)); len(row) < width; {
+		row = append(row, "")
+	}
 	return row
 }
 
-func compactTotalRow(total servicenoderesources.NodeResource, outputResources resources.Resources) table.Row {
+func compactTotalRow(label, note string, total servicenoderesources.NodeResource, outputResources resources.Resources) table.Row {
 	formatter := formatnoderesources.New(total)
-	row := table.Row{"TOTAL"}
+	row := table.Row{label, note}
 	if outputResources.IsCPU() {
 		row = append(row, formatter.CPUCapacityCompactString(), formatter.CPUDemandCompactString())
 	}
@@ -95,45 +209,32 @@ func compactTotalRow(total servicenoderesources.NodeResource, outputResources re
 	if outputResources.IsStorage() {
 		row = append(row, formatter.StorageCapacityCompactString(), formatter.StorageEphemeralCapacityCompactString())
 	}
+	if outputResources.IsPods() {
+		row = append(row, formatter.PodsCompactString())
+	}
+	for _, name := range outputResources.Extended() {
+		row = append(row, formatter.ExtendedCompactString(name))
+	}
 	return row
 }
 
-func accumulateTotal(total *servicenoderesources.NodeResource, resource servicenoderesources.NodeResource) {
-	total.CPU += resource.CPU
-	total.Memory += resource.Memory
-	total.UsedCPU += resource.UsedCPU
-	total.UsedMemory += resource.UsedMemory
-	total.AllocatableCPU += resource.AllocatableCPU
-	total.AllocatableMemory += resource.AllocatableMemory
-	total.CPURequest += resource.CPURequest
-	total.MemoryRequest += resource.MemoryRequest
-	total.CPULimit += resource.CPULimit
-	total.MemoryLimit += resource.MemoryLimit
-	total.AvailableCPU += resource.AvailableCPU
-	total.AvailableMemory += resource.AvailableMemory
-	total.FreeCPU += resource.FreeCPU
-	total.FreeMemory += resource.FreeMemory
-	total.Storage += resource.Storage
-	total.AllocatableStorage += resource.AllocatableStorage
-	total.UsedStorage += resource.UsedStorage
-	total.FreeStorage += resource.FreeStorage
-	total.StorageEphemeral += resource.StorageEphemeral
-	total.AllocatableStorageEphemeral += resource.AllocatableStorageEphemeral
-	total.UsedStorageEphemeral += resource.UsedStorageEphemeral
-	total.FreeStorageEphemeral += resource.FreeStorageEphemeral
-}
-
-func configureCompactTable(t table.Writer) {
+func configureCompactTable(t table.Writer, extendedColumns int, withCluster bool) {
 	applyTableStyle(t)
-	t.SetColumnConfigs([]table.ColumnConfig{
+	configs := []table.ColumnConfig{
 		{Number: compactNameColumn, Align: text.AlignLeft},
+		{Number: compactStatusColumn, Align: text.AlignLeft},
 		{Number: compactFirstMetric, Align: text.AlignRight},
 		{Number: compactSecondMetric, Align: text.AlignRight},
 		{Number: compactThirdMetric, Align: text.AlignRight},
 		{Number: compactFourthMetric, Align: text.AlignRight},
 		{Number: compactFifthMetric, Align: text.AlignRight},
+		{Number: compactSixthMetric, Align: text.AlignRight},
 		{Number: maxCompactColumns, Align: text.AlignRight},
-	})
+	}
+	for number := maxCompactColumns + 1; number <= maxCompactColumns+extendedColumns; number++ {
+		configs = append(configs, table.ColumnConfig{Number: number, Align: text.AlignRight})
+	}
+	t.SetColumnConfigs(clusterColumnConfigs(withCluster, configs))
 }
 
 func applyTableStyle(t table.Writer) {
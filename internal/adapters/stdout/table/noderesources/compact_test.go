@@ -2,17 +2,21 @@ package noderesources
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/stretchr/testify/require"
 
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
 	servicenoderesources "github.com/trezorg/k8spodsmetrics/internal/noderesources"
 	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	v1 "k8s.io/api/core/v1"
 )
 
 func TestCompactHeaderRow(t *testing.T) {
 	row := compactHeaderRow(resources.Resources{resources.All})
-	require.Equal(t, []any{"NAME", "CPU(alloc/used/free)", "CPU(req/lim)", "MEM(alloc/used/free)", "MEM(req/lim)", "STO(alloc/used/free)", "EPH(alloc/used/free)"}, []any(row))
+	require.Equal(t, []any{"NAME", "STATUS", "CPU(alloc/used/free)", "CPU(req/lim)", "MEM(alloc/used/free)", "MEM(req/lim)", "STO(alloc/used/free)", "EPH(alloc/used/free)", "PODS(alloc/run/free)"}, []any(row))
 }
 
 func TestCompactNodeRow(t *testing.T) {
@@ -20,12 +24,43 @@ func TestCompactNodeRow(t *testing.T) {
 	row := compactNodeRow(resource, resources.Resources{resources.CPU, resources.Memory})
 
 	require.Equal(t, "node-a", row[0])
-	require.Equal(t, "3900/1200/2700", row[1])
-	require.Contains(t, row[2], "2200/")
-	require.Contains(t, row[2], "6000")
-	require.Equal(t, "8KiB/3KiB/5KiB", row[3])
-	require.Contains(t, row[4], "8KiB/")
-	require.Contains(t, row[4], "16KiB")
+	require.Equal(t, "Ready", row[1])
+	require.Equal(t, "3900/1200/2700", row[2])
+	require.Contains(t, row[3], "2200/")
+	require.Contains(t, row[3], "6000")
+	require.Equal(t, "8KiB/3KiB/5KiB", row[4])
+	require.Contains(t, row[5], "8KiB/")
+	require.Contains(t, row[5], "16KiB")
+}
+
+func TestCompactQOSRow(t *testing.T) {
+	resource := testCompactNodeResource()
+	group := servicenoderesources.QOSResource{Class: v1.PodQOSGuaranteed, Pods: 2, CPURequest: 390, CPULimit: 390, MemoryRequest: 1024, MemoryLimit: 1024}
+	row := compactQOSRow(resource, group, resources.Resources{resources.All})
+
+	require.Len(t, row, len(compactHeaderRow(resources.Resources{resources.All})))
+	require.Equal(t, "└─ Guaranteed (2 pods)", row[0])
+	require.Equal(t, "", row[1])
+	require.Equal(t, "", row[2])
+	require.Equal(t, "390 (10%)/390 (10%)", row[3])
+	require.Equal(t, "", row[4])
+	require.Equal(t, "1KiB (12%)/1KiB (12%)", row[5])
+	require.Equal(t, "", row[8])
+}
+
+func TestPrintCompactToRendersQOSRows(t *testing.T) {
+	resource := testCompactNodeResource()
+	resource.QOS = []servicenoderesources.QOSResource{
+		{Class: v1.PodQOSGuaranteed, Pods: 1},
+		{Class: v1.PodQOSBestEffort, Pods: 4},
+	}
+
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, servicenoderesources.NodeResourceList{resource}, resources.Resources{resources.CPU})
+
+	output := buf.String()
+	require.Contains(t, output, "└─ Guaranteed (1 pods)")
+	require.Contains(t, output, "└─ BestEffort (4 pods)")
 }
 
 func TestPrintCompactToIncludesTotalFooter(t *testing.T) {
@@ -64,9 +99,70 @@ func TestPrintCompactToDoesNotTruncateNodeName(t *testing.T) {
 	require.NotContains(t, output, "...")
 }
 
+func TestPrintCompactToSchedulableFooter(t *testing.T) {
+	cordoned := testSecondCompactNodeResource()
+	cordoned.Unschedulable = true
+	cordoned.Schedulable = false
+
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, servicenoderesources.NodeResourceList{testCompactNodeResource(), cordoned}, resources.Resources{resources.CPU})
+
+	output := buf.String()
+	require.Contains(t, output, "Ready,SchedulingDisabled")
+	require.Contains(t, output, "TOTAL")
+	require.Regexp(t, `SCHEDULABLE +│ 1/2 NODES +│ 3900/1200/2700 +│`, output)
+}
+
+func TestPrintCompactToOmitsSchedulableFooterWhenAllSchedulable(t *testing.T) {
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, servicenoderesources.NodeResourceList{testCompactNodeResource(), testSecondCompactNodeResource()}, resources.Resources{resources.CPU})
+
+	require.NotContains(t, buf.String(), "SCHEDULABLE")
+}
+
+func TestCompactNodeRowPods(t *testing.T) {
+	resource := testCompactNodeResource()
+	resource.AllocatablePods = 110
+	resource.RunningPods = 42
+	resource.FreePods = 68
+
+	row := compactNodeRow(resource, resources.Resources{resources.Pods})
+	require.Equal(t, table.Row{"node-a", "Ready", "110/42/68"}, row)
+}
+
+func TestPrintCompactToExtendedResources(t *testing.T) {
+	first := testCompactNodeResource()
+	first.Extended = map[string]servicenoderesources.ExtendedResource{"nvidia.com/gpu": {Capacity: 4, Allocatable: 4, Request: 1, Limit: 2}}
+	second := testSecondCompactNodeResource()
+	second.Extended = map[string]servicenoderesources.ExtendedResource{"nvidia.com/gpu": {Capacity: 2, Allocatable: 2}}
+
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, servicenoderesources.NodeResourceList{first, second}, resources.Resources{resources.CPU, "nvidia.com/gpu"})
+
+	output := buf.String()
+	require.Contains(t, output, "NVIDIA.COM/GPU(ALLOC/REQ/LIM)")
+	require.Contains(t, output, "4/1/2")
+	require.Contains(t, output, "2/0/0")
+	require.Contains(t, output, "6/1/2")
+}
+
+func TestPrintCompactToMetricsUnavailable(t *testing.T) {
+	node := testCompactNodeResource()
+	node.MetricsUnavailable = true
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, servicenoderesources.NodeResourceList{node}, resources.Resources{resources.CPU})
+
+	output := buf.String()
+	require.True(t, strings.HasPrefix(output, metricssource.UnavailableWarning+"\n"))
+	require.Contains(t, output, "MetricsUnavailable")
+	require.Regexp(t, `\d+/n/a/n/a`, output)
+}
+
 func testCompactNodeResource() servicenoderesources.NodeResource {
 	return servicenoderesources.NodeResource{
 		Name:                        "node-a",
+		Ready:                       true,
+		Schedulable:                 true,
 		CPU:                         4000,
 		AllocatableCPU:              3900,
 		UsedCPU:                     1200,
@@ -93,6 +189,8 @@ func testCompactNodeResource() servicenoderesources.NodeResource {
 func testSecondCompactNodeResource() servicenoderesources.NodeResource {
 	return servicenoderesources.NodeResource{
 		Name:                        "node-b",
+		Ready:                       true,
+		Schedulable:                 true,
 		CPU:                         8000,
 		AllocatableCPU:              7900,
 		UsedCPU:                     6100,
@@ -115,3 +213,37 @@ func testSecondCompactNodeResource() servicenoderesources.NodeResource {
 		FreeStorageEphemeral:        90 * 1024,
 	}
 }
+
+func TestPrintCompactGroupsTo(t *testing.T) {
+	first := testCompactNodeResource()
+	first.Labels = map[string]string{"zone": "b"}
+	second := testSecondCompactNodeResource()
+	second.Labels = map[string]string{"zone": "a"}
+	third := testCompactNodeResource()
+	third.Name = "node-c"
+
+	var buf bytes.Buffer
+	PrintCompactGroupsTo(&buf, servicenoderesources.NodeResourceList{first, second, third}, resources.Resources{resources.CPU}, "zone")
+
+	output := buf.String()
+	require.Contains(t, output, "Grouped by zone")
+	require.Regexp(t, `(?s)node-b.*SUBTOTAL a +│ 1 nodes +│ +7900/6100/1800 │.*node-a.*SUBTOTAL b .*node-c.*SUBTOTAL <none> +│ 1 nodes`, output)
+	require.Contains(t, output, "TOTAL")
+	require.Contains(t, output, "15700/8500/7200")
+}
+
+func TestPrintCompactToClusters(t *testing.T) {
+	list := servicenoderesources.NodeResourceList{
+		{Cluster: "prod-us", Name: "node-1", AllocatableCPU: 1000, Ready: true, Schedulable: true},
+		{Cluster: "prod-eu", Name: "node-1", AllocatableCPU: 2000, Ready: true, Schedulable: true},
+	}
+
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, list, resources.Resources{resources.CPU})
+
+	output := buf.String()
+	require.Regexp(t, `│ CLUSTER +│ NAME +│ STATUS +│`, output)
+	require.Regexp(t, `│ prod-us +│ SUBTOTAL prod-us │ 1 nodes +│`, output)
+	require.Regexp(t, `│ prod-eu +│ SUBTOTAL prod-eu │ 1 nodes +│`, output)
+	require.Regexp(t, `│ +│ TOTAL +│`, output)
+}
@@ -1,6 +1,7 @@
 package noderesources
 
 import (
+	"fmt"
 	"io"
 	"os"
 
@@ -8,15 +9,17 @@ import (
 	"github.com/jedib0t/go-pretty/v6/text"
 	formatnoderesources "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/noderesources"
 	"github.com/trezorg/k8spodsmetrics/internal/columns"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
 	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
 	"github.com/trezorg/k8spodsmetrics/internal/resources"
 	"log/slog"
 )
 
 const (
-	expandedNodeNameColumn  = 1
-	expandedNodeFirstMetric = 2
-	expandedNodeMaxMetric   = 23
+	expandedNodeNameColumn   = 1
+	expandedNodeStatusColumn = 2
+	expandedNodeFirstMetric  = 3
+	expandedNodeMaxMetric    = 27
 )
 
 type Table func(
@@ -114,8 +117,8 @@ func (cs ColumnSet) appendStorageHeader(result table.Row) table.Row {
 	return result
 }
 
-func (cs ColumnSet) headerFooterRow(outputResources resources.Resources, firstColumn string) table.Row {
-	result := table.Row{firstColumn}
+func (cs ColumnSet) headerFooterRow(outputResources resources.Resources, firstColumn, statusColumn string) table.Row {
+	result := table.Row{firstColumn, statusColumn}
 	if outputResources.IsCPU() {
 		result = cs.appendResourceHeader(result, "CPU")
 	}
@@ -125,9 +128,95 @@ func (cs ColumnSet) headerFooterRow(outputResources resources.Resources, firstCo
 	if outputResources.IsStorage() {
 		result = cs.appendStorageHeader(result)
 	}
+	if outputResources.IsPods() {
+		result = cs.appendPodsHeader(result)
+	}
+	for _, name := range outputResources.Extended() {
+		result = cs.appendExtendedHeader(result, name)
+	}
+	return result
+}
+
+// appendPodsHeader adds pod slot columns. Running pods take the place of used
+// resources.
+func (cs ColumnSet) appendPodsHeader(result table.Row) table.Row {
+	if cs.Allocatable {
+		result = append(result, "Pods Allocatable")
+	}
+	if cs.Used {
+		result = append(result, "Pods Running")
+	}
+	if cs.Free {
+		result = append(result, "Pods Free")
+	}
+	return result
+}
+
+func (cs ColumnSet) appendPodsColumns(result table.Row, resource noderesources.NodeResource) table.Row {
+	formatter := formatnoderesources.New(resource)
+	if cs.Allocatable {
+		result = append(result, formatter.PodsAllocatableString())
+	}
+	if cs.Used {
+		result = append(result, formatter.PodsRunningString())
+	}
+	if cs.Free {
+		result = append(result, formatter.PodsFreeString())
+	}
+	return result
+}
+
+// appendExtendedHeader adds columns of an extended resource. Metrics server
+// reports no usage for them, so Used and Free are skipped.
+func (cs ColumnSet) appendExtendedHeader(result table.Row, name string) table.Row {
+	if cs.Total {
+		result = append(result, name+" Total")
+	}
+	if cs.Allocatable {
+		result = append(result, name+" Allocatable")
+	}
+	if cs.Request {
+		result = append(result, name+" Request")
+	}
+	if cs.Limit {
+		result = append(result, name+" Limit")
+	}
+	if cs.Available {
+		result = append(result, name+" Available")
+	}
 	return result
 }
 
+func (cs ColumnSet) appendExtendedColumns(result table.Row, resource noderesources.NodeResource, name string) table.Row {
+	formatter := formatnoderesources.New(resource)
+	if cs.Total {
+		result = append(result, formatter.ExtendedTotalString(name))
+	}
+	if cs.Allocatable {
+		result = append(result, formatter.ExtendedAllocatableString(name))
+	}
+	if cs.Request {
+		result = append(result, formatter.ExtendedRequestString(name))
+	}
+	if cs.Limit {
+		result = append(result, formatter.ExtendedLimitString(name))
+	}
+	if cs.Available {
+		result = append(result, formatter.ExtendedAvailableString(name))
+	}
+	return result
+}
+
+func (cs ColumnSet) extendedColumnsCount(outputResources resources.Resources) int {
+	perResource := 0
+	for _, set := range []bool{cs.Total, cs.Allocatable, cs.Request, cs.Limit, cs.Available} {
+		if set {
+			perResource++
+		}
+	}
+	return perResource * len(outputResources.Extended())
+}
+
 func (cs ColumnSet) appendCPUColumns(result table.Row, resource noderesources.NodeResource) table.Row {
 	formatter := formatnoderesources.New(resource)
 	if cs.Total {
@@ -137,7 +226,7 @@ func (cs ColumnSet) appendCPUColumns(result table.Row, resource noderesources.No
 		result = append(result, resource.AllocatableCPU)
 	}
 	if cs.Used {
-		result = append(result, resource.UsedCPU)
+		result = append(result, formatter.CPUUsedString())
 	}
 	if cs.Request {
 		result = append(result, formatter.CPURequestString())
@@ -210,7 +299,21 @@ func (cs ColumnSet) appendStorageColumns(result table.Row, resource noderesource
 }
 
 func (cs ColumnSet) dataRow(resource noderesources.NodeResource, outputResources resources.Resources) table.Row {
-	result := table.Row{resource.Name}
+	formatter := formatnoderesources.New(resource)
+	return cs.appendMetricColumns(table.Row{formatter.NameString(), formatter.StatusString()}, resource, outputResources)
+}
+
+// totalRow renders summed node values. The status cell carries a note such as
+// the number of nodes summed.
+func (cs ColumnSet) totalRow(label, note string, total noderesources.NodeResource, outputResources resources.Resources) table.Row {
+	return cs.appendMetricColumns(table.Row{label, note}, total, outputResources)
+}
+
+func (cs ColumnSet) appendMetricColumns(
+	result table.Row,
+	resource noderesources.NodeResource,
+	outputResources resources.Resources,
+) table.Row {
 	if outputResources.IsCPU() {
 		result = cs.appendCPUColumns(result, resource)
 	}
@@ -220,6 +323,53 @@ func (cs ColumnSet) dataRow(resource noderesources.NodeResource, outputResources
 	if outputResources.IsStorage() {
 		result = cs.appendStorageColumns(result, resource)
 	}
+	if outputResources.IsPods() {
+		result = cs.appendPodsColumns(result, resource)
+	}
+	for _, name := range outputResources.Extended() {
+		result = cs.appendExtendedColumns(result, resource, name)
+	}
+	return result
+}
+
+// qosRow renders the QoS class share of a node under its row. Only request
+// and limit cells are filled; the remaining columns stay empty.
+func (cs ColumnSet) qosRow(
+	resource noderesources.NodeResource,
+	group noderesources.QOSResource,
+	outputResources resources.Resources,
+) table.Row {
+	formatter := formatnoderesources.NewQOS(resource, group)
+	result := table.Row{"└─ " + formatter.NameString(), ""}
+	if outputResources.IsCPU() {
+		result = cs.appendQOSDemand(result, formatter.CPURequestString(), formatter.CPULimitString())
+	}
+	if outputResources.IsMemory() {
+		result = cs.appendQOSDemand(result, formatter.MemoryRequestString(), formatter.MemoryLimitString())
+	}
+	for width := len(cs.headerFooterRow(outputResources, "", "")); len(result) < width; {
+		result = append(result, "")
+	}
+	return result
+}
+
+func (cs ColumnSet) appendQOSDemand(result table.Row, request, limit string) table.Row {
+	for _, set := range []bool{cs.Total, cs.Allocatable, cs.Used} {
+		if set {
+			result = append(result, "")
+		}
+	}
+	if cs.Request {
+		result = append(result, request)
+	}
+	if cs.Limit {
+		result = append(result, limit)
+	}
+	for _, set := range []bool{cs.Available, cs.Free} {
+		if set {
+			result = append(result, "")
+		}
+	}
 	return result
 }
 
@@ -257,70 +407,125 @@ func PrintTo(
 	outputResources resources.Resources,
 	cs ColumnSet,
 ) {
+	printMetricsUnavailable(w, list)
+	groups, subtotals := clusterGroups(list)
+	printExpanded(w, groups, outputResources, cs, subtotals)
+}
+
+// printMetricsUnavailable writes the warning of nodes listed without usage
+// above the table.
+func printMetricsUnavailable(w io.Writer, list noderesources.NodeResourceList) {
+	if list.MetricsUnavailable() {
+		_, _ = fmt.Fprintln(w, metricssource.UnavailableWarning)
+	}
+}
+
+// ToGroupsTable prints nodes grouped by the value of label with a subtotal
+// row after every group.
+func ToGroupsTable(
+	outputResources resources.Resources,
+	cols []columns.Column,
+	label string,
+) Table {
+	cs := newColumnSet(cols)
+	return Table(func(list noderesources.NodeResourceList) {
+		PrintGroupsTo(os.Stdout, list, outputResources, cs, label)
+	})
+}
+
+func ToGroupsWriter(
+	outputResources resources.Resources,
+	cols []columns.Column,
+	label string,
+) func(io.Writer, noderesources.NodeResourceList) {
+	cs := newColumnSet(cols)
+	return func(w io.Writer, list noderesources.NodeResourceList) {
+		PrintGroupsTo(w, list, outputResources, cs, label)
+	}
+}
+
+func PrintGroupsTo(
+	w io.Writer,
+	list noderesources.NodeResourceList,
+	outputResources resources.Resources,
+	cs ColumnSet,
+	label string,
+) {
+	printMetricsUnavailable(w, list)
+	printExpanded(w, noderesources.GroupByLabel(list, label), outputResources, cs, true)
+}
+
+func printExpanded(
+	w io.Writer,
+	groups noderesources.NodeResourceGroupList,
+	outputResources resources.Resources,
+	cs ColumnSet,
+	subtotals bool,
+) {
+	withCluster := clustered(groups)
 	t := table.NewWriter()
 	t.SetOutputMirror(w)
-	configureExpandedTable(t)
-	t.AppendHeader(cs.headerFooterRow(outputResources, "Name"))
-	total := noderesources.NodeResource{}
-	for _, resource := range list {
-		t.AppendRow(cs.dataRow(resource, outputResources))
-		t.AppendSeparator()
-		if cs.Total {
-			total.CPU += resource.CPU
-			total.Memory += resource.Memory
-			total.Storage += resource.Storage
-			total.StorageEphemeral += resource.StorageEphemeral
-		}
-		if cs.Allocatable {
-			total.AllocatableCPU += resource.AllocatableCPU
-			total.AllocatableMemory += resource.AllocatableMemory
-			total.AllocatableStorage += resource.AllocatableStorage
-			total.AllocatableStorageEphemeral += resource.AllocatableStorageEphemeral
-		}
-		if cs.Used {
-			total.UsedCPU += resource.UsedCPU
-			total.UsedMemory += resource.UsedMemory
-			total.UsedStorage += resource.UsedStorage
-			total.UsedStorageEphemeral += resource.UsedStorageEphemeral
-		}
-		if cs.Request {
-			total.CPURequest += resource.CPURequest
-			total.MemoryRequest += resource.MemoryRequest
-		}
-		if cs.Limit {
-			total.CPULimit += resource.CPULimit
-			total.MemoryLimit += resource.MemoryLimit
+	configureExpandedTable(t, cs.extendedColumnsCount(outputResources), withCluster)
+	t.AppendHeader(clusterRow(withCluster, "Cluster", cs.headerFooterRow(outputResources, "Name", "Status")))
+	if subtotals && len(groups) > 0 && groups[0].Label != "" {
+		t.SetTitle(fmt.Sprintf("Grouped by %s", groups[0].Label))
+	}
+	var total, schedulable noderesources.NodeResource
+	nodes, schedulableNodes := 0, 0
+	for _, group := range groups {
+		for _, resource := range group.Items {
+			t.AppendRow(clusterRow(withCluster, resource.Cluster, cs.dataRow(resource, outputResources)))
+			for _, qos := range resource.QOS {
+				t.AppendRow(clusterRow(withCluster, "", cs.qosRow(resource, qos, outputResources)))
+			}
+			t.AppendSeparator()
+			total.Add(resource)
+			nodes++
+			if resource.Schedulable {
+				schedulable.Add(resource)
+				schedulableNodes++
+			}
 		}
-		if cs.Available {
-			total.AvailableCPU += resource.AvailableCPU
-			total.AvailableMemory += resource.AvailableMemory
-		}
-		if cs.Free {
-			total.FreeCPU += resource.FreeCPU
-			total.FreeMemory += resource.FreeMemory
-			total.FreeStorage += resource.FreeStorage
-			total.FreeStorageEphemeral += resource.FreeStorageEphemeral
+		if subtotals {
+			label := "Subtotal " + formatnoderesources.GroupValueString(group)
+			row := cs.totalRow(label, formatnoderesources.GroupNodesString(group), group.Total, outputResources)
+			t.AppendRow(clusterRow(withCluster, group.Cluster, row))
+			t.AppendSeparator()
 		}
 	}
-	t.AppendRow(cs.headerFooterRow(outputResources, "Total"))
+	t.AppendRow(clusterRow(withCluster, "", cs.headerFooterRow(outputResources, "Total", "")))
 	t.AppendSeparator()
-	t.AppendFooter(cs.dataRow(total, outputResources))
+	t.AppendFooter(clusterRow(withCluster, "", cs.totalRow("", "", total, outputResources)))
+	if nodes > 1 && schedulableNodes < nodes {
+		row := cs.totalRow("Schedulable", schedulableNote(schedulableNodes, nodes), schedulable, outputResources)
+		t.AppendFooter(clusterRow(withCluster, "", row))
+	}
 	t.Render()
 }
 
-func configureExpandedTable(t table.Writer) {
+// schedulableNote tells how many of the listed nodes the schedulable total covers.
+func schedulableNote(schedulable, nodes int) string {
+	return fmt.Sprintf("%d/%d nodes", schedulable, nodes)
+}
+
+func configureExpandedTable(t table.Writer, extendedColumns int, withCluster bool) {
 	applyTableStyle(t)
-	t.SetColumnConfigs(expandedColumnConfigs())
+	t.SetColumnConfigs(clusterColumnConfigs(withCluster, expandedColumnConfigs(extendedColumns)))
 }
 
-func expandedColumnConfigs() []table.ColumnConfig {
+func expandedColumnConfigs(extendedColumns int) []table.ColumnConfig {
 	configs := []table.ColumnConfig{{
 		Number:      expandedNodeNameColumn,
 		Align:       text.AlignLeft,
 		AlignHeader: text.AlignLeft,
 		AlignFooter: text.AlignLeft,
+	}, {
+		Number:      expandedNodeStatusColumn,
+		Align:       text.AlignLeft,
+		AlignHeader: text.AlignLeft,
+		AlignFooter: text.AlignLeft,
 	}}
-	for number := expandedNodeFirstMetric; number <= expandedNodeMaxMetric; number++ {
+	for number := expandedNodeFirstMetric; number <= expandedNodeMaxMetric+extendedColumns; number++ {
 		configs = append(configs, table.ColumnConfig{
 			Number:      number,
 			Align:       text.AlignRight,
@@ -8,38 +8,50 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/trezorg/k8spodsmetrics/internal/columns"
 	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
 	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	"github.com/trezorg/k8spodsmetrics/pkg/nodes"
+	v1 "k8s.io/api/core/v1"
 )
 
 func TestHeaderFooter(t *testing.T) {
 	t.Run("with CPU only", func(t *testing.T) {
 		outputResources := resources.Resources{resources.CPU}
 		cs := newColumnSet(nil)
-		result := cs.headerFooterRow(outputResources, "Test")
-		require.Len(t, result, 8)
+		result := cs.headerFooterRow(outputResources, "Test", "Status")
+		require.Len(t, result, 9)
 		require.Equal(t, "Test", result[0])
-		require.Equal(t, "CPU Total", result[1])
+		require.Equal(t, "Status", result[1])
+		require.Equal(t, "CPU Total", result[2])
 	})
 
 	t.Run("with Memory only", func(t *testing.T) {
 		outputResources := resources.Resources{resources.Memory}
 		cs := newColumnSet(nil)
-		result := cs.headerFooterRow(outputResources, "Test")
-		require.Len(t, result, 8)
+		result := cs.headerFooterRow(outputResources, "Test", "Status")
+		require.Len(t, result, 9)
 		require.Equal(t, "Test", result[0])
-		require.Equal(t, "Memory Total", result[1])
+		require.Equal(t, "Memory Total", result[2])
 	})
 
 	t.Run("with all resources", func(t *testing.T) {
 		outputResources := resources.Resources{resources.All}
 		cs := newColumnSet(nil)
-		result := cs.headerFooterRow(outputResources, "Test")
-		require.Len(t, result, 23)
+		result := cs.headerFooterRow(outputResources, "Test", "Status")
+		require.Len(t, result, 27)
 		require.Equal(t, "Test", result[0])
-		require.Equal(t, "CPU Total", result[1])
+		require.Equal(t, "CPU Total", result[2])
+		require.Equal(t, "Pods Free", result[26])
+	})
+
+	t.Run("with pods only", func(t *testing.T) {
+		outputResources := resources.Resources{resources.Pods}
+		cs := newColumnSet([]columns.Column{columns.Allocatable, columns.Used, columns.Free, columns.Request})
+		result := cs.headerFooterRow(outputResources, "Test", "Status")
+		require.Equal(t, table.Row{"Test", "Status", "Pods Allocatable", "Pods Running", "Pods Free"}, result)
 	})
 }
 
@@ -54,7 +66,7 @@ func TestRow(t *testing.T) {
 			UsedCPU:        100,
 		}
 		result := cs.dataRow(resource, outputResources)
-		require.Len(t, result, 8)
+		require.Len(t, result, 9)
 		require.Equal(t, "node-1", result[0])
 	})
 
@@ -68,7 +80,7 @@ func TestRow(t *testing.T) {
 			UsedMemory:        1024 * 1024 * 1024,
 		}
 		result := cs.dataRow(resource, outputResources)
-		require.Len(t, result, 8)
+		require.Len(t, result, 9)
 		require.Equal(t, "node-1", result[0])
 	})
 }
@@ -183,20 +195,141 @@ func TestPrintToExpandedSingleHeaderAndTotalFooter(t *testing.T) {
 	require.Equal(t, 1, strings.Count(cleanOutput, "CPU TOTAL"))
 	require.Equal(t, 1, strings.Count(cleanOutput, "CPU ALLOCATABLE"))
 	require.Equal(t, 1, strings.Count(cleanOutput, "CPU USED"))
-	require.Regexp(t, regexp.MustCompile(`(?s)│ Total │ CPU Total │ CPU Allocatable │ CPU Used │ CPU Request │ CPU Limit │ CPU Available │ CPU Free │\n├[-┼┤├─]+\n│       │         0 │               0 │        0 │           0 │         0 │             0 │        0 │`), cleanOutput)
+	require.Regexp(t, regexp.MustCompile(`(?s)│ Total │        │ CPU Total │ CPU Allocatable │ CPU Used │ CPU Request │ CPU Limit │ CPU Available │ CPU Free │\n├[-┼┤├─]+\n│       │        │         0 │               0 │        0 │           0 │         0 │             0 │        0 │`), cleanOutput)
+}
+
+func TestPrintToExpandedExtendedResources(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{Name: "gpu-1", Ready: true, Schedulable: true, Extended: map[string]noderesources.ExtendedResource{"nvidia.com/gpu": {Capacity: 4, Allocatable: 4, Request: 1, Limit: 1, Available: 3}}},
+		{Name: "gpu-2", Ready: true, Schedulable: true, Extended: map[string]noderesources.ExtendedResource{"nvidia.com/gpu": {Capacity: 8, Allocatable: 8, Request: 2, Limit: 2, Available: 6}}},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list, resources.Resources{"nvidia.com/gpu"}, newColumnSet(nil))
+
+	output := buf.String()
+	require.Contains(t, output, "NVIDIA.COM/GPU TOTAL")
+	require.Contains(t, output, "NVIDIA.COM/GPU AVAILABLE")
+	require.NotContains(t, output, "NVIDIA.COM/GPU USED")
+	require.NotContains(t, output, "NVIDIA.COM/GPU FREE")
+	require.Regexp(t, regexp.MustCompile(`│ gpu-1 +│ Ready +│ +4 │ +4 │ +1 │ +1 │ +3 │`), output)
+	require.Regexp(t, regexp.MustCompile(`│ +│ +│ +12 │ +12 │ +3 │ +3 │ +9 │`), output)
+}
+
+func TestQOSRow(t *testing.T) {
+	resource := noderesources.NodeResource{AllocatableCPU: 4000, AllocatableMemory: 4096}
+	group := noderesources.QOSResource{Class: v1.PodQOSBurstable, Pods: 3, CPURequest: 1000, CPULimit: 2000, MemoryRequest: 1024, MemoryLimit: 2048}
+	outputResources := resources.Resources{resources.All}
+
+	t.Run("fills request and limit cells only", func(t *testing.T) {
+		cs := newColumnSet(nil)
+		row := cs.qosRow(resource, group, outputResources)
+		require.Len(t, row, len(cs.headerFooterRow(outputResources, "", "")))
+		require.Equal(t, "└─ Burstable (3 pods)", row[0])
+		require.Equal(t, "", row[1])
+		require.Equal(t, table.Row{"", "", "", "1000 (25%)", "2000 (50%)", "", ""}, row[2:9])
+		require.Equal(t, table.Row{"", "", "", "1KiB (25%)", "2KiB (50%)", "", ""}, row[9:16])
+		for _, cell := range row[16:] {
+			require.Equal(t, "", cell)
+		}
+	})
+
+	t.Run("respects selected columns", func(t *testing.T) {
+		cs := newColumnSet([]columns.Column{columns.Used, columns.Request})
+		row := cs.qosRow(resource, group, resources.Resources{resources.CPU})
+		require.Equal(t, table.Row{"└─ Burstable (3 pods)", "", "", "1000 (25%)"}, row)
+	})
+}
+
+func TestPrintToRendersQOSRows(t *testing.T) {
+	list := noderesources.NodeResourceList{{
+		Name:           "node-1",
+		AllocatableCPU: 1000,
+		Ready:          true,
+		Schedulable:    true,
+		QOS: []noderesources.QOSResource{
+			{Class: v1.PodQOSGuaranteed, Pods: 1, CPURequest: 500, CPULimit: 500},
+			{Class: v1.PodQOSBurstable},
+			{Class: v1.PodQOSBestEffort, Pods: 2},
+		},
+	}}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list, resources.Resources{resources.CPU}, newColumnSet(nil))
+
+	output := buf.String()
+	require.Regexp(t, regexp.MustCompile(`│ └─ Guaranteed \(1 pods\) +│ +│ +│ +│ +│ +500 \(50%\) │ +500 \(50%\) │ +│ +│`), output)
+	require.Contains(t, output, "└─ Burstable (0 pods)")
+	require.Contains(t, output, "└─ BestEffort (2 pods)")
+}
+
+func TestPrintToSchedulableFooter(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{Name: "worker", AllocatableCPU: 1000, Ready: true, Schedulable: true},
+		{Name: "control-plane", AllocatableCPU: 500, Ready: true, Taints: []nodes.Taint{{Key: "node-role.kubernetes.io/control-plane", Effect: v1.TaintEffectNoSchedule}}},
+		{Name: "broken", AllocatableCPU: 2000, Conditions: []string{"DiskPressure"}},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list, resources.Resources{resources.CPU}, newColumnSet([]columns.Column{columns.Allocatable}))
+
+	output := regexp.MustCompile(`\x1b\[[0-9;]*m`).ReplaceAllString(buf.String(), "")
+	require.Regexp(t, `│ control-plane │ Ready,Tainted +│ +500 │`, output)
+	require.Regexp(t, `│ broken +│ NotReady,DiskPressure │ +2000 │`, output)
+	require.Regexp(t, `│ +│ +│ +3500 │`, output)
+	require.Regexp(t, `│ SCHEDULABLE +│ 1/3 NODES +│ +1000 │`, output)
 }
 
 func TestExpandedColumnConfigs(t *testing.T) {
-	configs := expandedColumnConfigs()
+	configs := expandedColumnConfigs(0)
 	require.Len(t, configs, expandedNodeMaxMetric)
 
-	require.Equal(t, text.AlignLeft, configs[0].Align)
-	require.Equal(t, text.AlignLeft, configs[0].AlignHeader)
-	require.Equal(t, text.AlignLeft, configs[0].AlignFooter)
+	for _, config := range configs[:2] {
+		require.Equal(t, text.AlignLeft, config.Align)
+		require.Equal(t, text.AlignLeft, config.AlignHeader)
+		require.Equal(t, text.AlignLeft, config.AlignFooter)
+	}
 
-	for _, config := range configs[1:] {
+	for _, config := range configs[2:] {
 		require.Equal(t, text.AlignRight, config.Align)
 		require.Equal(t, text.AlignRight, config.AlignHeader)
 		require.Equal(t, text.AlignRight, config.AlignFooter)
 	}
 }
+
+func TestPrintGroupsTo(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{Name: "node-1", AllocatableCPU: 1000, Ready: true, Schedulable: true, Labels: map[string]string{"pool": "general"}},
+		{Name: "node-2", AllocatableCPU: 2000, Ready: true, Unschedulable: true, Labels: map[string]string{"pool": "general"}},
+		{Name: "node-3", AllocatableCPU: 4000, Ready: true, Schedulable: true, Labels: map[string]string{"pool": "gpu"}},
+	}
+
+	var buf bytes.Buffer
+	PrintGroupsTo(&buf, list, resources.Resources{resources.CPU}, newColumnSet([]columns.Column{columns.Allocatable}), "pool")
+
+	output := regexp.MustCompile(`\x1b\[[0-9;]*m`).ReplaceAllString(buf.String(), "")
+	require.Contains(t, output, "Grouped by pool")
+	require.Regexp(t, `│ Subtotal general │ 2 nodes, 1 schedulable +│ +3000 │`, output)
+	require.Regexp(t, `│ Subtotal gpu +│ 1 nodes +│ +4000 │`, output)
+	require.Regexp(t, `│ +│ +│ +7000 │`, output)
+	require.Regexp(t, `│ SCHEDULABLE +│ 2/3 NODES +│ +5000 │`, output)
+}
+
+func TestPrintToClusters(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{Cluster: "prod-us", Name: "node-1", AllocatableCPU: 1000, Ready: true, Schedulable: true},
+		{Cluster: "prod-us", Name: "node-2", AllocatableCPU: 2000, Ready: true, Schedulable: true},
+		{Cluster: "prod-eu", Name: "node-1", AllocatableCPU: 4000, Ready: true, Schedulable: true},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list, resources.Resources{resources.CPU}, newColumnSet([]columns.Column{columns.Allocatable}))
+
+	output := regexp.MustCompile(`\x1b\[[0-9;]*m`).ReplaceAllString(buf.String(), "")
+	require.Regexp(t, `│ CLUSTER +│ NAME +│ STATUS +│ CPU ALLOCATABLE │`, output)
+	require.Regexp(t, `│ prod-us +│ node-1 +│`, output)
+	require.Regexp(t, `│ prod-us +│ Subtotal prod-us │ 2 nodes +│ +3000 │`, output)
+	require.Regexp(t, `│ prod-eu +│ Subtotal prod-eu │ 1 nodes +│ +4000 │`, output)
+	require.Regexp(t, `│ +│ +│ +│ +7000 │`, output)
+	require.NotContains(t, output, "Grouped by")
+}
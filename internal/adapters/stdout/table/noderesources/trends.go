@@ -0,0 +1,88 @@
+package noderesources
+
+import (
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/trend"
+	"github.com/trezorg/k8spodsmetrics/internal/history"
+	servicenoderesources "github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+)
+
+// compactMetricColumns is the number of CPU and of memory columns in compact
+// rows, the capacity and the demand.
+const compactMetricColumns = 2
+
+// compactTrends inserts a trend column after the CPU and the memory columns
+// of compact rows. Without usage history rows are left as they are.
+type compactTrends struct {
+	usage           *history.History
+	outputResources resources.Resources
+}
+
+func (c compactTrends) columns() int {
+	if c.usage == nil {
+		return 0
+	}
+	columns := 0
+	if c.outputResources.IsCPU() {
+		columns++
+	}
+	if c.outputResources.IsMemory() {
+		columns++
+	}
+	return columns
+}
+
+func (c compactTrends) header(row table.Row) table.Row {
+	return c.insert(row, "CPU TREND", "MEM TREND")
+}
+
+func (c compactTrends) node(resource servicenoderesources.NodeResource, row table.Row) table.Row {
+	if c.usage == nil {
+		return row
+	}
+	key := nodeKey(resource)
+	return c.insert(row, trend.String(c.usage.CPU(key)), trend.String(c.usage.Memory(key)))
+}
+
+func (c compactTrends) blank(row table.Row) table.Row {
+	return c.insert(row, "", "")
+}
+
+func (c compactTrends) insert(row table.Row, cpu, memory string) table.Row {
+	if c.usage == nil {
+		return row
+	}
+	// The metric columns follow the NAME and STATUS columns.
+	index := compactStatusColumn
+	result := append(table.Row{}, row[:index]...)
+	if c.outputResources.IsCPU() {
+		result = append(result, row[index:index+compactMetricColumns]...)
+		result = append(result, cpu)
+		index += compactMetricColumns
+	}
+	if c.outputResources.IsMemory() {
+		result = append(result, row[index:index+compactMetricColumns]...)
+		result = append(result, memory)
+		index += compactMetricColumns
+	}
+	return append(result, row[index:]...)
+}
+
+// nodeSamples returns the usage of every node keyed by cluster and name,
+// unknown for nodes without metrics.
+func nodeSamples(list servicenoderesources.NodeResourceList) map[string]history.Sample {
+	samples := make(map[string]history.Sample, len(list))
+	for _, resource := range list {
+		sample := history.Sample{CPU: resource.UsedCPU, Memory: resource.UsedMemory}
+		if resource.MetricsMissing || resource.MetricsUnavailable {
+			sample = history.Sample{CPU: history.Unknown, Memory: history.Unknown}
+		}
+		samples[nodeKey(resource)] = sample
+	}
+	return samples
+}
+
+func nodeKey(resource servicenoderesources.NodeResource) string {
+	return resource.Cluster + "/" + resource.Name
+}
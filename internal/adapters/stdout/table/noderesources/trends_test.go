@@ -0,0 +1,81 @@
+package noderesources
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/history"
+	servicenoderesources "github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+)
+
+func TestCompactTrendsInsert(t *testing.T) {
+	tests := []struct {
+		name            string
+		outputResources resources.Resources
+		row             table.Row
+		want            table.Row
+	}{
+		{
+			name:            "cpu and memory",
+			outputResources: resources.Resources{resources.All},
+			row:             table.Row{"node", "Ready", "cpu", "cpu req", "mem", "mem req", "pods"},
+			want:            table.Row{"node", "Ready", "cpu", "cpu req", "c", "mem", "mem req", "m", "pods"},
+		},
+		{
+			name:            "cpu only",
+			outputResources: resources.Resources{resources.CPU},
+			row:             table.Row{"node", "Ready", "cpu", "cpu req"},
+			want:            table.Row{"node", "Ready", "cpu", "cpu req", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trends := compactTrends{usage: history.New(3), outputResources: tt.outputResources}
+			require.Equal(t, tt.want, trends.insert(tt.row, "c", "m"))
+		})
+	}
+}
+
+func TestNodeSamples(t *testing.T) {
+	missing := testSecondCompactNodeResource()
+	missing.MetricsMissing = true
+
+	samples := nodeSamples(servicenoderesources.NodeResourceList{testCompactNodeResource(), missing})
+
+	require.Equal(t, history.Sample{CPU: 1200, Memory: 3 * 1024}, samples["/node-a"])
+	require.Equal(t, history.Sample{CPU: history.Unknown, Memory: history.Unknown}, samples["/node-b"])
+}
+
+func TestToCompactTrendWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+	}{
+		{name: "ungrouped"},
+		{name: "grouped by label", label: "zone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			write := ToCompactTrendWriter(resources.Resources{resources.CPU, resources.Memory}, tt.label, history.New(5))
+			first, second := testCompactNodeResource(), testSecondCompactNodeResource()
+			first.Labels = map[string]string{"zone": "a"}
+			second.Labels = map[string]string{"zone": "a"}
+
+			write(&bytes.Buffer{}, servicenoderesources.NodeResourceList{first, second})
+			first.UsedCPU = 600
+			var buf bytes.Buffer
+			write(&buf, servicenoderesources.NodeResourceList{first, second})
+
+			output := buf.String()
+			require.Contains(t, output, "CPU TREND")
+			require.Contains(t, output, "MEM TREND")
+			require.Contains(t, output, "█▄ ↓")
+			require.Contains(t, output, "██ →")
+			require.Contains(t, output, "TOTAL")
+		})
+	}
+}
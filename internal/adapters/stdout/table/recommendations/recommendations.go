@@ -0,0 +1,100 @@
+package recommendations
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	formatrecommendations "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/recommendations"
+	"github.com/trezorg/k8spodsmetrics/internal/recommendations"
+)
+
+const (
+	namespaceColumn = 1
+	workloadColumn  = 2
+	containerColumn = 3
+	samplesColumn   = 4
+	maxColumns      = 10
+)
+
+type Table func(list recommendations.RecommendationList)
+
+func ToTable() Table {
+	return Table(func(list recommendations.RecommendationList) {
+		PrintTo(os.Stdout, list)
+	})
+}
+
+// PrintTo renders current and recommended resources followed by the patches
+// applying the recommendations.
+func PrintTo(w io.Writer, list recommendations.RecommendationList) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	configureTable(t)
+	t.AppendHeader(headerRow())
+	for _, recommendation := range list {
+		t.AppendRow(recommendationRow(recommendation))
+	}
+	t.Render()
+
+	patches := list.Patches()
+	if len(patches) == 0 {
+		return
+	}
+	_, _ = io.WriteString(w, "\n")
+	formatrecommendations.PatchesTo(w, patches)
+}
+
+func headerRow() table.Row {
+	return table.Row{
+		"NAMESPACE",
+		"WORKLOAD",
+		"CONTAINER",
+		"SAMPLES",
+		"CPU(p50/p90/p99/max)",
+		"CPU REQ",
+		"CPU LIM",
+		"MEM(p50/p90/p99/max)",
+		"MEM REQ",
+		"MEM LIM",
+	}
+}
+
+func recommendationRow(recommendation recommendations.Recommendation) table.Row {
+	formatter := formatrecommendations.New(recommendation)
+	return table.Row{
+		recommendation.Namespace,
+		formatter.NameString(),
+		formatter.ContainerString(),
+		formatter.SamplesString(),
+		formatter.CPUUsageString(),
+		formatter.CPURequestString(),
+		formatter.CPULimitString(),
+		formatter.MemoryUsageString(),
+		formatter.MemoryRequestString(),
+		formatter.MemoryLimitString(),
+	}
+}
+
+func configureTable(t table.Writer) {
+	t.SetStyle(table.StyleLight)
+	configs := []table.ColumnConfig{
+		{Number: namespaceColumn, Align: text.AlignLeft},
+		{Number: workloadColumn, Align: text.AlignLeft},
+		{Number: containerColumn, Align: text.AlignLeft},
+	}
+	for number := samplesColumn; number <= maxColumns; number++ {
+		configs = append(configs, table.ColumnConfig{Number: number, Align: text.AlignRight})
+	}
+	t.SetColumnConfigs(configs)
+}
+
+func (s Table) Success(list recommendations.RecommendationList) {
+	s(list)
+}
+
+func (Table) Error(err error) {
+	slog.Error("table recommendations output failed", "error", err)
+}
@@ -0,0 +1,57 @@
+package recommendations
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/internal/recommendations"
+)
+
+func testRecommendations() recommendations.RecommendationList {
+	return recommendations.RecommendationList{
+		{
+			Namespace: "default",
+			Kind:      "Deployment",
+			Workload:  "web",
+			Container: "app",
+			Samples:   3,
+			Usage: recommendations.Usage{
+				CPU: recommendations.Percentiles{P50: 100, P90: 200, P99: 250, Max: 300},
+			},
+			Current: recommendations.Resources{Requests: recommendations.Values{CPU: 500}},
+			Recommended: recommendations.Resources{
+				Requests: recommendations.Values{CPU: 200, Memory: 64 << 20},
+				Limits:   recommendations.Values{CPU: 360, Memory: 128 << 20},
+			},
+		},
+		{Namespace: "default", Kind: "Pod", Workload: "debug", Container: "shell", Samples: 1},
+	}
+}
+
+func TestRecommendationRow(t *testing.T) {
+	row := recommendationRow(testRecommendations()[0])
+	require.Equal(t, []any{
+		"default", "Deployment/web", "app", "3", "100/200/250/300", "500 → 200", "- → 360", "0B/0B/0B/0B", "- → 64MiB", "- → 128MiB",
+	}, []any(row))
+}
+
+func TestPrintTo(t *testing.T) {
+	t.Run("prints the table and the patches", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintTo(&buf, testRecommendations())
+
+		output := buf.String()
+		require.Contains(t, output, "CPU(P50/P90/P99/MAX)")
+		require.Contains(t, output, "Pod/debug")
+		require.Contains(t, output, "# kubectl -n default patch deployment web")
+		require.NotContains(t, output, "patch pod debug")
+	})
+
+	t.Run("omits patches of unpatchable workloads", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintTo(&buf, testRecommendations()[1:])
+
+		require.NotContains(t, buf.String(), "kubectl")
+	})
+}
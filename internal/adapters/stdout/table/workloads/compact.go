@@ -0,0 +1,98 @@
+package workloads
+
+import (
+	"io"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	formatworkloads "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/workloads"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+)
+
+const (
+	compactNamespaceColumn = 1
+	compactWorkloadColumn  = 2
+	compactReplicasColumn  = 3
+	maxCompactColumns      = 7
+)
+
+func ToCompactTable(outputResources resources.Resources) Table {
+	return Table(func(list workloads.WorkloadList) {
+		PrintCompactTo(os.Stdout, list, outputResources)
+	})
+}
+
+func ToCompactWriter(outputResources resources.Resources) func(io.Writer, workloads.WorkloadList) {
+	return func(w io.Writer, list workloads.WorkloadList) {
+		PrintCompactTo(w, list, outputResources)
+	}
+}
+
+func PrintCompactTo(w io.Writer, list workloads.WorkloadList, outputResources resources.Resources) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	configureCompactTable(t)
+	t.AppendHeader(compactHeaderRow(outputResources))
+
+	var total workloads.Workload
+	for _, workload := range list {
+		t.AppendRow(compactWorkloadRow(workload, outputResources))
+		accumulateTotal(&total, workload)
+	}
+
+	if len(list) > 1 {
+		t.AppendFooter(compactTotalRow(total, outputResources))
+	}
+
+	t.Render()
+}
+
+func compactHeaderRow(outputResources resources.Resources) table.Row {
+	row := table.Row{"NAMESPACE", "WORKLOAD", "REPLICAS"}
+	if outputResources.IsCPU() {
+		row = append(row, "CPU(req/used/lim)", "CPU USED(avg/min/max)")
+	}
+	if outputResources.IsMemory() {
+		row = append(row, "MEM(req/used/lim)", "MEM USED(avg/min/max)")
+	}
+	return row
+}
+
+func compactWorkloadRow(workload workloads.Workload, outputResources resources.Resources) table.Row {
+	formatter := formatworkloads.New(workload)
+	row := table.Row{workload.Namespace, formatter.NameString(), formatter.ReplicasString()}
+	if outputResources.IsCPU() {
+		row = append(row, formatter.CPUCompactString(), formatter.CPUUsedStatsString())
+	}
+	if outputResources.IsMemory() {
+		row = append(row, formatter.MemoryCompactString(), formatter.MemoryUsedStatsString())
+	}
+	return row
+}
+
+func compactTotalRow(total workloads.Workload, outputResources resources.Resources) table.Row {
+	formatter := formatworkloads.New(total)
+	row := table.Row{"TOTAL", "", formatter.ReplicasString()}
+	if outputResources.IsCPU() {
+		row = append(row, formatter.CPUCompactString(), "")
+	}
+	if outputResources.IsMemory() {
+		row = append(row, formatter.MemoryCompactString(), "")
+	}
+	return row
+}
+
+func configureCompactTable(t table.Writer) {
+	applyTableStyle(t)
+	configs := []table.ColumnConfig{
+		{Number: compactNamespaceColumn, Align: text.AlignLeft},
+		{Number: compactWorkloadColumn, Align: text.AlignLeft},
+		{Number: compactReplicasColumn, Align: text.AlignRight},
+	}
+	for number := compactReplicasColumn + 1; number <= maxCompactColumns; number++ {
+		configs = append(configs, table.ColumnConfig{Number: number, Align: text.AlignRight})
+	}
+	t.SetColumnConfigs(configs)
+}
@@ -0,0 +1,41 @@
+package workloads
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+)
+
+func TestCompactHeaderRow(t *testing.T) {
+	row := compactHeaderRow(resources.Resources{resources.All})
+	require.Equal(t, []any{"NAMESPACE", "WORKLOAD", "REPLICAS", "CPU(req/used/lim)", "CPU USED(avg/min/max)", "MEM(req/used/lim)", "MEM USED(avg/min/max)"}, []any(row))
+}
+
+func TestCompactWorkloadRow(t *testing.T) {
+	row := compactWorkloadRow(testWorkloads()[0], resources.Resources{resources.CPU})
+	require.Equal(t, []any{"default", "Deployment/web", "2", "300/50/600", "25/10/40"}, []any(row))
+}
+
+func TestPrintCompactToIncludesTotalFooter(t *testing.T) {
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, testWorkloads(), resources.Resources{resources.CPU})
+
+	output := buf.String()
+	require.Contains(t, output, "CPU(REQ/USED/LIM)")
+	require.Contains(t, output, "TOTAL")
+	require.Contains(t, output, "400/50/600")
+	require.NotContains(t, output, "MEM(REQ/USED/LIM)")
+}
+
+func TestPrintCompactToSingleWorkloadHasNoFooter(t *testing.T) {
+	var buf bytes.Buffer
+	PrintCompactTo(&buf, workloads.WorkloadList{testWorkloads()[0]}, resources.Resources{resources.Memory})
+
+	output := buf.String()
+	require.NotContains(t, output, "TOTAL")
+	require.Contains(t, output, "2KiB/0B/0B")
+}
@@ -0,0 +1,209 @@
+package workloads
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	formatworkloads "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/workloads"
+	"github.com/trezorg/k8spodsmetrics/internal/columns"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+)
+
+const (
+	expandedNamespaceColumn = 1
+	expandedWorkloadColumn  = 2
+	expandedReplicasColumn  = 3
+	expandedFirstMetric     = 4
+	expandedMaxMetric       = 15
+	statsSuffix             = " avg/min/max"
+)
+
+type Table func(list workloads.WorkloadList)
+
+type ColumnSet struct {
+	Request bool
+	Limit   bool
+	Used    bool
+}
+
+func newColumnSet(cols []columns.Column) ColumnSet {
+	if len(cols) == 0 {
+		return ColumnSet{Request: true, Limit: true, Used: true}
+	}
+	cs := ColumnSet{}
+	for _, col := range cols {
+		//nolint:exhaustive // Total, Allocatable, Available, Free are node-only columns
+		switch col {
+		case columns.Request:
+			cs.Request = true
+		case columns.Limit:
+			cs.Limit = true
+		case columns.Used:
+			cs.Used = true
+		default:
+			// Node-only columns (Total, Allocatable, Available, Free) ignored for workloads
+		}
+	}
+	return cs
+}
+
+// appendResourceHeader adds a total and a per-replica statistics column for
+// each selected column.
+func (cs ColumnSet) appendResourceHeader(result table.Row, label string) table.Row {
+	if cs.Request {
+		result = append(result, label+" Request", label+" Request"+statsSuffix)
+	}
+	if cs.Limit {
+		result = append(result, label+" Limit", label+" Limit"+statsSuffix)
+	}
+	if cs.Used {
+		result = append(result, label+" Used", label+" Used"+statsSuffix)
+	}
+	return result
+}
+
+func (cs ColumnSet) headerFooterRow(outputResources resources.Resources, firstColumn string) table.Row {
+	result := table.Row{firstColumn, "Workload", "Replicas"}
+	if outputResources.IsCPU() {
+		result = cs.appendResourceHeader(result, "CPU")
+	}
+	if outputResources.IsMemory() {
+		result = cs.appendResourceHeader(result, "Memory")
+	}
+	return result
+}
+
+func (cs ColumnSet) appendCPUColumns(result table.Row, formatter formatworkloads.Formatter, total bool) table.Row {
+	if cs.Request {
+		result = append(result, formatter.CPURequestString(), statsOrEmpty(formatter.CPURequestStatsString, total))
+	}
+	if cs.Limit {
+		result = append(result, formatter.CPULimitString(), statsOrEmpty(formatter.CPULimitStatsString, total))
+	}
+	if cs.Used {
+		result = append(result, formatter.CPUUsedString(), statsOrEmpty(formatter.CPUUsedStatsString, total))
+	}
+	return result
+}
+
+func (cs ColumnSet) appendMemoryColumns(result table.Row, formatter formatworkloads.Formatter, total bool) table.Row {
+	if cs.Request {
+		result = append(result, formatter.MemoryRequestString(), statsOrEmpty(formatter.MemoryRequestStatsString, total))
+	}
+	if cs.Limit {
+		result = append(result, formatter.MemoryLimitString(), statsOrEmpty(formatter.MemoryLimitStatsString, total))
+	}
+	if cs.Used {
+		result = append(result, formatter.MemoryUsedString(), statsOrEmpty(formatter.MemoryUsedStatsString, total))
+	}
+	return result
+}
+
+func (cs ColumnSet) dataRow(workload workloads.Workload, outputResources resources.Resources) table.Row {
+	formatter := formatworkloads.New(workload)
+	result := table.Row{workload.Namespace, formatter.NameString(), formatter.ReplicasString()}
+	if outputResources.IsCPU() {
+		result = cs.appendCPUColumns(result, formatter, false)
+	}
+	if outputResources.IsMemory() {
+		result = cs.appendMemoryColumns(result, formatter, false)
+	}
+	return result
+}
+
+// totalRow shows summed totals. Per-replica statistics of different
+// workloads are not comparable and are left empty.
+func (cs ColumnSet) totalRow(total workloads.Workload, outputResources resources.Resources) table.Row {
+	formatter := formatworkloads.New(total)
+	result := table.Row{"", "", formatter.ReplicasString()}
+	if outputResources.IsCPU() {
+		result = cs.appendCPUColumns(result, formatter, true)
+	}
+	if outputResources.IsMemory() {
+		result = cs.appendMemoryColumns(result, formatter, true)
+	}
+	return result
+}
+
+func statsOrEmpty(stats func() string, total bool) string {
+	if total {
+		return ""
+	}
+	return stats()
+}
+
+func ToTable(outputResources resources.Resources, cols []columns.Column) Table {
+	cs := newColumnSet(cols)
+	return Table(func(list workloads.WorkloadList) {
+		PrintTo(os.Stdout, list, outputResources, cs)
+	})
+}
+
+func ToWriter(outputResources resources.Resources, cols []columns.Column) func(io.Writer, workloads.WorkloadList) {
+	cs := newColumnSet(cols)
+	return func(w io.Writer, list workloads.WorkloadList) {
+		PrintTo(w, list, outputResources, cs)
+	}
+}
+
+func PrintTo(w io.Writer, list workloads.WorkloadList, outputResources resources.Resources, cs ColumnSet) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	configureExpandedTable(t)
+	t.AppendHeader(cs.headerFooterRow(outputResources, "Namespace"))
+	var total workloads.Workload
+	for _, workload := range list {
+		t.AppendRow(cs.dataRow(workload, outputResources))
+		t.AppendSeparator()
+		accumulateTotal(&total, workload)
+	}
+	t.AppendRow(cs.headerFooterRow(outputResources, "Total"))
+	t.AppendSeparator()
+	t.AppendFooter(cs.totalRow(total, outputResources))
+	t.Render()
+}
+
+func accumulateTotal(total *workloads.Workload, workload workloads.Workload) {
+	total.Replicas += workload.Replicas
+	total.MetricsMissing += workload.MetricsMissing
+	total.Requests.CPU.Total += workload.Requests.CPU.Total
+	total.Requests.Memory.Total += workload.Requests.Memory.Total
+	total.Limits.CPU.Total += workload.Limits.CPU.Total
+	total.Limits.Memory.Total += workload.Limits.Memory.Total
+	total.Used.CPU.Total += workload.Used.CPU.Total
+	total.Used.Memory.Total += workload.Used.Memory.Total
+}
+
+func configureExpandedTable(t table.Writer) {
+	applyTableStyle(t)
+	configs := []table.ColumnConfig{
+		{Number: expandedNamespaceColumn, Align: text.AlignLeft, AlignHeader: text.AlignLeft, AlignFooter: text.AlignLeft},
+		{Number: expandedWorkloadColumn, Align: text.AlignLeft, AlignHeader: text.AlignLeft, AlignFooter: text.AlignLeft},
+		{Number: expandedReplicasColumn, Align: text.AlignRight, AlignHeader: text.AlignRight, AlignFooter: text.AlignRight},
+	}
+	for number := expandedFirstMetric; number <= expandedMaxMetric; number++ {
+		configs = append(configs, table.ColumnConfig{
+			Number:      number,
+			Align:       text.AlignRight,
+			AlignHeader: text.AlignRight,
+			AlignFooter: text.AlignRight,
+		})
+	}
+	t.SetColumnConfigs(configs)
+}
+
+func applyTableStyle(t table.Writer) {
+	t.SetStyle(table.StyleLight)
+}
+
+func (s Table) Success(list workloads.WorkloadList) {
+	s(list)
+}
+
+func (Table) Error(err error) {
+	slog.Error("table workloads output failed", "error", err)
+}
@@ -0,0 +1,72 @@
+package workloads
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/columns"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+)
+
+func testWorkloads() workloads.WorkloadList {
+	return workloads.WorkloadList{
+		{
+			Namespace: "default",
+			Kind:      "Deployment",
+			Name:      "web",
+			Replicas:  2,
+			Requests: workloads.Resources{
+				CPU:    workloads.Stats{Total: 300, Average: 150, Min: 100, Max: 200},
+				Memory: workloads.Stats{Total: 2048, Average: 1024, Min: 1024, Max: 1024},
+			},
+			Limits: workloads.Resources{CPU: workloads.Stats{Total: 600, Average: 300, Min: 200, Max: 400}},
+			Used:   workloads.Resources{CPU: workloads.Stats{Total: 50, Average: 25, Min: 10, Max: 40}},
+		},
+		{
+			Namespace:      "ops",
+			Kind:           "CronJob",
+			Name:           "backup",
+			Replicas:       1,
+			MetricsMissing: 1,
+			Requests:       workloads.Resources{CPU: workloads.Stats{Total: 100, Average: 100, Min: 100, Max: 100}},
+		},
+	}
+}
+
+func TestHeaderFooterRow(t *testing.T) {
+	cs := newColumnSet(nil)
+	row := cs.headerFooterRow(resources.Resources{resources.All}, "Namespace")
+	require.Len(t, row, expandedMaxMetric)
+	require.Equal(t, "CPU Request", row[3])
+	require.Equal(t, "CPU Request avg/min/max", row[4])
+
+	cs = newColumnSet([]columns.Column{columns.Used})
+	row = cs.headerFooterRow(resources.Resources{resources.Memory}, "Namespace")
+	require.Equal(t, []any{"Namespace", "Workload", "Replicas", "Memory Used", "Memory Used avg/min/max"}, []any(row))
+}
+
+func TestDataRow(t *testing.T) {
+	cs := newColumnSet(nil)
+	row := cs.dataRow(testWorkloads()[0], resources.Resources{resources.CPU})
+	require.Equal(t, []any{"default", "Deployment/web", "2", "300", "150/100/200", "600", "300/200/400", "50", "25/10/40"}, []any(row))
+
+	row = cs.dataRow(testWorkloads()[1], resources.Resources{resources.CPU})
+	require.Equal(t, "1 (1 no metrics)", row[2])
+	require.Empty(t, row[7])
+	require.Empty(t, row[8])
+}
+
+func TestPrintTo(t *testing.T) {
+	var buf bytes.Buffer
+	PrintTo(&buf, testWorkloads(), resources.Resources{resources.CPU}, newColumnSet([]columns.Column{columns.Request}))
+
+	output := buf.String()
+	require.Contains(t, output, "CPU REQUEST AVG/MIN/MAX")
+	require.Contains(t, output, "Deployment/web")
+	require.Contains(t, output, "CronJob/backup")
+	require.Contains(t, output, "400")
+	require.NotContains(t, output, "CPU Limit")
+}
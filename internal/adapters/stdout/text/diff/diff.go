@@ -0,0 +1,98 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	formatdiff "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/diff"
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+)
+
+type Text func(report diff.Report)
+
+func Print(report diff.Report) {
+	PrintTo(os.Stdout, report)
+}
+
+// PrintTo writes a line for every added, removed or changed pod, workload,
+// container and node with the values that differ.
+func PrintTo(w io.Writer, report diff.Report) {
+	var builder strings.Builder
+	if report.Empty() {
+		builder.WriteString("no changes\n")
+	}
+	for _, pod := range report.Pods {
+		kind := "pod"
+		if pod.Kind != "" {
+			kind = strings.ToLower(pod.Kind)
+		}
+		name := formatdiff.Name(pod.Cluster, pod.Namespace, pod.Name)
+		if pod.Change != diff.Changed || pod.Replicas != nil {
+			_, _ = fmt.Fprintf(&builder, "%s %s %s", pod.Change, kind, name)
+			writeValues(&builder, formatdiff.New(pod.Change), []value{
+				{name: "replicas", value: pod.Replicas, format: formatdiff.Count},
+			})
+		}
+		for _, container := range pod.Containers {
+			formatter := formatdiff.New(container.Change)
+			_, _ = fmt.Fprintf(&builder, "%s %s %s container %s", container.Change, kind, name, container.Name)
+			writeValues(&builder, formatter, []value{
+				{name: "cpu request", value: container.Requests.CPU, format: formatdiff.CPU},
+				{name: "cpu limit", value: container.Limits.CPU, format: formatdiff.CPU},
+				{name: "cpu used", value: container.Used.CPU, format: formatdiff.CPU},
+				{name: "memory request", value: container.Requests.Memory, format: formatdiff.Memory},
+				{name: "memory limit", value: container.Limits.Memory, format: formatdiff.Memory},
+				{name: "memory used", value: container.Used.Memory, format: formatdiff.Memory},
+			})
+		}
+	}
+	for _, node := range report.Nodes {
+		formatter := formatdiff.New(node.Change)
+		_, _ = fmt.Fprintf(&builder, "%s node %s", node.Change, formatdiff.Name(node.Cluster, node.Name))
+		writeValues(&builder, formatter, []value{
+			{name: "cpu allocatable", value: node.Allocatable.CPU, format: formatdiff.CPU},
+			{name: "cpu request", value: node.Requests.CPU, format: formatdiff.CPU},
+			{name: "cpu limit", value: node.Limits.CPU, format: formatdiff.CPU},
+			{name: "cpu used", value: node.Used.CPU, format: formatdiff.CPU},
+			{name: "memory allocatable", value: node.Allocatable.Memory, format: formatdiff.Memory},
+			{name: "memory request", value: node.Requests.Memory, format: formatdiff.Memory},
+			{name: "memory limit", value: node.Limits.Memory, format: formatdiff.Memory},
+			{name: "memory used", value: node.Used.Memory, format: formatdiff.Memory},
+		})
+	}
+	_, _ = io.WriteString(w, builder.String())
+}
+
+type value struct {
+	name   string
+	value  *diff.Value
+	format func(int64) string
+}
+
+// writeValues ends the line with the values that are set.
+func writeValues(builder *strings.Builder, formatter formatdiff.Formatter, values []value) {
+	separator := ": "
+	for _, v := range values {
+		if v.value == nil {
+			continue
+		}
+		builder.WriteString(separator + v.name + " " + formatter.PlainValueString(v.value, v.format))
+		separator = ", "
+	}
+	builder.WriteString("\n")
+}
+
+func (Text) SuccessTo(w io.Writer, report diff.Report) {
+	PrintTo(w, report)
+}
+
+func (j Text) Success(report diff.Report) {
+	j(report)
+}
+
+func (Text) Error(err error) {
+	slog.Error("text diff output failed", "error", err)
+}
@@ -0,0 +1,78 @@
+package diff
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+)
+
+func TestPrintTo(t *testing.T) {
+	report := diff.Report{
+		PodsCompared: true,
+		Pods: []diff.Pod{
+			{
+				Namespace: "default",
+				Name:      "web",
+				Change:    diff.Changed,
+				Containers: []diff.Container{{
+					Name:   "app",
+					Change: diff.Changed,
+					Used: diff.Resources{
+						CPU:    &diff.Value{Before: 100, After: 50, Delta: -50},
+						Memory: &diff.Value{Before: 32 << 20, After: 64 << 20, Delta: 32 << 20},
+					},
+				}},
+			},
+			{
+				Namespace:  "default",
+				Kind:       "StatefulSet",
+				Name:       "db",
+				Change:     diff.Added,
+				Replicas:   &diff.Value{After: 2, Delta: 2},
+				Containers: []diff.Container{{Name: "db", Change: diff.Added, Requests: diff.Resources{CPU: &diff.Value{After: 500, Delta: 500}}}},
+			},
+			{
+				Namespace: "default",
+				Kind:      "Deployment",
+				Name:      "api",
+				Change:    diff.Changed,
+				Replicas:  &diff.Value{Before: 3, After: 2, Delta: -1},
+			},
+		},
+		Nodes: []diff.Node{{Cluster: "east", Name: "node-1", Change: diff.Removed}},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, report)
+
+	require.Equal(t, `changed pod default/web container app: cpu used 100 → 50 (-50), memory used 32MiB → 64MiB (+32MiB)
+added statefulset default/db: replicas 2
+added statefulset default/db container db: cpu request 500
+changed deployment default/api: replicas 3 → 2 (-1)
+removed node east/node-1
+`, buf.String())
+}
+
+func TestPrintToNoChanges(t *testing.T) {
+	var buf bytes.Buffer
+	PrintTo(&buf, diff.Report{})
+
+	require.Equal(t, "no changes\n", buf.String())
+}
+
+func TestText_Success(t *testing.T) {
+	called := false
+	formatter := Text(func(diff.Report) { called = true })
+	formatter.Success(diff.Report{})
+	require.True(t, called)
+}
+
+func TestText_Error(t *testing.T) {
+	formatter := Text(Print)
+	require.NotPanics(t, func() {
+		formatter.Error(errors.New("test error"))
+	})
+}
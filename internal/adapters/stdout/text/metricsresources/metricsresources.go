@@ -8,40 +8,117 @@ import (
 	"os"
 
 	formatmetricsresources "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/humanize"
 	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 )
 
-type Text func(list metricsresources.PodMetricsResourceList)
+type Text func(list metricsresources.PodMetricsResourceList, warnings partial.Warnings)
 
-func Print(list metricsresources.PodMetricsResourceList) {
-	PrintTo(os.Stdout, list)
+func Print(list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	PrintTo(os.Stdout, list, warnings)
 }
 
-func PrintTo(w io.Writer, list metricsresources.PodMetricsResourceList) {
+func PrintTo(w io.Writer, list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
 	var buffer bytes.Buffer
+	if banner := warnings.String(); banner != "" {
+		_, _ = fmt.Fprintf(&buffer, "%s\n\n", banner)
+	}
+	if list.MetricsUnavailable() {
+		_, _ = fmt.Fprintf(&buffer, "%s\n\n", metricssource.UnavailableWarning)
+	}
 	for _, pod := range list {
+		if pod.Cluster != "" {
+			_, _ = fmt.Fprintf(&buffer, "Cluster:\t%s\n", pod.Cluster)
+		}
 		_, _ = fmt.Fprintf(&buffer, "Name:\t\t%s\n", pod.PodResource.Name)
 		_, _ = fmt.Fprintf(&buffer, "Namespace:\t%s\n", pod.PodResource.Namespace)
 		_, _ = fmt.Fprintf(&buffer, "Node:\t\t%s\n", pod.NodeName)
+		if pod.QOSClass != "" {
+			_, _ = fmt.Fprintf(&buffer, "QoS:\t\t%s\n", pod.QOSClass)
+		}
+		if metrics := formatmetricsresources.MetricsString(pod); metrics != "" {
+			_, _ = fmt.Fprintf(&buffer, "Metrics:\t%s\n", metrics)
+		}
+		if pod.PodMetric.StorageEphemeral > 0 {
+			_, _ = fmt.Fprintf(&buffer, "Ephemeral:\t%s\n", humanize.Bytes(pod.PodMetric.StorageEphemeral))
+		}
+		if len(pod.PodMetric.Volumes) > 0 {
+			_, _ = fmt.Fprint(&buffer, "Volumes:\n")
+			for _, volume := range pod.PodMetric.Volumes {
+				_, _ = fmt.Fprintf(
+					&buffer,
+					"  %s (%s):\t%s/%s\n",
+					volume.Name,
+					volume.ClaimName,
+					humanize.Bytes(volume.Used),
+					humanize.Bytes(volume.Capacity),
+				)
+			}
+		}
 		_, _ = fmt.Fprint(&buffer, "Containers:\n")
 		for _, container := range pod.ContainersMetrics() {
 			containerFormatter := formatmetricsresources.NewContainer(container)
 			_, _ = fmt.Fprintf(&buffer, "  Name:\t\t%s\n", containerFormatter.Name())
+			if container.Type != "" {
+				_, _ = fmt.Fprintf(&buffer, "  Type:\t\t%s\n", container.Type)
+			}
+			if container.MetricsMissing {
+				_, _ = fmt.Fprint(&buffer, "  Metrics:\tmissing\n")
+			}
+			if container.SpecMissing {
+				_, _ = fmt.Fprint(&buffer, "  Spec:\t\tmissing\n")
+			}
+			if container.State != "" {
+				_, _ = fmt.Fprintf(&buffer, "  State:\t\t%s\n", container.State)
+			}
+			if container.Restarts > 0 || container.LastTermination != nil {
+				_, _ = fmt.Fprintf(&buffer, "  Restarts:\t%s\n", containerFormatter.RestartsString(pod.ObservedAt()))
+			}
 			_, _ = fmt.Fprintf(&buffer, "  Requests:\t%s\n", containerFormatter.Requests().StringWithColor("yellow"))
 			_, _ = fmt.Fprintf(&buffer, "  Limits:\t%s\n", containerFormatter.Limits().StringWithColor("red"))
+			if rss := containerFormatter.Requests().MemoryRSSString(); rss != "" {
+				_, _ = fmt.Fprintf(&buffer, "  Memory RSS:\t%s\n", rss)
+			}
 		}
 		_, _ = fmt.Fprintln(&buffer)
 	}
+	if list.Clustered() {
+		writeClusterTotals(&buffer, list)
+	}
+	if missing := list.MissingMetrics().String(); missing != "" {
+		_, _ = fmt.Fprintln(&buffer, missing)
+	}
 	_, _ = io.WriteString(w, buffer.String())
 	_, _ = io.WriteString(w, "\n")
 }
 
-func (Text) SuccessTo(w io.Writer, list metricsresources.PodMetricsResourceList) {
-	PrintTo(w, list)
+// writeClusterTotals writes the subtotal of every cluster and their sum.
+func writeClusterTotals(w io.Writer, list metricsresources.PodMetricsResourceList) {
+	totals := list.ClusterTotals()
+	for _, cluster := range totals {
+		writeClusterTotal(w, "Cluster", cluster)
+	}
+	writeClusterTotal(w, "Total", metricsresources.SumClusterTotals(totals))
+}
+
+func writeClusterTotal(w io.Writer, label string, total metricsresources.ClusterTotal) {
+	formatter := formatmetricsresources.NewClusterTotal(total)
+	if total.Cluster != "" {
+		label += " " + total.Cluster
+	}
+	_, _ = fmt.Fprintf(w, "%s:\t%d pods\n", label, total.Pods)
+	_, _ = fmt.Fprintf(w, "  Requests:\t%s\n", formatter.Requests().StringWithColor("yellow"))
+	_, _ = fmt.Fprintf(w, "  Limits:\t%s\n", formatter.Limits().StringWithColor("red"))
+}
+
+func (Text) SuccessTo(w io.Writer, list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	PrintTo(w, list, warnings)
 }
 
-func (j Text) Success(list metricsresources.PodMetricsResourceList) {
-	j(list)
+func (j Text) Success(list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	j(list, warnings)
 }
 
 func (Text) Error(err error) {
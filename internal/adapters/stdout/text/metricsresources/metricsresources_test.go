@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
 )
@@ -36,7 +38,7 @@ func TestPrint(t *testing.T) {
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 
-		Print(list)
+		Print(list, nil)
 
 		w.Close()
 		os.Stdout = old
@@ -59,7 +61,7 @@ func TestPrint(t *testing.T) {
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 
-		Print(list)
+		Print(list, nil)
 
 		w.Close()
 		os.Stdout = old
@@ -72,6 +74,107 @@ func TestPrint(t *testing.T) {
 	})
 }
 
+func TestPrintToContainerStatus(t *testing.T) {
+	list := metricsresources.PodMetricsResourceList{
+		{
+			PodResource: pods.PodResource{
+				NamespaceName: pods.NamespaceName{Name: "test-pod", Namespace: "default"},
+				Containers: []pods.ContainerResource{
+					{
+						Name:            "app",
+						Restarts:        2,
+						State:           "Running",
+						LastTermination: &pods.Termination{Reason: pods.OOMKilled, ExitCode: 137},
+					},
+					{Name: "idle", State: "Running"},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list, nil)
+
+	output := buf.String()
+	require.Contains(t, output, "  Name:\t\tapp\n  Metrics:\tmissing\n  State:\t\tRunning\n  Restarts:\t2 (OOMKilled)\n")
+	require.Contains(t, output, "  Name:\t\tidle\n  Metrics:\tmissing\n  State:\t\tRunning\n  Requests:")
+}
+
+func TestPrintToKubeletStorage(t *testing.T) {
+	list := metricsresources.PodMetricsResourceList{
+		{
+			PodResource: pods.PodResource{
+				NamespaceName: pods.NamespaceName{Name: "db", Namespace: "default"},
+				Containers:    []pods.ContainerResource{{Name: "db"}},
+			},
+			PodMetric: podmetrics.PodMetric{
+				Name:             "db",
+				Namespace:        "default",
+				StorageEphemeral: 2048,
+				Volumes:          []podmetrics.VolumeMetric{{Name: "data", ClaimName: "data-db", Used: 1024, Capacity: 4096}},
+				Containers: []podmetrics.ContainerMetric{
+					{Name: "db", Metric: podmetrics.Metric{CPU: 10, Memory: 4096, MemoryRSS: 3072}},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list, nil)
+
+	output := buf.String()
+	require.Contains(t, output, "Ephemeral:\t2KiB\n")
+	require.Contains(t, output, "Volumes:\n  data (data-db):\t1KiB/4KiB\n")
+	require.Contains(t, output, "  Memory RSS:\t3KiB\n")
+}
+
+func TestPrintToMissingMetrics(t *testing.T) {
+	list := metricsresources.PodMetricsResourceList{
+		{
+			PodResource: pods.PodResource{
+				NamespaceName: pods.NamespaceName{Name: "web", Namespace: "default"},
+				NodeName:      "node-2",
+				Containers:    []pods.ContainerResource{{Name: "app"}},
+			},
+		},
+		{
+			PodResource: pods.PodResource{
+				NamespaceName: pods.NamespaceName{Name: "db", Namespace: "default"},
+				NodeName:      "node-1",
+				Containers:    []pods.ContainerResource{{Name: "db"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list, nil)
+
+	output := buf.String()
+	require.Contains(t, output, "Node:\t\tnode-2\nMetrics:\tmissing\n")
+	require.Contains(t, output, "2 pods without metrics on nodes node-1,node-2\n")
+}
+
+func TestPrintToClusters(t *testing.T) {
+	pod := func(cluster string, cpu int64) metricsresources.PodMetricsResource {
+		return metricsresources.PodMetricsResource{
+			Cluster: cluster,
+			PodResource: pods.PodResource{
+				NamespaceName: pods.NamespaceName{Name: "web", Namespace: "default"},
+				Containers:    []pods.ContainerResource{{Name: "app", Requests: pods.Resource{CPU: cpu}}},
+			},
+		}
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, metricsresources.PodMetricsResourceList{pod("prod-us", 100), pod("prod-eu", 200)}, nil)
+
+	output := buf.String()
+	require.Contains(t, output, "Cluster:\tprod-us\nName:\t\tweb\n")
+	require.Contains(t, output, "Cluster prod-us:\t1 pods\n  Requests:\tCPU=100/0,")
+	require.Contains(t, output, "Cluster prod-eu:\t1 pods\n  Requests:\tCPU=200/0,")
+	require.Contains(t, output, "Total:\t2 pods\n  Requests:\tCPU=300/0,")
+}
+
 func TestTextSuccess(t *testing.T) {
 	t.Run("calls Print", func(t *testing.T) {
 		list := metricsresources.PodMetricsResourceList{
@@ -95,7 +198,7 @@ func TestTextSuccess(t *testing.T) {
 		os.Stdout = w
 
 		formatter := Text(Print)
-		formatter.Success(list)
+		formatter.Success(list, nil)
 
 		w.Close()
 		os.Stdout = old
@@ -139,7 +242,7 @@ func TestPrintToDoesNotPanic(t *testing.T) {
 
 		var buffer bytes.Buffer
 		require.NotPanics(t, func() {
-			PrintTo(&buffer, list)
+			PrintTo(&buffer, list, nil)
 		})
 		require.Contains(t, buffer.String(), "test-pod")
 	})
@@ -149,8 +252,16 @@ func TestPrintToDoesNotPanic(t *testing.T) {
 
 		var buffer bytes.Buffer
 		require.NotPanics(t, func() {
-			PrintTo(&buffer, list)
+			PrintTo(&buffer, list, nil)
 		})
 		require.NotEmpty(t, buffer.String())
 	})
 }
+
+func TestPrintToPartialResultsBanner(t *testing.T) {
+	var buf bytes.Buffer
+	PrintTo(&buf, metricsresources.PodMetricsResourceList{}, partial.Warnings{
+		{Cluster: "prod-us", Scope: partial.Node, Name: "node-a", Message: "forbidden"},
+	})
+	require.True(t, strings.HasPrefix(buf.String(), "WARNING: partial results, 1 scope failed:\n  cluster prod-us node node-a: forbidden\n\n"))
+}
@@ -0,0 +1,43 @@
+package namespaces
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	formatnamespaces "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/namespaces"
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
+)
+
+type Text func(list namespaces.NamespaceResourceList)
+
+func Print(list namespaces.NamespaceResourceList) {
+	PrintTo(os.Stdout, list)
+}
+
+func PrintTo(w io.Writer, list namespaces.NamespaceResourceList) {
+	var buffer bytes.Buffer
+	for _, resource := range list {
+		formatter := formatnamespaces.New(resource)
+		_, _ = fmt.Fprintf(&buffer, "Namespace: %s\n", resource.Name)
+		_, _ = fmt.Fprintf(&buffer, "Pods: %s\n", formatter.PodsTemplate())
+		_, _ = fmt.Fprintf(&buffer, "CPU: %s\n", formatter.CPUTemplate())
+		_, _ = fmt.Fprintf(&buffer, "Memory: %s\n", formatter.MemoryTemplate())
+	}
+	_, _ = io.WriteString(w, buffer.String())
+	_, _ = io.WriteString(w, "\n")
+}
+
+func (Text) SuccessTo(w io.Writer, list namespaces.NamespaceResourceList) {
+	PrintTo(w, list)
+}
+
+func (j Text) Success(list namespaces.NamespaceResourceList) {
+	j(list)
+}
+
+func (Text) Error(err error) {
+	slog.Error("text namespaces output failed", "error", err)
+}
@@ -0,0 +1,49 @@
+package namespaces
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+)
+
+func TestPrintTo(t *testing.T) {
+	t.Run("prints text", func(t *testing.T) {
+		memoryQuota := quotas.NewQuota(4096, 1024)
+		list := namespaces.NamespaceResourceList{
+			{
+				Name:          "team-a",
+				Pods:          2,
+				CPURequest:    200,
+				MemoryRequest: 1024,
+				Quota:         quotas.NamespaceQuota{RequestsMemory: &memoryQuota},
+			},
+		}
+
+		var buf bytes.Buffer
+		PrintTo(&buf, list)
+		output := buf.String()
+
+		require.Contains(t, output, "Namespace: team-a\n")
+		require.Contains(t, output, "Pods: 2\n")
+		require.Contains(t, output, "CPU: Requests=200, Limits=0, Used=0\n")
+		require.Contains(t, output, "Memory: Requests=1KiB, Limits=0B, Used=0B, Requests Quota=1KiB/4KiB (25%)\n")
+	})
+
+	t.Run("prints empty list", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintTo(&buf, namespaces.NamespaceResourceList{})
+		require.Equal(t, "\n", buf.String())
+	})
+}
+
+func TestText_Error(t *testing.T) {
+	formatter := Text(Print)
+	require.NotPanics(t, func() {
+		formatter.Error(errors.New("test error"))
+	})
+}
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
 
 	formatnoderesources "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/noderesources"
+	"github.com/trezorg/k8spodsmetrics/internal/humanize"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
 	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
 )
 
@@ -19,16 +23,112 @@ func Print(list noderesources.NodeResourceList) {
 
 func PrintTo(w io.Writer, list noderesources.NodeResourceList) {
 	var buffer bytes.Buffer
+	writeMetricsUnavailable(&buffer, list)
 	for _, node := range list {
-		formatter := formatnoderesources.New(node)
-		_, _ = fmt.Fprintf(&buffer, "Name: %s\n", node.Name)
-		_, _ = fmt.Fprintf(&buffer, "Memory: %s\n", formatter.MemoryTemplate())
-		_, _ = fmt.Fprintf(&buffer, "CPU: %s\n", formatter.CPUTemplate())
+		writeNode(&buffer, node)
+	}
+	if list.Clustered() {
+		writeClusterTotals(&buffer, list)
 	}
 	_, _ = io.WriteString(w, buffer.String())
 	_, _ = io.WriteString(w, "\n")
 }
 
+// ToGroups prints nodes grouped by the value of label.
+func ToGroups(label string) Text {
+	return Text(func(list noderesources.NodeResourceList) {
+		PrintGroupsTo(os.Stdout, list, label)
+	})
+}
+
+// ToGroupsWriter is the writer form of ToGroups used in watch mode.
+func ToGroupsWriter(label string) func(io.Writer, noderesources.NodeResourceList) {
+	return func(w io.Writer, list noderesources.NodeResourceList) {
+		PrintGroupsTo(w, list, label)
+	}
+}
+
+// PrintGroupsTo prints the nodes of every label value followed by the group subtotal.
+func PrintGroupsTo(w io.Writer, list noderesources.NodeResourceList, label string) {
+	var buffer bytes.Buffer
+	writeMetricsUnavailable(&buffer, list)
+	for _, group := range noderesources.GroupByLabel(list, label) {
+		_, _ = fmt.Fprintf(
+			&buffer,
+			"Group: %s=%s (%s)\n",
+			label,
+			formatnoderesources.GroupValueString(group),
+			formatnoderesources.GroupNodesString(group),
+		)
+		for _, node := range group.Items {
+			writeNode(&buffer, node)
+		}
+		writeTotal(&buffer, "Subtotal", group.Total)
+	}
+	_, _ = io.WriteString(w, buffer.String())
+	_, _ = io.WriteString(w, "\n")
+}
+
+// writeMetricsUnavailable writes the warning of nodes listed without usage.
+func writeMetricsUnavailable(w io.Writer, list noderesources.NodeResourceList) {
+	if list.MetricsUnavailable() {
+		_, _ = fmt.Fprintf(w, "%s\n\n", metricssource.UnavailableWarning)
+	}
+}
+
+// writeClusterTotals writes the subtotal of every cluster and their sum.
+func writeClusterTotals(w io.Writer, list noderesources.NodeResourceList) {
+	envelope := noderesources.NewNodeResourceListEnvelope(list)
+	for _, cluster := range envelope.Clusters {
+		_, _ = fmt.Fprintf(w, "Cluster: %s (%s)\n", cluster.Cluster, formatnoderesources.ClusterNodesString(cluster))
+		writeTotal(w, "Subtotal", cluster.Total)
+	}
+	_, _ = fmt.Fprintf(w, "Total: %s\n", formatnoderesources.ClusterNodesString(*envelope.Total))
+	writeTotal(w, "Total", envelope.Total.Total)
+}
+
+func writeTotal(w io.Writer, label string, total noderesources.NodeResource) {
+	formatter := formatnoderesources.New(total)
+	_, _ = fmt.Fprintf(w, "%s Memory: %s\n", label, formatter.MemoryTemplate())
+	_, _ = fmt.Fprintf(w, "%s CPU: %s\n", label, formatter.CPUTemplate())
+	_, _ = fmt.Fprintf(w, "%s Pods: %s\n", label, formatter.PodsTemplate())
+}
+
+func writeNode(w io.Writer, node noderesources.NodeResource) {
+	formatter := formatnoderesources.New(node)
+	if node.Cluster != "" {
+		_, _ = fmt.Fprintf(w, "Cluster: %s\n", node.Cluster)
+	}
+	_, _ = fmt.Fprintf(w, "Name: %s\n", formatter.NameString())
+	_, _ = fmt.Fprintf(w, "Status: %s\n", formatter.StatusString())
+	if metrics := formatter.MetricsString(); metrics != "" {
+		_, _ = fmt.Fprintf(w, "Metrics: %s\n", metrics)
+	}
+	if len(node.Taints) > 0 {
+		_, _ = fmt.Fprintf(w, "Taints: %s\n", formatter.TaintsString())
+	}
+	_, _ = fmt.Fprintf(w, "Memory: %s\n", formatter.MemoryTemplate())
+	if node.UsedMemoryRSS > 0 {
+		_, _ = fmt.Fprintf(w, "Memory RSS: %s\n", humanize.Bytes(node.UsedMemoryRSS))
+	}
+	_, _ = fmt.Fprintf(w, "CPU: %s\n", formatter.CPUTemplate())
+	_, _ = fmt.Fprintf(w, "Pods: %s\n", formatter.PodsTemplate())
+	if node.ImageFilesystem > 0 {
+		_, _ = fmt.Fprintf(
+			w,
+			"Image Filesystem: Total=%s, Used=%s\n",
+			humanize.Bytes(node.ImageFilesystem),
+			humanize.Bytes(node.UsedImageFilesystem),
+		)
+	}
+	for _, name := range slices.Sorted(maps.Keys(node.Extended)) {
+		_, _ = fmt.Fprintf(w, "%s: %s\n", name, formatter.ExtendedTemplate(name))
+	}
+	for _, group := range node.QOS {
+		_, _ = fmt.Fprintf(w, "QoS %s: %s\n", group.Class, formatnoderesources.NewQOS(node, group).Template())
+	}
+}
+
 func (Text) SuccessTo(w io.Writer, list noderesources.NodeResourceList) {
 	PrintTo(w, list)
 }
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/pkg/nodes"
+	v1 "k8s.io/api/core/v1"
 )
 
 func TestPrint(t *testing.T) {
@@ -61,6 +64,111 @@ func TestPrint(t *testing.T) {
 	})
 }
 
+func TestPrintToExtendedResources(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{
+			Name: "gpu-1",
+			Extended: map[string]noderesources.ExtendedResource{
+				"nvidia.com/gpu": {Capacity: 4, Allocatable: 4, Request: 1, Limit: 1},
+				"hugepages-2Mi":  {Capacity: 4 * 1024 * 1024, Allocatable: 4 * 1024 * 1024},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list)
+
+	output := buf.String()
+	require.Contains(t, output, "hugepages-2Mi: Node=4MiB/4MiB, Requests=0B, Limits=0B\nnvidia.com/gpu: Node=4/4, Requests=1, Limits=1\n")
+}
+
+func TestPrintToKubeletMetrics(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{Name: "node-1", UsedMemoryRSS: 1024, ImageFilesystem: 4096, UsedImageFilesystem: 2048},
+		{Name: "node-2"},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list)
+
+	output := buf.String()
+	require.Contains(t, output, "Memory RSS: 1KiB\nCPU:")
+	require.Contains(t, output, "Image Filesystem: Total=4KiB, Used=2KiB\n")
+	require.Equal(t, 1, strings.Count(output, "Memory RSS:"))
+}
+
+func TestPrintToStatus(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{
+			Name:          "node-1",
+			Ready:         true,
+			Unschedulable: true,
+			Taints:        []nodes.Taint{{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}},
+		},
+		{Name: "node-2", Ready: true, Schedulable: true},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list)
+
+	output := buf.String()
+	require.Contains(t, output, "Status: \x1b[33mReady,SchedulingDisabled,Tainted\x1b[0m\nTaints: dedicated=gpu:NoSchedule\n")
+	require.Contains(t, output, "Name: node-2\nStatus: Ready\nMemory:")
+}
+
+func TestPrintToQOS(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{
+			Name:           "node-1",
+			AllocatableCPU: 2000,
+			QOS: []noderesources.QOSResource{
+				{Class: v1.PodQOSGuaranteed, Pods: 1, CPURequest: 500, CPULimit: 500},
+				{Class: v1.PodQOSBestEffort, Pods: 2},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list)
+
+	output := buf.String()
+	require.Contains(t, output, "QoS Guaranteed: Pods=1, CPU Requests=500 (25%), CPU Limits=500 (25%), Memory Requests=0B, Memory Limits=0B\n")
+	require.Contains(t, output, "QoS BestEffort: Pods=2, CPU Requests=0 (0%)")
+}
+
+func TestPrintGroupsTo(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{Name: "node-1", AllocatablePods: 10, Ready: true, Schedulable: true, Labels: map[string]string{"zone": "b"}},
+		{Name: "node-2", AllocatablePods: 20, Ready: true, Schedulable: true, Labels: map[string]string{"zone": "a"}},
+		{Name: "node-3", AllocatablePods: 30, Ready: true, Schedulable: true, Labels: map[string]string{"zone": "a"}},
+		{Name: "node-4", AllocatablePods: 5, Ready: true, Schedulable: true},
+	}
+
+	var buf bytes.Buffer
+	PrintGroupsTo(&buf, list, "zone")
+
+	output := buf.String()
+	require.Regexp(t, `(?s)^Group: zone=a \(2 nodes\)\nName: node-2\n.*Name: node-3\n.*Subtotal CPU: .*Group: zone=b \(1 nodes\)\nName: node-1\n.*Group: zone=<none> \(1 nodes\)\nName: node-4\n`, output)
+	require.Equal(t, 3, strings.Count(output, "Subtotal Memory: "))
+	require.Contains(t, output, "Subtotal Pods: Allocatable=50,")
+}
+
+func TestPrintToClusters(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{Cluster: "prod-us", Name: "node-1", AllocatablePods: 10, Ready: true, Schedulable: true},
+		{Cluster: "prod-eu", Name: "node-1", AllocatablePods: 20, Ready: true},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list)
+
+	output := buf.String()
+	require.Contains(t, output, "Cluster: prod-us\nName: node-1\n")
+	require.Regexp(t, `(?s)Cluster: prod-us \(1 nodes\)\nSubtotal Memory: .*Subtotal Pods: Allocatable=10,`, output)
+	require.Regexp(t, `(?s)Cluster: prod-eu \(1 nodes, 0 schedulable\)\n`, output)
+	require.Regexp(t, `(?s)Total: 2 nodes, 1 schedulable\n.*Total Pods: Allocatable=30,`, output)
+}
+
 func TestTextSuccess(t *testing.T) {
 	t.Run("calls Print", func(t *testing.T) {
 		list := noderesources.NodeResourceList{
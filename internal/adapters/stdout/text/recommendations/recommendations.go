@@ -0,0 +1,33 @@
+package recommendations
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	formatrecommendations "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/recommendations"
+	"github.com/trezorg/k8spodsmetrics/internal/recommendations"
+)
+
+type Text func(list recommendations.RecommendationList)
+
+func Print(list recommendations.RecommendationList) {
+	PrintTo(os.Stdout, list)
+}
+
+// PrintTo writes only the patches, ready to be split into files and applied.
+func PrintTo(w io.Writer, list recommendations.RecommendationList) {
+	formatrecommendations.PatchesTo(w, list.Patches())
+}
+
+func (Text) SuccessTo(w io.Writer, list recommendations.RecommendationList) {
+	PrintTo(w, list)
+}
+
+func (j Text) Success(list recommendations.RecommendationList) {
+	j(list)
+}
+
+func (Text) Error(err error) {
+	slog.Error("text recommendations output failed", "error", err)
+}
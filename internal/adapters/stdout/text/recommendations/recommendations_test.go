@@ -0,0 +1,47 @@
+package recommendations
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/recommendations"
+)
+
+func TestPrintTo(t *testing.T) {
+	list := recommendations.RecommendationList{
+		{
+			Namespace:   "default",
+			Kind:        "DaemonSet",
+			Workload:    "agent",
+			Container:   "agent",
+			Samples:     1,
+			Recommended: recommendations.Resources{Requests: recommendations.Values{CPU: 50}},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list)
+	output := buf.String()
+
+	require.True(t, strings.HasPrefix(output, "---\n# kubectl -n default patch daemonset agent"))
+	require.Contains(t, output, "cpu: 50m")
+	require.NotContains(t, output, "Samples")
+}
+
+func TestText_Success(t *testing.T) {
+	called := false
+	formatter := Text(func(recommendations.RecommendationList) { called = true })
+	formatter.Success(recommendations.RecommendationList{})
+	require.True(t, called)
+}
+
+func TestText_Error(t *testing.T) {
+	formatter := Text(Print)
+	require.NotPanics(t, func() {
+		formatter.Error(errors.New("test error"))
+	})
+}
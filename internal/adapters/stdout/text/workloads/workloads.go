@@ -0,0 +1,44 @@
+package workloads
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	formatworkloads "github.com/trezorg/k8spodsmetrics/internal/adapters/stdout/format/workloads"
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+)
+
+type Text func(list workloads.WorkloadList)
+
+func Print(list workloads.WorkloadList) {
+	PrintTo(os.Stdout, list)
+}
+
+func PrintTo(w io.Writer, list workloads.WorkloadList) {
+	var buffer bytes.Buffer
+	for _, workload := range list {
+		formatter := formatworkloads.New(workload)
+		_, _ = fmt.Fprintf(&buffer, "Workload: %s\n", formatter.NameString())
+		_, _ = fmt.Fprintf(&buffer, "Namespace: %s\n", workload.Namespace)
+		_, _ = fmt.Fprintf(&buffer, "Replicas: %s\n", formatter.ReplicasString())
+		_, _ = fmt.Fprintf(&buffer, "CPU: %s\n", formatter.CPUTemplate())
+		_, _ = fmt.Fprintf(&buffer, "Memory: %s\n", formatter.MemoryTemplate())
+	}
+	_, _ = io.WriteString(w, buffer.String())
+	_, _ = io.WriteString(w, "\n")
+}
+
+func (Text) SuccessTo(w io.Writer, list workloads.WorkloadList) {
+	PrintTo(w, list)
+}
+
+func (j Text) Success(list workloads.WorkloadList) {
+	j(list)
+}
+
+func (Text) Error(err error) {
+	slog.Error("text workloads output failed", "error", err)
+}
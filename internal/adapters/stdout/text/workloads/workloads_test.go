@@ -0,0 +1,48 @@
+package workloads
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+)
+
+func TestPrintTo(t *testing.T) {
+	t.Run("prints text", func(t *testing.T) {
+		list := workloads.WorkloadList{
+			{
+				Namespace: "default",
+				Kind:      "Deployment",
+				Name:      "web",
+				Replicas:  2,
+				Requests:  workloads.Resources{CPU: workloads.Stats{Total: 200, Average: 100, Min: 100, Max: 100}},
+			},
+		}
+
+		var buf bytes.Buffer
+		PrintTo(&buf, list)
+		output := buf.String()
+
+		require.Contains(t, output, "Workload: Deployment/web\n")
+		require.Contains(t, output, "Namespace: default\n")
+		require.Contains(t, output, "Replicas: 2\n")
+		require.Contains(t, output, "CPU: Requests=200 (avg=100, min=100, max=100)")
+		require.Contains(t, output, "Memory: Requests=0B")
+	})
+
+	t.Run("prints empty list", func(t *testing.T) {
+		var buf bytes.Buffer
+		PrintTo(&buf, workloads.WorkloadList{})
+		require.Equal(t, "\n", buf.String())
+	})
+}
+
+func TestText_Error(t *testing.T) {
+	formatter := Text(Print)
+	require.NotPanics(t, func() {
+		formatter.Error(errors.New("test error"))
+	})
+}
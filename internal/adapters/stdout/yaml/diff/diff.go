@@ -0,0 +1,37 @@
+package diff
+
+import (
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+	"log/slog"
+)
+
+type Yaml func(report diff.Report)
+
+func Print(report diff.Report) {
+	PrintTo(os.Stdout, report)
+}
+
+func PrintTo(w io.Writer, report diff.Report) {
+	enc := yaml.NewEncoder(w)
+	defer func() { _ = enc.Close() }()
+	if err := enc.Encode(report); err != nil {
+		slog.Error("failed to encode diff as yaml", "error", err)
+	}
+}
+
+func (Yaml) SuccessTo(w io.Writer, report diff.Report) {
+	PrintTo(w, report)
+}
+
+func (j Yaml) Success(report diff.Report) {
+	j(report)
+}
+
+func (Yaml) Error(err error) {
+	slog.Error("yaml diff output failed", "error", err)
+}
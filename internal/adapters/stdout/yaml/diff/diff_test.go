@@ -0,0 +1,45 @@
+package diff
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/diff"
+)
+
+func TestPrintTo(t *testing.T) {
+	report := diff.Report{
+		NodesCompared: true,
+		Nodes: []diff.Node{{
+			Name:   "node-1",
+			Change: diff.Changed,
+			Used:   diff.Resources{CPU: &diff.Value{Before: 500, After: 900, Delta: 400}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, report)
+	output := buf.String()
+
+	require.Contains(t, output, "nodes:")
+	require.Contains(t, output, "change: changed")
+	require.Contains(t, output, "delta: 400")
+	require.NotContains(t, output, "allocatable:")
+}
+
+func TestYaml_Success(t *testing.T) {
+	called := false
+	formatter := Yaml(func(diff.Report) { called = true })
+	formatter.Success(diff.Report{})
+	require.True(t, called)
+}
+
+func TestYaml_Error(t *testing.T) {
+	formatter := Yaml(Print)
+	require.NotPanics(t, func() {
+		formatter.Error(errors.New("test error"))
+	})
+}
@@ -7,17 +7,18 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"log/slog"
 )
 
-type Yaml func(list metricsresources.PodMetricsResourceList)
+type Yaml func(list metricsresources.PodMetricsResourceList, warnings partial.Warnings)
 
-func Print(list metricsresources.PodMetricsResourceList) {
-	PrintTo(os.Stdout, list)
+func Print(list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	PrintTo(os.Stdout, list, warnings)
 }
 
-func PrintTo(w io.Writer, list metricsresources.PodMetricsResourceList) {
-	data, err := yaml.Marshal(list)
+func PrintTo(w io.Writer, list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	data, err := yaml.Marshal(list.Envelope(warnings))
 	if err != nil {
 		slog.Error("failed to marshal metrics resources to yaml", "error", err)
 		return
@@ -26,12 +27,12 @@ func PrintTo(w io.Writer, list metricsresources.PodMetricsResourceList) {
 	_, _ = w.Write([]byte("\n"))
 }
 
-func (Yaml) SuccessTo(w io.Writer, list metricsresources.PodMetricsResourceList) {
-	PrintTo(w, list)
+func (Yaml) SuccessTo(w io.Writer, list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	PrintTo(w, list, warnings)
 }
 
-func (j Yaml) Success(list metricsresources.PodMetricsResourceList) {
-	j(list)
+func (j Yaml) Success(list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	j(list, warnings)
 }
 
 func (Yaml) Error(err error) {
@@ -36,7 +36,7 @@ func TestPrint(t *testing.T) {
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 
-		Print(list)
+		Print(list, nil)
 
 		w.Close()
 		os.Stdout = old
@@ -57,7 +57,7 @@ func TestPrint(t *testing.T) {
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 
-		Print(list)
+		Print(list, nil)
 
 		w.Close()
 		os.Stdout = old
@@ -93,7 +93,7 @@ func TestYaml_Success(t *testing.T) {
 		os.Stdout = w
 
 		formatter := Yaml(Print)
-		formatter.Success(list)
+		formatter.Success(list, nil)
 
 		w.Close()
 		os.Stdout = old
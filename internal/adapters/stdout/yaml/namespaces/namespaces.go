@@ -0,0 +1,38 @@
+package namespaces
+
+import (
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
+	"log/slog"
+)
+
+type Yaml func(list namespaces.NamespaceResourceList)
+
+func Print(list namespaces.NamespaceResourceList) {
+	PrintTo(os.Stdout, list)
+}
+
+func PrintTo(w io.Writer, list namespaces.NamespaceResourceList) {
+	enc := yaml.NewEncoder(w)
+	defer func() { _ = enc.Close() }()
+	envelope := namespaces.NamespaceResourceListEnvelope{Items: list}
+	if err := enc.Encode(envelope); err != nil {
+		slog.Error("failed to encode namespaces as yaml", "error", err)
+	}
+}
+
+func (Yaml) SuccessTo(w io.Writer, list namespaces.NamespaceResourceList) {
+	PrintTo(w, list)
+}
+
+func (j Yaml) Success(list namespaces.NamespaceResourceList) {
+	j(list)
+}
+
+func (Yaml) Error(err error) {
+	slog.Error("yaml namespaces output failed", "error", err)
+}
@@ -0,0 +1,39 @@
+package namespaces
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/trezorg/k8spodsmetrics/internal/namespaces"
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+)
+
+func TestPrintTo(t *testing.T) {
+	cpuQuota := quotas.NewQuota(2000, 1500)
+	list := namespaces.NamespaceResourceList{
+		{Name: "team-a", Pods: 2, Quota: quotas.NamespaceQuota{RequestsCPU: &cpuQuota}},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list)
+	output := buf.String()
+	require.Contains(t, output, "items:")
+	require.Contains(t, output, "name: team-a")
+	require.Contains(t, output, "requests_cpu:")
+	require.Contains(t, output, "percent: 75")
+
+	var decoded namespaces.NamespaceResourceListEnvelope
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, &cpuQuota, decoded.Items[0].Quota.RequestsCPU)
+}
+
+func TestYaml_Error(t *testing.T) {
+	formatter := Yaml(Print)
+	require.NotPanics(t, func() {
+		formatter.Error(errors.New("test error"))
+	})
+}
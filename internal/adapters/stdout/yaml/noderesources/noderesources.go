@@ -19,12 +19,35 @@ func Print(list noderesources.NodeResourceList) {
 func PrintTo(w io.Writer, list noderesources.NodeResourceList) {
 	enc := yaml.NewEncoder(w)
 	defer func() { _ = enc.Close() }()
-	envelope := noderesources.NodeResourceListEnvelope{Items: list}
+	envelope := noderesources.NewNodeResourceListEnvelope(list)
 	if err := enc.Encode(envelope); err != nil {
 		slog.Error("failed to encode node resources as yaml", "error", err)
 	}
 }
 
+// ToGroups prints nodes grouped by the value of label.
+func ToGroups(label string) Yaml {
+	return Yaml(func(list noderesources.NodeResourceList) {
+		PrintGroupsTo(os.Stdout, list, label)
+	})
+}
+
+// ToGroupsWriter is the writer form of ToGroups used in watch mode.
+func ToGroupsWriter(label string) func(io.Writer, noderesources.NodeResourceList) {
+	return func(w io.Writer, list noderesources.NodeResourceList) {
+		PrintGroupsTo(w, list, label)
+	}
+}
+
+func PrintGroupsTo(w io.Writer, list noderesources.NodeResourceList, label string) {
+	enc := yaml.NewEncoder(w)
+	defer func() { _ = enc.Close() }()
+	envelope := noderesources.NewNodeResourceGroupListEnvelope(list, label)
+	if err := enc.Encode(envelope); err != nil {
+		slog.Error("failed to encode node resource groups as yaml", "error", err)
+	}
+}
+
 func (Yaml) SuccessTo(w io.Writer, list noderesources.NodeResourceList) {
 	PrintTo(w, list)
 }
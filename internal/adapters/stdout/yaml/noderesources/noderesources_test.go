@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
 )
@@ -99,3 +100,25 @@ func TestYaml_Error(t *testing.T) {
 		})
 	})
 }
+
+func TestPrintGroupsTo(t *testing.T) {
+	list := noderesources.NodeResourceList{
+		{Name: "node-1", AllocatableCPU: 1000, Labels: map[string]string{"zone": "a"}},
+		{Name: "node-2", AllocatableCPU: 2000, Labels: map[string]string{"zone": "a"}},
+		{Name: "node-3", AllocatableCPU: 4000, Labels: map[string]string{"zone": "b"}},
+	}
+
+	var buf bytes.Buffer
+	ToGroupsWriter("zone")(&buf, list)
+
+	var decoded noderesources.NodeResourceGroupListEnvelope
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded.Groups, 2)
+	require.Equal(t, "zone", decoded.Groups[0].Label)
+	require.Equal(t, "a", decoded.Groups[0].Value)
+	require.Equal(t, 2, decoded.Groups[0].Nodes)
+	require.Equal(t, int64(3000), decoded.Groups[0].Total.AllocatableCPU)
+	require.Len(t, decoded.Groups[0].Items, 2)
+	require.Equal(t, "b", decoded.Groups[1].Value)
+	require.NotContains(t, buf.String(), "labels")
+}
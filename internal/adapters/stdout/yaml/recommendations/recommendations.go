@@ -0,0 +1,38 @@
+package recommendations
+
+import (
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/trezorg/k8spodsmetrics/internal/recommendations"
+	"log/slog"
+)
+
+type Yaml func(list recommendations.RecommendationList)
+
+func Print(list recommendations.RecommendationList) {
+	PrintTo(os.Stdout, list)
+}
+
+func PrintTo(w io.Writer, list recommendations.RecommendationList) {
+	enc := yaml.NewEncoder(w)
+	defer func() { _ = enc.Close() }()
+	envelope := recommendations.NewRecommendationListEnvelope(list)
+	if err := enc.Encode(envelope); err != nil {
+		slog.Error("failed to encode recommendations as yaml", "error", err)
+	}
+}
+
+func (Yaml) SuccessTo(w io.Writer, list recommendations.RecommendationList) {
+	PrintTo(w, list)
+}
+
+func (j Yaml) Success(list recommendations.RecommendationList) {
+	j(list)
+}
+
+func (Yaml) Error(err error) {
+	slog.Error("yaml recommendations output failed", "error", err)
+}
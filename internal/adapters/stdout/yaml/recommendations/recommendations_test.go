@@ -0,0 +1,48 @@
+package recommendations
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/recommendations"
+)
+
+func TestPrintTo(t *testing.T) {
+	list := recommendations.RecommendationList{
+		{
+			Namespace:   "ops",
+			Kind:        "CronJob",
+			Workload:    "backup",
+			Container:   "main",
+			Samples:     4,
+			Recommended: recommendations.Resources{Limits: recommendations.Values{Memory: 256 << 20}},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintTo(&buf, list)
+	output := buf.String()
+
+	require.Contains(t, output, "items:")
+	require.Contains(t, output, "workload: backup")
+	require.Contains(t, output, "patches:")
+	require.Contains(t, output, "jobTemplate:")
+	require.Contains(t, output, "memory: 256Mi")
+}
+
+func TestYaml_Success(t *testing.T) {
+	called := false
+	formatter := Yaml(func(recommendations.RecommendationList) { called = true })
+	formatter.Success(recommendations.RecommendationList{})
+	require.True(t, called)
+}
+
+func TestYaml_Error(t *testing.T) {
+	formatter := Yaml(Print)
+	require.NotPanics(t, func() {
+		formatter.Error(errors.New("test error"))
+	})
+}
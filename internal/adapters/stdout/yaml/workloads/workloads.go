@@ -0,0 +1,38 @@
+package workloads
+
+import (
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+	"log/slog"
+)
+
+type Yaml func(list workloads.WorkloadList)
+
+func Print(list workloads.WorkloadList) {
+	PrintTo(os.Stdout, list)
+}
+
+func PrintTo(w io.Writer, list workloads.WorkloadList) {
+	enc := yaml.NewEncoder(w)
+	defer func() { _ = enc.Close() }()
+	envelope := workloads.WorkloadListEnvelope{Items: list}
+	if err := enc.Encode(envelope); err != nil {
+		slog.Error("failed to encode workloads as yaml", "error", err)
+	}
+}
+
+func (Yaml) SuccessTo(w io.Writer, list workloads.WorkloadList) {
+	PrintTo(w, list)
+}
+
+func (j Yaml) Success(list workloads.WorkloadList) {
+	j(list)
+}
+
+func (Yaml) Error(err error) {
+	slog.Error("yaml workloads output failed", "error", err)
+}
@@ -0,0 +1,46 @@
+package workloads
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+)
+
+func TestPrintTo(t *testing.T) {
+	t.Run("prints valid YAML", func(t *testing.T) {
+		list := workloads.WorkloadList{
+			{Namespace: "ops", Kind: "CronJob", Name: "backup", Replicas: 1, MetricsMissing: 1},
+		}
+
+		var buf bytes.Buffer
+		PrintTo(&buf, list)
+		output := buf.String()
+		require.Contains(t, output, "items:")
+		require.Contains(t, output, "kind: CronJob")
+		require.Contains(t, output, "metrics_missing: 1")
+
+		var decoded workloads.WorkloadListEnvelope
+		require.NoError(t, yaml.Unmarshal(buf.Bytes(), &decoded))
+		require.Len(t, decoded.Items, 1)
+		require.Equal(t, "backup", decoded.Items[0].Name)
+	})
+}
+
+func TestYaml_Success(t *testing.T) {
+	called := false
+	formatter := Yaml(func(workloads.WorkloadList) { called = true })
+	formatter.Success(workloads.WorkloadList{})
+	require.True(t, called)
+}
+
+func TestYaml_Error(t *testing.T) {
+	formatter := Yaml(Print)
+	require.NotPanics(t, func() {
+		formatter.Error(errors.New("test error"))
+	})
+}
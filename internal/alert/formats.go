@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	choiceutil "github.com/trezorg/k8spodsmetrics/internal/choices"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
 )
 
 type Alert string
@@ -18,18 +19,48 @@ const (
 	CPULimit         Alert = "cpu_limit"
 	Storage          Alert = "storage"
 	StorageEphemeral Alert = "storage_ephemeral"
-	None             Alert = "none"
+	// Pods alerts on nodes nearing their pod limit.
+	Pods Alert = "pods"
+	// OOM alerts on pods having a container OOMKilled recently.
+	OOM Alert = "oom"
+	// Extended alerts on any extended resource. A single one is selected by
+	// its name, e.g. nvidia.com/gpu.
+	Extended Alert = "extended"
+	None     Alert = "none"
 )
 
-var choices = []Alert{Any, Memory, MemoryLimit, MemoryRequest, CPU, CPULimit, CPURequest, Storage, StorageEphemeral, None}
+var choices = []Alert{Any, Memory, MemoryLimit, MemoryRequest, CPU, CPULimit, CPURequest, Storage, StorageEphemeral, Pods, OOM, Extended, None}
 
 func Valid(o Alert) error {
+	if _, ok := ExtendedResource(o); ok {
+		return nil
+	}
 	if !choiceutil.Valid(o, choices) {
-		return fmt.Errorf("alert should be one of: %#v", choices)
+		return fmt.Errorf("alert should be one of: %#v or an extended resource name", choices)
 	}
 	return nil
 }
 
+// ValidForPods checks o is valid and applies to pods. Pod slots and extended
+// resources are alerted on nodes only, as pods report no usage for them.
+func ValidForPods(o Alert) error {
+	if err := Valid(o); err != nil {
+		return err
+	}
+	if _, ok := ExtendedResource(o); ok || o == Extended || o == Pods {
+		return fmt.Errorf("alert %q applies to nodes only", o)
+	}
+	return nil
+}
+
+// ExtendedResource returns the extended resource name the alert selects.
+func ExtendedResource(o Alert) (string, bool) {
+	if !resources.IsExtended(resources.Resource(o)) {
+		return "", false
+	}
+	return string(o), true
+}
+
 func StringList(separator string) string {
 	return choiceutil.StringList(choices, separator)
 }
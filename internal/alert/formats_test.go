@@ -11,7 +11,8 @@ func TestValid(t *testing.T) {
 		validAlerts := []Alert{
 			Any, Memory, MemoryRequest, MemoryLimit,
 			CPU, CPURequest, CPULimit,
-			Storage, StorageEphemeral, None,
+			Storage, StorageEphemeral, Pods, OOM, Extended, None,
+			Alert("nvidia.com/gpu"), Alert("hugepages-2Mi"),
 		}
 		for _, alert := range validAlerts {
 			err := Valid(alert)
@@ -26,6 +27,27 @@ func TestValid(t *testing.T) {
 	})
 }
 
+func TestValidForPods(t *testing.T) {
+	for _, alert := range []Alert{Any, Memory, CPULimit, Storage, StorageEphemeral, OOM, None} {
+		require.NoError(t, ValidForPods(alert))
+	}
+	for _, alert := range []Alert{Pods, Extended, Alert("nvidia.com/gpu")} {
+		require.ErrorContains(t, ValidForPods(alert), "applies to nodes only")
+	}
+	require.ErrorContains(t, ValidForPods(Alert("invalid")), "alert should be one of")
+}
+
+func TestExtendedResource(t *testing.T) {
+	name, ok := ExtendedResource(Alert("nvidia.com/gpu"))
+	require.True(t, ok)
+	require.Equal(t, "nvidia.com/gpu", name)
+
+	_, ok = ExtendedResource(CPU)
+	require.False(t, ok)
+	_, ok = ExtendedResource(Extended)
+	require.False(t, ok)
+}
+
 func TestStringList(t *testing.T) {
 	t.Run("default separator", func(t *testing.T) {
 		list := StringListDefault()
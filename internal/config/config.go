@@ -9,7 +9,31 @@
 //	  alert: cpu|memory
 //	  watch-period: 10
 //	  watch: true
+//	  watch-history: 20           # Samples in watch trend sparklines, 0 hides them
 //	  timeout: 30
+//	  metrics-source: metrics-server|kubelet|prometheus
+//	  metrics-max-age: 180        # Seconds after which metrics are marked stale
+//	  server: https://10.0.0.1:6443 # Connection overrides as in kubectl
+//	  token: <bearer token>
+//	  as: jane
+//	  as-group:
+//	    - developers
+//	  certificate-authority: /path/to/ca.crt
+//	  insecure-skip-tls-verify: false
+//	  request-timeout: 10s        # A duration or seconds, 0 waits forever
+//	  client-qps: 10              # Client rate limit
+//	  client-burst: 20
+//	  retry:                      # Retries of API requests failing with transient errors
+//	    retries: 3                # 0 disables retries
+//	    delay: 250ms              # First backoff delay, doubled on every retry
+//	    max-delay: 5s
+//	  prometheus:                 # Used with metrics-source: prometheus
+//	    url: http://prometheus:9090
+//	    rate-window: 5m
+//	    pod-cpu-query: sum by (namespace, pod, container) (rate(...[$window]))
+//	    pod-memory-query: ...
+//	    node-cpu-query: ...
+//	    node-memory-query: ...
 //	  columns:                    # Filter table columns (table output only)
 //	    - request
 //	    - limit
@@ -31,23 +55,50 @@
 //	  resources:
 //	    - cpu
 //	    - memory
+//	  qos:                        # Filter pods by QoS class
+//	    - burstable
+//	    - besteffort
 //	summary:
 //	  name: node-name
 //	  label: kubernetes.io/role=master
 //	  sorting: used_cpu|used_memory|name
 //	  reverse: false
+//	  include-terminated: false
+//	  group-by-qos: false
+//	  schedulable-only: false
+//	  group-by-label: topology.kubernetes.io/zone
+//	  resources:
+//	    - all
+//	workloads:
+//	  namespace: default          # Single namespace or a list, as for pods
+//	  label: app=nginx
+//	  nodes:
+//	    - node1
+//	  sorting: namespace|name|kind|replicas|used_cpu
+//	  reverse: false
+//	  resources:
+//	    - cpu
+//	    - memory
+//	namespaces:
+//	  namespace:                  # Empty lists every namespace
+//	    - team-a
+//	    - team-b
+//	  sorting: name|pods|used_cpu|quota_request_cpu|quota_pods
+//	  reverse: false
 //	  resources:
 //	    - all
 //
 // Merge Behavior:
 //   - CLI flags take precedence over file config values
 //   - Empty/zero values from CLI are replaced with file config values
+//   - metrics-max-age and watch-history of the file are kept when zero
 //   - Boolean values from file are used unless the CLI flag is explicitly set.
 //     In the CLI adapter, explicit `--watch=false` / `--reverse=false` overrides file `true`.
 package config
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
 	"os"
 
@@ -55,16 +106,57 @@ import (
 )
 
 // Common holds shared configuration options applicable to all commands.
+// MetricsMaxAge and WatchHistory are nil when unset, zero disables them.
 type Common struct {
-	KubeConfig   string   `yaml:"kubeconfig"`
-	KubeContext  string   `yaml:"context"`
-	Output       string   `yaml:"output"`
-	TableView    string   `yaml:"table-view"`
-	Alert        string   `yaml:"alert"`
-	WatchPeriod  uint     `yaml:"watch-period"`
-	WatchMetrics bool     `yaml:"watch"`
-	Columns      []string `yaml:"columns"`
-	Timeout      uint     `yaml:"timeout"`
+	KubeConfig    string     `yaml:"kubeconfig"`
+	KubeContext   string     `yaml:"context"`
+	Output        string     `yaml:"output"`
+	TableView     string     `yaml:"table-view"`
+	Alert         string     `yaml:"alert"`
+	MetricsSource string     `yaml:"metrics-source"`
+	MetricsMaxAge *uint      `yaml:"metrics-max-age"`
+	WatchPeriod   uint       `yaml:"watch-period"`
+	WatchMetrics  bool       `yaml:"watch"`
+	WatchHistory  *uint      `yaml:"watch-history"`
+	Columns       []string   `yaml:"columns"`
+	Timeout       uint       `yaml:"timeout"`
+	Prometheus    Prometheus `yaml:"prometheus"`
+	Retry         Retry      `yaml:"retry"`
+	Connection    `yaml:",inline"`
+}
+
+// Retry configures retries of API requests failing with transient errors.
+// Delays are durations such as 250ms. Retries is nil when unset, zero
+// disables retries.
+type Retry struct {
+	Retries  *uint  `yaml:"retries"`
+	Delay    string `yaml:"delay"`
+	MaxDelay string `yaml:"max-delay"`
+}
+
+// Connection holds the kubectl connection overrides and the client rate limit
+// applied on top of the kubeconfig.
+type Connection struct {
+	Server                string   `yaml:"server"`
+	Token                 string   `yaml:"token"`
+	As                    string   `yaml:"as"`
+	AsGroups              []string `yaml:"as-group"`
+	CertificateAuthority  string   `yaml:"certificate-authority"`
+	InsecureSkipTLSVerify bool     `yaml:"insecure-skip-tls-verify"`
+	RequestTimeout        string   `yaml:"request-timeout"`
+	ClientQPS             float32  `yaml:"client-qps"`
+	ClientBurst           int      `yaml:"client-burst"`
+}
+
+// Prometheus configures the prometheus metrics source. Empty queries use the
+// built-in cAdvisor queries; $window is replaced with the rate window.
+type Prometheus struct {
+	URL             string `yaml:"url"`
+	RateWindow      string `yaml:"rate-window"`
+	PodCPUQuery     string `yaml:"pod-cpu-query"`
+	PodMemoryQuery  string `yaml:"pod-memory-query"`
+	NodeCPUQuery    string `yaml:"node-cpu-query"`
+	NodeMemoryQuery string `yaml:"node-memory-query"`
 }
 
 // StringOrSlice is a custom type that can unmarshal from either a string or a slice of strings in YAML.
@@ -95,22 +187,66 @@ type Pods struct {
 	Sorting       string        `yaml:"sorting"`
 	Reverse       bool          `yaml:"reverse"`
 	Resources     []string      `yaml:"resources"`
+	QOS           []string      `yaml:"qos"`
 }
 
 // Summary holds configuration specific to the summary command.
 type Summary struct {
-	Name      string   `yaml:"name"`
-	Label     string   `yaml:"label"`
-	Sorting   string   `yaml:"sorting"`
-	Reverse   bool     `yaml:"reverse"`
-	Resources []string `yaml:"resources"`
+	Name              string   `yaml:"name"`
+	Label             string   `yaml:"label"`
+	Sorting           string   `yaml:"sorting"`
+	Reverse           bool     `yaml:"reverse"`
+	Resources         []string `yaml:"resources"`
+	IncludeTerminated bool     `yaml:"include-terminated"`
+	GroupByQOS        bool     `yaml:"group-by-qos"`
+	SchedulableOnly   bool     `yaml:"schedulable-only"`
+	GroupByLabel      string   `yaml:"group-by-label"`
+}
+
+// Workloads holds configuration specific to the workloads command.
+type Workloads struct {
+	Namespaces    StringOrSlice `yaml:"namespace"`
+	Label         string        `yaml:"label"`
+	FieldSelector string        `yaml:"field-selector"`
+	Nodes         []string      `yaml:"nodes"`
+	Sorting       string        `yaml:"sorting"`
+	Reverse       bool          `yaml:"reverse"`
+	Resources     []string      `yaml:"resources"`
+}
+
+// Recommend holds configuration specific to the recommend command. Durations
+// and policies use the command line syntax, such as 10m and max*1.2.
+type Recommend struct {
+	Namespaces          StringOrSlice `yaml:"namespace"`
+	Label               string        `yaml:"label"`
+	FieldSelector       string        `yaml:"field-selector"`
+	Nodes               []string      `yaml:"nodes"`
+	Duration            string        `yaml:"duration"`
+	Interval            string        `yaml:"interval"`
+	CPURequestPolicy    string        `yaml:"cpu-request-policy"`
+	CPULimitPolicy      string        `yaml:"cpu-limit-policy"`
+	MemoryRequestPolicy string        `yaml:"memory-request-policy"`
+	MemoryLimitPolicy   string        `yaml:"memory-limit-policy"`
+}
+
+// Namespaces holds configuration specific to the namespaces command.
+type Namespaces struct {
+	Namespaces    StringOrSlice `yaml:"namespace"`
+	Label         string        `yaml:"label"`
+	FieldSelector string        `yaml:"field-selector"`
+	Sorting       string        `yaml:"sorting"`
+	Reverse       bool          `yaml:"reverse"`
+	Resources     []string      `yaml:"resources"`
 }
 
 // Config represents the complete configuration file structure.
 type Config struct {
-	Common  Common  `yaml:"common"`
-	Pods    Pods    `yaml:"pods"`
-	Summary Summary `yaml:"summary"`
+	Common     Common     `yaml:"common"`
+	Pods       Pods       `yaml:"pods"`
+	Summary    Summary    `yaml:"summary"`
+	Workloads  Workloads  `yaml:"workloads"`
+	Namespaces Namespaces `yaml:"namespaces"`
+	Recommend  Recommend  `yaml:"recommend"`
 }
 
 // Load reads and parses a YAML configuration file from the given path.
@@ -133,7 +269,8 @@ func Load(path string) (*Config, error) {
 
 // MergeCommon merges file config values into the provided Common struct.
 // Only empty/zero values in the target are replaced with file config values.
-// Note: For boolean WatchMetrics, file's true will override target's false.
+// Note: For booleans WatchMetrics and InsecureSkipTLSVerify, file's true will
+// override target's false.
 func (c *Config) MergeCommon(common *Common) {
 	if common.KubeConfig == "" && c.Common.KubeConfig != "" {
 		common.KubeConfig = c.Common.KubeConfig
@@ -150,9 +287,18 @@ func (c *Config) MergeCommon(common *Common) {
 	if common.Alert == "" && c.Common.Alert != "" {
 		common.Alert = c.Common.Alert
 	}
+	if common.MetricsSource == "" && c.Common.MetricsSource != "" {
+		common.MetricsSource = c.Common.MetricsSource
+	}
+	if common.MetricsMaxAge == nil {
+		common.MetricsMaxAge = c.Common.MetricsMaxAge
+	}
 	if common.WatchPeriod == 0 && c.Common.WatchPeriod != 0 {
 		common.WatchPeriod = c.Common.WatchPeriod
 	}
+	if common.WatchHistory == nil {
+		common.WatchHistory = c.Common.WatchHistory
+	}
 	if !common.WatchMetrics && c.Common.WatchMetrics {
 		common.WatchMetrics = c.Common.WatchMetrics
 	}
@@ -162,6 +308,40 @@ func (c *Config) MergeCommon(common *Common) {
 	if common.Timeout == 0 && c.Common.Timeout != 0 {
 		common.Timeout = c.Common.Timeout
 	}
+	c.Common.Prometheus.merge(&common.Prometheus)
+	c.Common.Retry.merge(&common.Retry)
+	c.Common.Connection.merge(&common.Connection)
+}
+
+func (r Retry) merge(target *Retry) {
+	if target.Retries == nil {
+		target.Retries = r.Retries
+	}
+	target.Delay = cmp.Or(target.Delay, r.Delay)
+	target.MaxDelay = cmp.Or(target.MaxDelay, r.MaxDelay)
+}
+
+func (c Connection) merge(target *Connection) {
+	target.Server = cmp.Or(target.Server, c.Server)
+	target.Token = cmp.Or(target.Token, c.Token)
+	target.As = cmp.Or(target.As, c.As)
+	if len(target.AsGroups) == 0 {
+		target.AsGroups = c.AsGroups
+	}
+	target.CertificateAuthority = cmp.Or(target.CertificateAuthority, c.CertificateAuthority)
+	target.InsecureSkipTLSVerify = target.InsecureSkipTLSVerify || c.InsecureSkipTLSVerify
+	target.RequestTimeout = cmp.Or(target.RequestTimeout, c.RequestTimeout)
+	target.ClientQPS = cmp.Or(target.ClientQPS, c.ClientQPS)
+	target.ClientBurst = cmp.Or(target.ClientBurst, c.ClientBurst)
+}
+
+func (p Prometheus) merge(target *Prometheus) {
+	target.URL = cmp.Or(target.URL, p.URL)
+	target.RateWindow = cmp.Or(target.RateWindow, p.RateWindow)
+	target.PodCPUQuery = cmp.Or(target.PodCPUQuery, p.PodCPUQuery)
+	target.PodMemoryQuery = cmp.Or(target.PodMemoryQuery, p.PodMemoryQuery)
+	target.NodeCPUQuery = cmp.Or(target.NodeCPUQuery, p.NodeCPUQuery)
+	target.NodeMemoryQuery = cmp.Or(target.NodeMemoryQuery, p.NodeMemoryQuery)
 }
 
 // MergePods merges file config values into the provided Pods struct.
@@ -189,11 +369,14 @@ func (c *Config) MergePods(pods *Pods) {
 	if len(pods.Resources) == 0 && len(c.Pods.Resources) > 0 {
 		pods.Resources = c.Pods.Resources
 	}
+	if len(pods.QOS) == 0 && len(c.Pods.QOS) > 0 {
+		pods.QOS = c.Pods.QOS
+	}
 }
 
 // MergeSummary merges file config values into the provided Summary struct.
 // Only empty/zero values in the target are replaced with file config values.
-// Note: For boolean Reverse, file's true will override target's false.
+// Note: For booleans Reverse, IncludeTerminated, GroupByQOS and SchedulableOnly, file's true will override target's false.
 func (c *Config) MergeSummary(summary *Summary) {
 	if summary.Name == "" && c.Summary.Name != "" {
 		summary.Name = c.Summary.Name
@@ -210,4 +393,102 @@ func (c *Config) MergeSummary(summary *Summary) {
 	if len(summary.Resources) == 0 && len(c.Summary.Resources) > 0 {
 		summary.Resources = c.Summary.Resources
 	}
+	if !summary.IncludeTerminated && c.Summary.IncludeTerminated {
+		summary.IncludeTerminated = c.Summary.IncludeTerminated
+	}
+	if !summary.GroupByQOS && c.Summary.GroupByQOS {
+		summary.GroupByQOS = c.Summary.GroupByQOS
+	}
+	if !summary.SchedulableOnly && c.Summary.SchedulableOnly {
+		summary.SchedulableOnly = c.Summary.SchedulableOnly
+	}
+	if summary.GroupByLabel == "" && c.Summary.GroupByLabel != "" {
+		summary.GroupByLabel = c.Summary.GroupByLabel
+	}
+}
+
+// MergeWorkloads merges file config values into the provided Workloads struct.
+// Only empty/zero values in the target are replaced with file config values.
+// Note: For boolean Reverse, file's true will override target's false.
+func (c *Config) MergeWorkloads(workloads *Workloads) {
+	if len(workloads.Namespaces) == 0 && len(c.Workloads.Namespaces) > 0 {
+		workloads.Namespaces = c.Workloads.Namespaces
+	}
+	if workloads.Label == "" && c.Workloads.Label != "" {
+		workloads.Label = c.Workloads.Label
+	}
+	if workloads.FieldSelector == "" && c.Workloads.FieldSelector != "" {
+		workloads.FieldSelector = c.Workloads.FieldSelector
+	}
+	if len(workloads.Nodes) == 0 && len(c.Workloads.Nodes) > 0 {
+		workloads.Nodes = c.Workloads.Nodes
+	}
+	if workloads.Sorting == "" && c.Workloads.Sorting != "" {
+		workloads.Sorting = c.Workloads.Sorting
+	}
+	if !workloads.Reverse && c.Workloads.Reverse {
+		workloads.Reverse = c.Workloads.Reverse
+	}
+	if len(workloads.Resources) == 0 && len(c.Workloads.Resources) > 0 {
+		workloads.Resources = c.Workloads.Resources
+	}
+}
+
+// MergeNamespaces merges file config values into the provided Namespaces struct.
+// Only empty/zero values in the target are replaced with file config values.
+// Note: For boolean Reverse, file's true will override target's false.
+func (c *Config) MergeNamespaces(namespaces *Namespaces) {
+	if len(namespaces.Namespaces) == 0 && len(c.Namespaces.Namespaces) > 0 {
+		namespaces.Namespaces = c.Namespaces.Namespaces
+	}
+	if namespaces.Label == "" && c.Namespaces.Label != "" {
+		namespaces.Label = c.Namespaces.Label
+	}
+	if namespaces.FieldSelector == "" && c.Namespaces.FieldSelector != "" {
+		namespaces.FieldSelector = c.Namespaces.FieldSelector
+	}
+	if namespaces.Sorting == "" && c.Namespaces.Sorting != "" {
+		namespaces.Sorting = c.Namespaces.Sorting
+	}
+	if !namespaces.Reverse && c.Namespaces.Reverse {
+		namespaces.Reverse = c.Namespaces.Reverse
+	}
+	if len(namespaces.Resources) == 0 && len(c.Namespaces.Resources) > 0 {
+		namespaces.Resources = c.Namespaces.Resources
+	}
+}
+
+// MergeRecommend merges file config values into the provided Recommend struct.
+// Only empty values in the target are replaced with file config values.
+func (c *Config) MergeRecommend(recommend *Recommend) {
+	if len(recommend.Namespaces) == 0 && len(c.Recommend.Namespaces) > 0 {
+		recommend.Namespaces = c.Recommend.Namespaces
+	}
+	if recommend.Label == "" && c.Recommend.Label != "" {
+		recommend.Label = c.Recommend.Label
+	}
+	if recommend.FieldSelector == "" && c.Recommend.FieldSelector != "" {
+		recommend.FieldSelector = c.Recommend.FieldSelector
+	}
+	if len(recommend.Nodes) == 0 && len(c.Recommend.Nodes) > 0 {
+		recommend.Nodes = c.Recommend.Nodes
+	}
+	if recommend.Duration == "" && c.Recommend.Duration != "" {
+		recommend.Duration = c.Recommend.Duration
+	}
+	if recommend.Interval == "" && c.Recommend.Interval != "" {
+		recommend.Interval = c.Recommend.Interval
+	}
+	if recommend.CPURequestPolicy == "" && c.Recommend.CPURequestPolicy != "" {
+		recommend.CPURequestPolicy = c.Recommend.CPURequestPolicy
+	}
+	if recommend.CPULimitPolicy == "" && c.Recommend.CPULimitPolicy != "" {
+		recommend.CPULimitPolicy = c.Recommend.CPULimitPolicy
+	}
+	if recommend.MemoryRequestPolicy == "" && c.Recommend.MemoryRequestPolicy != "" {
+		recommend.MemoryRequestPolicy = c.Recommend.MemoryRequestPolicy
+	}
+	if recommend.MemoryLimitPolicy == "" && c.Recommend.MemoryLimitPolicy != "" {
+		recommend.MemoryLimitPolicy = c.Recommend.MemoryLimitPolicy
+	}
 }
@@ -135,14 +135,17 @@ func TestMergeCommon(t *testing.T) {
 	t.Run("merges empty values from file", func(t *testing.T) {
 		fileConfig := &Config{
 			Common: Common{
-				KubeConfig:   "/path/to/kubeconfig",
-				KubeContext:  "my-context",
-				Output:       "json",
-				TableView:    "compact",
-				Alert:        "cpu",
-				WatchPeriod:  10,
-				WatchMetrics: true,
-				Timeout:      45,
+				KubeConfig:    "/path/to/kubeconfig",
+				KubeContext:   "my-context",
+				Output:        "json",
+				TableView:     "compact",
+				Alert:         "cpu",
+				MetricsSource: "kubelet",
+				MetricsMaxAge: new(uint(300)),
+				WatchPeriod:   10,
+				WatchMetrics:  true,
+				WatchHistory:  new(uint(30)),
+				Timeout:       45,
 			},
 		}
 		common := &Common{}
@@ -153,31 +156,38 @@ func TestMergeCommon(t *testing.T) {
 		require.Equal(t, "json", common.Output)
 		require.Equal(t, "compact", common.TableView)
 		require.Equal(t, "cpu", common.Alert)
+		require.Equal(t, "kubelet", common.MetricsSource)
+		require.Equal(t, uint(300), *common.MetricsMaxAge)
 		require.Equal(t, uint(10), common.WatchPeriod)
 		require.True(t, common.WatchMetrics)
+		require.Equal(t, uint(30), *common.WatchHistory)
 		require.Equal(t, uint(45), common.Timeout)
 	})
 
 	t.Run("cli string and numeric values take precedence", func(t *testing.T) {
 		fileConfig := &Config{
 			Common: Common{
-				KubeConfig:  "/file/kubeconfig",
-				KubeContext: "file-context",
-				Output:      "yaml",
-				TableView:   "compact",
-				Alert:       "memory",
-				WatchPeriod: 5,
-				Timeout:     20,
+				KubeConfig:    "/file/kubeconfig",
+				KubeContext:   "file-context",
+				Output:        "yaml",
+				TableView:     "compact",
+				Alert:         "memory",
+				MetricsSource: "kubelet",
+				MetricsMaxAge: new(uint(300)),
+				WatchPeriod:   5,
+				Timeout:       20,
 			},
 		}
 		common := &Common{
-			KubeConfig:  "/cli/kubeconfig",
-			KubeContext: "cli-context",
-			Output:      "json",
-			TableView:   "expanded",
-			Alert:       "cpu",
-			WatchPeriod: 15,
-			Timeout:     10,
+			KubeConfig:    "/cli/kubeconfig",
+			KubeContext:   "cli-context",
+			Output:        "json",
+			TableView:     "expanded",
+			Alert:         "cpu",
+			MetricsSource: "metrics-server",
+			MetricsMaxAge: new(uint(60)),
+			WatchPeriod:   15,
+			Timeout:       10,
 		}
 
 		fileConfig.MergeCommon(common)
@@ -186,10 +196,89 @@ func TestMergeCommon(t *testing.T) {
 		require.Equal(t, "json", common.Output)
 		require.Equal(t, "expanded", common.TableView)
 		require.Equal(t, "cpu", common.Alert)
+		require.Equal(t, "metrics-server", common.MetricsSource)
+		require.Equal(t, uint(60), *common.MetricsMaxAge)
 		require.Equal(t, uint(15), common.WatchPeriod)
 		require.Equal(t, uint(10), common.Timeout)
 	})
 
+	t.Run("keeps explicit zero values from file", func(t *testing.T) {
+		fileConfig := &Config{Common: Common{MetricsMaxAge: new(uint(0)), WatchHistory: new(uint(0))}}
+		common := &Common{}
+
+		fileConfig.MergeCommon(common)
+		require.NotNil(t, common.MetricsMaxAge)
+		require.Zero(t, *common.MetricsMaxAge)
+		require.NotNil(t, common.WatchHistory)
+		require.Zero(t, *common.WatchHistory)
+	})
+
+	t.Run("merges prometheus settings field by field", func(t *testing.T) {
+		fileConfig := &Config{
+			Common: Common{
+				Prometheus: Prometheus{
+					URL:         "http://file:9090",
+					RateWindow:  "10m",
+					PodCPUQuery: "file_pod_cpu",
+				},
+			},
+		}
+		common := &Common{Prometheus: Prometheus{URL: "http://cli:9090"}}
+
+		fileConfig.MergeCommon(common)
+		require.Equal(t, "http://cli:9090", common.Prometheus.URL)
+		require.Equal(t, "10m", common.Prometheus.RateWindow)
+		require.Equal(t, "file_pod_cpu", common.Prometheus.PodCPUQuery)
+		require.Empty(t, common.Prometheus.NodeCPUQuery)
+	})
+
+	t.Run("merges retry settings field by field", func(t *testing.T) {
+		fileConfig := &Config{Common: Common{Retry: Retry{Retries: new(uint(5)), Delay: "1s", MaxDelay: "30s"}}}
+		common := &Common{Retry: Retry{Delay: "100ms"}}
+
+		fileConfig.MergeCommon(common)
+		require.Equal(t, Retry{Retries: new(uint(5)), Delay: "100ms", MaxDelay: "30s"}, common.Retry)
+	})
+
+	t.Run("keeps zero retries from the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("common:\n  retry:\n    retries: 0\n"), 0o600))
+		fileConfig, err := Load(path)
+		require.NoError(t, err)
+
+		common := &Common{}
+		fileConfig.MergeCommon(common)
+		require.NotNil(t, common.Retry.Retries)
+		require.Zero(t, *common.Retry.Retries)
+	})
+
+	t.Run("merges connection settings field by field", func(t *testing.T) {
+		fileConfig := &Config{
+			Common: Common{
+				Connection: Connection{
+					Server:                "https://file:6443",
+					Token:                 "file-token",
+					AsGroups:              []string{"developers"},
+					InsecureSkipTLSVerify: true,
+					RequestTimeout:        "10s",
+					ClientQPS:             50,
+					ClientBurst:           100,
+				},
+			},
+		}
+		common := &Common{Connection: Connection{Server: "https://cli:6443", ClientBurst: 40}}
+
+		fileConfig.MergeCommon(common)
+		require.Equal(t, "https://cli:6443", common.Server)
+		require.Equal(t, "file-token", common.Token)
+		require.Empty(t, common.As)
+		require.Equal(t, []string{"developers"}, common.AsGroups)
+		require.True(t, common.InsecureSkipTLSVerify)
+		require.Equal(t, "10s", common.RequestTimeout)
+		require.Equal(t, float32(50), common.ClientQPS)
+		require.Equal(t, 40, common.ClientBurst)
+	})
+
 	// Note: Boolean fields have a limitation - CLI default false cannot override file's true.
 	// This is by design since CLI boolean flags cannot distinguish "not set" from "explicitly false".
 	// If file has watch: true, the merged value will be true even if CLI doesn't pass --watch.
@@ -257,6 +346,7 @@ func TestMergePods(t *testing.T) {
 				Sorting:       "name",
 				Reverse:       true,
 				Resources:     []string{"cpu", "memory"},
+				QOS:           []string{"burstable"},
 			},
 		}
 		pods := &Pods{}
@@ -269,6 +359,7 @@ func TestMergePods(t *testing.T) {
 		require.Equal(t, "name", pods.Sorting)
 		require.True(t, pods.Reverse)
 		require.Equal(t, []string{"cpu", "memory"}, pods.Resources)
+		require.Equal(t, []string{"burstable"}, pods.QOS)
 	})
 
 	t.Run("cli string and slice values take precedence", func(t *testing.T) {
@@ -280,6 +371,7 @@ func TestMergePods(t *testing.T) {
 				Nodes:         []string{"file-node"},
 				Sorting:       "namespace",
 				Resources:     []string{"file-res"},
+				QOS:           []string{"guaranteed"},
 			},
 		}
 		pods := &Pods{
@@ -289,6 +381,7 @@ func TestMergePods(t *testing.T) {
 			Nodes:         []string{"cli-node"},
 			Sorting:       "name",
 			Resources:     []string{"cli-res"},
+			QOS:           []string{"besteffort"},
 		}
 
 		fileConfig.MergePods(pods)
@@ -298,6 +391,7 @@ func TestMergePods(t *testing.T) {
 		require.Equal(t, []string{"cli-node"}, pods.Nodes)
 		require.Equal(t, "name", pods.Sorting)
 		require.Equal(t, []string{"cli-res"}, pods.Resources)
+		require.Equal(t, []string{"besteffort"}, pods.QOS)
 	})
 
 	// Note: Boolean fields have a limitation - CLI default false cannot override file's true.
@@ -320,16 +414,24 @@ func TestMergeSummary(t *testing.T) {
 	t.Run("merges empty values from file", func(t *testing.T) {
 		fileConfig := &Config{
 			Summary: Summary{
-				Name:      "node-name",
-				Label:     "kubernetes.io/role=master",
-				Sorting:   "used_cpu",
-				Reverse:   true,
-				Resources: []string{"cpu", "memory"},
+				Name:              "node-name",
+				Label:             "kubernetes.io/role=master",
+				Sorting:           "used_cpu",
+				Reverse:           true,
+				Resources:         []string{"cpu", "memory"},
+				IncludeTerminated: true,
+				GroupByQOS:        true,
+				SchedulableOnly:   true,
+				GroupByLabel:      "topology.kubernetes.io/zone",
 			},
 		}
 		summary := &Summary{}
 
 		fileConfig.MergeSummary(summary)
+		require.True(t, summary.IncludeTerminated)
+		require.True(t, summary.GroupByQOS)
+		require.True(t, summary.SchedulableOnly)
+		require.Equal(t, "topology.kubernetes.io/zone", summary.GroupByLabel)
 		require.Equal(t, "node-name", summary.Name)
 		require.Equal(t, "kubernetes.io/role=master", summary.Label)
 		require.Equal(t, "used_cpu", summary.Sorting)
@@ -340,17 +442,19 @@ func TestMergeSummary(t *testing.T) {
 	t.Run("cli string and slice values take precedence", func(t *testing.T) {
 		fileConfig := &Config{
 			Summary: Summary{
-				Name:      "file-node",
-				Label:     "file=label",
-				Sorting:   "name",
-				Resources: []string{"file-res"},
+				Name:         "file-node",
+				Label:        "file=label",
+				Sorting:      "name",
+				Resources:    []string{"file-res"},
+				GroupByLabel: "file-zone",
 			},
 		}
 		summary := &Summary{
-			Name:      "cli-node",
-			Label:     "cli=label",
-			Sorting:   "used_cpu",
-			Resources: []string{"cli-res"},
+			Name:         "cli-node",
+			Label:        "cli=label",
+			Sorting:      "used_cpu",
+			Resources:    []string{"cli-res"},
+			GroupByLabel: "cli-zone",
 		}
 
 		fileConfig.MergeSummary(summary)
@@ -358,6 +462,7 @@ func TestMergeSummary(t *testing.T) {
 		require.Equal(t, "cli=label", summary.Label)
 		require.Equal(t, "used_cpu", summary.Sorting)
 		require.Equal(t, []string{"cli-res"}, summary.Resources)
+		require.Equal(t, "cli-zone", summary.GroupByLabel)
 	})
 
 	// Note: Boolean fields have a limitation - CLI default false cannot override file's true.
@@ -375,3 +480,126 @@ func TestMergeSummary(t *testing.T) {
 		require.True(t, summary.Reverse) // File's true overrides CLI's default false
 	})
 }
+
+func TestMergeWorkloads(t *testing.T) {
+	t.Run("merges empty values from file", func(t *testing.T) {
+		fileConfig := &Config{
+			Workloads: Workloads{
+				Namespaces:    StringOrSlice{"default"},
+				Label:         "app=nginx",
+				FieldSelector: "status.phase=Running",
+				Nodes:         []string{"node1"},
+				Sorting:       "replicas",
+				Reverse:       true,
+				Resources:     []string{"cpu"},
+			},
+		}
+		workloads := &Workloads{}
+
+		fileConfig.MergeWorkloads(workloads)
+		require.Equal(t, StringOrSlice{"default"}, workloads.Namespaces)
+		require.Equal(t, "app=nginx", workloads.Label)
+		require.Equal(t, "status.phase=Running", workloads.FieldSelector)
+		require.Equal(t, []string{"node1"}, workloads.Nodes)
+		require.Equal(t, "replicas", workloads.Sorting)
+		require.True(t, workloads.Reverse)
+		require.Equal(t, []string{"cpu"}, workloads.Resources)
+	})
+
+	t.Run("cli values take precedence", func(t *testing.T) {
+		fileConfig := &Config{
+			Workloads: Workloads{
+				Namespaces: StringOrSlice{"file-ns"},
+				Sorting:    "name",
+				Resources:  []string{"memory"},
+			},
+		}
+		workloads := &Workloads{
+			Namespaces: StringOrSlice{"cli-ns"},
+			Sorting:    "kind",
+			Resources:  []string{"cpu"},
+		}
+
+		fileConfig.MergeWorkloads(workloads)
+		require.Equal(t, StringOrSlice{"cli-ns"}, workloads.Namespaces)
+		require.Equal(t, "kind", workloads.Sorting)
+		require.Equal(t, []string{"cpu"}, workloads.Resources)
+	})
+}
+
+func TestMergeNamespaces(t *testing.T) {
+	t.Run("merges empty values from file", func(t *testing.T) {
+		fileConfig := &Config{
+			Namespaces: Namespaces{
+				Namespaces:    StringOrSlice{"team-a"},
+				Label:         "tier=backend",
+				FieldSelector: "status.phase=Running",
+				Sorting:       "quota_pods",
+				Reverse:       true,
+				Resources:     []string{"pods"},
+			},
+		}
+		namespaces := &Namespaces{}
+
+		fileConfig.MergeNamespaces(namespaces)
+		require.Equal(t, StringOrSlice{"team-a"}, namespaces.Namespaces)
+		require.Equal(t, "tier=backend", namespaces.Label)
+		require.Equal(t, "status.phase=Running", namespaces.FieldSelector)
+		require.Equal(t, "quota_pods", namespaces.Sorting)
+		require.True(t, namespaces.Reverse)
+		require.Equal(t, []string{"pods"}, namespaces.Resources)
+	})
+
+	t.Run("cli values take precedence", func(t *testing.T) {
+		fileConfig := &Config{
+			Namespaces: Namespaces{
+				Namespaces: StringOrSlice{"file-ns"},
+				Sorting:    "name",
+				Resources:  []string{"memory"},
+			},
+		}
+		namespaces := &Namespaces{
+			Namespaces: StringOrSlice{"cli-ns"},
+			Sorting:    "pods",
+			Resources:  []string{"cpu"},
+		}
+
+		fileConfig.MergeNamespaces(namespaces)
+		require.Equal(t, StringOrSlice{"cli-ns"}, namespaces.Namespaces)
+		require.Equal(t, "pods", namespaces.Sorting)
+		require.Equal(t, []string{"cpu"}, namespaces.Resources)
+	})
+}
+
+func TestMergeRecommend(t *testing.T) {
+	t.Run("merges empty values from file", func(t *testing.T) {
+		fileConfig := &Config{
+			Recommend: Recommend{
+				Namespaces:          StringOrSlice{"default"},
+				Label:               "app=nginx",
+				Nodes:               []string{"node1"},
+				Duration:            "1h",
+				Interval:            "1m",
+				CPURequestPolicy:    "p50",
+				CPULimitPolicy:      "none",
+				MemoryRequestPolicy: "p99",
+				MemoryLimitPolicy:   "max*1.5",
+			},
+		}
+		recommend := &Recommend{}
+
+		fileConfig.MergeRecommend(recommend)
+		require.Equal(t, fileConfig.Recommend, *recommend)
+	})
+
+	t.Run("cli values take precedence", func(t *testing.T) {
+		fileConfig := &Config{
+			Recommend: Recommend{Duration: "1h", CPULimitPolicy: "none"},
+		}
+		recommend := &Recommend{Duration: "5m", CPULimitPolicy: "max*2"}
+
+		fileConfig.MergeRecommend(recommend)
+		require.Equal(t, "5m", recommend.Duration)
+		require.Equal(t, "max*2", recommend.CPULimitPolicy)
+	})
+}
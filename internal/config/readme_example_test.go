@@ -21,13 +21,30 @@ func TestReadmeConfigExample(t *testing.T) {
 		require.Equal(t, []string{"request", "limit", "used"}, cfg.Common.Columns)
 		require.True(t, cfg.Common.WatchMetrics)
 		require.Equal(t, uint(45), cfg.Common.Timeout)
+		require.Equal(t, "kubelet", cfg.Common.MetricsSource)
+		require.Equal(t, "http://prometheus.monitoring:9090", cfg.Common.Prometheus.URL)
+		require.Equal(t, "5m", cfg.Common.Prometheus.RateWindow)
+		require.Equal(t, "jane", cfg.Common.As)
+		require.Equal(t, []string{"developers"}, cfg.Common.AsGroups)
+		require.Equal(t, "10s", cfg.Common.RequestTimeout)
+		require.Equal(t, float32(20), cfg.Common.ClientQPS)
+		require.Equal(t, 40, cfg.Common.ClientBurst)
+		require.Equal(t, Retry{Retries: new(uint(5)), Delay: "500ms", MaxDelay: "10s"}, cfg.Common.Retry)
 
 		require.Equal(t, StringOrSlice{"default"}, cfg.Pods.Namespaces)
 		require.Equal(t, []string{"node1", "node2"}, cfg.Pods.Nodes)
 		require.Equal(t, []string{"cpu", "memory"}, cfg.Pods.Resources)
+		require.Equal(t, []string{"burstable", "besteffort"}, cfg.Pods.QOS)
 
 		require.Equal(t, "used_cpu", cfg.Summary.Sorting)
 		require.Equal(t, []string{"all"}, cfg.Summary.Resources)
+		require.True(t, cfg.Summary.GroupByQOS)
+		require.Equal(t, "topology.kubernetes.io/zone", cfg.Summary.GroupByLabel)
+
+		require.Equal(t, "replicas", cfg.Workloads.Sorting)
+		require.Equal(t, StringOrSlice{"team-a", "team-b"}, cfg.Namespaces.Namespaces)
+		require.Equal(t, "1h", cfg.Recommend.Duration)
+		require.Equal(t, "none", cfg.Recommend.CPULimitPolicy)
 	})
 
 	t.Run("invalid key", func(t *testing.T) {
@@ -0,0 +1,480 @@
+// Package diff compares two results of pods and nodes, such as snapshots
+// recorded before and after a deploy, and reports what was added, removed or
+// changed.
+package diff
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+	"github.com/trezorg/k8spodsmetrics/pkg/owners"
+)
+
+type Change string
+
+const (
+	Added   Change = "added"
+	Removed Change = "removed"
+	Changed Change = "changed"
+)
+
+// Default thresholds ignore usage moving by less than 10m CPU, 16MiB memory
+// or 10 percent.
+const (
+	DefaultCPUThreshold     int64   = 10
+	DefaultMemoryThreshold  int64   = 16 << 20
+	DefaultPercentThreshold float64 = 10
+)
+
+// Thresholds tell usage noise from changes. A usage value changes when it
+// moves by at least CPU millicores or Memory bytes and by at least Percent of
+// its previous value. Requests, limits and allocatable resources change on
+// any difference.
+type Thresholds struct {
+	CPU     int64
+	Memory  int64
+	Percent float64
+}
+
+func DefaultThresholds() Thresholds {
+	return Thresholds{CPU: DefaultCPUThreshold, Memory: DefaultMemoryThreshold, Percent: DefaultPercentThreshold}
+}
+
+// Value is a value before and after, CPU in millicores and memory in bytes.
+// Added objects have a zero Before and removed ones a zero After.
+type Value struct {
+	Before int64 `json:"before" yaml:"before"`
+	After  int64 `json:"after" yaml:"after"`
+	Delta  int64 `json:"delta" yaml:"delta"`
+}
+
+// Resources holds the CPU and memory values that differ, nil values did not
+// change or are unknown.
+type Resources struct {
+	CPU    *Value `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	Memory *Value `json:"memory,omitempty" yaml:"memory,omitempty"`
+}
+
+// Container is a container added to, removed from or changed in a pod or
+// workload. Values of a workload are the sums over its replicas.
+type Container struct {
+	Name     string    `json:"name" yaml:"name"`
+	Change   Change    `json:"change" yaml:"change"`
+	Requests Resources `json:"requests,omitzero" yaml:"requests,omitempty"`
+	Limits   Resources `json:"limits,omitzero" yaml:"limits,omitempty"`
+	Used     Resources `json:"used,omitzero" yaml:"used,omitempty"`
+}
+
+// Pod is a pod without a controller or the pods of a workload, such as a
+// Deployment, added, removed or changed. Kind is the workload kind, empty for
+// a pod without a controller, and Node is only set for such a pod.
+type Pod struct {
+	Cluster   string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	Kind      string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Name      string `json:"name" yaml:"name"`
+	Node      string `json:"node,omitempty" yaml:"node,omitempty"`
+	Change    Change `json:"change" yaml:"change"`
+	// Replicas is set when the number of pods of a workload changed.
+	Replicas   *Value      `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	Containers []Container `json:"containers,omitempty" yaml:"containers,omitempty"`
+}
+
+type Node struct {
+	Cluster     string    `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	Name        string    `json:"name" yaml:"name"`
+	Change      Change    `json:"change" yaml:"change"`
+	Allocatable Resources `json:"allocatable,omitzero" yaml:"allocatable,omitempty"`
+	Requests    Resources `json:"requests,omitzero" yaml:"requests,omitempty"`
+	Limits      Resources `json:"limits,omitzero" yaml:"limits,omitempty"`
+	Used        Resources `json:"used,omitzero" yaml:"used,omitempty"`
+}
+
+// Report is the difference of two results. Pods and Nodes are nil when that
+// kind was not compared and empty when nothing changed.
+type Report struct {
+	Before time.Time `json:"before" yaml:"before"`
+	After  time.Time `json:"after" yaml:"after"`
+	Pods   []Pod     `json:"pods,omitempty" yaml:"pods,omitempty"`
+	Nodes  []Node    `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	// PodsCompared and NodesCompared tell an empty difference from a kind
+	// that was not compared.
+	PodsCompared  bool `json:"pods_compared" yaml:"pods_compared"`
+	NodesCompared bool `json:"nodes_compared" yaml:"nodes_compared"`
+}
+
+// Empty reports whether nothing changed.
+func (r Report) Empty() bool {
+	return len(r.Pods) == 0 && len(r.Nodes) == 0
+}
+
+func (r Resources) empty() bool {
+	return r.CPU == nil && r.Memory == nil
+}
+
+// usage is a container or node usage, unknown when metrics were missing.
+type usage struct {
+	cpu    int64
+	memory int64
+	known  bool
+}
+
+type resources struct {
+	requestCPU, requestMemory int64
+	limitCPU, limitMemory     int64
+	used                      usage
+}
+
+// podKey is the cluster and topmost controller of a pod, the pod itself when
+// it has no controller.
+type podKey struct {
+	cluster string
+	ref     owners.Reference
+}
+
+// replicas are the pods of one podKey.
+type replicas []metricsresources.PodMetricsResource
+
+type nodeKey struct {
+	cluster, name string
+}
+
+// Pods compares the pods of workloads by cluster, namespace, workload kind and
+// name, as pod names change when a workload is rolled out, and pods without a
+// controller by their name. Containers are matched by name. Unchanged pods
+// are left out, the result is sorted by key.
+func Pods(before, after metricsresources.PodMetricsResourceList, thresholds Thresholds) []Pod {
+	controllers := deploymentControllers(before, after)
+	beforeByKey := podsByKey(before, controllers)
+	afterByKey := podsByKey(after, controllers)
+	result := []Pod{}
+	for key, pods := range afterByKey {
+		previous, ok := beforeByKey[key]
+		if !ok {
+			result = append(result, newPod(key, pods, Added, podContainers(pods, Added)))
+			continue
+		}
+		pod := newPod(key, pods, Changed, diffContainers(previous, pods, thresholds))
+		if key.ref.Kind != owners.KindPod {
+			pod.Replicas = changedSpec(int64(len(previous)), int64(len(pods)))
+		}
+		if len(pod.Containers) > 0 || pod.Replicas != nil {
+			result = append(result, pod)
+		}
+	}
+	for key, pods := range beforeByKey {
+		if _, ok := afterByKey[key]; !ok {
+			result = append(result, newPod(key, pods, Removed, podContainers(pods, Removed)))
+		}
+	}
+	slices.SortFunc(result, func(a, b Pod) int {
+		return cmp.Or(
+			cmp.Compare(a.Cluster, b.Cluster),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return result
+}
+
+// Nodes compares nodes by cluster and name. Unchanged nodes are left out, the
+// result is sorted by key.
+func Nodes(before, after noderesources.NodeResourceList, thresholds Thresholds) []Node {
+	beforeByKey := nodesByKey(before)
+	afterByKey := nodesByKey(after)
+	result := []Node{}
+	for key, node := range afterByKey {
+		previous, ok := beforeByKey[key]
+		if !ok {
+			result = append(result, newNode(key, Added, resources{}, nodeResources(node), node.AllocatableCPU, node.AllocatableMemory))
+			continue
+		}
+		diffed := newNode(key, Changed, nodeResources(previous), nodeResources(node), 0, 0)
+		diffed.Allocatable = Resources{
+			CPU:    changedSpec(previous.AllocatableCPU, node.AllocatableCPU),
+			Memory: changedSpec(previous.AllocatableMemory, node.AllocatableMemory),
+		}
+		diffed.Used = changedUsage(nodeResources(previous).used, nodeResources(node).used, thresholds)
+		if !diffed.Allocatable.empty() || !diffed.Requests.empty() || !diffed.Limits.empty() || !diffed.Used.empty() {
+			result = append(result, diffed)
+		}
+	}
+	for key, node := range beforeByKey {
+		if _, ok := afterByKey[key]; !ok {
+			removed := newNode(key, Removed, nodeResources(node), resources{}, 0, 0)
+			removed.Allocatable = Resources{
+				CPU:    changedSpec(node.AllocatableCPU, 0),
+				Memory: changedSpec(node.AllocatableMemory, 0),
+			}
+			result = append(result, removed)
+		}
+	}
+	slices.SortFunc(result, func(a, b Node) int {
+		return cmp.Or(cmp.Compare(a.Cluster, b.Cluster), cmp.Compare(a.Name, b.Name))
+	})
+	return result
+}
+
+func podsByKey(list metricsresources.PodMetricsResourceList, controllers owners.Controllers) map[podKey]replicas {
+	result := make(map[podKey]replicas, len(list))
+	for _, pod := range list {
+		key := podKey{cluster: pod.Cluster, ref: workloads.Reference(pod.PodResource, controllers)}
+		result[key] = append(result[key], pod)
+	}
+	return result
+}
+
+// deploymentControllers maps the ReplicaSets owning pods to their Deployment.
+// Recordings keep the owner of a pod only, so the Deployment is taken from
+// the ReplicaSet name, the Deployment name followed by the pod template hash.
+// A rollout creates a new ReplicaSet, matching by Deployment compares the
+// pods before and after it.
+func deploymentControllers(lists ...metricsresources.PodMetricsResourceList) owners.Controllers {
+	result := owners.Controllers{}
+	for _, list := range lists {
+		for _, pod := range list {
+			if pod.Owner.Kind != owners.KindReplicaSet {
+				continue
+			}
+			idx := strings.LastIndex(pod.Owner.Name, "-")
+			if idx <= 0 || !isTemplateHash(pod.Owner.Name[idx+1:]) {
+				continue
+			}
+			replicaSet := owners.Reference{Namespace: pod.PodResource.Namespace, Kind: owners.KindReplicaSet, Name: pod.Owner.Name}
+			result[replicaSet] = owners.Reference{Namespace: pod.PodResource.Namespace, Kind: kindDeployment, Name: pod.Owner.Name[:idx]}
+		}
+	}
+	return result
+}
+
+const (
+	kindDeployment = "Deployment"
+	// templateHashAlphabet holds the characters of pod template hashes, the
+	// digits and consonants Kubernetes encodes them with.
+	templateHashAlphabet = "bcdfghjklmnpqrstvwxz2456789"
+	maxTemplateHash      = 10
+)
+
+func isTemplateHash(value string) bool {
+	if value == "" || len(value) > maxTemplateHash {
+		return false
+	}
+	return strings.Trim(value, templateHashAlphabet) == ""
+}
+
+func nodesByKey(list noderesources.NodeResourceList) map[nodeKey]noderesources.NodeResource {
+	result := make(map[nodeKey]noderesources.NodeResource, len(list))
+	for _, node := range list {
+		result[nodeKey{cluster: node.Cluster, name: node.Name}] = node
+	}
+	return result
+}
+
+func newPod(key podKey, pods replicas, change Change, containers []Container) Pod {
+	pod := Pod{
+		Cluster:    key.cluster,
+		Namespace:  key.ref.Namespace,
+		Name:       key.ref.Name,
+		Change:     change,
+		Containers: containers,
+	}
+	if key.ref.Kind == owners.KindPod {
+		pod.Node = pods[0].NodeName
+		return pod
+	}
+	pod.Kind = key.ref.Kind
+	switch change {
+	case Added:
+		pod.Replicas = changedSpec(0, int64(len(pods)))
+	case Removed:
+		pod.Replicas = changedSpec(int64(len(pods)), 0)
+	case Changed:
+		// Pods compares the replicas of changed workloads.
+	}
+	return pod
+}
+
+// containerResources sums the containers of the pods by name. Usage is known
+// when every pod running the container reports it.
+func containerResources(pods replicas) map[string]resources {
+	result := map[string]resources{}
+	for _, pod := range pods {
+		for _, container := range pod.ContainersMetrics() {
+			values, ok := result[container.Name]
+			if !ok {
+				values.used.known = true
+			}
+			values.requestCPU += container.Requests.CPURequest
+			values.requestMemory += container.Requests.MemoryRequest
+			values.limitCPU += container.Limits.CPURequest
+			values.limitMemory += container.Limits.MemoryRequest
+			values.used.cpu += container.Requests.CPUUsed
+			values.used.memory += container.Requests.MemoryUsed
+			values.used.known = values.used.known &&
+				!container.MetricsMissing && !container.MetricsUnavailable && container.Requests.CPUUsed >= 0 && container.Requests.MemoryUsed >= 0
+			result[container.Name] = values
+		}
+	}
+	return result
+}
+
+// containerNames lists the container names of the pods in the order they
+// first appear in.
+func containerNames(pods replicas) []string {
+	var result []string
+	for _, pod := range pods {
+		for _, container := range pod.ContainersMetrics() {
+			if !slices.Contains(result, container.Name) {
+				result = append(result, container.Name)
+			}
+		}
+	}
+	return result
+}
+
+// podContainers lists all containers of added or removed pods with their
+// values.
+func podContainers(pods replicas, change Change) []Container {
+	values := containerResources(pods)
+	names := containerNames(pods)
+	result := make([]Container, 0, len(names))
+	for _, name := range names {
+		result = append(result, wholeContainer(name, change, values[name]))
+	}
+	return result
+}
+
+func wholeContainer(name string, change Change, values resources) Container {
+	before, after := values, resources{}
+	if change == Added {
+		before, after = resources{}, values
+	}
+	container := Container{Name: name, Change: change}
+	container.Requests, container.Limits = changedSpecs(before, after)
+	if values.used.known {
+		container.Used = Resources{
+			CPU:    changedSpec(before.used.cpu, after.used.cpu),
+			Memory: changedSpec(before.used.memory, after.used.memory),
+		}
+	}
+	return container
+}
+
+// diffContainers compares the containers of pods present in both results.
+func diffContainers(before, after replicas, thresholds Thresholds) []Container {
+	beforeValues := containerResources(before)
+	afterValues := containerResources(after)
+	var result []Container
+	for _, name := range containerNames(after) {
+		values := afterValues[name]
+		previous, ok := beforeValues[name]
+		if !ok {
+			result = append(result, wholeContainer(name, Added, values))
+			continue
+		}
+		changed := Container{Name: name, Change: Changed}
+		changed.Requests, changed.Limits = changedSpecs(previous, values)
+		changed.Used = changedUsage(previous.used, values.used, thresholds)
+		if !changed.Requests.empty() || !changed.Limits.empty() || !changed.Used.empty() {
+			result = append(result, changed)
+		}
+	}
+	for _, name := range containerNames(before) {
+		if _, ok := afterValues[name]; !ok {
+			result = append(result, wholeContainer(name, Removed, beforeValues[name]))
+		}
+	}
+	return result
+}
+
+func nodeResources(node noderesources.NodeResource) resources {
+	return resources{
+		requestCPU:    node.CPURequest,
+		requestMemory: node.MemoryRequest,
+		limitCPU:      node.CPULimit,
+		limitMemory:   node.MemoryLimit,
+		used: usage{
+			cpu:    node.UsedCPU,
+			memory: node.UsedMemory,
+			known:  !node.MetricsMissing && !node.MetricsUnavailable,
+		},
+	}
+}
+
+func newNode(key nodeKey, change Change, before, after resources, allocatableCPU, allocatableMemory int64) Node {
+	node := Node{Cluster: key.cluster, Name: key.name, Change: change}
+	node.Requests, node.Limits = changedSpecs(before, after)
+	node.Allocatable = Resources{
+		CPU:    changedSpec(0, allocatableCPU),
+		Memory: changedSpec(0, allocatableMemory),
+	}
+	if change != Changed {
+		values := after
+		if change == Removed {
+			values = before
+		}
+		if values.used.known {
+			node.Used = Resources{
+				CPU:    changedSpec(before.used.cpu, after.used.cpu),
+				Memory: changedSpec(before.used.memory, after.used.memory),
+			}
+		}
+	}
+	return node
+}
+
+func changedSpecs(before, after resources) (Resources, Resources) {
+	requests := Resources{
+		CPU:    changedSpec(before.requestCPU, after.requestCPU),
+		Memory: changedSpec(before.requestMemory, after.requestMemory),
+	}
+	limits := Resources{
+		CPU:    changedSpec(before.limitCPU, after.limitCPU),
+		Memory: changedSpec(before.limitMemory, after.limitMemory),
+	}
+	return requests, limits
+}
+
+// changedSpec returns the value when it differs at all.
+func changedSpec(before, after int64) *Value {
+	if before == after {
+		return nil
+	}
+	return &Value{Before: before, After: after, Delta: after - before}
+}
+
+// changedUsage returns the usage values that moved beyond the thresholds.
+// Unknown usage on either side is left out.
+func changedUsage(before, after usage, thresholds Thresholds) Resources {
+	if !before.known || !after.known {
+		return Resources{}
+	}
+	var result Resources
+	if exceeds(before.cpu, after.cpu, thresholds.CPU, thresholds.Percent) {
+		result.CPU = changedSpec(before.cpu, after.cpu)
+	}
+	if exceeds(before.memory, after.memory, thresholds.Memory, thresholds.Percent) {
+		result.Memory = changedSpec(before.memory, after.memory)
+	}
+	return result
+}
+
+func exceeds(before, after, threshold int64, percent float64) bool {
+	delta := after - before
+	if delta < 0 {
+		delta = -delta
+	}
+	if delta == 0 || delta < threshold {
+		return false
+	}
+	if before == 0 {
+		return true
+	}
+	return float64(delta)*100 >= percent*float64(before)
+}
@@ -0,0 +1,340 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/pkg/owners"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+)
+
+type testContainer struct {
+	name                      string
+	requestCPU, requestMemory int64
+	limitCPU                  int64
+	usedCPU, usedMemory       int64
+}
+
+func testPod(namespace, name string, containers ...testContainer) metricsresources.PodMetricsResource {
+	pod := metricsresources.PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Namespace: namespace, Name: name},
+			NodeName:      "node-1",
+		},
+		PodMetric: podmetrics.PodMetric{Namespace: namespace, Name: name},
+	}
+	for _, container := range containers {
+		pod.PodResource.Containers = append(pod.PodResource.Containers, pods.ContainerResource{
+			Name:     container.name,
+			Requests: pods.Resource{CPU: container.requestCPU, Memory: container.requestMemory},
+			Limits:   pods.Resource{CPU: container.limitCPU},
+		})
+		pod.PodMetric.Containers = append(pod.PodMetric.Containers, podmetrics.ContainerMetric{
+			Name:   container.name,
+			Metric: podmetrics.Metric{CPU: container.usedCPU, Memory: container.usedMemory},
+		})
+	}
+	return pod
+}
+
+func TestPods(t *testing.T) {
+	app := testContainer{name: "app", requestCPU: 100, requestMemory: 64 << 20, usedCPU: 50, usedMemory: 32 << 20}
+
+	t.Run("added and removed pods list their containers", func(t *testing.T) {
+		before := metricsresources.PodMetricsResourceList{testPod("default", "old", app)}
+		after := metricsresources.PodMetricsResourceList{testPod("default", "new", app)}
+
+		result := Pods(before, after, DefaultThresholds())
+
+		require.Len(t, result, 2)
+		require.Equal(t, "new", result[0].Name)
+		require.Equal(t, Added, result[0].Change)
+		require.Equal(t, "node-1", result[0].Node)
+		require.Equal(t, []Container{{
+			Name:     "app",
+			Change:   Added,
+			Requests: Resources{CPU: &Value{After: 100, Delta: 100}, Memory: &Value{After: 64 << 20, Delta: 64 << 20}},
+			Used:     Resources{CPU: &Value{After: 50, Delta: 50}, Memory: &Value{After: 32 << 20, Delta: 32 << 20}},
+		}}, result[0].Containers)
+		require.Equal(t, "old", result[1].Name)
+		require.Equal(t, Removed, result[1].Change)
+		require.Equal(t, &Value{Before: 100, Delta: -100}, result[1].Containers[0].Requests.CPU)
+	})
+
+	t.Run("changed requests and limits count on any difference", func(t *testing.T) {
+		changed := app
+		changed.requestCPU = 101
+		changed.limitCPU = 200
+
+		result := Pods(
+			metricsresources.PodMetricsResourceList{testPod("default", "web", app)},
+			metricsresources.PodMetricsResourceList{testPod("default", "web", changed)},
+			DefaultThresholds(),
+		)
+
+		require.Equal(t, []Pod{{
+			Namespace: "default",
+			Name:      "web",
+			Node:      "node-1",
+			Change:    Changed,
+			Containers: []Container{{
+				Name:     "app",
+				Change:   Changed,
+				Requests: Resources{CPU: &Value{Before: 100, After: 101, Delta: 1}},
+				Limits:   Resources{CPU: &Value{After: 200, Delta: 200}},
+			}},
+		}}, result)
+	})
+
+	t.Run("usage changes below the thresholds are ignored", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			usedCPU    int64
+			usedMemory int64
+			changed    bool
+		}{
+			{name: "unchanged", usedCPU: 50, usedMemory: 32 << 20},
+			{name: "below absolute threshold", usedCPU: 59, usedMemory: 32 << 20},
+			{name: "beyond both thresholds", usedCPU: 60, usedMemory: 32 << 20, changed: true},
+			{name: "memory below percent threshold", usedCPU: 50, usedMemory: 35 << 20},
+			{name: "memory beyond both thresholds", usedCPU: 50, usedMemory: 64 << 20, changed: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				changed := app
+				changed.usedCPU = tt.usedCPU
+				changed.usedMemory = tt.usedMemory
+
+				result := Pods(
+					metricsresources.PodMetricsResourceList{testPod("default", "web", app)},
+					metricsresources.PodMetricsResourceList{testPod("default", "web", changed)},
+					DefaultThresholds(),
+				)
+
+				require.Equal(t, tt.changed, len(result) == 1)
+			})
+		}
+	})
+
+	t.Run("unknown usage is not compared", func(t *testing.T) {
+		unknown := testPod("default", "web", app)
+		unknown.PodMetric = podmetrics.PodMetric{}
+
+		result := Pods(
+			metricsresources.PodMetricsResourceList{testPod("default", "web", app)},
+			metricsresources.PodMetricsResourceList{unknown},
+			DefaultThresholds(),
+		)
+
+		require.Empty(t, result)
+	})
+
+	t.Run("containers are matched by name", func(t *testing.T) {
+		sidecar := testContainer{name: "sidecar", requestCPU: 10}
+
+		result := Pods(
+			metricsresources.PodMetricsResourceList{testPod("default", "web", app)},
+			metricsresources.PodMetricsResourceList{testPod("default", "web", app, sidecar)},
+			DefaultThresholds(),
+		)
+
+		require.Len(t, result, 1)
+		require.Equal(t, Changed, result[0].Change)
+		require.Len(t, result[0].Containers, 1)
+		require.Equal(t, "sidecar", result[0].Containers[0].Name)
+		require.Equal(t, Added, result[0].Containers[0].Change)
+	})
+
+	t.Run("pods are keyed by cluster", func(t *testing.T) {
+		east := testPod("default", "web", app)
+		east.Cluster = "east"
+		west := testPod("default", "web", app)
+		west.Cluster = "west"
+
+		result := Pods(
+			metricsresources.PodMetricsResourceList{east},
+			metricsresources.PodMetricsResourceList{west},
+			DefaultThresholds(),
+		)
+
+		require.Len(t, result, 2)
+		require.Equal(t, "east", result[0].Cluster)
+		require.Equal(t, Removed, result[0].Change)
+		require.Equal(t, "west", result[1].Cluster)
+		require.Equal(t, Added, result[1].Change)
+	})
+}
+
+func TestPodsMatchWorkloads(t *testing.T) {
+	app := testContainer{name: "app", requestCPU: 100, requestMemory: 64 << 20, usedCPU: 50, usedMemory: 32 << 20}
+	owned := func(name string, owner pods.Owner, containers ...testContainer) metricsresources.PodMetricsResource {
+		pod := testPod("default", name, containers...)
+		pod.Owner = owner
+		return pod
+	}
+	oldReplicaSet := pods.Owner{Kind: owners.KindReplicaSet, Name: "web-5d8f7c9b4"}
+	newReplicaSet := pods.Owner{Kind: owners.KindReplicaSet, Name: "web-7c6d8b5f9"}
+	statefulSet := pods.Owner{Kind: "StatefulSet", Name: "db"}
+
+	t.Run("rolled out pods are compared by deployment", func(t *testing.T) {
+		changed := app
+		changed.requestCPU = 200
+
+		result := Pods(
+			metricsresources.PodMetricsResourceList{
+				owned("web-5d8f7c9b4-abcde", oldReplicaSet, app),
+				owned("web-5d8f7c9b4-fghij", oldReplicaSet, app),
+			},
+			metricsresources.PodMetricsResourceList{
+				owned("web-7c6d8b5f9-klmno", newReplicaSet, changed),
+				owned("web-7c6d8b5f9-pqrst", newReplicaSet, changed),
+			},
+			DefaultThresholds(),
+		)
+
+		require.Equal(t, []Pod{{
+			Namespace: "default",
+			Kind:      "Deployment",
+			Name:      "web",
+			Change:    Changed,
+			Containers: []Container{{
+				Name:     "app",
+				Change:   Changed,
+				Requests: Resources{CPU: &Value{Before: 200, After: 400, Delta: 200}},
+			}},
+		}}, result)
+	})
+
+	t.Run("unchanged workloads with new pod names are left out", func(t *testing.T) {
+		result := Pods(
+			metricsresources.PodMetricsResourceList{owned("db-0", statefulSet, app), owned("web-5d8f7c9b4-abcde", oldReplicaSet, app)},
+			metricsresources.PodMetricsResourceList{owned("web-7c6d8b5f9-klmno", newReplicaSet, app), owned("db-0", statefulSet, app)},
+			DefaultThresholds(),
+		)
+
+		require.Empty(t, result)
+	})
+
+	t.Run("scaled workloads report their replicas", func(t *testing.T) {
+		result := Pods(
+			metricsresources.PodMetricsResourceList{owned("db-0", statefulSet, app)},
+			metricsresources.PodMetricsResourceList{owned("db-0", statefulSet, app), owned("db-1", statefulSet, app)},
+			DefaultThresholds(),
+		)
+
+		require.Len(t, result, 1)
+		require.Equal(t, "StatefulSet", result[0].Kind)
+		require.Equal(t, "db", result[0].Name)
+		require.Empty(t, result[0].Node)
+		require.Equal(t, &Value{Before: 1, After: 2, Delta: 1}, result[0].Replicas)
+		require.Equal(t, &Value{Before: 100, After: 200, Delta: 100}, result[0].Containers[0].Requests.CPU)
+	})
+
+	t.Run("added workloads and bare pods", func(t *testing.T) {
+		result := Pods(
+			metricsresources.PodMetricsResourceList{testPod("default", "debug", app)},
+			metricsresources.PodMetricsResourceList{owned("web-7c6d8b5f9-klmno", newReplicaSet, app)},
+			DefaultThresholds(),
+		)
+
+		require.Len(t, result, 2)
+		require.Equal(t, "debug", result[0].Name)
+		require.Empty(t, result[0].Kind)
+		require.Equal(t, Removed, result[0].Change)
+		require.Equal(t, "node-1", result[0].Node)
+		require.Equal(t, "web", result[1].Name)
+		require.Equal(t, Added, result[1].Change)
+		require.Equal(t, &Value{After: 1, Delta: 1}, result[1].Replicas)
+	})
+
+	t.Run("replica sets without a template hash are kept", func(t *testing.T) {
+		replicaSet := pods.Owner{Kind: owners.KindReplicaSet, Name: "standalone"}
+		result := Pods(
+			metricsresources.PodMetricsResourceList{},
+			metricsresources.PodMetricsResourceList{owned("standalone-abcde", replicaSet, app)},
+			DefaultThresholds(),
+		)
+
+		require.Len(t, result, 1)
+		require.Equal(t, owners.KindReplicaSet, result[0].Kind)
+		require.Equal(t, "standalone", result[0].Name)
+	})
+}
+
+func TestNodes(t *testing.T) {
+	node := noderesources.NodeResource{
+		Name:              "node-1",
+		AllocatableCPU:    4000,
+		AllocatableMemory: 8 << 30,
+		CPURequest:        1000,
+		MemoryRequest:     1 << 30,
+		UsedCPU:           500,
+		UsedMemory:        2 << 30,
+	}
+
+	t.Run("changed nodes", func(t *testing.T) {
+		changed := node
+		changed.CPURequest = 1500
+		changed.UsedMemory = 3 << 30
+
+		result := Nodes(noderesources.NodeResourceList{node}, noderesources.NodeResourceList{changed}, DefaultThresholds())
+
+		require.Equal(t, []Node{{
+			Name:     "node-1",
+			Change:   Changed,
+			Requests: Resources{CPU: &Value{Before: 1000, After: 1500, Delta: 500}},
+			Used:     Resources{Memory: &Value{Before: 2 << 30, After: 3 << 30, Delta: 1 << 30}},
+		}}, result)
+	})
+
+	t.Run("unchanged nodes are left out", func(t *testing.T) {
+		changed := node
+		changed.UsedCPU = 505
+
+		require.Empty(t, Nodes(noderesources.NodeResourceList{node}, noderesources.NodeResourceList{changed}, DefaultThresholds()))
+	})
+
+	t.Run("added and removed nodes", func(t *testing.T) {
+		added := node
+		added.Name = "node-2"
+		added.MetricsMissing = true
+
+		result := Nodes(noderesources.NodeResourceList{node}, noderesources.NodeResourceList{added}, DefaultThresholds())
+
+		require.Len(t, result, 2)
+		require.Equal(t, "node-1", result[0].Name)
+		require.Equal(t, Removed, result[0].Change)
+		require.Equal(t, &Value{Before: 4000, Delta: -4000}, result[0].Allocatable.CPU)
+		require.Equal(t, &Value{Before: 500, Delta: -500}, result[0].Used.CPU)
+		require.Equal(t, "node-2", result[1].Name)
+		require.Equal(t, Added, result[1].Change)
+		require.Equal(t, &Value{After: 8 << 30, Delta: 8 << 30}, result[1].Allocatable.Memory)
+		require.Nil(t, result[1].Used.CPU)
+	})
+}
+
+func TestExceeds(t *testing.T) {
+	tests := []struct {
+		name      string
+		before    int64
+		after     int64
+		threshold int64
+		percent   float64
+		exceeds   bool
+	}{
+		{name: "no change", before: 100, after: 100, exceeds: false},
+		{name: "any change without thresholds", before: 100, after: 101, exceeds: true},
+		{name: "below absolute threshold", before: 100, after: 105, threshold: 10, exceeds: false},
+		{name: "below percent", before: 1000, after: 1050, threshold: 10, percent: 10, exceeds: false},
+		{name: "decrease beyond both", before: 1000, after: 800, threshold: 10, percent: 10, exceeds: true},
+		{name: "from zero", before: 0, after: 20, threshold: 10, percent: 10, exceeds: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.exceeds, exceeds(tt.before, tt.after, tt.threshold, tt.percent))
+		})
+	}
+}
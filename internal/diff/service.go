@@ -0,0 +1,209 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/internal/recording"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+)
+
+// ErrNothingToCompare is returned when the two sides have no kind of result
+// in common.
+var ErrNothingToCompare = errors.New("nothing to compare")
+
+type Config struct {
+	// Before is the recording compared against, taken at BeforeAt or at its
+	// last snapshots for a zero BeforeAt.
+	Before   string
+	BeforeAt time.Time
+	// After is the recording compared, the live cluster when empty.
+	After   string
+	AfterAt time.Time
+	// Namespaces keeps the pods of these namespaces on both sides. Empty
+	// keeps all pods.
+	Namespaces []string
+	Thresholds Thresholds
+	// Pods and Nodes request the live cluster for the kinds recorded in
+	// Before. Live pods are requested from Namespaces, or from the namespaces
+	// the Before pods were requested from when Namespaces is empty.
+	Pods  metricsresources.Config
+	Nodes noderesources.Config
+}
+
+// side is one side of a diff, a nil list was not recorded or requested.
+// namespaces are the namespaces the pods were requested from, empty for all.
+type side struct {
+	timestamp  time.Time
+	namespaces []string
+	pods       metricsresources.PodMetricsResourceList
+	nodes      noderesources.NodeResourceList
+}
+
+func (c Config) Validate() error {
+	if c.Before == "" {
+		return errors.New("diff needs the recording to compare against")
+	}
+	if c.After == "" && !c.AfterAt.IsZero() {
+		return errors.New("a time of the after side needs an after recording")
+	}
+	if c.Thresholds.CPU < 0 || c.Thresholds.Memory < 0 || c.Thresholds.Percent < 0 {
+		return errors.New("diff thresholds must not be negative")
+	}
+	return nil
+}
+
+// Process compares the sides and passes the report to successProcessor. A
+// partial live request is compared as it is and its *partial.Error returned
+// after the report is processed.
+func (c *Config) Process(successProcessor SuccessProcessor) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	before, err := readSide(c.Before, c.BeforeAt)
+	if err != nil {
+		return err
+	}
+	var after side
+	var partialErr error
+	if c.After != "" {
+		after, err = readSide(c.After, c.AfterAt)
+	} else {
+		after, err = c.live(before)
+		if isPartial(err) {
+			partialErr, err = err, nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+	report, err := c.compare(before, after)
+	if err != nil {
+		return err
+	}
+	successProcessor.Success(report)
+	return partialErr
+}
+
+func (c Config) compare(before, after side) (Report, error) {
+	report := Report{Before: before.timestamp, After: after.timestamp}
+	if before.pods != nil && after.pods != nil {
+		report.PodsCompared = true
+		report.Pods = Pods(
+			before.pods.FilterNamespaces(c.Namespaces),
+			after.pods.FilterNamespaces(c.Namespaces),
+			c.Thresholds,
+		)
+	}
+	if before.nodes != nil && after.nodes != nil {
+		report.NodesCompared = true
+		report.Nodes = Nodes(before.nodes, after.nodes, c.Thresholds)
+	}
+	if !report.PodsCompared && !report.NodesCompared {
+		return Report{}, fmt.Errorf("%w: the sides have no pods or nodes snapshots in common", ErrNothingToCompare)
+	}
+	return report, nil
+}
+
+// readSide reads the pods and nodes snapshots of the recording at path taken
+// at at. The side is as old as its latest snapshot.
+func readSide(path string, at time.Time) (side, error) {
+	snapshots, err := recording.ReadAll(path)
+	if err != nil {
+		return side{}, err
+	}
+	var result side
+	for _, kind := range []recording.Kind{recording.Pods, recording.Nodes} {
+		recorded := recording.Filter(snapshots, kind)
+		if len(recorded) == 0 {
+			continue
+		}
+		snapshot, err := recording.At(recorded, at)
+		if err != nil {
+			return side{}, fmt.Errorf("%s %s: %w", path, kind, err)
+		}
+		if snapshot.Timestamp.After(result.timestamp) {
+			result.timestamp = snapshot.Timestamp
+		}
+		switch kind {
+		case recording.Pods:
+			result.namespaces = snapshot.Namespaces
+			result.pods = snapshot.PodList()
+		case recording.Nodes:
+			result.nodes = snapshot.NodeList()
+		}
+	}
+	if result.pods == nil && result.nodes == nil {
+		return side{}, fmt.Errorf("%w: %s", recording.ErrNoSnapshots, path)
+	}
+	return result, nil
+}
+
+// live requests the kinds recorded in before from the cluster.
+func (c *Config) live(before side) (side, error) {
+	result := side{timestamp: time.Now()}
+	var partialErr error
+	if before.pods != nil {
+		pods := c.Pods
+		pods.Namespaces = c.Namespaces
+		if len(pods.Namespaces) == 0 {
+			pods.Namespaces = before.namespaces
+			pods.AllNamespaces = len(pods.Namespaces) == 0
+		}
+		captured := &podsCapture{list: metricsresources.PodMetricsResourceList{}}
+		err := pods.Process(captured)
+		if isPartial(err) {
+			partialErr, err = err, nil
+		}
+		if err != nil {
+			return side{}, err
+		}
+		result.pods = captured.list
+	}
+	if before.nodes != nil {
+		captured := &nodesCapture{list: noderesources.NodeResourceList{}}
+		if err := c.Nodes.Process(captured); err != nil {
+			return side{}, err
+		}
+		result.nodes = captured.list
+	}
+	return result, partialErr
+}
+
+func isPartial(err error) bool {
+	var partialErr *partial.Error
+	return errors.As(err, &partialErr)
+}
+
+// podsCapture and nodesCapture keep the result of a live request. They start
+// with empty lists, so an empty result is compared rather than skipped.
+type podsCapture struct {
+	list metricsresources.PodMetricsResourceList
+}
+
+func (p *podsCapture) Success(list metricsresources.PodMetricsResourceList, _ partial.Warnings) {
+	if list != nil {
+		p.list = list
+	}
+}
+
+type nodesCapture struct {
+	list noderesources.NodeResourceList
+}
+
+func (n *nodesCapture) Success(list noderesources.NodeResourceList) {
+	if list != nil {
+		n.list = list
+	}
+}
+
+type SuccessProcessor interface {
+	Success(Report)
+}
+
+type ErrorProcessor interface {
+	Error(error)
+}
@@ -0,0 +1,160 @@
+package diff
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/internal/recording"
+)
+
+type reportCapture struct {
+	report Report
+}
+
+func (r *reportCapture) Success(report Report) {
+	r.report = report
+}
+
+func writeRecording(t *testing.T, snapshots ...recording.Snapshot) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+	recorder, err := recording.Open(path)
+	require.NoError(t, err)
+	for _, snapshot := range snapshots {
+		recorder.Record(snapshot)
+	}
+	require.NoError(t, recorder.Close())
+	return path
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		err  string
+	}{
+		{name: "valid config", cfg: Config{Before: "before.jsonl", Thresholds: DefaultThresholds()}},
+		{name: "missing before", cfg: Config{}, err: "diff needs the recording to compare against"},
+		{
+			name: "after time without after recording",
+			cfg:  Config{Before: "before.jsonl", AfterAt: time.Now()},
+			err:  "a time of the after side needs an after recording",
+		},
+		{
+			name: "negative threshold",
+			cfg:  Config{Before: "before.jsonl", Thresholds: Thresholds{CPU: -1}},
+			err:  "diff thresholds must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestProcessRecordings(t *testing.T) {
+	first := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	app := testContainer{name: "app", requestCPU: 100}
+	scaled := testContainer{name: "app", requestCPU: 200}
+	before := writeRecording(t,
+		recording.NewPodSnapshot(first, nil, metricsresources.PodMetricsResourceList{testPod("default", "web", app)}, nil),
+		recording.NewPodSnapshot(first.Add(time.Minute), nil, metricsresources.PodMetricsResourceList{
+			testPod("default", "web", app),
+			testPod("kube-system", "dns", app),
+		}, nil),
+		recording.NewNodeSnapshot(first, noderesources.NodeResourceList{{Name: "node-1"}}),
+	)
+	after := writeRecording(t,
+		recording.NewPodSnapshot(first.Add(time.Hour), nil, metricsresources.PodMetricsResourceList{
+			testPod("default", "web", scaled),
+			testPod("kube-system", "dns", app),
+		}, nil),
+	)
+
+	t.Run("compares the kinds of both sides", func(t *testing.T) {
+		cfg := Config{Before: before, After: after, Thresholds: DefaultThresholds()}
+		capture := &reportCapture{}
+
+		require.NoError(t, cfg.Process(capture))
+
+		require.Equal(t, first.Add(time.Minute), capture.report.Before)
+		require.Equal(t, first.Add(time.Hour), capture.report.After)
+		require.True(t, capture.report.PodsCompared)
+		require.False(t, capture.report.NodesCompared)
+		require.Len(t, capture.report.Pods, 1)
+		require.Equal(t, "web", capture.report.Pods[0].Name)
+		require.Equal(t, Changed, capture.report.Pods[0].Change)
+	})
+
+	t.Run("snapshots at time", func(t *testing.T) {
+		cfg := Config{Before: before, BeforeAt: first, After: after, Thresholds: DefaultThresholds()}
+		capture := &reportCapture{}
+
+		require.NoError(t, cfg.Process(capture))
+
+		require.Len(t, capture.report.Pods, 2)
+		require.Equal(t, "default", capture.report.Pods[0].Namespace)
+		require.Equal(t, "dns", capture.report.Pods[1].Name)
+		require.Equal(t, Added, capture.report.Pods[1].Change)
+	})
+
+	t.Run("namespaces filter both sides", func(t *testing.T) {
+		cfg := Config{Before: before, BeforeAt: first, After: after, Namespaces: []string{"kube-system"}}
+		capture := &reportCapture{}
+
+		require.NoError(t, cfg.Process(capture))
+
+		require.Len(t, capture.report.Pods, 1)
+		require.Equal(t, "dns", capture.report.Pods[0].Name)
+	})
+
+	t.Run("same recording has no changes", func(t *testing.T) {
+		cfg := Config{Before: before, After: before}
+		capture := &reportCapture{}
+
+		require.NoError(t, cfg.Process(capture))
+
+		require.True(t, capture.report.Empty())
+		require.True(t, capture.report.NodesCompared)
+	})
+
+	t.Run("nothing in common", func(t *testing.T) {
+		nodes := writeRecording(t, recording.NewNodeSnapshot(first, noderesources.NodeResourceList{{Name: "node-1"}}))
+		cfg := Config{Before: nodes, After: after}
+
+		require.ErrorIs(t, cfg.Process(&reportCapture{}), ErrNothingToCompare)
+	})
+
+	t.Run("time before the first snapshot", func(t *testing.T) {
+		cfg := Config{Before: before, BeforeAt: first.Add(-time.Hour), After: after}
+
+		require.ErrorIs(t, cfg.Process(&reportCapture{}), recording.ErrNoSnapshots)
+	})
+}
+
+func TestReadSideNamespaces(t *testing.T) {
+	first := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	app := testContainer{name: "app", requestCPU: 100}
+	path := writeRecording(t,
+		recording.NewPodSnapshot(first, []string{"db", "web"}, metricsresources.PodMetricsResourceList{testPod("web", "a", app)}, nil),
+	)
+
+	result, err := readSide(path, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"db", "web"}, result.namespaces, "the requested namespaces, not the namespaces of the pods")
+
+	path = writeRecording(t, recording.NewPodSnapshot(first, nil, metricsresources.PodMetricsResourceList{testPod("web", "a", app)}, nil))
+	result, err = readSide(path, time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, result.namespaces, "all namespaces")
+}
@@ -0,0 +1,99 @@
+// Package history keeps the recent usage of pods and nodes across watch
+// ticks, so trends can be shown next to the current values.
+package history
+
+// Unknown marks a sample taken while usage was not reported.
+const Unknown int64 = -1
+
+// Series is a ring buffer of the last samples of a value.
+type Series struct {
+	values []int64
+	next   int
+	full   bool
+}
+
+func NewSeries(size int) *Series {
+	return &Series{values: make([]int64, size)}
+}
+
+// Add appends value, replacing the oldest sample once the series is full.
+func (s *Series) Add(value int64) {
+	if len(s.values) == 0 {
+		return
+	}
+	s.values[s.next] = value
+	s.next = (s.next + 1) % len(s.values)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+// Values returns the samples from the oldest to the latest.
+func (s *Series) Values() []int64 {
+	if !s.full {
+		return append([]int64(nil), s.values[:s.next]...)
+	}
+	return append(append([]int64(nil), s.values[s.next:]...), s.values[:s.next]...)
+}
+
+// Sample is the CPU usage in millicores and the memory usage in bytes of a
+// pod or node at a tick, Unknown when not reported.
+type Sample struct {
+	CPU    int64
+	Memory int64
+}
+
+// Usage is the CPU and memory history of a pod or node.
+type Usage struct {
+	CPU    *Series
+	Memory *Series
+}
+
+// History keeps the last Size samples of every pod or node seen at the last
+// tick. It is not safe for concurrent use, watch ticks are rendered one at a
+// time.
+type History struct {
+	size  int
+	usage map[string]Usage
+}
+
+func New(size int) *History {
+	return &History{size: size, usage: map[string]Usage{}}
+}
+
+// Update adds the samples of a tick. Pods and nodes missing from the tick
+// are forgotten, so a recreated pod starts a new history.
+func (h *History) Update(samples map[string]Sample) {
+	for key := range h.usage {
+		if _, ok := samples[key]; !ok {
+			delete(h.usage, key)
+		}
+	}
+	for key, sample := range samples {
+		usage, ok := h.usage[key]
+		if !ok {
+			usage = Usage{CPU: NewSeries(h.size), Memory: NewSeries(h.size)}
+			h.usage[key] = usage
+		}
+		usage.CPU.Add(sample.CPU)
+		usage.Memory.Add(sample.Memory)
+	}
+}
+
+// CPU returns the CPU samples of key from the oldest to the latest.
+func (h *History) CPU(key string) []int64 {
+	usage, ok := h.usage[key]
+	if !ok {
+		return nil
+	}
+	return usage.CPU.Values()
+}
+
+// Memory returns the memory samples of key from the oldest to the latest.
+func (h *History) Memory(key string) []int64 {
+	usage, ok := h.usage[key]
+	if !ok {
+		return nil
+	}
+	return usage.Memory.Values()
+}
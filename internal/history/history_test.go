@@ -0,0 +1,50 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeries(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		values []int64
+		want   []int64
+	}{
+		{name: "empty", size: 3, want: []int64{}},
+		{name: "not full", size: 3, values: []int64{1, 2}, want: []int64{1, 2}},
+		{name: "full", size: 3, values: []int64{1, 2, 3}, want: []int64{1, 2, 3}},
+		{name: "wraps around", size: 3, values: []int64{1, 2, 3, 4, 5}, want: []int64{3, 4, 5}},
+		{name: "zero size keeps nothing", size: 0, values: []int64{1, 2}, want: []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := NewSeries(tt.size)
+			for _, value := range tt.values {
+				series.Add(value)
+			}
+			got := series.Values()
+			if len(tt.want) == 0 {
+				require.Empty(t, got)
+				return
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	h := New(2)
+	h.Update(map[string]Sample{"default/web": {CPU: 10, Memory: 100}, "default/db": {CPU: 1, Memory: Unknown}})
+	h.Update(map[string]Sample{"default/web": {CPU: 20, Memory: 200}, "default/db": {CPU: 2, Memory: 10}})
+	h.Update(map[string]Sample{"default/web": {CPU: 30, Memory: 300}})
+
+	require.Equal(t, []int64{20, 30}, h.CPU("default/web"))
+	require.Equal(t, []int64{200, 300}, h.Memory("default/web"))
+	require.Nil(t, h.CPU("default/db"), "pods missing from a tick are forgotten")
+
+	h.Update(map[string]Sample{"default/db": {CPU: 5, Memory: 50}})
+	require.Equal(t, []int64{5}, h.CPU("default/db"))
+}
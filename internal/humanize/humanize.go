@@ -3,6 +3,8 @@ package humanize
 import (
 	"fmt"
 	"math"
+
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
 )
 
 type Number interface {
@@ -46,3 +48,12 @@ func Bytes[V Number](s V) string {
 	}
 	return fmt.Sprintf("%s%0.1f%s", sign, rounded, sizes[i])
 }
+
+// Quantity formats an extended resource value: hugepages sizes as bytes,
+// everything else (GPUs and other devices) as a plain count.
+func Quantity(name string, value int64) string {
+	if resources.IsHugePages(name) {
+		return Bytes(value)
+	}
+	return fmt.Sprintf("%d", value)
+}
@@ -49,3 +49,8 @@ func TestHumanizeBytes(t *testing.T) {
 		require.Equal(t, "1024EiB", Bytes(big))
 	})
 }
+
+func TestQuantity(t *testing.T) {
+	require.Equal(t, "2", Quantity("nvidia.com/gpu", 2))
+	require.Equal(t, "64MiB", Quantity("hugepages-2Mi", 64*1024*1024))
+}
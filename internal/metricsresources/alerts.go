@@ -1,5 +1,15 @@
 package metricsresources
 
+import "time"
+
+// RecentOOMWindow is how long after an OOMKilled termination a pod is alerted
+// by the oom alert.
+const RecentOOMWindow = time.Hour
+
+// volumeUsedPercentAlert is the persistent volume claim usage a pod is
+// alerted at by the storage alert.
+const volumeUsedPercentAlert = 95
+
 func (c ContainerMetricsResource) IsMemoryAlerted() bool {
 	return c.Limits.MemoryAlert() || c.Requests.MemoryAlert()
 }
@@ -28,6 +38,11 @@ func (c ContainerMetricsResource) IsAlerted() bool {
 	return c.IsMemoryAlerted() || c.IsCPUAlerted()
 }
 
+// IsOOMKilledSince reports whether the container was OOMKilled at or after since.
+func (c ContainerMetricsResource) IsOOMKilledSince(since time.Time) bool {
+	return c.Terminated.IsOOMKilledSince(since) || c.LastTermination.IsOOMKilledSince(since)
+}
+
 func (c ContainerMetricsResources) IsMemoryAlerted() bool {
 	for _, container := range c {
 		if container.IsMemoryAlerted() {
@@ -91,6 +106,35 @@ func (c ContainerMetricsResources) IsAlerted() bool {
 	return false
 }
 
+func (c ContainerMetricsResources) IsOOMKilledSince(since time.Time) bool {
+	for _, container := range c {
+		if container.IsOOMKilledSince(since) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStorageEphemeralAlerted reports whether the pod ephemeral storage usage
+// reaches its requests or limits. Usage is only reported by the kubelet
+// metrics source.
+func (r PodMetricsResource) IsStorageEphemeralAlerted() bool {
+	pod := r.PodMetrics()
+	return pod.Requests.StorageEphemeralAlert() || pod.Limits.StorageEphemeralAlert()
+}
+
+// IsStorageAlerted reports whether a persistent volume claim of the pod is
+// more than volumeUsedPercentAlert percent full. Volumes are only reported by
+// the kubelet metrics source.
+func (r PodMetricsResource) IsStorageAlerted() bool {
+	for _, volume := range r.PodMetric.Volumes {
+		if volume.Capacity > 0 && (float64(volume.Used)/float64(volume.Capacity))*100 > volumeUsedPercentAlert {
+			return true
+		}
+	}
+	return false
+}
+
 func (m MetricsResource) CPUAlert() bool {
 	return m.CPURequest > 0 && m.CPURequest <= m.CPUUsed
 }
@@ -98,3 +142,7 @@ func (m MetricsResource) CPUAlert() bool {
 func (m MetricsResource) MemoryAlert() bool {
 	return m.MemoryRequest > 0 && m.MemoryRequest <= m.MemoryUsed
 }
+
+func (m MetricsResource) StorageEphemeralAlert() bool {
+	return m.StorageEphemeralRequest > 0 && m.StorageEphemeralRequest <= m.StorageEphemeralUsed
+}
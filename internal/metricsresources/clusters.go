@@ -0,0 +1,95 @@
+package metricsresources
+
+import (
+	"github.com/trezorg/k8spodsmetrics/internal/serviceorchestration"
+)
+
+// ClusterTotal sums requests, limits and usage of the pods of one cluster.
+type ClusterTotal struct {
+	Cluster  string   `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	Pods     int      `json:"pods" yaml:"pods"`
+	Requests Resource `json:"requests" yaml:"requests"`
+	Limits   Resource `json:"limits" yaml:"limits"`
+	Used     Resource `json:"used" yaml:"used"`
+}
+
+// Clustered reports whether the pods were read from several contexts and
+// carry their cluster.
+func (r PodMetricsResourceList) Clustered() bool {
+	for _, pod := range r {
+		if pod.Cluster != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupByCluster splits pods by cluster. Clusters keep the order they first
+// appear in, pods keep their order within a cluster.
+func (r PodMetricsResourceList) GroupByCluster() []PodMetricsResourceList {
+	var result []PodMetricsResourceList
+	index := map[string]int{}
+	for _, pod := range r {
+		idx, ok := index[pod.Cluster]
+		if !ok {
+			idx = len(result)
+			index[pod.Cluster] = idx
+			result = append(result, nil)
+		}
+		result[idx] = append(result[idx], pod)
+	}
+	return result
+}
+
+// ClusterTotals returns a subtotal for every cluster in GroupByCluster order.
+func (r PodMetricsResourceList) ClusterTotals() []ClusterTotal {
+	groups := r.GroupByCluster()
+	result := make([]ClusterTotal, 0, len(groups))
+	for _, group := range groups {
+		total := ClusterTotal{Cluster: group[0].Cluster}
+		for _, pod := range group {
+			total.add(pod.toOutput())
+		}
+		result = append(result, total)
+	}
+	return result
+}
+
+func (t *ClusterTotal) add(pod PodMetricsResourceOutput) {
+	t.Pods++
+	t.Requests.add(pod.Requests)
+	t.Limits.add(pod.Limits)
+	t.Used.add(pod.Used)
+}
+
+// SumClusterTotals returns the grand total of all clusters.
+func SumClusterTotals(totals []ClusterTotal) ClusterTotal {
+	var result ClusterTotal
+	for _, total := range totals {
+		result.Pods += total.Pods
+		result.Requests.add(total.Requests)
+		result.Limits.add(total.Limits)
+		result.Used.add(total.Used)
+	}
+	return result
+}
+
+func (r *Resource) add(other Resource) {
+	r.CPU += other.CPU
+	r.Memory += other.Memory
+	r.MemoryRSS += other.MemoryRSS
+	r.Storage += other.Storage
+	r.StorageEphemeral += other.StorageEphemeral
+	for name, value := range other.Extended {
+		if r.Extended == nil {
+			r.Extended = make(map[string]int64, len(other.Extended))
+		}
+		r.Extended[name] += value
+	}
+}
+
+func mergeClusters(responses []serviceorchestration.ClusterResponse[PodMetricsResourceList]) (PodMetricsResourceList, error) {
+	return serviceorchestration.MergeClusters(responses, func(pod *PodMetricsResource, cluster string) {
+		pod.Cluster = cluster
+	})
+}
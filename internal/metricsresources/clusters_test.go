@@ -0,0 +1,117 @@
+package metricsresources
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/internal/serviceorchestration"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+)
+
+func clusterPod(cluster, name string, cpuRequest, cpuUsed int64) PodMetricsResource {
+	return PodMetricsResource{
+		Cluster: cluster,
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Name: name, Namespace: "default"},
+			Containers: []pods.ContainerResource{{
+				Name:     name,
+				Requests: pods.Resource{CPU: cpuRequest, Extended: map[string]int64{"nvidia.com/gpu": 1}},
+				Limits:   pods.Resource{CPU: 2 * cpuRequest},
+			}},
+		},
+		PodMetric: podmetrics.PodMetric{
+			Name:       name,
+			Namespace:  "default",
+			Containers: []podmetrics.ContainerMetric{{Name: name, Metric: podmetrics.Metric{CPU: cpuUsed}}},
+		},
+	}
+}
+
+func TestGroupByCluster(t *testing.T) {
+	list := PodMetricsResourceList{
+		clusterPod("prod-us", "api", 100, 50),
+		clusterPod("prod-eu", "api", 200, 100),
+		clusterPod("prod-us", "web", 300, 150),
+	}
+
+	require.True(t, list.Clustered())
+	groups := list.GroupByCluster()
+	require.Len(t, groups, 2)
+	require.Equal(t, "prod-us", groups[0][0].Cluster)
+	require.Len(t, groups[0], 2)
+	require.Equal(t, "web", groups[0][1].PodResource.Name)
+	require.Equal(t, "prod-eu", groups[1][0].Cluster)
+
+	require.False(t, PodMetricsResourceList{clusterPod("", "api", 100, 50)}.Clustered())
+}
+
+func TestClusterTotals(t *testing.T) {
+	list := PodMetricsResourceList{
+		clusterPod("prod-us", "api", 100, 50),
+		clusterPod("prod-us", "web", 300, 150),
+		clusterPod("prod-eu", "api", 200, 100),
+	}
+
+	totals := list.ClusterTotals()
+	require.Equal(t, []ClusterTotal{
+		{
+			Cluster:  "prod-us",
+			Pods:     2,
+			Requests: Resource{CPU: 400, Extended: map[string]int64{"nvidia.com/gpu": 2}},
+			Limits:   Resource{CPU: 800},
+			Used:     Resource{CPU: 200},
+		},
+		{
+			Cluster:  "prod-eu",
+			Pods:     1,
+			Requests: Resource{CPU: 200, Extended: map[string]int64{"nvidia.com/gpu": 1}},
+			Limits:   Resource{CPU: 400},
+			Used:     Resource{CPU: 100},
+		},
+	}, totals)
+	require.Equal(t, ClusterTotal{
+		Pods:     3,
+		Requests: Resource{CPU: 600, Extended: map[string]int64{"nvidia.com/gpu": 3}},
+		Limits:   Resource{CPU: 1200},
+		Used:     Resource{CPU: 300},
+	}, SumClusterTotals(totals))
+}
+
+func TestClusterSerialization(t *testing.T) {
+	t.Run("clusters add subtotals and the total", func(t *testing.T) {
+		list := PodMetricsResourceList{clusterPod("prod-us", "api", 100, 50), clusterPod("prod-eu", "api", 200, 100)}
+
+		data, err := json.Marshal(list)
+		require.NoError(t, err)
+		var envelope PodMetricsResourceOutputEnvelope
+		require.NoError(t, json.Unmarshal(data, &envelope))
+		require.Equal(t, "prod-us", envelope.Items[0].Cluster)
+		require.Len(t, envelope.Clusters, 2)
+		require.Equal(t, int64(100), envelope.Clusters[0].Requests.CPU)
+		require.NotNil(t, envelope.Total)
+		require.Equal(t, 2, envelope.Total.Pods)
+		require.Equal(t, int64(300), envelope.Total.Requests.CPU)
+	})
+
+	t.Run("a single cluster has no subtotals", func(t *testing.T) {
+		data, err := json.Marshal(PodMetricsResourceList{clusterPod("", "api", 100, 50)})
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "cluster")
+		var envelope PodMetricsResourceOutputEnvelope
+		require.NoError(t, json.Unmarshal(data, &envelope))
+		require.Empty(t, envelope.Clusters)
+		require.Nil(t, envelope.Total)
+	})
+}
+
+func TestMergeClusters(t *testing.T) {
+	result, err := mergeClusters([]serviceorchestration.ClusterResponse[PodMetricsResourceList]{
+		{Cluster: "prod-us", Data: PodMetricsResourceList{clusterPod("", "api", 100, 50)}},
+		{Cluster: "prod-eu", Error: serviceorchestration.ErrRequestTimeout},
+	})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Equal(t, "prod-us", result[0].Cluster)
+}
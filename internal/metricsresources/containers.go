@@ -1,45 +1,177 @@
 package metricsresources
 
+import (
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+)
+
+// ContainersMetrics joins the pod spec containers with their metrics by name.
+// Spec containers without metrics keep unset usage and are marked with
+// MetricsMissing, or MetricsUnavailable when the metrics API was unavailable.
+// Containers reported by metrics-server but absent from the
+// spec follow the spec containers with SpecMissing set.
 func (r PodMetricsResource) ContainersMetrics() ContainerMetricsResources {
-	containerMetricsResources := make(ContainerMetricsResources, 0, len(r.PodMetric.Containers))
-	for i, container := range r.PodResource.Containers {
-		containerMetricsResource := ContainerMetricsResource{
-			Name: container.Name,
-		}
-		var cpuMetric, memoryMetric, storageMetric, storageEphemeralMetric int64
-		if i < len(r.PodMetric.Containers) {
-			metrics := r.PodMetric.Containers[i]
-			cpuMetric = metrics.CPU
-			memoryMetric = metrics.Memory
-			storageMetric = metrics.Storage
-			storageEphemeralMetric = metrics.StorageEphemeral
+	metricsByName := make(map[string]podmetrics.Metric, len(r.PodMetric.Containers))
+	for _, metric := range r.PodMetric.Containers {
+		metricsByName[metric.Name] = metric.Metric
+	}
+
+	containerMetricsResources := make(ContainerMetricsResources, 0, len(r.PodResource.Containers))
+	for _, container := range r.PodResource.Containers {
+		metric, ok := metricsByName[container.Name]
+		if ok {
+			delete(metricsByName, container.Name)
 		} else {
-			cpuMetric = unset
-			memoryMetric = unset
-			storageMetric = unset
-			storageEphemeralMetric = unset
+			metric = podmetrics.Metric{CPU: unset, Memory: unset, MemoryRSS: unset, Storage: unset, StorageEphemeral: unset}
+		}
+		containerMetricsResource := containerMetrics(container, metric)
+		containerMetricsResource.MetricsMissing = !ok && !r.MetricsUnavailable
+		containerMetricsResource.MetricsUnavailable = r.MetricsUnavailable
+		containerMetricsResources = append(containerMetricsResources, containerMetricsResource)
+	}
+
+	// Iterate the metrics slice rather than the map to keep the output stable.
+	for _, metric := range r.PodMetric.Containers {
+		if _, ok := metricsByName[metric.Name]; !ok {
+			continue
 		}
-		containerMetricsResource.Requests = MetricsResource{
+		delete(metricsByName, metric.Name)
+		containerMetricsResource := containerMetrics(pods.ContainerResource{Name: metric.Name}, metric.Metric)
+		containerMetricsResource.SpecMissing = true
+		containerMetricsResources = append(containerMetricsResources, containerMetricsResource)
+	}
+	return containerMetricsResources
+}
+
+func containerMetrics(container pods.ContainerResource, metric podmetrics.Metric) ContainerMetricsResource {
+	return ContainerMetricsResource{
+		Name:            container.Name,
+		Type:            container.Type,
+		Restarts:        container.Restarts,
+		State:           container.State,
+		Terminated:      container.Terminated,
+		LastTermination: container.LastTermination,
+		Requests: MetricsResource{
 			CPURequest:              container.Requests.CPU,
 			MemoryRequest:           container.Requests.Memory,
-			CPUUsed:                 cpuMetric,
-			MemoryUsed:              memoryMetric,
+			CPUUsed:                 metric.CPU,
+			MemoryUsed:              metric.Memory,
+			MemoryRSSUsed:           metric.MemoryRSS,
 			StorageRequest:          container.Requests.Storage,
 			StorageEphemeralRequest: container.Requests.StorageEphemeral,
-			StorageUsed:             storageMetric,
-			StorageEphemeralUsed:    storageEphemeralMetric,
-		}
-		containerMetricsResource.Limits = MetricsResource{
+			StorageUsed:             metric.Storage,
+			StorageEphemeralUsed:    metric.StorageEphemeral,
+			Extended:                container.Requests.Extended,
+		},
+		Limits: MetricsResource{
 			CPURequest:              container.Limits.CPU,
 			MemoryRequest:           container.Limits.Memory,
-			CPUUsed:                 cpuMetric,
-			MemoryUsed:              memoryMetric,
+			CPUUsed:                 metric.CPU,
+			MemoryUsed:              metric.Memory,
+			MemoryRSSUsed:           metric.MemoryRSS,
 			StorageRequest:          container.Limits.Storage,
 			StorageEphemeralRequest: container.Limits.StorageEphemeral,
-			StorageUsed:             storageMetric,
-			StorageEphemeralUsed:    storageEphemeralMetric,
+			StorageUsed:             metric.Storage,
+			StorageEphemeralUsed:    metric.StorageEphemeral,
+			Extended:                container.Limits.Extended,
+		},
+	}
+}
+
+// Restarts sums the restart counts of the pod containers.
+func (r PodMetricsResource) Restarts() int32 {
+	var restarts int32
+	for _, container := range r.PodResource.Containers {
+		restarts += container.Restarts
+	}
+	return restarts
+}
+
+// LastTermination returns the most recent previous run termination of the pod
+// containers, nil when no container was restarted.
+func (r PodMetricsResource) LastTermination() *pods.Termination {
+	var last *pods.Termination
+	for _, container := range r.PodResource.Containers {
+		if container.LastTermination == nil {
+			continue
+		}
+		if last == nil || container.LastTermination.FinishedAt.After(last.FinishedAt) {
+			last = container.LastTermination
 		}
-		containerMetricsResources = append(containerMetricsResources, containerMetricsResource)
 	}
-	return containerMetricsResources
+	return last
+}
+
+// PodMetrics aggregates the pod into a single resource: usage is summed over
+// containers reporting metrics, requests and limits are the effective pod values
+// the scheduler accounts for. Pod level storage reported by the kubelet
+// metrics source, covering emptyDir volumes and persistent volume claims,
+// replaces the container sums.
+func (r PodMetricsResource) PodMetrics() ContainerMetricsResource {
+	requests := r.PodResource.EffectiveRequests()
+	limits := r.PodResource.EffectiveLimits()
+	used := podUsage(r.ContainersMetrics())
+	if r.PodMetric.StorageEphemeral > 0 {
+		used.StorageEphemeralUsed = r.PodMetric.StorageEphemeral
+	}
+	if len(r.PodMetric.Volumes) > 0 {
+		used.StorageUsed = 0
+		for _, volume := range r.PodMetric.Volumes {
+			used.StorageUsed += volume.Used
+		}
+	}
+	return ContainerMetricsResource{
+		Name:               r.PodResource.Name,
+		Restarts:           r.Restarts(),
+		LastTermination:    r.LastTermination(),
+		Requests:           podMetricsResource(requests, used),
+		Limits:             podMetricsResource(limits, used),
+		MetricsUnavailable: r.MetricsUnavailable,
+	}
+}
+
+func podMetricsResource(resource pods.Resource, used MetricsResource) MetricsResource {
+	return MetricsResource{
+		CPURequest:              resource.CPU,
+		MemoryRequest:           resource.Memory,
+		StorageRequest:          resource.Storage,
+		StorageEphemeralRequest: resource.StorageEphemeral,
+		CPUUsed:                 used.CPUUsed,
+		MemoryUsed:              used.MemoryUsed,
+		MemoryRSSUsed:           used.MemoryRSSUsed,
+		StorageUsed:             used.StorageUsed,
+		StorageEphemeralUsed:    used.StorageEphemeralUsed,
+		Extended:                resource.Extended,
+	}
+}
+
+// podUsage sums container usage skipping containers without metrics, for
+// example completed init containers. It stays unset when no container
+// reports metrics.
+func podUsage(containers ContainerMetricsResources) MetricsResource {
+	used := MetricsResource{
+		CPUUsed:              unset,
+		MemoryUsed:           unset,
+		MemoryRSSUsed:        unset,
+		StorageUsed:          unset,
+		StorageEphemeralUsed: unset,
+	}
+	for _, container := range containers {
+		used.CPUUsed = addUsage(used.CPUUsed, container.Requests.CPUUsed)
+		used.MemoryUsed = addUsage(used.MemoryUsed, container.Requests.MemoryUsed)
+		used.MemoryRSSUsed = addUsage(used.MemoryRSSUsed, container.Requests.MemoryRSSUsed)
+		used.StorageUsed = addUsage(used.StorageUsed, container.Requests.StorageUsed)
+		used.StorageEphemeralUsed = addUsage(used.StorageEphemeralUsed, container.Requests.StorageEphemeralUsed)
+	}
+	return used
+}
+
+func addUsage(total, value int64) int64 {
+	if value == unset {
+		return total
+	}
+	if total == unset {
+		return value
+	}
+	return total + value
 }
@@ -0,0 +1,164 @@
+package metricsresources
+
+import (
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+)
+
+type (
+	// Efficiency is the usage of a container or pod relative to its requests
+	// and limits. Percentages are nil when the request or limit is not set.
+	// Idle is the requested amount left unused, in millicores and bytes, and
+	// zero when usage exceeds the request.
+	Efficiency struct {
+		CPURequestPercent    *float64 `json:"cpu_request_percent,omitempty" yaml:"cpu_request_percent,omitempty"`
+		CPULimitPercent      *float64 `json:"cpu_limit_percent,omitempty" yaml:"cpu_limit_percent,omitempty"`
+		MemoryRequestPercent *float64 `json:"memory_request_percent,omitempty" yaml:"memory_request_percent,omitempty"`
+		MemoryLimitPercent   *float64 `json:"memory_limit_percent,omitempty" yaml:"memory_limit_percent,omitempty"`
+		CPUIdle              int64    `json:"cpu_idle" yaml:"cpu_idle"`
+		MemoryIdle           int64    `json:"memory_idle" yaml:"memory_idle"`
+	}
+
+	// Waste sums the idle CPU and memory of the pods of a namespace or a
+	// cluster. Pods without usage are not counted.
+	Waste struct {
+		Cluster    string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+		Namespace  string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+		Pods       int    `json:"pods" yaml:"pods"`
+		CPUIdle    int64  `json:"cpu_idle" yaml:"cpu_idle"`
+		MemoryIdle int64  `json:"memory_idle" yaml:"memory_idle"`
+		// counted sums the requests, limits and usage of the counted pods.
+		counted ContainerMetricsResource
+	}
+
+	// WasteSummary is the waste of every namespace, of every cluster when
+	// several are queried, and their sum.
+	WasteSummary struct {
+		Namespaces []Waste `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+		Clusters   []Waste `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+		Total      Waste   `json:"total" yaml:"total"`
+	}
+)
+
+// Efficiency returns the usage of the container relative to its requests and
+// limits, false when its usage is unknown.
+func (c ContainerMetricsResource) Efficiency() (Efficiency, bool) {
+	cpuUsed, memoryUsed := c.Requests.CPUUsed, c.Requests.MemoryUsed
+	if c.MetricsUnavailable || cpuUsed == unset || memoryUsed == unset {
+		return Efficiency{}, false
+	}
+	return Efficiency{
+		CPURequestPercent:    percent(cpuUsed, c.Requests.CPURequest),
+		CPULimitPercent:      percent(cpuUsed, c.Limits.CPURequest),
+		MemoryRequestPercent: percent(memoryUsed, c.Requests.MemoryRequest),
+		MemoryLimitPercent:   percent(memoryUsed, c.Limits.MemoryRequest),
+		CPUIdle:              idle(cpuUsed, c.Requests.CPURequest),
+		MemoryIdle:           idle(memoryUsed, c.Requests.MemoryRequest),
+	}, true
+}
+
+// Efficiency returns the usage of the pod relative to its effective requests
+// and limits, false when its usage is unknown.
+func (r PodMetricsResource) Efficiency() (Efficiency, bool) {
+	return r.PodMetrics().Efficiency()
+}
+
+func percent(used, total int64) *float64 {
+	if total <= 0 {
+		return nil
+	}
+	result := quotas.Percent(used, total)
+	return &result
+}
+
+func idle(used, requested int64) int64 {
+	return max(requested-used, 0)
+}
+
+// Add counts the idle resources of pod, skipping pods without usage.
+func (w *Waste) Add(pod PodMetricsResource) {
+	metrics := pod.PodMetrics()
+	efficiency, ok := metrics.Efficiency()
+	if !ok {
+		return
+	}
+	w.Pods++
+	w.CPUIdle += efficiency.CPUIdle
+	w.MemoryIdle += efficiency.MemoryIdle
+	w.count(metrics)
+}
+
+func (w *Waste) add(other Waste) {
+	w.Pods += other.Pods
+	w.CPUIdle += other.CPUIdle
+	w.MemoryIdle += other.MemoryIdle
+	w.count(other.counted)
+}
+
+func (w *Waste) count(metrics ContainerMetricsResource) {
+	w.counted.Requests.CPURequest += metrics.Requests.CPURequest
+	w.counted.Requests.MemoryRequest += metrics.Requests.MemoryRequest
+	w.counted.Limits.CPURequest += metrics.Limits.CPURequest
+	w.counted.Limits.MemoryRequest += metrics.Limits.MemoryRequest
+	w.counted.Requests.CPUUsed += metrics.Requests.CPUUsed
+	w.counted.Requests.MemoryUsed += metrics.Requests.MemoryUsed
+}
+
+// Efficiency returns the usage of the counted pods relative to their summed
+// requests and limits, and their idle resources. Pods without usage are left
+// out of the percentages as they are of the idle resources; false when no pod
+// was counted.
+func (w Waste) Efficiency() (Efficiency, bool) {
+	if w.Pods == 0 {
+		return Efficiency{}, false
+	}
+	efficiency, _ := w.counted.Efficiency()
+	efficiency.CPUIdle = w.CPUIdle
+	efficiency.MemoryIdle = w.MemoryIdle
+	return efficiency, true
+}
+
+// NamespaceWaste returns the waste of every namespace of every cluster in
+// the order they first appear in.
+func (r PodMetricsResourceList) NamespaceWaste() []Waste {
+	type key struct{ cluster, namespace string }
+	var result []Waste
+	index := map[key]int{}
+	for _, pod := range r {
+		k := key{cluster: pod.Cluster, namespace: pod.PodResource.Namespace}
+		idx, ok := index[k]
+		if !ok {
+			idx = len(result)
+			index[k] = idx
+			result = append(result, Waste{Cluster: pod.Cluster, Namespace: pod.PodResource.Namespace})
+		}
+		result[idx].Add(pod)
+	}
+	return result
+}
+
+// ClusterWaste returns the waste of every cluster in GroupByCluster order.
+func (r PodMetricsResourceList) ClusterWaste() []Waste {
+	groups := r.GroupByCluster()
+	result := make([]Waste, 0, len(groups))
+	for _, group := range groups {
+		waste := Waste{Cluster: group[0].Cluster}
+		for _, pod := range group {
+			waste.Add(pod)
+		}
+		result = append(result, waste)
+	}
+	return result
+}
+
+// WasteSummary returns the waste of every namespace and of every cluster,
+// the latter only when several clusters are queried.
+func (r PodMetricsResourceList) WasteSummary() WasteSummary {
+	summary := WasteSummary{Namespaces: r.NamespaceWaste()}
+	for _, waste := range summary.Namespaces {
+		summary.Total.add(waste)
+	}
+	if r.Clustered() {
+		summary.Clusters = r.ClusterWaste()
+	}
+	return summary
+}
@@ -0,0 +1,149 @@
+package metricsresources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+)
+
+func TestContainerEfficiency(t *testing.T) {
+	tests := []struct {
+		name      string
+		container ContainerMetricsResource
+		want      Efficiency
+		ok        bool
+	}{
+		{
+			name: "requests and limits",
+			container: ContainerMetricsResource{
+				Requests: MetricsResource{CPURequest: 200, CPUUsed: 50, MemoryRequest: 1024, MemoryUsed: 768},
+				Limits:   MetricsResource{CPURequest: 300, CPUUsed: 50, MemoryRequest: 3072, MemoryUsed: 768},
+			},
+			want: Efficiency{
+				CPURequestPercent:    new(25.0),
+				CPULimitPercent:      new(16.7),
+				MemoryRequestPercent: new(75.0),
+				MemoryLimitPercent:   new(25.0),
+				CPUIdle:              150,
+				MemoryIdle:           256,
+			},
+			ok: true,
+		},
+		{
+			name: "usage above the request is not idle",
+			container: ContainerMetricsResource{
+				Requests: MetricsResource{CPURequest: 100, CPUUsed: 250, MemoryUsed: 512},
+			},
+			want: Efficiency{CPURequestPercent: new(250.0)},
+			ok:   true,
+		},
+		{
+			name: "unknown usage",
+			container: ContainerMetricsResource{
+				Requests: MetricsResource{CPURequest: 100, CPUUsed: unset, MemoryUsed: unset},
+			},
+		},
+		{
+			name: "metrics unavailable",
+			container: ContainerMetricsResource{
+				Requests:           MetricsResource{CPURequest: 100, CPUUsed: unset, MemoryUsed: unset},
+				MetricsUnavailable: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.container.Efficiency()
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPodEfficiency(t *testing.T) {
+	pod := testPodMetricsResource("web", "default",
+		[]pods.ContainerResource{testContainerResource("app", 300, 600, 2048, 4096), testContainerResource("sidecar", 100, 0, 0, 0)},
+		[]podmetrics.ContainerMetric{testContainerMetric("app", 150, 1024), testContainerMetric("sidecar", 50, 512)})
+
+	efficiency, ok := pod.Efficiency()
+
+	require.True(t, ok)
+	require.Equal(t, 50.0, *efficiency.CPURequestPercent)
+	require.Equal(t, 75.0, *efficiency.MemoryRequestPercent)
+	require.Equal(t, 33.3, *efficiency.CPULimitPercent)
+	require.Equal(t, 37.5, *efficiency.MemoryLimitPercent)
+	require.Equal(t, int64(200), efficiency.CPUIdle)
+	require.Equal(t, int64(512), efficiency.MemoryIdle)
+
+	_, ok = testPodMetricsResource("pending", "default", []pods.ContainerResource{testContainerResource("app", 100, 0, 0, 0)}, nil).Efficiency()
+	require.False(t, ok)
+}
+
+func TestWasteSummary(t *testing.T) {
+	pod := func(cluster, namespace, name string, cpuRequest, cpuUsed int64) PodMetricsResource {
+		resource := testPodMetricsResource(name, namespace,
+			[]pods.ContainerResource{testContainerResource("app", cpuRequest, 0, 1024, 0)},
+			[]podmetrics.ContainerMetric{testContainerMetric("app", cpuUsed, 256)})
+		resource.Cluster = cluster
+		return resource
+	}
+	withoutMetrics := testPodMetricsResource("pending", "default", []pods.ContainerResource{testContainerResource("app", 500, 0, 0, 0)}, nil)
+
+	t.Run("namespaces", func(t *testing.T) {
+		list := PodMetricsResourceList{
+			pod("", "default", "web", 200, 50),
+			pod("", "kube-system", "dns", 100, 150),
+			pod("", "default", "db", 300, 100),
+			withoutMetrics,
+		}
+
+		summary := list.WasteSummary()
+
+		require.Equal(t, []Waste{
+			{Namespace: "default", Pods: 2, CPUIdle: 350, MemoryIdle: 1536},
+			{Namespace: "kube-system", Pods: 1, CPUIdle: 0, MemoryIdle: 768},
+		}, uncounted(summary.Namespaces...))
+		require.Empty(t, summary.Clusters)
+		require.Equal(t, []Waste{{Pods: 3, CPUIdle: 350, MemoryIdle: 2304}}, uncounted(summary.Total))
+
+		efficiency, ok := summary.Total.Efficiency()
+		require.True(t, ok)
+		require.Equal(t, 50.0, *efficiency.CPURequestPercent, "pods without usage are left out")
+		require.Equal(t, int64(350), efficiency.CPUIdle)
+	})
+
+	t.Run("clusters", func(t *testing.T) {
+		list := PodMetricsResourceList{
+			pod("prod-us", "default", "web", 200, 50),
+			pod("prod-eu", "default", "web", 100, 40),
+			pod("prod-us", "default", "db", 300, 100),
+		}
+
+		summary := list.WasteSummary()
+
+		require.Equal(t, []Waste{
+			{Cluster: "prod-us", Namespace: "default", Pods: 2, CPUIdle: 350, MemoryIdle: 1536},
+			{Cluster: "prod-eu", Namespace: "default", Pods: 1, CPUIdle: 60, MemoryIdle: 768},
+		}, uncounted(summary.Namespaces...))
+		require.Equal(t, []Waste{
+			{Cluster: "prod-us", Pods: 2, CPUIdle: 350, MemoryIdle: 1536},
+			{Cluster: "prod-eu", Pods: 1, CPUIdle: 60, MemoryIdle: 768},
+		}, uncounted(summary.Clusters...))
+		require.Equal(t, []Waste{{Pods: 3, CPUIdle: 410, MemoryIdle: 2304}}, uncounted(summary.Total))
+	})
+
+	t.Run("no pods with usage", func(t *testing.T) {
+		_, ok := PodMetricsResourceList{withoutMetrics}.WasteSummary().Total.Efficiency()
+		require.False(t, ok)
+	})
+}
+
+// uncounted drops the sums behind the efficiency of wastes.
+func uncounted(wastes ...Waste) []Waste {
+	for i := range wastes {
+		wastes[i].counted = ContainerMetricsResource{}
+	}
+	return wastes
+}
@@ -2,8 +2,10 @@ package metricsresources
 
 import (
 	"slices"
+	"time"
 
 	alerts "github.com/trezorg/k8spodsmetrics/internal/alert"
+	"github.com/trezorg/k8spodsmetrics/internal/qos"
 )
 
 func (r PodMetricsResourceList) filterBy(predicate containerMetricsPredicate) PodMetricsResourceList {
@@ -26,7 +28,18 @@ func (r PodMetricsResourceList) filterByPodResource(predicate podResourceMetrics
 	return result
 }
 
-func (r PodMetricsResourceList) filterNodes(nodes []string) PodMetricsResourceList {
+// FilterNamespaces keeps pods of one of namespaces. An empty list keeps all pods.
+func (r PodMetricsResourceList) FilterNamespaces(namespaces []string) PodMetricsResourceList {
+	if len(namespaces) == 0 {
+		return r
+	}
+	return r.filterByPodResource(func(r PodMetricsResource) bool {
+		return slices.Contains(namespaces, r.PodResource.Namespace)
+	})
+}
+
+// FilterNodes keeps pods scheduled on one of nodes. An empty list keeps all pods.
+func (r PodMetricsResourceList) FilterNodes(nodes []string) PodMetricsResourceList {
 	if len(nodes) == 0 {
 		return r
 	}
@@ -35,10 +48,28 @@ func (r PodMetricsResourceList) filterNodes(nodes []string) PodMetricsResourceLi
 	})
 }
 
-func (r PodMetricsResourceList) filterByAlert(alert alerts.Alert) PodMetricsResourceList {
+// FilterQOS keeps pods of one of classes. An empty list keeps all pods.
+func (r PodMetricsResourceList) FilterQOS(classes []qos.Class) PodMetricsResourceList {
+	if len(classes) == 0 {
+		return r
+	}
+	return r.filterByPodResource(func(r PodMetricsResource) bool {
+		return qos.Matches(r.QOSClass, classes)
+	})
+}
+
+// FilterByAlert keeps pods having a container alerted for alert. The oom alert,
+// also part of any, keeps pods with a container OOMKilled within RecentOOMWindow
+// before now, the time the pods were listed.
+// The storage alerts check pod usage, which only the kubelet metrics source
+// reports.
+func (r PodMetricsResourceList) FilterByAlert(alert alerts.Alert, now time.Time) PodMetricsResourceList {
+	oomSince := now.Add(-RecentOOMWindow)
 	switch alert {
 	case alerts.Any:
-		return r.filterBy(func(c ContainerMetricsResources) bool { return c.IsAlerted() })
+		return r.filterBy(func(c ContainerMetricsResources) bool { return c.IsAlerted() || c.IsOOMKilledSince(oomSince) })
+	case alerts.OOM:
+		return r.filterBy(func(c ContainerMetricsResources) bool { return c.IsOOMKilledSince(oomSince) })
 	case alerts.Memory:
 		return r.filterBy(func(c ContainerMetricsResources) bool { return c.IsMemoryAlerted() })
 	case alerts.MemoryRequest:
@@ -51,7 +82,13 @@ func (r PodMetricsResourceList) filterByAlert(alert alerts.Alert) PodMetricsReso
 		return r.filterBy(func(c ContainerMetricsResources) bool { return c.IsCPURequestAlerted() })
 	case alerts.CPULimit:
 		return r.filterBy(func(c ContainerMetricsResources) bool { return c.IsCPULimitAlerted() })
-	case alerts.Storage, alerts.StorageEphemeral, alerts.None:
+	case alerts.Storage:
+		return r.filterByPodResource(PodMetricsResource.IsStorageAlerted)
+	case alerts.StorageEphemeral:
+		return r.filterByPodResource(PodMetricsResource.IsStorageEphemeralAlerted)
+	// Pod slots and extended resources are node-only alerts rejected by
+	// Config.Validate.
+	case alerts.Pods, alerts.Extended, alerts.None:
 		return r
 	}
 	return r
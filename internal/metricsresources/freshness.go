@@ -0,0 +1,109 @@
+package metricsresources
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MissingMetrics counts the running pods metrics were not reported for and
+// lists the nodes they run on.
+type MissingMetrics struct {
+	Pods  int      `json:"pods" yaml:"pods"`
+	Nodes []string `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+}
+
+// HasMetrics reports whether the metrics source returned usage for the pod.
+func (r PodMetricsResource) HasMetrics() bool {
+	return r.PodMetric.Name != ""
+}
+
+// MetricsMissing reports whether a pod expected to report usage has none.
+// Unscheduled and terminated pods run no containers and are left out, as are
+// pods listed while the metrics API was unavailable.
+func (r PodMetricsResource) MetricsMissing() bool {
+	return !r.HasMetrics() && !r.MetricsUnavailable && r.NodeName != "" && !r.IsTerminated()
+}
+
+// MetricsUnavailable reports whether any pod was listed while the metrics API
+// was unavailable.
+func (r PodMetricsResourceList) MetricsUnavailable() bool {
+	return slices.ContainsFunc(r, func(pod PodMetricsResource) bool { return pod.MetricsUnavailable })
+}
+
+// markMetricsUnavailable flags the pods listed without usage as the metrics
+// API was unavailable.
+func (r PodMetricsResourceList) markMetricsUnavailable() {
+	for i := range r {
+		r[i].MetricsUnavailable = true
+	}
+}
+
+// ObservedAt is when the pod was listed, the current time if unknown.
+func (r PodMetricsResource) ObservedAt() time.Time {
+	if r.ListedAt.IsZero() {
+		return time.Now()
+	}
+	return r.ListedAt
+}
+
+// ObservedAt is when the pods were listed, the current time if unknown.
+func (r PodMetricsResourceList) ObservedAt() time.Time {
+	if len(r) == 0 {
+		return time.Now()
+	}
+	return r[0].ObservedAt()
+}
+
+// markListed records now as the time the pods were listed.
+func (r PodMetricsResourceList) markListed(now time.Time) {
+	for i := range r {
+		r[i].ListedAt = now
+	}
+}
+
+// markStaleMetrics flags pods whose metrics were collected more than maxAge
+// before now. Sources without timestamps are never stale.
+func (r PodMetricsResourceList) markStaleMetrics(now time.Time, maxAge time.Duration) {
+	if maxAge <= 0 {
+		return
+	}
+	for i := range r {
+		timestamp := r[i].PodMetric.Timestamp
+		r[i].MetricsStale = r[i].HasMetrics() && !timestamp.IsZero() && now.Sub(timestamp) > maxAge
+	}
+}
+
+// MissingMetrics summarizes the pods without metrics.
+func (r PodMetricsResourceList) MissingMetrics() MissingMetrics {
+	var result MissingMetrics
+	for _, pod := range r {
+		if !pod.MetricsMissing() {
+			continue
+		}
+		result.Pods++
+		if !slices.Contains(result.Nodes, pod.NodeName) {
+			result.Nodes = append(result.Nodes, pod.NodeName)
+		}
+	}
+	slices.SortFunc(result.Nodes, cmp.Compare[string])
+	return result
+}
+
+// String renders the summary line, empty when every pod has metrics.
+func (m MissingMetrics) String() string {
+	if m.Pods == 0 {
+		return ""
+	}
+	pods := "pods"
+	if m.Pods == 1 {
+		pods = "pod"
+	}
+	nodes := "nodes"
+	if len(m.Nodes) == 1 {
+		nodes = "node"
+	}
+	return fmt.Sprintf("%d %s without metrics on %s %s", m.Pods, pods, nodes, strings.Join(m.Nodes, ","))
+}
@@ -0,0 +1,105 @@
+package metricsresources
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	v1 "k8s.io/api/core/v1"
+)
+
+func freshnessPod(name, node string, phase v1.PodPhase, metric *podmetrics.PodMetric) PodMetricsResource {
+	resource := PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Name: name, Namespace: "default"},
+			NodeName:      node,
+			Phase:         phase,
+			Containers:    []pods.ContainerResource{{Name: "app"}},
+		},
+	}
+	if metric != nil {
+		resource.PodMetric = *metric
+	}
+	return resource
+}
+
+func TestMetricsMissing(t *testing.T) {
+	withMetrics := &podmetrics.PodMetric{Name: "web", Namespace: "default"}
+	tests := []struct {
+		name     string
+		resource PodMetricsResource
+		missing  bool
+	}{
+		{name: "with metrics", resource: freshnessPod("web", "node-1", v1.PodRunning, withMetrics)},
+		{name: "running without metrics", resource: freshnessPod("web", "node-1", v1.PodRunning, nil), missing: true},
+		{name: "unscheduled", resource: freshnessPod("web", "", v1.PodPending, nil)},
+		{name: "succeeded", resource: freshnessPod("web", "node-1", v1.PodSucceeded, nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.missing, tt.resource.MetricsMissing())
+		})
+	}
+}
+
+func TestMarkStaleMetrics(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 10, 0, 0, time.UTC)
+	list := PodMetricsResourceList{
+		freshnessPod("fresh", "node-1", v1.PodRunning, &podmetrics.PodMetric{Name: "fresh", Timestamp: now.Add(-time.Minute)}),
+		freshnessPod("old", "node-1", v1.PodRunning, &podmetrics.PodMetric{Name: "old", Timestamp: now.Add(-5 * time.Minute)}),
+		freshnessPod("untimed", "node-1", v1.PodRunning, &podmetrics.PodMetric{Name: "untimed"}),
+		freshnessPod("missing", "node-1", v1.PodRunning, nil),
+	}
+
+	list.markStaleMetrics(now, 3*time.Minute)
+	stale := make([]bool, 0, len(list))
+	for _, pod := range list {
+		stale = append(stale, pod.MetricsStale)
+	}
+	require.Equal(t, []bool{false, true, false, false}, stale)
+}
+
+func TestMissingMetricsSummary(t *testing.T) {
+	list := PodMetricsResourceList{
+		freshnessPod("a", "node-2", v1.PodRunning, nil),
+		freshnessPod("b", "node-1", v1.PodRunning, nil),
+		freshnessPod("c", "node-2", v1.PodPending, nil),
+		freshnessPod("d", "node-3", v1.PodRunning, &podmetrics.PodMetric{Name: "d", Namespace: "default"}),
+		freshnessPod("e", "node-3", v1.PodFailed, nil),
+	}
+
+	missing := list.MissingMetrics()
+	require.Equal(t, MissingMetrics{Pods: 3, Nodes: []string{"node-1", "node-2"}}, missing)
+	require.Equal(t, "3 pods without metrics on nodes node-1,node-2", missing.String())
+	require.Equal(t, "1 pod without metrics on node node-1", MissingMetrics{Pods: 1, Nodes: []string{"node-1"}}.String())
+	require.Empty(t, PodMetricsResourceList{list[3]}.MissingMetrics().String())
+}
+
+func TestMetricsFreshnessOutput(t *testing.T) {
+	collected := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	fresh := freshnessPod("web", "node-1", v1.PodRunning, &podmetrics.PodMetric{
+		Name:      "web",
+		Namespace: "default",
+		Timestamp: collected,
+		Window:    30 * time.Second,
+	})
+	fresh.MetricsStale = true
+	list := PodMetricsResourceList{fresh, freshnessPod("db", "node-2", v1.PodRunning, nil)}
+
+	data, err := json.Marshal(list)
+	require.NoError(t, err)
+	var envelope PodMetricsResourceOutputEnvelope
+	require.NoError(t, json.Unmarshal(data, &envelope))
+
+	require.Len(t, envelope.Items, 2)
+	require.Equal(t, &collected, envelope.Items[0].MetricsTimestamp)
+	require.Equal(t, "30s", envelope.Items[0].MetricsWindow)
+	require.True(t, envelope.Items[0].MetricsStale)
+	require.False(t, envelope.Items[0].MetricsMissing)
+	require.Nil(t, envelope.Items[1].MetricsTimestamp)
+	require.True(t, envelope.Items[1].MetricsMissing)
+	require.Equal(t, &MissingMetrics{Pods: 1, Nodes: []string{"node-2"}}, envelope.MissingMetrics)
+}
@@ -1,14 +1,18 @@
 package metricsresources
 
 import (
+	"time"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	v1 "k8s.io/api/core/v1"
 )
 
 const (
 	Requests ResourceType = iota
 	Limits
-	unset = int64(-1)
+	unset = podmetrics.Unset
 )
 
 type (
@@ -17,6 +21,17 @@ type (
 	PodMetricsResource struct {
 		pods.PodResource
 		podmetrics.PodMetric
+		// MetricsStale marks metrics collected longer ago than the allowed age.
+		MetricsStale bool
+		// MetricsUnavailable marks a pod listed while the metrics API was
+		// unavailable, whose usage is unknown rather than missing.
+		MetricsUnavailable bool
+		// Cluster is the kubeconfig context the pod was read from. It is only
+		// set when several contexts are queried.
+		Cluster string
+		// ListedAt is when the pod was listed, the snapshot time of a
+		// recording. Alert and metrics ages are measured from it.
+		ListedAt time.Time
 	}
 
 	PodMetricsResourceList []PodMetricsResource
@@ -26,43 +41,102 @@ type (
 		MemoryRequest           int64 `json:"memory_request,omitempty" yaml:"memory_request,omitempty"`
 		CPUUsed                 int64 `json:"cpu_used,omitempty" yaml:"cpu_used,omitempty"`
 		MemoryUsed              int64 `json:"memory_used,omitempty" yaml:"memory_used,omitempty"`
+		MemoryRSSUsed           int64 `json:"memory_rss_used,omitempty" yaml:"memory_rss_used,omitempty"`
 		StorageRequest          int64 `json:"storage_request,omitempty" yaml:"storage_request,omitempty"`
 		StorageEphemeralRequest int64 `json:"storage_ephemeral_request,omitempty" yaml:"storage_ephemeral_request,omitempty"`
 		StorageUsed             int64 `json:"storage_used,omitempty" yaml:"storage_used,omitempty"`
 		StorageEphemeralUsed    int64 `json:"storage_ephemeral_used,omitempty" yaml:"storage_ephemeral_used,omitempty"`
+		// Extended holds requested (or limited) extended resources by name.
+		// Metrics server reports no usage for them.
+		Extended map[string]int64 `json:"extended,omitempty" yaml:"extended,omitempty"`
 	}
 
 	Resource struct {
-		CPU    int64 `json:"cpu,omitempty" yaml:"cpu,omitempty"`
-		Memory int64 `json:"memory,omitempty" yaml:"memory,omitempty"`
+		CPU              int64            `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+		Memory           int64            `json:"memory,omitempty" yaml:"memory,omitempty"`
+		MemoryRSS        int64            `json:"memory_rss,omitempty" yaml:"memory_rss,omitempty"`
+		Storage          int64            `json:"storage,omitempty" yaml:"storage,omitempty"`
+		StorageEphemeral int64            `json:"storage_ephemeral,omitempty" yaml:"storage_ephemeral,omitempty"`
+		Extended         map[string]int64 `json:"extended,omitempty" yaml:"extended,omitempty"`
 	}
 
 	ContainerMetricsResource struct {
-		Name     string          `json:"name,omitempty" yaml:"name,omitempty"`
-		Limits   MetricsResource `json:"limits" yaml:"limits"`
-		Requests MetricsResource `json:"requests" yaml:"requests"`
+		Name     string             `json:"name,omitempty" yaml:"name,omitempty"`
+		Type     pods.ContainerType `json:"type,omitempty" yaml:"type,omitempty"`
+		Limits   MetricsResource    `json:"limits" yaml:"limits"`
+		Requests MetricsResource    `json:"requests" yaml:"requests"`
+		// MetricsMissing marks a spec container metrics-server reported nothing for.
+		MetricsMissing bool `json:"metrics_missing,omitempty" yaml:"metrics_missing,omitempty"`
+		// SpecMissing marks a container that has metrics but is absent from the pod spec.
+		SpecMissing bool `json:"spec_missing,omitempty" yaml:"spec_missing,omitempty"`
+		// MetricsUnavailable marks a container of a pod listed while the
+		// metrics API was unavailable.
+		MetricsUnavailable bool `json:"metrics_unavailable,omitempty" yaml:"metrics_unavailable,omitempty"`
+		// Restarts, State and the terminations are taken from the pod status.
+		Restarts        int32             `json:"restarts,omitempty" yaml:"restarts,omitempty"`
+		State           string            `json:"state,omitempty" yaml:"state,omitempty"`
+		Terminated      *pods.Termination `json:"terminated,omitempty" yaml:"terminated,omitempty"`
+		LastTermination *pods.Termination `json:"last_termination,omitempty" yaml:"last_termination,omitempty"`
 	}
 
 	ContainerMetricsResourceOutput struct {
-		Name     string   `json:"name,omitempty" yaml:"name"`
-		Limits   Resource `json:"limits" yaml:"limits"`
-		Requests Resource `json:"requests" yaml:"requests"`
-		Used     Resource `json:"used" yaml:"used"`
+		Name               string             `json:"name,omitempty" yaml:"name"`
+		Type               pods.ContainerType `json:"type,omitempty" yaml:"type,omitempty"`
+		Limits             Resource           `json:"limits" yaml:"limits"`
+		Requests           Resource           `json:"requests" yaml:"requests"`
+		Used               Resource           `json:"used" yaml:"used"`
+		MetricsMissing     bool               `json:"metrics_missing,omitempty" yaml:"metrics_missing,omitempty"`
+		SpecMissing        bool               `json:"spec_missing,omitempty" yaml:"spec_missing,omitempty"`
+		MetricsUnavailable bool               `json:"metrics_unavailable,omitempty" yaml:"metrics_unavailable,omitempty"`
+		Efficiency         *Efficiency        `json:"efficiency,omitempty" yaml:"efficiency,omitempty"`
+		Restarts           int32              `json:"restarts,omitempty" yaml:"restarts,omitempty"`
+		State              string             `json:"state,omitempty" yaml:"state,omitempty"`
+		Terminated         *pods.Termination  `json:"terminated,omitempty" yaml:"terminated,omitempty"`
+		LastTermination    *pods.Termination  `json:"last_termination,omitempty" yaml:"last_termination,omitempty"`
 	}
 
 	ContainerMetricsResources        []ContainerMetricsResource
 	ContainerMetricsResourcesOutputs []ContainerMetricsResourceOutput
 
 	PodMetricsResourceOutput struct {
+		Cluster    string                           `json:"cluster,omitempty" yaml:"cluster,omitempty"`
 		Name       string                           `json:"name,omitempty" yaml:"name,omitempty"`
 		Namespace  string                           `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 		Node       string                           `json:"node,omitempty" yaml:"node,omitempty"`
+		QOSClass   v1.PodQOSClass                   `json:"qos_class,omitempty" yaml:"qos_class,omitempty"`
+		Restarts   int32                            `json:"restarts,omitempty" yaml:"restarts,omitempty"`
+		Requests   Resource                         `json:"requests" yaml:"requests"`
+		Limits     Resource                         `json:"limits" yaml:"limits"`
+		Used       Resource                         `json:"used" yaml:"used"`
+		Efficiency *Efficiency                      `json:"efficiency,omitempty" yaml:"efficiency,omitempty"`
+		Volumes    []podmetrics.VolumeMetric        `json:"volumes,omitempty" yaml:"volumes,omitempty"`
 		Containers ContainerMetricsResourcesOutputs `json:"containers,omitempty" yaml:"containers,omitempty"`
+		// MetricsTimestamp and MetricsWindow are reported by the metrics source,
+		// MetricsWindow being the interval CPU usage is averaged over.
+		MetricsTimestamp   *time.Time `json:"metrics_timestamp,omitempty" yaml:"metrics_timestamp,omitempty"`
+		MetricsWindow      string     `json:"metrics_window,omitempty" yaml:"metrics_window,omitempty"`
+		MetricsMissing     bool       `json:"metrics_missing,omitempty" yaml:"metrics_missing,omitempty"`
+		MetricsStale       bool       `json:"metrics_stale,omitempty" yaml:"metrics_stale,omitempty"`
+		MetricsUnavailable bool       `json:"metrics_unavailable,omitempty" yaml:"metrics_unavailable,omitempty"`
 	}
 	PodMetricsResourceListOutput []PodMetricsResourceOutput
 
 	PodMetricsResourceOutputEnvelope struct {
-		Items PodMetricsResourceListOutput `json:"items,omitempty" yaml:"items,omitempty"`
+		Items          PodMetricsResourceListOutput `json:"items,omitempty" yaml:"items,omitempty"`
+		MissingMetrics *MissingMetrics              `json:"missing_metrics,omitempty" yaml:"missing_metrics,omitempty"`
+		// MetricsUnavailable is set when pods were listed without usage as
+		// the metrics API was unavailable.
+		MetricsUnavailable bool `json:"metrics_unavailable,omitempty" yaml:"metrics_unavailable,omitempty"`
+		// Clusters and Total are the per-cluster subtotals and their sum,
+		// set when pods of several clusters are listed.
+		Clusters []ClusterTotal `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+		Total    *ClusterTotal  `json:"total,omitempty" yaml:"total,omitempty"`
+		// Waste is the idle CPU and memory of namespaces and clusters, set
+		// when any pod reports usage.
+		Waste *WasteSummary `json:"waste,omitempty" yaml:"waste,omitempty"`
+		// Errors are the namespaces, nodes and clusters missing from
+		// partial results.
+		Errors partial.Warnings `json:"errors,omitempty" yaml:"errors,omitempty"`
 	}
 
 	containerMetricsPredicate   func(c ContainerMetricsResources) bool
@@ -4,10 +4,14 @@ import (
 	"io"
 	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	alerts "github.com/trezorg/k8spodsmetrics/internal/alert"
+	"github.com/trezorg/k8spodsmetrics/internal/qos"
 	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	v1 "k8s.io/api/core/v1"
 )
 
 func posResourceList(name, namespace, container string) pods.PodResourceList {
@@ -91,6 +95,241 @@ func TestContainersMetricsWithoutMetrics(t *testing.T) {
 	require.Len(t, containers, 1)
 	require.Equal(t, unset, containers[0].Requests.CPUUsed)
 	require.Equal(t, unset, containers[0].Requests.MemoryUsed)
+	require.True(t, containers[0].MetricsMissing)
+}
+
+func TestContainersMetricsJoinsByName(t *testing.T) {
+	resource := PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Name: "foo", Namespace: "bar"},
+			Containers: []pods.ContainerResource{
+				{Name: "app", Requests: pods.Resource{CPU: 100}},
+				{Name: "worker", Requests: pods.Resource{CPU: 200}},
+				{Name: "proxy", Type: pods.SidecarContainer, Requests: pods.Resource{CPU: 50}},
+			},
+		},
+		PodMetric: podmetrics.PodMetric{
+			Name:      "foo",
+			Namespace: "bar",
+			Containers: []podmetrics.ContainerMetric{
+				{Name: "app", Metric: podmetrics.Metric{CPU: 10}},
+				{Name: "debugger", Metric: podmetrics.Metric{CPU: 5}},
+				{Name: "proxy", Metric: podmetrics.Metric{CPU: 30}},
+			},
+		},
+	}
+
+	containers := resource.ContainersMetrics()
+	require.Len(t, containers, 4)
+
+	require.Equal(t, "app", containers[0].Name)
+	require.Equal(t, int64(10), containers[0].Requests.CPUUsed)
+	require.False(t, containers[0].MetricsMissing)
+
+	require.Equal(t, "worker", containers[1].Name)
+	require.Equal(t, unset, containers[1].Requests.CPUUsed)
+	require.True(t, containers[1].MetricsMissing)
+
+	require.Equal(t, "proxy", containers[2].Name)
+	require.Equal(t, int64(30), containers[2].Requests.CPUUsed)
+	require.Equal(t, int64(50), containers[2].Requests.CPURequest)
+
+	require.Equal(t, "debugger", containers[3].Name)
+	require.Equal(t, int64(5), containers[3].Requests.CPUUsed)
+	require.Equal(t, int64(0), containers[3].Requests.CPURequest)
+	require.True(t, containers[3].SpecMissing)
+
+	require.Equal(t, int64(45), resource.PodMetrics().Requests.CPUUsed)
+}
+
+func TestContainersMetricsOutputMarkers(t *testing.T) {
+	resource := PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Name: "foo", Namespace: "bar"},
+			Containers:    []pods.ContainerResource{{Name: "app"}},
+		},
+		PodMetric: podmetrics.PodMetric{
+			Name:       "foo",
+			Namespace:  "bar",
+			Containers: []podmetrics.ContainerMetric{{Name: "debugger", Metric: podmetrics.Metric{CPU: 5}}},
+		},
+	}
+
+	output := resource.toOutput()
+	require.Len(t, output.Containers, 2)
+	require.True(t, output.Containers[0].MetricsMissing)
+	require.True(t, output.Containers[1].SpecMissing)
+}
+
+func TestFilterQOS(t *testing.T) {
+	list := PodMetricsResourceList{
+		{PodResource: pods.PodResource{NamespaceName: pods.NamespaceName{Name: "g"}, QOSClass: v1.PodQOSGuaranteed}},
+		{PodResource: pods.PodResource{NamespaceName: pods.NamespaceName{Name: "b"}, QOSClass: v1.PodQOSBurstable}},
+		{PodResource: pods.PodResource{NamespaceName: pods.NamespaceName{Name: "be"}, QOSClass: v1.PodQOSBestEffort}},
+	}
+
+	require.Equal(t, list, list.FilterQOS(nil))
+	filtered := list.FilterQOS([]qos.Class{qos.BestEffort, qos.Burstable})
+	require.Len(t, filtered, 2)
+	require.Equal(t, "b", filtered[0].PodResource.Name)
+	require.Equal(t, "be", filtered[1].PodResource.Name)
+	require.Equal(t, v1.PodQOSBestEffort, filtered[1].toOutput().QOSClass)
+}
+
+func TestContainersMetricsCarryStatus(t *testing.T) {
+	lastTermination := &pods.Termination{Reason: pods.OOMKilled, ExitCode: 137, FinishedAt: time.Now()}
+	resource := PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Name: "foo", Namespace: "bar"},
+			Containers: []pods.ContainerResource{
+				{Name: "app", Restarts: 2, State: "Running", LastTermination: lastTermination},
+				{Name: "sidecar", Restarts: 1, State: "Running"},
+			},
+		},
+	}
+
+	containers := resource.ContainersMetrics()
+	require.Equal(t, int32(2), containers[0].Restarts)
+	require.Equal(t, "Running", containers[0].State)
+	require.Equal(t, lastTermination, containers[0].LastTermination)
+	require.Equal(t, int32(3), resource.Restarts())
+	require.Equal(t, int32(3), resource.PodMetrics().Restarts)
+	require.Equal(t, lastTermination, resource.PodMetrics().LastTermination)
+
+	output := resource.toOutput()
+	require.Equal(t, int32(3), output.Restarts)
+	require.Equal(t, lastTermination, output.Containers[0].LastTermination)
+}
+
+func TestFilterByAlertOOM(t *testing.T) {
+	pod := func(name string, termination *pods.Termination) PodMetricsResource {
+		return PodMetricsResource{
+			PodResource: pods.PodResource{
+				NamespaceName: pods.NamespaceName{Name: name},
+				Containers:    []pods.ContainerResource{{Name: "app", LastTermination: termination}},
+			},
+		}
+	}
+	// A replayed snapshot is judged by the time it was taken.
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	list := PodMetricsResourceList{
+		pod("recent", &pods.Termination{Reason: pods.OOMKilled, FinishedAt: now.Add(-time.Minute)}),
+		pod("old", &pods.Termination{Reason: pods.OOMKilled, FinishedAt: now.Add(-2 * RecentOOMWindow)}),
+		pod("error", &pods.Termination{Reason: "Error", FinishedAt: now}),
+		pod("running", nil),
+	}
+
+	filtered := list.FilterByAlert(alerts.OOM, now)
+	require.Len(t, filtered, 1)
+	require.Equal(t, "recent", filtered[0].PodResource.Name)
+
+	filtered = list.FilterByAlert(alerts.Any, now)
+	require.Len(t, filtered, 1)
+	require.Equal(t, "recent", filtered[0].PodResource.Name)
+
+	require.Empty(t, list.FilterByAlert(alerts.OOM, now.Add(2*RecentOOMWindow)))
+}
+
+func TestFilterByAlertStorage(t *testing.T) {
+	pod := func(name string, ephemeralLimit int64, metric podmetrics.PodMetric) PodMetricsResource {
+		metric.Name = name
+		return PodMetricsResource{
+			PodResource: pods.PodResource{
+				NamespaceName: pods.NamespaceName{Name: name},
+				Containers:    []pods.ContainerResource{{Name: "app", Limits: pods.Resource{StorageEphemeral: ephemeralLimit}}},
+			},
+			PodMetric: metric,
+		}
+	}
+	list := PodMetricsResourceList{
+		pod("ephemeral-full", 1000, podmetrics.PodMetric{StorageEphemeral: 1000}),
+		pod("ephemeral-free", 1000, podmetrics.PodMetric{StorageEphemeral: 100}),
+		pod("volume-full", 0, podmetrics.PodMetric{Volumes: []podmetrics.VolumeMetric{{Name: "data", Used: 99, Capacity: 100}}}),
+		pod("volume-free", 0, podmetrics.PodMetric{Volumes: []podmetrics.VolumeMetric{{Name: "data", Used: 50, Capacity: 100}}}),
+		pod("no-metrics", 1000, podmetrics.PodMetric{}),
+	}
+
+	filtered := list.FilterByAlert(alerts.StorageEphemeral, time.Now())
+	require.Len(t, filtered, 1)
+	require.Equal(t, "ephemeral-full", filtered[0].PodResource.Name)
+
+	filtered = list.FilterByAlert(alerts.Storage, time.Now())
+	require.Len(t, filtered, 1)
+	require.Equal(t, "volume-full", filtered[0].PodResource.Name)
+}
+
+func TestPodMetricsUsesEffectiveRequests(t *testing.T) {
+	resource := PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Name: "foo", Namespace: "bar"},
+			Containers: []pods.ContainerResource{
+				{Name: "app", Requests: pods.Resource{CPU: 100, Memory: 1024}, Limits: pods.Resource{CPU: 200}},
+				{Name: "migrate", Type: pods.InitContainer, Requests: pods.Resource{CPU: 500}},
+			},
+			Overhead: pods.Resource{CPU: 10},
+		},
+		PodMetric: podmetrics.PodMetric{
+			Name:      "foo",
+			Namespace: "bar",
+			Containers: []podmetrics.ContainerMetric{
+				{Name: "app", Metric: podmetrics.Metric{CPU: 80, Memory: 512}},
+			},
+		},
+	}
+
+	pod := resource.PodMetrics()
+	require.Equal(t, int64(510), pod.Requests.CPURequest)
+	require.Equal(t, int64(1024), pod.Requests.MemoryRequest)
+	require.Equal(t, int64(210), pod.Limits.CPURequest)
+	require.Equal(t, int64(80), pod.Requests.CPUUsed)
+	require.Equal(t, int64(512), pod.Requests.MemoryUsed)
+
+	containers := resource.ContainersMetrics()
+	require.Len(t, containers, 2)
+	require.Equal(t, pods.InitContainer, containers[1].Type)
+	require.Equal(t, unset, containers[1].Requests.CPUUsed)
+}
+
+func TestPodMetricsWithoutMetricsKeepsUsageUnset(t *testing.T) {
+	resource := PodMetricsResource{PodResource: posResourceList("foo", "bar", "foo-container")[0]}
+
+	pod := resource.PodMetrics()
+	require.Equal(t, int64(1), pod.Requests.CPURequest)
+	require.Equal(t, unset, pod.Requests.CPUUsed)
+	require.Equal(t, unset, pod.Requests.MemoryUsed)
+}
+
+func TestPodMetricsKubeletStorage(t *testing.T) {
+	resource := PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Name: "db", Namespace: "bar"},
+			Containers:    []pods.ContainerResource{{Name: "db"}, {Name: "exporter"}},
+		},
+		PodMetric: podmetrics.PodMetric{
+			Name:             "db",
+			Namespace:        "bar",
+			StorageEphemeral: 4096,
+			Volumes: []podmetrics.VolumeMetric{
+				{Name: "data", ClaimName: "data-db", Used: 1000, Capacity: 5000},
+				{Name: "wal", ClaimName: "wal-db", Used: 500, Capacity: 1000},
+			},
+			Containers: []podmetrics.ContainerMetric{
+				{Name: "db", Metric: podmetrics.Metric{CPU: 10, Memory: 300, MemoryRSS: 200, StorageEphemeral: 100}},
+				{Name: "exporter", Metric: podmetrics.Metric{CPU: 1, Memory: 30, MemoryRSS: 20, StorageEphemeral: 10}},
+			},
+		},
+	}
+
+	pod := resource.PodMetrics()
+	require.Equal(t, int64(220), pod.Requests.MemoryRSSUsed)
+	require.Equal(t, int64(4096), pod.Requests.StorageEphemeralUsed)
+	require.Equal(t, int64(1500), pod.Requests.StorageUsed)
+
+	output := resource.toOutput()
+	require.Equal(t, Resource{CPU: 11, Memory: 330, MemoryRSS: 220, Storage: 1500, StorageEphemeral: 4096}, output.Used)
+	require.Len(t, output.Volumes, 2)
+	require.Equal(t, int64(200), output.Containers[0].Used.MemoryRSS)
+	require.Equal(t, int64(100), output.Containers[0].Used.StorageEphemeral)
 }
 
 func TestNewPodRepository(t *testing.T) {
@@ -9,6 +9,8 @@ import (
 
 	"log/slog"
 
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -25,31 +27,53 @@ type PodRepository interface {
 	FetchMetrics(
 		ctx context.Context,
 		metricsClient metricsv1beta1.MetricsV1beta1Interface,
+		coreClient corev1.CoreV1Interface,
 		filter podmetrics.MetricFilter,
 	) (podmetrics.PodMetricList, error)
 }
 
-type podRepository struct{}
+type podRepository struct {
+	metrics metricssource.Reader
+	// pods is set for watches, which keep pods in informer caches.
+	pods *pods.Cache
+}
 
 func NewPodRepository() PodRepository {
-	return &podRepository{}
+	return NewPodRepositoryWithSource(metricssource.Config{})
+}
+
+// NewPodRepositoryWithSource returns a repository reading usage metrics from
+// the configured source, metrics-server when none is set.
+func NewPodRepositoryWithSource(source metricssource.Config) PodRepository {
+	return &podRepository{metrics: metricssource.New(source)}
 }
 
-func (podRepository) FetchPods(
+// NewCachedPodRepositoryWithSource is NewPodRepositoryWithSource keeping pods
+// in informer caches until ctx is done, so only metrics are requested on every
+// fetch.
+func NewCachedPodRepositoryWithSource(ctx context.Context, source metricssource.Config) PodRepository {
+	return &podRepository{metrics: metricssource.New(source), pods: pods.NewCache(ctx)}
+}
+
+func (r podRepository) FetchPods(
 	ctx context.Context,
 	podsClient corev1.CoreV1Interface,
 	filter pods.PodFilter,
 	nodeNames ...string,
 ) (pods.PodResourceList, error) {
+	if r.pods != nil {
+		return r.pods.Pods(ctx, podsClient, filter, nodeNames...)
+	}
 	return pods.Pods(ctx, podsClient, filter, nodeNames...)
 }
 
-func (podRepository) FetchMetrics(
+func (r podRepository) FetchMetrics(
 	ctx context.Context,
 	metricsClient metricsv1beta1.MetricsV1beta1Interface,
+	coreClient corev1.CoreV1Interface,
 	filter podmetrics.MetricFilter,
 ) (podmetrics.PodMetricList, error) {
-	return podmetrics.Metrics(ctx, metricsClient, filter)
+	return r.metrics.PodMetrics(ctx, metricsClient, coreClient, filter)
 }
 
 type FetchConfig struct {
@@ -80,7 +104,9 @@ func FetchPodMetrics(
 		return fetchPodMetricsForNamespace(ctx, repo, metricsClient, podsClient, config, ns)
 	}
 
-	// Multiple namespaces: query each in parallel
+	// Multiple namespaces: query each in parallel. The kubelet source reads
+	// every node once and the namespaces split the result.
+	ctx = metricssource.WithSharedReads(ctx)
 	var wg sync.WaitGroup
 	results := make([]PodMetricsResourceList, len(config.Namespaces))
 	rErrors := make([]error, len(config.Namespaces))
@@ -96,11 +122,7 @@ func FetchPodMetrics(
 
 	wg.Wait()
 
-	if err := errors.Join(rErrors...); err != nil {
-		return nil, err
-	}
-
-	return slices.Concat(results...), nil
+	return partial.Join(ctx, partial.Namespace, config.Namespaces, results, rErrors)
 }
 
 func fetchPodMetricsForNamespace(
@@ -119,10 +141,11 @@ func fetchPodMetricsForNamespace(
 	wg := sync.WaitGroup{}
 
 	wg.Go(func() {
-		metricsList, cErrors[0] = repo.FetchMetrics(ctx, metricsClient, podmetrics.MetricFilter{
+		metricsList, cErrors[0] = repo.FetchMetrics(ctx, metricsClient, podsClient, podmetrics.MetricFilter{
 			Namespaces:    []string{namespace},
 			LabelSelector: config.Label,
 			FieldSelector: config.FieldSelector,
+			Nodes:         config.Nodes,
 		})
 		if cErrors[0] != nil {
 			cErrors[0] = wrapPodMetricsActionError("fetch pod usage metrics", namespace, cErrors[0])
@@ -142,11 +165,22 @@ func fetchPodMetricsForNamespace(
 
 	wg.Wait()
 
+	// Pods are still listed with their requests and limits when the metrics
+	// API is unavailable, watches keep polling until usage is back.
+	unavailable := errors.Is(cErrors[0], metricssource.ErrUnavailable)
+	if unavailable {
+		slog.Debug("Metrics API is unavailable", slog.String("namespace", namespace), slog.Any("error", cErrors[0]))
+		cErrors[0] = nil
+	}
+
 	if err := errors.Join(cErrors...); err != nil {
 		return podMetricsResourceList, err
 	}
 
 	podMetricsResourceList = merge(podsList, metricsList)
+	if unavailable {
+		podMetricsResourceList.markMetricsUnavailable()
+	}
 	return podMetricsResourceList, nil
 }
 
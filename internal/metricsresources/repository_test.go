@@ -3,9 +3,12 @@ package metricsresources
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -32,6 +35,7 @@ func (s stubPodRepository) FetchPods(
 func (s stubPodRepository) FetchMetrics(
 	_ context.Context,
 	metricsClient metricsv1beta1.MetricsV1beta1Interface,
+	_ corev1.CoreV1Interface,
 	filter podmetrics.MetricFilter,
 ) (podmetrics.PodMetricList, error) {
 	if s.fetchMetrics != nil {
@@ -84,3 +88,63 @@ func TestFetchPodMetricsWrapsAllNamespaceErrors(t *testing.T) {
 	require.NotContains(t, err.Error(), `namespace ""`)
 	require.ErrorIs(t, err, rootErr)
 }
+
+func TestFetchPodMetricsKeepsListedNamespacesWithPartialResults(t *testing.T) {
+	rootErr := errors.New("forbidden")
+	repo := stubPodRepository{
+		fetchPods: func(_ corev1.CoreV1Interface, filter pods.PodFilter, _ ...string) (pods.PodResourceList, error) {
+			if filter.Namespaces[0] == "team-b" {
+				return nil, rootErr
+			}
+			return pods.PodResourceList{{NamespaceName: pods.NamespaceName{Name: "web", Namespace: filter.Namespaces[0]}}}, nil
+		},
+	}
+	config := FetchConfig{Namespaces: []string{"team-a", "team-b"}}
+
+	_, err := FetchPodMetrics(t.Context(), repo, nil, nil, config)
+	require.ErrorIs(t, err, rootErr)
+
+	list, err := partial.Collect(partial.Enable(t.Context()), func(ctx context.Context) (PodMetricsResourceList, error) {
+		return FetchPodMetrics(ctx, repo, nil, nil, config)
+	})
+	require.Len(t, list, 1)
+	require.Equal(t, "team-a", list[0].PodResource.Namespace)
+	var partialErr *partial.Error
+	require.ErrorAs(t, err, &partialErr)
+	require.Len(t, partialErr.Warnings, 1)
+	require.Equal(t, partial.Namespace, partialErr.Warnings[0].Scope)
+	require.Equal(t, "team-b", partialErr.Warnings[0].Name)
+	require.Contains(t, partialErr.Warnings[0].Message, `fetch pod resources for namespace "team-b"`)
+}
+
+func TestFetchPodMetricsWithMetricsUnavailable(t *testing.T) {
+	repo := stubPodRepository{
+		fetchPods: func(_ corev1.CoreV1Interface, filter pods.PodFilter, _ ...string) (pods.PodResourceList, error) {
+			return pods.PodResourceList{{
+				NamespaceName: pods.NamespaceName{Name: "web", Namespace: filter.Namespaces[0]},
+				NodeName:      "node-a",
+				Containers:    []pods.ContainerResource{{Name: "app", Requests: pods.Resource{CPU: 100}}},
+			}}, nil
+		},
+		fetchMetrics: func(metricsv1beta1.MetricsV1beta1Interface, podmetrics.MetricFilter) (podmetrics.PodMetricList, error) {
+			return nil, fmt.Errorf("%w: the server is currently unable to handle the request", metricssource.ErrUnavailable)
+		},
+	}
+
+	list, err := FetchPodMetrics(t.Context(), repo, nil, nil, FetchConfig{Namespaces: []string{"team-a"}})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.True(t, list.MetricsUnavailable())
+	require.False(t, list[0].MetricsMissing())
+	require.Zero(t, list.MissingMetrics().Pods)
+
+	containers := list[0].ContainersMetrics()
+	require.Len(t, containers, 1)
+	require.True(t, containers[0].MetricsUnavailable)
+	require.False(t, containers[0].MetricsMissing)
+	require.Equal(t, int64(100), containers[0].Requests.CPURequest)
+
+	envelope := list.Envelope(nil)
+	require.True(t, envelope.MetricsUnavailable)
+	require.Nil(t, envelope.MissingMetrics)
+}
@@ -2,25 +2,41 @@ package metricsresources
 
 import (
 	"encoding/json"
+	"time"
 
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"gopkg.in/yaml.v3"
 )
 
 func (c ContainerMetricsResource) toOutput() ContainerMetricsResourceOutput {
 	return ContainerMetricsResourceOutput{
 		Name: c.Name,
+		Type: c.Type,
 		Limits: Resource{
-			CPU:    c.Limits.CPURequest,
-			Memory: c.Limits.MemoryRequest,
+			CPU:      c.Limits.CPURequest,
+			Memory:   c.Limits.MemoryRequest,
+			Extended: c.Limits.Extended,
 		},
 		Requests: Resource{
-			CPU:    c.Requests.CPURequest,
-			Memory: c.Requests.MemoryRequest,
+			CPU:      c.Requests.CPURequest,
+			Memory:   c.Requests.MemoryRequest,
+			Extended: c.Requests.Extended,
 		},
 		Used: Resource{
-			CPU:    c.Requests.CPUUsed,
-			Memory: c.Requests.MemoryUsed,
+			CPU:              c.Requests.CPUUsed,
+			Memory:           c.Requests.MemoryUsed,
+			MemoryRSS:        usageOrZero(c.Requests.MemoryRSSUsed),
+			Storage:          usageOrZero(c.Requests.StorageUsed),
+			StorageEphemeral: usageOrZero(c.Requests.StorageEphemeralUsed),
 		},
+		MetricsMissing:     c.MetricsMissing,
+		SpecMissing:        c.SpecMissing,
+		MetricsUnavailable: c.MetricsUnavailable,
+		Efficiency:         efficiencyOrNil(c.Efficiency()),
+		Restarts:           c.Restarts,
+		State:              c.State,
+		Terminated:         c.Terminated,
+		LastTermination:    c.LastTermination,
 	}
 }
 
@@ -34,12 +50,64 @@ func (c ContainerMetricsResources) toOutput() ContainerMetricsResourcesOutputs {
 
 func (r PodMetricsResource) toOutput() PodMetricsResourceOutput {
 	containers := r.ContainersMetrics()
+	requests := r.PodResource.EffectiveRequests()
+	limits := r.PodResource.EffectiveLimits()
+	pod := r.PodMetrics()
+	used := pod.Requests
 	return PodMetricsResourceOutput{
-		r.PodResource.Name,
-		r.PodResource.Namespace,
-		r.NodeName,
-		containers.toOutput(),
+		Cluster:   r.Cluster,
+		Name:      r.PodResource.Name,
+		Namespace: r.PodResource.Namespace,
+		Node:      r.NodeName,
+		QOSClass:  r.QOSClass,
+		Restarts:  r.Restarts(),
+		Requests:  Resource{CPU: requests.CPU, Memory: requests.Memory, Extended: requests.Extended},
+		Limits:    Resource{CPU: limits.CPU, Memory: limits.Memory, Extended: limits.Extended},
+		Used: Resource{
+			CPU:              usageOrZero(used.CPUUsed),
+			Memory:           usageOrZero(used.MemoryUsed),
+			MemoryRSS:        usageOrZero(used.MemoryRSSUsed),
+			Storage:          usageOrZero(used.StorageUsed),
+			StorageEphemeral: usageOrZero(used.StorageEphemeralUsed),
+		},
+		Efficiency:         efficiencyOrNil(pod.Efficiency()),
+		Volumes:            r.PodMetric.Volumes,
+		Containers:         containers.toOutput(),
+		MetricsTimestamp:   timestampOrNil(r.PodMetric.Timestamp),
+		MetricsWindow:      windowString(r.PodMetric.Window),
+		MetricsMissing:     r.MetricsMissing(),
+		MetricsStale:       r.MetricsStale,
+		MetricsUnavailable: r.MetricsUnavailable,
+	}
+}
+
+func timestampOrNil(timestamp time.Time) *time.Time {
+	if timestamp.IsZero() {
+		return nil
 	}
+	return &timestamp
+}
+
+func windowString(window time.Duration) string {
+	if window <= 0 {
+		return ""
+	}
+	return window.String()
+}
+
+func efficiencyOrNil(efficiency Efficiency, ok bool) *Efficiency {
+	if !ok {
+		return nil
+	}
+	return &efficiency
+}
+
+// usageOrZero drops the unset marker so that missing usage is omitted.
+func usageOrZero(value int64) int64 {
+	if value == unset {
+		return 0
+	}
+	return value
 }
 
 func (r PodMetricsResourceList) toOutput() PodMetricsResourceOutputEnvelope {
@@ -47,9 +115,30 @@ func (r PodMetricsResourceList) toOutput() PodMetricsResourceOutputEnvelope {
 	for _, item := range r {
 		items = append(items, item.toOutput())
 	}
-	return PodMetricsResourceOutputEnvelope{
-		Items: items,
+	envelope := PodMetricsResourceOutputEnvelope{
+		Items:              items,
+		MetricsUnavailable: r.MetricsUnavailable(),
+	}
+	if missing := r.MissingMetrics(); missing.Pods > 0 {
+		envelope.MissingMetrics = &missing
 	}
+	if r.Clustered() {
+		envelope.Clusters = r.ClusterTotals()
+		total := SumClusterTotals(envelope.Clusters)
+		envelope.Total = &total
+	}
+	if waste := r.WasteSummary(); waste.Total.Pods > 0 {
+		envelope.Waste = &waste
+	}
+	return envelope
+}
+
+// Envelope is the JSON and YAML document of the list with the warnings of
+// partial results.
+func (r PodMetricsResourceList) Envelope(warnings partial.Warnings) PodMetricsResourceOutputEnvelope {
+	envelope := r.toOutput()
+	envelope.Errors = warnings
+	return envelope
 }
 
 func (r PodMetricsResource) MarshalJSON() ([]byte, error) {
@@ -3,33 +3,63 @@ package metricsresources
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/trezorg/k8spodsmetrics/internal/alert"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
+	"github.com/trezorg/k8spodsmetrics/internal/qos"
 	"github.com/trezorg/k8spodsmetrics/internal/serviceorchestration"
 	sorting "github.com/trezorg/k8spodsmetrics/internal/sorting/metricsresources"
 	"github.com/trezorg/k8spodsmetrics/pkg/client"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 )
 
 type Config struct {
-	KubeConfig    string
-	KubeContext   string
-	Namespaces    []string
+	KubeConfig string
+	// KubeContext is a context name or a comma separated list of names and
+	// glob patterns. Several contexts are queried concurrently.
+	KubeContext string
+	Overrides   client.Overrides
+	Namespaces  []string
+	// AllNamespaces keeps an empty Namespaces from defaulting to the
+	// namespace of the kubeconfig context.
+	AllNamespaces bool
 	Label         string
 	FieldSelector string
 	Nodes         []string
+	// QOSClasses keeps pods of these QoS classes. Empty keeps all pods.
+	QOSClasses    []string
 	Sorting       string
 	Alert         string
+	MetricsSource metricssource.Config
+	// MetricsMaxAge is the age after which metrics are marked stale, zero
+	// disables the check.
+	MetricsMaxAge time.Duration
 	WatchPeriod   uint
 	Timeout       uint
-	Reverse       bool
+	Retry         retry.Policy
+	// PartialResults keeps the pods of the namespaces, nodes and clusters
+	// that were listed when others fail and reports the failed ones as
+	// warnings.
+	PartialResults bool
+	Reverse        bool
+	// kubeContexts are the contexts KubeContext expands to.
+	kubeContexts []string
 }
 
 type WatchResponse = serviceorchestration.WatchResponse[PodMetricsResourceList]
 
 func (c Config) Validate() error {
-	if err := alert.Valid(alert.Alert(c.Alert)); err != nil {
+	if err := alert.ValidForPods(alert.Alert(c.Alert)); err != nil {
+		return err
+	}
+	if err := qos.Valid(qos.FromStrings(c.QOSClasses...)...); err != nil {
+		return err
+	}
+	if err := c.MetricsSource.Validate(); err != nil {
 		return err
 	}
 	return sorting.Valid(sorting.Sorting(c.Sorting))
@@ -51,6 +81,7 @@ func (c Config) apiRequest(
 	metricsClient metricsv1beta1.MetricsV1beta1Interface,
 	podsClient corev1.CoreV1Interface,
 ) (PodMetricsResourceList, error) {
+	ctx = retry.WithPolicy(ctx, c.Retry)
 	fetchConfig := FetchConfig{
 		Namespaces:    c.Namespaces,
 		Label:         c.Label,
@@ -61,37 +92,113 @@ func (c Config) apiRequest(
 	if err != nil {
 		return nil, err
 	}
-	podMetricsResourceList = podMetricsResourceList.filterByAlert(alert.Alert(c.Alert))
-	podMetricsResourceList = podMetricsResourceList.filterNodes(c.Nodes)
-	podMetricsResourceList.sort(c.Sorting, c.Reverse)
-	return podMetricsResourceList, nil
+	now := time.Now()
+	podMetricsResourceList.markListed(now)
+	podMetricsResourceList.markStaleMetrics(now, c.MetricsMaxAge)
+	return c.refine(podMetricsResourceList, now), nil
+}
+
+// Refine applies the namespace, node, QoS and alert filters and the sorting
+// to pods read elsewhere, such as from a recording. Pods of several clusters
+// are sorted within their cluster and clusters keep their order, as when
+// they are requested. Recent OOM kills are those before now, the time the
+// pods were read.
+func (c Config) Refine(list PodMetricsResourceList, now time.Time) PodMetricsResourceList {
+	list = list.FilterNamespaces(c.Namespaces)
+	if !list.Clustered() {
+		return c.refine(list, now)
+	}
+	var result PodMetricsResourceList
+	for _, group := range list.GroupByCluster() {
+		result = append(result, c.refine(group, now)...)
+	}
+	return result
+}
+
+func (c Config) refine(list PodMetricsResourceList, now time.Time) PodMetricsResourceList {
+	list = list.FilterByAlert(alert.Alert(c.Alert), now)
+	list = list.FilterNodes(c.Nodes)
+	list = list.FilterQOS(qos.FromStrings(c.QOSClasses...))
+	list.sort(c.Sorting, c.Reverse)
+	return list
+}
+
+func (c *Config) newRepository() PodRepository {
+	return NewPodRepositoryWithSource(c.MetricsSource)
+}
+
+func (c *Config) newWatchRepository(ctx context.Context) PodRepository {
+	return NewCachedPodRepositoryWithSource(ctx, c.MetricsSource)
 }
 
 func (c *Config) Request(ctx context.Context) (PodMetricsResourceList, error) {
+	ctx = c.partialContext(ctx)
+	if len(c.kubeContexts) > 1 {
+		return serviceorchestration.RequestClustersWithRepo(
+			ctx,
+			c.KubeConfig,
+			c.kubeContexts,
+			c.Timeout,
+			c.Overrides.Clients,
+			c.newRepository,
+			c.apiRequest,
+			mergeClusters,
+		)
+	}
 	return serviceorchestration.RequestWithRepo(
 		ctx,
 		c.KubeConfig,
-		c.KubeContext,
+		c.kubeContext(),
 		c.Timeout,
-		client.Clients,
-		NewPodRepository,
+		c.Overrides.Clients,
+		c.newRepository,
 		c.apiRequest,
 	)
 }
 
 func (c *Config) Watch(ctx context.Context) <-chan WatchResponse {
-	return serviceorchestration.WatchWithRepo(
+	ctx = c.partialContext(ctx)
+	if len(c.kubeContexts) > 1 {
+		return serviceorchestration.WatchClustersWithRepoContext(
+			ctx,
+			c.KubeConfig,
+			c.kubeContexts,
+			c.WatchPeriod,
+			c.Timeout,
+			c.Overrides.Clients,
+			c.newWatchRepository,
+			c.apiRequest,
+			mergeClusters,
+		)
+	}
+	return serviceorchestration.WatchWithRepoContext(
 		ctx,
 		c.KubeConfig,
-		c.KubeContext,
+		c.kubeContext(),
 		c.WatchPeriod,
 		c.Timeout,
-		client.Clients,
-		NewPodRepository,
+		c.Overrides.Clients,
+		c.newWatchRepository,
 		c.apiRequest,
 	)
 }
 
+func (c *Config) partialContext(ctx context.Context) context.Context {
+	if c.PartialResults {
+		return partial.Enable(ctx)
+	}
+	return ctx
+}
+
+// kubeContext is the single context to query, a pattern matching one context
+// resolves to it.
+func (c *Config) kubeContext() string {
+	if len(c.kubeContexts) == 1 {
+		return c.kubeContexts[0]
+	}
+	return c.KubeContext
+}
+
 func (c *Config) prepare() error {
 	if c.KubeConfig == "" {
 		var err error
@@ -100,9 +207,32 @@ func (c *Config) prepare() error {
 			return err
 		}
 	}
+	kubeContexts, err := client.Contexts(c.KubeConfig, c.KubeContext)
+	if err != nil {
+		return err
+	}
+	c.kubeContexts = kubeContexts
+	// Each of several contexts may name its own namespace, so only a single
+	// context narrows the pods to its namespace.
+	if len(c.Namespaces) > 0 || c.AllNamespaces || len(kubeContexts) > 1 {
+		return nil
+	}
+	namespace, err := client.ContextNamespace(c.KubeConfig, kubeContexts[0])
+	if err != nil {
+		return err
+	}
+	if namespace != "" {
+		c.Namespaces = []string{namespace}
+	}
 	return nil
 }
 
+// RequestedNamespaces returns the namespaces pods are requested from once a
+// request is prepared, empty for all namespaces.
+func (c *Config) RequestedNamespaces() []string {
+	return c.Namespaces
+}
+
 func (c *Config) prepareRequest() error {
 	if err := c.Validate(); err != nil {
 		return err
@@ -118,15 +248,17 @@ func (c *Config) prepareWatch() error {
 }
 
 func (c *Config) Process(successProcessor SuccessProcessor) error {
-	return serviceorchestration.ProcessRequest(c.prepareRequest, c.Request, successProcessor.Success)
+	return serviceorchestration.ProcessPartialRequest(c.prepareRequest, c.Request, successProcessor.Success)
 }
 
 func (c *Config) ProcessWatch(successProcessor SuccessProcessor, errorProcessor ErrorProcessor) error {
-	return serviceorchestration.ProcessWatch(c.prepareWatch, c.Watch, successProcessor.Success, errorProcessor.Error)
+	return serviceorchestration.ProcessPartialWatch(c.prepareWatch, c.Watch, successProcessor.Success, errorProcessor.Error)
 }
 
+// SuccessProcessor renders pods. Partial results come with the warnings of
+// the scopes that failed.
 type SuccessProcessor interface {
-	Success(PodMetricsResourceList)
+	Success(PodMetricsResourceList, partial.Warnings)
 }
 
 type ErrorProcessor interface {
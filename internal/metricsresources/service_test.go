@@ -5,8 +5,11 @@ import (
 	"errors"
 	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
 	v1 "k8s.io/api/core/v1"
@@ -75,6 +78,31 @@ func TestConfigValidate(t *testing.T) {
 		cfg := Config{Sorting: "name", Alert: "invalid"}
 		require.ErrorContains(t, cfg.Validate(), "alert should be one of")
 	})
+
+	t.Run("node only alert", func(t *testing.T) {
+		for _, name := range []string{"pods", "extended", "nvidia.com/gpu"} {
+			cfg := Config{Sorting: "name", Alert: name}
+			require.ErrorContains(t, cfg.Validate(), "applies to nodes only")
+		}
+	})
+
+	t.Run("qos classes", func(t *testing.T) {
+		cfg := Config{Sorting: "qos", Alert: "none", QOSClasses: []string{"BestEffort", "burstable"}}
+		require.NoError(t, cfg.Validate())
+		cfg.QOSClasses = []string{"critical"}
+		require.ErrorContains(t, cfg.Validate(), "qos should be one of")
+	})
+
+	t.Run("metrics source", func(t *testing.T) {
+		cfg := Config{Sorting: "name", Alert: "none", MetricsSource: metricssource.Config{Source: metricssource.Kubelet}}
+		require.NoError(t, cfg.Validate())
+		cfg.MetricsSource = metricssource.Config{Source: "invalid"}
+		require.ErrorContains(t, cfg.Validate(), "metrics source should be one of")
+		cfg.MetricsSource = metricssource.Config{Source: metricssource.Prometheus}
+		require.ErrorContains(t, cfg.Validate(), "prometheus url is required")
+		cfg.MetricsSource.PrometheusURL = "http://prometheus:9090"
+		require.NoError(t, cfg.Validate())
+	})
 }
 
 func TestConfigValidateWatch(t *testing.T) {
@@ -86,12 +114,53 @@ func TestConfigValidateWatch(t *testing.T) {
 
 type noopSuccessProcessor struct{}
 
-func (noopSuccessProcessor) Success(PodMetricsResourceList) {}
+func (noopSuccessProcessor) Success(PodMetricsResourceList, partial.Warnings) {}
 
 type noopErrorProcessor struct{}
 
 func (noopErrorProcessor) Error(error) {}
 
+func TestConfigRefine(t *testing.T) {
+	pod := func(cluster, namespace, name, node string) PodMetricsResource {
+		return PodMetricsResource{
+			PodResource: pods.PodResource{
+				NamespaceName: pods.NamespaceName{Namespace: namespace, Name: name},
+				NodeName:      node,
+			},
+			Cluster: cluster,
+		}
+	}
+	names := func(list PodMetricsResourceList) []string {
+		result := make([]string, 0, len(list))
+		for _, pod := range list {
+			result = append(result, pod.Cluster+"/"+pod.PodResource.Namespace+"/"+pod.PodResource.Name)
+		}
+		return result
+	}
+
+	t.Run("filters namespaces and nodes and sorts", func(t *testing.T) {
+		list := PodMetricsResourceList{
+			pod("", "default", "web-2", "node-1"),
+			pod("", "kube-system", "dns", "node-1"),
+			pod("", "default", "web-1", "node-1"),
+			pod("", "default", "web-3", "node-2"),
+		}
+		cfg := Config{Namespaces: []string{"default"}, Nodes: []string{"node-1"}, Sorting: "name", Alert: "none"}
+		require.Equal(t, []string{"/default/web-1", "/default/web-2"}, names(cfg.Refine(list, time.Now())))
+	})
+
+	t.Run("sorts within clusters keeping their order", func(t *testing.T) {
+		list := PodMetricsResourceList{
+			pod("prod", "default", "b", "node-1"),
+			pod("prod", "default", "a", "node-1"),
+			pod("dev", "default", "d", "node-1"),
+			pod("dev", "default", "c", "node-1"),
+		}
+		cfg := Config{Sorting: "name", Alert: "none"}
+		require.Equal(t, []string{"prod/default/a", "prod/default/b", "dev/default/c", "dev/default/d"}, names(cfg.Refine(list, time.Now())))
+	})
+}
+
 func TestProcessValidationError(t *testing.T) {
 	cfg := Config{KubeConfig: "dummy", Sorting: "invalid", Alert: "none"}
 
@@ -4,6 +4,7 @@ import (
 	"cmp"
 	"slices"
 
+	"github.com/trezorg/k8spodsmetrics/internal/qos"
 	"github.com/trezorg/k8spodsmetrics/internal/sorting/metricsresources"
 	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
@@ -22,50 +23,34 @@ func direction(reversed bool, result int) int {
 	return result
 }
 
-func cpuRequest(containers []pods.ContainerResource) int64 {
-	var result int64
-	for _, c := range containers {
-		result += c.Requests.CPU
-	}
-	return result
+func cpuRequest(pod pods.PodResource) int64 {
+	return pod.EffectiveRequests().CPU
 }
 
-func cpuLimit(containers []pods.ContainerResource) int64 {
-	var result int64
-	for _, c := range containers {
-		result += c.Limits.CPU
-	}
-	return result
+func cpuLimit(pod pods.PodResource) int64 {
+	return pod.EffectiveLimits().CPU
 }
 
 func cpuUsed(containers []podmetrics.ContainerMetric) int64 {
 	var result int64
 	for _, c := range containers {
-		result += c.CPU
+		result += podmetrics.UsageOrZero(c.CPU)
 	}
 	return result
 }
 
-func memoryRequest(containers []pods.ContainerResource) int64 {
-	var result int64
-	for _, c := range containers {
-		result += c.Requests.Memory
-	}
-	return result
+func memoryRequest(pod pods.PodResource) int64 {
+	return pod.EffectiveRequests().Memory
 }
 
-func memoryLimit(containers []pods.ContainerResource) int64 {
-	var result int64
-	for _, c := range containers {
-		result += c.Limits.Memory
-	}
-	return result
+func memoryLimit(pod pods.PodResource) int64 {
+	return pod.EffectiveLimits().Memory
 }
 
 func memoryUsed(containers []podmetrics.ContainerMetric) int64 {
 	var result int64
 	for _, c := range containers {
-		result += c.Memory
+		result += podmetrics.UsageOrZero(c.Memory)
 	}
 	return result
 }
@@ -107,7 +92,17 @@ func (r PodMetricsResourceList) sortByNode(reversed bool) {
 	})
 }
 
-func (r PodMetricsResourceList) sortPodResource(reversed bool, f func([]pods.ContainerResource) int64) {
+func (r PodMetricsResourceList) sortByQOS(reversed bool) {
+	slices.SortStableFunc(r, func(a, b PodMetricsResource) int {
+		return direction(reversed, cmp.Or(
+			cmp.Compare(qos.EvictionRank(a.QOSClass), qos.EvictionRank(b.QOSClass)),
+			cmp.Compare(a.PodResource.Namespace, b.PodResource.Namespace),
+			cmp.Compare(a.PodResource.Name, b.PodResource.Name),
+		))
+	})
+}
+
+func (r PodMetricsResourceList) sortPodResource(reversed bool, f func(pods.PodResource) int64) {
 	type sortItem struct {
 		resource PodMetricsResource
 		key      int64
@@ -116,7 +111,7 @@ func (r PodMetricsResourceList) sortPodResource(reversed bool, f func([]pods.Con
 	for i := range r {
 		items[i] = sortItem{
 			resource: r[i],
-			key:      f(r[i].PodResource.Containers),
+			key:      f(r[i].PodResource),
 		}
 	}
 	slices.SortStableFunc(items, func(a, b sortItem) int {
@@ -147,6 +142,38 @@ func (r PodMetricsResourceList) sortPodMetric(reversed bool, f func([]podmetrics
 	}
 }
 
+// sortEfficiency orders pods by a value of their efficiency, pods without
+// usage first.
+func (r PodMetricsResourceList) sortEfficiency(reversed bool, f func(Efficiency) float64) {
+	type sortItem struct {
+		resource PodMetricsResource
+		key      float64
+	}
+	items := make([]sortItem, len(r))
+	for i := range r {
+		key := float64(unset)
+		if efficiency, ok := r[i].Efficiency(); ok {
+			key = f(efficiency)
+		}
+		items[i] = sortItem{resource: r[i], key: key}
+	}
+	slices.SortStableFunc(items, func(a, b sortItem) int {
+		return direction(reversed, cmp.Compare(a.key, b.key))
+	})
+	for i := range items {
+		r[i] = items[i].resource
+	}
+}
+
+// percentKey orders a percentage of an unset request or limit before any
+// other.
+func percentKey(value *float64) float64 {
+	if value == nil {
+		return float64(unset)
+	}
+	return *value
+}
+
 func (r PodMetricsResourceList) sortByRequestCPU(reversed bool) {
 	r.sortPodResource(reversed, cpuRequest)
 }
@@ -179,6 +206,39 @@ func (r PodMetricsResourceList) sortByUsedStorageEphemeral(reversed bool) {
 	r.sortPodMetric(reversed, storageEphemeralUsed)
 }
 
+func (r PodMetricsResourceList) sortByEfficiencyRequestCPU(reversed bool) {
+	r.sortEfficiency(reversed, func(e Efficiency) float64 { return percentKey(e.CPURequestPercent) })
+}
+
+func (r PodMetricsResourceList) sortByEfficiencyLimitCPU(reversed bool) {
+	r.sortEfficiency(reversed, func(e Efficiency) float64 { return percentKey(e.CPULimitPercent) })
+}
+
+func (r PodMetricsResourceList) sortByEfficiencyRequestMemory(reversed bool) {
+	r.sortEfficiency(reversed, func(e Efficiency) float64 { return percentKey(e.MemoryRequestPercent) })
+}
+
+func (r PodMetricsResourceList) sortByEfficiencyLimitMemory(reversed bool) {
+	r.sortEfficiency(reversed, func(e Efficiency) float64 { return percentKey(e.MemoryLimitPercent) })
+}
+
+func (r PodMetricsResourceList) sortByIdleCPU(reversed bool) {
+	r.sortEfficiency(reversed, func(e Efficiency) float64 { return float64(e.CPUIdle) })
+}
+
+func (r PodMetricsResourceList) sortByIdleMemory(reversed bool) {
+	r.sortEfficiency(reversed, func(e Efficiency) float64 { return float64(e.MemoryIdle) })
+}
+
+func (r PodMetricsResourceList) sortByExtended(reversed bool, field metricsresources.ExtendedField, name string) {
+	r.sortPodResource(reversed, func(pod pods.PodResource) int64 {
+		if field == metricsresources.ExtendedLimit {
+			return pod.EffectiveLimits().Extended[name]
+		}
+		return pod.EffectiveRequests().Extended[name]
+	})
+}
+
 func (r PodMetricsResourceList) sort(by string, reverse bool) {
 	switch metricsresources.Sorting(by) {
 	case metricsresources.Name:
@@ -187,6 +247,8 @@ func (r PodMetricsResourceList) sort(by string, reverse bool) {
 		r.sortByNamespace(reverse)
 	case metricsresources.Node:
 		r.sortByNode(reverse)
+	case metricsresources.QOS:
+		r.sortByQOS(reverse)
 	case metricsresources.LimitCPU:
 		r.sortByLimitCPU(reverse)
 	case metricsresources.RequestCPU:
@@ -203,7 +265,23 @@ func (r PodMetricsResourceList) sort(by string, reverse bool) {
 		r.sortByUsedStorage(reverse)
 	case metricsresources.UsedStorageEphemeral:
 		r.sortByUsedStorageEphemeral(reverse)
+	case metricsresources.EfficiencyRequestCPU:
+		r.sortByEfficiencyRequestCPU(reverse)
+	case metricsresources.EfficiencyLimitCPU:
+		r.sortByEfficiencyLimitCPU(reverse)
+	case metricsresources.EfficiencyRequestMemory:
+		r.sortByEfficiencyRequestMemory(reverse)
+	case metricsresources.EfficiencyLimitMemory:
+		r.sortByEfficiencyLimitMemory(reverse)
+	case metricsresources.IdleCPU:
+		r.sortByIdleCPU(reverse)
+	case metricsresources.IdleMemory:
+		r.sortByIdleMemory(reverse)
 	default:
+		if field, name, ok := metricsresources.Extended(metricsresources.Sorting(by)); ok {
+			r.sortByExtended(reverse, field, name)
+			return
+		}
 		// keep current order on unknown sorting
 		return
 	}
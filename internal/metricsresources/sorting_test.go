@@ -7,6 +7,7 @@ import (
 	"github.com/trezorg/k8spodsmetrics/internal/sorting/metricsresources"
 	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	v1 "k8s.io/api/core/v1"
 )
 
 func testPodMetricsResource(name, namespace string, containers []pods.ContainerResource, metrics []podmetrics.ContainerMetric) PodMetricsResource {
@@ -68,11 +69,15 @@ func TestCPURequest(t *testing.T) {
 			testContainerResource("c1", 100, 200, 1024, 2048),
 			testContainerResource("c2", 50, 100, 512, 1024),
 		}, 150},
+		{"init container exceeding application containers", []pods.ContainerResource{
+			testContainerResource("c1", 100, 200, 1024, 2048),
+			{Name: "init", Type: pods.InitContainer, Requests: pods.Resource{CPU: 400}},
+		}, 400},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := cpuRequest(tc.containers)
+			result := cpuRequest(pods.PodResource{Containers: tc.containers})
 			require.Equal(t, tc.expected, result)
 		})
 	}
@@ -94,7 +99,7 @@ func TestCPULimit(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := cpuLimit(tc.containers)
+			result := cpuLimit(pods.PodResource{Containers: tc.containers})
 			require.Equal(t, tc.expected, result)
 		})
 	}
@@ -138,7 +143,7 @@ func TestMemoryRequest(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := memoryRequest(tc.containers)
+			result := memoryRequest(pods.PodResource{Containers: tc.containers})
 			require.Equal(t, tc.expected, result)
 		})
 	}
@@ -160,7 +165,7 @@ func TestMemoryLimit(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := memoryLimit(tc.containers)
+			result := memoryLimit(pods.PodResource{Containers: tc.containers})
 			require.Equal(t, tc.expected, result)
 		})
 	}
@@ -252,6 +257,31 @@ func TestSortByNode(t *testing.T) {
 	require.Equal(t, "node-a", list[2].NodeName)
 }
 
+func testPodMetricsResourceWithQOS(name string, class v1.PodQOSClass) PodMetricsResource {
+	r := testPodMetricsResource(name, "ns1", nil, nil)
+	r.QOSClass = class
+	return r
+}
+
+func TestSortByQOS(t *testing.T) {
+	list := PodMetricsResourceList{
+		testPodMetricsResourceWithQOS("pod-g", v1.PodQOSGuaranteed),
+		testPodMetricsResourceWithQOS("pod-b2", v1.PodQOSBurstable),
+		testPodMetricsResourceWithQOS("pod-be", v1.PodQOSBestEffort),
+		testPodMetricsResourceWithQOS("pod-b1", v1.PodQOSBurstable),
+	}
+
+	list.sort(string(metricsresources.QOS), false)
+	require.Equal(t, "pod-be", list[0].Name)
+	require.Equal(t, "pod-b1", list[1].Name)
+	require.Equal(t, "pod-b2", list[2].Name)
+	require.Equal(t, "pod-g", list[3].Name)
+
+	list.sort(string(metricsresources.QOS), true)
+	require.Equal(t, "pod-g", list[0].Name)
+	require.Equal(t, "pod-be", list[3].Name)
+}
+
 func TestSortByNamespace(t *testing.T) {
 	list := PodMetricsResourceList{
 		testPodMetricsResource("pod-b", "ns-c", nil, nil),
@@ -651,3 +681,64 @@ func TestSortWithMultipleContainers(t *testing.T) {
 	require.Equal(t, "pod-2", list[0].Name)
 	require.Equal(t, "pod-1", list[1].Name)
 }
+
+func TestSortByExtended(t *testing.T) {
+	gpuContainer := func(request, limit int64) []pods.ContainerResource {
+		return []pods.ContainerResource{{
+			Name:     "cuda",
+			Requests: pods.Resource{Extended: map[string]int64{"nvidia.com/gpu": request}},
+			Limits:   pods.Resource{Extended: map[string]int64{"nvidia.com/gpu": limit}},
+		}}
+	}
+	list := PodMetricsResourceList{
+		testPodMetricsResource("pod-a", "ns1", gpuContainer(2, 2), nil),
+		testPodMetricsResource("pod-b", "ns1", nil, nil),
+		testPodMetricsResource("pod-c", "ns1", gpuContainer(1, 4), nil),
+	}
+
+	list.sort("request_nvidia.com/gpu", false)
+	require.Equal(t, "pod-b", list[0].Name)
+	require.Equal(t, "pod-c", list[1].Name)
+	require.Equal(t, "pod-a", list[2].Name)
+
+	list.sort("limit_nvidia.com/gpu", true)
+	require.Equal(t, "pod-c", list[0].Name)
+	require.Equal(t, "pod-a", list[1].Name)
+	require.Equal(t, "pod-b", list[2].Name)
+}
+
+func TestSortByEfficiency(t *testing.T) {
+	newList := func() PodMetricsResourceList {
+		return PodMetricsResourceList{
+			testPodMetricsResource("busy", "ns1",
+				[]pods.ContainerResource{testContainerResource("c1", 100, 200, 1000, 4000)},
+				[]podmetrics.ContainerMetric{testContainerMetric("c1", 90, 500)}),
+			testPodMetricsResource("no-metrics", "ns1",
+				[]pods.ContainerResource{testContainerResource("c1", 100, 200, 1000, 4000)}, nil),
+			testPodMetricsResource("idle", "ns1",
+				[]pods.ContainerResource{testContainerResource("c1", 400, 0, 1000, 2000)},
+				[]podmetrics.ContainerMetric{testContainerMetric("c1", 40, 900)}),
+		}
+	}
+	tests := []struct {
+		by       metricsresources.Sorting
+		expected []string
+	}{
+		{by: metricsresources.EfficiencyRequestCPU, expected: []string{"no-metrics", "idle", "busy"}},
+		{by: metricsresources.EfficiencyLimitCPU, expected: []string{"no-metrics", "idle", "busy"}},
+		{by: metricsresources.EfficiencyRequestMemory, expected: []string{"no-metrics", "busy", "idle"}},
+		{by: metricsresources.EfficiencyLimitMemory, expected: []string{"no-metrics", "busy", "idle"}},
+		{by: metricsresources.IdleCPU, expected: []string{"no-metrics", "busy", "idle"}},
+		{by: metricsresources.IdleMemory, expected: []string{"no-metrics", "idle", "busy"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.by), func(t *testing.T) {
+			list := newList()
+			list.sort(string(tt.by), false)
+			require.Equal(t, tt.expected, []string{list[0].Name, list[1].Name, list[2].Name})
+
+			list.sort(string(tt.by), true)
+			require.Equal(t, tt.expected[2], list[0].Name)
+		})
+	}
+}
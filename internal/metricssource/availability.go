@@ -0,0 +1,46 @@
+package metricssource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+// ErrUnavailable is wrapped by the errors of metrics-server requests when
+// discovery shows the metrics.k8s.io API is not registered or not served.
+// Callers render requests and limits without usage in that case.
+var ErrUnavailable = errors.New("metrics API is unavailable")
+
+// UnavailableWarning is shown above results rendered without usage.
+const UnavailableWarning = "WARNING: the metrics API (metrics.k8s.io) is unavailable, usage is not shown"
+
+// unavailableError wraps err with ErrUnavailable when a metrics request failed
+// as not found or service unavailable and discovery of the metrics API fails
+// the same way. A single missing node or pod is still reported as not found.
+func unavailableError(ctx context.Context, metricsClient metricsv1beta1.MetricsV1beta1Interface, err error) error {
+	if err == nil || !apiMissing(err) || metricsClient == nil {
+		return err
+	}
+	// Fake clients have no REST client to probe discovery with.
+	restClient, ok := metricsClient.RESTClient().(*rest.RESTClient)
+	if !ok || restClient == nil {
+		return err
+	}
+	discoveryErr := restClient.Get().
+		AbsPath("/apis", v1beta1.SchemeGroupVersion.Group, v1beta1.SchemeGroupVersion.Version).
+		Do(ctx).
+		Error()
+	if discoveryErr == nil || !apiMissing(discoveryErr) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrUnavailable, err)
+}
+
+func apiMissing(err error) bool {
+	return apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err)
+}
@@ -0,0 +1,74 @@
+package metricssource
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/nodemetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/metrics/pkg/client/clientset/versioned/fake"
+	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+const discoveryPath = "/apis/metrics.k8s.io/v1beta1"
+
+// metricsServer answers the metrics API with the given status, discovery
+// included unless discovery is set.
+func metricsServer(t *testing.T, status, discovery int) metricsv1beta1.MetricsV1beta1Interface {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == discoveryPath && discovery != 0 {
+			w.WriteHeader(discovery)
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	client, err := metricsv1beta1.NewForConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+	return client
+}
+
+func TestMetricsServerReaderUnavailable(t *testing.T) {
+	reader := metricsServerReader{}
+
+	t.Run("service unavailable", func(t *testing.T) {
+		client := metricsServer(t, http.StatusServiceUnavailable, 0)
+		_, err := reader.PodMetrics(t.Context(), client, nil, podmetrics.MetricFilter{})
+		require.ErrorIs(t, err, ErrUnavailable)
+		require.True(t, apierrors.IsServiceUnavailable(err))
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		client := metricsServer(t, http.StatusNotFound, 0)
+		_, err := reader.NodeMetrics(t.Context(), client, nil, nodemetrics.MetricsFilter{}, "")
+		require.ErrorIs(t, err, ErrUnavailable)
+	})
+
+	t.Run("missing node of a served API", func(t *testing.T) {
+		client := metricsServer(t, http.StatusNotFound, http.StatusOK)
+		_, err := reader.NodeMetrics(t.Context(), client, nil, nodemetrics.MetricsFilter{}, "node-a")
+		require.True(t, apierrors.IsNotFound(err))
+		require.NotErrorIs(t, err, ErrUnavailable)
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		client := metricsServer(t, http.StatusForbidden, 0)
+		_, err := reader.PodMetrics(t.Context(), client, nil, podmetrics.MetricFilter{})
+		require.True(t, apierrors.IsForbidden(err))
+		require.NotErrorIs(t, err, ErrUnavailable)
+	})
+}
+
+func TestUnavailableErrorWithoutDiscovery(t *testing.T) {
+	err := apierrors.NewServiceUnavailable("unavailable")
+	require.Equal(t, err, unavailableError(t.Context(), fake.NewSimpleClientset().MetricsV1beta1(), err))
+	require.Equal(t, err, unavailableError(t.Context(), nil, err))
+	require.NoError(t, unavailableError(t.Context(), nil, nil))
+	require.False(t, errors.Is(unavailableError(t.Context(), nil, err), ErrUnavailable))
+}
@@ -0,0 +1,37 @@
+package metricssource
+
+import (
+	"fmt"
+
+	choiceutil "github.com/trezorg/k8spodsmetrics/internal/choices"
+)
+
+// Source selects where usage metrics are read from.
+type Source string
+
+const (
+	// MetricsServer reads the metrics.k8s.io API served by metrics-server.
+	MetricsServer Source = "metrics-server"
+	// Kubelet reads the kubelet Summary API of every node through the
+	// apiserver node proxy. It also reports filesystem and volume usage.
+	Kubelet Source = "kubelet"
+	// Prometheus queries cAdvisor series from a Prometheus server.
+	Prometheus Source = "prometheus"
+)
+
+var choices = []Source{MetricsServer, Kubelet, Prometheus}
+
+func Valid(s Source) error {
+	if !choiceutil.Valid(s, choices) {
+		return fmt.Errorf("metrics source should be one of: %#v", choices)
+	}
+	return nil
+}
+
+func StringList(separator string) string {
+	return choiceutil.StringList(choices, separator)
+}
+
+func StringListDefault() string {
+	return StringList(choiceutil.DefaultSeparator)
+}
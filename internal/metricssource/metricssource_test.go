@@ -0,0 +1,29 @@
+package metricssource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValid(t *testing.T) {
+	t.Run("valid metrics sources", func(t *testing.T) {
+		for _, source := range []Source{MetricsServer, Kubelet, Prometheus} {
+			require.NoError(t, Valid(source))
+		}
+	})
+
+	t.Run("invalid metrics source", func(t *testing.T) {
+		err := Valid(Source("invalid"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "metrics source should be one of")
+	})
+}
+
+func TestStringListDefault(t *testing.T) {
+	list := StringListDefault()
+	require.Contains(t, list, string(MetricsServer))
+	require.Contains(t, list, string(Kubelet))
+	require.Contains(t, list, string(Prometheus))
+	require.Contains(t, list, "|")
+}
@@ -0,0 +1,158 @@
+package metricssource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/kubeletstats"
+	"github.com/trezorg/k8spodsmetrics/pkg/nodemetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/prometheus"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+// Config selects and configures the usage metrics source.
+type Config struct {
+	Source Source
+	// PrometheusURL and PrometheusQueries are used by the Prometheus source.
+	PrometheusURL     string
+	PrometheusQueries prometheus.Queries
+}
+
+func (c Config) Validate() error {
+	if c.Source == "" {
+		return nil
+	}
+	if err := Valid(c.Source); err != nil {
+		return err
+	}
+	if c.Source != Prometheus {
+		return nil
+	}
+	if c.PrometheusURL == "" {
+		return errors.New("prometheus url is required for the prometheus metrics source")
+	}
+	return c.PrometheusQueries.Validate()
+}
+
+// Reader reads pod and node usage. Both clients are passed on every call as
+// they are created per request; a source uses the one it needs.
+type Reader interface {
+	PodMetrics(
+		ctx context.Context,
+		metricsClient metricsv1beta1.MetricsV1beta1Interface,
+		coreClient corev1.CoreV1Interface,
+		filter podmetrics.MetricFilter,
+	) (podmetrics.PodMetricList, error)
+	NodeMetrics(
+		ctx context.Context,
+		metricsClient metricsv1beta1.MetricsV1beta1Interface,
+		coreClient corev1.CoreV1Interface,
+		filter nodemetrics.MetricsFilter,
+		name string,
+	) (nodemetrics.List, error)
+}
+
+// WithSharedReads returns a context in which sources reading the whole
+// cluster or whole nodes whatever the namespaces asked for, i.e. Prometheus
+// and the kubelet, read them once for all the per-namespace reads of a
+// request.
+func WithSharedReads(ctx context.Context) context.Context {
+	return prometheus.WithSharedQueries(kubeletstats.WithSharedSummaries(ctx))
+}
+
+// New returns the reader of c.Source, metrics-server when it is empty.
+func New(c Config) Reader {
+	switch c.Source {
+	case Kubelet:
+		return kubeletReader{}
+	case Prometheus:
+		return prometheusReader{client: prometheus.NewClient(c.PrometheusURL), queries: c.PrometheusQueries}
+	case MetricsServer:
+		return metricsServerReader{}
+	default:
+		return metricsServerReader{}
+	}
+}
+
+type metricsServerReader struct{}
+
+func (metricsServerReader) PodMetrics(
+	ctx context.Context,
+	metricsClient metricsv1beta1.MetricsV1beta1Interface,
+	_ corev1.CoreV1Interface,
+	filter podmetrics.MetricFilter,
+) (podmetrics.PodMetricList, error) {
+	metrics, err := podmetrics.Metrics(ctx, metricsClient, filter)
+	return metrics, unavailableError(ctx, metricsClient, err)
+}
+
+func (metricsServerReader) NodeMetrics(
+	ctx context.Context,
+	metricsClient metricsv1beta1.MetricsV1beta1Interface,
+	_ corev1.CoreV1Interface,
+	filter nodemetrics.MetricsFilter,
+	name string,
+) (nodemetrics.List, error) {
+	metrics, err := nodemetrics.Metrics(ctx, metricsClient, filter, name)
+	return metrics, unavailableError(ctx, metricsClient, err)
+}
+
+type kubeletReader struct{}
+
+func (kubeletReader) PodMetrics(
+	ctx context.Context,
+	_ metricsv1beta1.MetricsV1beta1Interface,
+	coreClient corev1.CoreV1Interface,
+	filter podmetrics.MetricFilter,
+) (podmetrics.PodMetricList, error) {
+	return kubeletstats.PodMetrics(ctx, coreClient, filter)
+}
+
+func (kubeletReader) NodeMetrics(
+	ctx context.Context,
+	_ metricsv1beta1.MetricsV1beta1Interface,
+	coreClient corev1.CoreV1Interface,
+	filter nodemetrics.MetricsFilter,
+	name string,
+) (nodemetrics.List, error) {
+	return kubeletstats.NodeMetrics(ctx, coreClient, filter, name)
+}
+
+type prometheusReader struct {
+	client  prometheus.Client
+	queries prometheus.Queries
+}
+
+func (r prometheusReader) PodMetrics(
+	ctx context.Context,
+	_ metricsv1beta1.MetricsV1beta1Interface,
+	_ corev1.CoreV1Interface,
+	filter podmetrics.MetricFilter,
+) (podmetrics.PodMetricList, error) {
+	return r.client.PodMetrics(ctx, r.queries, filter)
+}
+
+// NodeMetrics resolves a label selector to node names through the core client
+// as Prometheus series do not carry node labels.
+func (r prometheusReader) NodeMetrics(
+	ctx context.Context,
+	_ metricsv1beta1.MetricsV1beta1Interface,
+	coreClient corev1.CoreV1Interface,
+	filter nodemetrics.MetricsFilter,
+	name string,
+) (nodemetrics.List, error) {
+	if name != "" || filter.LabelSelector == "" {
+		return r.client.NodeMetrics(ctx, r.queries, name)
+	}
+	names, err := kubeletstats.ListNodeNames(ctx, coreClient, filter.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("list nodes: %w", err)
+	}
+	if len(names) == 0 {
+		return nodemetrics.List{}, nil
+	}
+	return r.client.NodeMetrics(ctx, r.queries, names...)
+}
@@ -0,0 +1,51 @@
+package metricssource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/prometheus"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{name: "empty source", config: Config{}},
+		{name: "kubelet", config: Config{Source: Kubelet}},
+		{name: "invalid source", config: Config{Source: "invalid"}, wantErr: "metrics source should be one of"},
+		{name: "prometheus without url", config: Config{Source: Prometheus}, wantErr: "prometheus url is required"},
+		{name: "prometheus", config: Config{Source: Prometheus, PrometheusURL: "http://prometheus:9090"}},
+		{
+			name: "prometheus invalid window",
+			config: Config{
+				Source:            Prometheus,
+				PrometheusURL:     "http://prometheus:9090",
+				PrometheusQueries: prometheus.Queries{Window: "5 minutes"},
+			},
+			wantErr: "prometheus rate window",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	require.IsType(t, metricsServerReader{}, New(Config{}))
+	require.IsType(t, metricsServerReader{}, New(Config{Source: MetricsServer}))
+	require.IsType(t, kubeletReader{}, New(Config{Source: Kubelet}))
+
+	reader := New(Config{Source: Prometheus, PrometheusURL: "http://prometheus:9090"})
+	require.IsType(t, prometheusReader{}, reader)
+	require.Equal(t, "http://prometheus:9090", reader.(prometheusReader).client.URL)
+}
@@ -0,0 +1,49 @@
+package namespaces
+
+import (
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+)
+
+// aggregate sums non-terminated pods per namespace and attaches namespace
+// quotas. Namespaces having a quota but no pods are listed with zero totals.
+func aggregate(list metricsresources.PodMetricsResourceList, namespaceQuotas quotas.NamespaceQuotas) NamespaceResourceList {
+	var result NamespaceResourceList
+	index := map[string]int{}
+	namespace := func(name string) *NamespaceResource {
+		idx, ok := index[name]
+		if !ok {
+			idx = len(result)
+			index[name] = idx
+			result = append(result, NamespaceResource{Name: name, Quota: namespaceQuotas[name]})
+		}
+		return &result[idx]
+	}
+
+	for _, pod := range list {
+		if pod.IsTerminated() {
+			continue
+		}
+		resource := namespace(pod.PodResource.Namespace)
+		requests := pod.EffectiveRequests()
+		limits := pod.EffectiveLimits()
+		resource.Pods++
+		resource.CPURequest += requests.CPU
+		resource.CPULimit += limits.CPU
+		resource.MemoryRequest += requests.Memory
+		resource.MemoryLimit += limits.Memory
+		if len(pod.PodMetric.Containers) == 0 {
+			resource.MetricsMissing++
+			continue
+		}
+		for _, container := range pod.PodMetric.Containers {
+			resource.CPUUsed += podmetrics.UsageOrZero(container.CPU)
+			resource.MemoryUsed += podmetrics.UsageOrZero(container.Memory)
+		}
+	}
+	for name := range namespaceQuotas {
+		namespace(name)
+	}
+	return result
+}
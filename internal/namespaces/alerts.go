@@ -0,0 +1,68 @@
+package namespaces
+
+import (
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+)
+
+// quotaPercentAlert is the quota utilisation a namespace is alerted at.
+const quotaPercentAlert = 90
+
+func isQuotaAlerted(quota *quotas.Quota) bool {
+	return quota != nil && quota.Percent >= quotaPercentAlert
+}
+
+// usageQuota returns the hard limit usage is compared with: the requests
+// quota, or the limits quota when requests are not constrained.
+func usageQuota(requests, limits *quotas.Quota) (int64, bool) {
+	if requests != nil {
+		return requests.Hard, true
+	}
+	if limits != nil {
+		return limits.Hard, true
+	}
+	return 0, false
+}
+
+func (n NamespaceResource) IsCPURequestAlerted() bool {
+	return isQuotaAlerted(n.Quota.RequestsCPU)
+}
+
+func (n NamespaceResource) IsCPULimitAlerted() bool {
+	return isQuotaAlerted(n.Quota.LimitsCPU)
+}
+
+// IsCPUUsedAlerted reports whether CPU usage approaches the namespace quota.
+func (n NamespaceResource) IsCPUUsedAlerted() bool {
+	hard, ok := usageQuota(n.Quota.RequestsCPU, n.Quota.LimitsCPU)
+	return ok && quotas.Percent(n.CPUUsed, hard) >= quotaPercentAlert
+}
+
+func (n NamespaceResource) IsCPUAlerted() bool {
+	return n.IsCPURequestAlerted() || n.IsCPULimitAlerted() || n.IsCPUUsedAlerted()
+}
+
+func (n NamespaceResource) IsMemoryRequestAlerted() bool {
+	return isQuotaAlerted(n.Quota.RequestsMemory)
+}
+
+func (n NamespaceResource) IsMemoryLimitAlerted() bool {
+	return isQuotaAlerted(n.Quota.LimitsMemory)
+}
+
+// IsMemoryUsedAlerted reports whether memory usage approaches the namespace quota.
+func (n NamespaceResource) IsMemoryUsedAlerted() bool {
+	hard, ok := usageQuota(n.Quota.RequestsMemory, n.Quota.LimitsMemory)
+	return ok && quotas.Percent(n.MemoryUsed, hard) >= quotaPercentAlert
+}
+
+func (n NamespaceResource) IsMemoryAlerted() bool {
+	return n.IsMemoryRequestAlerted() || n.IsMemoryLimitAlerted() || n.IsMemoryUsedAlerted()
+}
+
+func (n NamespaceResource) IsPodsAlerted() bool {
+	return isQuotaAlerted(n.Quota.Pods)
+}
+
+func (n NamespaceResource) IsAlerted() bool {
+	return n.IsCPUAlerted() || n.IsMemoryAlerted() || n.IsPodsAlerted()
+}
@@ -0,0 +1,39 @@
+package namespaces
+
+import (
+	alerts "github.com/trezorg/k8spodsmetrics/internal/alert"
+)
+
+func (l NamespaceResourceList) filterBy(predicate func(NamespaceResource) bool) NamespaceResourceList {
+	var result NamespaceResourceList
+	for _, resource := range l {
+		if predicate(resource) {
+			result = append(result, resource)
+		}
+	}
+	return result
+}
+
+func (l NamespaceResourceList) filterByAlert(alert alerts.Alert) NamespaceResourceList {
+	//nolint:exhaustive // storage and extended resources have no namespace quota
+	switch alert {
+	case alerts.Any:
+		return l.filterBy(NamespaceResource.IsAlerted)
+	case alerts.CPU:
+		return l.filterBy(NamespaceResource.IsCPUAlerted)
+	case alerts.CPURequest:
+		return l.filterBy(NamespaceResource.IsCPURequestAlerted)
+	case alerts.CPULimit:
+		return l.filterBy(NamespaceResource.IsCPULimitAlerted)
+	case alerts.Memory:
+		return l.filterBy(NamespaceResource.IsMemoryAlerted)
+	case alerts.MemoryRequest:
+		return l.filterBy(NamespaceResource.IsMemoryRequestAlerted)
+	case alerts.MemoryLimit:
+		return l.filterBy(NamespaceResource.IsMemoryLimitAlerted)
+	case alerts.Pods:
+		return l.filterBy(NamespaceResource.IsPodsAlerted)
+	default:
+		return l
+	}
+}
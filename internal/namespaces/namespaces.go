@@ -0,0 +1,29 @@
+package namespaces
+
+import (
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+)
+
+type (
+	NamespaceResource struct {
+		Name string `json:"name" yaml:"name"`
+		// Pods counts non-terminated pods. Terminated pods are left out as
+		// they are by ResourceQuota.
+		Pods          int64 `json:"pods" yaml:"pods"`
+		CPURequest    int64 `json:"cpu_request" yaml:"cpu_request"`
+		CPULimit      int64 `json:"cpu_limit" yaml:"cpu_limit"`
+		CPUUsed       int64 `json:"cpu_used" yaml:"cpu_used"`
+		MemoryRequest int64 `json:"memory_request" yaml:"memory_request"`
+		MemoryLimit   int64 `json:"memory_limit" yaml:"memory_limit"`
+		MemoryUsed    int64 `json:"memory_used" yaml:"memory_used"`
+		// MetricsMissing counts pods metrics-server reported nothing for.
+		MetricsMissing int64                 `json:"metrics_missing,omitempty" yaml:"metrics_missing,omitempty"`
+		Quota          quotas.NamespaceQuota `json:"quota" yaml:"quota"`
+	}
+
+	NamespaceResourceList []NamespaceResource
+
+	NamespaceResourceListEnvelope struct {
+		Items NamespaceResourceList `json:"items,omitempty" yaml:"items,omitempty"`
+	}
+)
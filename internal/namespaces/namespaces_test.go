@@ -0,0 +1,164 @@
+package namespaces
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	alerts "github.com/trezorg/k8spodsmetrics/internal/alert"
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+	v1 "k8s.io/api/core/v1"
+)
+
+func quota(hard, used int64) *quotas.Quota {
+	result := quotas.NewQuota(hard, used)
+	return &result
+}
+
+func testPod(namespace, name string, cpuRequest, cpuUsed int64) metricsresources.PodMetricsResource {
+	resource := metricsresources.PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Namespace: namespace, Name: name},
+			Containers: []pods.ContainerResource{{
+				Name:     "app",
+				Requests: pods.Resource{CPU: cpuRequest, Memory: 100},
+				Limits:   pods.Resource{CPU: cpuRequest * 2, Memory: 200},
+			}},
+		},
+	}
+	if cpuUsed >= 0 {
+		resource.PodMetric = podmetrics.PodMetric{
+			Namespace: namespace,
+			Name:      name,
+			Containers: []podmetrics.ContainerMetric{
+				{Name: "app", Metric: podmetrics.Metric{CPU: cpuUsed, Memory: 50}},
+			},
+		}
+	}
+	return resource
+}
+
+func TestAggregate(t *testing.T) {
+	finished := testPod("team-a", "job", 1000, -1)
+	finished.Phase = v1.PodSucceeded
+	list := metricsresources.PodMetricsResourceList{
+		testPod("team-a", "web-1", 100, 40),
+		testPod("team-a", "web-2", 200, -1),
+		testPod("team-b", "db-0", 500, 300),
+		finished,
+	}
+	namespaceQuotas := quotas.NamespaceQuotas{
+		"team-a": {RequestsCPU: quota(1000, 300)},
+		"team-c": {Pods: quota(10, 0)},
+	}
+
+	result := aggregate(list, namespaceQuotas)
+	require.Equal(t, NamespaceResourceList{
+		{
+			Name:           "team-a",
+			Pods:           2,
+			CPURequest:     300,
+			CPULimit:       600,
+			CPUUsed:        40,
+			MemoryRequest:  200,
+			MemoryLimit:    400,
+			MemoryUsed:     50,
+			MetricsMissing: 1,
+			Quota:          quotas.NamespaceQuota{RequestsCPU: quota(1000, 300)},
+		},
+		{
+			Name:          "team-b",
+			Pods:          1,
+			CPURequest:    500,
+			CPULimit:      1000,
+			CPUUsed:       300,
+			MemoryRequest: 100,
+			MemoryLimit:   200,
+			MemoryUsed:    50,
+		},
+		{
+			Name:  "team-c",
+			Quota: quotas.NamespaceQuota{Pods: quota(10, 0)},
+		},
+	}, result)
+}
+
+func TestAlerts(t *testing.T) {
+	resource := NamespaceResource{
+		CPUUsed:    950,
+		MemoryUsed: 10,
+		Quota: quotas.NamespaceQuota{
+			RequestsCPU:  quota(1000, 500),
+			LimitsMemory: quota(100, 95),
+			Pods:         quota(10, 9),
+		},
+	}
+
+	require.False(t, resource.IsCPURequestAlerted())
+	require.True(t, resource.IsCPUUsedAlerted())
+	require.True(t, resource.IsCPUAlerted())
+	require.True(t, resource.IsMemoryLimitAlerted())
+	require.False(t, resource.IsMemoryUsedAlerted())
+	require.True(t, resource.IsMemoryAlerted())
+	require.True(t, resource.IsPodsAlerted())
+	require.False(t, NamespaceResource{CPUUsed: 1000}.IsAlerted(), "no quota, no alert")
+}
+
+func TestFilterByAlert(t *testing.T) {
+	list := NamespaceResourceList{
+		{Name: "busy", Quota: quotas.NamespaceQuota{RequestsCPU: quota(1000, 950)}},
+		{Name: "full", Quota: quotas.NamespaceQuota{Pods: quota(10, 10)}},
+		{Name: "free"},
+	}
+
+	names := func(l NamespaceResourceList) []string {
+		result := make([]string, 0, len(l))
+		for _, n := range l {
+			result = append(result, n.Name)
+		}
+		return result
+	}
+
+	require.Equal(t, []string{"busy", "full", "free"}, names(list.filterByAlert(alerts.None)))
+	require.Equal(t, []string{"busy", "full"}, names(list.filterByAlert(alerts.Any)))
+	require.Equal(t, []string{"busy"}, names(list.filterByAlert(alerts.CPURequest)))
+	require.Empty(t, list.filterByAlert(alerts.CPULimit))
+	require.Equal(t, []string{"full"}, names(list.filterByAlert(alerts.Pods)))
+	require.Len(t, list.filterByAlert(alerts.Storage), 3)
+}
+
+func TestSort(t *testing.T) {
+	list := NamespaceResourceList{
+		{Name: "b", Pods: 3, CPUUsed: 10, Quota: quotas.NamespaceQuota{RequestsCPU: quota(100, 50)}},
+		{Name: "c", Pods: 1, CPUUsed: 30},
+		{Name: "a", Pods: 2, CPUUsed: 20, Quota: quotas.NamespaceQuota{RequestsCPU: quota(100, 90)}},
+	}
+	names := func(l NamespaceResourceList) []string {
+		result := make([]string, 0, len(l))
+		for _, n := range l {
+			result = append(result, n.Name)
+		}
+		return result
+	}
+
+	tests := []struct {
+		sorting  string
+		reverse  bool
+		expected []string
+	}{
+		{sorting: "name", expected: []string{"a", "b", "c"}},
+		{sorting: "pods", expected: []string{"c", "a", "b"}},
+		{sorting: "used_cpu", reverse: true, expected: []string{"c", "a", "b"}},
+		{sorting: "quota_request_cpu", expected: []string{"c", "b", "a"}},
+		{sorting: "quota_request_cpu", reverse: true, expected: []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sorting, func(t *testing.T) {
+			sorted := append(NamespaceResourceList(nil), list...)
+			sorted.sort(tt.sorting, tt.reverse)
+			require.Equal(t, tt.expected, names(sorted))
+		})
+	}
+}
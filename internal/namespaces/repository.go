@@ -0,0 +1,89 @@
+package namespaces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+type NamespaceRepository interface {
+	metricsresources.PodRepository
+	FetchQuotas(ctx context.Context, coreClient corev1.CoreV1Interface, namespace string) (quotas.NamespaceQuotas, error)
+}
+
+type namespaceRepository struct {
+	metricsresources.PodRepository
+}
+
+func NewNamespaceRepository() NamespaceRepository {
+	return NewNamespaceRepositoryWithSource(metricssource.Config{})
+}
+
+// NewNamespaceRepositoryWithSource returns a repository reading usage metrics
+// from the configured source, metrics-server when none is set.
+func NewNamespaceRepositoryWithSource(source metricssource.Config) NamespaceRepository {
+	return &namespaceRepository{PodRepository: metricsresources.NewPodRepositoryWithSource(source)}
+}
+
+// NewCachedNamespaceRepositoryWithSource is NewNamespaceRepositoryWithSource
+// keeping pods in informer caches until ctx is done.
+func NewCachedNamespaceRepositoryWithSource(ctx context.Context, source metricssource.Config) NamespaceRepository {
+	return &namespaceRepository{PodRepository: metricsresources.NewCachedPodRepositoryWithSource(ctx, source)}
+}
+
+func (namespaceRepository) FetchQuotas(
+	ctx context.Context,
+	coreClient corev1.CoreV1Interface,
+	namespace string,
+) (quotas.NamespaceQuotas, error) {
+	return quotas.Quotas(ctx, coreClient, namespace)
+}
+
+func fetchQuotas(
+	ctx context.Context,
+	repo NamespaceRepository,
+	coreClient corev1.CoreV1Interface,
+	namespaces []string,
+) (quotas.NamespaceQuotas, error) {
+	namespaces = slices.DeleteFunc(slices.Clone(namespaces), func(n string) bool { return n == "" })
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	results := make([]quotas.NamespaceQuotas, len(namespaces))
+	errs := make([]error, len(namespaces))
+	var wg sync.WaitGroup
+	for idx, namespace := range namespaces {
+		wg.Go(func() {
+			namespaceQuotas, err := repo.FetchQuotas(ctx, coreClient, namespace)
+			if err != nil {
+				if namespace == "" {
+					errs[idx] = fmt.Errorf("fetch resource quotas across all namespaces: %w", err)
+				} else {
+					errs[idx] = fmt.Errorf("fetch resource quotas for namespace %q: %w", namespace, err)
+				}
+				return
+			}
+			results[idx] = namespaceQuotas
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	result := quotas.NamespaceQuotas{}
+	for _, namespaceQuotas := range results {
+		for name, quota := range namespaceQuotas {
+			result[name] = quota
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,172 @@
+package namespaces
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/trezorg/k8spodsmetrics/internal/alert"
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
+	"github.com/trezorg/k8spodsmetrics/internal/serviceorchestration"
+	sorting "github.com/trezorg/k8spodsmetrics/internal/sorting/namespaces"
+	"github.com/trezorg/k8spodsmetrics/pkg/client"
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+type Config struct {
+	KubeConfig    string
+	KubeContext   string
+	Overrides     client.Overrides
+	Namespaces    []string
+	Label         string
+	FieldSelector string
+	Sorting       string
+	Alert         string
+	MetricsSource metricssource.Config
+	WatchPeriod   uint
+	Timeout       uint
+	Retry         retry.Policy
+	Reverse       bool
+}
+
+type WatchResponse = serviceorchestration.WatchResponse[NamespaceResourceList]
+
+func (c Config) Validate() error {
+	if err := alert.Valid(alert.Alert(c.Alert)); err != nil {
+		return err
+	}
+	if err := c.MetricsSource.Validate(); err != nil {
+		return err
+	}
+	return sorting.Valid(sorting.Sorting(c.Sorting))
+}
+
+func (c Config) ValidateWatch() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.WatchPeriod == 0 {
+		return errors.New("watch period must be greater than 0")
+	}
+	return nil
+}
+
+func (c Config) apiRequest(
+	ctx context.Context,
+	repo NamespaceRepository,
+	metricsClient metricsv1beta1.MetricsV1beta1Interface,
+	coreClient corev1.CoreV1Interface,
+) (NamespaceResourceList, error) {
+	ctx = retry.WithPolicy(ctx, c.Retry)
+	fetchConfig := metricsresources.FetchConfig{
+		// FetchPodMetrics compacts namespaces in place while quotas are fetched.
+		Namespaces:    slices.Clone(c.Namespaces),
+		Label:         c.Label,
+		FieldSelector: c.FieldSelector,
+	}
+	var (
+		podMetricsResourceList metricsresources.PodMetricsResourceList
+		namespaceQuotas        quotas.NamespaceQuotas
+		podsErr, quotasErr     error
+		wg                     sync.WaitGroup
+	)
+	wg.Go(func() {
+		podMetricsResourceList, podsErr = metricsresources.FetchPodMetrics(ctx, repo, metricsClient, coreClient, fetchConfig)
+	})
+	wg.Go(func() {
+		namespaceQuotas, quotasErr = fetchQuotas(ctx, repo, coreClient, c.Namespaces)
+	})
+	wg.Wait()
+	if err := errors.Join(podsErr, quotasErr); err != nil {
+		return nil, err
+	}
+	namespaceResourceList := aggregate(podMetricsResourceList, namespaceQuotas)
+	namespaceResourceList = namespaceResourceList.filterByAlert(alert.Alert(c.Alert))
+	namespaceResourceList.sort(c.Sorting, c.Reverse)
+	return namespaceResourceList, nil
+}
+
+func (c *Config) newRepository() NamespaceRepository {
+	return NewNamespaceRepositoryWithSource(c.MetricsSource)
+}
+
+func (c *Config) newWatchRepository(ctx context.Context) NamespaceRepository {
+	return NewCachedNamespaceRepositoryWithSource(ctx, c.MetricsSource)
+}
+
+func (c *Config) Request(ctx context.Context) (NamespaceResourceList, error) {
+	return serviceorchestration.RequestWithRepo(
+		ctx,
+		c.KubeConfig,
+		c.KubeContext,
+		c.Timeout,
+		c.Overrides.Clients,
+		c.newRepository,
+		c.apiRequest,
+	)
+}
+
+func (c *Config) Watch(ctx context.Context) <-chan WatchResponse {
+	return serviceorchestration.WatchWithRepoContext(
+		ctx,
+		c.KubeConfig,
+		c.KubeContext,
+		c.WatchPeriod,
+		c.Timeout,
+		c.Overrides.Clients,
+		c.newWatchRepository,
+		c.apiRequest,
+	)
+}
+
+func (c *Config) prepare() error {
+	if c.KubeConfig == "" {
+		var err error
+		c.KubeConfig, err = client.FindKubeConfig()
+		if err != nil {
+			return err
+		}
+	}
+	// Several contexts are only fanned out by pods and summary.
+	kubeContext, err := client.SingleContext(c.KubeConfig, c.KubeContext)
+	if err != nil {
+		return err
+	}
+	c.KubeContext = kubeContext
+	return nil
+}
+
+func (c *Config) prepareRequest() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	return c.prepare()
+}
+
+func (c *Config) prepareWatch() error {
+	if err := c.ValidateWatch(); err != nil {
+		return err
+	}
+	return c.prepare()
+}
+
+func (c *Config) Process(successProcessor SuccessProcessor) error {
+	return serviceorchestration.ProcessRequest(c.prepareRequest, c.Request, successProcessor.Success)
+}
+
+func (c *Config) ProcessWatch(successProcessor SuccessProcessor, errorProcessor ErrorProcessor) error {
+	return serviceorchestration.ProcessWatch(c.prepareWatch, c.Watch, successProcessor.Success, errorProcessor.Error)
+}
+
+type SuccessProcessor interface {
+	Success(NamespaceResourceList)
+}
+
+type ErrorProcessor interface {
+	Error(error)
+}
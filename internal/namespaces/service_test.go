@@ -0,0 +1,111 @@
+package namespaces
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+type stubNamespaceRepository struct {
+	pods        pods.PodResourceList
+	fetchQuotas func(namespace string) (quotas.NamespaceQuotas, error)
+}
+
+func (s stubNamespaceRepository) FetchPods(
+	_ context.Context,
+	_ corev1.CoreV1Interface,
+	filter pods.PodFilter,
+	_ ...string,
+) (pods.PodResourceList, error) {
+	if len(filter.Namespaces) == 0 {
+		return s.pods, nil
+	}
+	var result pods.PodResourceList
+	for _, pod := range s.pods {
+		if slices.Contains(filter.Namespaces, pod.Namespace) {
+			result = append(result, pod)
+		}
+	}
+	return result, nil
+}
+
+func (stubNamespaceRepository) FetchMetrics(
+	context.Context,
+	metricsv1beta1.MetricsV1beta1Interface,
+	corev1.CoreV1Interface,
+	podmetrics.MetricFilter,
+) (podmetrics.PodMetricList, error) {
+	return nil, nil
+}
+
+func (s stubNamespaceRepository) FetchQuotas(
+	_ context.Context,
+	_ corev1.CoreV1Interface,
+	namespace string,
+) (quotas.NamespaceQuotas, error) {
+	if s.fetchQuotas != nil {
+		return s.fetchQuotas(namespace)
+	}
+	return quotas.NamespaceQuotas{}, nil
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, Config{Sorting: "name", Alert: "none"}.Validate())
+	require.ErrorContains(t, Config{Sorting: "namespace", Alert: "none"}.Validate(), "sorting should be one of")
+	require.ErrorContains(t, Config{Sorting: "name", Alert: "none"}.ValidateWatch(), "watch period")
+	require.NoError(t, Config{Sorting: "name", Alert: "none", WatchPeriod: 5}.ValidateWatch())
+}
+
+func TestAPIRequest(t *testing.T) {
+	podList := pods.PodResourceList{
+		{NamespaceName: pods.NamespaceName{Namespace: "team-a", Name: "web"}},
+		{NamespaceName: pods.NamespaceName{Namespace: "team-b", Name: "db"}},
+	}
+
+	t.Run("fetches quotas per namespace", func(t *testing.T) {
+		requested := make(chan string, 2)
+		repo := stubNamespaceRepository{
+			pods: podList,
+			fetchQuotas: func(namespace string) (quotas.NamespaceQuotas, error) {
+				requested <- namespace
+				return quotas.NamespaceQuotas{namespace: {Pods: quota(10, 10)}}, nil
+			},
+		}
+		config := Config{Namespaces: []string{"team-a", "team-b"}, Sorting: "name", Alert: "pods"}
+
+		result, err := config.apiRequest(t.Context(), repo, nil, nil)
+		require.NoError(t, err)
+		close(requested)
+		var namespaces []string
+		for namespace := range requested {
+			namespaces = append(namespaces, namespace)
+		}
+		require.ElementsMatch(t, []string{"team-a", "team-b"}, namespaces)
+		require.Len(t, result, 2)
+		require.Equal(t, "team-a", result[0].Name)
+		require.Equal(t, int64(1), result[0].Pods)
+		require.Equal(t, []string{"team-a", "team-b"}, config.Namespaces)
+	})
+
+	t.Run("wraps quota errors", func(t *testing.T) {
+		rootErr := errors.New("forbidden")
+		repo := stubNamespaceRepository{
+			pods: podList,
+			fetchQuotas: func(string) (quotas.NamespaceQuotas, error) {
+				return nil, rootErr
+			},
+		}
+
+		_, err := Config{Sorting: "name"}.apiRequest(t.Context(), repo, nil, nil)
+		require.ErrorIs(t, err, rootErr)
+		require.ErrorContains(t, err, "fetch resource quotas across all namespaces")
+	})
+}
@@ -0,0 +1,64 @@
+package namespaces
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/trezorg/k8spodsmetrics/internal/sorting/namespaces"
+	"github.com/trezorg/k8spodsmetrics/pkg/quotas"
+)
+
+func direction(reversed bool, result int) int {
+	if reversed {
+		return -result
+	}
+	return result
+}
+
+// quotaPercent orders namespaces without the quota before any constrained one.
+func quotaPercent(quota *quotas.Quota) float64 {
+	if quota == nil {
+		return -1
+	}
+	return quota.Percent
+}
+
+func sortBy[T cmp.Ordered](l NamespaceResourceList, reversed bool, key func(NamespaceResource) T) {
+	slices.SortStableFunc(l, func(a, b NamespaceResource) int {
+		return direction(reversed, cmp.Or(cmp.Compare(key(a), key(b)), cmp.Compare(a.Name, b.Name)))
+	})
+}
+
+func (l NamespaceResourceList) sort(by string, reverse bool) {
+	switch namespaces.Sorting(by) {
+	case namespaces.Name:
+		sortBy(l, reverse, func(n NamespaceResource) string { return n.Name })
+	case namespaces.Pods:
+		sortBy(l, reverse, func(n NamespaceResource) int64 { return n.Pods })
+	case namespaces.RequestCPU:
+		sortBy(l, reverse, func(n NamespaceResource) int64 { return n.CPURequest })
+	case namespaces.LimitCPU:
+		sortBy(l, reverse, func(n NamespaceResource) int64 { return n.CPULimit })
+	case namespaces.UsedCPU:
+		sortBy(l, reverse, func(n NamespaceResource) int64 { return n.CPUUsed })
+	case namespaces.RequestMemory:
+		sortBy(l, reverse, func(n NamespaceResource) int64 { return n.MemoryRequest })
+	case namespaces.LimitMemory:
+		sortBy(l, reverse, func(n NamespaceResource) int64 { return n.MemoryLimit })
+	case namespaces.UsedMemory:
+		sortBy(l, reverse, func(n NamespaceResource) int64 { return n.MemoryUsed })
+	case namespaces.QuotaRequestCPU:
+		sortBy(l, reverse, func(n NamespaceResource) float64 { return quotaPercent(n.Quota.RequestsCPU) })
+	case namespaces.QuotaLimitCPU:
+		sortBy(l, reverse, func(n NamespaceResource) float64 { return quotaPercent(n.Quota.LimitsCPU) })
+	case namespaces.QuotaRequestMemory:
+		sortBy(l, reverse, func(n NamespaceResource) float64 { return quotaPercent(n.Quota.RequestsMemory) })
+	case namespaces.QuotaLimitMemory:
+		sortBy(l, reverse, func(n NamespaceResource) float64 { return quotaPercent(n.Quota.LimitsMemory) })
+	case namespaces.QuotaPods:
+		sortBy(l, reverse, func(n NamespaceResource) float64 { return quotaPercent(n.Quota.Pods) })
+	default:
+		// keep current order on unknown sorting
+		return
+	}
+}
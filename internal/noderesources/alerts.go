@@ -1,7 +1,7 @@
 package noderesources
 
 func (n NodeResource) IsAlerted() bool {
-	return n.IsCPUAlerted() || n.IsMemoryAlerted()
+	return n.IsCPUAlerted() || n.IsMemoryAlerted() || n.IsPodsAlerted() || n.IsAnyExtendedAlerted()
 }
 
 func (n NodeResource) IsMemoryAlerted() bool {
@@ -41,3 +41,40 @@ func (n NodeResource) IsStorageEphemeralAlerted() bool {
 	}
 	return (float64(n.UsedStorageEphemeral)/float64(n.StorageEphemeral))*100 > storageEphemeralPercentAlert
 }
+
+// IsPodsAlerted reports whether the node runs more than podsPercentAlert
+// percent of the pods it accepts.
+func (n NodeResource) IsPodsAlerted() bool {
+	if n.AllocatablePods <= 0 {
+		return false
+	}
+	return (float64(n.RunningPods)/float64(n.AllocatablePods))*100 > podsPercentAlert
+}
+
+// IsExtendedAlerted reports whether requests or limits of the named extended
+// resource exceed the node allocatable. A fully allocated resource is the
+// normal state for devices and is not alerted.
+func (n NodeResource) IsExtendedAlerted(name string) bool {
+	return n.Extended[name].IsAlerted()
+}
+
+func (n NodeResource) IsAnyExtendedAlerted() bool {
+	for _, resource := range n.Extended {
+		if resource.IsAlerted() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r ExtendedResource) IsAlerted() bool {
+	return r.IsRequestAlerted() || r.IsLimitAlerted()
+}
+
+func (r ExtendedResource) IsRequestAlerted() bool {
+	return r.Request > r.Allocatable
+}
+
+func (r ExtendedResource) IsLimitAlerted() bool {
+	return r.Limit > r.Allocatable
+}
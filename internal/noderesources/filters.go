@@ -32,8 +32,23 @@ func (n NodeResourceList) filterByAlert(alert alerts.Alert) NodeResourceList {
 		return n.filterBy(func(n NodeResource) bool { return n.IsStorageAlerted() })
 	case alerts.StorageEphemeral:
 		return n.filterBy(func(n NodeResource) bool { return n.IsStorageEphemeralAlerted() })
-	case alerts.None:
+	case alerts.Pods:
+		return n.filterBy(func(n NodeResource) bool { return n.IsPodsAlerted() })
+	case alerts.Extended:
+		return n.filterBy(func(n NodeResource) bool { return n.IsAnyExtendedAlerted() })
+	// OOM kills are reported per pod.
+	case alerts.OOM, alerts.None:
 		return n
 	}
+	if name, ok := alerts.ExtendedResource(alert); ok {
+		return n.filterBy(func(n NodeResource) bool { return n.IsExtendedAlerted(name) })
+	}
 	return n
 }
+
+func (n NodeResourceList) filterBySchedulable(schedulableOnly bool) NodeResourceList {
+	if !schedulableOnly {
+		return n
+	}
+	return n.filterBy(func(n NodeResource) bool { return n.Schedulable })
+}
@@ -0,0 +1,48 @@
+package noderesources
+
+import (
+	"slices"
+	"time"
+)
+
+// ObservedAt is when the node was listed, the current time if unknown.
+func (n NodeResource) ObservedAt() time.Time {
+	if n.ListedAt.IsZero() {
+		return time.Now()
+	}
+	return n.ListedAt
+}
+
+// markListed records now as the time the nodes were listed.
+func (n NodeResourceList) markListed(now time.Time) {
+	for i := range n {
+		n[i].ListedAt = now
+	}
+}
+
+// markStaleMetrics flags nodes whose metrics were collected more than maxAge
+// before now. Sources without timestamps are never stale.
+func (n NodeResourceList) markStaleMetrics(now time.Time, maxAge time.Duration) {
+	if maxAge <= 0 {
+		return
+	}
+	for i := range n {
+		timestamp := n[i].MetricsTimestamp
+		n[i].MetricsStale = timestamp != nil && now.Sub(*timestamp) > maxAge
+	}
+}
+
+// MetricsUnavailable reports whether any node was listed while the metrics API
+// was unavailable.
+func (n NodeResourceList) MetricsUnavailable() bool {
+	return slices.ContainsFunc(n, func(node NodeResource) bool { return node.MetricsUnavailable })
+}
+
+// markMetricsUnavailable flags the nodes listed without usage as the metrics
+// API was unavailable. Their usage is unknown rather than missing.
+func (n NodeResourceList) markMetricsUnavailable() {
+	for i := range n {
+		n[i].MetricsUnavailable = true
+		n[i].MetricsMissing = false
+	}
+}
@@ -0,0 +1,228 @@
+package noderesources
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/trezorg/k8spodsmetrics/internal/serviceorchestration"
+)
+
+type (
+	// NodeResourceGroup holds the nodes sharing a label value along with their
+	// subtotal. Total sums the numeric values of the nodes; its name is the
+	// label value and its status fields are left unset. Nodes of different
+	// clusters are grouped apart.
+	NodeResourceGroup struct {
+		Cluster          string           `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+		Label            string           `json:"label" yaml:"label"`
+		Value            string           `json:"value" yaml:"value"`
+		Nodes            int              `json:"nodes" yaml:"nodes"`
+		SchedulableNodes int              `json:"schedulable_nodes" yaml:"schedulable_nodes"`
+		Total            NodeResource     `json:"total" yaml:"total"`
+		Items            NodeResourceList `json:"items,omitempty" yaml:"items,omitempty"`
+	}
+	NodeResourceGroupList         []NodeResourceGroup
+	NodeResourceGroupListEnvelope struct {
+		Groups NodeResourceGroupList `json:"groups,omitempty" yaml:"groups,omitempty"`
+		// MetricsUnavailable is set when nodes were listed without usage as
+		// the metrics API was unavailable.
+		MetricsUnavailable bool `json:"metrics_unavailable,omitempty" yaml:"metrics_unavailable,omitempty"`
+	}
+	// ClusterTotal sums the nodes of one cluster.
+	ClusterTotal struct {
+		Cluster          string       `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+		Nodes            int          `json:"nodes" yaml:"nodes"`
+		SchedulableNodes int          `json:"schedulable_nodes" yaml:"schedulable_nodes"`
+		Total            NodeResource `json:"total" yaml:"total"`
+	}
+	groupKey struct {
+		cluster string
+		value   string
+	}
+)
+
+// GroupByLabel splits nodes by cluster and the value of label. Clusters keep
+// the order they first appear in and groups of a cluster are ordered by
+// value; nodes without the label form a group with an empty value, listed
+// last. Nodes keep their order within a group. An empty label groups by
+// cluster only.
+func GroupByLabel(list NodeResourceList, label string) NodeResourceGroupList {
+	var result NodeResourceGroupList
+	index := map[groupKey]int{}
+	clusters := map[string]int{}
+	for _, node := range list {
+		value := ""
+		if label != "" {
+			value = node.Labels[label]
+		}
+		if _, ok := clusters[node.Cluster]; !ok {
+			clusters[node.Cluster] = len(clusters)
+		}
+		key := groupKey{cluster: node.Cluster, value: value}
+		idx, ok := index[key]
+		if !ok {
+			idx = len(result)
+			index[key] = idx
+			result = append(result, NodeResourceGroup{
+				Cluster: node.Cluster,
+				Label:   label,
+				Value:   value,
+				Total:   NodeResource{Cluster: node.Cluster, Name: value},
+			})
+		}
+		group := &result[idx]
+		group.Items = append(group.Items, node)
+		group.Nodes++
+		if node.Schedulable {
+			group.SchedulableNodes++
+		}
+		group.Total.Add(node)
+	}
+	slices.SortStableFunc(result, func(a, b NodeResourceGroup) int {
+		if a.Cluster != b.Cluster {
+			return clusters[a.Cluster] - clusters[b.Cluster]
+		}
+		if (a.Value == "") != (b.Value == "") {
+			if a.Value == "" {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+	return result
+}
+
+// Clustered reports whether the nodes were read from several contexts and
+// carry their cluster.
+func (l NodeResourceList) Clustered() bool {
+	for _, node := range l {
+		if node.Cluster != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// groupByCluster splits nodes by cluster. Clusters keep the order they first
+// appear in, nodes keep their order within a cluster.
+func (l NodeResourceList) groupByCluster() []NodeResourceList {
+	var result []NodeResourceList
+	index := map[string]int{}
+	for _, node := range l {
+		idx, ok := index[node.Cluster]
+		if !ok {
+			idx = len(result)
+			index[node.Cluster] = idx
+			result = append(result, nil)
+		}
+		result[idx] = append(result[idx], node)
+	}
+	return result
+}
+
+// ClusterTotals sums the nodes of every cluster. Clusters keep the order they
+// first appear in.
+func ClusterTotals(list NodeResourceList) []ClusterTotal {
+	groups := GroupByLabel(list, "")
+	result := make([]ClusterTotal, 0, len(groups))
+	for _, group := range groups {
+		total := group.Total
+		total.Name = group.Cluster
+		result = append(result, ClusterTotal{
+			Cluster:          group.Cluster,
+			Nodes:            group.Nodes,
+			SchedulableNodes: group.SchedulableNodes,
+			Total:            total,
+		})
+	}
+	return result
+}
+
+// NewNodeResourceGroupListEnvelope groups list by label for serialization.
+func NewNodeResourceGroupListEnvelope(list NodeResourceList, label string) NodeResourceGroupListEnvelope {
+	return NodeResourceGroupListEnvelope{Groups: GroupByLabel(list, label), MetricsUnavailable: list.MetricsUnavailable()}
+}
+
+// NewNodeResourceListEnvelope wraps list for serialization. Nodes of several
+// clusters get per-cluster subtotals and their sum.
+func NewNodeResourceListEnvelope(list NodeResourceList) NodeResourceListEnvelope {
+	envelope := NodeResourceListEnvelope{Items: list, MetricsUnavailable: list.MetricsUnavailable()}
+	if !list.Clustered() {
+		return envelope
+	}
+	envelope.Clusters = ClusterTotals(list)
+	total := ClusterTotal{}
+	for _, cluster := range envelope.Clusters {
+		total.Nodes += cluster.Nodes
+		total.SchedulableNodes += cluster.SchedulableNodes
+		total.Total.Add(cluster.Total)
+	}
+	envelope.Total = &total
+	return envelope
+}
+
+func mergeClusters(responses []serviceorchestration.ClusterResponse[NodeResourceList]) (NodeResourceList, error) {
+	return serviceorchestration.MergeClusters(responses, func(node *NodeResource, cluster string) {
+		node.Cluster = cluster
+	})
+}
+
+// Add sums the numeric values of node into n. It is the single place node
+// totals, group and cluster subtotals are accumulated in.
+func (n *NodeResource) Add(node NodeResource) {
+	n.CPU += node.CPU
+	n.Memory += node.Memory
+	n.UsedCPU += node.UsedCPU
+	n.UsedMemory += node.UsedMemory
+	n.MetricsUnavailable = n.MetricsUnavailable || node.MetricsUnavailable
+	n.AllocatableCPU += node.AllocatableCPU
+	n.AllocatableMemory += node.AllocatableMemory
+	n.CPURequest += node.CPURequest
+	n.MemoryRequest += node.MemoryRequest
+	n.CPULimit += node.CPULimit
+	n.MemoryLimit += node.MemoryLimit
+	n.AvailableCPU += node.AvailableCPU
+	n.AvailableMemory += node.AvailableMemory
+	n.FreeCPU += node.FreeCPU
+	n.FreeMemory += node.FreeMemory
+	n.Storage += node.Storage
+	n.AllocatableStorage += node.AllocatableStorage
+	n.UsedStorage += node.UsedStorage
+	n.FreeStorage += node.FreeStorage
+	n.StorageEphemeral += node.StorageEphemeral
+	n.AllocatableStorageEphemeral += node.AllocatableStorageEphemeral
+	n.UsedStorageEphemeral += node.UsedStorageEphemeral
+	n.FreeStorageEphemeral += node.FreeStorageEphemeral
+	n.UsedMemoryRSS += node.UsedMemoryRSS
+	n.ImageFilesystem += node.ImageFilesystem
+	n.UsedImageFilesystem += node.UsedImageFilesystem
+	n.RunningPods += node.RunningPods
+	n.AllocatablePods += node.AllocatablePods
+	n.FreePods += node.FreePods
+	n.ExcludedTerminatedPods += node.ExcludedTerminatedPods
+	for name, extended := range node.Extended {
+		if n.Extended == nil {
+			n.Extended = make(map[string]ExtendedResource, len(node.Extended))
+		}
+		current := n.Extended[name]
+		current.Capacity += extended.Capacity
+		current.Allocatable += extended.Allocatable
+		current.Request += extended.Request
+		current.Limit += extended.Limit
+		current.Available += extended.Available
+		n.Extended[name] = current
+	}
+	for _, group := range node.QOS {
+		idx := slices.IndexFunc(n.QOS, func(q QOSResource) bool { return q.Class == group.Class })
+		if idx < 0 {
+			n.QOS = append(n.QOS, QOSResource{Class: group.Class})
+			idx = len(n.QOS) - 1
+		}
+		n.QOS[idx].Pods += group.Pods
+		n.QOS[idx].CPURequest += group.CPURequest
+		n.QOS[idx].CPULimit += group.CPULimit
+		n.QOS[idx].MemoryRequest += group.MemoryRequest
+		n.QOS[idx].MemoryLimit += group.MemoryLimit
+	}
+}
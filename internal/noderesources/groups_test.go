@@ -0,0 +1,110 @@
+package noderesources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestGroupByLabel(t *testing.T) {
+	const zone = "topology.kubernetes.io/zone"
+	list := NodeResourceList{
+		{Name: "b-1", Labels: map[string]string{zone: "b"}, AllocatableCPU: 1000, CPURequest: 500, Schedulable: true},
+		{Name: "none", AllocatableCPU: 100},
+		{Name: "a-1", Labels: map[string]string{zone: "a"}, AllocatableCPU: 2000, UsedMemory: 10},
+		{Name: "b-2", Labels: map[string]string{zone: "b"}, AllocatableCPU: 3000, CPURequest: 700, ExcludedTerminatedPods: 1},
+	}
+
+	result := GroupByLabel(list, zone)
+	require.Len(t, result, 3)
+
+	require.Equal(t, "a", result[0].Value)
+	require.Equal(t, zone, result[0].Label)
+	require.Equal(t, 1, result[0].Nodes)
+	require.Equal(t, int64(10), result[0].Total.UsedMemory)
+
+	require.Equal(t, "b", result[1].Value)
+	require.Equal(t, 2, result[1].Nodes)
+	require.Equal(t, 1, result[1].SchedulableNodes)
+	require.Equal(t, []string{"b-1", "b-2"}, []string{result[1].Items[0].Name, result[1].Items[1].Name})
+	require.Equal(t, "b", result[1].Total.Name)
+	require.Equal(t, int64(4000), result[1].Total.AllocatableCPU)
+	require.Equal(t, int64(1200), result[1].Total.CPURequest)
+	require.Equal(t, 1, result[1].Total.ExcludedTerminatedPods)
+
+	require.Empty(t, result[2].Value)
+	require.Equal(t, "none", result[2].Items[0].Name)
+}
+
+func TestGroupByLabelEmpty(t *testing.T) {
+	require.Empty(t, GroupByLabel(nil, "zone"))
+}
+
+func TestNodeResourceAdd(t *testing.T) {
+	var total NodeResource
+	total.Add(NodeResource{
+		CPU:      1000,
+		Extended: map[string]ExtendedResource{"nvidia.com/gpu": {Capacity: 2, Allocatable: 2, Request: 1}},
+		QOS:      []QOSResource{{Class: v1.PodQOSGuaranteed, Pods: 1, CPURequest: 100}},
+	})
+	total.Add(NodeResource{
+		CPU:      500,
+		Extended: map[string]ExtendedResource{"nvidia.com/gpu": {Capacity: 4, Allocatable: 4, Request: 2}},
+		QOS:      []QOSResource{{Class: v1.PodQOSGuaranteed, Pods: 2, CPURequest: 200}, {Class: v1.PodQOSBestEffort, Pods: 3}},
+	})
+
+	require.Equal(t, int64(1500), total.CPU)
+	require.Equal(t, ExtendedResource{Capacity: 6, Allocatable: 6, Request: 3}, total.Extended["nvidia.com/gpu"])
+	require.Equal(t, []QOSResource{
+		{Class: v1.PodQOSGuaranteed, Pods: 3, CPURequest: 300},
+		{Class: v1.PodQOSBestEffort, Pods: 3},
+	}, total.QOS)
+}
+
+func TestGroupByLabelClusters(t *testing.T) {
+	const zone = "topology.kubernetes.io/zone"
+	list := NodeResourceList{
+		{Cluster: "prod-us", Name: "us-b", Labels: map[string]string{zone: "b"}, AllocatableCPU: 1000},
+		{Cluster: "prod-eu", Name: "eu-a", Labels: map[string]string{zone: "a"}, AllocatableCPU: 2000},
+		{Cluster: "prod-us", Name: "us-a", Labels: map[string]string{zone: "a"}, AllocatableCPU: 3000},
+	}
+
+	result := GroupByLabel(list, zone)
+	require.Len(t, result, 3)
+	require.Equal(t, []string{"prod-us", "prod-us", "prod-eu"}, []string{result[0].Cluster, result[1].Cluster, result[2].Cluster})
+	require.Equal(t, []string{"a", "b", "a"}, []string{result[0].Value, result[1].Value, result[2].Value})
+	require.Equal(t, int64(3000), result[0].Total.AllocatableCPU)
+
+	byCluster := GroupByLabel(list, "")
+	require.Len(t, byCluster, 2)
+	require.Equal(t, "prod-us", byCluster[0].Cluster)
+	require.Equal(t, 2, byCluster[0].Nodes)
+	require.Equal(t, int64(4000), byCluster[0].Total.AllocatableCPU)
+}
+
+func TestNewNodeResourceListEnvelope(t *testing.T) {
+	t.Run("clusters add subtotals and the total", func(t *testing.T) {
+		list := NodeResourceList{
+			{Cluster: "prod-us", Name: "node-1", AllocatableCPU: 1000, Schedulable: true},
+			{Cluster: "prod-us", Name: "node-2", AllocatableCPU: 2000},
+			{Cluster: "prod-eu", Name: "node-1", AllocatableCPU: 4000, Schedulable: true},
+		}
+
+		envelope := NewNodeResourceListEnvelope(list)
+		require.Equal(t, list, envelope.Items)
+		require.Len(t, envelope.Clusters, 2)
+		require.Equal(t, "prod-us", envelope.Clusters[0].Cluster)
+		require.Equal(t, "prod-us", envelope.Clusters[0].Total.Name)
+		require.Equal(t, 2, envelope.Clusters[0].Nodes)
+		require.Equal(t, 1, envelope.Clusters[0].SchedulableNodes)
+		require.Equal(t, int64(3000), envelope.Clusters[0].Total.AllocatableCPU)
+		require.Equal(t, &ClusterTotal{Nodes: 3, SchedulableNodes: 2, Total: NodeResource{AllocatableCPU: 7000}}, envelope.Total)
+	})
+
+	t.Run("a single cluster has no subtotals", func(t *testing.T) {
+		envelope := NewNodeResourceListEnvelope(NodeResourceList{{Name: "node-1"}})
+		require.Empty(t, envelope.Clusters)
+		require.Nil(t, envelope.Total)
+	})
+}
@@ -2,27 +2,48 @@ package noderesources
 
 import (
 	"log/slog"
+	"slices"
 
 	"github.com/trezorg/k8spodsmetrics/pkg/nodemetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/nodes"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	v1 "k8s.io/api/core/v1"
 )
 
-func merge(podResourceList pods.PodResourceList, nodeList nodes.NodeList, nodeMetricList nodemetrics.List) NodeResourceList {
+// merge builds node resources from nodes, the pods bound to them and node
+// metrics. Terminated pods are counted per node and left out of the request and
+// limit totals unless includeTerminated is set. They never take a pod slot.
+func merge(
+	podResourceList pods.PodResourceList,
+	nodeList nodes.NodeList,
+	nodeMetricList nodemetrics.List,
+	includeTerminated bool,
+) NodeResourceList {
 	nodesMap := make(map[string]*NodeResource)
 	for _, node := range nodeList {
 		nodesMap[node.Name] = &NodeResource{
 			Name:                        node.Name,
+			Labels:                      node.Labels,
 			CPU:                         node.CPU,
 			Memory:                      node.Memory,
 			AllocatableCPU:              node.AllocatableCPU,
 			AllocatableMemory:           node.AllocatableMemory,
+			AvailableCPU:                node.AllocatableCPU,
+			AvailableMemory:             node.AllocatableMemory,
 			Storage:                     node.Storage,
 			AllocatableStorage:          node.AllocatableStorage,
 			UsedStorage:                 node.UsedStorage,
 			StorageEphemeral:            node.StorageEphemeral,
 			AllocatableStorageEphemeral: node.AllocatableStorageEphemeral,
 			UsedStorageEphemeral:        node.UsedStorageEphemeral,
+			AllocatablePods:             node.AllocatablePods,
+			FreePods:                    node.AllocatablePods,
+			Extended:                    nodeExtendedResources(node),
+			Ready:                       node.Ready,
+			Unschedulable:               node.Unschedulable,
+			Schedulable:                 node.IsSchedulable(),
+			Conditions:                  node.Conditions,
+			Taints:                      node.Taints,
 		}
 	}
 	for _, pod := range podResourceList {
@@ -31,15 +52,25 @@ func merge(podResourceList pods.PodResourceList, nodeList nodes.NodeList, nodeMe
 			slog.Debug("Cannot find node", slog.String("node", pod.NodeName))
 			continue
 		}
-		for _, container := range pod.Containers {
-			nodeResource.CPULimit += container.Limits.CPU
-			nodeResource.CPURequest += container.Requests.CPU
-			nodeResource.MemoryLimit += container.Limits.Memory
-			nodeResource.MemoryRequest += container.Requests.Memory
+		if !pod.IsTerminated() {
+			nodeResource.RunningPods++
+			nodeResource.FreePods = nodeResource.AllocatablePods - nodeResource.RunningPods
 		}
+		if !includeTerminated && pod.IsTerminated() {
+			nodeResource.ExcludedTerminatedPods++
+			continue
+		}
+		requests := pod.EffectiveRequests()
+		limits := pod.EffectiveLimits()
+		nodeResource.CPULimit += limits.CPU
+		nodeResource.CPURequest += requests.CPU
+		nodeResource.MemoryLimit += limits.Memory
+		nodeResource.MemoryRequest += requests.Memory
 		nodeResource.AvailableCPU = nodeResource.AllocatableCPU - nodeResource.CPURequest
 		nodeResource.AvailableMemory = nodeResource.AllocatableMemory - nodeResource.MemoryRequest
+		nodeResource.addExtended(requests.Extended, limits.Extended)
 	}
+	reported := make(map[string]bool, len(nodeMetricList))
 	for _, metric := range nodeMetricList {
 		nodeResource, ok := nodesMap[metric.Name]
 		if !ok {
@@ -54,6 +85,19 @@ func merge(podResourceList pods.PodResourceList, nodeList nodes.NodeList, nodeMe
 		nodeResource.UsedStorage = metric.Storage
 		nodeResource.FreeStorageEphemeral = nodeResource.AllocatableStorageEphemeral - metric.StorageEphemeral
 		nodeResource.UsedStorageEphemeral = metric.StorageEphemeral
+		nodeResource.UsedMemoryRSS = metric.MemoryRSS
+		nodeResource.ImageFilesystem = metric.ImageFilesystem
+		nodeResource.UsedImageFilesystem = metric.ImageFilesystemUsed
+		if !metric.Timestamp.IsZero() {
+			nodeResource.MetricsTimestamp = &metric.Timestamp
+		}
+		if metric.Window > 0 {
+			nodeResource.MetricsWindow = metric.Window.String()
+		}
+		reported[metric.Name] = true
+	}
+	for name, nodeResource := range nodesMap {
+		nodeResource.MetricsMissing = !reported[name]
 	}
 	nodeResourceList := make(NodeResourceList, 0, len(nodesMap))
 	for _, node := range nodesMap {
@@ -61,3 +105,68 @@ func merge(podResourceList pods.PodResourceList, nodeList nodes.NodeList, nodeMe
 	}
 	return nodeResourceList
 }
+
+func nodeExtendedResources(node nodes.Node) map[string]ExtendedResource {
+	if len(node.Extended) == 0 {
+		return nil
+	}
+	result := make(map[string]ExtendedResource, len(node.Extended))
+	for name, capacity := range node.Extended {
+		allocatable := node.AllocatableExtended[name]
+		result[name] = ExtendedResource{Capacity: capacity, Allocatable: allocatable, Available: allocatable}
+	}
+	return result
+}
+
+func (n *NodeResource) addExtended(requests, limits map[string]int64) {
+	update := func(name string, apply func(*ExtendedResource)) {
+		if n.Extended == nil {
+			n.Extended = make(map[string]ExtendedResource)
+		}
+		resource := n.Extended[name]
+		apply(&resource)
+		resource.Available = resource.Allocatable - resource.Request
+		n.Extended[name] = resource
+	}
+	for name, value := range requests {
+		update(name, func(r *ExtendedResource) { r.Request += value })
+	}
+	for name, value := range limits {
+		update(name, func(r *ExtendedResource) { r.Limit += value })
+	}
+}
+
+// qosClasses lists QoS classes in the order they are reported, from the
+// best protected to the first evicted.
+var qosClasses = []v1.PodQOSClass{v1.PodQOSGuaranteed, v1.PodQOSBurstable, v1.PodQOSBestEffort}
+
+// groupByQOS fills the QoS breakdown of every node from the pods counted in
+// its totals, so the classes add up to the node requests and limits.
+func groupByQOS(nodeResourceList NodeResourceList, podResourceList pods.PodResourceList, includeTerminated bool) {
+	index := make(map[string]int, len(nodeResourceList))
+	for i := range nodeResourceList {
+		index[nodeResourceList[i].Name] = i
+		nodeResourceList[i].QOS = make([]QOSResource, len(qosClasses))
+		for j, class := range qosClasses {
+			nodeResourceList[i].QOS[j].Class = class
+		}
+	}
+	for _, pod := range podResourceList {
+		i, ok := index[pod.NodeName]
+		if !ok || (!includeTerminated && pod.IsTerminated()) {
+			continue
+		}
+		j := slices.Index(qosClasses, pod.QOSClass)
+		if j < 0 {
+			continue
+		}
+		requests := pod.EffectiveRequests()
+		limits := pod.EffectiveLimits()
+		group := &nodeResourceList[i].QOS[j]
+		group.Pods++
+		group.CPURequest += requests.CPU
+		group.CPULimit += limits.CPU
+		group.MemoryRequest += requests.Memory
+		group.MemoryLimit += limits.Memory
+	}
+}
@@ -1,12 +1,23 @@
 package noderesources
 
+import (
+	"time"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/nodes"
+	v1 "k8s.io/api/core/v1"
+)
+
 const (
 	storageUsedPercentAlert      = 95
 	storageEphemeralPercentAlert = 95
+	podsPercentAlert             = 90
 )
 
 type (
 	NodeResource struct {
+		// Cluster is the kubeconfig context the node was read from. It is
+		// only set when several contexts are queried.
+		Cluster                     string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
 		Name                        string `json:"name" yaml:"name"`
 		CPU                         int64  `json:"cpu" yaml:"cpu"`
 		Memory                      int64  `json:"memory" yaml:"memory"`
@@ -30,12 +41,91 @@ type (
 		AllocatableStorageEphemeral int64  `json:"allocatable_storage_ephemeral" yaml:"allocatable_storage_ephemeral"`
 		UsedStorageEphemeral        int64  `json:"used_storage_ephemeral" yaml:"used_storage_ephemeral"`
 		FreeStorageEphemeral        int64  `json:"free_storage_ephemeral" yaml:"free_storage_ephemeral"`
+		// UsedMemoryRSS and the image filesystem are reported by the kubelet
+		// metrics source only.
+		UsedMemoryRSS       int64 `json:"used_memory_rss,omitempty" yaml:"used_memory_rss,omitempty"`
+		ImageFilesystem     int64 `json:"image_filesystem,omitempty" yaml:"image_filesystem,omitempty"`
+		UsedImageFilesystem int64 `json:"used_image_filesystem,omitempty" yaml:"used_image_filesystem,omitempty"`
+		// RunningPods counts pods bound to the node that are not terminated.
+		RunningPods     int64 `json:"running_pods" yaml:"running_pods"`
+		AllocatablePods int64 `json:"allocatable_pods" yaml:"allocatable_pods"`
+		FreePods        int64 `json:"free_pods" yaml:"free_pods"`
+		// ExcludedTerminatedPods counts Succeeded/Failed pods left out of the request and limit totals.
+		ExcludedTerminatedPods int `json:"excluded_terminated_pods" yaml:"excluded_terminated_pods"`
+		// Extended holds extended resources such as nvidia.com/gpu keyed by resource name.
+		Extended map[string]ExtendedResource `json:"extended,omitempty" yaml:"extended,omitempty"`
+		// QOS breaks requests and limits down by pod QoS class. It is only
+		// filled when grouping by QoS is requested.
+		QOS []QOSResource `json:"qos,omitempty" yaml:"qos,omitempty"`
+		// Ready reports whether the node Ready condition is True.
+		Ready bool `json:"ready" yaml:"ready"`
+		// Unschedulable is set for cordoned nodes.
+		Unschedulable bool `json:"unschedulable" yaml:"unschedulable"`
+		// Schedulable reports whether new pods can land on the node: it is
+		// Ready, not cordoned and has no NoSchedule or NoExecute taint.
+		Schedulable bool `json:"schedulable" yaml:"schedulable"`
+		// Conditions lists the problem conditions that are True, e.g. MemoryPressure.
+		Conditions []string      `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+		Taints     []nodes.Taint `json:"taints,omitempty" yaml:"taints,omitempty"`
+		// MetricsTimestamp and MetricsWindow are reported by the metrics source,
+		// MetricsWindow being the interval CPU usage is averaged over.
+		MetricsTimestamp *time.Time `json:"metrics_timestamp,omitempty" yaml:"metrics_timestamp,omitempty"`
+		MetricsWindow    string     `json:"metrics_window,omitempty" yaml:"metrics_window,omitempty"`
+		// MetricsMissing marks nodes the metrics source reported nothing for,
+		// their usage is zero. MetricsStale marks metrics older than allowed.
+		MetricsMissing bool `json:"metrics_missing,omitempty" yaml:"metrics_missing,omitempty"`
+		MetricsStale   bool `json:"metrics_stale,omitempty" yaml:"metrics_stale,omitempty"`
+		// MetricsUnavailable marks nodes listed while the metrics API was
+		// unavailable, their usage is unknown.
+		MetricsUnavailable bool `json:"metrics_unavailable,omitempty" yaml:"metrics_unavailable,omitempty"`
+		// ListedAt is when the node was listed, the snapshot time of a
+		// recording. Metrics ages are measured from it.
+		ListedAt time.Time `json:"-" yaml:"-"`
+		// Labels are the node labels. They are used for grouping and are not
+		// serialized.
+		Labels map[string]string `json:"-" yaml:"-"`
+	}
+	// QOSResource holds the share of a node taken by pods of one QoS class.
+	QOSResource struct {
+		Class         v1.PodQOSClass `json:"class" yaml:"class"`
+		Pods          int64          `json:"pods" yaml:"pods"`
+		CPURequest    int64          `json:"cpu_request" yaml:"cpu_request"`
+		CPULimit      int64          `json:"cpu_limit" yaml:"cpu_limit"`
+		MemoryRequest int64          `json:"memory_request" yaml:"memory_request"`
+		MemoryLimit   int64          `json:"memory_limit" yaml:"memory_limit"`
+	}
+	// ExtendedResource describes an extended resource on a node. Metrics server
+	// does not report usage for them, so only scheduling values are available.
+	ExtendedResource struct {
+		Capacity    int64 `json:"capacity" yaml:"capacity"`
+		Allocatable int64 `json:"allocatable" yaml:"allocatable"`
+		Request     int64 `json:"request" yaml:"request"`
+		Limit       int64 `json:"limit" yaml:"limit"`
+		Available   int64 `json:"available" yaml:"available"`
 	}
 	NodeResourceList         []NodeResource
 	NodeResourceListEnvelope struct {
 		Items NodeResourceList `json:"items,omitempty" yaml:"items,omitempty"`
+		// Clusters and Total are the per-cluster subtotals and their sum,
+		// set when nodes of several clusters are listed.
+		Clusters []ClusterTotal `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+		Total    *ClusterTotal  `json:"total,omitempty" yaml:"total,omitempty"`
+		// MetricsUnavailable is set when nodes were listed without usage as
+		// the metrics API was unavailable.
+		MetricsUnavailable bool `json:"metrics_unavailable,omitempty" yaml:"metrics_unavailable,omitempty"`
 	}
 	// NodeResourceListEnvelop is kept as a compatibility alias for previous typoed name.
 	NodeResourceListEnvelop = NodeResourceListEnvelope
 	nodePredicate           func(n NodeResource) bool
 )
+
+// IsTainted reports whether the node has a taint keeping pods without a
+// matching toleration off it.
+func (n NodeResource) IsTainted() bool {
+	for _, taint := range n.Taints {
+		if taint.PreventsScheduling() {
+			return true
+		}
+	}
+	return false
+}
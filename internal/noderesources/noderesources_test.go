@@ -3,30 +3,68 @@ package noderesources
 import (
 	"io"
 	"log/slog"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	alerts "github.com/trezorg/k8spodsmetrics/internal/alert"
 	"github.com/trezorg/k8spodsmetrics/pkg/nodemetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/nodes"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	v1 "k8s.io/api/core/v1"
 )
 
 func TestMergeNodeResources(t *testing.T) {
 	slog.SetDefault(slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo})))
 
 	t.Run("empty lists", func(t *testing.T) {
-		result := merge(pods.PodResourceList{}, nodes.NodeList{}, nodemetrics.List{})
+		result := merge(pods.PodResourceList{}, nodes.NodeList{}, nodemetrics.List{}, false)
 		require.Empty(t, result)
 	})
 
 	t.Run("only nodes", func(t *testing.T) {
 		nodeList := nodes.NodeList{{Name: "node1", CPU: 4000, Memory: 16 * 1024 * 1024 * 1024}}
-		result := merge(pods.PodResourceList{}, nodeList, nodemetrics.List{})
+		result := merge(pods.PodResourceList{}, nodeList, nodemetrics.List{}, false)
 		require.Len(t, result, 1)
 		require.Equal(t, "node1", result[0].Name)
 		require.Equal(t, int64(4000), result[0].CPU)
 	})
 
+	t.Run("kubelet metrics", func(t *testing.T) {
+		nodeList := nodes.NodeList{{Name: "node1", StorageEphemeral: 1000, AllocatableStorageEphemeral: 900}}
+		metrics := nodemetrics.List{{
+			Name:                "node1",
+			Memory:              2048,
+			MemoryRSS:           1024,
+			StorageEphemeral:    400,
+			ImageFilesystem:     5000,
+			ImageFilesystemUsed: 2000,
+		}}
+		result := merge(pods.PodResourceList{}, nodeList, metrics, false)
+		require.Len(t, result, 1)
+		require.Equal(t, int64(1024), result[0].UsedMemoryRSS)
+		require.Equal(t, int64(400), result[0].UsedStorageEphemeral)
+		require.Equal(t, int64(500), result[0].FreeStorageEphemeral)
+		require.Equal(t, int64(5000), result[0].ImageFilesystem)
+		require.Equal(t, int64(2000), result[0].UsedImageFilesystem)
+	})
+
+	t.Run("metrics timestamp and missing metrics", func(t *testing.T) {
+		collected := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+		nodeList := nodes.NodeList{{Name: "node1"}, {Name: "node2"}}
+		metrics := nodemetrics.List{{Name: "node1", CPU: 100, Timestamp: collected, Window: 30 * time.Second}}
+		result := merge(pods.PodResourceList{}, nodeList, metrics, false)
+		slices.SortFunc(result, func(a, b NodeResource) int { return strings.Compare(a.Name, b.Name) })
+		require.Len(t, result, 2)
+		require.Equal(t, &collected, result[0].MetricsTimestamp)
+		require.Equal(t, "30s", result[0].MetricsWindow)
+		require.False(t, result[0].MetricsMissing)
+		require.Nil(t, result[1].MetricsTimestamp)
+		require.True(t, result[1].MetricsMissing)
+	})
+
 	t.Run("node with pods", func(t *testing.T) {
 		nodeList := nodes.NodeList{{Name: "node1", AllocatableCPU: 4000, AllocatableMemory: 16 * 1024 * 1024 * 1024}}
 		podList := pods.PodResourceList{
@@ -37,17 +75,132 @@ func TestMergeNodeResources(t *testing.T) {
 				},
 			},
 		}
-		result := merge(podList, nodeList, nodemetrics.List{})
+		result := merge(podList, nodeList, nodemetrics.List{}, false)
 		require.Len(t, result, 1)
 		require.Equal(t, int64(500), result[0].CPURequest)
 		require.Equal(t, int64(512*1024*1024), result[0].MemoryRequest)
 		require.Equal(t, int64(3500), result[0].AvailableCPU)
 	})
 
+	t.Run("node with init containers, sidecars and overhead", func(t *testing.T) {
+		nodeList := nodes.NodeList{{Name: "node1", AllocatableCPU: 4000, AllocatableMemory: 16 * 1024 * 1024 * 1024}}
+		podList := pods.PodResourceList{
+			{
+				NodeName: "node1",
+				Containers: []pods.ContainerResource{
+					{Name: "app", Requests: pods.Resource{CPU: 100, Memory: 256}, Limits: pods.Resource{CPU: 200}},
+					{Name: "proxy", Type: pods.SidecarContainer, Requests: pods.Resource{CPU: 50, Memory: 64}},
+					{Name: "migrate", Type: pods.InitContainer, Requests: pods.Resource{CPU: 1000, Memory: 128}},
+				},
+				Overhead: pods.Resource{CPU: 10, Memory: 32},
+			},
+		}
+		result := merge(podList, nodeList, nodemetrics.List{}, false)
+		require.Len(t, result, 1)
+		require.Equal(t, int64(1060), result[0].CPURequest)
+		require.Equal(t, int64(352), result[0].MemoryRequest)
+		require.Equal(t, int64(210), result[0].CPULimit)
+		require.Equal(t, int64(2940), result[0].AvailableCPU)
+	})
+
+	t.Run("terminated pods are excluded and counted", func(t *testing.T) {
+		nodeList := nodes.NodeList{{Name: "node1", AllocatableCPU: 4000}}
+		podList := pods.PodResourceList{
+			{NodeName: "node1", Phase: v1.PodRunning, Containers: []pods.ContainerResource{{Name: "app", Requests: pods.Resource{CPU: 100}}}},
+			{NodeName: "node1", Phase: v1.PodSucceeded, Containers: []pods.ContainerResource{{Name: "job", Requests: pods.Resource{CPU: 500}}}},
+			{NodeName: "node1", Phase: v1.PodFailed, Containers: []pods.ContainerResource{{Name: "job", Requests: pods.Resource{CPU: 700}}}},
+		}
+		result := merge(podList, nodeList, nodemetrics.List{}, false)
+		require.Len(t, result, 1)
+		require.Equal(t, int64(100), result[0].CPURequest)
+		require.Equal(t, int64(3900), result[0].AvailableCPU)
+		require.Equal(t, 2, result[0].ExcludedTerminatedPods)
+		require.Equal(t, int64(1), result[0].RunningPods)
+
+		result = merge(podList, nodeList, nodemetrics.List{}, true)
+		require.Len(t, result, 1)
+		require.Equal(t, int64(1300), result[0].CPURequest)
+		require.Equal(t, 0, result[0].ExcludedTerminatedPods)
+	})
+
+	t.Run("node with only terminated pods", func(t *testing.T) {
+		nodeList := nodes.NodeList{{Name: "node1", AllocatableCPU: 4000, AllocatableMemory: 1024}}
+		podList := pods.PodResourceList{
+			{NodeName: "node1", Phase: v1.PodSucceeded, Containers: []pods.ContainerResource{{Name: "job", Requests: pods.Resource{CPU: 500, Memory: 64}}}},
+		}
+		result := merge(podList, nodeList, nodemetrics.List{}, false)
+		require.Len(t, result, 1)
+		require.Equal(t, int64(0), result[0].CPURequest)
+		require.Equal(t, int64(4000), result[0].AvailableCPU)
+		require.Equal(t, int64(1024), result[0].AvailableMemory)
+		require.Equal(t, 1, result[0].ExcludedTerminatedPods)
+	})
+
+	t.Run("pod slots", func(t *testing.T) {
+		nodeList := nodes.NodeList{{Name: "node1", AllocatablePods: 110}, {Name: "node2", AllocatablePods: 20}}
+		podList := pods.PodResourceList{
+			{NodeName: "node1", Phase: v1.PodRunning},
+			{NodeName: "node1", Phase: v1.PodPending},
+			{NodeName: "node1", Phase: v1.PodSucceeded},
+		}
+		result := merge(podList, nodeList, nodemetrics.List{}, true)
+		require.Len(t, result, 2)
+		for _, node := range result {
+			switch node.Name {
+			case "node1":
+				require.Equal(t, int64(2), node.RunningPods)
+				require.Equal(t, int64(108), node.FreePods)
+			case "node2":
+				require.Equal(t, int64(0), node.RunningPods)
+				require.Equal(t, int64(20), node.FreePods)
+			}
+		}
+	})
+
+	t.Run("node with extended resources", func(t *testing.T) {
+		nodeList := nodes.NodeList{{
+			Name:                "node1",
+			Extended:            map[string]int64{"nvidia.com/gpu": 4},
+			AllocatableExtended: map[string]int64{"nvidia.com/gpu": 4},
+		}}
+		gpu := map[string]int64{"nvidia.com/gpu": 1}
+		podList := pods.PodResourceList{
+			{NodeName: "node1", Containers: []pods.ContainerResource{{Name: "c1", Requests: pods.Resource{Extended: gpu}, Limits: pods.Resource{Extended: gpu}}}},
+			{NodeName: "node1", Containers: []pods.ContainerResource{{Name: "c2", Requests: pods.Resource{Extended: gpu}, Limits: pods.Resource{Extended: gpu}}}},
+		}
+		result := merge(podList, nodeList, nodemetrics.List{}, false)
+		require.Len(t, result, 1)
+		require.Equal(t, ExtendedResource{Capacity: 4, Allocatable: 4, Request: 2, Limit: 2, Available: 2}, result[0].Extended["nvidia.com/gpu"])
+	})
+
+	t.Run("node status", func(t *testing.T) {
+		taints := []nodes.Taint{{Key: "node-role.kubernetes.io/control-plane", Effect: v1.TaintEffectNoSchedule}}
+		nodeList := nodes.NodeList{
+			{Name: "worker", Ready: true},
+			{Name: "control-plane", Ready: true, Taints: taints},
+			{Name: "broken", Conditions: []string{"MemoryPressure"}},
+		}
+		result := merge(pods.PodResourceList{}, nodeList, nodemetrics.List{}, false)
+		require.Len(t, result, 3)
+		for _, node := range result {
+			switch node.Name {
+			case "worker":
+				require.True(t, node.Schedulable)
+			case "control-plane":
+				require.False(t, node.Schedulable)
+				require.Equal(t, taints, node.Taints)
+			case "broken":
+				require.False(t, node.Ready)
+				require.False(t, node.Schedulable)
+				require.Equal(t, []string{"MemoryPressure"}, node.Conditions)
+			}
+		}
+	})
+
 	t.Run("node with metrics", func(t *testing.T) {
 		nodeList := nodes.NodeList{{Name: "node1", AllocatableCPU: 4000, AllocatableMemory: 16 * 1024 * 1024 * 1024}}
 		metricsList := nodemetrics.List{{Name: "node1", CPU: 2000, Memory: 8 * 1024 * 1024 * 1024}}
-		result := merge(pods.PodResourceList{}, nodeList, metricsList)
+		result := merge(pods.PodResourceList{}, nodeList, metricsList, false)
 		require.Len(t, result, 1)
 		require.Equal(t, int64(2000), result[0].UsedCPU)
 		require.Equal(t, int64(8*1024*1024*1024), result[0].UsedMemory)
@@ -55,6 +208,44 @@ func TestMergeNodeResources(t *testing.T) {
 	})
 }
 
+func TestGroupByQOS(t *testing.T) {
+	nodeList := nodes.NodeList{{Name: "node1", AllocatableCPU: 4000}, {Name: "node2"}}
+	podList := pods.PodResourceList{
+		{NodeName: "node1", QOSClass: v1.PodQOSGuaranteed, Containers: []pods.ContainerResource{{Name: "db", Requests: pods.Resource{CPU: 1000, Memory: 1024}, Limits: pods.Resource{CPU: 1000, Memory: 1024}}}},
+		{NodeName: "node1", QOSClass: v1.PodQOSBurstable, Containers: []pods.ContainerResource{{Name: "api", Requests: pods.Resource{CPU: 200}, Limits: pods.Resource{CPU: 500}}}},
+		{NodeName: "node1", QOSClass: v1.PodQOSBurstable, Containers: []pods.ContainerResource{{Name: "web", Requests: pods.Resource{CPU: 100, Memory: 64}}}},
+		{NodeName: "node1", QOSClass: v1.PodQOSBestEffort, Containers: []pods.ContainerResource{{Name: "debug"}}},
+		{NodeName: "node1", QOSClass: v1.PodQOSBurstable, Phase: v1.PodSucceeded, Containers: []pods.ContainerResource{{Name: "job", Requests: pods.Resource{CPU: 700}}}},
+	}
+
+	t.Run("classes add up to node totals", func(t *testing.T) {
+		result := merge(podList, nodeList, nodemetrics.List{}, false)
+		groupByQOS(result, podList, false)
+		for _, node := range result {
+			require.Len(t, node.QOS, 3)
+			if node.Name != "node1" {
+				require.Equal(t, QOSResource{Class: v1.PodQOSGuaranteed}, node.QOS[0])
+				continue
+			}
+			require.Equal(t, QOSResource{Class: v1.PodQOSGuaranteed, Pods: 1, CPURequest: 1000, CPULimit: 1000, MemoryRequest: 1024, MemoryLimit: 1024}, node.QOS[0])
+			require.Equal(t, QOSResource{Class: v1.PodQOSBurstable, Pods: 2, CPURequest: 300, CPULimit: 500, MemoryRequest: 64}, node.QOS[1])
+			require.Equal(t, QOSResource{Class: v1.PodQOSBestEffort, Pods: 1}, node.QOS[2])
+			require.Equal(t, node.CPURequest, node.QOS[0].CPURequest+node.QOS[1].CPURequest+node.QOS[2].CPURequest)
+		}
+	})
+
+	t.Run("terminated pods follow include terminated", func(t *testing.T) {
+		result := merge(podList, nodeList, nodemetrics.List{}, true)
+		groupByQOS(result, podList, true)
+		for _, node := range result {
+			if node.Name == "node1" {
+				require.Equal(t, int64(3), node.QOS[1].Pods)
+				require.Equal(t, int64(1000), node.QOS[1].CPURequest)
+			}
+		}
+	})
+}
+
 func TestNewNodeRepository(t *testing.T) {
 	repo := NewNodeRepository()
 	require.NotNil(t, repo)
@@ -93,3 +284,137 @@ func TestNodeResource_IsStorageEphemeralAlerted(t *testing.T) {
 		require.False(t, resource.IsStorageEphemeralAlerted())
 	})
 }
+
+func TestNodeResource_IsExtendedAlerted(t *testing.T) {
+	resource := NodeResource{Extended: map[string]ExtendedResource{
+		"nvidia.com/gpu":   {Capacity: 4, Allocatable: 2, Request: 3, Limit: 3},
+		"example.com/fpga": {Capacity: 4, Allocatable: 4, Request: 4, Limit: 4},
+	}}
+	require.True(t, resource.IsExtendedAlerted("nvidia.com/gpu"))
+	require.False(t, resource.IsExtendedAlerted("example.com/fpga"), "fully allocated is not alerted")
+	require.False(t, resource.IsExtendedAlerted("missing.com/device"))
+	require.True(t, resource.IsAnyExtendedAlerted())
+	require.True(t, resource.IsAlerted())
+}
+
+func TestNodeResource_IsPodsAlerted(t *testing.T) {
+	t.Run("unknown pod limit is not alerted", func(t *testing.T) {
+		resource := NodeResource{RunningPods: 10}
+		require.False(t, resource.IsPodsAlerted())
+	})
+
+	t.Run("nearly full node is alerted", func(t *testing.T) {
+		resource := NodeResource{AllocatablePods: 110, RunningPods: 100}
+		require.True(t, resource.IsPodsAlerted())
+		require.True(t, resource.IsAlerted())
+	})
+
+	t.Run("usage at threshold is not alerted", func(t *testing.T) {
+		resource := NodeResource{AllocatablePods: 100, RunningPods: 90}
+		require.False(t, resource.IsPodsAlerted())
+	})
+}
+
+func TestFilterByPodsAlert(t *testing.T) {
+	list := NodeResourceList{
+		{Name: "full", AllocatablePods: 10, RunningPods: 10},
+		{Name: "empty", AllocatablePods: 10, RunningPods: 1},
+	}
+	result := list.filterByAlert(alerts.Pods)
+	require.Len(t, result, 1)
+	require.Equal(t, "full", result[0].Name)
+}
+
+func TestNodeResource_IsTainted(t *testing.T) {
+	require.False(t, NodeResource{}.IsTainted())
+	require.False(t, NodeResource{Taints: []nodes.Taint{{Key: "spot", Effect: v1.TaintEffectPreferNoSchedule}}}.IsTainted())
+	require.True(t, NodeResource{Taints: []nodes.Taint{{Key: "spot", Effect: v1.TaintEffectNoSchedule}}}.IsTainted())
+}
+
+func TestFilterBySchedulable(t *testing.T) {
+	list := NodeResourceList{
+		{Name: "ready", Ready: true, Schedulable: true},
+		{Name: "cordoned", Ready: true, Unschedulable: true},
+		{Name: "not-ready"},
+	}
+	require.Len(t, list.filterBySchedulable(false), 3)
+
+	result := list.filterBySchedulable(true)
+	require.Len(t, result, 1)
+	require.Equal(t, "ready", result[0].Name)
+}
+
+func TestSortPods(t *testing.T) {
+	list := NodeResourceList{
+		{Name: "a", AllocatablePods: 110, RunningPods: 10, FreePods: 100},
+		{Name: "b", AllocatablePods: 20, RunningPods: 15, FreePods: 5},
+	}
+
+	list.sort("free_pods", false)
+	require.Equal(t, []string{"b", "a"}, nodeNames(list))
+
+	list.sort("running_pods", true)
+	require.Equal(t, []string{"b", "a"}, nodeNames(list))
+
+	list.sort("allocatable_pods", true)
+	require.Equal(t, []string{"a", "b"}, nodeNames(list))
+}
+
+func TestFilterByExtendedAlert(t *testing.T) {
+	list := NodeResourceList{
+		{Name: "gpu-over", Extended: map[string]ExtendedResource{"nvidia.com/gpu": {Capacity: 2, Allocatable: 1, Request: 2}}},
+		{Name: "gpu-full", Extended: map[string]ExtendedResource{"nvidia.com/gpu": {Capacity: 1, Allocatable: 1, Request: 1}}},
+		{Name: "gpu-free", Extended: map[string]ExtendedResource{"nvidia.com/gpu": {Capacity: 4, Allocatable: 4, Request: 1}}},
+		{Name: "cpu-only"},
+	}
+
+	result := list.filterByAlert(alerts.Alert("nvidia.com/gpu"))
+	require.Len(t, result, 1)
+	require.Equal(t, "gpu-over", result[0].Name)
+
+	result = list.filterByAlert(alerts.Extended)
+	require.Len(t, result, 1)
+	require.Equal(t, "gpu-over", result[0].Name)
+}
+
+func TestSortExtended(t *testing.T) {
+	list := NodeResourceList{
+		{Name: "a", Extended: map[string]ExtendedResource{"nvidia.com/gpu": {Request: 1, Available: 3}}},
+		{Name: "b", Extended: map[string]ExtendedResource{"nvidia.com/gpu": {Request: 3, Available: 1}}},
+		{Name: "c"},
+	}
+
+	list.sort("request_nvidia.com/gpu", false)
+	require.Equal(t, []string{"c", "a", "b"}, nodeNames(list))
+
+	list.sort("available_nvidia.com/gpu", true)
+	require.Equal(t, []string{"a", "b", "c"}, nodeNames(list))
+}
+
+func nodeNames(list NodeResourceList) []string {
+	names := make([]string, 0, len(list))
+	for _, node := range list {
+		names = append(names, node.Name)
+	}
+	return names
+}
+
+func TestMarkStaleMetrics(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 10, 0, 0, time.UTC)
+	fresh := now.Add(-time.Minute)
+	old := now.Add(-5 * time.Minute)
+	list := NodeResourceList{
+		{Name: "fresh", MetricsTimestamp: &fresh},
+		{Name: "old", MetricsTimestamp: &old},
+		{Name: "unknown"},
+	}
+
+	list.markStaleMetrics(now, 3*time.Minute)
+	require.False(t, list[0].MetricsStale)
+	require.True(t, list[1].MetricsStale)
+	require.False(t, list[2].MetricsStale)
+
+	list[1].MetricsStale = false
+	list.markStaleMetrics(now, 0)
+	require.False(t, list[1].MetricsStale, "zero max age disables the check")
+}
@@ -8,6 +8,7 @@ import (
 
 	"log/slog"
 
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
 	"github.com/trezorg/k8spodsmetrics/pkg/nodemetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/nodes"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
@@ -31,47 +32,79 @@ type NodeRepository interface {
 	FetchMetrics(
 		ctx context.Context,
 		metricsClient metricsv1beta1.MetricsV1beta1Interface,
+		coreClient corev1.CoreV1Interface,
 		filter nodemetrics.MetricsFilter,
 		name string,
 	) (nodemetrics.List, error)
 }
 
-type nodeRepository struct{}
+type nodeRepository struct {
+	metrics metricssource.Reader
+	// nodes and pods are set for watches, which keep them in informer caches.
+	nodes *nodes.Cache
+	pods  *pods.Cache
+}
 
 func NewNodeRepository() NodeRepository {
-	return &nodeRepository{}
+	return NewNodeRepositoryWithSource(metricssource.Config{})
+}
+
+// NewNodeRepositoryWithSource returns a repository reading usage metrics from
+// the configured source, metrics-server when none is set.
+func NewNodeRepositoryWithSource(source metricssource.Config) NodeRepository {
+	return &nodeRepository{metrics: metricssource.New(source)}
+}
+
+// NewCachedNodeRepositoryWithSource is NewNodeRepositoryWithSource keeping
+// nodes and pods in informer caches until ctx is done, so only metrics are
+// requested on every fetch.
+func NewCachedNodeRepositoryWithSource(ctx context.Context, source metricssource.Config) NodeRepository {
+	return &nodeRepository{
+		metrics: metricssource.New(source),
+		nodes:   nodes.NewCache(ctx),
+		pods:    pods.NewCache(ctx),
+	}
 }
 
-func (nodeRepository) FetchNodes(
+func (r nodeRepository) FetchNodes(
 	ctx context.Context,
 	coreClient corev1.CoreV1Interface,
 	filter nodes.NodeFilter,
 	name string,
 ) (nodes.NodeList, error) {
+	if r.nodes != nil {
+		return r.nodes.Nodes(ctx, coreClient, filter, name)
+	}
 	return nodes.Nodes(ctx, coreClient, filter, name)
 }
 
-func (nodeRepository) FetchPods(
+func (r nodeRepository) FetchPods(
 	ctx context.Context,
 	coreClient corev1.CoreV1Interface,
 	filter pods.PodFilter,
 	name string,
 ) (pods.PodResourceList, error) {
+	if r.pods != nil {
+		return r.pods.Pods(ctx, coreClient, filter, name)
+	}
 	return pods.Pods(ctx, coreClient, filter, name)
 }
 
-func (nodeRepository) FetchMetrics(
+func (r nodeRepository) FetchMetrics(
 	ctx context.Context,
 	metricsClient metricsv1beta1.MetricsV1beta1Interface,
+	coreClient corev1.CoreV1Interface,
 	filter nodemetrics.MetricsFilter,
 	name string,
 ) (nodemetrics.List, error) {
-	return nodemetrics.Metrics(ctx, metricsClient, filter, name)
+	return r.metrics.NodeMetrics(ctx, metricsClient, coreClient, filter, name)
 }
 
 type FetchConfig struct {
-	Label string
-	Name  string
+	Label             string
+	Name              string
+	IncludeTerminated bool
+	GroupByQOS        bool
 }
 
 func FetchNodeMetrics(
@@ -106,7 +139,7 @@ func FetchNodeMetrics(
 	})
 
 	wg.Go(func() {
-		nodeMetricsList, cErrors[2] = repo.FetchMetrics(ctx, metricsClient, nodemetrics.MetricsFilter{LabelSelector: config.Label}, config.Name)
+		nodeMetricsList, cErrors[2] = repo.FetchMetrics(ctx, metricsClient, coreClient, nodemetrics.MetricsFilter{LabelSelector: config.Label}, config.Name)
 		if cErrors[2] != nil {
 			cErrors[2] = wrapNodeFetchError("fetch node metrics", config.Name, cErrors[2])
 		}
@@ -114,11 +147,26 @@ func FetchNodeMetrics(
 
 	wg.Wait()
 
+	// Nodes are still listed with their allocatable resources, requests and
+	// limits when the metrics API is unavailable, watches keep polling until
+	// usage is back.
+	unavailable := errors.Is(cErrors[2], metricssource.ErrUnavailable)
+	if unavailable {
+		slog.Debug("Metrics API is unavailable", slog.Any("error", cErrors[2]))
+		cErrors[2] = nil
+	}
+
 	if err := errors.Join(cErrors...); err != nil {
 		return nodeResources, err
 	}
 
-	nodeResources = merge(podsList, nodesList, nodeMetricsList)
+	nodeResources = merge(podsList, nodesList, nodeMetricsList, config.IncludeTerminated)
+	if unavailable {
+		nodeResources.markMetricsUnavailable()
+	}
+	if config.GroupByQOS {
+		groupByQOS(nodeResources, podsList, config.IncludeTerminated)
+	}
 	return nodeResources, nil
 }
 
@@ -3,12 +3,15 @@ package noderesources
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
 	"github.com/trezorg/k8spodsmetrics/pkg/nodemetrics"
 	"github.com/trezorg/k8spodsmetrics/pkg/nodes"
 	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	v1 "k8s.io/api/core/v1"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 )
@@ -46,6 +49,7 @@ func (s stubNodeRepository) FetchPods(
 func (s stubNodeRepository) FetchMetrics(
 	_ context.Context,
 	metricsClient metricsv1beta1.MetricsV1beta1Interface,
+	_ corev1.CoreV1Interface,
 	filter nodemetrics.MetricsFilter,
 	name string,
 ) (nodemetrics.List, error) {
@@ -98,3 +102,49 @@ func TestFetchNodeMetricsWrapsBranchErrors(t *testing.T) {
 		require.ErrorIs(t, err, rootErr)
 	})
 }
+
+func TestFetchNodeMetricsGroupByQOS(t *testing.T) {
+	repo := stubNodeRepository{
+		fetchNodes: func(corev1.CoreV1Interface, nodes.NodeFilter, string) (nodes.NodeList, error) {
+			return nodes.NodeList{{Name: "node1"}}, nil
+		},
+		fetchPods: func(corev1.CoreV1Interface, pods.PodFilter, string) (pods.PodResourceList, error) {
+			return pods.PodResourceList{{NodeName: "node1", QOSClass: v1.PodQOSBestEffort}}, nil
+		},
+	}
+
+	result, err := FetchNodeMetrics(t.Context(), repo, nil, nil, FetchConfig{})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Empty(t, result[0].QOS)
+
+	result, err = FetchNodeMetrics(t.Context(), repo, nil, nil, FetchConfig{GroupByQOS: true})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Len(t, result[0].QOS, 3)
+	require.Equal(t, int64(1), result[0].QOS[2].Pods)
+}
+
+func TestFetchNodeMetricsWithMetricsUnavailable(t *testing.T) {
+	repo := stubNodeRepository{
+		fetchNodes: func(corev1.CoreV1Interface, nodes.NodeFilter, string) (nodes.NodeList, error) {
+			return nodes.NodeList{{Name: "node1", AllocatableCPU: 4000}}, nil
+		},
+		fetchPods: func(corev1.CoreV1Interface, pods.PodFilter, string) (pods.PodResourceList, error) {
+			return pods.PodResourceList{{NodeName: "node1", Containers: []pods.ContainerResource{{Requests: pods.Resource{CPU: 500}}}}}, nil
+		},
+		fetchMetrics: func(metricsv1beta1.MetricsV1beta1Interface, nodemetrics.MetricsFilter, string) (nodemetrics.List, error) {
+			return nil, fmt.Errorf("%w: the server could not find the requested resource", metricssource.ErrUnavailable)
+		},
+	}
+
+	result, err := FetchNodeMetrics(t.Context(), repo, nil, nil, FetchConfig{})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.True(t, result.MetricsUnavailable())
+	require.True(t, result[0].MetricsUnavailable)
+	require.False(t, result[0].MetricsMissing)
+	require.Equal(t, int64(500), result[0].CPURequest)
+	require.Equal(t, int64(3500), result[0].AvailableCPU)
+	require.True(t, NewNodeResourceListEnvelope(result).MetricsUnavailable)
+}
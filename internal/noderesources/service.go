@@ -3,25 +3,39 @@ package noderesources
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/trezorg/k8spodsmetrics/internal/alert"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
 	"github.com/trezorg/k8spodsmetrics/internal/serviceorchestration"
 	sorting "github.com/trezorg/k8spodsmetrics/internal/sorting/noderesources"
 	"github.com/trezorg/k8spodsmetrics/pkg/client"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
+	"k8s.io/apimachinery/pkg/labels"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 )
 
 type Config struct {
-	KubeConfig  string
-	KubeContext string
-	Label       string
-	Name        string
-	Sorting     string
-	Alert       string
-	WatchPeriod uint
-	Timeout     uint
-	Reverse     bool
+	KubeConfig        string
+	KubeContext       string
+	Overrides         client.Overrides
+	Label             string
+	Name              string
+	Sorting           string
+	Alert             string
+	MetricsSource     metricssource.Config
+	MetricsMaxAge     time.Duration
+	WatchPeriod       uint
+	Timeout           uint
+	Retry             retry.Policy
+	Reverse           bool
+	IncludeTerminated bool
+	GroupByQOS        bool
+	// SchedulableOnly drops cordoned, NotReady and NoSchedule tainted nodes.
+	SchedulableOnly bool
+	kubeContexts    []string
 }
 
 type WatchResponse = serviceorchestration.WatchResponse[NodeResourceList]
@@ -30,6 +44,9 @@ func (c Config) Validate() error {
 	if err := alert.Valid(alert.Alert(c.Alert)); err != nil {
 		return err
 	}
+	if err := c.MetricsSource.Validate(); err != nil {
+		return err
+	}
 	return sorting.Valid(sorting.Sorting(c.Sorting))
 }
 
@@ -49,44 +66,119 @@ func (c Config) apiRequest(
 	metricsClient metricsv1beta1.MetricsV1beta1Interface,
 	coreClient corev1.CoreV1Interface,
 ) (NodeResourceList, error) {
+	ctx = retry.WithPolicy(ctx, c.Retry)
 	fetchConfig := FetchConfig{
-		Label: c.Label,
-		Name:  c.Name,
+		Label:             c.Label,
+		Name:              c.Name,
+		IncludeTerminated: c.IncludeTerminated,
+		GroupByQOS:        c.GroupByQOS,
 	}
 	nodeResources, err := FetchNodeMetrics(ctx, repo, coreClient, metricsClient, fetchConfig)
 	if err != nil {
 		return nil, err
 	}
-	nodeResources = nodeResources.filterByAlert(alert.Alert(c.Alert))
-	nodeResources.sort(c.Sorting, c.Reverse)
-	return nodeResources, nil
+	now := time.Now()
+	nodeResources.markListed(now)
+	nodeResources.markStaleMetrics(now, c.MetricsMaxAge)
+	return c.refine(nodeResources), nil
+}
+
+// Refine applies the name, label, schedulable and alert filters and the
+// sorting to nodes read elsewhere, such as from a recording. Nodes of several
+// clusters are sorted within their cluster and clusters keep their order, as
+// when they are requested.
+func (c Config) Refine(list NodeResourceList) (NodeResourceList, error) {
+	selector, err := labels.Parse(c.Label)
+	if err != nil {
+		return nil, fmt.Errorf("invalid node label selector %q: %w", c.Label, err)
+	}
+	list = list.filterBy(func(n NodeResource) bool {
+		return (c.Name == "" || n.Name == c.Name) && selector.Matches(labels.Set(n.Labels))
+	})
+	if !list.Clustered() {
+		return c.refine(list), nil
+	}
+	var result NodeResourceList
+	for _, group := range list.groupByCluster() {
+		result = append(result, c.refine(group)...)
+	}
+	return result, nil
+}
+
+func (c Config) refine(list NodeResourceList) NodeResourceList {
+	list = list.filterBySchedulable(c.SchedulableOnly)
+	list = list.filterByAlert(alert.Alert(c.Alert))
+	list.sort(c.Sorting, c.Reverse)
+	return list
+}
+
+func (c *Config) newRepository() NodeRepository {
+	return NewNodeRepositoryWithSource(c.MetricsSource)
+}
+
+func (c *Config) newWatchRepository(ctx context.Context) NodeRepository {
+	return NewCachedNodeRepositoryWithSource(ctx, c.MetricsSource)
 }
 
 func (c *Config) Request(ctx context.Context) (NodeResourceList, error) {
+	if len(c.kubeContexts) > 1 {
+		return serviceorchestration.RequestClustersWithRepo(
+			ctx,
+			c.KubeConfig,
+			c.kubeContexts,
+			c.Timeout,
+			c.Overrides.Clients,
+			c.newRepository,
+			c.apiRequest,
+			mergeClusters,
+		)
+	}
 	return serviceorchestration.RequestWithRepo(
 		ctx,
 		c.KubeConfig,
-		c.KubeContext,
+		c.kubeContext(),
 		c.Timeout,
-		client.Clients,
-		NewNodeRepository,
+		c.Overrides.Clients,
+		c.newRepository,
 		c.apiRequest,
 	)
 }
 
 func (c *Config) Watch(ctx context.Context) <-chan WatchResponse {
-	return serviceorchestration.WatchWithRepo(
+	if len(c.kubeContexts) > 1 {
+		return serviceorchestration.WatchClustersWithRepoContext(
+			ctx,
+			c.KubeConfig,
+			c.kubeContexts,
+			c.WatchPeriod,
+			c.Timeout,
+			c.Overrides.Clients,
+			c.newWatchRepository,
+			c.apiRequest,
+			mergeClusters,
+		)
+	}
+	return serviceorchestration.WatchWithRepoContext(
 		ctx,
 		c.KubeConfig,
-		c.KubeContext,
+		c.kubeContext(),
 		c.WatchPeriod,
 		c.Timeout,
-		client.Clients,
-		NewNodeRepository,
+		c.Overrides.Clients,
+		c.newWatchRepository,
 		c.apiRequest,
 	)
 }
 
+// kubeContext is the single context to query, a pattern matching one context
+// resolves to it.
+func (c *Config) kubeContext() string {
+	if len(c.kubeContexts) == 1 {
+		return c.kubeContexts[0]
+	}
+	return c.KubeContext
+}
+
 func (c *Config) prepare() error {
 	if c.KubeConfig == "" {
 		var err error
@@ -95,6 +187,11 @@ func (c *Config) prepare() error {
 			return err
 		}
 	}
+	kubeContexts, err := client.Contexts(c.KubeConfig, c.KubeContext)
+	if err != nil {
+		return err
+	}
+	c.kubeContexts = kubeContexts
 	return nil
 }
 
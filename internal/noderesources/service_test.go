@@ -86,6 +86,75 @@ type noopErrorProcessor struct{}
 
 func (noopErrorProcessor) Error(error) {}
 
+func TestConfigRefine(t *testing.T) {
+	node := func(cluster, name, zone string, schedulable bool) NodeResource {
+		return NodeResource{
+			Cluster:     cluster,
+			Name:        name,
+			Schedulable: schedulable,
+			Labels:      map[string]string{"zone": zone},
+		}
+	}
+	names := func(list NodeResourceList) []string {
+		result := make([]string, 0, len(list))
+		for _, node := range list {
+			result = append(result, node.Cluster+"/"+node.Name)
+		}
+		return result
+	}
+	list := NodeResourceList{
+		node("prod", "node-b", "a", true),
+		node("prod", "node-a", "a", true),
+		node("prod", "node-c", "b", false),
+		node("dev", "node-e", "a", true),
+		node("dev", "node-d", "a", true),
+	}
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected []string
+		err      string
+	}{
+		{
+			name:     "sorts within clusters keeping their order",
+			cfg:      Config{Sorting: "name", Alert: "none"},
+			expected: []string{"prod/node-a", "prod/node-b", "prod/node-c", "dev/node-d", "dev/node-e"},
+		},
+		{
+			name:     "label selector",
+			cfg:      Config{Label: "zone in (b)", Sorting: "name", Alert: "none"},
+			expected: []string{"prod/node-c"},
+		},
+		{
+			name:     "schedulable only",
+			cfg:      Config{Label: "zone=b", SchedulableOnly: true, Sorting: "name", Alert: "none"},
+			expected: []string{},
+		},
+		{
+			name:     "name",
+			cfg:      Config{Name: "node-d", Sorting: "name", Alert: "none"},
+			expected: []string{"dev/node-d"},
+		},
+		{
+			name: "invalid label selector",
+			cfg:  Config{Label: "zone in (", Sorting: "name", Alert: "none"},
+			err:  "invalid node label selector",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.cfg.Refine(list)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, names(result))
+		})
+	}
+}
+
 func TestProcessValidationError(t *testing.T) {
 	cfg := Config{KubeConfig: "dummy", Sorting: "invalid", Alert: "none"}
 
@@ -229,3 +298,58 @@ func TestFetchNodeMetricsFollowsPagination(t *testing.T) {
 	require.Equal(t, int64(200), byName["node-2"].CPURequest)
 	require.Equal(t, int64(400), byName["node-2"].UsedCPU)
 }
+
+func TestWatchRepositoryPollsOnlyMetrics(t *testing.T) {
+	ctx := t.Context()
+	coreClient := corefake.NewSimpleClientset(
+		&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: v1.NodeStatus{
+				Capacity:    v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+				Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("3")},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "default"},
+			Spec: v1.PodSpec{
+				NodeName: "node-1",
+				Containers: []v1.Container{{
+					Name: "app",
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+					},
+				}},
+			},
+		},
+	)
+	metricsClient := metricsfake.NewSimpleClientset()
+	metricsLists := 0
+	metricsClient.PrependReactor("list", "nodes", func(ktesting.Action) (bool, runtime.Object, error) {
+		metricsLists++
+		return true, &metricsv1beta1.NodeMetricsList{
+			Items: []metricsv1beta1.NodeMetrics{{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Usage:      v1.ResourceList{v1.ResourceCPU: resource.MustParse("300m")},
+			}},
+		}, nil
+	})
+
+	cfg := Config{Sorting: "name", Alert: "none"}
+	repo := cfg.newWatchRepository(ctx)
+	for range 2 {
+		result, err := cfg.apiRequest(ctx, repo, metricsClient.MetricsV1beta1(), coreClient.CoreV1())
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, int64(100), result[0].CPURequest)
+		require.Equal(t, int64(300), result[0].UsedCPU)
+	}
+
+	lists := map[string]int{}
+	for _, action := range coreClient.Actions() {
+		if action.GetVerb() == "list" {
+			lists[action.GetResource().Resource]++
+		}
+	}
+	require.Equal(t, map[string]int{"nodes": 1, "pods": 1}, lists)
+	require.Equal(t, 2, metricsLists)
+}
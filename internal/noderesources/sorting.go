@@ -104,6 +104,18 @@ func (n NodeResourceList) sortFreeStorageEphemeral(reversed bool) {
 	sortBy(n, reversed, func(resource NodeResource) int64 { return resource.FreeStorageEphemeral })
 }
 
+func (n NodeResourceList) sortRunningPods(reversed bool) {
+	sortBy(n, reversed, func(resource NodeResource) int64 { return resource.RunningPods })
+}
+
+func (n NodeResourceList) sortAllocatablePods(reversed bool) {
+	sortBy(n, reversed, func(resource NodeResource) int64 { return resource.AllocatablePods })
+}
+
+func (n NodeResourceList) sortFreePods(reversed bool) {
+	sortBy(n, reversed, func(resource NodeResource) int64 { return resource.FreePods })
+}
+
 func (n NodeResourceList) sort(by string, reversed bool) { //nolint:revive // it is ok
 	switch noderesources.Sorting(by) {
 	case noderesources.Name:
@@ -148,8 +160,37 @@ func (n NodeResourceList) sort(by string, reversed bool) { //nolint:revive // it
 		n.sortFreeStorage(reversed)
 	case noderesources.FreeStorageEphemeral:
 		n.sortFreeStorageEphemeral(reversed)
+	case noderesources.RunningPods:
+		n.sortRunningPods(reversed)
+	case noderesources.AllocatablePods:
+		n.sortAllocatablePods(reversed)
+	case noderesources.FreePods:
+		n.sortFreePods(reversed)
 	default:
+		if field, name, ok := noderesources.Extended(noderesources.Sorting(by)); ok {
+			n.sortExtended(reversed, field, name)
+			return
+		}
 		// keep current order on unknown sorting
 		return
 	}
 }
+
+func (n NodeResourceList) sortExtended(reversed bool, field noderesources.ExtendedField, name string) {
+	sortBy(n, reversed, func(resource NodeResource) int64 {
+		extended := resource.Extended[name]
+		switch field {
+		case noderesources.ExtendedTotal:
+			return extended.Capacity
+		case noderesources.ExtendedAllocatable:
+			return extended.Allocatable
+		case noderesources.ExtendedRequest:
+			return extended.Request
+		case noderesources.ExtendedLimit:
+			return extended.Limit
+		case noderesources.ExtendedAvailable:
+			return extended.Available
+		}
+		return 0
+	})
+}
@@ -0,0 +1,84 @@
+package qos
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	choiceutil "github.com/trezorg/k8spodsmetrics/internal/choices"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Class is a pod QoS class as accepted on the command line.
+type Class string
+
+const (
+	Guaranteed Class = "guaranteed"
+	Burstable  Class = "burstable"
+	BestEffort Class = "besteffort"
+)
+
+var choices = []Class{Guaranteed, Burstable, BestEffort}
+
+func Valid(classes ...Class) error {
+	for _, class := range classes {
+		if !choiceutil.Valid(class, choices) {
+			return fmt.Errorf("qos should be one of: %s", StringList(", "))
+		}
+	}
+	return nil
+}
+
+// FromStrings converts values case-insensitively, so Kubernetes spelling such
+// as BestEffort is accepted too.
+func FromStrings(values ...string) []Class {
+	result := make([]Class, 0, len(values))
+	for _, value := range values {
+		class := Class(strings.ToLower(value))
+		if !slices.Contains(result, class) {
+			result = append(result, class)
+		}
+	}
+	return result
+}
+
+func ToStrings(classes ...Class) []string {
+	result := make([]string, 0, len(classes))
+	for _, class := range classes {
+		result = append(result, string(class))
+	}
+	return result
+}
+
+// Of returns the class of a Kubernetes pod QoS class.
+func Of(class v1.PodQOSClass) Class {
+	return Class(strings.ToLower(string(class)))
+}
+
+// Matches reports whether class is one of classes. No classes match any pod.
+func Matches(class v1.PodQOSClass, classes []Class) bool {
+	return len(classes) == 0 || slices.Contains(classes, Of(class))
+}
+
+// EvictionRank orders classes by eviction under node pressure: BestEffort pods
+// are evicted first and Guaranteed ones last.
+func EvictionRank(class v1.PodQOSClass) int {
+	switch class {
+	case v1.PodQOSBestEffort:
+		return 0
+	case v1.PodQOSBurstable:
+		return 1
+	case v1.PodQOSGuaranteed:
+		return 2
+	default:
+		return len(choices)
+	}
+}
+
+func StringList(separator string) string {
+	return choiceutil.StringList(choices, separator)
+}
+
+func StringListDefault() string {
+	return StringList(choiceutil.DefaultSeparator)
+}
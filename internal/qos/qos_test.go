@@ -0,0 +1,31 @@
+package qos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestValid(t *testing.T) {
+	require.NoError(t, Valid(Guaranteed, Burstable, BestEffort))
+	require.ErrorContains(t, Valid(Class("critical")), "qos should be one of")
+}
+
+func TestFromStrings(t *testing.T) {
+	require.Equal(t, []Class{BestEffort, Burstable}, FromStrings("BestEffort", "burstable", "besteffort"))
+	require.Empty(t, FromStrings())
+	require.Equal(t, []string{"guaranteed"}, ToStrings(Guaranteed))
+}
+
+func TestMatches(t *testing.T) {
+	require.True(t, Matches(v1.PodQOSBurstable, nil))
+	require.True(t, Matches(v1.PodQOSBestEffort, []Class{BestEffort, Burstable}))
+	require.False(t, Matches(v1.PodQOSGuaranteed, []Class{BestEffort, Burstable}))
+}
+
+func TestEvictionRank(t *testing.T) {
+	require.Less(t, EvictionRank(v1.PodQOSBestEffort), EvictionRank(v1.PodQOSBurstable))
+	require.Less(t, EvictionRank(v1.PodQOSBurstable), EvictionRank(v1.PodQOSGuaranteed))
+	require.Less(t, EvictionRank(v1.PodQOSGuaranteed), EvictionRank(""))
+}
@@ -0,0 +1,130 @@
+package recommendations
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// podSpecPaths maps the workload kinds that can be patched to the path of
+// their pod template spec. Bare pods and jobs cannot change their resources
+// this way, a job's pod template is immutable.
+var podSpecPaths = map[string][]string{
+	"Deployment":            {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+type (
+	// Patch is a strategic merge patch setting the recommended resources of
+	// the containers of a workload.
+	Patch struct {
+		Namespace string `json:"namespace" yaml:"namespace"`
+		Kind      string `json:"kind" yaml:"kind"`
+		Name      string `json:"name" yaml:"name"`
+		Patch     any    `json:"patch" yaml:"patch"`
+	}
+
+	podSpecPatch struct {
+		InitContainers []containerPatch `json:"initContainers,omitempty" yaml:"initContainers,omitempty"`
+		Containers     []containerPatch `json:"containers,omitempty" yaml:"containers,omitempty"`
+	}
+
+	containerPatch struct {
+		Name      string         `json:"name" yaml:"name"`
+		Resources resourcesPatch `json:"resources" yaml:"resources"`
+	}
+
+	resourcesPatch struct {
+		Requests map[string]string `json:"requests,omitempty" yaml:"requests,omitempty"`
+		Limits   map[string]string `json:"limits,omitempty" yaml:"limits,omitempty"`
+	}
+)
+
+// Command returns the kubectl command applying the patch stored in file.
+func (p Patch) Command(file string) string {
+	return "kubectl -n " + p.Namespace + " patch " + strings.ToLower(p.Kind) + " " + p.Name +
+		" --type strategic --patch-file " + file
+}
+
+// Patches returns a patch per workload of a patchable kind. Init and sidecar
+// containers are patched under initContainers. Containers without any
+// recommended value are left out.
+func (r RecommendationList) Patches() []Patch {
+	var result []Patch
+	for start := 0; start < len(r); {
+		end := start + 1
+		for end < len(r) && sameWorkload(r[start], r[end]) {
+			end++
+		}
+		if patch, ok := newPatch(r[start:end]); ok {
+			result = append(result, patch)
+		}
+		start = end
+	}
+	return result
+}
+
+func sameWorkload(a, b Recommendation) bool {
+	return a.Namespace == b.Namespace && a.Kind == b.Kind && a.Workload == b.Workload
+}
+
+func newPatch(workload RecommendationList) (Patch, bool) {
+	path, ok := podSpecPaths[workload[0].Kind]
+	if !ok {
+		return Patch{}, false
+	}
+	var spec podSpecPatch
+	for _, recommendation := range workload {
+		container := containerPatch{Name: recommendation.Container, Resources: newResourcesPatch(recommendation.Recommended)}
+		if container.Resources.Requests == nil && container.Resources.Limits == nil {
+			continue
+		}
+		if recommendation.Type == pods.InitContainer || recommendation.Type == pods.SidecarContainer {
+			spec.InitContainers = append(spec.InitContainers, container)
+		} else {
+			spec.Containers = append(spec.Containers, container)
+		}
+	}
+	if spec.InitContainers == nil && spec.Containers == nil {
+		return Patch{}, false
+	}
+	var patch any = spec
+	for _, field := range slices.Backward(path) {
+		patch = map[string]any{field: patch}
+	}
+	return Patch{Namespace: workload[0].Namespace, Kind: workload[0].Kind, Name: workload[0].Workload, Patch: patch}, true
+}
+
+func newResourcesPatch(values Resources) resourcesPatch {
+	return resourcesPatch{Requests: quantities(values.Requests), Limits: quantities(values.Limits)}
+}
+
+func quantities(values Values) map[string]string {
+	var result map[string]string
+	if values.CPU != 0 {
+		result = map[string]string{"cpu": CPUQuantity(values.CPU)}
+	}
+	if values.Memory != 0 {
+		if result == nil {
+			result = map[string]string{}
+		}
+		result["memory"] = MemoryQuantity(values.Memory)
+	}
+	return result
+}
+
+// CPUQuantity formats millicores the way Kubernetes does, for example 250m or 2.
+func CPUQuantity(millicores int64) string {
+	return resource.NewMilliQuantity(millicores, resource.DecimalSI).String()
+}
+
+// MemoryQuantity formats bytes the way Kubernetes does, for example 128Mi.
+func MemoryQuantity(bytes int64) string {
+	return resource.NewQuantity(bytes, resource.BinarySI).String()
+}
@@ -0,0 +1,91 @@
+package recommendations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	"gopkg.in/yaml.v3"
+)
+
+func testRecommendations() RecommendationList {
+	recommended := Resources{
+		Requests: Values{CPU: 250, Memory: 128 << 20},
+		Limits:   Values{CPU: 1500, Memory: 192 << 20},
+	}
+	return RecommendationList{
+		{Namespace: "batch", Kind: "CronJob", Workload: "report", Container: "main", Recommended: recommended},
+		{Namespace: "default", Kind: "Deployment", Workload: "web", Container: "app", Recommended: recommended},
+		{
+			Namespace:   "default",
+			Kind:        "Deployment",
+			Workload:    "web",
+			Container:   "proxy",
+			Type:        pods.SidecarContainer,
+			Recommended: Resources{Requests: Values{CPU: 10}},
+		},
+		{Namespace: "default", Kind: "Job", Workload: "migrate", Container: "migrate", Recommended: recommended},
+		{Namespace: "default", Kind: "Pod", Workload: "debug", Container: "shell", Recommended: recommended},
+		{Namespace: "default", Kind: "StatefulSet", Workload: "db", Container: "db"},
+	}
+}
+
+func TestPatches(t *testing.T) {
+	patches := testRecommendations().Patches()
+	require.Len(t, patches, 2)
+	for _, patch := range patches {
+		require.NotEqual(t, "Job", patch.Kind, "a bare job cannot be patched")
+	}
+
+	cronJob, err := yaml.Marshal(patches[0].Patch)
+	require.NoError(t, err)
+	require.Equal(t, `spec:
+    jobTemplate:
+        spec:
+            template:
+                spec:
+                    containers:
+                        - name: main
+                          resources:
+                            requests:
+                                cpu: 250m
+                                memory: 128Mi
+                            limits:
+                                cpu: 1500m
+                                memory: 192Mi
+`, string(cronJob))
+
+	deployment, err := yaml.Marshal(patches[1].Patch)
+	require.NoError(t, err)
+	require.Equal(t, `spec:
+    template:
+        spec:
+            initContainers:
+                - name: proxy
+                  resources:
+                    requests:
+                        cpu: 10m
+            containers:
+                - name: app
+                  resources:
+                    requests:
+                        cpu: 250m
+                        memory: 128Mi
+                    limits:
+                        cpu: 1500m
+                        memory: 192Mi
+`, string(deployment))
+	require.Equal(t, "web", patches[1].Name)
+	require.Equal(
+		t,
+		"kubectl -n default patch deployment web --type strategic --patch-file web.yaml",
+		patches[1].Command("web.yaml"),
+	)
+}
+
+func TestQuantities(t *testing.T) {
+	require.Equal(t, "2", CPUQuantity(2000))
+	require.Equal(t, "1m", CPUQuantity(1))
+	require.Equal(t, "1Gi", MemoryQuantity(1<<30))
+	require.Equal(t, "100Mi", MemoryQuantity(100<<20))
+}
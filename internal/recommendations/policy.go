@@ -0,0 +1,143 @@
+package recommendations
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	choiceutil "github.com/trezorg/k8spodsmetrics/internal/choices"
+)
+
+// Statistic selects the usage percentile a policy starts from.
+type Statistic string
+
+const (
+	P50 Statistic = "p50"
+	P90 Statistic = "p90"
+	P99 Statistic = "p99"
+	Max Statistic = "max"
+	// None leaves the value out of the recommendation and the patch.
+	None Statistic = "none"
+
+	// mebibyte is the step memory recommendations are rounded up to.
+	mebibyte = 1 << 20
+)
+
+var statistics = []Statistic{P50, P90, P99, Max, None}
+
+// Policy turns usage percentiles into a request or limit: the selected
+// statistic multiplied by Factor.
+type Policy struct {
+	Statistic Statistic
+	Factor    float64
+}
+
+// Policies holds the policy of each recommended value.
+type Policies struct {
+	CPURequest    Policy
+	CPULimit      Policy
+	MemoryRequest Policy
+	MemoryLimit   Policy
+}
+
+const (
+	DefaultRequestPolicy = "p90"
+	DefaultLimitPolicy   = "max*1.2"
+)
+
+// DefaultPolicies requests the 90th percentile and limits to 120% of the
+// maximum seen.
+func DefaultPolicies() Policies {
+	request := Policy{Statistic: P90, Factor: 1}
+	limit := Policy{Statistic: Max, Factor: 1.2}
+	return Policies{CPURequest: request, CPULimit: limit, MemoryRequest: request, MemoryLimit: limit}
+}
+
+// ParsePolicy reads a policy such as p90, max or max*1.2.
+func ParsePolicy(value string) (Policy, error) {
+	statistic, factor, hasFactor := strings.Cut(strings.ToLower(strings.TrimSpace(value)), "*")
+	policy := Policy{Statistic: Statistic(strings.TrimSpace(statistic)), Factor: 1}
+	if !choiceutil.Valid(policy.Statistic, statistics) {
+		return Policy{}, fmt.Errorf(
+			"invalid policy %q, expected one of %s optionally followed by *factor, for example max*1.2",
+			value,
+			StringListDefault(),
+		)
+	}
+	if !hasFactor {
+		return policy, nil
+	}
+	if policy.Statistic == None {
+		return Policy{}, fmt.Errorf("invalid policy %q, %s takes no factor", value, None)
+	}
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(factor), 64)
+	if err != nil || parsed <= 0 || math.IsInf(parsed, 0) {
+		return Policy{}, fmt.Errorf("invalid policy %q, the factor should be a positive number", value)
+	}
+	policy.Factor = parsed
+	return policy, nil
+}
+
+func StringListDefault() string {
+	return choiceutil.StringList(statistics, choiceutil.DefaultSeparator)
+}
+
+func (p Policy) String() string {
+	if p.Factor == 1 || p.Statistic == None {
+		return string(p.Statistic)
+	}
+	return string(p.Statistic) + "*" + strconv.FormatFloat(p.Factor, 'f', -1, 64)
+}
+
+// apply returns the statistic of usage multiplied by the factor, rounded up.
+// None returns zero.
+func (p Policy) apply(usage Percentiles) int64 {
+	var value int64
+	switch p.Statistic {
+	case P50:
+		value = usage.P50
+	case P90:
+		value = usage.P90
+	case P99:
+		value = usage.P99
+	case Max:
+		value = usage.Max
+	case None:
+		return 0
+	}
+	return int64(math.Ceil(float64(value) * p.Factor))
+}
+
+// cpu recommends at least one millicore so an idle container keeps a request.
+func (p Policy) cpu(usage Percentiles) int64 {
+	if p.Statistic == None {
+		return 0
+	}
+	return max(p.apply(usage), 1)
+}
+
+// memory rounds up to whole mebibytes.
+func (p Policy) memory(usage Percentiles) int64 {
+	if p.Statistic == None {
+		return 0
+	}
+	value := max(p.apply(usage), 1)
+	return (value + mebibyte - 1) / mebibyte * mebibyte
+}
+
+// recommend applies the policies to usage. Limits below the recommended
+// requests are raised to them, as the API server rejects such containers.
+func (p Policies) recommend(usage Usage) Resources {
+	result := Resources{
+		Requests: Values{CPU: p.CPURequest.cpu(usage.CPU), Memory: p.MemoryRequest.memory(usage.Memory)},
+		Limits:   Values{CPU: p.CPULimit.cpu(usage.CPU), Memory: p.MemoryLimit.memory(usage.Memory)},
+	}
+	if result.Limits.CPU != 0 {
+		result.Limits.CPU = max(result.Limits.CPU, result.Requests.CPU)
+	}
+	if result.Limits.Memory != 0 {
+		result.Limits.Memory = max(result.Limits.Memory, result.Requests.Memory)
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package recommendations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected Policy
+		err      string
+	}{
+		{name: "percentile", value: "p90", expected: Policy{Statistic: P90, Factor: 1}},
+		{name: "maximum with factor", value: "max*1.2", expected: Policy{Statistic: Max, Factor: 1.2}},
+		{name: "spaces and case", value: " P99 * 1.5 ", expected: Policy{Statistic: P99, Factor: 1.5}},
+		{name: "none", value: "none", expected: Policy{Statistic: None, Factor: 1}},
+		{name: "unknown statistic", value: "p75", err: "invalid policy \"p75\""},
+		{name: "none with factor", value: "none*2", err: "takes no factor"},
+		{name: "zero factor", value: "max*0", err: "positive number"},
+		{name: "invalid factor", value: "max*x", err: "positive number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ParsePolicy(tt.value)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, policy)
+		})
+	}
+}
+
+func TestPolicyString(t *testing.T) {
+	require.Equal(t, "p90", Policy{Statistic: P90, Factor: 1}.String())
+	require.Equal(t, "max*1.2", Policy{Statistic: Max, Factor: 1.2}.String())
+	require.Equal(t, "none", Policy{Statistic: None}.String())
+}
+
+func TestPoliciesRecommend(t *testing.T) {
+	usage := Usage{
+		CPU:    Percentiles{P50: 40, P90: 90, P99: 120, Max: 150},
+		Memory: Percentiles{P50: 50 << 20, P90: 100<<20 + 1, P99: 110 << 20, Max: 120 << 20},
+	}
+
+	t.Run("default policies", func(t *testing.T) {
+		result := DefaultPolicies().recommend(usage)
+		require.Equal(t, Resources{
+			Requests: Values{CPU: 90, Memory: 101 << 20},
+			Limits:   Values{CPU: 180, Memory: 144 << 20},
+		}, result)
+	})
+
+	t.Run("none leaves values unset", func(t *testing.T) {
+		policies := DefaultPolicies()
+		policies.CPULimit = Policy{Statistic: None}
+		result := policies.recommend(usage)
+		require.Zero(t, result.Limits.CPU)
+		require.Equal(t, int64(90), result.Requests.CPU)
+	})
+
+	t.Run("limits are not below requests", func(t *testing.T) {
+		policies := Policies{
+			CPURequest:    Policy{Statistic: Max, Factor: 2},
+			CPULimit:      Policy{Statistic: P50, Factor: 1},
+			MemoryRequest: Policy{Statistic: Max, Factor: 1},
+			MemoryLimit:   Policy{Statistic: P50, Factor: 1},
+		}
+		result := policies.recommend(usage)
+		require.Equal(t, int64(300), result.Limits.CPU)
+		require.Equal(t, int64(120<<20), result.Limits.Memory)
+	})
+
+	t.Run("idle containers keep minimal values", func(t *testing.T) {
+		result := DefaultPolicies().recommend(Usage{})
+		require.Equal(t, Values{CPU: 1, Memory: 1 << 20}, result.Requests)
+	})
+}
+
+func TestDefaultPolicies(t *testing.T) {
+	policies := DefaultPolicies()
+	require.Equal(t, DefaultRequestPolicy, policies.CPURequest.String())
+	require.Equal(t, DefaultRequestPolicy, policies.MemoryRequest.String())
+	require.Equal(t, DefaultLimitPolicy, policies.CPULimit.String())
+	require.Equal(t, DefaultLimitPolicy, policies.MemoryLimit.String())
+}
@@ -0,0 +1,79 @@
+package recommendations
+
+import (
+	"slices"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+)
+
+type (
+	// Percentiles summarizes the usage samples of a container.
+	Percentiles struct {
+		P50 int64 `json:"p50" yaml:"p50"`
+		P90 int64 `json:"p90" yaml:"p90"`
+		P99 int64 `json:"p99" yaml:"p99"`
+		Max int64 `json:"max" yaml:"max"`
+	}
+
+	// Usage holds CPU percentiles in millicores and memory ones in bytes.
+	Usage struct {
+		CPU    Percentiles `json:"cpu" yaml:"cpu"`
+		Memory Percentiles `json:"memory" yaml:"memory"`
+	}
+
+	// Values holds CPU in millicores and memory in bytes. Zero means unset.
+	Values struct {
+		CPU    int64 `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+		Memory int64 `json:"memory,omitempty" yaml:"memory,omitempty"`
+	}
+
+	Resources struct {
+		Requests Values `json:"requests" yaml:"requests"`
+		Limits   Values `json:"limits" yaml:"limits"`
+	}
+
+	// Recommendation proposes requests and limits for a container of a
+	// workload from the usage sampled across its replicas.
+	Recommendation struct {
+		Namespace   string             `json:"namespace" yaml:"namespace"`
+		Kind        string             `json:"kind" yaml:"kind"`
+		Workload    string             `json:"workload" yaml:"workload"`
+		Container   string             `json:"container" yaml:"container"`
+		Type        pods.ContainerType `json:"type,omitempty" yaml:"type,omitempty"`
+		Samples     int                `json:"samples" yaml:"samples"`
+		Usage       Usage              `json:"usage" yaml:"usage"`
+		Current     Resources          `json:"current" yaml:"current"`
+		Recommended Resources          `json:"recommended" yaml:"recommended"`
+	}
+
+	RecommendationList []Recommendation
+
+	RecommendationListEnvelope struct {
+		Items   RecommendationList `json:"items,omitempty" yaml:"items,omitempty"`
+		Patches []Patch            `json:"patches,omitempty" yaml:"patches,omitempty"`
+	}
+)
+
+func NewRecommendationListEnvelope(list RecommendationList) RecommendationListEnvelope {
+	return RecommendationListEnvelope{Items: list, Patches: list.Patches()}
+}
+
+// newPercentiles returns the nearest-rank percentiles of samples, which are
+// sorted in place.
+func newPercentiles(samples []int64) Percentiles {
+	if len(samples) == 0 {
+		return Percentiles{}
+	}
+	slices.Sort(samples)
+	return Percentiles{
+		P50: nearestRank(samples, 50),
+		P90: nearestRank(samples, 90),
+		P99: nearestRank(samples, 99),
+		Max: samples[len(samples)-1],
+	}
+}
+
+func nearestRank(sorted []int64, percentile int) int64 {
+	rank := (percentile*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,116 @@
+package recommendations
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+	"github.com/trezorg/k8spodsmetrics/pkg/owners"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+)
+
+type containerKey struct {
+	reference owners.Reference
+	container string
+}
+
+type podContainerKey struct {
+	pod       pods.NamespaceName
+	container string
+}
+
+// series holds the usage samples of a container across the replicas of a
+// workload and the resources of its latest spec.
+type series struct {
+	containerType pods.ContainerType
+	cpu           []int64
+	memory        []int64
+	current       Resources
+}
+
+// sampler pools container usage per workload over several requests.
+type sampler struct {
+	series map[containerKey]*series
+	// collected keeps the timestamp of the last sample of each container so a
+	// metric reported again before the source refreshed it is counted once.
+	collected   map[podContainerKey]time.Time
+	samples     int
+	unavailable bool
+}
+
+func newSampler() *sampler {
+	return &sampler{series: map[containerKey]*series{}, collected: map[podContainerKey]time.Time{}}
+}
+
+// add records the usage of the running containers of list. Ephemeral
+// containers have no resources to recommend and are skipped.
+func (s *sampler) add(list metricsresources.PodMetricsResourceList, controllers owners.Controllers) {
+	s.unavailable = s.unavailable || list.MetricsUnavailable()
+	for _, pod := range list {
+		if pod.IsTerminated() || !pod.HasMetrics() {
+			continue
+		}
+		ref := workloads.Reference(pod.PodResource, controllers)
+		containers := pod.PodResource.Containers
+		for _, metric := range pod.PodMetric.Containers {
+			idx := slices.IndexFunc(containers, func(c pods.ContainerResource) bool { return c.Name == metric.Name })
+			if idx < 0 || containers[idx].Type == pods.EphemeralContainer ||
+				metric.CPU == podmetrics.Unset || metric.Memory == podmetrics.Unset {
+				continue
+			}
+			podKey := podContainerKey{pod: pod.NamespaceName, container: metric.Name}
+			if timestamp, ok := s.collected[podKey]; ok && !timestamp.IsZero() && timestamp.Equal(pod.PodMetric.Timestamp) {
+				continue
+			}
+			s.collected[podKey] = pod.PodMetric.Timestamp
+
+			spec := containers[idx]
+			key := containerKey{reference: ref, container: metric.Name}
+			current, ok := s.series[key]
+			if !ok {
+				current = &series{}
+				s.series[key] = current
+			}
+			current.containerType = spec.Type
+			current.cpu = append(current.cpu, metric.CPU)
+			current.memory = append(current.memory, metric.Memory)
+			current.current = Resources{
+				Requests: Values{CPU: spec.Requests.CPU, Memory: spec.Requests.Memory},
+				Limits:   Values{CPU: spec.Limits.CPU, Memory: spec.Limits.Memory},
+			}
+			s.samples++
+		}
+	}
+}
+
+// recommendations applies policies to the sampled usage, ordered by
+// namespace, workload and container.
+func (s *sampler) recommendations(policies Policies) RecommendationList {
+	result := make(RecommendationList, 0, len(s.series))
+	for key, samples := range s.series {
+		usage := Usage{CPU: newPercentiles(samples.cpu), Memory: newPercentiles(samples.memory)}
+		result = append(result, Recommendation{
+			Namespace:   key.reference.Namespace,
+			Kind:        key.reference.Kind,
+			Workload:    key.reference.Name,
+			Container:   key.container,
+			Type:        samples.containerType,
+			Samples:     len(samples.cpu),
+			Usage:       usage,
+			Current:     samples.current,
+			Recommended: policies.recommend(usage),
+		})
+	}
+	slices.SortFunc(result, func(a, b Recommendation) int {
+		return cmp.Or(
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Workload, b.Workload),
+			cmp.Compare(a.Container, b.Container),
+		)
+	})
+	return result
+}
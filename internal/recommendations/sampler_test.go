@@ -0,0 +1,95 @@
+package recommendations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/owners"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+)
+
+func testPod(name string, timestamp time.Time, cpu, memory int64) metricsresources.PodMetricsResource {
+	return metricsresources.PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Namespace: "default", Name: name},
+			NodeName:      "node1",
+			Owner:         pods.Owner{Kind: owners.KindReplicaSet, Name: "web-1"},
+			Containers: []pods.ContainerResource{
+				{
+					Name:     "app",
+					Requests: pods.Resource{CPU: 500, Memory: 256 << 20},
+					Limits:   pods.Resource{CPU: 1000, Memory: 512 << 20},
+				},
+				{Name: "proxy", Type: pods.SidecarContainer},
+				{Name: "debugger", Type: pods.EphemeralContainer},
+			},
+		},
+		PodMetric: podmetrics.PodMetric{
+			Name:      name,
+			Namespace: "default",
+			Timestamp: timestamp,
+			Containers: []podmetrics.ContainerMetric{
+				{Name: "app", Metric: podmetrics.Metric{CPU: cpu, Memory: memory}},
+				{Name: "proxy", Metric: podmetrics.Metric{CPU: 5, Memory: 10 << 20}},
+				{Name: "debugger", Metric: podmetrics.Metric{CPU: 1, Memory: 1 << 20}},
+			},
+		},
+	}
+}
+
+func TestNewPercentiles(t *testing.T) {
+	require.Equal(t, Percentiles{}, newPercentiles(nil))
+	require.Equal(t, Percentiles{P50: 7, P90: 7, P99: 7, Max: 7}, newPercentiles([]int64{7}))
+
+	samples := make([]int64, 0, 100)
+	for value := int64(100); value > 0; value-- {
+		samples = append(samples, value)
+	}
+	require.Equal(t, Percentiles{P50: 50, P90: 90, P99: 99, Max: 100}, newPercentiles(samples))
+}
+
+func TestSamplerRecommendations(t *testing.T) {
+	controllers := owners.Controllers{
+		{Namespace: "default", Kind: owners.KindReplicaSet, Name: "web-1"}: {
+			Namespace: "default", Kind: "Deployment", Name: "web",
+		},
+	}
+	first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(30 * time.Second)
+
+	s := newSampler()
+	s.add(metricsresources.PodMetricsResourceList{testPod("web-1-a", first, 100, 100<<20), testPod("web-1-b", first, 200, 200<<20)}, controllers)
+	// The source has not refreshed web-1-a yet.
+	s.add(metricsresources.PodMetricsResourceList{testPod("web-1-a", first, 100, 100<<20), testPod("web-1-b", second, 300, 300<<20)}, controllers)
+	s.add(metricsresources.PodMetricsResourceList{{PodResource: pods.PodResource{Phase: "Pending"}}}, controllers)
+
+	result := s.recommendations(DefaultPolicies())
+	require.Len(t, result, 2)
+	require.Equal(t, 6, s.samples)
+
+	app := result[0]
+	require.Equal(t, "Deployment", app.Kind)
+	require.Equal(t, "web", app.Workload)
+	require.Equal(t, "app", app.Container)
+	require.Equal(t, 3, app.Samples)
+	require.Equal(t, Percentiles{P50: 200, P90: 300, P99: 300, Max: 300}, app.Usage.CPU)
+	require.Equal(t, Values{CPU: 500, Memory: 256 << 20}, app.Current.Requests)
+	require.Equal(t, Values{CPU: 300, Memory: 300 << 20}, app.Recommended.Requests)
+	require.Equal(t, Values{CPU: 360, Memory: 360 << 20}, app.Recommended.Limits)
+
+	proxy := result[1]
+	require.Equal(t, "proxy", proxy.Container)
+	require.Equal(t, pods.SidecarContainer, proxy.Type)
+	require.Equal(t, 3, proxy.Samples)
+}
+
+func TestSamplerMetricsUnavailable(t *testing.T) {
+	s := newSampler()
+	s.add(metricsresources.PodMetricsResourceList{{MetricsUnavailable: true}}, owners.Controllers{})
+	require.True(t, s.unavailable)
+	require.Zero(t, s.samples)
+	require.Empty(t, s.recommendations(DefaultPolicies()))
+}
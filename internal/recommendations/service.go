@@ -0,0 +1,180 @@
+package recommendations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
+	"github.com/trezorg/k8spodsmetrics/internal/serviceorchestration"
+	"github.com/trezorg/k8spodsmetrics/internal/workloads"
+	"github.com/trezorg/k8spodsmetrics/pkg/client"
+	"github.com/trezorg/k8spodsmetrics/pkg/owners"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+const (
+	DefaultDuration = 10 * time.Minute
+	DefaultInterval = 30 * time.Second
+)
+
+// ErrNoSamples is returned when no container reported usage while sampling.
+var ErrNoSamples = errors.New("no usage samples were collected")
+
+type Config struct {
+	KubeConfig    string
+	KubeContext   string
+	Overrides     client.Overrides
+	Namespaces    []string
+	AllNamespaces bool
+	Label         string
+	FieldSelector string
+	Nodes         []string
+	MetricsSource metricssource.Config
+	// Duration is how long usage is sampled for, one sample every Interval.
+	Duration time.Duration
+	Interval time.Duration
+	Policies Policies
+	Timeout  uint
+	Retry    retry.Policy
+}
+
+// sample is the usage of a single request with the controllers of its pods.
+type sample struct {
+	pods        metricsresources.PodMetricsResourceList
+	controllers owners.Controllers
+}
+
+func (c Config) Validate() error {
+	if c.Duration <= 0 {
+		return errors.New("sampling duration must be greater than 0")
+	}
+	if c.Interval <= 0 {
+		return errors.New("sampling interval must be greater than 0")
+	}
+	if c.Interval > c.Duration {
+		return fmt.Errorf("sampling interval %s must not exceed the duration %s", c.Interval, c.Duration)
+	}
+	return c.MetricsSource.Validate()
+}
+
+func (c Config) apiRequest(
+	ctx context.Context,
+	repo workloads.WorkloadRepository,
+	metricsClient metricsv1beta1.MetricsV1beta1Interface,
+	podsClient corev1.CoreV1Interface,
+) (sample, error) {
+	ctx = retry.WithPolicy(ctx, c.Retry)
+	fetchConfig := metricsresources.FetchConfig{
+		Namespaces:    c.Namespaces,
+		Label:         c.Label,
+		FieldSelector: c.FieldSelector,
+		Nodes:         c.Nodes,
+	}
+	podMetricsResourceList, err := metricsresources.FetchPodMetrics(ctx, repo, metricsClient, podsClient, fetchConfig)
+	if err != nil {
+		return sample{}, err
+	}
+	podMetricsResourceList = podMetricsResourceList.FilterNodes(c.Nodes)
+	controllers, err := workloads.ResolveControllers(ctx, repo, podMetricsResourceList, c.Namespaces)
+	if err != nil {
+		return sample{}, err
+	}
+	return sample{pods: podMetricsResourceList, controllers: controllers}, nil
+}
+
+func (c *Config) newRepository(ctx context.Context) workloads.WorkloadRepository {
+	return workloads.NewCachedWorkloadRepository(ctx, c.KubeConfig, c.KubeContext, c.Overrides, c.MetricsSource)
+}
+
+// Request samples usage every Interval until Duration passes and recommends
+// resources from the samples. Failed samples are logged and skipped.
+func (c *Config) Request(ctx context.Context) (RecommendationList, error) {
+	samplingCtx, cancel := context.WithTimeout(ctx, c.Duration)
+	defer cancel()
+
+	slog.Info("Sampling pod usage", "duration", c.Duration, "interval", c.Interval)
+	s := newSampler()
+	var lastErr error
+	responses := serviceorchestration.SampleWithRepoContext(
+		samplingCtx,
+		c.KubeConfig,
+		c.KubeContext,
+		c.Interval,
+		c.Timeout,
+		c.Overrides.Clients,
+		c.newRepository,
+		c.apiRequest,
+	)
+	for response := range responses {
+		if response.Error != nil {
+			// Requests cut short by the end of the sampling window are not failures.
+			if samplingCtx.Err() == nil {
+				slog.Warn("Cannot sample pod usage", "error", response.Error)
+				lastErr = response.Error
+			}
+			continue
+		}
+		s.add(response.Data.pods, response.Data.controllers)
+		slog.Debug("Sampled pod usage", "samples", s.samples)
+	}
+	if ctx.Err() != nil {
+		return nil, context.Cause(ctx)
+	}
+	if s.samples == 0 {
+		switch {
+		case s.unavailable:
+			return nil, fmt.Errorf("%w: %w", ErrNoSamples, metricssource.ErrUnavailable)
+		case lastErr != nil:
+			return nil, fmt.Errorf("%w: %w", ErrNoSamples, lastErr)
+		}
+		return nil, ErrNoSamples
+	}
+	return s.recommendations(c.Policies), nil
+}
+
+func (c *Config) prepare() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.KubeConfig == "" {
+		var err error
+		c.KubeConfig, err = client.FindKubeConfig()
+		if err != nil {
+			return err
+		}
+	}
+	kubeContext, err := client.SingleContext(c.KubeConfig, c.KubeContext)
+	if err != nil {
+		return err
+	}
+	c.KubeContext = kubeContext
+	if len(c.Namespaces) > 0 || c.AllNamespaces {
+		return nil
+	}
+	namespace, err := client.ContextNamespace(c.KubeConfig, kubeContext)
+	if err != nil {
+		return err
+	}
+	if namespace != "" {
+		c.Namespaces = []string{namespace}
+	}
+	return nil
+}
+
+func (c *Config) Process(successProcessor SuccessProcessor) error {
+	return serviceorchestration.ProcessRequest(c.prepare, c.Request, successProcessor.Success)
+}
+
+type SuccessProcessor interface {
+	Success(RecommendationList)
+}
+
+type ErrorProcessor interface {
+	Error(error)
+}
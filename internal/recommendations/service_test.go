@@ -0,0 +1,89 @@
+package recommendations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/owners"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+type stubWorkloadRepository struct {
+	pods             pods.PodResourceList
+	metrics          podmetrics.PodMetricList
+	fetchControllers func(namespace string) (owners.Controllers, error)
+}
+
+func (s stubWorkloadRepository) FetchPods(
+	context.Context,
+	corev1.CoreV1Interface,
+	pods.PodFilter,
+	...string,
+) (pods.PodResourceList, error) {
+	return s.pods, nil
+}
+
+func (s stubWorkloadRepository) FetchMetrics(
+	context.Context,
+	metricsv1beta1.MetricsV1beta1Interface,
+	corev1.CoreV1Interface,
+	podmetrics.MetricFilter,
+) (podmetrics.PodMetricList, error) {
+	return s.metrics, nil
+}
+
+func (s stubWorkloadRepository) FetchControllers(_ context.Context, namespace string) (owners.Controllers, error) {
+	if s.fetchControllers != nil {
+		return s.fetchControllers(namespace)
+	}
+	return owners.Controllers{}, nil
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, Config{Duration: time.Minute, Interval: time.Second}.Validate())
+	require.ErrorContains(t, Config{Interval: time.Second}.Validate(), "duration must be greater than 0")
+	require.ErrorContains(t, Config{Duration: time.Minute}.Validate(), "interval must be greater than 0")
+	require.ErrorContains(t, Config{Duration: time.Second, Interval: time.Minute}.Validate(), "must not exceed")
+}
+
+func TestAPIRequest(t *testing.T) {
+	pod := testPod("web-1-a", time.Now(), 100, 100<<20)
+	repo := stubWorkloadRepository{
+		pods:    pods.PodResourceList{pod.PodResource},
+		metrics: podmetrics.PodMetricList{pod.PodMetric},
+	}
+
+	t.Run("resolves owners", func(t *testing.T) {
+		var requested []string
+		repo.fetchControllers = func(namespace string) (owners.Controllers, error) {
+			requested = append(requested, namespace)
+			return owners.Controllers{
+				{Namespace: "default", Kind: owners.KindReplicaSet, Name: "web-1"}: {
+					Namespace: "default", Kind: "Deployment", Name: "web",
+				},
+			}, nil
+		}
+
+		result, err := Config{Namespaces: []string{"default"}}.apiRequest(t.Context(), repo, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"default"}, requested)
+		require.Len(t, result.pods, 1)
+		require.Equal(t, "web", result.controllers.Resolve(owners.Reference{
+			Namespace: "default", Kind: owners.KindReplicaSet, Name: "web-1",
+		}).Name)
+	})
+
+	t.Run("returns owner errors", func(t *testing.T) {
+		rootErr := errors.New("forbidden")
+		repo.fetchControllers = func(string) (owners.Controllers, error) { return nil, rootErr }
+
+		_, err := Config{}.apiRequest(t.Context(), repo, nil, nil)
+		require.ErrorIs(t, err, rootErr)
+	})
+}
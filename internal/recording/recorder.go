@@ -0,0 +1,123 @@
+package recording
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+)
+
+// Recorder appends snapshots to a writer, one JSON line each. The first
+// failed write stops recording and is returned by Close, results keep being
+// rendered. A nil Recorder records nothing.
+type Recorder struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	now    func() time.Time
+	err    error
+}
+
+// NewRecorder records to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w, now: time.Now}
+}
+
+// Open appends snapshots to the file at path, creating it if needed. An empty
+// path returns a nil Recorder.
+func Open(path string) (*Recorder, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open recording: %w", err)
+	}
+	recorder := NewRecorder(file)
+	recorder.closer = file
+	return recorder, nil
+}
+
+// Record appends snapshot.
+func (r *Recorder) Record(snapshot Snapshot) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	line, err := json.Marshal(snapshot)
+	if err == nil {
+		_, err = r.w.Write(append(line, '\n'))
+	}
+	if err != nil {
+		r.err = fmt.Errorf("cannot record %s: %w", snapshot.Kind, err)
+		slog.Error("Recording stopped", slog.String("error", err.Error()))
+	}
+}
+
+// Close closes the recording file and returns the error that stopped
+// recording, if any.
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var closeErr error
+	if r.closer != nil {
+		closeErr = r.closer.Close()
+	}
+	return errors.Join(r.err, closeErr)
+}
+
+// Pods records pods before passing them to next. namespaces returns the
+// namespaces the pods were requested from, all namespaces when empty.
+func (r *Recorder) Pods(
+	next metricsresources.SuccessProcessor,
+	namespaces func() []string,
+) metricsresources.SuccessProcessor {
+	if r == nil {
+		return next
+	}
+	return podsRecorder{recorder: r, next: next, namespaces: namespaces}
+}
+
+// Nodes records nodes before passing them to next.
+func (r *Recorder) Nodes(next noderesources.SuccessProcessor) noderesources.SuccessProcessor {
+	if r == nil {
+		return next
+	}
+	return nodesRecorder{recorder: r, next: next}
+}
+
+type podsRecorder struct {
+	recorder   *Recorder
+	next       metricsresources.SuccessProcessor
+	namespaces func() []string
+}
+
+func (p podsRecorder) Success(list metricsresources.PodMetricsResourceList, warnings partial.Warnings) {
+	p.recorder.Record(NewPodSnapshot(p.recorder.now(), p.namespaces(), list, warnings))
+	p.next.Success(list, warnings)
+}
+
+type nodesRecorder struct {
+	recorder *Recorder
+	next     noderesources.SuccessProcessor
+}
+
+func (n nodesRecorder) Success(list noderesources.NodeResourceList) {
+	n.recorder.Record(NewNodeSnapshot(n.recorder.now(), list))
+	n.next.Success(list)
+}
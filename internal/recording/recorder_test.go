@@ -0,0 +1,131 @@
+package recording
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+)
+
+type podsProcessor struct {
+	lists []metricsresources.PodMetricsResourceList
+}
+
+func (p *podsProcessor) Success(list metricsresources.PodMetricsResourceList, _ partial.Warnings) {
+	p.lists = append(p.lists, list)
+}
+
+type nodesProcessor struct {
+	lists []noderesources.NodeResourceList
+}
+
+func (n *nodesProcessor) Success(list noderesources.NodeResourceList) {
+	n.lists = append(n.lists, list)
+}
+
+type failingWriter struct {
+	writes int
+}
+
+func (f *failingWriter) Write([]byte) (int, error) {
+	f.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestRecorderPods(t *testing.T) {
+	var buffer bytes.Buffer
+	recorder := NewRecorder(&buffer)
+	ticks := []time.Time{recordedAt, recordedAt.Add(5 * time.Second)}
+	recorder.now = func() time.Time {
+		now := ticks[0]
+		ticks = ticks[1:]
+		return now
+	}
+	next := &podsProcessor{}
+	processor := recorder.Pods(next, func() []string { return []string{"default"} })
+
+	processor.Success(testPods(), nil)
+	processor.Success(testPods(), nil)
+	require.NoError(t, recorder.Close())
+
+	require.Len(t, next.lists, 2)
+	snapshots, err := Read(&buffer)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	require.True(t, recordedAt.Equal(snapshots[0].Timestamp))
+	require.True(t, recordedAt.Add(5*time.Second).Equal(snapshots[1].Timestamp))
+	require.Equal(t, []string{"default"}, snapshots[0].Namespaces)
+}
+
+func TestRecorderNodes(t *testing.T) {
+	var buffer bytes.Buffer
+	recorder := NewRecorder(&buffer)
+	next := &nodesProcessor{}
+
+	recorder.Nodes(next).Success(noderesources.NodeResourceList{{Name: "node-1"}})
+
+	require.Len(t, next.lists, 1)
+	snapshots, err := Read(&buffer)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.Equal(t, Nodes, snapshots[0].Kind)
+}
+
+func TestNilRecorder(t *testing.T) {
+	var recorder *Recorder
+	pods := &podsProcessor{}
+	nodes := &nodesProcessor{}
+
+	require.Same(t, pods, recorder.Pods(pods, nil))
+	require.Same(t, nodes, recorder.Nodes(nodes))
+	require.NoError(t, recorder.Close())
+}
+
+func TestRecorderStopsOnWriteError(t *testing.T) {
+	writer := &failingWriter{}
+	recorder := NewRecorder(writer)
+	next := &podsProcessor{}
+	processor := recorder.Pods(next, func() []string { return nil })
+
+	processor.Success(testPods(), nil)
+	processor.Success(testPods(), nil)
+
+	require.Len(t, next.lists, 2, "results are rendered when recording fails")
+	require.Equal(t, 1, writer.writes)
+	require.ErrorContains(t, recorder.Close(), "cannot record pods: disk full")
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("empty path records nothing", func(t *testing.T) {
+		recorder, err := Open("")
+		require.NoError(t, err)
+		require.Nil(t, recorder)
+	})
+
+	t.Run("appends to existing recording", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "recording.jsonl")
+		for range 2 {
+			recorder, err := Open(path)
+			require.NoError(t, err)
+			recorder.Record(NewNodeSnapshot(recordedAt, nil))
+			require.NoError(t, recorder.Close())
+		}
+
+		snapshots, err := ReadFile(path, Nodes)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 2)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		_, err := Open(filepath.Join(t.TempDir(), "missing", "recording.jsonl"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -0,0 +1,133 @@
+// Package recording saves pod and node results as JSON lines and reads them
+// back for replay. Every line is a snapshot of one request or watch tick.
+package recording
+
+import (
+	"time"
+
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+)
+
+// Version is the snapshot format written by this build. Snapshots of other
+// versions are rejected on replay.
+const Version = 1
+
+// Kind is the command a snapshot was recorded by.
+type Kind string
+
+const (
+	Pods  Kind = "pods"
+	Nodes Kind = "nodes"
+)
+
+// Snapshot is one recorded result. Pods and Nodes hold the result before
+// output filtering such as columns or resources, so replay can render it in
+// any form. Namespaces are the namespaces the pods were requested from, empty
+// for all namespaces.
+type Snapshot struct {
+	Version    int              `json:"version"`
+	Kind       Kind             `json:"kind"`
+	Timestamp  time.Time        `json:"timestamp"`
+	Namespaces []string         `json:"namespaces,omitempty"`
+	Warnings   partial.Warnings `json:"warnings,omitempty"`
+	Pods       []PodRecord      `json:"pods,omitempty"`
+	Nodes      []NodeRecord     `json:"nodes,omitempty"`
+}
+
+// PodRecord keeps the pod spec and usage apart, the rendered pod form merges
+// them and cannot be read back.
+type PodRecord struct {
+	Pod                pods.PodResource `json:"pod"`
+	Metric             MetricRecord     `json:"metric"`
+	MetricsStale       bool             `json:"metrics_stale,omitempty"`
+	MetricsUnavailable bool             `json:"metrics_unavailable,omitempty"`
+	Cluster            string           `json:"cluster,omitempty"`
+}
+
+// MetricRecord is podmetrics.PodMetric with JSON names. It is empty for pods
+// the metrics source reported nothing for.
+type MetricRecord struct {
+	Namespace        string                       `json:"namespace,omitempty"`
+	Name             string                       `json:"name,omitempty"`
+	Containers       []podmetrics.ContainerMetric `json:"containers,omitempty"`
+	StorageEphemeral int64                        `json:"storage_ephemeral,omitempty"`
+	Volumes          []podmetrics.VolumeMetric    `json:"volumes,omitempty"`
+	Timestamp        time.Time                    `json:"timestamp,omitzero"`
+	Window           time.Duration                `json:"window,omitempty"`
+}
+
+// NodeRecord is a node with its labels, which the rendered node form leaves
+// out and grouping by label needs.
+type NodeRecord struct {
+	noderesources.NodeResource
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// NewPodSnapshot records pods requested from namespaces, all namespaces when
+// empty, and the warnings of a partial result taken at timestamp.
+func NewPodSnapshot(
+	timestamp time.Time,
+	namespaces []string,
+	list metricsresources.PodMetricsResourceList,
+	warnings partial.Warnings,
+) Snapshot {
+	records := make([]PodRecord, 0, len(list))
+	for _, pod := range list {
+		records = append(records, PodRecord{
+			Pod:                pod.PodResource,
+			Metric:             MetricRecord(pod.PodMetric),
+			MetricsStale:       pod.MetricsStale,
+			MetricsUnavailable: pod.MetricsUnavailable,
+			Cluster:            pod.Cluster,
+		})
+	}
+	return Snapshot{
+		Version:    Version,
+		Kind:       Pods,
+		Timestamp:  timestamp,
+		Namespaces: namespaces,
+		Warnings:   warnings,
+		Pods:       records,
+	}
+}
+
+// NewNodeSnapshot records nodes taken at timestamp.
+func NewNodeSnapshot(timestamp time.Time, list noderesources.NodeResourceList) Snapshot {
+	records := make([]NodeRecord, 0, len(list))
+	for _, node := range list {
+		records = append(records, NodeRecord{NodeResource: node, Labels: node.Labels})
+	}
+	return Snapshot{Version: Version, Kind: Nodes, Timestamp: timestamp, Nodes: records}
+}
+
+// PodList returns the recorded pods.
+func (s Snapshot) PodList() metricsresources.PodMetricsResourceList {
+	list := make(metricsresources.PodMetricsResourceList, 0, len(s.Pods))
+	for _, record := range s.Pods {
+		list = append(list, metricsresources.PodMetricsResource{
+			PodResource:        record.Pod,
+			PodMetric:          podmetrics.PodMetric(record.Metric),
+			MetricsStale:       record.MetricsStale,
+			MetricsUnavailable: record.MetricsUnavailable,
+			Cluster:            record.Cluster,
+			ListedAt:           s.Timestamp,
+		})
+	}
+	return list
+}
+
+// NodeList returns the recorded nodes.
+func (s Snapshot) NodeList() noderesources.NodeResourceList {
+	list := make(noderesources.NodeResourceList, 0, len(s.Nodes))
+	for _, record := range s.Nodes {
+		node := record.NodeResource
+		node.Labels = record.Labels
+		node.ListedAt = s.Timestamp
+		list = append(list, node)
+	}
+	return list
+}
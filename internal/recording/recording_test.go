@@ -0,0 +1,93 @@
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/noderesources"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	v1 "k8s.io/api/core/v1"
+)
+
+var recordedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func testPods() metricsresources.PodMetricsResourceList {
+	return metricsresources.PodMetricsResourceList{
+		{
+			PodResource: pods.PodResource{
+				NamespaceName: pods.NamespaceName{Namespace: "default", Name: "web-1"},
+				NodeName:      "node-1",
+				QOSClass:      v1.PodQOSBurstable,
+				Owner:         pods.Owner{Kind: "ReplicaSet", Name: "web-5d8f"},
+				Containers: []pods.ContainerResource{{
+					Name:     "app",
+					Requests: pods.Resource{CPU: 100, Memory: 128 << 20},
+					Limits:   pods.Resource{CPU: 200, Memory: 256 << 20},
+					Restarts: 2,
+				}},
+			},
+			PodMetric: podmetrics.PodMetric{
+				Namespace: "default",
+				Name:      "web-1",
+				Containers: []podmetrics.ContainerMetric{{
+					Name:   "app",
+					Metric: podmetrics.Metric{CPU: 150, Memory: 200 << 20},
+				}},
+				Timestamp: recordedAt.Add(-30 * time.Second),
+				Window:    15 * time.Second,
+			},
+			MetricsStale: true,
+			Cluster:      "prod",
+		},
+	}
+}
+
+func TestPodSnapshotRoundTrip(t *testing.T) {
+	warnings := partial.Warnings{{Scope: partial.Namespace, Name: "kube-system", Message: "forbidden"}}
+	snapshot := NewPodSnapshot(recordedAt, []string{"default", "kube-system"}, testPods(), warnings)
+	require.Equal(t, Version, snapshot.Version)
+	require.Equal(t, Pods, snapshot.Kind)
+
+	line, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	snapshots, err := Read(bytes.NewReader(line))
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+
+	require.True(t, recordedAt.Equal(snapshots[0].Timestamp))
+	require.Equal(t, warnings, snapshots[0].Warnings)
+	require.Equal(t, []string{"default", "kube-system"}, snapshots[0].Namespaces)
+	expected := testPods()
+	for i := range expected {
+		expected[i].ListedAt = recordedAt
+	}
+	require.Equal(t, expected, snapshots[0].PodList(), "pods are listed at the snapshot time")
+}
+
+func TestNodeSnapshotKeepsLabels(t *testing.T) {
+	list := noderesources.NodeResourceList{{
+		Name:           "node-1",
+		AllocatableCPU: 4000,
+		UsedCPU:        1200,
+		Schedulable:    true,
+		Labels:         map[string]string{"topology.kubernetes.io/zone": "a"},
+	}}
+
+	line, err := json.Marshal(NewNodeSnapshot(recordedAt, list))
+	require.NoError(t, err)
+	require.Contains(t, string(line), `"labels":{"topology.kubernetes.io/zone":"a"}`)
+
+	snapshots, err := Read(bytes.NewReader(line))
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.Equal(t, Nodes, snapshots[0].Kind)
+	list[0].ListedAt = recordedAt
+	require.Equal(t, list, snapshots[0].NodeList())
+}
@@ -0,0 +1,180 @@
+package recording
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/trezorg/k8spodsmetrics/internal/serviceorchestration"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+)
+
+var ErrNoSnapshots = errors.New("no snapshots recorded")
+
+// Read decodes the snapshots of a recording in the order they were recorded,
+// one per line. An incomplete last line, left when recording was interrupted,
+// is skipped with a warning.
+func Read(r io.Reader) ([]Snapshot, error) {
+	reader := bufio.NewReader(r)
+	var result []Snapshot
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("snapshot %d: %w", len(result)+1, err)
+		}
+		last := err != nil
+		if len(bytes.TrimSpace(line)) > 0 {
+			var snapshot Snapshot
+			if err := json.Unmarshal(line, &snapshot); err != nil {
+				if last {
+					slog.Warn("Skipping incomplete last snapshot", slog.Int("snapshot", len(result)+1), slog.String("error", err.Error()))
+					return result, nil
+				}
+				return nil, fmt.Errorf("snapshot %d: %w", len(result)+1, err)
+			}
+			if snapshot.Version != Version {
+				return nil, fmt.Errorf("snapshot %d: unsupported version %d, expected %d", len(result)+1, snapshot.Version, Version)
+			}
+			result = append(result, snapshot)
+		}
+		if last {
+			return result, nil
+		}
+	}
+}
+
+// ReadAll reads the snapshots of all kinds from the recording at path.
+func ReadAll(path string) ([]Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open recording: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	snapshots, err := Read(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read recording %s: %w", path, err)
+	}
+	return snapshots, nil
+}
+
+// ReadFile reads the snapshots of kind from the recording at path.
+func ReadFile(path string, kind Kind) ([]Snapshot, error) {
+	snapshots, err := ReadAll(path)
+	if err != nil {
+		return nil, err
+	}
+	snapshots = Filter(snapshots, kind)
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("%w: %s has no %s snapshots", ErrNoSnapshots, path, kind)
+	}
+	return snapshots, nil
+}
+
+// Filter keeps the snapshots of kind.
+func Filter(snapshots []Snapshot, kind Kind) []Snapshot {
+	var result []Snapshot
+	for _, snapshot := range snapshots {
+		if snapshot.Kind == kind {
+			result = append(result, snapshot)
+		}
+	}
+	return result
+}
+
+// At returns the last snapshot taken at or before at, the last snapshot for
+// a zero at.
+func At(snapshots []Snapshot, at time.Time) (Snapshot, error) {
+	if len(snapshots) == 0 {
+		return Snapshot{}, ErrNoSnapshots
+	}
+	if at.IsZero() {
+		return snapshots[len(snapshots)-1], nil
+	}
+	i, err := indexAt(snapshots, at)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return snapshots[i], nil
+}
+
+// From returns the snapshots starting with the last one taken at or before
+// at, all snapshots for a zero at.
+func From(snapshots []Snapshot, at time.Time) ([]Snapshot, error) {
+	if len(snapshots) == 0 {
+		return nil, ErrNoSnapshots
+	}
+	if at.IsZero() {
+		return snapshots, nil
+	}
+	i, err := indexAt(snapshots, at)
+	if err != nil {
+		return nil, err
+	}
+	return snapshots[i:], nil
+}
+
+func indexAt(snapshots []Snapshot, at time.Time) (int, error) {
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if !snapshots[i].Timestamp.After(at) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%w at or before %s, the first one is at %s",
+		ErrNoSnapshots, at.Format(time.RFC3339), snapshots[0].Timestamp.Format(time.RFC3339))
+}
+
+// Play returns a watch of snapshots converted by convert. Snapshots are sent
+// as far apart as they were recorded divided by speed, warnings come as a
+// *partial.Error like those of a live partial watch. The channel is closed
+// after the last snapshot or when ctx is done.
+func Play[T any](
+	snapshots []Snapshot,
+	speed float64,
+	convert func(Snapshot) (T, error),
+) func(context.Context) <-chan serviceorchestration.WatchResponse[T] {
+	return func(ctx context.Context) <-chan serviceorchestration.WatchResponse[T] {
+		out := make(chan serviceorchestration.WatchResponse[T])
+		go func() {
+			defer close(out)
+			for i, snapshot := range snapshots {
+				if i > 0 && !wait(ctx, snapshot.Timestamp.Sub(snapshots[i-1].Timestamp), speed) {
+					return
+				}
+				var response serviceorchestration.WatchResponse[T]
+				response.Data, response.Error = convert(snapshot)
+				if response.Error == nil && len(snapshot.Warnings) > 0 {
+					response.Error = &partial.Error{Warnings: snapshot.Warnings}
+				}
+				select {
+				case out <- response:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		return out
+	}
+}
+
+// wait sleeps for delay divided by speed and reports whether ctx is still
+// running.
+func wait(ctx context.Context, delay time.Duration, speed float64) bool {
+	if delay <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(time.Duration(float64(delay) / speed))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
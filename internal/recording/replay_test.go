@@ -0,0 +1,165 @@
+package recording
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+)
+
+func snapshotsAt(offsets ...time.Duration) []Snapshot {
+	result := make([]Snapshot, 0, len(offsets))
+	for _, offset := range offsets {
+		result = append(result, Snapshot{Version: Version, Kind: Nodes, Timestamp: recordedAt.Add(offset)})
+	}
+	return result
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+		err      string
+	}{
+		{name: "empty", input: "", expected: 0},
+		{
+			name:     "several lines",
+			input:    "{\"version\":1,\"kind\":\"pods\"}\n\n{\"version\":1,\"kind\":\"nodes\"}\n",
+			expected: 2,
+		},
+		{name: "unsupported version", input: `{"version":2,"kind":"pods"}`, err: "snapshot 1: unsupported version 2, expected 1"},
+		{name: "invalid json", input: "{\"version\":1}\n{\n{\"version\":1}\n", err: "snapshot 2"},
+		{
+			name:     "incomplete last line",
+			input:    "{\"version\":1,\"kind\":\"pods\"}\n{\"version\":1,\"kind\":\"po",
+			expected: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshots, err := Read(strings.NewReader(tt.input))
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, snapshots, tt.expected)
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+	content := "{\"version\":1,\"kind\":\"pods\"}\n{\"version\":1,\"kind\":\"nodes\"}\n{\"version\":1,\"kind\":\"pods\"}\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	snapshots, err := ReadFile(path, Pods)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+
+	nodesOnly := filepath.Join(t.TempDir(), "nodes.jsonl")
+	require.NoError(t, os.WriteFile(nodesOnly, []byte("{\"version\":1,\"kind\":\"nodes\"}\n"), 0o644))
+	_, err = ReadFile(nodesOnly, Pods)
+	require.ErrorIs(t, err, ErrNoSnapshots)
+
+	_, err = ReadFile(filepath.Join(t.TempDir(), "missing.jsonl"), Pods)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestAtAndFrom(t *testing.T) {
+	snapshots := snapshotsAt(0, time.Minute, 2*time.Minute)
+	tests := []struct {
+		name  string
+		at    time.Time
+		index int
+		err   bool
+	}{
+		{name: "zero is the last", at: time.Time{}, index: 2},
+		{name: "exact time", at: recordedAt.Add(time.Minute), index: 1},
+		{name: "between snapshots", at: recordedAt.Add(90 * time.Second), index: 1},
+		{name: "after the last", at: recordedAt.Add(time.Hour), index: 2},
+		{name: "before the first", at: recordedAt.Add(-time.Second), err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, err := At(snapshots, tt.at)
+			if tt.err {
+				require.ErrorIs(t, err, ErrNoSnapshots)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, snapshots[tt.index], snapshot)
+		})
+	}
+
+	t.Run("from zero plays all", func(t *testing.T) {
+		result, err := From(snapshots, time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, snapshots, result)
+	})
+
+	t.Run("from starts at the snapshot shown at that time", func(t *testing.T) {
+		result, err := From(snapshots, recordedAt.Add(90*time.Second))
+		require.NoError(t, err)
+		require.Equal(t, snapshots[1:], result)
+	})
+
+	t.Run("no snapshots", func(t *testing.T) {
+		_, err := At(nil, time.Time{})
+		require.ErrorIs(t, err, ErrNoSnapshots)
+		_, err = From(nil, time.Time{})
+		require.ErrorIs(t, err, ErrNoSnapshots)
+	})
+}
+
+func TestPlay(t *testing.T) {
+	timestamp := func(snapshot Snapshot) (time.Time, error) {
+		return snapshot.Timestamp, nil
+	}
+
+	t.Run("keeps recorded spacing divided by speed", func(t *testing.T) {
+		snapshots := snapshotsAt(0, 2*time.Second, 4*time.Second)
+		started := time.Now()
+		var played []time.Time
+		for response := range Play(snapshots, 100, timestamp)(context.Background()) {
+			require.NoError(t, response.Error)
+			played = append(played, response.Data)
+		}
+		require.Len(t, played, 3)
+		require.True(t, recordedAt.Add(4*time.Second).Equal(played[2]))
+		require.GreaterOrEqual(t, time.Since(started), 40*time.Millisecond)
+	})
+
+	t.Run("warnings come as partial errors", func(t *testing.T) {
+		snapshots := snapshotsAt(0)
+		snapshots[0].Warnings = partial.Warnings{{Scope: partial.Node, Name: "node-1", Message: "timeout"}}
+		response := <-Play(snapshots, 1, timestamp)(context.Background())
+		var partialErr *partial.Error
+		require.ErrorAs(t, response.Error, &partialErr)
+		require.Equal(t, snapshots[0].Warnings, partialErr.Warnings)
+	})
+
+	t.Run("conversion errors are sent", func(t *testing.T) {
+		response := <-Play(snapshotsAt(0), 1, func(Snapshot) (int, error) {
+			return 0, errors.New("invalid selector")
+		})(context.Background())
+		require.ErrorContains(t, response.Error, "invalid selector")
+	})
+
+	t.Run("stops when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		responses := Play(snapshotsAt(0, time.Hour), 1, timestamp)(ctx)
+		<-responses
+		cancel()
+		_, ok := <-responses
+		require.False(t, ok)
+	})
+}
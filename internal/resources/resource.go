@@ -3,8 +3,10 @@ package resources
 import (
 	"fmt"
 	"slices"
+	"strings"
 
 	choiceutil "github.com/trezorg/k8spodsmetrics/internal/choices"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 type (
@@ -16,21 +18,55 @@ const (
 	Memory  Resource = "memory"
 	CPU     Resource = "cpu"
 	Storage Resource = "storage"
-	All     Resource = "all"
+	// Pods selects the pod slot columns of nodes.
+	Pods Resource = "pods"
+	All  Resource = "all"
 )
 
 var (
-	choices            = []Resource{Memory, CPU, Storage, All}
+	choices            = []Resource{Memory, CPU, Storage, Pods, All}
 	stringChoices      = ToStrings(choices...)
-	ErrInvalidResource = fmt.Errorf("invalid resource. Should be one of: %#v", stringChoices)
+	ErrInvalidResource = fmt.Errorf(
+		"invalid resource. Should be one of: %#v or an extended resource name like nvidia.com/gpu or hugepages-2Mi",
+		stringChoices,
+	)
+	// podChoices leave out pod slots, which are reported for nodes and
+	// namespaces only.
+	podChoices            = []Resource{Memory, CPU, Storage, All}
+	ErrInvalidPodResource = fmt.Errorf(
+		"invalid resource. Should be one of: %#v or an extended resource name like nvidia.com/gpu or hugepages-2Mi",
+		ToStrings(podChoices...),
+	)
 )
 
+const hugePagesPrefix = "hugepages-"
+
+// IsExtended reports whether r names an extended resource, for example
+// nvidia.com/gpu or hugepages-2Mi, rather than one of the built-in choices.
+func IsExtended(r Resource) bool {
+	if slices.Contains(choices, r) {
+		return false
+	}
+	name := string(r)
+	if !strings.Contains(name, "/") && !strings.HasPrefix(name, hugePagesPrefix) {
+		return false
+	}
+	return len(validation.IsQualifiedName(name)) == 0
+}
+
+// IsHugePages reports whether the resource name is a hugepages size measured in bytes.
+func IsHugePages(name string) bool {
+	return strings.HasPrefix(name, hugePagesPrefix)
+}
+
+// Compact sorts and deduplicates resources. All replaces the built-in
+// resources, extended resources are kept next to it.
 func Compact(resources ...Resource) Resources {
 	slices.Sort(resources)
 	resources = slices.Compact(resources)
 	if len(resources) > 0 {
 		if slices.Contains(resources, All) {
-			return []Resource{All}
+			return append([]Resource{All}, slices.DeleteFunc(resources, func(r Resource) bool { return !IsExtended(r) })...)
 		}
 	}
 	return resources
@@ -38,13 +74,23 @@ func Compact(resources ...Resource) Resources {
 
 func Valid(resources ...Resource) error {
 	for _, r := range resources {
-		if !slices.Contains(choices, r) {
+		if !slices.Contains(choices, r) && !IsExtended(r) {
 			return ErrInvalidResource
 		}
 	}
 	return nil
 }
 
+// ValidForPods checks the resources of pod and workload output.
+func ValidForPods(resources ...Resource) error {
+	for _, r := range resources {
+		if !slices.Contains(podChoices, r) && !IsExtended(r) {
+			return ErrInvalidPodResource
+		}
+	}
+	return nil
+}
+
 func FromStrings(resources ...string) Resources {
 	if len(resources) == 0 {
 		return []Resource{All}
@@ -76,6 +122,22 @@ func (r Resources) IsStorage() bool {
 	return slices.Contains(r, All) || slices.Contains(r, Storage)
 }
 
+func (r Resources) IsPods() bool {
+	return slices.Contains(r, All) || slices.Contains(r, Pods)
+}
+
+// Extended returns the names of the selected extended resources. They are
+// never implied by All and have to be requested explicitly.
+func (r Resources) Extended() []string {
+	var result []string
+	for _, resource := range r {
+		if IsExtended(resource) {
+			result = append(result, string(resource))
+		}
+	}
+	return result
+}
+
 func StringList(separator string) string {
 	return choiceutil.StringList(choices, separator)
 }
@@ -83,3 +145,11 @@ func StringList(separator string) string {
 func StringListDefault() string {
 	return StringList(choiceutil.DefaultSeparator)
 }
+
+func PodStringList(separator string) string {
+	return choiceutil.StringList(podChoices, separator)
+}
+
+func PodStringListDefault() string {
+	return PodStringList(choiceutil.DefaultSeparator)
+}
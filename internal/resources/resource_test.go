@@ -29,6 +29,10 @@ func TestCompact(t *testing.T) {
 			in:  Resources{Memory, CPU, CPU, Memory, All},
 			out: Resources{All},
 		},
+		{
+			in:  Resources{"nvidia.com/gpu", Memory, All, "hugepages-2Mi", "nvidia.com/gpu"},
+			out: Resources{All, "hugepages-2Mi", "nvidia.com/gpu"},
+		},
 	}
 
 	for _, data := range testData {
@@ -52,6 +56,18 @@ func TestIsValid(t *testing.T) {
 			in:  Resources{Memory, CPU, CPU, Memory, All, Resource("xxx")},
 			err: ErrInvalidResource,
 		},
+		{
+			in:  Resources{CPU, "nvidia.com/gpu", "hugepages-2Mi"},
+			err: nil,
+		},
+		{
+			in:  Resources{Pods, Memory},
+			err: nil,
+		},
+		{
+			in:  Resources{"nvidia.com/"},
+			err: ErrInvalidResource,
+		},
 	}
 
 	for _, data := range testData {
@@ -61,3 +77,30 @@ func TestIsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestValidForPods(t *testing.T) {
+	require.NoError(t, ValidForPods(CPU, Memory, Storage, All, "nvidia.com/gpu"))
+	require.Equal(t, ErrInvalidPodResource, ValidForPods(CPU, Pods))
+	require.Equal(t, ErrInvalidPodResource, ValidForPods("xxx"))
+	require.NotContains(t, PodStringListDefault(), string(Pods))
+}
+
+func TestExtended(t *testing.T) {
+	selected := FromStrings("cpu", "nvidia.com/gpu", "hugepages-1Gi")
+	require.Equal(t, []string{"hugepages-1Gi", "nvidia.com/gpu"}, selected.Extended())
+	require.True(t, selected.IsCPU())
+	require.False(t, selected.IsMemory())
+
+	require.Empty(t, FromStrings("all").Extended())
+	require.False(t, IsExtended(CPU))
+	require.False(t, IsExtended("gpu"))
+	require.True(t, IsHugePages("hugepages-2Mi"))
+	require.False(t, IsHugePages("nvidia.com/gpu"))
+}
+
+func TestIsPods(t *testing.T) {
+	require.True(t, Resources{Pods}.IsPods())
+	require.True(t, Resources{All}.IsPods())
+	require.False(t, Resources{CPU, Memory}.IsPods())
+	require.False(t, IsExtended(Pods))
+}
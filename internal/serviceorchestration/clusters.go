@@ -0,0 +1,144 @@
+package serviceorchestration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+// ClusterResponse is the result of a request against one kubeconfig context.
+type ClusterResponse[T any] struct {
+	Cluster string
+	Data    T
+	Error   error
+}
+
+// MergeFunc combines the responses of all clusters into one result.
+type MergeFunc[T any] func([]ClusterResponse[T]) (T, error)
+
+// requestClusters runs request for every context concurrently. Responses keep
+// the order of kubeContexts. Failed contexts are recorded as warnings of
+// partial results.
+func requestClusters[T any](
+	ctx context.Context,
+	kubeContexts []string,
+	request func(context.Context, int, string) (T, error),
+) []ClusterResponse[T] {
+	responses := make([]ClusterResponse[T], len(kubeContexts))
+	var wg sync.WaitGroup
+	for i, kubeContext := range kubeContexts {
+		wg.Go(func() {
+			data, err := request(partial.WithCluster(ctx, kubeContext), i, kubeContext)
+			responses[i] = ClusterResponse[T]{Cluster: kubeContext, Data: data, Error: err}
+		})
+	}
+	wg.Wait()
+	for _, response := range responses {
+		if response.Error != nil {
+			partial.Record(ctx, partial.Cluster, response.Cluster, response.Error)
+		}
+	}
+	return responses
+}
+
+// RequestClustersWithRepo is RequestWithRepo run against several contexts at
+// once. Every context gets its own clients, repository and timeout.
+func RequestClustersWithRepo[T any, R any](
+	ctx context.Context,
+	kubeConfig string,
+	kubeContexts []string,
+	timeout uint,
+	clientsFactory ClientsFactory,
+	repoFactory func() R,
+	request RepoRequestFunc[T, R],
+	merge MergeFunc[T],
+) (T, error) {
+	return partial.Collect(ctx, func(ctx context.Context) (T, error) {
+		return merge(requestClusters(ctx, kubeContexts, func(ctx context.Context, _ int, kubeContext string) (T, error) {
+			return RequestWithRepo(ctx, kubeConfig, kubeContext, timeout, clientsFactory, repoFactory, request)
+		}))
+	})
+}
+
+// WatchClustersWithRepoContext is WatchWithRepoContext run against several
+// contexts at once. Every tick requests all contexts and sends the merged
+// result. Repositories are created once per context and live as long as the
+// watch; clients failing to be created are retried on the next tick.
+func WatchClustersWithRepoContext[T any, R any](
+	ctx context.Context,
+	kubeConfig string,
+	kubeContexts []string,
+	watchPeriodSeconds uint,
+	timeout uint,
+	clientsFactory ClientsFactory,
+	repoFactory func(context.Context) R,
+	request RepoRequestFunc[T, R],
+	merge MergeFunc[T],
+) <-chan WatchResponse[T] {
+	type cluster struct {
+		repo          R
+		metricsClient metricsv1beta1.MetricsV1beta1Interface
+		coreClient    corev1.CoreV1Interface
+		ready         bool
+	}
+	clusters := make([]cluster, len(kubeContexts))
+	for i := range clusters {
+		clusters[i].repo = repoFactory(ctx)
+	}
+	// Ticks run one after another, so a cluster is only touched by one
+	// request at a time.
+	requestCluster := func(ctx context.Context, i int, kubeContext string) (T, error) {
+		c := &clusters[i]
+		if !c.ready {
+			metricsClient, coreClient, err := clientsFactory(kubeConfig, kubeContext)
+			if err != nil {
+				var zero T
+				return zero, err
+			}
+			c.metricsClient, c.coreClient, c.ready = metricsClient, coreClient, true
+		}
+		return request(ctx, c.repo, c.metricsClient, c.coreClient)
+	}
+	requestAll := func(
+		requestContext context.Context,
+		_ metricsv1beta1.MetricsV1beta1Interface,
+		_ corev1.CoreV1Interface,
+	) (T, error) {
+		return merge(requestClusters(requestContext, kubeContexts, requestCluster))
+	}
+	noClients := func(string, string) (metricsv1beta1.MetricsV1beta1Interface, corev1.CoreV1Interface, error) {
+		return nil, nil, nil
+	}
+
+	return WatchWithClients(ctx, kubeConfig, "", watchPeriodSeconds, timeout, noClients, requestAll)
+}
+
+// MergeClusters concatenates the lists of all clusters in response order and
+// sets the cluster of every item. A failed cluster is logged and left out, so
+// one unreachable cluster does not hide the others. An error is returned only
+// when every cluster failed.
+func MergeClusters[S ~[]E, E any](responses []ClusterResponse[S], setCluster func(*E, string)) (S, error) {
+	var result S
+	var errs []error
+	for _, response := range responses {
+		if response.Error != nil {
+			slog.Warn("cluster request failed", "cluster", response.Cluster, "error", response.Error)
+			errs = append(errs, fmt.Errorf("cluster %s: %w", response.Cluster, response.Error))
+			continue
+		}
+		for i := range response.Data {
+			setCluster(&response.Data[i], response.Cluster)
+		}
+		result = append(result, response.Data...)
+	}
+	if len(errs) > 0 && len(errs) == len(responses) {
+		return nil, errors.Join(errs...)
+	}
+	return result, nil
+}
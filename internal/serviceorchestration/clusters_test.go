@@ -0,0 +1,173 @@
+package serviceorchestration
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+type clusterItem struct {
+	Name    string
+	Cluster string
+}
+
+func setClusterItemCluster(item *clusterItem, cluster string) {
+	item.Cluster = cluster
+}
+
+func TestMergeClusters(t *testing.T) {
+	errUnreachable := errors.New("connection refused")
+
+	t.Run("keeps cluster order and sets the cluster", func(t *testing.T) {
+		result, err := MergeClusters([]ClusterResponse[[]clusterItem]{
+			{Cluster: "prod-us", Data: []clusterItem{{Name: "b"}}},
+			{Cluster: "prod-eu", Data: []clusterItem{{Name: "a"}, {Name: "c"}}},
+		}, setClusterItemCluster)
+
+		require.NoError(t, err)
+		require.Equal(t, []clusterItem{
+			{Name: "b", Cluster: "prod-us"},
+			{Name: "a", Cluster: "prod-eu"},
+			{Name: "c", Cluster: "prod-eu"},
+		}, result)
+	})
+
+	t.Run("leaves out failed clusters", func(t *testing.T) {
+		result, err := MergeClusters([]ClusterResponse[[]clusterItem]{
+			{Cluster: "prod-us", Error: errUnreachable},
+			{Cluster: "prod-eu", Data: []clusterItem{{Name: "a"}}},
+		}, setClusterItemCluster)
+
+		require.NoError(t, err)
+		require.Equal(t, []clusterItem{{Name: "a", Cluster: "prod-eu"}}, result)
+	})
+
+	t.Run("fails when every cluster failed", func(t *testing.T) {
+		_, err := MergeClusters([]ClusterResponse[[]clusterItem]{
+			{Cluster: "prod-us", Error: errUnreachable},
+			{Cluster: "prod-eu", Error: errUnreachable},
+		}, setClusterItemCluster)
+
+		require.ErrorIs(t, err, errUnreachable)
+		require.ErrorContains(t, err, "cluster prod-us")
+		require.ErrorContains(t, err, "cluster prod-eu")
+	})
+}
+
+func TestRequestClustersWithRepo(t *testing.T) {
+	errUnreachable := errors.New("connection refused")
+	var repos atomic.Int32
+
+	result, err := RequestClustersWithRepo(
+		t.Context(),
+		"config",
+		[]string{"prod-us", "prod-eu", "staging"},
+		0,
+		func(_ string, kubeContext string) (metricsv1beta1.MetricsV1beta1Interface, corev1.CoreV1Interface, error) {
+			if kubeContext == "staging" {
+				return nil, nil, errUnreachable
+			}
+			return nil, nil, nil
+		},
+		func() string {
+			repos.Add(1)
+			return "repo"
+		},
+		func(context.Context, string, metricsv1beta1.MetricsV1beta1Interface, corev1.CoreV1Interface) ([]clusterItem, error) {
+			return []clusterItem{{Name: "pod"}}, nil
+		},
+		func(responses []ClusterResponse[[]clusterItem]) ([]clusterItem, error) {
+			require.ErrorIs(t, responses[2].Error, errUnreachable)
+			return MergeClusters(responses, setClusterItemCluster)
+		},
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, []clusterItem{{Name: "pod", Cluster: "prod-us"}, {Name: "pod", Cluster: "prod-eu"}}, result)
+	require.Equal(t, int32(3), repos.Load())
+}
+
+func TestWatchClustersWithRepoContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var repos, clients, requests atomic.Int32
+	responses := WatchClustersWithRepoContext(
+		ctx,
+		"config",
+		[]string{"prod-us", "prod-eu"},
+		1,
+		0,
+		func(string, string) (metricsv1beta1.MetricsV1beta1Interface, corev1.CoreV1Interface, error) {
+			clients.Add(1)
+			return nil, nil, nil
+		},
+		func(context.Context) string {
+			repos.Add(1)
+			return "repo"
+		},
+		func(_ context.Context, repo string, _ metricsv1beta1.MetricsV1beta1Interface, _ corev1.CoreV1Interface) ([]clusterItem, error) {
+			if requests.Add(1) == 4 {
+				cancel()
+			}
+			return []clusterItem{{Name: repo}}, nil
+		},
+		func(responses []ClusterResponse[[]clusterItem]) ([]clusterItem, error) {
+			return MergeClusters(responses, setClusterItemCluster)
+		},
+	)
+
+	ticks := 0
+	for response := range responses {
+		require.NoError(t, response.Error)
+		require.Equal(t, []clusterItem{{Name: "repo", Cluster: "prod-us"}, {Name: "repo", Cluster: "prod-eu"}}, response.Data)
+		ticks++
+	}
+
+	require.Equal(t, 2, ticks)
+	require.Equal(t, int32(2), repos.Load(), "a repository per cluster for the whole watch")
+	require.Equal(t, int32(2), clients.Load(), "clients are created once per cluster")
+}
+
+func TestRequestClustersWithRepoPartialResults(t *testing.T) {
+	errUnreachable := errors.New("connection refused")
+	errForbidden := errors.New("forbidden")
+
+	result, err := RequestClustersWithRepo(
+		partial.Enable(t.Context()),
+		"config",
+		[]string{"prod-us", "prod-eu", "staging"},
+		0,
+		func(_ string, kubeContext string) (metricsv1beta1.MetricsV1beta1Interface, corev1.CoreV1Interface, error) {
+			if kubeContext == "staging" {
+				return nil, nil, errUnreachable
+			}
+			return nil, nil, nil
+		},
+		func() string { return "repo" },
+		func(ctx context.Context, _ string, _ metricsv1beta1.MetricsV1beta1Interface, _ corev1.CoreV1Interface) ([]clusterItem, error) {
+			return partial.Join(ctx, partial.Namespace, []string{"team-a", "team-b"},
+				[][]clusterItem{{{Name: "pod"}}, nil},
+				[]error{nil, errForbidden},
+			)
+		},
+		func(responses []ClusterResponse[[]clusterItem]) ([]clusterItem, error) {
+			return MergeClusters(responses, setClusterItemCluster)
+		},
+	)
+
+	require.Equal(t, []clusterItem{{Name: "pod", Cluster: "prod-us"}, {Name: "pod", Cluster: "prod-eu"}}, result)
+	var partialErr *partial.Error
+	require.ErrorAs(t, err, &partialErr)
+	require.Equal(t, partial.Warnings{
+		{Scope: partial.Cluster, Name: "staging", Message: "connection refused"},
+		{Cluster: "prod-eu", Scope: partial.Namespace, Name: "team-b", Message: "forbidden"},
+		{Cluster: "prod-us", Scope: partial.Namespace, Name: "team-b", Message: "forbidden"},
+	}, partialErr.Warnings)
+}
@@ -11,6 +11,7 @@ import (
 
 	"log/slog"
 
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 )
@@ -79,7 +80,9 @@ func RequestWithClients[T any](
 		return zero, err
 	}
 
-	return request(ctx, metricsClient, coreClient)
+	return partial.Collect(ctx, func(ctx context.Context) (T, error) {
+		return request(ctx, metricsClient, coreClient)
+	})
 }
 
 func RequestWithRepo[T any, R any](
@@ -111,6 +114,25 @@ func WatchWithClients[T any](
 	timeout uint,
 	clientsFactory ClientsFactory,
 	request RequestFunc[T],
+) <-chan WatchResponse[T] {
+	watchPeriod, err := durationFromSeconds(watchPeriodSeconds, "watch period")
+	if err != nil {
+		ch := make(chan WatchResponse[T], 1)
+		ch <- WatchResponse[T]{Error: err}
+		close(ch)
+		return ch
+	}
+	return watchEvery(ctx, kubeConfig, kubeContext, watchPeriod, timeout, clientsFactory, request)
+}
+
+func watchEvery[T any](
+	ctx context.Context,
+	kubeConfig string,
+	kubeContext string,
+	watchPeriod time.Duration,
+	timeout uint,
+	clientsFactory ClientsFactory,
+	request RequestFunc[T],
 ) <-chan WatchResponse[T] {
 	ch := make(chan WatchResponse[T], 1)
 	slog.Debug("Preparing client...")
@@ -118,12 +140,6 @@ func WatchWithClients[T any](
 	go func() {
 		defer close(ch)
 
-		watchPeriod, err := durationFromSeconds(watchPeriodSeconds, "watch period")
-		if err != nil {
-			ch <- WatchResponse[T]{Error: err}
-			return
-		}
-
 		metricsClient, coreClient, err := clientsFactory(kubeConfig, kubeContext)
 		if err != nil {
 			ch <- WatchResponse[T]{Error: err}
@@ -146,13 +162,12 @@ func WatchWithClients[T any](
 				requestCtx, cancel = context.WithTimeoutCause(ctx, timeoutDuration, ErrRequestTimeout)
 				defer cancel()
 			}
-			data, requestErr := request(requestCtx, metricsClient, coreClient)
-			if requestErr != nil {
-				ch <- WatchResponse[T]{Error: requestErr}
-				return
-			}
-
-			ch <- WatchResponse[T]{Data: data}
+			data, requestErr := partial.Collect(requestCtx, func(ctx context.Context) (T, error) {
+				return request(ctx, metricsClient, coreClient)
+			})
+			// Partial results come with an error and the data of the scopes
+			// that succeeded.
+			ch <- WatchResponse[T]{Data: data, Error: requestErr}
 		}
 
 		produce()
@@ -183,7 +198,32 @@ func WatchWithRepo[T any, R any](
 	repoFactory func() R,
 	request RepoRequestFunc[T, R],
 ) <-chan WatchResponse[T] {
-	repo := repoFactory()
+	return WatchWithRepoContext(
+		ctx,
+		kubeConfig,
+		kubeContext,
+		watchPeriodSeconds,
+		timeout,
+		clientsFactory,
+		func(context.Context) R { return repoFactory() },
+		request,
+	)
+}
+
+// WatchWithRepoContext is WatchWithRepo for repositories living as long as the
+// watch, such as ones keeping objects in informer caches. repoFactory gets the
+// watch context, which is done when the watch ends.
+func WatchWithRepoContext[T any, R any](
+	ctx context.Context,
+	kubeConfig string,
+	kubeContext string,
+	watchPeriodSeconds uint,
+	timeout uint,
+	clientsFactory ClientsFactory,
+	repoFactory func(context.Context) R,
+	request RepoRequestFunc[T, R],
+) <-chan WatchResponse[T] {
+	repo := repoFactory(ctx)
 	requestWithRepo := func(
 		requestContext context.Context,
 		metricsClient metricsv1beta1.MetricsV1beta1Interface,
@@ -195,6 +235,30 @@ func WatchWithRepo[T any, R any](
 	return WatchWithClients(ctx, kubeConfig, kubeContext, watchPeriodSeconds, timeout, clientsFactory, requestWithRepo)
 }
 
+// SampleWithRepoContext is WatchWithRepoContext with an interval that is not
+// limited to whole seconds. The responses stop when ctx is done.
+func SampleWithRepoContext[T any, R any](
+	ctx context.Context,
+	kubeConfig string,
+	kubeContext string,
+	interval time.Duration,
+	timeout uint,
+	clientsFactory ClientsFactory,
+	repoFactory func(context.Context) R,
+	request RepoRequestFunc[T, R],
+) <-chan WatchResponse[T] {
+	repo := repoFactory(ctx)
+	requestWithRepo := func(
+		requestContext context.Context,
+		metricsClient metricsv1beta1.MetricsV1beta1Interface,
+		coreClient corev1.CoreV1Interface,
+	) (T, error) {
+		return request(requestContext, repo, metricsClient, coreClient)
+	}
+
+	return watchEvery(ctx, kubeConfig, kubeContext, interval, timeout, clientsFactory, requestWithRepo)
+}
+
 func RunWithPreparedContext(prepare func() error, run func(context.Context) error) error {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, shutdownSignals...)
@@ -214,13 +278,31 @@ func ProcessRequest[T any](
 	prepare func() error,
 	request func(context.Context) (T, error),
 	successProcessor func(T),
+) error {
+	return ProcessPartialRequest(prepare, request, func(resources T, _ partial.Warnings) {
+		successProcessor(resources)
+	})
+}
+
+// ProcessPartialRequest is ProcessRequest for requests returning partial
+// results. Their data is processed with the warnings of the failed scopes and
+// the *partial.Error is returned.
+func ProcessPartialRequest[T any](
+	prepare func() error,
+	request func(context.Context) (T, error),
+	successProcessor func(T, partial.Warnings),
 ) error {
 	return RunWithPreparedContext(prepare, func(ctx context.Context) error {
 		resources, err := request(ctx)
+		var partialErr *partial.Error
+		if errors.As(err, &partialErr) {
+			successProcessor(resources, partialErr.Warnings)
+			return partialErr
+		}
 		if err != nil {
 			return fmt.Errorf("cannot get k8s resources: %w", err)
 		}
-		successProcessor(resources)
+		successProcessor(resources, nil)
 		return nil
 	})
 }
@@ -230,19 +312,37 @@ func ProcessWatch[T any](
 	watch func(context.Context) <-chan WatchResponse[T],
 	successProcessor func(T),
 	errorProcessor func(error),
+) error {
+	return ProcessPartialWatch(prepare, watch, func(resources T, _ partial.Warnings) {
+		successProcessor(resources)
+	}, errorProcessor)
+}
+
+// ProcessPartialWatch is ProcessWatch for requests returning partial results,
+// which are processed with their warnings like complete ones.
+func ProcessPartialWatch[T any](
+	prepare func() error,
+	watch func(context.Context) <-chan WatchResponse[T],
+	successProcessor func(T, partial.Warnings),
+	errorProcessor func(error),
 ) error {
 	return RunWithPreparedContext(prepare, func(ctx context.Context) error {
 		lastErrorFingerprint := ""
 		for resources := range watch(ctx) {
-			if resources.Error != nil {
+			var partialErr *partial.Error
+			switch {
+			case errors.As(resources.Error, &partialErr):
+				lastErrorFingerprint = ""
+				successProcessor(resources.Data, partialErr.Warnings)
+			case resources.Error != nil:
 				fingerprint := watchErrorFingerprint(resources.Error)
 				if fingerprint != lastErrorFingerprint {
 					errorProcessor(resources.Error)
 					lastErrorFingerprint = fingerprint
 				}
-			} else {
+			default:
 				lastErrorFingerprint = ""
-				successProcessor(resources.Data)
+				successProcessor(resources.Data, nil)
 			}
 		}
 		return nil
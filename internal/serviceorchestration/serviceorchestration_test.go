@@ -5,8 +5,10 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 )
@@ -108,6 +110,77 @@ func TestWatchWithClients(t *testing.T) {
 	})
 }
 
+func TestWatchWithRepoContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var repoContext context.Context
+	repos := 0
+	responses := WatchWithRepoContext(
+		ctx,
+		"config",
+		"context",
+		1,
+		0,
+		func(string, string) (metricsv1beta1.MetricsV1beta1Interface, corev1.CoreV1Interface, error) {
+			return nil, nil, nil
+		},
+		func(ctx context.Context) string {
+			repoContext = ctx
+			repos++
+			return "repo"
+		},
+		func(_ context.Context, repo string, _ metricsv1beta1.MetricsV1beta1Interface, _ corev1.CoreV1Interface) (string, error) {
+			cancel()
+			return repo, nil
+		},
+	)
+
+	values := []string{}
+	for response := range responses {
+		require.NoError(t, response.Error)
+		values = append(values, response.Data)
+	}
+
+	require.Equal(t, []string{"repo"}, values)
+	require.Equal(t, 1, repos)
+	require.ErrorIs(t, repoContext.Err(), context.Canceled, "the repository context ends with the watch")
+}
+
+func TestSampleWithRepoContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 55*time.Millisecond)
+	defer cancel()
+
+	repos := 0
+	responses := SampleWithRepoContext(
+		ctx,
+		"config",
+		"context",
+		20*time.Millisecond,
+		0,
+		func(string, string) (metricsv1beta1.MetricsV1beta1Interface, corev1.CoreV1Interface, error) {
+			return nil, nil, nil
+		},
+		func(context.Context) string {
+			repos++
+			return "repo"
+		},
+		func(_ context.Context, repo string, _ metricsv1beta1.MetricsV1beta1Interface, _ corev1.CoreV1Interface) (string, error) {
+			return repo, nil
+		},
+	)
+
+	samples := 0
+	for response := range responses {
+		require.NoError(t, response.Error)
+		require.Equal(t, "repo", response.Data)
+		samples++
+	}
+
+	require.GreaterOrEqual(t, samples, 2, "sub-second intervals sample more than once")
+	require.Equal(t, 1, repos)
+}
+
 func TestProcessWatchSuppressesRepeatedErrors(t *testing.T) {
 	t.Run("suppresses identical consecutive errors", func(t *testing.T) {
 		errRepeated := errors.New("temporary failure")
@@ -179,6 +252,59 @@ func TestProcessWatchSuppressesRepeatedErrors(t *testing.T) {
 	})
 }
 
+func TestProcessPartialRequest(t *testing.T) {
+	warnings := partial.Warnings{{Scope: partial.Namespace, Name: "team-b", Message: "forbidden"}}
+	var processed []partial.Warnings
+
+	err := ProcessPartialRequest(
+		func() error { return nil },
+		func(context.Context) (int, error) { return 42, &partial.Error{Warnings: warnings} },
+		func(v int, w partial.Warnings) {
+			require.Equal(t, 42, v)
+			processed = append(processed, w)
+		},
+	)
+
+	var partialErr *partial.Error
+	require.ErrorAs(t, err, &partialErr)
+	require.Equal(t, []partial.Warnings{warnings}, processed)
+
+	err = ProcessPartialRequest(
+		func() error { return nil },
+		func(context.Context) (int, error) { return 0, errors.New("forbidden") },
+		func(int, partial.Warnings) { t.Fatal("failed request must not be processed") },
+	)
+	require.ErrorContains(t, err, "cannot get k8s resources")
+}
+
+func TestProcessPartialWatch(t *testing.T) {
+	warnings := partial.Warnings{{Scope: partial.Node, Name: "node-a", Message: "forbidden"}}
+	var successes []int
+	var processed []partial.Warnings
+	var reportedErrors []error
+
+	err := ProcessPartialWatch(
+		func() error { return nil },
+		func(context.Context) <-chan WatchResponse[int] {
+			return watchResponses(
+				WatchResponse[int]{Data: 1, Error: &partial.Error{Warnings: warnings}},
+				WatchResponse[int]{Error: errors.New("unreachable")},
+				WatchResponse[int]{Data: 2},
+			)
+		},
+		func(v int, w partial.Warnings) {
+			successes = append(successes, v)
+			processed = append(processed, w)
+		},
+		func(err error) { reportedErrors = append(reportedErrors, err) },
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, successes)
+	require.Equal(t, []partial.Warnings{warnings, nil}, processed)
+	require.Len(t, reportedErrors, 1)
+}
+
 func TestRunWithPreparedContext(t *testing.T) {
 	t.Run("returns prepare error", func(t *testing.T) {
 		expectedErr := errors.New("prepare error")
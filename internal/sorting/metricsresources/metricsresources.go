@@ -2,16 +2,21 @@ package metricsresources
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	choiceutil "github.com/trezorg/k8spodsmetrics/internal/choices"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
 )
 
 type Sorting string
 
 const (
-	Name                 Sorting = "name"
-	Namespace            Sorting = "namespace"
-	Node                 Sorting = "node"
+	Name      Sorting = "name"
+	Namespace Sorting = "namespace"
+	Node      Sorting = "node"
+	// QOS sorts pods in eviction order: BestEffort, Burstable, Guaranteed.
+	QOS                  Sorting = "qos"
 	RequestCPU           Sorting = "request_cpu"
 	LimitCPU             Sorting = "limit_cpu"
 	UsedCPU              Sorting = "used_cpu"
@@ -20,12 +25,22 @@ const (
 	UsedMemory           Sorting = "used_memory"
 	UsedStorage          Sorting = "used_storage"
 	UsedStorageEphemeral Sorting = "used_storage_ephemeral"
+	// Efficiency sortings order pods by usage relative to their requests and
+	// limits, pods without usage or without the request or limit first.
+	EfficiencyRequestCPU    Sorting = "efficiency_request_cpu"
+	EfficiencyLimitCPU      Sorting = "efficiency_limit_cpu"
+	EfficiencyRequestMemory Sorting = "efficiency_request_memory"
+	EfficiencyLimitMemory   Sorting = "efficiency_limit_memory"
+	// Idle sortings order pods by the requested amount left unused.
+	IdleCPU    Sorting = "idle_cpu"
+	IdleMemory Sorting = "idle_memory"
 )
 
 var choices = []Sorting{
 	Name,
 	Namespace,
 	Node,
+	QOS,
 	RequestCPU,
 	LimitCPU,
 	UsedCPU,
@@ -34,15 +49,48 @@ var choices = []Sorting{
 	UsedMemory,
 	UsedStorage,
 	UsedStorageEphemeral,
+	EfficiencyRequestCPU,
+	EfficiencyLimitCPU,
+	EfficiencyRequestMemory,
+	EfficiencyLimitMemory,
+	IdleCPU,
+	IdleMemory,
 }
 
+// ExtendedField is the part of an extended resource used for sorting, e.g.
+// request_nvidia.com/gpu sorts pods by requested GPUs.
+type ExtendedField string
+
+const (
+	ExtendedRequest ExtendedField = "request"
+	ExtendedLimit   ExtendedField = "limit"
+)
+
+var extendedFields = []ExtendedField{ExtendedRequest, ExtendedLimit}
+
 func Valid(o Sorting) error {
+	if _, _, ok := Extended(o); ok {
+		return nil
+	}
 	if !choiceutil.Valid(o, choices) {
-		return fmt.Errorf("sorting should be one of: %s", StringList(", "))
+		return fmt.Errorf(
+			"sorting should be one of: %s or <%s>_<extended resource>",
+			StringList(", "),
+			choiceutil.StringList(extendedFields, choiceutil.DefaultSeparator),
+		)
 	}
 	return nil
 }
 
+// Extended splits an extended resource sorting into its field and resource name.
+func Extended(o Sorting) (ExtendedField, string, bool) {
+	field, name, found := strings.Cut(string(o), "_")
+	if !found || !slices.Contains(extendedFields, ExtendedField(field)) || !resources.IsExtended(resources.Resource(name)) {
+		return "", "", false
+	}
+	return ExtendedField(field), name, true
+}
+
 func StringList(separator string) string {
 	return choiceutil.StringList(choices, separator)
 }
@@ -23,6 +23,11 @@ func TestValid(t *testing.T) {
 		{"valid used_memory", UsedMemory, false},
 		{"valid used_storage", UsedStorage, false},
 		{"valid used_storage_ephemeral", UsedStorageEphemeral, false},
+		{"valid efficiency_request_cpu", EfficiencyRequestCPU, false},
+		{"valid efficiency_limit_memory", EfficiencyLimitMemory, false},
+		{"valid idle_cpu", IdleCPU, false},
+		{"valid idle_memory", IdleMemory, false},
+		{"invalid efficiency of storage", Sorting("efficiency_request_storage"), true},
 		{"invalid empty", Sorting(""), true},
 		{"invalid unknown", Sorting("unknown_field"), true},
 		{"invalid case", Sorting("NAME"), true},
@@ -76,4 +81,10 @@ func TestSortingConstants(t *testing.T) {
 	require.Equal(t, Sorting("used_memory"), UsedMemory)
 	require.Equal(t, Sorting("used_storage"), UsedStorage)
 	require.Equal(t, Sorting("used_storage_ephemeral"), UsedStorageEphemeral)
+	require.Equal(t, Sorting("efficiency_request_cpu"), EfficiencyRequestCPU)
+	require.Equal(t, Sorting("efficiency_limit_cpu"), EfficiencyLimitCPU)
+	require.Equal(t, Sorting("efficiency_request_memory"), EfficiencyRequestMemory)
+	require.Equal(t, Sorting("efficiency_limit_memory"), EfficiencyLimitMemory)
+	require.Equal(t, Sorting("idle_cpu"), IdleCPU)
+	require.Equal(t, Sorting("idle_memory"), IdleMemory)
 }
@@ -0,0 +1,57 @@
+package namespaces
+
+import (
+	"fmt"
+
+	choiceutil "github.com/trezorg/k8spodsmetrics/internal/choices"
+)
+
+type Sorting string
+
+const (
+	Name          Sorting = "name"
+	Pods          Sorting = "pods"
+	RequestCPU    Sorting = "request_cpu"
+	LimitCPU      Sorting = "limit_cpu"
+	UsedCPU       Sorting = "used_cpu"
+	RequestMemory Sorting = "request_memory"
+	LimitMemory   Sorting = "limit_memory"
+	UsedMemory    Sorting = "used_memory"
+	// Quota sortings order namespaces by quota utilisation percentage.
+	QuotaRequestCPU    Sorting = "quota_request_cpu"
+	QuotaLimitCPU      Sorting = "quota_limit_cpu"
+	QuotaRequestMemory Sorting = "quota_request_memory"
+	QuotaLimitMemory   Sorting = "quota_limit_memory"
+	QuotaPods          Sorting = "quota_pods"
+)
+
+var choices = []Sorting{
+	Name,
+	Pods,
+	RequestCPU,
+	LimitCPU,
+	UsedCPU,
+	RequestMemory,
+	LimitMemory,
+	UsedMemory,
+	QuotaRequestCPU,
+	QuotaLimitCPU,
+	QuotaRequestMemory,
+	QuotaLimitMemory,
+	QuotaPods,
+}
+
+func Valid(o Sorting) error {
+	if !choiceutil.Valid(o, choices) {
+		return fmt.Errorf("sorting should be one of: %s", StringList(", "))
+	}
+	return nil
+}
+
+func StringList(separator string) string {
+	return choiceutil.StringList(choices, separator)
+}
+
+func StringListDefault() string {
+	return StringList(choiceutil.DefaultSeparator)
+}
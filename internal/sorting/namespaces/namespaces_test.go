@@ -0,0 +1,45 @@
+package namespaces
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValid(t *testing.T) {
+	testCases := []struct {
+		name        string
+		sorting     Sorting
+		expectError bool
+	}{
+		{"valid name", Name, false},
+		{"valid pods", Pods, false},
+		{"valid used_cpu", UsedCPU, false},
+		{"valid limit_memory", LimitMemory, false},
+		{"valid quota_request_cpu", QuotaRequestCPU, false},
+		{"valid quota_limit_memory", QuotaLimitMemory, false},
+		{"valid quota_pods", QuotaPods, false},
+		{"invalid empty", Sorting(""), true},
+		{"invalid namespace", Sorting("namespace"), true},
+		{"invalid case", Sorting("NAME"), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := Valid(tc.sorting)
+			if tc.expectError {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "sorting should be one of")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStringListDefault(t *testing.T) {
+	result := StringListDefault()
+	require.Contains(t, result, "quota_pods")
+	require.Contains(t, result, "|")
+}
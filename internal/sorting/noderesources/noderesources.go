@@ -2,8 +2,11 @@ package noderesources
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	choiceutil "github.com/trezorg/k8spodsmetrics/internal/choices"
+	"github.com/trezorg/k8spodsmetrics/internal/resources"
 )
 
 type Sorting string
@@ -30,6 +33,9 @@ const (
 	AllocatableStorageEphemeral Sorting = "allocatable_storage_ephemeral"
 	UsedStorageEphemeral        Sorting = "used_storage_ephemeral"
 	FreeStorageEphemeral        Sorting = "free_storage_ephemeral"
+	RunningPods                 Sorting = "running_pods"
+	AllocatablePods             Sorting = "allocatable_pods"
+	FreePods                    Sorting = "free_pods"
 )
 
 var choices = []Sorting{
@@ -54,15 +60,48 @@ var choices = []Sorting{
 	UsedStorageEphemeral,
 	FreeStorage,
 	FreeStorageEphemeral,
+	RunningPods,
+	AllocatablePods,
+	FreePods,
 }
 
+// ExtendedField is the part of an extended resource used for sorting, e.g.
+// request_nvidia.com/gpu sorts nodes by requested GPUs.
+type ExtendedField string
+
+const (
+	ExtendedTotal       ExtendedField = "total"
+	ExtendedAllocatable ExtendedField = "allocatable"
+	ExtendedRequest     ExtendedField = "request"
+	ExtendedLimit       ExtendedField = "limit"
+	ExtendedAvailable   ExtendedField = "available"
+)
+
+var extendedFields = []ExtendedField{ExtendedTotal, ExtendedAllocatable, ExtendedRequest, ExtendedLimit, ExtendedAvailable}
+
 func Valid(o Sorting) error {
+	if _, _, ok := Extended(o); ok {
+		return nil
+	}
 	if !choiceutil.Valid(o, choices) {
-		return fmt.Errorf("sorting should be one of: %s", StringList(", "))
+		return fmt.Errorf(
+			"sorting should be one of: %s or <%s>_<extended resource>",
+			StringList(", "),
+			choiceutil.StringList(extendedFields, choiceutil.DefaultSeparator),
+		)
 	}
 	return nil
 }
 
+// Extended splits an extended resource sorting into its field and resource name.
+func Extended(o Sorting) (ExtendedField, string, bool) {
+	field, name, found := strings.Cut(string(o), "_")
+	if !found || !slices.Contains(extendedFields, ExtendedField(field)) || !resources.IsExtended(resources.Resource(name)) {
+		return "", "", false
+	}
+	return ExtendedField(field), name, true
+}
+
 func StringList(separator string) string {
 	return choiceutil.StringList(choices, separator)
 }
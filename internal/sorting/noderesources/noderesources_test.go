@@ -12,6 +12,7 @@ func TestValid(t *testing.T) {
 		RequestMemory, LimitMemory, UsedMemory, TotalMemory, AvailableMemory, FreeMemory,
 		Storage, AllocatableStorage, UsedStorage, FreeStorage,
 		StorageEphemeral, AllocatableStorageEphemeral, UsedStorageEphemeral, FreeStorage, FreeStorageEphemeral,
+		RunningPods, AllocatablePods, FreePods,
 	}
 
 	for _, s := range validSortings {
@@ -73,4 +74,7 @@ func TestSortingConstants(t *testing.T) {
 	require.Equal(t, Sorting("allocatable_storage"), AllocatableStorage)
 	require.Equal(t, Sorting("used_storage"), UsedStorage)
 	require.Equal(t, Sorting("free_storage"), FreeStorage)
+	require.Equal(t, Sorting("running_pods"), RunningPods)
+	require.Equal(t, Sorting("allocatable_pods"), AllocatablePods)
+	require.Equal(t, Sorting("free_pods"), FreePods)
 }
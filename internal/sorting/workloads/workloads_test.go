@@ -0,0 +1,48 @@
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValid(t *testing.T) {
+	testCases := []struct {
+		name        string
+		sorting     Sorting
+		expectError bool
+	}{
+		{"valid name", Name, false},
+		{"valid namespace", Namespace, false},
+		{"valid kind", Kind, false},
+		{"valid replicas", Replicas, false},
+		{"valid request_cpu", RequestCPU, false},
+		{"valid limit_cpu", LimitCPU, false},
+		{"valid used_cpu", UsedCPU, false},
+		{"valid request_memory", RequestMemory, false},
+		{"valid limit_memory", LimitMemory, false},
+		{"valid used_memory", UsedMemory, false},
+		{"invalid empty", Sorting(""), true},
+		{"invalid node", Sorting("node"), true},
+		{"invalid case", Sorting("NAME"), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := Valid(tc.sorting)
+			if tc.expectError {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "sorting should be one of")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStringListDefault(t *testing.T) {
+	result := StringListDefault()
+	require.Contains(t, result, "replicas")
+	require.Contains(t, result, "|")
+}
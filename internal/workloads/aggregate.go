@@ -0,0 +1,78 @@
+package workloads
+
+import (
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/owners"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+)
+
+// aggregate groups pods by their topmost controller. Pods without a
+// controller form a workload of their own. Terminated pods no longer hold
+// resources and are left out.
+func aggregate(list metricsresources.PodMetricsResourceList, controllers owners.Controllers) WorkloadList {
+	var result WorkloadList
+	index := map[owners.Reference]int{}
+	for _, pod := range list {
+		if pod.IsTerminated() {
+			continue
+		}
+		ref := Reference(pod.PodResource, controllers)
+		idx, ok := index[ref]
+		if !ok {
+			idx = len(result)
+			index[ref] = idx
+			result = append(result, Workload{Namespace: ref.Namespace, Kind: ref.Kind, Name: ref.Name})
+		}
+		result[idx].pods = append(result[idx].pods, pod)
+	}
+	for i := range result {
+		result[i].computeStats()
+	}
+	return result
+}
+
+// Reference returns the topmost controller of pod, or the pod itself when it
+// has no owner.
+func Reference(pod pods.PodResource, controllers owners.Controllers) owners.Reference {
+	if pod.Owner.Kind == "" {
+		return owners.Reference{Namespace: pod.Namespace, Kind: owners.KindPod, Name: pod.Name}
+	}
+	return controllers.Resolve(owners.Reference{Namespace: pod.Namespace, Kind: pod.Owner.Kind, Name: pod.Owner.Name})
+}
+
+func needsControllers(list metricsresources.PodMetricsResourceList) bool {
+	for _, pod := range list {
+		if pod.Owner.Kind == owners.KindReplicaSet || pod.Owner.Kind == owners.KindJob {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Workload) computeStats() {
+	var requestCPU, requestMemory, limitCPU, limitMemory, usedCPU, usedMemory []int64
+	for _, pod := range w.pods {
+		requests := pod.EffectiveRequests()
+		limits := pod.EffectiveLimits()
+		requestCPU = append(requestCPU, requests.CPU)
+		requestMemory = append(requestMemory, requests.Memory)
+		limitCPU = append(limitCPU, limits.CPU)
+		limitMemory = append(limitMemory, limits.Memory)
+		if len(pod.PodMetric.Containers) == 0 {
+			w.MetricsMissing++
+			continue
+		}
+		var cpu, memory int64
+		for _, container := range pod.PodMetric.Containers {
+			cpu += podmetrics.UsageOrZero(container.CPU)
+			memory += podmetrics.UsageOrZero(container.Memory)
+		}
+		usedCPU = append(usedCPU, cpu)
+		usedMemory = append(usedMemory, memory)
+	}
+	w.Replicas = len(w.pods)
+	w.Requests = Resources{CPU: newStats(requestCPU), Memory: newStats(requestMemory)}
+	w.Limits = Resources{CPU: newStats(limitCPU), Memory: newStats(limitMemory)}
+	w.Used = Resources{CPU: newStats(usedCPU), Memory: newStats(usedMemory)}
+}
@@ -0,0 +1,24 @@
+package workloads
+
+import (
+	alerts "github.com/trezorg/k8spodsmetrics/internal/alert"
+)
+
+// IsAlerted reports whether a replica of the workload has a container alerted
+// for alert.
+func (w Workload) IsAlerted(alert alerts.Alert) bool {
+	return len(w.pods.FilterByAlert(alert, w.pods.ObservedAt())) > 0
+}
+
+func (l WorkloadList) filterByAlert(alert alerts.Alert) WorkloadList {
+	if alert == alerts.None {
+		return l
+	}
+	var result WorkloadList
+	for _, workload := range l {
+		if workload.IsAlerted(alert) {
+			result = append(result, workload)
+		}
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+package workloads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
+	"github.com/trezorg/k8spodsmetrics/pkg/client"
+	"github.com/trezorg/k8spodsmetrics/pkg/owners"
+	appsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	batchv1 "k8s.io/client-go/kubernetes/typed/batch/v1"
+)
+
+type WorkloadRepository interface {
+	metricsresources.PodRepository
+	FetchControllers(ctx context.Context, namespace string) (owners.Controllers, error)
+}
+
+type ownerClients struct {
+	apps  appsv1.AppsV1Interface
+	batch batchv1.BatchV1Interface
+}
+
+type workloadRepository struct {
+	metricsresources.PodRepository
+	clients func() (ownerClients, error)
+}
+
+// NewWorkloadRepository returns a repository reading usage from source and
+// creating the apps and batch clients for kubeConfig and kubeContext with
+// overrides on first use.
+func NewWorkloadRepository(kubeConfig, kubeContext string, overrides client.Overrides, source metricssource.Config) WorkloadRepository {
+	return newWorkloadRepository(metricsresources.NewPodRepositoryWithSource(source), kubeConfig, kubeContext, overrides)
+}
+
+// NewCachedWorkloadRepository is NewWorkloadRepository keeping pods in
+// informer caches until ctx is done.
+func NewCachedWorkloadRepository(ctx context.Context, kubeConfig, kubeContext string, overrides client.Overrides, source metricssource.Config) WorkloadRepository {
+	return newWorkloadRepository(metricsresources.NewCachedPodRepositoryWithSource(ctx, source), kubeConfig, kubeContext, overrides)
+}
+
+func newWorkloadRepository(podRepository metricsresources.PodRepository, kubeConfig, kubeContext string, overrides client.Overrides) WorkloadRepository {
+	return &workloadRepository{
+		PodRepository: podRepository,
+		clients: sync.OnceValues(func() (ownerClients, error) {
+			apps, batch, err := overrides.OwnerClients(kubeConfig, kubeContext)
+			return ownerClients{apps: apps, batch: batch}, err
+		}),
+	}
+}
+
+func (r workloadRepository) FetchControllers(ctx context.Context, namespace string) (owners.Controllers, error) {
+	clients, err := r.clients()
+	if err != nil {
+		return nil, err
+	}
+	return owners.Fetch(ctx, clients.apps, clients.batch, namespace)
+}
+
+// ResolveControllers fetches the controllers of namespaces when pods of list
+// are owned by ReplicaSets or Jobs. Other lists need no requests.
+func ResolveControllers(
+	ctx context.Context,
+	repo WorkloadRepository,
+	list metricsresources.PodMetricsResourceList,
+	namespaces []string,
+) (owners.Controllers, error) {
+	if !needsControllers(list) {
+		return owners.Controllers{}, nil
+	}
+	return fetchControllers(ctx, repo, namespaces)
+}
+
+func fetchControllers(ctx context.Context, repo WorkloadRepository, namespaces []string) (owners.Controllers, error) {
+	namespaces = slices.DeleteFunc(slices.Clone(namespaces), func(n string) bool { return n == "" })
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	results := make([]owners.Controllers, len(namespaces))
+	errs := make([]error, len(namespaces))
+	var wg sync.WaitGroup
+	for idx, namespace := range namespaces {
+		wg.Go(func() {
+			controllers, err := repo.FetchControllers(ctx, namespace)
+			if err != nil {
+				if namespace == "" {
+					errs[idx] = fmt.Errorf("resolve workload owners across all namespaces: %w", err)
+				} else {
+					errs[idx] = fmt.Errorf("resolve workload owners for namespace %q: %w", namespace, err)
+				}
+				return
+			}
+			results[idx] = controllers
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	controllers := owners.Controllers{}
+	for _, result := range results {
+		controllers.Merge(result)
+	}
+	return controllers, nil
+}
@@ -0,0 +1,174 @@
+package workloads
+
+import (
+	"context"
+	"errors"
+
+	"github.com/trezorg/k8spodsmetrics/internal/alert"
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/internal/metricssource"
+	"github.com/trezorg/k8spodsmetrics/internal/serviceorchestration"
+	sorting "github.com/trezorg/k8spodsmetrics/internal/sorting/workloads"
+	"github.com/trezorg/k8spodsmetrics/pkg/client"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+type Config struct {
+	KubeConfig    string
+	KubeContext   string
+	Overrides     client.Overrides
+	Namespaces    []string
+	AllNamespaces bool
+	Label         string
+	FieldSelector string
+	Nodes         []string
+	Sorting       string
+	Alert         string
+	MetricsSource metricssource.Config
+	WatchPeriod   uint
+	Timeout       uint
+	Retry         retry.Policy
+	Reverse       bool
+}
+
+type WatchResponse = serviceorchestration.WatchResponse[WorkloadList]
+
+func (c Config) Validate() error {
+	if err := alert.ValidForPods(alert.Alert(c.Alert)); err != nil {
+		return err
+	}
+	if err := c.MetricsSource.Validate(); err != nil {
+		return err
+	}
+	return sorting.Valid(sorting.Sorting(c.Sorting))
+}
+
+func (c Config) ValidateWatch() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.WatchPeriod == 0 {
+		return errors.New("watch period must be greater than 0")
+	}
+	return nil
+}
+
+func (c Config) apiRequest(
+	ctx context.Context,
+	repo WorkloadRepository,
+	metricsClient metricsv1beta1.MetricsV1beta1Interface,
+	podsClient corev1.CoreV1Interface,
+) (WorkloadList, error) {
+	ctx = retry.WithPolicy(ctx, c.Retry)
+	fetchConfig := metricsresources.FetchConfig{
+		Namespaces:    c.Namespaces,
+		Label:         c.Label,
+		FieldSelector: c.FieldSelector,
+		Nodes:         c.Nodes,
+	}
+	podMetricsResourceList, err := metricsresources.FetchPodMetrics(ctx, repo, metricsClient, podsClient, fetchConfig)
+	if err != nil {
+		return nil, err
+	}
+	podMetricsResourceList = podMetricsResourceList.FilterNodes(c.Nodes)
+	controllers, err := ResolveControllers(ctx, repo, podMetricsResourceList, c.Namespaces)
+	if err != nil {
+		return nil, err
+	}
+	workloadList := aggregate(podMetricsResourceList, controllers)
+	workloadList = workloadList.filterByAlert(alert.Alert(c.Alert))
+	workloadList.sort(c.Sorting, c.Reverse)
+	return workloadList, nil
+}
+
+func (c *Config) newRepository() WorkloadRepository {
+	return NewWorkloadRepository(c.KubeConfig, c.KubeContext, c.Overrides, c.MetricsSource)
+}
+
+func (c *Config) newWatchRepository(ctx context.Context) WorkloadRepository {
+	return NewCachedWorkloadRepository(ctx, c.KubeConfig, c.KubeContext, c.Overrides, c.MetricsSource)
+}
+
+func (c *Config) Request(ctx context.Context) (WorkloadList, error) {
+	return serviceorchestration.RequestWithRepo(
+		ctx,
+		c.KubeConfig,
+		c.KubeContext,
+		c.Timeout,
+		c.Overrides.Clients,
+		c.newRepository,
+		c.apiRequest,
+	)
+}
+
+func (c *Config) Watch(ctx context.Context) <-chan WatchResponse {
+	return serviceorchestration.WatchWithRepoContext(
+		ctx,
+		c.KubeConfig,
+		c.KubeContext,
+		c.WatchPeriod,
+		c.Timeout,
+		c.Overrides.Clients,
+		c.newWatchRepository,
+		c.apiRequest,
+	)
+}
+
+func (c *Config) prepare() error {
+	if c.KubeConfig == "" {
+		var err error
+		c.KubeConfig, err = client.FindKubeConfig()
+		if err != nil {
+			return err
+		}
+	}
+	// Several contexts are only fanned out by pods and summary.
+	kubeContext, err := client.SingleContext(c.KubeConfig, c.KubeContext)
+	if err != nil {
+		return err
+	}
+	c.KubeContext = kubeContext
+	if len(c.Namespaces) > 0 || c.AllNamespaces {
+		return nil
+	}
+	namespace, err := client.ContextNamespace(c.KubeConfig, kubeContext)
+	if err != nil {
+		return err
+	}
+	if namespace != "" {
+		c.Namespaces = []string{namespace}
+	}
+	return nil
+}
+
+func (c *Config) prepareRequest() error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	return c.prepare()
+}
+
+func (c *Config) prepareWatch() error {
+	if err := c.ValidateWatch(); err != nil {
+		return err
+	}
+	return c.prepare()
+}
+
+func (c *Config) Process(successProcessor SuccessProcessor) error {
+	return serviceorchestration.ProcessRequest(c.prepareRequest, c.Request, successProcessor.Success)
+}
+
+func (c *Config) ProcessWatch(successProcessor SuccessProcessor, errorProcessor ErrorProcessor) error {
+	return serviceorchestration.ProcessWatch(c.prepareWatch, c.Watch, successProcessor.Success, errorProcessor.Error)
+}
+
+type SuccessProcessor interface {
+	Success(WorkloadList)
+}
+
+type ErrorProcessor interface {
+	Error(error)
+}
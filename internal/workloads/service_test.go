@@ -0,0 +1,112 @@
+package workloads
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/owners"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+type stubWorkloadRepository struct {
+	pods             pods.PodResourceList
+	metrics          podmetrics.PodMetricList
+	fetchControllers func(namespace string) (owners.Controllers, error)
+}
+
+func (s stubWorkloadRepository) FetchPods(
+	context.Context,
+	corev1.CoreV1Interface,
+	pods.PodFilter,
+	...string,
+) (pods.PodResourceList, error) {
+	return s.pods, nil
+}
+
+func (s stubWorkloadRepository) FetchMetrics(
+	context.Context,
+	metricsv1beta1.MetricsV1beta1Interface,
+	corev1.CoreV1Interface,
+	podmetrics.MetricFilter,
+) (podmetrics.PodMetricList, error) {
+	return s.metrics, nil
+}
+
+func (s stubWorkloadRepository) FetchControllers(_ context.Context, namespace string) (owners.Controllers, error) {
+	if s.fetchControllers != nil {
+		return s.fetchControllers(namespace)
+	}
+	return owners.Controllers{}, nil
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, Config{Sorting: "namespace", Alert: "none"}.Validate())
+	require.ErrorContains(t, Config{Sorting: "node", Alert: "none"}.Validate(), "sorting should be one of")
+	require.Error(t, Config{Sorting: "name", Alert: "unknown"}.Validate())
+	require.ErrorContains(t, Config{Sorting: "name", Alert: "nvidia.com/gpu"}.Validate(), "applies to nodes only")
+	require.ErrorContains(t, Config{Sorting: "name", Alert: "none"}.ValidateWatch(), "watch period")
+	require.NoError(t, Config{Sorting: "name", Alert: "none", WatchPeriod: 5}.ValidateWatch())
+}
+
+func TestAPIRequest(t *testing.T) {
+	podList := pods.PodResourceList{
+		{
+			NamespaceName: pods.NamespaceName{Namespace: "default", Name: "web-1-a"},
+			NodeName:      "node1",
+			Owner:         pods.Owner{Kind: owners.KindReplicaSet, Name: "web-1"},
+		},
+		{
+			NamespaceName: pods.NamespaceName{Namespace: "default", Name: "web-1-b"},
+			NodeName:      "node2",
+			Owner:         pods.Owner{Kind: owners.KindReplicaSet, Name: "web-1"},
+		},
+		{
+			NamespaceName: pods.NamespaceName{Namespace: "default", Name: "db-0"},
+			NodeName:      "node1",
+			Owner:         pods.Owner{Kind: "StatefulSet", Name: "db"},
+		},
+	}
+
+	t.Run("resolves owners per namespace", func(t *testing.T) {
+		var requested []string
+		repo := stubWorkloadRepository{
+			pods: podList,
+			fetchControllers: func(namespace string) (owners.Controllers, error) {
+				requested = append(requested, namespace)
+				return owners.Controllers{
+					{Namespace: "default", Kind: owners.KindReplicaSet, Name: "web-1"}: {
+						Namespace: "default", Kind: "Deployment", Name: "web",
+					},
+				}, nil
+			},
+		}
+		config := Config{Namespaces: []string{"default"}, Nodes: []string{"node1"}, Sorting: "name"}
+
+		result, err := config.apiRequest(t.Context(), repo, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"default"}, requested)
+		require.Len(t, result, 2)
+		require.Equal(t, "db", result[0].Name)
+		require.Equal(t, "web", result[1].Name)
+		require.Equal(t, 1, result[1].Replicas)
+	})
+
+	t.Run("wraps owner errors", func(t *testing.T) {
+		rootErr := errors.New("forbidden")
+		repo := stubWorkloadRepository{
+			pods: podList,
+			fetchControllers: func(string) (owners.Controllers, error) {
+				return nil, rootErr
+			},
+		}
+
+		_, err := Config{Sorting: "name"}.apiRequest(t.Context(), repo, nil, nil)
+		require.ErrorIs(t, err, rootErr)
+		require.ErrorContains(t, err, "resolve workload owners across all namespaces")
+	})
+}
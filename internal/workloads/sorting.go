@@ -0,0 +1,61 @@
+package workloads
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/trezorg/k8spodsmetrics/internal/sorting/workloads"
+)
+
+func direction(reversed bool, result int) int {
+	if reversed {
+		return -result
+	}
+	return result
+}
+
+func (l WorkloadList) sortBy(reversed bool, compare func(a, b Workload) int) {
+	slices.SortStableFunc(l, func(a, b Workload) int {
+		return direction(reversed, compare(a, b))
+	})
+}
+
+func (l WorkloadList) sortByValue(reversed bool, value func(w Workload) int64) {
+	l.sortBy(reversed, func(a, b Workload) int {
+		return cmp.Compare(value(a), value(b))
+	})
+}
+
+func (l WorkloadList) sort(by string, reverse bool) {
+	switch workloads.Sorting(by) {
+	case workloads.Name:
+		l.sortBy(reverse, func(a, b Workload) int {
+			return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Namespace, b.Namespace))
+		})
+	case workloads.Namespace:
+		l.sortBy(reverse, func(a, b Workload) int {
+			return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Name, b.Name))
+		})
+	case workloads.Kind:
+		l.sortBy(reverse, func(a, b Workload) int {
+			return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+		})
+	case workloads.Replicas:
+		l.sortByValue(reverse, func(w Workload) int64 { return int64(w.Replicas) })
+	case workloads.RequestCPU:
+		l.sortByValue(reverse, func(w Workload) int64 { return w.Requests.CPU.Total })
+	case workloads.LimitCPU:
+		l.sortByValue(reverse, func(w Workload) int64 { return w.Limits.CPU.Total })
+	case workloads.UsedCPU:
+		l.sortByValue(reverse, func(w Workload) int64 { return w.Used.CPU.Total })
+	case workloads.RequestMemory:
+		l.sortByValue(reverse, func(w Workload) int64 { return w.Requests.Memory.Total })
+	case workloads.LimitMemory:
+		l.sortByValue(reverse, func(w Workload) int64 { return w.Limits.Memory.Total })
+	case workloads.UsedMemory:
+		l.sortByValue(reverse, func(w Workload) int64 { return w.Used.Memory.Total })
+	default:
+		// keep current order on unknown sorting
+		return
+	}
+}
@@ -0,0 +1,59 @@
+package workloads
+
+import (
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+)
+
+type (
+	// Stats describes a value across the replicas of a workload.
+	Stats struct {
+		Total   int64 `json:"total" yaml:"total"`
+		Average int64 `json:"average" yaml:"average"`
+		Min     int64 `json:"min" yaml:"min"`
+		Max     int64 `json:"max" yaml:"max"`
+	}
+
+	Resources struct {
+		CPU    Stats `json:"cpu" yaml:"cpu"`
+		Memory Stats `json:"memory" yaml:"memory"`
+	}
+
+	Workload struct {
+		Namespace string `json:"namespace" yaml:"namespace"`
+		Kind      string `json:"kind" yaml:"kind"`
+		Name      string `json:"name" yaml:"name"`
+		Replicas  int    `json:"replicas" yaml:"replicas"`
+		// MetricsMissing counts replicas metrics-server reported nothing for.
+		// Used statistics cover the remaining replicas only.
+		MetricsMissing int       `json:"metrics_missing,omitempty" yaml:"metrics_missing,omitempty"`
+		Requests       Resources `json:"requests" yaml:"requests"`
+		Limits         Resources `json:"limits" yaml:"limits"`
+		Used           Resources `json:"used" yaml:"used"`
+		pods           metricsresources.PodMetricsResourceList
+	}
+
+	WorkloadList []Workload
+
+	WorkloadListEnvelope struct {
+		Items WorkloadList `json:"items,omitempty" yaml:"items,omitempty"`
+	}
+)
+
+// HasMetrics reports whether at least one replica has usage metrics.
+func (w Workload) HasMetrics() bool {
+	return w.MetricsMissing < w.Replicas
+}
+
+func newStats(values []int64) Stats {
+	if len(values) == 0 {
+		return Stats{}
+	}
+	result := Stats{Min: values[0], Max: values[0]}
+	for _, value := range values {
+		result.Total += value
+		result.Min = min(result.Min, value)
+		result.Max = max(result.Max, value)
+	}
+	result.Average = result.Total / int64(len(values))
+	return result
+}
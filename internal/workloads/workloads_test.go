@@ -0,0 +1,141 @@
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	alerts "github.com/trezorg/k8spodsmetrics/internal/alert"
+	"github.com/trezorg/k8spodsmetrics/internal/metricsresources"
+	"github.com/trezorg/k8spodsmetrics/pkg/owners"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/pods"
+	v1 "k8s.io/api/core/v1"
+)
+
+func testPod(namespace, name string, owner pods.Owner, cpuRequest, cpuUsed int64) metricsresources.PodMetricsResource {
+	resource := metricsresources.PodMetricsResource{
+		PodResource: pods.PodResource{
+			NamespaceName: pods.NamespaceName{Namespace: namespace, Name: name},
+			Owner:         owner,
+			Containers: []pods.ContainerResource{{
+				Name:     "app",
+				Requests: pods.Resource{CPU: cpuRequest, Memory: 100},
+				Limits:   pods.Resource{CPU: cpuRequest * 2, Memory: 200},
+			}},
+		},
+	}
+	if cpuUsed >= 0 {
+		resource.PodMetric = podmetrics.PodMetric{
+			Namespace: namespace,
+			Name:      name,
+			Containers: []podmetrics.ContainerMetric{
+				{Name: "app", Metric: podmetrics.Metric{CPU: cpuUsed, Memory: 50}},
+			},
+		}
+	}
+	return resource
+}
+
+func TestNewStats(t *testing.T) {
+	require.Equal(t, Stats{}, newStats(nil))
+	require.Equal(t, Stats{Total: 60, Average: 20, Min: 10, Max: 30}, newStats([]int64{30, 10, 20}))
+}
+
+func TestAggregate(t *testing.T) {
+	controllers := owners.Controllers{
+		{Namespace: "default", Kind: owners.KindReplicaSet, Name: "web-1"}: {Namespace: "default", Kind: "Deployment", Name: "web"},
+		{Namespace: "default", Kind: owners.KindReplicaSet, Name: "web-2"}: {Namespace: "default", Kind: "Deployment", Name: "web"},
+		{Namespace: "ops", Kind: owners.KindJob, Name: "backup-1"}:         {Namespace: "ops", Kind: "CronJob", Name: "backup"},
+	}
+	completed := testPod("ops", "backup-0", pods.Owner{Kind: owners.KindJob, Name: "backup-1"}, 100, -1)
+	completed.Phase = v1.PodSucceeded
+	list := metricsresources.PodMetricsResourceList{
+		testPod("default", "web-1-a", pods.Owner{Kind: owners.KindReplicaSet, Name: "web-1"}, 100, 40),
+		testPod("default", "web-2-a", pods.Owner{Kind: owners.KindReplicaSet, Name: "web-2"}, 300, 80),
+		testPod("default", "web-2-b", pods.Owner{Kind: owners.KindReplicaSet, Name: "web-2"}, 200, -1),
+		testPod("default", "db-0", pods.Owner{Kind: "StatefulSet", Name: "db"}, 500, 100),
+		testPod("default", "debug", pods.Owner{}, 10, 1),
+		testPod("ops", "backup-1-a", pods.Owner{Kind: owners.KindJob, Name: "backup-1"}, 100, 20),
+		completed,
+	}
+
+	result := aggregate(list, controllers)
+	require.Len(t, result, 4)
+
+	web := result[0]
+	require.Equal(t, "Deployment", web.Kind)
+	require.Equal(t, "web", web.Name)
+	require.Equal(t, 3, web.Replicas)
+	require.Equal(t, 1, web.MetricsMissing)
+	require.True(t, web.HasMetrics())
+	require.Equal(t, Stats{Total: 600, Average: 200, Min: 100, Max: 300}, web.Requests.CPU)
+	require.Equal(t, Stats{Total: 1200, Average: 400, Min: 200, Max: 600}, web.Limits.CPU)
+	require.Equal(t, Stats{Total: 120, Average: 60, Min: 40, Max: 80}, web.Used.CPU)
+	require.Equal(t, Stats{Total: 300, Average: 100, Min: 100, Max: 100}, web.Requests.Memory)
+
+	require.Equal(t, owners.Reference{Namespace: "default", Kind: "StatefulSet", Name: "db"},
+		owners.Reference{Namespace: result[1].Namespace, Kind: result[1].Kind, Name: result[1].Name})
+	require.Equal(t, owners.Reference{Namespace: "default", Kind: owners.KindPod, Name: "debug"},
+		owners.Reference{Namespace: result[2].Namespace, Kind: result[2].Kind, Name: result[2].Name})
+
+	backup := result[3]
+	require.Equal(t, "CronJob", backup.Kind)
+	require.Equal(t, 1, backup.Replicas, "terminated pods are left out")
+}
+
+func TestNeedsControllers(t *testing.T) {
+	require.False(t, needsControllers(metricsresources.PodMetricsResourceList{
+		testPod("default", "db-0", pods.Owner{Kind: "StatefulSet", Name: "db"}, 1, 1),
+	}))
+	require.True(t, needsControllers(metricsresources.PodMetricsResourceList{
+		testPod("default", "web-1-a", pods.Owner{Kind: owners.KindReplicaSet, Name: "web-1"}, 1, 1),
+	}))
+}
+
+func TestFilterByAlert(t *testing.T) {
+	list := aggregate(metricsresources.PodMetricsResourceList{
+		testPod("default", "busy", pods.Owner{}, 100, 150),
+		testPod("default", "idle", pods.Owner{}, 100, 10),
+	}, owners.Controllers{})
+
+	require.Len(t, list.filterByAlert(alerts.None), 2)
+	filtered := list.filterByAlert(alerts.CPU)
+	require.Len(t, filtered, 1)
+	require.Equal(t, "busy", filtered[0].Name)
+	require.True(t, filtered[0].IsAlerted(alerts.CPU))
+	require.Empty(t, list.filterByAlert(alerts.Memory))
+}
+
+func TestSort(t *testing.T) {
+	list := WorkloadList{
+		{Namespace: "b", Kind: "Deployment", Name: "web", Replicas: 1, Used: Resources{CPU: Stats{Total: 30}}},
+		{Namespace: "a", Kind: "StatefulSet", Name: "db", Replicas: 3, Used: Resources{CPU: Stats{Total: 10}}},
+		{Namespace: "a", Kind: "CronJob", Name: "backup", Replicas: 2, Used: Resources{CPU: Stats{Total: 20}}},
+	}
+	names := func(l WorkloadList) []string {
+		result := make([]string, 0, len(l))
+		for _, w := range l {
+			result = append(result, w.Name)
+		}
+		return result
+	}
+
+	tests := []struct {
+		sorting  string
+		reverse  bool
+		expected []string
+	}{
+		{sorting: "name", expected: []string{"backup", "db", "web"}},
+		{sorting: "namespace", expected: []string{"backup", "db", "web"}},
+		{sorting: "kind", expected: []string{"backup", "web", "db"}},
+		{sorting: "replicas", expected: []string{"web", "backup", "db"}},
+		{sorting: "used_cpu", reverse: true, expected: []string{"web", "backup", "db"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sorting, func(t *testing.T) {
+			sorted := append(WorkloadList(nil), list...)
+			sorted.sort(tt.sorting, tt.reverse)
+			require.Equal(t, tt.expected, names(sorted))
+		})
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"k8s.io/client-go/kubernetes"
+	appsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	batchv1 "k8s.io/client-go/kubernetes/typed/batch/v1"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -23,6 +27,73 @@ const (
 	clientBurstEnvVar = "K8SPODSMETRICS_CLIENT_BURST"
 )
 
+// Overrides are the kubectl connection settings applied on top of the
+// kubeconfig. Zero values keep what the kubeconfig or the in-cluster config
+// says.
+type Overrides struct {
+	Server                string
+	Token                 string
+	Impersonate           string
+	ImpersonateGroups     []string
+	CertificateAuthority  string
+	InsecureSkipTLSVerify bool
+	// RequestTimeout bounds a single API request, zero waits forever.
+	RequestTimeout time.Duration
+	// QPS and Burst limit the client request rate. Zero values fall back to
+	// the K8SPODSMETRICS_CLIENT_QPS and K8SPODSMETRICS_CLIENT_BURST
+	// environment variables and then to the defaults.
+	QPS   float32
+	Burst int
+}
+
+func (o Overrides) configOverrides(context string) *clientcmd.ConfigOverrides {
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: context}
+	overrides.ClusterInfo.Server = o.Server
+	overrides.ClusterInfo.CertificateAuthority = o.CertificateAuthority
+	overrides.ClusterInfo.InsecureSkipTLSVerify = o.InsecureSkipTLSVerify
+	overrides.AuthInfo.Token = o.Token
+	return overrides
+}
+
+// applyInCluster applies the overrides clientcmd leaves to the kubeconfig to
+// an in-cluster config.
+func (o Overrides) applyInCluster(config *rest.Config) {
+	if o.Token != "" {
+		config.BearerToken = o.Token
+		config.BearerTokenFile = ""
+	}
+	if o.CertificateAuthority != "" || o.InsecureSkipTLSVerify {
+		config.CAFile = o.CertificateAuthority
+		config.CAData = nil
+		config.Insecure = o.InsecureSkipTLSVerify
+	}
+}
+
+func (o Overrides) apply(config *rest.Config) {
+	if o.Impersonate != "" || len(o.ImpersonateGroups) > 0 {
+		config.Impersonate.UserName = o.Impersonate
+		config.Impersonate.Groups = o.ImpersonateGroups
+	}
+	if o.RequestTimeout > 0 {
+		config.Timeout = o.RequestTimeout
+	}
+	qps, burst := o.rateLimit()
+	config.QPS = qps
+	config.Burst = burst
+	config.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+}
+
+func (o Overrides) rateLimit() (float32, int) {
+	qps, burst := rateLimitFromEnv()
+	if o.QPS > 0 {
+		qps = o.QPS
+	}
+	if o.Burst > 0 {
+		burst = o.Burst
+	}
+	return qps, burst
+}
+
 func rateLimitFromEnv() (float32, int) {
 	qps := defaultClientQPS
 	burst := defaultClientBurst
@@ -48,36 +119,38 @@ func rateLimitFromEnv() (float32, int) {
 	return qps, burst
 }
 
-func applyRateLimit(config *rest.Config) {
-	qps, burst := rateLimitFromEnv()
-	config.QPS = qps
-	config.Burst = burst
-	config.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
-}
-
-func restConfig(kubeconfigPath string, context string) (*rest.Config, error) {
-	if kubeconfigPath == "" {
+func restConfig(kubeconfigPath string, context string, overrides Overrides) (*rest.Config, error) {
+	if kubeconfigPath == "" && overrides.Server == "" {
 		slog.Warn("--kubeconfig was not specified. Using the inClusterConfig. This might not work.")
 		kubeconfig, err := rest.InClusterConfig()
 		if err == nil {
-			applyRateLimit(kubeconfig)
+			overrides.applyInCluster(kubeconfig)
+			overrides.apply(kubeconfig)
 			return kubeconfig, nil
 		}
 		slog.Warn("error creating inClusterConfig, falling back to default config", "error", err)
 	}
-	configLoadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath}
-	configOverrides := &clientcmd.ConfigOverrides{}
-	if context != "" {
-		configOverrides.CurrentContext = context
-	}
-	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(configLoadingRules, configOverrides).ClientConfig()
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules(kubeconfigPath),
+		overrides.configOverrides(context),
+	).ClientConfig()
 	if err != nil {
 		return nil, err
 	}
-	applyRateLimit(config)
+	overrides.apply(config)
 	return config, nil
 }
 
+// loadingRules loads a single kubeconfig or, for a KUBECONFIG style list of
+// paths, merges them the way kubectl does: the first file setting a value
+// wins and missing files are skipped.
+func loadingRules(kubeconfigPath string) *clientcmd.ClientConfigLoadingRules {
+	if strings.ContainsRune(kubeconfigPath, filepath.ListSeparator) {
+		return &clientcmd.ClientConfigLoadingRules{Precedence: filepath.SplitList(kubeconfigPath)}
+	}
+	return &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath}
+}
+
 func metricsClient(config *rest.Config) (metricsv1beta1.MetricsV1beta1Interface, error) {
 	client, err := metrics.NewForConfig(config)
 	if err != nil {
@@ -94,8 +167,14 @@ func podsClient(config *rest.Config) (corev1.CoreV1Interface, error) {
 	return client.CoreV1(), nil
 }
 
+// Clients returns the metrics and core clients for a kubeconfig context.
 func Clients(kubeconfigPath string, context string) (metricsv1beta1.MetricsV1beta1Interface, corev1.CoreV1Interface, error) {
-	config, err := restConfig(kubeconfigPath, context)
+	return Overrides{}.Clients(kubeconfigPath, context)
+}
+
+// Clients is the package Clients with the overrides applied.
+func (o Overrides) Clients(kubeconfigPath string, context string) (metricsv1beta1.MetricsV1beta1Interface, corev1.CoreV1Interface, error) {
+	config, err := restConfig(kubeconfigPath, context, o)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -110,8 +189,32 @@ func Clients(kubeconfigPath string, context string) (metricsv1beta1.MetricsV1bet
 	return mc, pc, nil
 }
 
+// OwnerClients returns clients used to walk pod owners up to their
+// Deployments and CronJobs.
+func OwnerClients(kubeconfigPath string, context string) (appsv1.AppsV1Interface, batchv1.BatchV1Interface, error) {
+	return Overrides{}.OwnerClients(kubeconfigPath, context)
+}
+
+// OwnerClients is the package OwnerClients with the overrides applied.
+func (o Overrides) OwnerClients(kubeconfigPath string, context string) (appsv1.AppsV1Interface, batchv1.BatchV1Interface, error) {
+	config, err := restConfig(kubeconfigPath, context, o)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client.AppsV1(), client.BatchV1(), nil
+}
+
 func CoreV1Client(kubeconfigPath string, context string) (corev1.CoreV1Interface, error) {
-	config, err := restConfig(kubeconfigPath, context)
+	return Overrides{}.CoreV1Client(kubeconfigPath, context)
+}
+
+// CoreV1Client is the package CoreV1Client with the overrides applied.
+func (o Overrides) CoreV1Client(kubeconfigPath string, context string) (corev1.CoreV1Interface, error) {
+	config, err := restConfig(kubeconfigPath, context, o)
 	if err != nil {
 		return nil, err
 	}
@@ -123,7 +226,12 @@ func CoreV1Client(kubeconfigPath string, context string) (corev1.CoreV1Interface
 }
 
 func ForMetrics(kubeconfigPath string, context string) (metricsv1beta1.MetricsV1beta1Interface, error) {
-	config, err := restConfig(kubeconfigPath, context)
+	return Overrides{}.ForMetrics(kubeconfigPath, context)
+}
+
+// ForMetrics is the package ForMetrics with the overrides applied.
+func (o Overrides) ForMetrics(kubeconfigPath string, context string) (metricsv1beta1.MetricsV1beta1Interface, error) {
+	config, err := restConfig(kubeconfigPath, context, o)
 	if err != nil {
 		return nil, err
 	}
@@ -134,6 +242,8 @@ func ForMetrics(kubeconfigPath string, context string) (metricsv1beta1.MetricsV1
 	return mc, nil
 }
 
+// FindKubeConfig returns the KUBECONFIG environment variable, which may hold
+// a list of paths, or ~/.kube/config when it exists.
 func FindKubeConfig() (string, error) {
 	env := os.Getenv("KUBECONFIG")
 	if env != "" {
@@ -3,7 +3,9 @@ package client
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"k8s.io/client-go/rest"
@@ -45,16 +47,74 @@ func TestFindKubeConfig(t *testing.T) {
 
 func TestRestConfig(t *testing.T) {
 	t.Run("invalid kubeconfig", func(t *testing.T) {
-		_, err := restConfig("/nonexistent/config", "")
+		_, err := restConfig("/nonexistent/config", "", Overrides{})
 		require.Error(t, err)
 	})
 
 	t.Run("in cluster config fallback", func(t *testing.T) {
-		_, err := restConfig("", "")
+		_, err := restConfig("", "", Overrides{})
 		require.Error(t, err)
 	})
 }
 
+func TestRestConfigOverrides(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(testKubeconfig), 0o600))
+
+	t.Run("applies connection overrides", func(t *testing.T) {
+		cfg, err := restConfig(kubeconfig, "prod-eu", Overrides{
+			Server:                "https://10.0.0.1:6443",
+			Token:                 "secret",
+			Impersonate:           "jane",
+			ImpersonateGroups:     []string{"developers"},
+			InsecureSkipTLSVerify: true,
+			RequestTimeout:        15 * time.Second,
+			QPS:                   50,
+			Burst:                 100,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://10.0.0.1:6443", cfg.Host)
+		require.Equal(t, "secret", cfg.BearerToken)
+		require.Equal(t, "jane", cfg.Impersonate.UserName)
+		require.Equal(t, []string{"developers"}, cfg.Impersonate.Groups)
+		require.True(t, cfg.Insecure)
+		require.Equal(t, 15*time.Second, cfg.Timeout)
+		require.Equal(t, float32(50), cfg.QPS)
+		require.Equal(t, 100, cfg.Burst)
+	})
+
+	t.Run("keeps the kubeconfig without overrides", func(t *testing.T) {
+		cfg, err := restConfig(kubeconfig, "", Overrides{})
+		require.NoError(t, err)
+		require.Equal(t, "https://127.0.0.1:6443", cfg.Host)
+		require.Empty(t, cfg.BearerToken)
+		require.Zero(t, cfg.Timeout)
+	})
+
+	t.Run("server without kubeconfig", func(t *testing.T) {
+		cfg, err := restConfig("", "", Overrides{Server: "https://10.0.0.1:6443", Token: "secret"})
+		require.NoError(t, err)
+		require.Equal(t, "https://10.0.0.1:6443", cfg.Host)
+		require.Equal(t, "secret", cfg.BearerToken)
+	})
+
+	t.Run("merges a kubeconfig list", func(t *testing.T) {
+		other := filepath.Join(dir, "other")
+		require.NoError(t, os.WriteFile(other, []byte(otherKubeconfig), 0o600))
+		missing := filepath.Join(dir, "missing")
+		list := strings.Join([]string{missing, kubeconfig, other}, string(filepath.ListSeparator))
+
+		cfg, err := restConfig(list, "dev", Overrides{})
+		require.NoError(t, err)
+		require.Equal(t, "https://10.0.0.2:6443", cfg.Host)
+
+		cfg, err = restConfig(list, "", Overrides{})
+		require.NoError(t, err)
+		require.Equal(t, "https://127.0.0.1:6443", cfg.Host)
+	})
+}
+
 func TestClients(t *testing.T) {
 	t.Run("invalid config", func(t *testing.T) {
 		mc, pc, err := Clients("/invalid/path", "")
@@ -72,6 +132,15 @@ func TestCoreV1Client(t *testing.T) {
 	})
 }
 
+func TestOwnerClients(t *testing.T) {
+	t.Run("invalid config", func(t *testing.T) {
+		apps, batch, err := OwnerClients("/invalid/path", "")
+		require.Error(t, err)
+		require.Nil(t, apps)
+		require.Nil(t, batch)
+	})
+}
+
 func TestForMetrics(t *testing.T) {
 	t.Run("invalid config", func(t *testing.T) {
 		mc, err := ForMetrics("/invalid/path", "")
@@ -80,13 +149,13 @@ func TestForMetrics(t *testing.T) {
 	})
 }
 
-func TestApplyRateLimit(t *testing.T) {
+func TestOverridesRateLimit(t *testing.T) {
 	t.Run("defaults", func(t *testing.T) {
 		t.Setenv(clientQPSEnvVar, "")
 		t.Setenv(clientBurstEnvVar, "")
 		cfg := &rest.Config{}
 
-		applyRateLimit(cfg)
+		Overrides{}.apply(cfg)
 
 		require.Equal(t, defaultClientQPS, cfg.QPS)
 		require.Equal(t, defaultClientBurst, cfg.Burst)
@@ -98,19 +167,30 @@ func TestApplyRateLimit(t *testing.T) {
 		t.Setenv(clientBurstEnvVar, "30")
 		cfg := &rest.Config{}
 
-		applyRateLimit(cfg)
+		Overrides{}.apply(cfg)
 
 		require.Equal(t, float32(15.5), cfg.QPS)
 		require.Equal(t, 30, cfg.Burst)
 		require.NotNil(t, cfg.RateLimiter)
 	})
 
+	t.Run("overrides take precedence over env", func(t *testing.T) {
+		t.Setenv(clientQPSEnvVar, "15.5")
+		t.Setenv(clientBurstEnvVar, "30")
+		cfg := &rest.Config{}
+
+		Overrides{QPS: 50, Burst: 100}.apply(cfg)
+
+		require.Equal(t, float32(50), cfg.QPS)
+		require.Equal(t, 100, cfg.Burst)
+	})
+
 	t.Run("invalid env values fallback to defaults", func(t *testing.T) {
 		t.Setenv(clientQPSEnvVar, "not-a-number")
 		t.Setenv(clientBurstEnvVar, "-1")
 		cfg := &rest.Config{}
 
-		applyRateLimit(cfg)
+		Overrides{}.apply(cfg)
 
 		require.Equal(t, defaultClientQPS, cfg.QPS)
 		require.Equal(t, defaultClientBurst, cfg.Burst)
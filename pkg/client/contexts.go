@@ -0,0 +1,120 @@
+package client
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// contextSeparator separates the contexts of a multi-cluster request.
+const contextSeparator = ","
+
+// IsMultiContext reports whether contexts names more than one context, either
+// as a comma separated list or as a glob pattern.
+func IsMultiContext(contexts string) bool {
+	return strings.Contains(contexts, contextSeparator) || isContextPattern(contexts)
+}
+
+// Contexts expands a comma separated list of kubeconfig context names and glob
+// patterns such as prod-* into context names. Patterns are matched against
+// the contexts of kubeconfigPath in name order, names are kept as given. An
+// empty list stands for the current context and yields a single empty name.
+func Contexts(kubeconfigPath string, contexts string) ([]string, error) {
+	var patterns []string
+	for pattern := range strings.SplitSeq(contexts, contextSeparator) {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if len(patterns) == 0 {
+		return []string{""}, nil
+	}
+
+	var known []string
+	result := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if !isContextPattern(pattern) {
+			if !slices.Contains(result, pattern) {
+				result = append(result, pattern)
+			}
+			continue
+		}
+		if known == nil {
+			var err error
+			if known, err = kubeconfigContexts(kubeconfigPath); err != nil {
+				return nil, err
+			}
+		}
+		matched := false
+		for _, name := range known {
+			if ok, _ := path.Match(pattern, name); ok {
+				matched = true
+				if !slices.Contains(result, name) {
+					result = append(result, name)
+				}
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no kubeconfig context matches %q", pattern)
+		}
+	}
+	return result, nil
+}
+
+// SingleContext is Contexts for callers querying one cluster. It fails when
+// contexts expands to more than one context.
+func SingleContext(kubeconfigPath string, contexts string) (string, error) {
+	names, err := Contexts(kubeconfigPath, contexts)
+	if err != nil {
+		return "", err
+	}
+	if len(names) > 1 {
+		return "", fmt.Errorf("expected a single kubeconfig context, got %d: %s", len(names), strings.Join(names, contextSeparator))
+	}
+	return names[0], nil
+}
+
+func isContextPattern(context string) bool {
+	return strings.ContainsAny(context, "*?[")
+}
+
+// ContextNamespace returns the namespace a kubeconfig context is set to, or
+// an empty string when the context leaves it unset. An empty context stands
+// for the current context.
+func ContextNamespace(kubeconfigPath string, context string) (string, error) {
+	config, err := loadKubeconfig(kubeconfigPath)
+	if err != nil {
+		return "", fmt.Errorf("load kubeconfig namespace: %w", err)
+	}
+	if context == "" {
+		context = config.CurrentContext
+	}
+	if kubeContext, ok := config.Contexts[context]; ok {
+		return kubeContext.Namespace, nil
+	}
+	return "", nil
+}
+
+func kubeconfigContexts(kubeconfigPath string) ([]string, error) {
+	config, err := loadKubeconfig(kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("load kubeconfig contexts: %w", err)
+	}
+	names := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+func loadKubeconfig(kubeconfigPath string) (*clientcmdapi.Config, error) {
+	if kubeconfigPath == "" {
+		return clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	}
+	return loadingRules(kubeconfigPath).Load()
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: user
+contexts:
+- name: prod-eu
+  context: {cluster: cluster, user: user}
+- name: prod-us
+  context: {cluster: cluster, user: user}
+- name: staging
+  context: {cluster: cluster, user: user}
+current-context: staging
+`
+
+const otherKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://10.0.0.2:6443
+users:
+- name: dev
+contexts:
+- name: dev
+  context: {cluster: dev, user: dev, namespace: team-a}
+current-context: dev
+`
+
+func TestContexts(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(testKubeconfig), 0o600))
+
+	tests := []struct {
+		name     string
+		contexts string
+		expected []string
+		err      string
+	}{
+		{name: "current context", contexts: "", expected: []string{""}},
+		{name: "single name", contexts: "staging", expected: []string{"staging"}},
+		{name: "names keep their order", contexts: "staging, prod-us", expected: []string{"staging", "prod-us"}},
+		{name: "glob", contexts: "prod-*", expected: []string{"prod-eu", "prod-us"}},
+		{name: "glob and name without duplicates", contexts: "prod-us,prod-*", expected: []string{"prod-us", "prod-eu"}},
+		{name: "names are not checked", contexts: "dev", expected: []string{"dev"}},
+		{name: "unmatched glob", contexts: "dev-*", err: `no kubeconfig context matches "dev-*"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contexts, err := Contexts(kubeconfig, tt.contexts)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, contexts)
+		})
+	}
+}
+
+func TestContextsKubeconfigList(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(testKubeconfig), 0o600))
+	other := filepath.Join(dir, "other")
+	require.NoError(t, os.WriteFile(other, []byte(otherKubeconfig), 0o600))
+
+	contexts, err := Contexts(kubeconfig+string(filepath.ListSeparator)+other, "*")
+	require.NoError(t, err)
+	require.Equal(t, []string{"dev", "prod-eu", "prod-us", "staging"}, contexts)
+}
+
+func TestContextNamespace(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(testKubeconfig), 0o600))
+	other := filepath.Join(dir, "other")
+	require.NoError(t, os.WriteFile(other, []byte(otherKubeconfig), 0o600))
+
+	tests := []struct {
+		name       string
+		kubeconfig string
+		context    string
+		expected   string
+	}{
+		{name: "context without namespace", kubeconfig: kubeconfig, context: "prod-eu", expected: ""},
+		{name: "named context", kubeconfig: other, context: "dev", expected: "team-a"},
+		{name: "current context", kubeconfig: other, context: "", expected: "team-a"},
+		{name: "unknown context", kubeconfig: other, context: "prod", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace, err := ContextNamespace(tt.kubeconfig, tt.context)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, namespace)
+		})
+	}
+}
+
+func TestIsMultiContext(t *testing.T) {
+	require.False(t, IsMultiContext(""))
+	require.False(t, IsMultiContext("staging"))
+	require.True(t, IsMultiContext("staging,prod"))
+	require.True(t, IsMultiContext("prod-*"))
+}
+
+func TestSingleContext(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(testKubeconfig), 0o600))
+
+	context, err := SingleContext(kubeconfig, "staging*")
+	require.NoError(t, err)
+	require.Equal(t, "staging", context)
+
+	context, err = SingleContext(kubeconfig, "")
+	require.NoError(t, err)
+	require.Empty(t, context)
+
+	_, err = SingleContext(kubeconfig, "prod-*")
+	require.EqualError(t, err, "expected a single kubeconfig context, got 2: prod-eu,prod-us")
+}
@@ -0,0 +1,127 @@
+// Package informers keeps listings of apiserver objects in informer caches, so
+// that the repeated requests of a watch read memory instead of listing all
+// objects again.
+package informers
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"log/slog"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+)
+
+const syncPollInterval = 50 * time.Millisecond
+
+// Informer keeps the objects of one listing in sync with the apiserver.
+type Informer struct {
+	informer cache.SharedIndexInformer
+	mu       sync.Mutex
+	err      error
+}
+
+// listWatch lists with plain paginated lists. Streaming lists wait for a
+// bookmark fake clients never send and only pay off for far larger listings.
+type listWatch struct {
+	*cache.ListWatch
+}
+
+func (listWatch) IsWatchListSemanticsUnSupported() bool {
+	return true
+}
+
+func start(ctx context.Context, lw *cache.ListWatch, object runtime.Object) *Informer {
+	result := &Informer{
+		informer: cache.NewSharedIndexInformer(listWatch{lw}, object, 0, cache.Indexers{}),
+	}
+	// Both only fail for started informers.
+	_ = result.informer.SetTransform(dropManagedFields)
+	_ = result.informer.SetWatchErrorHandlerWithContext(result.watchError)
+	go result.informer.RunWithContext(ctx)
+	return result
+}
+
+// watchError keeps the error for List instead of logging it, the informer
+// retries with backoff.
+func (i *Informer) watchError(_ context.Context, r *cache.Reflector, err error) {
+	slog.Debug("Informer list and watch failed", "type", r.TypeDescription(), "error", err)
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.err = err
+}
+
+func (i *Informer) lastError() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.err
+}
+
+// List returns the cached objects ordered by namespace and name. It waits for
+// the first listing and returns its error when it failed, the informer keeps
+// retrying in the background.
+func (i *Informer) List(ctx context.Context) ([]any, error) {
+	err := wait.PollUntilContextCancel(ctx, syncPollInterval, true, func(context.Context) (bool, error) {
+		if i.informer.HasSynced() {
+			return true, nil
+		}
+		return false, i.lastError()
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+		return nil, err
+	}
+	objects := i.informer.GetStore().List()
+	slices.SortFunc(objects, func(a, b any) int {
+		return cmp.Compare(objectKey(a), objectKey(b))
+	})
+	return objects, nil
+}
+
+// Set starts an informer per listing on first use. All of them stop when the
+// context the set was created with is done.
+type Set[K comparable] struct {
+	ctx       context.Context
+	mu        sync.Mutex
+	informers map[K]*Informer
+}
+
+func NewSet[K comparable](ctx context.Context) *Set[K] {
+	return &Set[K]{ctx: ctx, informers: make(map[K]*Informer)}
+}
+
+// Informer returns the informer of key, starting it with lw on first use.
+func (s *Set[K]) Informer(key K, lw *cache.ListWatch, object runtime.Object) *Informer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if informer, ok := s.informers[key]; ok {
+		return informer
+	}
+	informer := start(s.ctx, lw, object)
+	s.informers[key] = informer
+	return informer
+}
+
+// dropManagedFields saves memory, managed fields are never read.
+func dropManagedFields(object any) (any, error) {
+	if accessor, err := meta.Accessor(object); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+	return object, nil
+}
+
+func objectKey(object any) string {
+	key, err := cache.MetaNamespaceKeyFunc(object)
+	if err != nil {
+		return ""
+	}
+	return key
+}
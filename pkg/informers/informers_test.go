@@ -0,0 +1,78 @@
+package informers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestSetInformer(t *testing.T) {
+	lists := 0
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(context.Context, metav1.ListOptions) (runtime.Object, error) {
+			lists++
+			return &v1.PodList{Items: []v1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web", ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}}},
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api"}},
+			}}, nil
+		},
+		WatchFuncWithContext: func(context.Context, metav1.ListOptions) (watch.Interface, error) {
+			return watch.NewFake(), nil
+		},
+	}
+	set := NewSet[string](t.Context())
+
+	informer := set.Informer("default", lw, &v1.Pod{})
+	require.Same(t, informer, set.Informer("default", lw, &v1.Pod{}))
+
+	objects, err := informer.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	require.Equal(t, "api", objects[0].(*v1.Pod).Name)
+	require.Equal(t, "web", objects[1].(*v1.Pod).Name)
+	require.Nil(t, objects[1].(*v1.Pod).ManagedFields)
+	require.Equal(t, 1, lists)
+}
+
+func TestInformerList(t *testing.T) {
+	t.Run("returns the list error", func(t *testing.T) {
+		expectedErr := errors.New("pods are forbidden")
+		lw := &cache.ListWatch{
+			ListWithContextFunc: func(context.Context, metav1.ListOptions) (runtime.Object, error) {
+				return nil, expectedErr
+			},
+			WatchFuncWithContext: func(context.Context, metav1.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		}
+
+		_, err := NewSet[string](t.Context()).Informer("", lw, &v1.Pod{}).List(t.Context())
+		require.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("returns the context cause while waiting", func(t *testing.T) {
+		expectedErr := errors.New("request timed out")
+		lw := &cache.ListWatch{
+			ListWithContextFunc: func(ctx context.Context, _ metav1.ListOptions) (runtime.Object, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			WatchFuncWithContext: func(context.Context, metav1.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		}
+		ctx, cancel := context.WithTimeoutCause(t.Context(), 100*time.Millisecond, expectedErr)
+		defer cancel()
+
+		_, err := NewSet[string](t.Context()).Informer("", lw, &v1.Pod{}).List(ctx)
+		require.ErrorIs(t, err, expectedErr)
+	})
+}
@@ -0,0 +1,106 @@
+package kubeletstats
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/nodemetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// PodMetrics reads pod metrics from the kubelets of filter.Nodes, or of all
+// nodes, keeping pods of filter.Namespaces. Label and field selectors cannot
+// be applied to kubelet stats, pods they exclude are dropped when metrics are
+// joined with pods.
+func PodMetrics(ctx context.Context, coreV1Ifc corev1.CoreV1Interface, filter podmetrics.MetricFilter) (podmetrics.PodMetricList, error) {
+	summaries, err := Summaries(ctx, coreV1Ifc, "", filter.Nodes...)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := slices.DeleteFunc(slices.Clone(filter.Namespaces), func(n string) bool { return n == "" })
+	result := podmetrics.PodMetricList{}
+	for _, summary := range summaries {
+		for _, pod := range summary.Pods {
+			if len(namespaces) > 0 && !slices.Contains(namespaces, pod.PodRef.Namespace) {
+				continue
+			}
+			result = append(result, podMetric(pod))
+		}
+	}
+	return result, nil
+}
+
+// NodeMetrics reads node metrics from the kubelet of name, or of all nodes
+// matching filter.LabelSelector when name is empty.
+func NodeMetrics(
+	ctx context.Context,
+	coreV1Ifc corev1.CoreV1Interface,
+	filter nodemetrics.MetricsFilter,
+	name string,
+) (nodemetrics.List, error) {
+	summaries, err := Summaries(ctx, coreV1Ifc, filter.LabelSelector, name)
+	if err != nil {
+		return nil, err
+	}
+	result := make(nodemetrics.List, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, nodeMetric(summary.Node))
+	}
+	return result, nil
+}
+
+func podMetric(pod PodStats) podmetrics.PodMetric {
+	metric := podmetrics.PodMetric{
+		Namespace:        pod.PodRef.Namespace,
+		Name:             pod.PodRef.Name,
+		StorageEphemeral: pod.EphemeralStorage.used(),
+	}
+	for _, container := range pod.Containers {
+		// Containers are sampled separately, the oldest sample dates the pod.
+		if sampled := container.CPU.time(); !sampled.IsZero() && (metric.Timestamp.IsZero() || sampled.Before(metric.Timestamp)) {
+			metric.Timestamp = sampled
+		}
+		metric.Containers = append(metric.Containers, podmetrics.ContainerMetric{
+			Name: container.Name,
+			Metric: podmetrics.Metric{
+				CPU:              container.CPU.milliCores(),
+				Memory:           container.Memory.workingSet(),
+				MemoryRSS:        container.Memory.rss(),
+				StorageEphemeral: container.Rootfs.used() + container.Logs.used(),
+			},
+		})
+	}
+	slices.SortFunc(metric.Containers, func(a, b podmetrics.ContainerMetric) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	for _, volume := range pod.VolumeStats {
+		if volume.PVCRef == nil {
+			continue
+		}
+		metric.Volumes = append(metric.Volumes, podmetrics.VolumeMetric{
+			Name:      volume.Name,
+			ClaimName: volume.PVCRef.Name,
+			Used:      volume.used(),
+			Capacity:  volume.capacity(),
+		})
+	}
+	return metric
+}
+
+func nodeMetric(node NodeStats) nodemetrics.NodeMetric {
+	metric := nodemetrics.NodeMetric{
+		Name:             node.NodeName,
+		CPU:              podmetrics.UsageOrZero(node.CPU.milliCores()),
+		Memory:           podmetrics.UsageOrZero(node.Memory.workingSet()),
+		MemoryRSS:        podmetrics.UsageOrZero(node.Memory.rss()),
+		StorageEphemeral: node.Fs.used(),
+		Timestamp:        node.CPU.time(),
+	}
+	if node.Runtime != nil {
+		metric.ImageFilesystem = node.Runtime.ImageFs.capacity()
+		metric.ImageFilesystemUsed = node.Runtime.ImageFs.used()
+	}
+	return metric
+}
@@ -0,0 +1,78 @@
+package kubeletstats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/nodemetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+)
+
+func TestPodMetrics(t *testing.T) {
+	client := newFakeKubelets(t, map[string]string{"node-1": summaryNode1, "node-2": summaryNode2})
+
+	t.Run("usage, storage and volumes", func(t *testing.T) {
+		metrics, err := PodMetrics(context.Background(), client, podmetrics.MetricFilter{Nodes: []string{"node-1"}})
+		require.NoError(t, err)
+		require.Len(t, metrics, 2)
+
+		web := metrics[0]
+		require.Equal(t, "web", web.Name)
+		require.Equal(t, "default", web.Namespace)
+		require.Equal(t, int64(50), web.StorageEphemeral)
+		require.True(t, web.Timestamp.Equal(time.Date(2026, 10, 17, 10, 0, 3, 0, time.UTC)), "oldest container sample dates the pod")
+		require.Equal(t, []podmetrics.ContainerMetric{
+			{Name: "app", Metric: podmetrics.Metric{CPU: 250, Memory: 300, MemoryRSS: 200, StorageEphemeral: 30}},
+			{Name: "sidecar", Metric: podmetrics.Metric{CPU: 2, Memory: 100, MemoryRSS: 80, StorageEphemeral: 15}},
+		}, web.Containers)
+		require.Equal(t, []podmetrics.VolumeMetric{
+			{Name: "data", ClaimName: "data-web", Used: 700, Capacity: 1000},
+		}, web.Volumes)
+	})
+
+	t.Run("container without stats keeps unset usage", func(t *testing.T) {
+		metric := podMetric(PodStats{
+			PodRef:     PodReference{Name: "starting", Namespace: "default"},
+			Containers: []ContainerStats{{Name: "app"}, {Name: "sidecar", Memory: &MemoryStats{}}},
+		})
+		require.Equal(t, []podmetrics.ContainerMetric{
+			{Name: "app", Metric: podmetrics.Metric{CPU: podmetrics.Unset, Memory: podmetrics.Unset, MemoryRSS: podmetrics.Unset}},
+			{Name: "sidecar", Metric: podmetrics.Metric{CPU: podmetrics.Unset, Memory: podmetrics.Unset, MemoryRSS: podmetrics.Unset}},
+		}, metric.Containers)
+	})
+
+	t.Run("namespace filter", func(t *testing.T) {
+		metrics, err := PodMetrics(context.Background(), client, podmetrics.MetricFilter{Namespaces: []string{"default"}})
+		require.NoError(t, err)
+		names := make([]string, 0, len(metrics))
+		for _, metric := range metrics {
+			names = append(names, metric.Name)
+		}
+		require.ElementsMatch(t, []string{"web", "db"}, names)
+	})
+}
+
+func TestNodeMetrics(t *testing.T) {
+	client := newFakeKubelets(t, map[string]string{"node-1": summaryNode1, "node-2": summaryNode2})
+
+	metrics, err := NodeMetrics(context.Background(), client, nodemetrics.MetricsFilter{}, "node-1")
+	require.NoError(t, err)
+	require.Equal(t, nodemetrics.List{{
+		Name:                "node-1",
+		CPU:                 1500,
+		Memory:              2048,
+		MemoryRSS:           1024,
+		StorageEphemeral:    40000,
+		ImageFilesystem:     50000,
+		ImageFilesystemUsed: 20000,
+		Timestamp:           time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC).Local(),
+	}}, metrics)
+
+	metrics, err = NodeMetrics(context.Background(), client, nodemetrics.MetricsFilter{}, "")
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+	require.Equal(t, "node-2", metrics[1].Name)
+	require.Equal(t, int64(500), metrics[1].CPU)
+}
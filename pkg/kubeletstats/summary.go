@@ -0,0 +1,289 @@
+// Package kubeletstats reads the kubelet Summary API through the apiserver
+// node proxy. Unlike metrics.k8s.io it reports container root filesystem and
+// log usage, pod ephemeral storage, persistent volume usage, node filesystems
+// and memory RSS next to the working set.
+package kubeletstats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"log/slog"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// Summary is the subset of the kubelet stats/summary response used here.
+// Every statistic is optional: kubelets omit what the runtime does not report.
+type Summary struct {
+	Node NodeStats  `json:"node"`
+	Pods []PodStats `json:"pods"`
+}
+
+type NodeStats struct {
+	NodeName string        `json:"nodeName"`
+	CPU      *CPUStats     `json:"cpu,omitempty"`
+	Memory   *MemoryStats  `json:"memory,omitempty"`
+	Fs       *FsStats      `json:"fs,omitempty"`
+	Runtime  *RuntimeStats `json:"runtime,omitempty"`
+}
+
+type RuntimeStats struct {
+	ImageFs *FsStats `json:"imageFs,omitempty"`
+}
+
+type PodStats struct {
+	PodRef           PodReference     `json:"podRef"`
+	Containers       []ContainerStats `json:"containers"`
+	VolumeStats      []VolumeStats    `json:"volume,omitempty"`
+	EphemeralStorage *FsStats         `json:"ephemeral-storage,omitempty"`
+}
+
+type PodReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type ContainerStats struct {
+	Name   string       `json:"name"`
+	CPU    *CPUStats    `json:"cpu,omitempty"`
+	Memory *MemoryStats `json:"memory,omitempty"`
+	Rootfs *FsStats     `json:"rootfs,omitempty"`
+	Logs   *FsStats     `json:"logs,omitempty"`
+}
+
+type CPUStats struct {
+	// Time is when the kubelet sampled the usage.
+	Time           metav1.Time `json:"time"`
+	UsageNanoCores *uint64     `json:"usageNanoCores,omitempty"`
+}
+
+type MemoryStats struct {
+	WorkingSetBytes *uint64 `json:"workingSetBytes,omitempty"`
+	RSSBytes        *uint64 `json:"rssBytes,omitempty"`
+}
+
+type FsStats struct {
+	CapacityBytes *uint64 `json:"capacityBytes,omitempty"`
+	UsedBytes     *uint64 `json:"usedBytes,omitempty"`
+}
+
+type VolumeStats struct {
+	FsStats
+	Name   string        `json:"name"`
+	PVCRef *PVCReference `json:"pvcRef,omitempty"`
+}
+
+type PVCReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// Fetch reads the summary of a single node.
+func Fetch(ctx context.Context, coreV1Ifc corev1.CoreV1Interface, nodeName string) (Summary, error) {
+	raw, err := coreV1Ifc.RESTClient().
+		Get().
+		Resource("nodes").
+		Name(nodeName).
+		SubResource("proxy").
+		Suffix("stats", "summary").
+		DoRaw(ctx)
+	if err != nil {
+		return Summary{}, fmt.Errorf("get kubelet stats summary for node %q: %w", nodeName, err)
+	}
+	var summary Summary
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		return Summary{}, fmt.Errorf("decode kubelet stats summary for node %q: %w", nodeName, err)
+	}
+	return summary, nil
+}
+
+// maxConcurrentFetches caps the kubelet summaries requested at once through
+// the apiserver node proxy.
+const maxConcurrentFetches = 16
+
+type sharedSummariesKey struct{}
+
+// sharedSummaries holds the summaries read within one request by node
+// selection.
+type sharedSummaries struct {
+	mu    sync.Mutex
+	reads map[string]*summariesRead
+}
+
+type summariesRead struct {
+	once      sync.Once
+	summaries []Summary
+	err       error
+}
+
+// WithSharedSummaries returns a context whose Summaries calls selecting the
+// same nodes read them once, e.g. the per-namespace pod metrics reads of one
+// request. Callers must not modify the shared summaries.
+func WithSharedSummaries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sharedSummariesKey{}, &sharedSummaries{reads: map[string]*summariesRead{}})
+}
+
+func (s *sharedSummaries) read(key string) *summariesRead {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	read, ok := s.reads[key]
+	if !ok {
+		read = &summariesRead{}
+		s.reads[key] = read
+	}
+	return read
+}
+
+// Summaries reads the summaries of nodeNames, or of all nodes matching
+// labelSelector when no name is given. Nodes whose kubelet cannot be reached,
+// e.g. NotReady ones, are skipped with a warning; an error is returned only
+// when no summary could be read.
+func Summaries(
+	ctx context.Context,
+	coreV1Ifc corev1.CoreV1Interface,
+	labelSelector string,
+	nodeNames ...string,
+) ([]Summary, error) {
+	nodeNames = slices.DeleteFunc(slices.Clone(nodeNames), func(n string) bool { return n == "" })
+	shared, ok := ctx.Value(sharedSummariesKey{}).(*sharedSummaries)
+	if !ok {
+		return summaries(ctx, coreV1Ifc, labelSelector, nodeNames)
+	}
+	slices.Sort(nodeNames)
+	read := shared.read(labelSelector + "\x00" + strings.Join(nodeNames, ","))
+	read.once.Do(func() {
+		read.summaries, read.err = summaries(ctx, coreV1Ifc, labelSelector, nodeNames)
+	})
+	return read.summaries, read.err
+}
+
+func summaries(
+	ctx context.Context,
+	coreV1Ifc corev1.CoreV1Interface,
+	labelSelector string,
+	nodeNames []string,
+) ([]Summary, error) {
+	if len(nodeNames) == 0 {
+		var err error
+		nodeNames, err = ListNodeNames(ctx, coreV1Ifc, labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("list nodes: %w", err)
+		}
+	}
+	if len(nodeNames) == 0 {
+		return nil, nil
+	}
+
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, maxConcurrentFetches)
+	fetched := make([]Summary, len(nodeNames))
+	rErrors := make([]error, len(nodeNames))
+	for idx, nodeName := range nodeNames {
+		wg.Go(func() {
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			fetched[idx], rErrors[idx] = Fetch(ctx, coreV1Ifc, nodeName)
+		})
+	}
+	wg.Wait()
+
+	result := make([]Summary, 0, len(fetched))
+	for idx, err := range rErrors {
+		if err != nil {
+			slog.Warn("Skipping node without kubelet stats", slog.String("node", nodeNames[idx]), slog.Any("error", err))
+			continue
+		}
+		result = append(result, fetched[idx])
+	}
+	if len(result) == 0 {
+		return nil, errors.Join(rErrors...)
+	}
+	return result, nil
+}
+
+// ListNodeNames lists the names of the nodes matching labelSelector, all
+// nodes when it is empty, through the retrying paginated list.
+func ListNodeNames(ctx context.Context, coreV1Ifc corev1.CoreV1Interface, labelSelector string) ([]string, error) {
+	opts := metav1.ListOptions{LabelSelector: labelSelector}
+	return retry.List(ctx, opts, func(ctx context.Context, opts metav1.ListOptions) ([]string, string, error) {
+		nodes, err := coreV1Ifc.Nodes().List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		names := make([]string, 0, len(nodes.Items))
+		for _, node := range nodes.Items {
+			names = append(names, node.Name)
+		}
+		return names, nodes.Continue, nil
+	})
+}
+
+func value(v *uint64) int64 {
+	if v == nil {
+		return 0
+	}
+	return int64(*v) //nolint:gosec // byte and nanocore counters fit into int64
+}
+
+// usage is the value of a CPU or memory counter, podmetrics.Unset when the
+// kubelet did not report it.
+func usage(v *uint64) int64 {
+	if v == nil {
+		return podmetrics.Unset
+	}
+	return value(v)
+}
+
+const nanoCoresInMilliCore = 1_000_000
+
+func (s *CPUStats) milliCores() int64 {
+	if s == nil || s.UsageNanoCores == nil {
+		return podmetrics.Unset
+	}
+	return value(s.UsageNanoCores) / nanoCoresInMilliCore
+}
+
+func (s *CPUStats) time() time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	return s.Time.Time
+}
+
+func (s *MemoryStats) workingSet() int64 {
+	if s == nil {
+		return podmetrics.Unset
+	}
+	return usage(s.WorkingSetBytes)
+}
+
+func (s *MemoryStats) rss() int64 {
+	if s == nil {
+		return podmetrics.Unset
+	}
+	return usage(s.RSSBytes)
+}
+
+func (s *FsStats) used() int64 {
+	if s == nil {
+		return 0
+	}
+	return value(s.UsedBytes)
+}
+
+func (s *FsStats) capacity() int64 {
+	if s == nil {
+		return 0
+	}
+	return value(s.CapacityBytes)
+}
@@ -0,0 +1,174 @@
+package kubeletstats
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+const summaryNode1 = `{
+  "node": {
+    "nodeName": "node-1",
+    "cpu": {"time": "2026-10-17T10:00:00Z", "usageNanoCores": 1500000000},
+    "memory": {"workingSetBytes": 2048, "rssBytes": 1024},
+    "fs": {"capacityBytes": 100000, "usedBytes": 40000},
+    "runtime": {"imageFs": {"capacityBytes": 50000, "usedBytes": 20000}}
+  },
+  "pods": [
+    {
+      "podRef": {"name": "web", "namespace": "default"},
+      "containers": [
+        {
+          "name": "sidecar",
+          "cpu": {"time": "2026-10-17T10:00:05Z", "usageNanoCores": 2000000},
+          "memory": {"workingSetBytes": 100, "rssBytes": 80},
+          "rootfs": {"usedBytes": 10},
+          "logs": {"usedBytes": 5}
+        },
+        {
+          "name": "app",
+          "cpu": {"time": "2026-10-17T10:00:03Z", "usageNanoCores": 250000000},
+          "memory": {"workingSetBytes": 300, "rssBytes": 200},
+          "rootfs": {"usedBytes": 30}
+        }
+      ],
+      "volume": [
+        {"name": "data", "usedBytes": 700, "capacityBytes": 1000, "pvcRef": {"name": "data-web", "namespace": "default"}},
+        {"name": "tmp", "usedBytes": 5}
+      ],
+      "ephemeral-storage": {"usedBytes": 50}
+    },
+    {
+      "podRef": {"name": "dns", "namespace": "kube-system"},
+      "containers": [{"name": "dns"}]
+    }
+  ]
+}`
+
+const summaryNode2 = `{
+  "node": {"nodeName": "node-2", "cpu": {"usageNanoCores": 500000000}},
+  "pods": [{"podRef": {"name": "db", "namespace": "default"}, "containers": [{"name": "db"}]}]
+}`
+
+func newFakeKubelets(t *testing.T, summaries map[string]string) corev1.CoreV1Interface {
+	t.Helper()
+	client, _ := newCountingFakeKubelets(t, summaries)
+	return client
+}
+
+// newCountingFakeKubelets is newFakeKubelets also counting the summary requests.
+func newCountingFakeKubelets(t *testing.T, summaries map[string]string) (corev1.CoreV1Interface, *atomic.Int32) {
+	t.Helper()
+	requests := &atomic.Int32{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/nodes", func(w http.ResponseWriter, _ *http.Request) {
+		nodes := v1.NodeList{TypeMeta: metav1.TypeMeta{Kind: "NodeList", APIVersion: "v1"}}
+		for _, name := range []string{"node-1", "node-2", "node-3"} {
+			nodes.Items = append(nodes.Items, v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(nodes))
+	})
+	mux.HandleFunc("/api/v1/nodes/{node}/proxy/stats/summary", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		summary, ok := summaries[r.PathValue("node")]
+		if !ok {
+			http.Error(w, "kubelet unreachable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(summary))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client, err := corev1.NewForConfig(&rest.Config{Host: srv.URL})
+	require.NoError(t, err)
+	return client, requests
+}
+
+func TestFetch(t *testing.T) {
+	client := newFakeKubelets(t, map[string]string{"node-1": summaryNode1})
+
+	summary, err := Fetch(context.Background(), client, "node-1")
+	require.NoError(t, err)
+	require.Equal(t, "node-1", summary.Node.NodeName)
+	require.Len(t, summary.Pods, 2)
+
+	_, err = Fetch(context.Background(), client, "node-3")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `node "node-3"`)
+}
+
+func TestSummaries(t *testing.T) {
+	tests := []struct {
+		name      string
+		summaries map[string]string
+		nodes     []string
+		expected  []string
+		wantErr   bool
+	}{
+		{
+			name:      "all nodes skipping unreachable kubelets",
+			summaries: map[string]string{"node-1": summaryNode1, "node-2": summaryNode2},
+			expected:  []string{"node-1", "node-2"},
+		},
+		{
+			name:      "selected nodes",
+			summaries: map[string]string{"node-1": summaryNode1, "node-2": summaryNode2},
+			nodes:     []string{"node-2"},
+			expected:  []string{"node-2"},
+		},
+		{
+			name:      "no reachable kubelet",
+			summaries: map[string]string{},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeKubelets(t, tt.summaries)
+			summaries, err := Summaries(context.Background(), client, "", tt.nodes...)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			names := make([]string, 0, len(summaries))
+			for _, summary := range summaries {
+				names = append(names, summary.Node.NodeName)
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestSharedSummaries(t *testing.T) {
+	client, requests := newCountingFakeKubelets(t, map[string]string{"node-1": summaryNode1, "node-2": summaryNode2})
+	ctx := WithSharedSummaries(context.Background())
+
+	var wg sync.WaitGroup
+	for _, namespace := range []string{"default", "kube-system", "other"} {
+		wg.Go(func() {
+			_, err := PodMetrics(ctx, client, podmetrics.MetricFilter{Namespaces: []string{namespace}})
+			require.NoError(t, err)
+		})
+	}
+	wg.Wait()
+	require.Equal(t, int32(3), requests.Load(), "every node is read once, node-3 failing")
+
+	_, err := Summaries(ctx, client, "", "node-1")
+	require.NoError(t, err)
+	require.Equal(t, int32(4), requests.Load(), "another node selection is read separately")
+}
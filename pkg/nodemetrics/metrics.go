@@ -2,7 +2,9 @@ package nodemetrics
 
 import (
 	"context"
+	"time"
 
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
@@ -15,6 +17,15 @@ type NodeMetric struct {
 	Memory           int64
 	Storage          int64
 	StorageEphemeral int64
+	// MemoryRSS and the image filesystem are only reported by the kubelet
+	// source. StorageEphemeral is then the node root filesystem usage.
+	MemoryRSS           int64
+	ImageFilesystem     int64
+	ImageFilesystemUsed int64
+	// Timestamp is when the usage was collected and Window the interval CPU
+	// usage is averaged over. Zero values mean the source does not report them.
+	Timestamp time.Time
+	Window    time.Duration
 }
 
 type List []NodeMetric
@@ -35,7 +46,9 @@ func Metrics(ctx context.Context, api metricsv1beta1.MetricsV1beta1Interface, fi
 		})
 	} else {
 		var nodeMetric *v1beta1.NodeMetrics
-		nodeMetric, err = api.NodeMetricses().Get(ctx, nodeName, metav1.GetOptions{})
+		nodeMetric, err = retry.Do(ctx, func(ctx context.Context) (*v1beta1.NodeMetrics, error) {
+			return api.NodeMetricses().Get(ctx, nodeName, metav1.GetOptions{})
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -49,7 +62,11 @@ func Metrics(ctx context.Context, api metricsv1beta1.MetricsV1beta1Interface, fi
 	result := make(List, 0, len(nodeMetrics.Items))
 	for _, nodeMetric := range nodeMetrics.Items {
 		resourceList := nodeMetric.Usage
-		metric := NodeMetric{Name: nodeMetric.Name}
+		metric := NodeMetric{
+			Name:      nodeMetric.Name,
+			Timestamp: nodeMetric.Timestamp.Time,
+			Window:    nodeMetric.Window.Duration,
+		}
 		for name, quantity := range resourceList {
 			switch name { //nolint:exhaustive // it is ok
 			case v1.ResourceMemory:
@@ -80,16 +97,15 @@ func listNodeMetrics(
 	api metricsv1beta1.MetricsV1beta1Interface,
 	opts metav1.ListOptions,
 ) (*v1beta1.NodeMetricsList, error) {
-	result := &v1beta1.NodeMetricsList{}
-	for {
+	items, err := retry.List(ctx, opts, func(ctx context.Context, opts metav1.ListOptions) ([]v1beta1.NodeMetrics, string, error) {
 		nodeMetrics, err := api.NodeMetricses().List(ctx, opts)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
-		result.Items = append(result.Items, nodeMetrics.Items...)
-		if nodeMetrics.Continue == "" {
-			return result, nil
-		}
-		opts.Continue = nodeMetrics.Continue
+		return nodeMetrics.Items, nodeMetrics.Continue, nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return &v1beta1.NodeMetricsList{Items: items}, nil
 }
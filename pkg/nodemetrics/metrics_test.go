@@ -2,9 +2,12 @@ package nodemetrics
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -93,6 +96,8 @@ func TestMetricsFollowsPagination(t *testing.T) {
 			Items: []metricsv1beta1.NodeMetrics{
 				{
 					ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+					Timestamp:  metav1.NewTime(time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)),
+					Window:     metav1.Duration{Duration: 20 * time.Second},
 					Usage: v1.ResourceList{
 						v1.ResourceCPU: resource.MustParse("100m"),
 					},
@@ -123,5 +128,30 @@ func TestMetricsFollowsPagination(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, result, 2)
 	require.Equal(t, "node-1", result[0].Name)
+	require.Equal(t, time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC), result[0].Timestamp)
+	require.Equal(t, 20*time.Second, result[0].Window)
 	require.Equal(t, "node-2", result[1].Name)
 }
+
+func TestMetricsRetries(t *testing.T) {
+	client := metricsfake.NewSimpleClientset()
+	failures := 1
+	client.PrependReactor("list", "nodes", func(ktesting.Action) (bool, runtime.Object, error) {
+		if failures > 0 {
+			failures--
+			return true, nil, apierrors.NewServiceUnavailable("the server is currently unable to handle the request")
+		}
+		return true, &metricsv1beta1.NodeMetricsList{Items: []metricsv1beta1.NodeMetrics{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		}}, nil
+	})
+
+	result, err := Metrics(retry.WithPolicy(t.Context(), retry.Policy{Retries: 2, Delay: time.Millisecond}), client.MetricsV1beta1(), MetricsFilter{}, "")
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Zero(t, failures)
+
+	failures = 2
+	_, err = Metrics(retry.WithPolicy(t.Context(), retry.Policy{Retries: 1, Delay: time.Millisecond}), client.MetricsV1beta1(), MetricsFilter{}, "")
+	require.True(t, apierrors.IsServiceUnavailable(err))
+}
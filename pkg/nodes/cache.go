@@ -0,0 +1,60 @@
+package nodes
+
+import (
+	"context"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/informers"
+	v1 "k8s.io/api/core/v1" //nolint:revive // it is ok
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Cache lists nodes from informer caches. Every distinct filter starts an
+// informer on first use, later calls only read its cache.
+type Cache struct {
+	informers *informers.Set[NodeFilter]
+}
+
+// NewCache returns a cache whose informers run until ctx is done.
+func NewCache(ctx context.Context) *Cache {
+	return &Cache{informers: informers.NewSet[NodeFilter](ctx)}
+}
+
+// Nodes is Nodes reading from the cache. A named node that does not exist
+// is reported with a NotFound error the way Get does.
+func (c *Cache) Nodes(ctx context.Context, corev1Ifc corev1.CoreV1Interface, filter NodeFilter, name string) (NodeList, error) {
+	if name != "" {
+		filter = NodeFilter{FieldSelector: "metadata.name=" + name}
+	}
+	withSelectors := func(opts metav1.ListOptions) metav1.ListOptions {
+		opts.LabelSelector = filter.LabelSelector
+		opts.FieldSelector = filter.FieldSelector
+		return opts
+	}
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return corev1Ifc.Nodes().List(ctx, withSelectors(opts))
+		},
+		WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			return corev1Ifc.Nodes().Watch(ctx, withSelectors(opts))
+		},
+	}
+	objects, err := c.informers.Informer(filter, lw, &v1.Node{}).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(NodeList, 0, len(objects))
+	for _, object := range objects {
+		if node, ok := object.(*v1.Node); ok {
+			result = append(result, convertNode(*node))
+		}
+	}
+	if name != "" && len(result) == 0 {
+		return nil, apierrors.NewNotFound(v1.Resource("nodes"), name)
+	}
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package nodes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCacheNodes(t *testing.T) {
+	ctx := t.Context()
+	node := func(name string, labels map[string]string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status: v1.NodeStatus{
+				Capacity:    v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+				Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("3")},
+			},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		node("node-b", map[string]string{"pool": "gpu"}),
+		node("node-a", map[string]string{"pool": "cpu"}),
+	)
+	cache := NewCache(ctx)
+
+	t.Run("lists nodes ordered by name", func(t *testing.T) {
+		result, err := cache.Nodes(ctx, client.CoreV1(), NodeFilter{}, "")
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.Equal(t, "node-a", result[0].Name)
+		require.Equal(t, int64(4000), result[0].CPU)
+		require.Equal(t, int64(3000), result[0].AllocatableCPU)
+		require.Equal(t, "node-b", result[1].Name)
+	})
+
+	t.Run("keeps a listing per label selector", func(t *testing.T) {
+		result, err := cache.Nodes(ctx, client.CoreV1(), NodeFilter{LabelSelector: "pool=gpu"}, "")
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, "node-b", result[0].Name)
+	})
+
+	t.Run("follows node changes", func(t *testing.T) {
+		updated := node("node-a", map[string]string{"pool": "cpu"})
+		updated.Spec.Unschedulable = true
+		_, err := client.CoreV1().Nodes().Update(ctx, updated, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			result, err := cache.Nodes(ctx, client.CoreV1(), NodeFilter{}, "")
+			return err == nil && result[0].Unschedulable
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("reports a missing named node", func(t *testing.T) {
+		_, err := cache.Nodes(ctx, fake.NewSimpleClientset().CoreV1(), NodeFilter{}, "node-c")
+		require.True(t, apierrors.IsNotFound(err))
+	})
+}
@@ -2,7 +2,10 @@ package nodes
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	v1 "k8s.io/api/core/v1" //nolint:revive // it is ok
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -15,6 +18,7 @@ type NodeFilter struct {
 
 type Node struct {
 	Name                        string
+	Labels                      map[string]string
 	CPU                         int64
 	Memory                      int64
 	AllocatableCPU              int64
@@ -27,10 +31,38 @@ type Node struct {
 	StorageEphemeral            int64
 	AllocatableStorageEphemeral int64
 	UsedStorageEphemeral        int64
+	// AllocatablePods is the number of pods the node accepts.
+	AllocatablePods int64
+	// Extended and AllocatableExtended hold capacity and allocatable of other
+	// resources with non-zero capacity, e.g. nvidia.com/gpu or hugepages-2Mi.
+	Extended            map[string]int64
+	AllocatableExtended map[string]int64
+	// Ready reports whether the node Ready condition is True.
+	Ready bool
+	// Unschedulable is set for cordoned nodes.
+	Unschedulable bool
+	// Conditions lists the problem conditions that are True, e.g. MemoryPressure.
+	Conditions []string
+	Taints     []Taint
+}
+
+// Taint is a node taint as printed by kubectl: key=value:effect.
+type Taint struct {
+	Key    string         `json:"key" yaml:"key"`
+	Value  string         `json:"value,omitempty" yaml:"value,omitempty"`
+	Effect v1.TaintEffect `json:"effect" yaml:"effect"`
 }
 
 type NodeList []Node
 
+// problemConditions are node conditions that signal trouble when True.
+var problemConditions = []v1.NodeConditionType{
+	v1.NodeMemoryPressure,
+	v1.NodeDiskPressure,
+	v1.NodePIDPressure,
+	v1.NodeNetworkUnavailable,
+}
+
 func Nodes(ctx context.Context, corev1Ifc corev1.CoreV1Interface, filter NodeFilter, name string) (NodeList, error) {
 	var nodes *v1.NodeList
 	var err error
@@ -45,7 +77,9 @@ func Nodes(ctx context.Context, corev1Ifc corev1.CoreV1Interface, filter NodeFil
 		}
 	} else {
 		var node *v1.Node
-		node, err = client.Get(ctx, name, metav1.GetOptions{})
+		node, err = retry.Do(ctx, func(ctx context.Context) (*v1.Node, error) {
+			return client.Get(ctx, name, metav1.GetOptions{})
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -56,61 +90,150 @@ func Nodes(ctx context.Context, corev1Ifc corev1.CoreV1Interface, filter NodeFil
 
 	result := make(NodeList, 0, len(nodes.Items))
 	for _, node := range nodes.Items {
-		memory, ok := node.Status.Capacity.Memory().AsInt64()
-		if !ok {
-			memory = int64(node.Status.Capacity.Memory().AsApproximateFloat64())
-		}
-		allocatableMemory, ok := node.Status.Allocatable.Memory().AsInt64()
-		if !ok {
-			allocatableMemory = int64(node.Status.Allocatable.Memory().AsApproximateFloat64())
+		result = append(result, convertNode(node))
+	}
+	return result, err
+}
+
+func convertNode(node v1.Node) Node {
+	memory, ok := node.Status.Capacity.Memory().AsInt64()
+	if !ok {
+		memory = int64(node.Status.Capacity.Memory().AsApproximateFloat64())
+	}
+	allocatableMemory, ok := node.Status.Allocatable.Memory().AsInt64()
+	if !ok {
+		allocatableMemory = int64(node.Status.Allocatable.Memory().AsApproximateFloat64())
+	}
+	storage, ok := node.Status.Capacity.Storage().AsInt64()
+	if !ok {
+		storage = int64(node.Status.Capacity.Storage().AsApproximateFloat64())
+	}
+	storageEphemeral, ok := node.Status.Capacity.StorageEphemeral().AsInt64()
+	if !ok {
+		storageEphemeral = int64(node.Status.Capacity.StorageEphemeral().AsApproximateFloat64())
+	}
+	allocatableStorage, ok := node.Status.Allocatable.Storage().AsInt64()
+	if !ok {
+		allocatableStorage = int64(node.Status.Allocatable.Storage().AsApproximateFloat64())
+	}
+	allocatableStorageEphemeral, ok := node.Status.Allocatable.StorageEphemeral().AsInt64()
+	if !ok {
+		allocatableStorageEphemeral = int64(node.Status.Allocatable.StorageEphemeral().AsApproximateFloat64())
+	}
+	nodeResource := Node{
+		Name:                        node.Name,
+		Labels:                      node.Labels,
+		CPU:                         node.Status.Capacity.Cpu().MilliValue(),
+		AllocatableCPU:              node.Status.Allocatable.Cpu().MilliValue(),
+		Memory:                      memory,
+		AllocatableMemory:           allocatableMemory,
+		Storage:                     storage,
+		StorageEphemeral:            storageEphemeral,
+		AllocatableStorage:          allocatableStorage,
+		AllocatableStorageEphemeral: allocatableStorageEphemeral,
+		AllocatablePods:             node.Status.Allocatable.Pods().Value(),
+	}
+	nodeResource.Extended, nodeResource.AllocatableExtended = extendedResources(node.Status)
+	nodeResource.Ready, nodeResource.Conditions = nodeConditions(node.Status.Conditions)
+	nodeResource.Unschedulable = node.Spec.Unschedulable
+	nodeResource.Taints = nodeTaints(node.Spec.Taints)
+	nodeResource.UsedCPU = nodeResource.CPU - nodeResource.AllocatableCPU
+	nodeResource.UsedMemory = nodeResource.Memory - nodeResource.AllocatableMemory
+	nodeResource.UsedStorage = nodeResource.Storage - nodeResource.AllocatableStorage
+	nodeResource.UsedStorageEphemeral = nodeResource.StorageEphemeral - nodeResource.AllocatableStorageEphemeral
+	return nodeResource
+}
+
+// IsSchedulable reports whether new pods can land on the node: it is Ready,
+// not cordoned and has no NoSchedule or NoExecute taint.
+func (n Node) IsSchedulable() bool {
+	if !n.Ready || n.Unschedulable {
+		return false
+	}
+	for _, taint := range n.Taints {
+		if taint.PreventsScheduling() {
+			return false
 		}
-		storage, ok := node.Status.Capacity.Storage().AsInt64()
-		if !ok {
-			storage = int64(node.Status.Capacity.Storage().AsApproximateFloat64())
+	}
+	return true
+}
+
+// PreventsScheduling reports whether the taint keeps pods without a matching
+// toleration off the node.
+func (t Taint) PreventsScheduling() bool {
+	return t.Effect == v1.TaintEffectNoSchedule || t.Effect == v1.TaintEffectNoExecute
+}
+
+func (t Taint) String() string {
+	if t.Value == "" {
+		return fmt.Sprintf("%s:%s", t.Key, t.Effect)
+	}
+	return fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
+}
+
+func nodeConditions(conditions []v1.NodeCondition) (bool, []string) {
+	var ready bool
+	var problems []string
+	for _, condition := range conditions {
+		if condition.Type == v1.NodeReady {
+			ready = condition.Status == v1.ConditionTrue
+			continue
 		}
-		storageEphemeral, ok := node.Status.Capacity.StorageEphemeral().AsInt64()
-		if !ok {
-			storageEphemeral = int64(node.Status.Capacity.StorageEphemeral().AsApproximateFloat64())
+		if condition.Status == v1.ConditionTrue && slices.Contains(problemConditions, condition.Type) {
+			problems = append(problems, string(condition.Type))
 		}
-		allocatableStorage, ok := node.Status.Allocatable.Storage().AsInt64()
-		if !ok {
-			allocatableStorage = int64(node.Status.Allocatable.Storage().AsApproximateFloat64())
+	}
+	return ready, problems
+}
+
+func nodeTaints(taints []v1.Taint) []Taint {
+	if len(taints) == 0 {
+		return nil
+	}
+	result := make([]Taint, 0, len(taints))
+	for _, taint := range taints {
+		result = append(result, Taint{Key: taint.Key, Value: taint.Value, Effect: taint.Effect})
+	}
+	return result
+}
+
+func extendedResources(status v1.NodeStatus) (map[string]int64, map[string]int64) {
+	var capacity, allocatable map[string]int64
+	for name, quantity := range status.Capacity {
+		if !isExtendedResource(name) || quantity.IsZero() {
+			continue
 		}
-		allocatableStorageEphemeral, ok := node.Status.Allocatable.StorageEphemeral().AsInt64()
-		if !ok {
-			allocatableStorageEphemeral = int64(node.Status.Allocatable.StorageEphemeral().AsApproximateFloat64())
+		if capacity == nil {
+			capacity = make(map[string]int64)
+			allocatable = make(map[string]int64)
 		}
-		nodeResource := Node{
-			Name:                        node.Name,
-			CPU:                         node.Status.Capacity.Cpu().MilliValue(),
-			AllocatableCPU:              node.Status.Allocatable.Cpu().MilliValue(),
-			Memory:                      memory,
-			AllocatableMemory:           allocatableMemory,
-			Storage:                     storage,
-			StorageEphemeral:            storageEphemeral,
-			AllocatableStorage:          allocatableStorage,
-			AllocatableStorageEphemeral: allocatableStorageEphemeral,
+		capacity[string(name)] = quantity.Value()
+		if allocatableQuantity, ok := status.Allocatable[name]; ok {
+			allocatable[string(name)] = allocatableQuantity.Value()
 		}
-		nodeResource.UsedCPU = nodeResource.CPU - nodeResource.AllocatableCPU
-		nodeResource.UsedMemory = nodeResource.Memory - nodeResource.AllocatableMemory
-		nodeResource.UsedStorage = nodeResource.Storage - nodeResource.AllocatableStorage
-		nodeResource.UsedStorageEphemeral = nodeResource.StorageEphemeral - nodeResource.AllocatableStorageEphemeral
-		result = append(result, nodeResource)
 	}
-	return result, err
+	return capacity, allocatable
+}
+
+func isExtendedResource(name v1.ResourceName) bool {
+	switch name {
+	case v1.ResourceCPU, v1.ResourceMemory, v1.ResourceStorage, v1.ResourceEphemeralStorage, v1.ResourcePods:
+		return false
+	default:
+		return true
+	}
 }
 
 func listNodes(ctx context.Context, client corev1.NodeInterface, opts metav1.ListOptions) (*v1.NodeList, error) {
-	result := &v1.NodeList{}
-	for {
+	items, err := retry.List(ctx, opts, func(ctx context.Context, opts metav1.ListOptions) ([]v1.Node, string, error) {
 		nodes, err := client.List(ctx, opts)
 		if err != nil {
-			return nil, err
-		}
-		result.Items = append(result.Items, nodes.Items...)
-		if nodes.Continue == "" {
-			return result, nil
+			return nil, "", err
 		}
-		opts.Continue = nodes.Continue
+		return nodes.Items, nodes.Continue, nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return &v1.NodeList{Items: items}, nil
 }
@@ -2,9 +2,12 @@ package nodes
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -72,6 +75,70 @@ func TestNodes(t *testing.T) {
 		require.Equal(t, int64(2000), result[1].CPU)
 		require.Equal(t, int64(1900), result[1].AllocatableCPU)
 		require.Equal(t, int64(100), result[1].UsedCPU)
+		require.Nil(t, result[1].Extended)
+	})
+
+	t.Run("extended resources", func(t *testing.T) {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu-node"},
+			Status: v1.NodeStatus{
+				Capacity: v1.ResourceList{
+					v1.ResourceCPU:   resource.MustParse("8"),
+					v1.ResourcePods:  resource.MustParse("110"),
+					"nvidia.com/gpu": resource.MustParse("4"),
+					"hugepages-2Mi":  resource.MustParse("1Gi"),
+					"hugepages-1Gi":  resource.MustParse("0"),
+				},
+				Allocatable: v1.ResourceList{
+					v1.ResourceCPU:   resource.MustParse("8"),
+					v1.ResourcePods:  resource.MustParse("110"),
+					"nvidia.com/gpu": resource.MustParse("3"),
+					"hugepages-2Mi":  resource.MustParse("1Gi"),
+					"hugepages-1Gi":  resource.MustParse("0"),
+				},
+			},
+		}
+		client := fake.NewSimpleClientset(node)
+
+		result, err := Nodes(ctx, client.CoreV1(), NodeFilter{}, "gpu-node")
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, map[string]int64{"nvidia.com/gpu": 4, "hugepages-2Mi": 1024 * 1024 * 1024}, result[0].Extended)
+		require.Equal(t, map[string]int64{"nvidia.com/gpu": 3, "hugepages-2Mi": 1024 * 1024 * 1024}, result[0].AllocatableExtended)
+		require.Equal(t, int64(110), result[0].AllocatablePods)
+	})
+
+	t.Run("conditions, cordon and taints", func(t *testing.T) {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Spec: v1.NodeSpec{
+				Unschedulable: true,
+				Taints: []v1.Taint{
+					{Key: "node-role.kubernetes.io/control-plane", Effect: v1.TaintEffectNoSchedule},
+					{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectPreferNoSchedule},
+				},
+			},
+			Status: v1.NodeStatus{
+				Conditions: []v1.NodeCondition{
+					{Type: v1.NodeReady, Status: v1.ConditionTrue},
+					{Type: v1.NodeMemoryPressure, Status: v1.ConditionTrue},
+					{Type: v1.NodeDiskPressure, Status: v1.ConditionFalse},
+					{Type: v1.NodePIDPressure, Status: v1.ConditionUnknown},
+				},
+			},
+		}
+		client := fake.NewSimpleClientset(node)
+
+		result, err := Nodes(ctx, client.CoreV1(), NodeFilter{}, "node-1")
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.True(t, result[0].Ready)
+		require.True(t, result[0].Unschedulable)
+		require.Equal(t, []string{"MemoryPressure"}, result[0].Conditions)
+		require.Equal(t, []Taint{
+			{Key: "node-role.kubernetes.io/control-plane", Effect: v1.TaintEffectNoSchedule},
+			{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectPreferNoSchedule},
+		}, result[0].Taints)
 	})
 
 	t.Run("get specific node", func(t *testing.T) {
@@ -149,6 +216,7 @@ func TestNodes(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, result, 1)
 		require.Equal(t, "node-1", result[0].Name)
+		require.Equal(t, map[string]string{"env": "prod"}, result[0].Labels)
 	})
 
 	t.Run("empty list", func(t *testing.T) {
@@ -228,3 +296,60 @@ func TestNodesFollowsPagination(t *testing.T) {
 	require.Equal(t, "node-1", result[0].Name)
 	require.Equal(t, "node-2", result[1].Name)
 }
+
+func TestNodesRestartsExpiredList(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	type listOptionsGetter interface {
+		GetListOptions() metav1.ListOptions
+	}
+	lists := 0
+	client.PrependReactor("list", "nodes", func(action ktesting.Action) (bool, runtime.Object, error) {
+		listAction, ok := action.(listOptionsGetter)
+		require.True(t, ok)
+		lists++
+		switch {
+		case listAction.GetListOptions().Continue == "":
+			return true, &v1.NodeList{
+				ListMeta: metav1.ListMeta{Continue: "page-2"},
+				Items:    []v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}},
+			}, nil
+		case lists == 2:
+			return true, nil, apierrors.NewResourceExpired("the provided continue parameter is too old")
+		default:
+			return true, &v1.NodeList{Items: []v1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}}}}, nil
+		}
+	})
+
+	result, err := Nodes(retry.WithPolicy(t.Context(), retry.Policy{Retries: 2, Delay: time.Millisecond}), client.CoreV1(), NodeFilter{}, "")
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, "node-1", result[0].Name)
+	require.Equal(t, "node-2", result[1].Name)
+	require.Equal(t, 4, lists)
+}
+
+func TestNodeIsSchedulable(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     Node
+		expected bool
+	}{
+		{name: "ready", node: Node{Ready: true}, expected: true},
+		{name: "not ready", node: Node{}, expected: false},
+		{name: "cordoned", node: Node{Ready: true, Unschedulable: true}, expected: false},
+		{name: "no schedule taint", node: Node{Ready: true, Taints: []Taint{{Key: "a", Effect: v1.TaintEffectNoSchedule}}}, expected: false},
+		{name: "no execute taint", node: Node{Ready: true, Taints: []Taint{{Key: "a", Effect: v1.TaintEffectNoExecute}}}, expected: false},
+		{name: "prefer no schedule taint", node: Node{Ready: true, Taints: []Taint{{Key: "a", Effect: v1.TaintEffectPreferNoSchedule}}}, expected: true},
+		{name: "under pressure", node: Node{Ready: true, Conditions: []string{"DiskPressure"}}, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.node.IsSchedulable())
+		})
+	}
+}
+
+func TestTaintString(t *testing.T) {
+	require.Equal(t, "dedicated=gpu:NoSchedule", Taint{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}.String())
+	require.Equal(t, "node.kubernetes.io/not-ready:NoExecute", Taint{Key: "node.kubernetes.io/not-ready", Effect: v1.TaintEffectNoExecute}.String())
+}
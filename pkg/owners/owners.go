@@ -0,0 +1,129 @@
+package owners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	batchv1 "k8s.io/client-go/kubernetes/typed/batch/v1"
+)
+
+const (
+	KindReplicaSet = "ReplicaSet"
+	KindJob        = "Job"
+	KindPod        = "Pod"
+
+	// maxDepth guards Resolve against ownership cycles.
+	maxDepth = 8
+)
+
+// Reference identifies a namespaced object by kind and name.
+type Reference struct {
+	Namespace string
+	Kind      string
+	Name      string
+}
+
+// Controllers maps ReplicaSets and Jobs to the objects controlling them, for
+// example a Deployment or a CronJob.
+type Controllers map[Reference]Reference
+
+// Fetch lists ReplicaSets and Jobs of the namespace (all namespaces when empty)
+// and records their controllers.
+func Fetch(
+	ctx context.Context,
+	appsClient appsv1.AppsV1Interface,
+	batchClient batchv1.BatchV1Interface,
+	namespace string,
+) (Controllers, error) {
+	result := Controllers{}
+	replicaSetsErr := listReplicaSets(ctx, appsClient, namespace, result)
+	if replicaSetsErr != nil {
+		replicaSetsErr = fmt.Errorf("list replica sets: %w", replicaSetsErr)
+	}
+	jobsErr := listJobs(ctx, batchClient, namespace, result)
+	if jobsErr != nil {
+		jobsErr = fmt.Errorf("list jobs: %w", jobsErr)
+	}
+	if err := errors.Join(replicaSetsErr, jobsErr); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Resolve walks up the controllers of ref and returns the topmost one. A
+// reference without a known controller is returned unchanged.
+func (c Controllers) Resolve(ref Reference) Reference {
+	for range maxDepth {
+		owner, ok := c[ref]
+		if !ok {
+			return ref
+		}
+		ref = owner
+	}
+	return ref
+}
+
+// Merge adds the controllers of other to c.
+func (c Controllers) Merge(other Controllers) {
+	for ref, owner := range other {
+		c[ref] = owner
+	}
+}
+
+func (c Controllers) add(meta metav1.ObjectMeta, kind string) {
+	controller := metav1.GetControllerOf(&meta)
+	if controller == nil {
+		return
+	}
+	c[Reference{Namespace: meta.Namespace, Kind: kind, Name: meta.Name}] = Reference{
+		Namespace: meta.Namespace,
+		Kind:      controller.Kind,
+		Name:      controller.Name,
+	}
+}
+
+func listReplicaSets(ctx context.Context, client appsv1.AppsV1Interface, namespace string, result Controllers) error {
+	replicaSets, err := retry.List(ctx, metav1.ListOptions{}, func(ctx context.Context, opts metav1.ListOptions) ([]metav1.ObjectMeta, string, error) {
+		list, err := client.ReplicaSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		metas := make([]metav1.ObjectMeta, 0, len(list.Items))
+		for _, replicaSet := range list.Items {
+			metas = append(metas, replicaSet.ObjectMeta)
+		}
+		return metas, list.Continue, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, meta := range replicaSets {
+		result.add(meta, KindReplicaSet)
+	}
+	return nil
+}
+
+func listJobs(ctx context.Context, client batchv1.BatchV1Interface, namespace string, result Controllers) error {
+	jobs, err := retry.List(ctx, metav1.ListOptions{}, func(ctx context.Context, opts metav1.ListOptions) ([]metav1.ObjectMeta, string, error) {
+		list, err := client.Jobs(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		metas := make([]metav1.ObjectMeta, 0, len(list.Items))
+		for _, job := range list.Items {
+			metas = append(metas, job.ObjectMeta)
+		}
+		return metas, list.Continue, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, meta := range jobs {
+		result.add(meta, KindJob)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package owners
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func controlledBy(kind, name string) []metav1.OwnerReference {
+	isController := true
+	return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &isController}}
+}
+
+func TestFetch(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("replica sets and jobs", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+				Name: "web-5d4f8", Namespace: "default", OwnerReferences: controlledBy("Deployment", "web"),
+			}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "orphan", Namespace: "default"}},
+			&batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+				Name: "backup-29000000", Namespace: "ops", OwnerReferences: controlledBy("CronJob", "backup"),
+			}},
+		)
+
+		result, err := Fetch(ctx, client.AppsV1(), client.BatchV1(), "")
+		require.NoError(t, err)
+		require.Equal(t, Controllers{
+			{Namespace: "default", Kind: KindReplicaSet, Name: "web-5d4f8"}: {Namespace: "default", Kind: "Deployment", Name: "web"},
+			{Namespace: "ops", Kind: KindJob, Name: "backup-29000000"}:      {Namespace: "ops", Kind: "CronJob", Name: "backup"},
+		}, result)
+	})
+
+	t.Run("list errors are joined", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("list", "replicasets", func(ktesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("forbidden")
+		})
+		client.PrependReactor("list", "jobs", func(ktesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("timeout")
+		})
+
+		_, err := Fetch(ctx, client.AppsV1(), client.BatchV1(), "default")
+		require.ErrorContains(t, err, "list replica sets: forbidden")
+		require.ErrorContains(t, err, "list jobs: timeout")
+	})
+
+	t.Run("follows pagination", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+		calls := 0
+		client.PrependReactor("list", "replicasets", func(action ktesting.Action) (bool, runtime.Object, error) {
+			calls++
+			listAction, ok := action.(ktesting.ListActionImpl)
+			require.True(t, ok)
+			if listAction.ListOptions.Continue == "" {
+				return true, &appsv1.ReplicaSetList{
+					ListMeta: metav1.ListMeta{Continue: "next"},
+					Items: []appsv1.ReplicaSet{{ObjectMeta: metav1.ObjectMeta{
+						Name: "a-1", Namespace: "default", OwnerReferences: controlledBy("Deployment", "a"),
+					}}},
+				}, nil
+			}
+			return true, &appsv1.ReplicaSetList{
+				Items: []appsv1.ReplicaSet{{ObjectMeta: metav1.ObjectMeta{
+					Name: "b-1", Namespace: "default", OwnerReferences: controlledBy("Deployment", "b"),
+				}}},
+			}, nil
+		})
+
+		result, err := Fetch(ctx, client.AppsV1(), client.BatchV1(), "default")
+		require.NoError(t, err)
+		require.Equal(t, 2, calls)
+		require.Len(t, result, 2)
+	})
+}
+
+func TestControllersResolve(t *testing.T) {
+	controllers := Controllers{
+		{Namespace: "default", Kind: KindReplicaSet, Name: "web-5d4f8"}: {Namespace: "default", Kind: "Deployment", Name: "web"},
+		{Namespace: "ops", Kind: KindJob, Name: "backup-1"}:             {Namespace: "ops", Kind: "CronJob", Name: "backup"},
+		{Namespace: "loop", Kind: "A", Name: "a"}:                       {Namespace: "loop", Kind: "B", Name: "b"},
+		{Namespace: "loop", Kind: "B", Name: "b"}:                       {Namespace: "loop", Kind: "A", Name: "a"},
+	}
+
+	require.Equal(t,
+		Reference{Namespace: "default", Kind: "Deployment", Name: "web"},
+		controllers.Resolve(Reference{Namespace: "default", Kind: KindReplicaSet, Name: "web-5d4f8"}),
+	)
+	require.Equal(t,
+		Reference{Namespace: "ops", Kind: "CronJob", Name: "backup"},
+		controllers.Resolve(Reference{Namespace: "ops", Kind: KindJob, Name: "backup-1"}),
+	)
+	require.Equal(t,
+		Reference{Namespace: "default", Kind: "StatefulSet", Name: "db"},
+		controllers.Resolve(Reference{Namespace: "default", Kind: "StatefulSet", Name: "db"}),
+	)
+	require.NotPanics(t, func() { controllers.Resolve(Reference{Namespace: "loop", Kind: "A", Name: "a"}) })
+}
+
+func TestControllersMerge(t *testing.T) {
+	controllers := Controllers{{Kind: KindJob, Name: "a"}: {Kind: "CronJob", Name: "a"}}
+	controllers.Merge(Controllers{{Kind: KindJob, Name: "b"}: {Kind: "CronJob", Name: "b"}})
+	require.Len(t, controllers, 2)
+}
@@ -0,0 +1,169 @@
+// Package partial keeps a request going when some of the namespaces, nodes or
+// clusters it queries fail. The scopes that succeeded are returned and every
+// failed scope is recorded as a warning of the request.
+package partial
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ExitCode is the exit code of a command rendering partial results.
+const ExitCode = 3
+
+// Scope is the kind of object a failed query was limited to.
+type Scope string
+
+const (
+	Namespace Scope = "namespace"
+	Node      Scope = "node"
+	Cluster   Scope = "cluster"
+)
+
+// Warning describes a scope whose query failed and whose objects are missing
+// from the result.
+type Warning struct {
+	// Cluster is the kubeconfig context of the scope, set when several
+	// contexts are queried.
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	Scope   Scope  `json:"scope" yaml:"scope"`
+	Name    string `json:"name" yaml:"name"`
+	Message string `json:"message" yaml:"message"`
+}
+
+func (w Warning) String() string {
+	scope := fmt.Sprintf("%s %s", w.Scope, w.Name)
+	if w.Cluster != "" {
+		scope = fmt.Sprintf("cluster %s %s", w.Cluster, scope)
+	}
+	return fmt.Sprintf("%s: %s", scope, w.Message)
+}
+
+type Warnings []Warning
+
+// String renders the banner shown above partial results, empty without
+// warnings.
+func (w Warnings) String() string {
+	if len(w) == 0 {
+		return ""
+	}
+	scopes := "scopes"
+	if len(w) == 1 {
+		scopes = "scope"
+	}
+	var builder strings.Builder
+	_, _ = fmt.Fprintf(&builder, "WARNING: partial results, %d %s failed:", len(w), scopes)
+	for _, warning := range w {
+		_, _ = fmt.Fprintf(&builder, "\n  %s", warning)
+	}
+	return builder.String()
+}
+
+// Error is returned along with the data of a request some scopes of which
+// failed.
+type Error struct {
+	Warnings Warnings
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, 0, len(e.Warnings))
+	for _, warning := range e.Warnings {
+		messages = append(messages, warning.String())
+	}
+	return "partial results: " + strings.Join(messages, "; ")
+}
+
+type (
+	enabledKey   struct{}
+	collectorKey struct{}
+	clusterKey   struct{}
+)
+
+type collector struct {
+	mu       sync.Mutex
+	warnings Warnings
+}
+
+func (c *collector) add(warning Warning) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warnings = append(c.warnings, warning)
+}
+
+// Enable returns a context whose requests, run with Collect, return partial
+// results.
+func Enable(ctx context.Context) context.Context {
+	return context.WithValue(ctx, enabledKey{}, true)
+}
+
+// WithCluster returns a context whose warnings are recorded for cluster.
+func WithCluster(ctx context.Context, cluster string) context.Context {
+	return context.WithValue(ctx, clusterKey{}, cluster)
+}
+
+// Collect runs request collecting the warnings of its failed scopes when ctx
+// enables partial results. Warnings are returned as an *Error along with the
+// data. Requests nested in a collecting request add to its warnings.
+func Collect[T any](ctx context.Context, request func(context.Context) (T, error)) (T, error) {
+	if enabled, _ := ctx.Value(enabledKey{}).(bool); !enabled || collectorFrom(ctx) != nil {
+		return request(ctx)
+	}
+	c := &collector{}
+	data, err := request(context.WithValue(ctx, collectorKey{}, c))
+	if err != nil || len(c.warnings) == 0 {
+		return data, err
+	}
+	warnings := slices.Clone(c.warnings)
+	slices.SortFunc(warnings, func(a, b Warning) int {
+		return cmp.Or(
+			cmp.Compare(a.Cluster, b.Cluster),
+			cmp.Compare(a.Scope, b.Scope),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return data, &Error{Warnings: warnings}
+}
+
+// Record adds a warning for a failed scope to the request of ctx. It does
+// nothing outside of a collecting request.
+func Record(ctx context.Context, scope Scope, name string, err error) {
+	c := collectorFrom(ctx)
+	if c == nil {
+		return
+	}
+	cluster, _ := ctx.Value(clusterKey{}).(string)
+	c.add(Warning{Cluster: cluster, Scope: scope, Name: name, Message: err.Error()})
+}
+
+// Join concatenates the results of scopes queried one by one, errs holding
+// the error of every scope. Within a collecting request the failed scopes are
+// recorded as warnings and the others returned; otherwise, or when every
+// scope failed, the joined errors are returned.
+func Join[S ~[]E, E any](ctx context.Context, scope Scope, names []string, results []S, errs []error) (S, error) {
+	err := errors.Join(errs...)
+	if err == nil {
+		return slices.Concat(results...), nil
+	}
+	if collectorFrom(ctx) == nil || !slices.Contains(errs, nil) {
+		return nil, err
+	}
+	var result S
+	for i, scopeErr := range errs {
+		if scopeErr != nil {
+			Record(ctx, scope, names[i], scopeErr)
+			continue
+		}
+		result = append(result, results[i]...)
+	}
+	return result, nil
+}
+
+func collectorFrom(ctx context.Context) *collector {
+	c, _ := ctx.Value(collectorKey{}).(*collector)
+	return c
+}
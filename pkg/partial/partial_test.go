@@ -0,0 +1,103 @@
+package partial
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errForbidden = errors.New("forbidden")
+
+func TestJoin(t *testing.T) {
+	names := []string{"team-a", "team-b", "team-c"}
+	results := [][]string{{"pod-a"}, nil, {"pod-c"}}
+	errs := []error{nil, errForbidden, nil}
+
+	t.Run("fails without partial results", func(t *testing.T) {
+		_, err := Join(Enable(t.Context()), Namespace, names, results, errs)
+		require.ErrorIs(t, err, errForbidden)
+	})
+
+	t.Run("keeps the scopes that succeeded", func(t *testing.T) {
+		data, err := Collect(Enable(t.Context()), func(ctx context.Context) ([]string, error) {
+			return Join(ctx, Namespace, names, results, errs)
+		})
+		require.Equal(t, []string{"pod-a", "pod-c"}, data)
+		var partialErr *Error
+		require.ErrorAs(t, err, &partialErr)
+		require.Equal(t, Warnings{{Scope: Namespace, Name: "team-b", Message: "forbidden"}}, partialErr.Warnings)
+	})
+
+	t.Run("fails when every scope failed", func(t *testing.T) {
+		_, err := Collect(Enable(t.Context()), func(ctx context.Context) ([]string, error) {
+			return Join(ctx, Namespace, names[:1], [][]string{nil}, []error{errForbidden})
+		})
+		require.ErrorIs(t, err, errForbidden)
+		var partialErr *Error
+		require.NotErrorAs(t, err, &partialErr)
+	})
+
+	t.Run("concatenates complete results", func(t *testing.T) {
+		data, err := Join(t.Context(), Namespace, names, results, make([]error, len(names)))
+		require.NoError(t, err)
+		require.Equal(t, []string{"pod-a", "pod-c"}, data)
+	})
+}
+
+func TestCollect(t *testing.T) {
+	t.Run("is disabled by default", func(t *testing.T) {
+		_, err := Collect(t.Context(), func(ctx context.Context) (int, error) {
+			Record(ctx, Node, "node-a", errForbidden)
+			return 1, nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("sorts warnings and keeps the cluster", func(t *testing.T) {
+		data, err := Collect(Enable(t.Context()), func(ctx context.Context) (int, error) {
+			Record(WithCluster(ctx, "prod-us"), Node, "node-b", errForbidden)
+			Record(ctx, Cluster, "prod-eu", errors.New("unreachable"))
+			Record(WithCluster(ctx, "prod-us"), Namespace, "team-a", errForbidden)
+			return 1, nil
+		})
+		require.Equal(t, 1, data)
+		var partialErr *Error
+		require.ErrorAs(t, err, &partialErr)
+		require.Equal(t, Warnings{
+			{Scope: Cluster, Name: "prod-eu", Message: "unreachable"},
+			{Cluster: "prod-us", Scope: Namespace, Name: "team-a", Message: "forbidden"},
+			{Cluster: "prod-us", Scope: Node, Name: "node-b", Message: "forbidden"},
+		}, partialErr.Warnings)
+	})
+
+	t.Run("nested requests add to the outer one", func(t *testing.T) {
+		_, err := Collect(Enable(t.Context()), func(ctx context.Context) (int, error) {
+			inner, innerErr := Collect(ctx, func(ctx context.Context) (int, error) {
+				Record(ctx, Namespace, "team-a", errForbidden)
+				return 1, nil
+			})
+			require.NoError(t, innerErr)
+			return inner, nil
+		})
+		var partialErr *Error
+		require.ErrorAs(t, err, &partialErr)
+		require.Len(t, partialErr.Warnings, 1)
+	})
+}
+
+func TestWarningsString(t *testing.T) {
+	require.Empty(t, Warnings{}.String())
+	require.Equal(t,
+		"WARNING: partial results, 1 scope failed:\n  namespace team-b: forbidden",
+		Warnings{{Scope: Namespace, Name: "team-b", Message: "forbidden"}}.String(),
+	)
+	require.Equal(t,
+		"WARNING: partial results, 2 scopes failed:\n  cluster prod-us node node-a: forbidden\n  cluster prod-eu: unreachable",
+		Warnings{
+			{Cluster: "prod-us", Scope: Node, Name: "node-a", Message: "forbidden"},
+			{Scope: Cluster, Name: "prod-eu", Message: "unreachable"},
+		}.String(),
+	)
+}
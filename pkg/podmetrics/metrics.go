@@ -3,29 +3,67 @@ package podmetrics
 import (
 	"cmp"
 	"context"
-	"errors"
 	"fmt"
 	"slices"
 	"sync"
+	"time"
 
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 )
 
+// Unset marks CPU and memory usage of a container the source did not report,
+// e.g. one the kubelet has no stats for yet.
+const Unset = int64(-1)
+
+// UsageOrZero counts Unset usage as zero.
+func UsageOrZero(value int64) int64 {
+	if value == Unset {
+		return 0
+	}
+	return value
+}
+
 type Metric struct {
-	CPU              int64
-	Memory           int64
-	Storage          int64
-	StorageEphemeral int64
+	CPU              int64 `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+	Memory           int64 `json:"memory,omitempty" yaml:"memory,omitempty"`
+	Storage          int64 `json:"storage,omitempty" yaml:"storage,omitempty"`
+	StorageEphemeral int64 `json:"storage_ephemeral,omitempty" yaml:"storage_ephemeral,omitempty"`
+	// MemoryRSS is the resident set size. Memory is the working set the
+	// kubelet evicts on, which also counts active page cache. Only the kubelet
+	// source reports it.
+	MemoryRSS int64 `json:"memory_rss,omitempty" yaml:"memory_rss,omitempty"`
 }
 type ContainerMetric struct {
-	Name string
-	Metric
+	Name   string `json:"name" yaml:"name"`
+	Metric `yaml:",inline"`
 }
+
+// VolumeMetric is the usage of a persistent volume claim mounted by a pod.
+type VolumeMetric struct {
+	Name      string `json:"name" yaml:"name"`
+	ClaimName string `json:"claim_name" yaml:"claim_name"`
+	Used      int64  `json:"used" yaml:"used"`
+	Capacity  int64  `json:"capacity" yaml:"capacity"`
+}
+
 type PodMetric struct {
 	Namespace  string
 	Name       string
 	Containers []ContainerMetric
+	// StorageEphemeral is the pod ephemeral storage usage: container root
+	// filesystems and logs plus emptyDir volumes. Volumes lists the persistent
+	// volume claims. Both are only reported by the kubelet source, zero and
+	// empty mean not reported.
+	StorageEphemeral int64
+	Volumes          []VolumeMetric
+	// Timestamp is when the usage was collected and Window the interval CPU
+	// usage is averaged over. Zero values mean the source does not report them.
+	Timestamp time.Time
+	Window    time.Duration
 }
 
 type PodMetricList []PodMetric
@@ -34,6 +72,9 @@ type MetricFilter struct {
 	Namespaces    []string
 	LabelSelector string
 	FieldSelector string
+	// Nodes limits the nodes sources reading metrics per node query. It is
+	// ignored by metrics.k8s.io.
+	Nodes []string
 }
 
 // Metrics get pod metrics for MetricFilter
@@ -67,11 +108,7 @@ func Metrics(ctx context.Context, api metricsv1beta1.MetricsV1beta1Interface, fi
 
 	wg.Wait()
 
-	if err := errors.Join(rErrors...); err != nil {
-		return nil, err
-	}
-
-	return slices.Concat(metrics...), nil
+	return partial.Join(ctx, partial.Namespace, filter.Namespaces, metrics, rErrors)
 }
 
 func listMetrics(ctx context.Context, api metricsv1beta1.MetricsV1beta1Interface, filter MetricFilter, namespace string) (PodMetricList, error) {
@@ -80,42 +117,48 @@ func listMetrics(ctx context.Context, api metricsv1beta1.MetricsV1beta1Interface
 		FieldSelector: filter.FieldSelector,
 	}
 
-	result := PodMetricList{}
-	for {
+	podMetrics, err := retry.List(ctx, opts, func(ctx context.Context, opts metav1.ListOptions) ([]v1beta1.PodMetrics, string, error) {
 		podMetrics, err := api.PodMetricses(namespace).List(ctx, opts)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
+		return podMetrics.Items, podMetrics.Continue, nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		for _, podMetric := range podMetrics.Items {
-			metric := PodMetric{Name: podMetric.Name, Namespace: podMetric.Namespace}
-			for _, container := range podMetric.Containers {
-				containerMetric := ContainerMetric{
-					Name: container.Name,
-				}
-				containerMetric.CPU = container.Usage.Cpu().MilliValue()
-				memory, ok := container.Usage.Memory().AsInt64()
-				if ok {
-					containerMetric.Memory = memory
-				}
-				storage, ok := container.Usage.Storage().AsInt64()
-				if ok {
-					containerMetric.Storage = storage
-				}
-				storage, ok = container.Usage.StorageEphemeral().AsInt64()
-				if ok {
-					containerMetric.StorageEphemeral = storage
-				}
-				metric.Containers = append(metric.Containers, containerMetric)
-			}
-			slices.SortFunc(metric.Containers, func(a, b ContainerMetric) int {
-				return cmp.Compare(a.Name, b.Name)
-			})
-			result = append(result, metric)
+	result := make(PodMetricList, 0, len(podMetrics))
+	for _, podMetric := range podMetrics {
+		metric := PodMetric{
+			Name:      podMetric.Name,
+			Namespace: podMetric.Namespace,
+			Timestamp: podMetric.Timestamp.Time,
+			Window:    podMetric.Window.Duration,
 		}
-		if podMetrics.Continue == "" {
-			return result, nil
+		for _, container := range podMetric.Containers {
+			containerMetric := ContainerMetric{
+				Name: container.Name,
+			}
+			containerMetric.CPU = container.Usage.Cpu().MilliValue()
+			memory, ok := container.Usage.Memory().AsInt64()
+			if ok {
+				containerMetric.Memory = memory
+			}
+			storage, ok := container.Usage.Storage().AsInt64()
+			if ok {
+				containerMetric.Storage = storage
+			}
+			storage, ok = container.Usage.StorageEphemeral().AsInt64()
+			if ok {
+				containerMetric.StorageEphemeral = storage
+			}
+			metric.Containers = append(metric.Containers, containerMetric)
 		}
-		opts.Continue = podMetrics.Continue
+		slices.SortFunc(metric.Containers, func(a, b ContainerMetric) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+		result = append(result, metric)
 	}
+	return result, nil
 }
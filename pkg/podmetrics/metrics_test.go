@@ -3,9 +3,12 @@ package podmetrics
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -138,6 +141,8 @@ func TestListMetricsFollowsPagination(t *testing.T) {
 			Items: []metricsv1beta1.PodMetrics{
 				{
 					ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "default"},
+					Timestamp:  metav1.NewTime(time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)),
+					Window:     metav1.Duration{Duration: 30 * time.Second},
 					Containers: []metricsv1beta1.ContainerMetrics{
 						{
 							Name: "app",
@@ -178,9 +183,30 @@ func TestListMetricsFollowsPagination(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, result, 2)
 	require.Equal(t, "pod-1", result[0].Name)
+	require.Equal(t, time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC), result[0].Timestamp)
+	require.Equal(t, 30*time.Second, result[0].Window)
 	require.Equal(t, "pod-2", result[1].Name)
 }
 
+func TestListMetricsRetries(t *testing.T) {
+	client := metricsfake.NewSimpleClientset()
+	failures := 2
+	client.PrependReactor("list", "pods", func(ktesting.Action) (bool, runtime.Object, error) {
+		if failures > 0 {
+			failures--
+			return true, nil, apierrors.NewServiceUnavailable("the server is currently unable to handle the request")
+		}
+		return true, &metricsv1beta1.PodMetricsList{Items: []metricsv1beta1.PodMetrics{
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "default"}},
+		}}, nil
+	})
+
+	result, err := listMetrics(retry.WithPolicy(t.Context(), retry.Policy{Retries: 2, Delay: time.Millisecond}), client.MetricsV1beta1(), MetricFilter{}, "default")
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Zero(t, failures)
+}
+
 func TestMetricsWrapsNamespaceErrors(t *testing.T) {
 	ctx := t.Context()
 	client := metricsfake.NewSimpleClientset()
@@ -0,0 +1,80 @@
+package pods
+
+import (
+	"context"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/informers"
+	v1 "k8s.io/api/core/v1" //nolint:revive // it is used
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// listing identifies the pods kept by one informer.
+type listing struct {
+	namespace     string
+	labelSelector string
+	fieldSelector string
+}
+
+// Cache lists pods from informer caches. Every distinct listing starts an
+// informer on first use, later calls only read its cache.
+type Cache struct {
+	informers *informers.Set[listing]
+}
+
+// NewCache returns a cache whose informers run until ctx is done.
+func NewCache(ctx context.Context) *Cache {
+	return &Cache{informers: informers.NewSet[listing](ctx)}
+}
+
+// Pods is Pods reading from the cache.
+func (c *Cache) Pods(
+	ctx context.Context,
+	coreV1Ifc corev1.CoreV1Interface,
+	filter PodFilter,
+	nodeNames ...string,
+) (PodResourceList, error) {
+	return podsFor(ctx, filter, nodeNames, func(ctx context.Context, filter PodFilter, namespace string) (PodResourceList, error) {
+		return c.listPods(ctx, coreV1Ifc, filter, namespace)
+	})
+}
+
+func (c *Cache) listPods(
+	ctx context.Context,
+	coreV1Ifc corev1.CoreV1Interface,
+	filter PodFilter,
+	namespace string,
+) (PodResourceList, error) {
+	key := listing{
+		namespace:     namespace,
+		labelSelector: filter.LabelSelector,
+		fieldSelector: buildFieldSelector(filter),
+	}
+	withSelectors := func(opts metav1.ListOptions) metav1.ListOptions {
+		opts.LabelSelector = key.labelSelector
+		opts.FieldSelector = key.fieldSelector
+		return opts
+	}
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return coreV1Ifc.Pods(namespace).List(ctx, withSelectors(opts))
+		},
+		WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			return coreV1Ifc.Pods(namespace).Watch(ctx, withSelectors(opts))
+		},
+	}
+	objects, err := c.informers.Informer(key, lw, &v1.Pod{}).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(PodResourceList, 0, len(objects))
+	for _, object := range objects {
+		if pod, ok := object.(*v1.Pod); ok {
+			result = append(result, convertPodToResource(*pod))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package pods
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestCachePods(t *testing.T) {
+	ctx := t.Context()
+	client := fake.NewSimpleClientset(
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}, Spec: v1.PodSpec{NodeName: "node-a"}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}, Spec: v1.PodSpec{NodeName: "node-b"}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "dns", Namespace: "kube-system"}, Spec: v1.PodSpec{NodeName: "node-a"}},
+	)
+	cache := NewCache(ctx)
+
+	result, err := cache.Pods(ctx, client.CoreV1(), PodFilter{Namespaces: []string{"default"}})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, "api", result[0].Name)
+	require.Equal(t, "web", result[1].Name)
+
+	_, err = client.CoreV1().Pods("default").Create(ctx, &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default"}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		result, err = cache.Pods(ctx, client.CoreV1(), PodFilter{Namespaces: []string{"default"}})
+		return err == nil && len(result) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lists := 0
+	for _, action := range client.Actions() {
+		if action.Matches("list", "pods") {
+			lists++
+		}
+	}
+	require.Equal(t, 1, lists, "pods are listed once and then watched")
+}
+
+func TestCachePodsListingsByNamespace(t *testing.T) {
+	ctx := t.Context()
+	client := fake.NewSimpleClientset(
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "dns", Namespace: "kube-system"}},
+	)
+	cache := NewCache(ctx)
+
+	result, err := cache.Pods(ctx, client.CoreV1(), PodFilter{Namespaces: []string{"default", "kube-system"}})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+
+	result, err = cache.Pods(ctx, client.CoreV1(), PodFilter{})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, "default", result[0].Namespace)
+	require.Equal(t, "kube-system", result[1].Namespace)
+}
+
+func TestCachePodsReturnsListError(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	expectedErr := errors.New("pods are forbidden")
+	client.PrependReactor("list", "pods", func(ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, expectedErr
+	})
+
+	_, err := NewCache(t.Context()).Pods(t.Context(), client.CoreV1(), PodFilter{}, "node-a")
+	require.ErrorContains(t, err, `list pods for node "node-a"`)
+	require.ErrorContains(t, err, expectedErr.Error())
+}
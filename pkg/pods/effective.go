@@ -0,0 +1,97 @@
+package pods
+
+func (r Resource) add(other Resource) Resource {
+	return Resource{
+		CPU:              r.CPU + other.CPU,
+		Memory:           r.Memory + other.Memory,
+		Storage:          r.Storage + other.Storage,
+		StorageEphemeral: r.StorageEphemeral + other.StorageEphemeral,
+		Extended:         combineExtended(r.Extended, other.Extended, func(a, b int64) int64 { return a + b }),
+	}
+}
+
+func (r Resource) max(other Resource) Resource {
+	return Resource{
+		CPU:              max(r.CPU, other.CPU),
+		Memory:           max(r.Memory, other.Memory),
+		Storage:          max(r.Storage, other.Storage),
+		StorageEphemeral: max(r.StorageEphemeral, other.StorageEphemeral),
+		Extended:         combineExtended(r.Extended, other.Extended, func(a, b int64) int64 { return max(a, b) }),
+	}
+}
+
+// combineExtended applies combine to every extended resource present in
+// either map, treating a missing one as zero. It returns nil when both are empty.
+func combineExtended(a, b map[string]int64, combine func(a, b int64) int64) map[string]int64 {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	result := make(map[string]int64, max(len(a), len(b)))
+	for name, value := range a {
+		result[name] = combine(value, b[name])
+	}
+	for name, value := range b {
+		if _, ok := a[name]; !ok {
+			result[name] = combine(0, value)
+		}
+	}
+	return result
+}
+
+// addWhereSet adds other only to the resources that are already non-zero in r.
+func (r Resource) addWhereSet(other Resource) Resource {
+	addIfSet := func(value, extra int64) int64 {
+		if value == 0 {
+			return 0
+		}
+		return value + extra
+	}
+	var extended map[string]int64
+	for name, value := range r.Extended {
+		if extended == nil {
+			extended = make(map[string]int64, len(r.Extended))
+		}
+		extended[name] = addIfSet(value, other.Extended[name])
+	}
+	return Resource{
+		CPU:              addIfSet(r.CPU, other.CPU),
+		Memory:           addIfSet(r.Memory, other.Memory),
+		Storage:          addIfSet(r.Storage, other.Storage),
+		StorageEphemeral: addIfSet(r.StorageEphemeral, other.StorageEphemeral),
+		Extended:         extended,
+	}
+}
+
+// effective aggregates container resources the way the scheduler does:
+// application containers and sidecars run together, while every init
+// container runs alone next to the sidecars started before it. The pod needs
+// the larger of both phases. Ephemeral containers are not accounted.
+func effective(containers []ContainerResource, value func(ContainerResource) Resource) Resource {
+	var running, sidecars, initPhase Resource
+	for _, container := range containers {
+		switch container.Type {
+		case InitContainer:
+			initPhase = initPhase.max(value(container).add(sidecars))
+		case SidecarContainer:
+			sidecars = sidecars.add(value(container))
+			running = running.add(value(container))
+			initPhase = initPhase.max(sidecars)
+		case EphemeralContainer:
+		default:
+			running = running.add(value(container))
+		}
+	}
+	return running.max(initPhase)
+}
+
+// EffectiveRequests returns the requests the scheduler reserves for the pod,
+// including init containers, sidecars and the RuntimeClass overhead.
+func (p PodResource) EffectiveRequests() Resource {
+	return effective(p.Containers, func(c ContainerResource) Resource { return c.Requests }).add(p.Overhead)
+}
+
+// EffectiveLimits returns the pod limits computed like EffectiveRequests.
+// The overhead is added only to resources that have a limit set.
+func (p PodResource) EffectiveLimits() Resource {
+	return effective(p.Containers, func(c ContainerResource) Resource { return c.Limits }).addWhereSet(p.Overhead)
+}
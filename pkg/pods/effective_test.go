@@ -0,0 +1,120 @@
+package pods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectiveRequests(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pod      PodResource
+		expected Resource
+	}{
+		{
+			name:     "no containers",
+			pod:      PodResource{},
+			expected: Resource{},
+		},
+		{
+			name: "application containers are summed",
+			pod: PodResource{Containers: []ContainerResource{
+				{Name: "a", Requests: Resource{CPU: 100, Memory: 128}},
+				{Name: "b", Requests: Resource{CPU: 200, Memory: 256}},
+			}},
+			expected: Resource{CPU: 300, Memory: 384},
+		},
+		{
+			name: "init container larger than application containers wins",
+			pod: PodResource{Containers: []ContainerResource{
+				{Name: "app", Requests: Resource{CPU: 100, Memory: 128}},
+				{Name: "migrate", Type: InitContainer, Requests: Resource{CPU: 500, Memory: 64}},
+			}},
+			expected: Resource{CPU: 500, Memory: 128},
+		},
+		{
+			name: "sidecars are added to application containers",
+			pod: PodResource{Containers: []ContainerResource{
+				{Name: "app", Requests: Resource{CPU: 100, Memory: 128}},
+				{Name: "proxy", Type: SidecarContainer, Requests: Resource{CPU: 50, Memory: 64}},
+			}},
+			expected: Resource{CPU: 150, Memory: 192},
+		},
+		{
+			name: "init container runs next to sidecars started before it",
+			pod: PodResource{Containers: []ContainerResource{
+				{Name: "app", Requests: Resource{CPU: 100}},
+				{Name: "proxy", Type: SidecarContainer, Requests: Resource{CPU: 50}},
+				{Name: "migrate", Type: InitContainer, Requests: Resource{CPU: 200}},
+			}},
+			expected: Resource{CPU: 250},
+		},
+		{
+			name: "init container ignores sidecars started after it",
+			pod: PodResource{Containers: []ContainerResource{
+				{Name: "app", Requests: Resource{CPU: 100}},
+				{Name: "migrate", Type: InitContainer, Requests: Resource{CPU: 200}},
+				{Name: "proxy", Type: SidecarContainer, Requests: Resource{CPU: 50}},
+			}},
+			expected: Resource{CPU: 200},
+		},
+		{
+			name: "extended resources follow the same rules",
+			pod: PodResource{Containers: []ContainerResource{
+				{Name: "app", Requests: Resource{Extended: map[string]int64{"nvidia.com/gpu": 1}}},
+				{Name: "worker", Requests: Resource{Extended: map[string]int64{"nvidia.com/gpu": 1, "hugepages-2Mi": 1024}}},
+				{Name: "warmup", Type: InitContainer, Requests: Resource{Extended: map[string]int64{"hugepages-2Mi": 4096}}},
+			}},
+			expected: Resource{Extended: map[string]int64{"nvidia.com/gpu": 2, "hugepages-2Mi": 4096}},
+		},
+		{
+			name: "ephemeral containers are ignored",
+			pod: PodResource{Containers: []ContainerResource{
+				{Name: "app", Requests: Resource{CPU: 100}},
+				{Name: "debugger", Type: EphemeralContainer, Requests: Resource{CPU: 300}},
+			}},
+			expected: Resource{CPU: 100},
+		},
+		{
+			name: "overhead is added",
+			pod: PodResource{
+				Containers: []ContainerResource{{Name: "app", Requests: Resource{CPU: 100, Memory: 128}}},
+				Overhead:   Resource{CPU: 10, Memory: 32},
+			},
+			expected: Resource{CPU: 110, Memory: 160},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.pod.EffectiveRequests())
+		})
+	}
+}
+
+func TestEffectiveLimits(t *testing.T) {
+	t.Run("init container limit wins", func(t *testing.T) {
+		pod := PodResource{Containers: []ContainerResource{
+			{Name: "app", Limits: Resource{CPU: 200, Memory: 256}},
+			{Name: "migrate", Type: InitContainer, Limits: Resource{CPU: 1000}},
+		}}
+		require.Equal(t, Resource{CPU: 1000, Memory: 256}, pod.EffectiveLimits())
+	})
+
+	t.Run("overhead is added only to limited resources", func(t *testing.T) {
+		pod := PodResource{
+			Containers: []ContainerResource{{Name: "app", Limits: Resource{Memory: 256}}},
+			Overhead:   Resource{CPU: 10, Memory: 32},
+		}
+		require.Equal(t, Resource{Memory: 288}, pod.EffectiveLimits())
+	})
+
+	t.Run("overhead is added to limited extended resources", func(t *testing.T) {
+		pod := PodResource{
+			Containers: []ContainerResource{{Name: "app", Limits: Resource{Extended: map[string]int64{"nvidia.com/gpu": 1}}}},
+			Overhead:   Resource{Extended: map[string]int64{"nvidia.com/gpu": 0, "example.com/foo": 1}},
+		}
+		require.Equal(t, Resource{Extended: map[string]int64{"nvidia.com/gpu": 1}}, pod.EffectiveLimits())
+	})
+}
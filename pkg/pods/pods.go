@@ -3,11 +3,13 @@ package pods
 import (
 	"cmp"
 	"context"
-	"errors"
 	"fmt"
 	"slices"
 	"sync"
+	"time"
 
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	v1 "k8s.io/api/core/v1" //nolint:revive // it is used
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -18,12 +20,62 @@ type Resource struct {
 	Memory           int64 `json:"memory,omitempty" yaml:"memory,omitempty"`
 	Storage          int64 `json:"storage,omitempty" yaml:"storage,omitempty"`
 	StorageEphemeral int64 `json:"storage_ephemeral,omitempty" yaml:"storage_ephemeral,omitempty"`
+	// Extended holds any other resource, for example nvidia.com/gpu or
+	// hugepages-2Mi (in bytes), keyed by resource name.
+	Extended map[string]int64 `json:"extended,omitempty" yaml:"extended,omitempty"`
+}
+
+// ContainerType distinguishes init and sidecar containers from regular ones.
+// Regular application containers leave it empty.
+type ContainerType string
+
+const (
+	// InitContainer runs to completion before the application containers start.
+	InitContainer ContainerType = "init"
+	// SidecarContainer is an init container with restartPolicy Always that keeps
+	// running next to the application containers.
+	SidecarContainer ContainerType = "sidecar"
+	// EphemeralContainer is a debug container added to a running pod. It has no
+	// resources and is not accounted by the scheduler.
+	EphemeralContainer ContainerType = "ephemeral"
+)
+
+// OOMKilled is the termination reason of a container killed for exceeding its
+// memory limit.
+const OOMKilled = "OOMKilled"
+
+// Termination describes how a container run ended.
+type Termination struct {
+	Reason     string    `json:"reason,omitempty" yaml:"reason,omitempty"`
+	ExitCode   int32     `json:"exit_code" yaml:"exit_code"`
+	FinishedAt time.Time `json:"finished_at,omitzero" yaml:"finished_at,omitempty"`
+}
+
+// IsOOMKilledSince reports whether the run was OOMKilled at or after since.
+func (t *Termination) IsOOMKilledSince(since time.Time) bool {
+	return t != nil && t.Reason == OOMKilled && !t.FinishedAt.Before(since)
 }
 
 type ContainerResource struct {
-	Name     string   `json:"name,omitempty" yaml:"name,omitempty"`
-	Limits   Resource `json:"limits" yaml:"limits"`
-	Requests Resource `json:"requests" yaml:"requests"`
+	Name     string        `json:"name,omitempty" yaml:"name,omitempty"`
+	Type     ContainerType `json:"type,omitempty" yaml:"type,omitempty"`
+	Limits   Resource      `json:"limits" yaml:"limits"`
+	Requests Resource      `json:"requests" yaml:"requests"`
+	// Restarts, State and the termination fields come from the pod status.
+	// State is shown the kubectl way: Running, the waiting reason such as
+	// CrashLoopBackOff or the termination reason such as OOMKilled.
+	Restarts int32  `json:"restarts,omitempty" yaml:"restarts,omitempty"`
+	State    string `json:"state,omitempty" yaml:"state,omitempty"`
+	// Terminated is set while the container is in the terminated state.
+	Terminated *Termination `json:"terminated,omitempty" yaml:"terminated,omitempty"`
+	// LastTermination is the previous run of a restarted container.
+	LastTermination *Termination `json:"last_termination,omitempty" yaml:"last_termination,omitempty"`
+}
+
+// IsOOMKilledSince reports whether the current or the previous run of the
+// container was OOMKilled at or after since.
+func (c ContainerResource) IsOOMKilledSince(since time.Time) bool {
+	return c.Terminated.IsOOMKilledSince(since) || c.LastTermination.IsOOMKilledSince(since)
 }
 
 type NamespaceName struct {
@@ -31,14 +83,36 @@ type NamespaceName struct {
 	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
 }
 
+// Owner is the controller of a pod taken from its ownerReferences.
+type Owner struct {
+	Kind string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
 type PodResource struct {
 	NamespaceName `json:"namespace_name" yaml:"namespace_name"`
-	NodeName      string              `json:"node_name,omitempty" yaml:"node_name,omitempty"`
-	Containers    []ContainerResource `json:"containers,omitempty" yaml:"containers,omitempty"`
+	NodeName      string      `json:"node_name,omitempty" yaml:"node_name,omitempty"`
+	Phase         v1.PodPhase `json:"phase,omitempty" yaml:"phase,omitempty"`
+	// QOSClass decides the eviction order under node pressure: BestEffort
+	// pods go first, then Burstable ones.
+	QOSClass v1.PodQOSClass `json:"qos_class,omitempty" yaml:"qos_class,omitempty"`
+	// Owner is empty for pods without a controller.
+	Owner Owner `json:"owner,omitempty" yaml:"owner,omitempty"`
+	// Containers lists application containers sorted by name followed by
+	// init, sidecar and ephemeral containers in their declaration order.
+	Containers []ContainerResource `json:"containers,omitempty" yaml:"containers,omitempty"`
+	// Overhead is the RuntimeClass pod overhead accounted on top of containers.
+	Overhead Resource `json:"overhead" yaml:"overhead"`
 }
 
 type PodResourceList []PodResource
 
+// IsTerminated reports whether the pod has finished (Succeeded or Failed).
+// Terminated pods keep their node binding but no longer hold node resources.
+func (p PodResource) IsTerminated() bool {
+	return p.Phase == v1.PodSucceeded || p.Phase == v1.PodFailed
+}
+
 type PodFilter struct {
 	Namespaces    []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
 	LabelSelector string   `json:"label_selector,omitempty" yaml:"label_selector,omitempty"`
@@ -46,54 +120,53 @@ type PodFilter struct {
 	NodeName      string   `json:"node,omitempty" yaml:"node,omitempty"`
 }
 
-func extractContainerResources(container v1.Container) ContainerResource {
-	limits := container.Resources.Limits
-	requests := container.Resources.Requests
-	containerResource := ContainerResource{
-		Name: container.Name,
-	}
-
-	// Extract limits
-	if cpuLimit, ok := limits[v1.ResourceCPU]; ok {
-		containerResource.Limits.CPU = cpuLimit.MilliValue()
+func extractResource(resources v1.ResourceList) Resource {
+	var resource Resource
+	if cpu, ok := resources[v1.ResourceCPU]; ok {
+		resource.CPU = cpu.MilliValue()
 	}
-	if memoryLimit, ok := limits[v1.ResourceMemory]; ok {
-		if memory, ok := memoryLimit.AsInt64(); ok {
-			containerResource.Limits.Memory = memory
+	if memory, ok := resources[v1.ResourceMemory]; ok {
+		if value, ok := memory.AsInt64(); ok {
+			resource.Memory = value
 		}
 	}
-	if storageLimit, ok := limits[v1.ResourceStorage]; ok {
-		if storage, ok := storageLimit.AsInt64(); ok {
-			containerResource.Limits.Storage = storage
+	if storage, ok := resources[v1.ResourceStorage]; ok {
+		if value, ok := storage.AsInt64(); ok {
+			resource.Storage = value
 		}
 	}
-	if storageEphemeralLimit, ok := limits[v1.ResourceEphemeralStorage]; ok {
-		if storage, ok := storageEphemeralLimit.AsInt64(); ok {
-			containerResource.Limits.StorageEphemeral = storage
+	if storageEphemeral, ok := resources[v1.ResourceEphemeralStorage]; ok {
+		if value, ok := storageEphemeral.AsInt64(); ok {
+			resource.StorageEphemeral = value
 		}
 	}
-
-	// Extract requests
-	if cpuRequest, ok := requests[v1.ResourceCPU]; ok {
-		containerResource.Requests.CPU = cpuRequest.MilliValue()
-	}
-	if memoryRequest, ok := requests[v1.ResourceMemory]; ok {
-		if memory, ok := memoryRequest.AsInt64(); ok {
-			containerResource.Requests.Memory = memory
+	for name, quantity := range resources {
+		if !isExtendedResource(name) {
+			continue
 		}
-	}
-	if storageRequest, ok := requests[v1.ResourceStorage]; ok {
-		if storage, ok := storageRequest.AsInt64(); ok {
-			containerResource.Requests.Storage = storage
+		if resource.Extended == nil {
+			resource.Extended = make(map[string]int64)
 		}
+		resource.Extended[string(name)] = quantity.Value()
 	}
-	if storageEphemeralRequest, ok := requests[v1.ResourceEphemeralStorage]; ok {
-		if storage, ok := storageEphemeralRequest.AsInt64(); ok {
-			containerResource.Requests.StorageEphemeral = storage
-		}
+	return resource
+}
+
+func isExtendedResource(name v1.ResourceName) bool {
+	switch name {
+	case v1.ResourceCPU, v1.ResourceMemory, v1.ResourceStorage, v1.ResourceEphemeralStorage, v1.ResourcePods:
+		return false
+	default:
+		return true
 	}
+}
 
-	return containerResource
+func extractContainerResources(container v1.Container) ContainerResource {
+	return ContainerResource{
+		Name:     container.Name,
+		Limits:   extractResource(container.Resources.Limits),
+		Requests: extractResource(container.Resources.Requests),
+	}
 }
 
 func convertPodToResource(pod v1.Pod) PodResource {
@@ -103,9 +176,18 @@ func convertPodToResource(pod v1.Pod) PodResource {
 			Namespace: pod.Namespace,
 		},
 		NodeName: pod.Spec.NodeName,
+		Phase:    pod.Status.Phase,
+		QOSClass: podQOSClass(pod),
+	}
+	if controller := metav1.GetControllerOf(&pod); controller != nil {
+		podResource.Owner = Owner{Kind: controller.Kind, Name: controller.Name}
 	}
 
-	containers := make([]ContainerResource, 0, len(pod.Spec.Containers))
+	containers := make(
+		[]ContainerResource,
+		0,
+		len(pod.Spec.Containers)+len(pod.Spec.InitContainers)+len(pod.Spec.EphemeralContainers),
+	)
 	for _, container := range pod.Spec.Containers {
 		containers = append(containers, extractContainerResources(container))
 	}
@@ -114,27 +196,140 @@ func convertPodToResource(pod v1.Pod) PodResource {
 		return cmp.Compare(a.Name, b.Name)
 	})
 
+	// Init containers keep the spec order: the effective pod request depends on
+	// which sidecars were started before each init container.
+	for _, container := range pod.Spec.InitContainers {
+		containerResource := extractContainerResources(container)
+		containerResource.Type = InitContainer
+		if isSidecar(container) {
+			containerResource.Type = SidecarContainer
+		}
+		containers = append(containers, containerResource)
+	}
+
+	for _, container := range pod.Spec.EphemeralContainers {
+		containers = append(containers, ContainerResource{Name: container.Name, Type: EphemeralContainer})
+	}
+
+	applyContainerStatuses(containers, pod.Status)
 	podResource.Containers = containers
+	podResource.Overhead = extractResource(pod.Spec.Overhead)
 	return podResource
 }
 
+// applyContainerStatuses joins container statuses to containers by name.
+// Containers without a status yet, e.g. of a pending pod, are left as is.
+func applyContainerStatuses(containers []ContainerResource, status v1.PodStatus) {
+	statuses := slices.Concat(status.ContainerStatuses, status.InitContainerStatuses, status.EphemeralContainerStatuses)
+	byName := make(map[string]v1.ContainerStatus, len(statuses))
+	for _, containerStatus := range statuses {
+		byName[containerStatus.Name] = containerStatus
+	}
+	for idx := range containers {
+		containerStatus, ok := byName[containers[idx].Name]
+		if !ok {
+			continue
+		}
+		containers[idx].Restarts = containerStatus.RestartCount
+		containers[idx].State = containerState(containerStatus.State)
+		containers[idx].Terminated = termination(containerStatus.State.Terminated)
+		containers[idx].LastTermination = termination(containerStatus.LastTerminationState.Terminated)
+	}
+}
+
+func containerState(state v1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running"
+	case state.Waiting != nil:
+		return cmp.Or(state.Waiting.Reason, "Waiting")
+	case state.Terminated != nil:
+		return cmp.Or(state.Terminated.Reason, "Terminated")
+	default:
+		return ""
+	}
+}
+
+func termination(state *v1.ContainerStateTerminated) *Termination {
+	if state == nil {
+		return nil
+	}
+	return &Termination{
+		Reason:     state.Reason,
+		ExitCode:   state.ExitCode,
+		FinishedAt: state.FinishedAt.Time,
+	}
+}
+
+// podQOSClass returns the QoS class from the pod status. Pods the API server
+// has not classified yet get it from their cpu and memory requests and limits
+// the way kubelet does.
+func podQOSClass(pod v1.Pod) v1.PodQOSClass {
+	if pod.Status.QOSClass != "" {
+		return pod.Status.QOSClass
+	}
+	hasResources := false
+	guaranteed := true
+	for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			request, hasRequest := container.Resources.Requests[name]
+			limit, hasLimit := container.Resources.Limits[name]
+			hasRequest = hasRequest && !request.IsZero()
+			hasLimit = hasLimit && !limit.IsZero()
+			if hasRequest || hasLimit {
+				hasResources = true
+			}
+			// A missing request defaults to the limit.
+			if !hasLimit || (hasRequest && request.Cmp(limit) != 0) {
+				guaranteed = false
+			}
+		}
+	}
+	switch {
+	case !hasResources:
+		return v1.PodQOSBestEffort
+	case guaranteed:
+		return v1.PodQOSGuaranteed
+	default:
+		return v1.PodQOSBurstable
+	}
+}
+
+func isSidecar(container v1.Container) bool {
+	return container.RestartPolicy != nil && *container.RestartPolicy == v1.ContainerRestartPolicyAlways
+}
+
+// listFunc lists the pods of filter in namespace.
+type listFunc func(ctx context.Context, filter PodFilter, namespace string) (PodResourceList, error)
+
 func Pods(
 	ctx context.Context,
 	coreV1Ifc corev1.CoreV1Interface,
 	filter PodFilter,
 	nodeNames ...string,
+) (PodResourceList, error) {
+	return podsFor(ctx, filter, nodeNames, func(ctx context.Context, filter PodFilter, namespace string) (PodResourceList, error) {
+		return listPods(ctx, coreV1Ifc, filter, namespace)
+	})
+}
+
+func podsFor(
+	ctx context.Context,
+	filter PodFilter,
+	nodeNames []string,
+	list listFunc,
 ) (PodResourceList, error) {
 	nodeNames = slices.DeleteFunc(nodeNames, func(n string) bool { return n == "" })
 	filter.Namespaces = slices.DeleteFunc(filter.Namespaces, func(n string) bool { return n == "" })
 
 	// If no namespaces specified, query all namespaces (empty string)
 	if len(filter.Namespaces) == 0 {
-		return podsForNamespace(ctx, coreV1Ifc, filter, nodeNames, "")
+		return podsForNamespace(ctx, filter, nodeNames, "", list)
 	}
 
 	// Single namespace
 	if len(filter.Namespaces) == 1 {
-		return podsForNamespace(ctx, coreV1Ifc, filter, nodeNames, filter.Namespaces[0])
+		return podsForNamespace(ctx, filter, nodeNames, filter.Namespaces[0], list)
 	}
 
 	// Multiple namespaces: query each in parallel
@@ -145,7 +340,7 @@ func Pods(
 
 	for idx, ns := range filter.Namespaces {
 		wg.Go(func() {
-			pods[idx], rErrors[idx] = podsForNamespace(ctx, coreV1Ifc, filter, nodeNames, ns)
+			pods[idx], rErrors[idx] = podsForNamespace(ctx, filter, nodeNames, ns, list)
 			if rErrors[idx] != nil && len(nodeNames) == 0 {
 				rErrors[idx] = fmt.Errorf("list pods for namespace %q: %w", ns, rErrors[idx])
 			}
@@ -154,22 +349,18 @@ func Pods(
 
 	wg.Wait()
 
-	if err := errors.Join(rErrors...); err != nil {
-		return nil, err
-	}
-
-	return slices.Concat(pods...), nil
+	return partial.Join(ctx, partial.Namespace, filter.Namespaces, pods, rErrors)
 }
 
 func podsForNamespace(
 	ctx context.Context,
-	coreV1Ifc corev1.CoreV1Interface,
 	filter PodFilter,
 	nodeNames []string,
 	namespace string,
+	list listFunc,
 ) (PodResourceList, error) {
 	if len(nodeNames) == 0 {
-		return listPods(ctx, coreV1Ifc, filter, namespace)
+		return list(ctx, filter, namespace)
 	}
 
 	var wg sync.WaitGroup
@@ -181,7 +372,7 @@ func podsForNamespace(
 		wg.Go(func() {
 			nodeFilter := filter
 			nodeFilter.NodeName = nodeName
-			pods[idx], rErrors[idx] = listPods(ctx, coreV1Ifc, nodeFilter, namespace)
+			pods[idx], rErrors[idx] = list(ctx, nodeFilter, namespace)
 			if rErrors[idx] != nil {
 				rErrors[idx] = wrapListPodsError(rErrors[idx], namespace, nodeName)
 			}
@@ -190,11 +381,7 @@ func podsForNamespace(
 
 	wg.Wait()
 
-	if err := errors.Join(rErrors...); err != nil {
-		return nil, err
-	}
-
-	return slices.Concat(pods...), nil
+	return partial.Join(ctx, partial.Node, nodeNames, pods, rErrors)
 }
 
 func listPods(
@@ -207,21 +394,22 @@ func listPods(
 		LabelSelector: filter.LabelSelector,
 		FieldSelector: buildFieldSelector(filter),
 	}
-	result := PodResourceList{}
-	for {
+	pods, err := retry.List(ctx, opts, func(ctx context.Context, opts metav1.ListOptions) ([]v1.Pod, string, error) {
 		pods, err := coreV1Ifc.Pods(namespace).List(ctx, opts)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
+		return pods.Items, pods.Continue, nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		for _, pod := range pods.Items {
-			result = append(result, convertPodToResource(pod))
-		}
-		if pods.Continue == "" {
-			return result, nil
-		}
-		opts.Continue = pods.Continue
+	result := make(PodResourceList, 0, len(pods))
+	for _, pod := range pods {
+		result = append(result, convertPodToResource(pod))
 	}
+	return result, nil
 }
 
 func buildFieldSelector(filter PodFilter) string {
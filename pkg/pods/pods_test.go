@@ -1,11 +1,16 @@
 package pods
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/partial"
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -91,6 +96,24 @@ func TestExtractContainerResources(t *testing.T) {
 		result := extractContainerResources(container)
 		require.Equal(t, int64(10*1024*1024*1024), result.Limits.Storage)
 		require.Equal(t, int64(5*1024*1024*1024), result.Limits.StorageEphemeral)
+		require.Nil(t, result.Limits.Extended)
+	})
+
+	t.Run("with extended resources", func(t *testing.T) {
+		container := v1.Container{
+			Name: "gpu-container",
+			Resources: v1.ResourceRequirements{
+				Limits: v1.ResourceList{
+					"nvidia.com/gpu":  resource.MustParse("2"),
+					"hugepages-2Mi":   resource.MustParse("64Mi"),
+					v1.ResourceCPU:    resource.MustParse("1"),
+					v1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		}
+		result := extractContainerResources(container)
+		require.Equal(t, int64(1000), result.Limits.CPU)
+		require.Equal(t, map[string]int64{"nvidia.com/gpu": 2, "hugepages-2Mi": 64 * 1024 * 1024}, result.Limits.Extended)
 	})
 }
 
@@ -114,14 +137,33 @@ func TestConvertPodToResource(t *testing.T) {
 					},
 				},
 			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
 		}
 		result := convertPodToResource(pod)
+		require.Equal(t, v1.PodRunning, result.Phase)
 		require.Equal(t, "test-pod", result.Name)
 		require.Equal(t, "test-ns", result.Namespace)
 		require.Equal(t, "worker-1", result.NodeName)
 		require.Len(t, result.Containers, 1)
 		require.Equal(t, "main", result.Containers[0].Name)
 		require.Equal(t, int64(100), result.Containers[0].Requests.CPU)
+		require.Equal(t, Owner{}, result.Owner)
+	})
+
+	t.Run("controller owner", func(t *testing.T) {
+		isController := true
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "web-5d4f8-abcde",
+				Namespace: "test-ns",
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "ConfigMap", Name: "not-a-controller"},
+					{Kind: "ReplicaSet", Name: "web-5d4f8", Controller: &isController},
+				},
+			},
+		}
+		result := convertPodToResource(pod)
+		require.Equal(t, Owner{Kind: "ReplicaSet", Name: "web-5d4f8"}, result.Owner)
 	})
 
 	t.Run("multiple containers sorted", func(t *testing.T) {
@@ -144,6 +186,237 @@ func TestConvertPodToResource(t *testing.T) {
 		require.Equal(t, "m-container", result.Containers[1].Name)
 		require.Equal(t, "z-container", result.Containers[2].Name)
 	})
+
+	t.Run("init and sidecar containers follow application containers", func(t *testing.T) {
+		always := v1.ContainerRestartPolicyAlways
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sidecar-pod",
+				Namespace: "default",
+			},
+			Spec: v1.PodSpec{
+				InitContainers: []v1.Container{
+					{Name: "z-migrate"},
+					{Name: "a-proxy", RestartPolicy: &always},
+				},
+				Containers: []v1.Container{
+					{Name: "main"},
+				},
+				Overhead: v1.ResourceList{
+					v1.ResourceCPU: resource.MustParse("10m"),
+				},
+			},
+		}
+		result := convertPodToResource(pod)
+		require.Len(t, result.Containers, 3)
+		require.Equal(t, "main", result.Containers[0].Name)
+		require.Empty(t, result.Containers[0].Type)
+		require.Equal(t, "z-migrate", result.Containers[1].Name)
+		require.Equal(t, InitContainer, result.Containers[1].Type)
+		require.Equal(t, "a-proxy", result.Containers[2].Name)
+		require.Equal(t, SidecarContainer, result.Containers[2].Type)
+		require.Equal(t, int64(10), result.Overhead.CPU)
+	})
+
+	t.Run("ephemeral containers follow init containers", func(t *testing.T) {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "debug-pod",
+				Namespace: "default",
+			},
+			Spec: v1.PodSpec{
+				Containers:     []v1.Container{{Name: "main"}},
+				InitContainers: []v1.Container{{Name: "migrate"}},
+				EphemeralContainers: []v1.EphemeralContainer{
+					{EphemeralContainerCommon: v1.EphemeralContainerCommon{Name: "debugger"}},
+				},
+			},
+		}
+		result := convertPodToResource(pod)
+		require.Len(t, result.Containers, 3)
+		require.Equal(t, "debugger", result.Containers[2].Name)
+		require.Equal(t, EphemeralContainer, result.Containers[2].Type)
+	})
+}
+
+func TestConvertPodToResourceContainerStatuses(t *testing.T) {
+	finishedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "test-ns"},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "init"}},
+			Containers:     []v1.Container{{Name: "app"}, {Name: "pending"}, {Name: "sidecar"}},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			InitContainerStatuses: []v1.ContainerStatus{
+				{
+					Name:  "init",
+					State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}},
+				},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					Name:         "app",
+					RestartCount: 3,
+					State:        v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+					LastTerminationState: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{
+							Reason:     OOMKilled,
+							ExitCode:   137,
+							FinishedAt: metav1.NewTime(finishedAt),
+						},
+					},
+				},
+				{
+					Name:         "sidecar",
+					RestartCount: 7,
+					State:        v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				},
+			},
+		},
+	}
+
+	result := convertPodToResource(pod)
+	require.Len(t, result.Containers, 4)
+
+	app := result.Containers[0]
+	require.Equal(t, "app", app.Name)
+	require.Equal(t, int32(3), app.Restarts)
+	require.Equal(t, "Running", app.State)
+	require.Nil(t, app.Terminated)
+	require.Equal(t, &Termination{Reason: OOMKilled, ExitCode: 137, FinishedAt: finishedAt}, app.LastTermination)
+	require.True(t, app.IsOOMKilledSince(finishedAt.Add(-time.Minute)))
+	require.False(t, app.IsOOMKilledSince(finishedAt.Add(time.Minute)))
+
+	pending := result.Containers[1]
+	require.Equal(t, "pending", pending.Name)
+	require.Empty(t, pending.State)
+	require.Zero(t, pending.Restarts)
+
+	sidecar := result.Containers[2]
+	require.Equal(t, "CrashLoopBackOff", sidecar.State)
+	require.Equal(t, int32(7), sidecar.Restarts)
+	require.Nil(t, sidecar.LastTermination)
+
+	initContainer := result.Containers[3]
+	require.Equal(t, InitContainer, initContainer.Type)
+	require.Equal(t, "Completed", initContainer.State)
+	require.Equal(t, &Termination{Reason: "Completed"}, initContainer.Terminated)
+	require.False(t, initContainer.IsOOMKilledSince(time.Time{}))
+}
+
+func TestTerminationIsOOMKilledSince(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		name        string
+		termination *Termination
+		expected    bool
+	}{
+		{name: "nil", termination: nil, expected: false},
+		{name: "recent oom", termination: &Termination{Reason: OOMKilled, FinishedAt: now}, expected: true},
+		{name: "old oom", termination: &Termination{Reason: OOMKilled, FinishedAt: now.Add(-2 * time.Hour)}, expected: false},
+		{name: "error", termination: &Termination{Reason: "Error", FinishedAt: now}, expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.termination.IsOOMKilledSince(now.Add(-time.Hour)))
+		})
+	}
+}
+
+func TestPodQOSClass(t *testing.T) {
+	resources := func(requests, limits string) v1.ResourceRequirements {
+		var result v1.ResourceRequirements
+		if requests != "" {
+			result.Requests = v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(requests),
+				v1.ResourceMemory: resource.MustParse(requests + "Mi"),
+			}
+		}
+		if limits != "" {
+			result.Limits = v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(limits),
+				v1.ResourceMemory: resource.MustParse(limits + "Mi"),
+			}
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name     string
+		pod      v1.Pod
+		expected v1.PodQOSClass
+	}{
+		{
+			name: "status wins",
+			pod: v1.Pod{
+				Spec:   v1.PodSpec{Containers: []v1.Container{{Name: "main"}}},
+				Status: v1.PodStatus{QOSClass: v1.PodQOSGuaranteed},
+			},
+			expected: v1.PodQOSGuaranteed,
+		},
+		{
+			name:     "no resources",
+			pod:      v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "main"}}}},
+			expected: v1.PodQOSBestEffort,
+		},
+		{
+			name: "limits only",
+			pod: v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+				{Name: "main", Resources: resources("", "1")},
+			}}},
+			expected: v1.PodQOSGuaranteed,
+		},
+		{
+			name: "requests equal limits",
+			pod: v1.Pod{Spec: v1.PodSpec{
+				InitContainers: []v1.Container{{Name: "init", Resources: resources("2", "2")}},
+				Containers:     []v1.Container{{Name: "main", Resources: resources("1", "1")}},
+			}},
+			expected: v1.PodQOSGuaranteed,
+		},
+		{
+			name: "requests below limits",
+			pod: v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+				{Name: "main", Resources: resources("1", "2")},
+			}}},
+			expected: v1.PodQOSBurstable,
+		},
+		{
+			name: "one container without limits",
+			pod: v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+				{Name: "main", Resources: resources("1", "1")},
+				{Name: "sidecar"},
+			}}},
+			expected: v1.PodQOSBurstable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, convertPodToResource(tc.pod).QOSClass)
+		})
+	}
+}
+
+func TestPodResourceIsTerminated(t *testing.T) {
+	testCases := []struct {
+		phase    v1.PodPhase
+		expected bool
+	}{
+		{phase: "", expected: false},
+		{phase: v1.PodPending, expected: false},
+		{phase: v1.PodRunning, expected: false},
+		{phase: v1.PodSucceeded, expected: true},
+		{phase: v1.PodFailed, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.phase), func(t *testing.T) {
+			require.Equal(t, tc.expected, PodResource{Phase: tc.phase}.IsTerminated())
+		})
+	}
 }
 
 func TestResourceStructs(t *testing.T) {
@@ -229,6 +502,23 @@ func TestListPodsFollowsPagination(t *testing.T) {
 	require.Equal(t, "pod-2", result[1].Name)
 }
 
+func TestListPodsRetries(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "default"}})
+	failures := 2
+	client.PrependReactor("list", "pods", func(ktesting.Action) (bool, runtime.Object, error) {
+		if failures == 0 {
+			return false, nil, nil
+		}
+		failures--
+		return true, nil, apierrors.NewTooManyRequests("slow down", 0)
+	})
+
+	result, err := listPods(retry.WithPolicy(t.Context(), retry.Policy{Retries: 2, Delay: time.Millisecond}), client.CoreV1(), PodFilter{}, "default")
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Zero(t, failures)
+}
+
 func TestPodsWrapsNamespaceErrors(t *testing.T) {
 	ctx := t.Context()
 	client := fake.NewSimpleClientset()
@@ -301,3 +591,35 @@ func TestPodsAvoidsDoubleWrappingNamespaceForNodeErrors(t *testing.T) {
 	require.NotContains(t, err.Error(), `list pods for namespace "team-a": list pods for namespace "team-a"`)
 	require.ErrorIs(t, err, expectedErr)
 }
+
+func TestPodsKeepsListedScopesWithPartialResults(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "healthy"}},
+	)
+	expectedErr := errors.New("forbidden")
+
+	type namespacedAction interface {
+		GetNamespace() string
+	}
+
+	client.PrependReactor("list", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+		listAction, ok := action.(namespacedAction)
+		require.True(t, ok)
+		if listAction.GetNamespace() == "broken" {
+			return true, nil, expectedErr
+		}
+		return false, nil, nil
+	})
+
+	result, err := partial.Collect(partial.Enable(t.Context()), func(ctx context.Context) (PodResourceList, error) {
+		return Pods(ctx, client.CoreV1(), PodFilter{Namespaces: []string{"healthy", "broken"}})
+	})
+	require.Len(t, result, 1)
+	require.Equal(t, "web", result[0].Name)
+	var partialErr *partial.Error
+	require.ErrorAs(t, err, &partialErr)
+	require.Len(t, partialErr.Warnings, 1)
+	require.Equal(t, partial.Namespace, partialErr.Warnings[0].Scope)
+	require.Equal(t, "broken", partialErr.Warnings[0].Name)
+	require.Contains(t, partialErr.Warnings[0].Message, "forbidden")
+}
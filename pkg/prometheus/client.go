@@ -0,0 +1,153 @@
+// Package prometheus reads container and node usage from the Prometheus HTTP
+// API. Only instant vector queries are supported, which is all usage needs.
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	statusSuccess      = "success"
+	resultTypeVector   = "vector"
+	maxErrorBodyLength = 512
+
+	millisecondsInSecond = 1000
+)
+
+// Sample is a single series of an instant vector. Timestamp is the query
+// evaluation time.
+type Sample struct {
+	Labels    map[string]string
+	Value     float64
+	Timestamp time.Time
+}
+
+type Vector []Sample
+
+type Client struct {
+	// URL is the Prometheus base URL, e.g. http://prometheus:9090.
+	URL        string
+	HTTPClient *http.Client
+}
+
+func NewClient(baseURL string) Client {
+	return Client{URL: baseURL, HTTPClient: http.DefaultClient}
+}
+
+type queryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string `json:"resultType"`
+		// Result is decoded once the result type is known to be a vector.
+		Result json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type vectorSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]any            `json:"value"`
+}
+
+// Query runs an instant query evaluated at the current time.
+func (c Client) Query(ctx context.Context, query string) (Vector, error) {
+	endpoint, err := url.JoinPath(c.URL, "api", "v1", "query")
+	if err != nil {
+		return nil, fmt.Errorf("prometheus url %q: %w", c.URL, err)
+	}
+	form := url.Values{"query": {query}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus query %q: %w", query, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read prometheus response: %w", err)
+	}
+	var response queryResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("prometheus query %q: %s: %s", query, resp.Status, truncate(string(body)))
+		}
+		return nil, fmt.Errorf("decode prometheus response: %w", err)
+	}
+	if response.Status != statusSuccess {
+		return nil, fmt.Errorf("prometheus query %q: %s: %s", query, response.ErrorType, response.Error)
+	}
+	if response.Data.ResultType != resultTypeVector {
+		return nil, fmt.Errorf("prometheus query %q: unexpected result type %q, want %q", query, response.Data.ResultType, resultTypeVector)
+	}
+
+	var results []vectorSample
+	if err := json.Unmarshal(response.Data.Result, &results); err != nil {
+		return nil, fmt.Errorf("decode prometheus vector: %w", err)
+	}
+	vector := make(Vector, 0, len(results))
+	for _, result := range results {
+		value, timestamp, err := sampleValue(result.Value)
+		if err != nil {
+			return nil, fmt.Errorf("prometheus query %q: %w", query, err)
+		}
+		vector = append(vector, Sample{Labels: result.Metric, Value: value, Timestamp: timestamp})
+	}
+	return vector, nil
+}
+
+// sampleValue parses the [timestamp, "value"] pair of a sample, the
+// timestamp being fractional unix seconds.
+func sampleValue(pair [2]any) (float64, time.Time, error) {
+	raw, ok := pair[1].(string)
+	if !ok {
+		return 0, time.Time{}, errors.New("sample value is not a string")
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	var timestamp time.Time
+	if seconds, ok := pair[0].(float64); ok {
+		timestamp = time.UnixMilli(int64(seconds * millisecondsInSecond))
+	}
+	return value, timestamp, nil
+}
+
+func truncate(s string) string {
+	if len(s) > maxErrorBodyLength {
+		return s[:maxErrorBodyLength] + "..."
+	}
+	return s
+}
+
+var windowPattern = regexp.MustCompile(`^([0-9]+(ms|s|m|h|d|w|y))+$`)
+
+// ValidWindow checks a PromQL range duration such as 5m or 1h30m.
+func ValidWindow(window string) error {
+	if !windowPattern.MatchString(window) {
+		return fmt.Errorf("prometheus rate window %q should be a PromQL duration like 5m", window)
+	}
+	return nil
+}
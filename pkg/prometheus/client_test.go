@@ -0,0 +1,94 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newFakePrometheus serves /api/v1/query answering with responses keyed by the
+// query form value.
+func newFakePrometheus(t *testing.T, responses map[string]string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/query", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, r.ParseForm())
+		response, ok := responses[r.PostForm.Get("query")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, `{"status":"error","errorType":"bad_data","error":"unknown query %s"}`, r.PostForm.Get("query"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func vectorResponse(samples ...string) string {
+	return `{"status":"success","data":{"resultType":"vector","result":[` + strings.Join(samples, ",") + `]}}`
+}
+
+func TestQuery(t *testing.T) {
+	srv := newFakePrometheus(t, map[string]string{
+		"up": vectorResponse(
+			`{"metric":{"job":"a"},"value":[1700000000.1,"1"]}`,
+			`{"metric":{"job":"b"},"value":[1700000000.1,"0.5"]}`,
+		),
+		"scalar(1)": `{"status":"success","data":{"resultType":"scalar","result":[1700000000.1,"1"]}}`,
+		"bad":       vectorResponse(`{"metric":{},"value":[1700000000.1,1]}`),
+	})
+	client := NewClient(srv.URL)
+
+	t.Run("vector", func(t *testing.T) {
+		vector, err := client.Query(context.Background(), "up")
+		require.NoError(t, err)
+		evaluated := time.UnixMilli(1700000000100)
+		require.Equal(t, Vector{
+			{Labels: map[string]string{"job": "a"}, Value: 1, Timestamp: evaluated},
+			{Labels: map[string]string{"job": "b"}, Value: 0.5, Timestamp: evaluated},
+		}, vector)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		_, err := client.Query(context.Background(), "missing")
+		require.ErrorContains(t, err, "bad_data: unknown query missing")
+	})
+
+	t.Run("non vector result", func(t *testing.T) {
+		_, err := client.Query(context.Background(), "scalar(1)")
+		require.ErrorContains(t, err, `unexpected result type "scalar"`)
+	})
+
+	t.Run("non string sample value", func(t *testing.T) {
+		_, err := client.Query(context.Background(), "bad")
+		require.ErrorContains(t, err, "sample value is not a string")
+	})
+
+	t.Run("non json error body", func(t *testing.T) {
+		plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "gateway down", http.StatusBadGateway)
+		}))
+		defer plain.Close()
+		_, err := NewClient(plain.URL).Query(context.Background(), "up")
+		require.ErrorContains(t, err, "502 Bad Gateway: gateway down")
+	})
+}
+
+func TestValidWindow(t *testing.T) {
+	for _, window := range []string{"5m", "30s", "1h30m", "500ms"} {
+		require.NoError(t, ValidWindow(window), window)
+	}
+	for _, window := range []string{"", "5", "5 m", "m5", "-5m"} {
+		require.Error(t, ValidWindow(window), window)
+	}
+}
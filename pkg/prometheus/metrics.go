@@ -0,0 +1,277 @@
+package prometheus
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/nodemetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+)
+
+// WindowPlaceholder is replaced with the rate window in queries.
+const WindowPlaceholder = "$window"
+
+const (
+	DefaultWindow = "5m"
+	// DefaultPodCPUQuery returns CPU cores per container labelled by
+	// namespace, pod and container, as scraped from cAdvisor.
+	DefaultPodCPUQuery = `sum by (namespace, pod, container) (rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[$window]))`
+	// DefaultPodMemoryQuery returns the working set bytes per container.
+	DefaultPodMemoryQuery = `sum by (namespace, pod, container) (container_memory_working_set_bytes{container!="",container!="POD"})`
+	// DefaultNodeCPUQuery returns CPU cores per node from the root cgroup.
+	DefaultNodeCPUQuery = `sum by (node) (rate(container_cpu_usage_seconds_total{id="/"}[$window]))`
+	// DefaultNodeMemoryQuery returns the working set bytes per node from the root cgroup.
+	DefaultNodeMemoryQuery = `sum by (node) (container_memory_working_set_bytes{id="/"})`
+)
+
+const (
+	labelNamespace = "namespace"
+	labelPod       = "pod"
+	labelContainer = "container"
+	labelNode      = "node"
+
+	milliCoresInCore = 1000
+)
+
+// Queries holds the PromQL used to read usage. Pod queries must label samples
+// with namespace, pod and container, node queries with node. CPU queries return
+// cores, memory queries bytes. Empty queries fall back to the defaults.
+type Queries struct {
+	Window     string
+	PodCPU     string
+	PodMemory  string
+	NodeCPU    string
+	NodeMemory string
+}
+
+func (q Queries) withDefaults() Queries {
+	q.Window = cmp.Or(q.Window, DefaultWindow)
+	q.PodCPU = cmp.Or(q.PodCPU, DefaultPodCPUQuery)
+	q.PodMemory = cmp.Or(q.PodMemory, DefaultPodMemoryQuery)
+	q.NodeCPU = cmp.Or(q.NodeCPU, DefaultNodeCPUQuery)
+	q.NodeMemory = cmp.Or(q.NodeMemory, DefaultNodeMemoryQuery)
+	return q
+}
+
+// window returns the rate window as a duration, zero for windows using units
+// Go durations lack such as days.
+func (q Queries) window() time.Duration {
+	window, err := time.ParseDuration(q.Window)
+	if err != nil {
+		return 0
+	}
+	return window
+}
+
+func (q Queries) render(query string) string {
+	return strings.ReplaceAll(query, WindowPlaceholder, q.Window)
+}
+
+// Validate checks the rate window.
+func (q Queries) Validate() error {
+	return ValidWindow(q.withDefaults().Window)
+}
+
+type sharedQueriesKey struct{}
+
+// sharedQueries holds the results of the queries run within one request by
+// query.
+type sharedQueries struct {
+	mu    sync.Mutex
+	reads map[string]*queryRead
+}
+
+type queryRead struct {
+	once   sync.Once
+	vector Vector
+	err    error
+}
+
+// WithSharedQueries returns a context in which the usage queries run by
+// PodMetrics and NodeMetrics are sent once, e.g. for the per-namespace pod
+// metrics reads of one request. Callers must not modify the shared vectors.
+func WithSharedQueries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sharedQueriesKey{}, &sharedQueries{reads: map[string]*queryRead{}})
+}
+
+func (s *sharedQueries) read(query string) *queryRead {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	read, ok := s.reads[query]
+	if !ok {
+		read = &queryRead{}
+		s.reads[query] = read
+	}
+	return read
+}
+
+// sharedQuery runs query once per context made by WithSharedQueries.
+func (c Client) sharedQuery(ctx context.Context, query string) (Vector, error) {
+	shared, ok := ctx.Value(sharedQueriesKey{}).(*sharedQueries)
+	if !ok {
+		return c.Query(ctx, query)
+	}
+	read := shared.read(c.URL + "\x00" + query)
+	read.once.Do(func() {
+		read.vector, read.err = c.Query(ctx, query)
+	})
+	return read.vector, read.err
+}
+
+// queryPair runs the CPU and memory queries in parallel.
+func (c Client) queryPair(ctx context.Context, cpuQuery, memoryQuery string) (Vector, Vector, error) {
+	var (
+		cpu, memory       Vector
+		cpuErr, memoryErr error
+		wg                sync.WaitGroup
+	)
+	wg.Go(func() {
+		cpu, cpuErr = c.sharedQuery(ctx, cpuQuery)
+	})
+	wg.Go(func() {
+		memory, memoryErr = c.sharedQuery(ctx, memoryQuery)
+	})
+	wg.Wait()
+	if err := errors.Join(cpuErr, memoryErr); err != nil {
+		return nil, nil, err
+	}
+	return cpu, memory, nil
+}
+
+// PodMetrics reads container usage of pods in filter.Namespaces, or of all
+// pods. Label and field selectors cannot be applied to Prometheus series, pods
+// they exclude are dropped when metrics are joined with pods.
+func (c Client) PodMetrics(ctx context.Context, queries Queries, filter podmetrics.MetricFilter) (podmetrics.PodMetricList, error) {
+	queries = queries.withDefaults()
+	cpu, memory, err := c.queryPair(ctx, queries.render(queries.PodCPU), queries.render(queries.PodMemory))
+	if err != nil {
+		return nil, err
+	}
+	namespaces := slices.DeleteFunc(slices.Clone(filter.Namespaces), func(n string) bool { return n == "" })
+
+	type containerKey struct {
+		namespace, pod, container string
+	}
+	usage := make(map[containerKey]*podmetrics.Metric)
+	metric := func(sample Sample) *podmetrics.Metric {
+		key := containerKey{
+			namespace: sample.Labels[labelNamespace],
+			pod:       sample.Labels[labelPod],
+			container: sample.Labels[labelContainer],
+		}
+		if key.pod == "" || key.container == "" {
+			return nil
+		}
+		if len(namespaces) > 0 && !slices.Contains(namespaces, key.namespace) {
+			return nil
+		}
+		if _, ok := usage[key]; !ok {
+			usage[key] = &podmetrics.Metric{}
+		}
+		return usage[key]
+	}
+	for _, sample := range cpu {
+		if m := metric(sample); m != nil {
+			m.CPU = milliCores(sample.Value)
+		}
+	}
+	for _, sample := range memory {
+		if m := metric(sample); m != nil {
+			m.Memory = int64(sample.Value)
+		}
+	}
+
+	podsByName := make(map[containerKey]*podmetrics.PodMetric)
+	for key, m := range usage {
+		podKey := containerKey{namespace: key.namespace, pod: key.pod}
+		pod, ok := podsByName[podKey]
+		if !ok {
+			pod = &podmetrics.PodMetric{
+				Namespace: key.namespace,
+				Name:      key.pod,
+				Timestamp: evaluatedAt(cpu, memory),
+				Window:    queries.window(),
+			}
+			podsByName[podKey] = pod
+		}
+		pod.Containers = append(pod.Containers, podmetrics.ContainerMetric{Name: key.container, Metric: *m})
+	}
+	result := make(podmetrics.PodMetricList, 0, len(podsByName))
+	for _, pod := range podsByName {
+		slices.SortFunc(pod.Containers, func(a, b podmetrics.ContainerMetric) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+		result = append(result, *pod)
+	}
+	slices.SortFunc(result, func(a, b podmetrics.PodMetric) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	return result, nil
+}
+
+// NodeMetrics reads the usage of nodeNames, or of all nodes reported when
+// none is given.
+func (c Client) NodeMetrics(ctx context.Context, queries Queries, nodeNames ...string) (nodemetrics.List, error) {
+	queries = queries.withDefaults()
+	cpu, memory, err := c.queryPair(ctx, queries.render(queries.NodeCPU), queries.render(queries.NodeMemory))
+	if err != nil {
+		return nil, err
+	}
+	nodeNames = slices.DeleteFunc(slices.Clone(nodeNames), func(n string) bool { return n == "" })
+
+	usage := make(map[string]*nodemetrics.NodeMetric)
+	metric := func(sample Sample) *nodemetrics.NodeMetric {
+		name := sample.Labels[labelNode]
+		if name == "" || (len(nodeNames) > 0 && !slices.Contains(nodeNames, name)) {
+			return nil
+		}
+		if _, ok := usage[name]; !ok {
+			usage[name] = &nodemetrics.NodeMetric{
+				Name:      name,
+				Timestamp: evaluatedAt(cpu, memory),
+				Window:    queries.window(),
+			}
+		}
+		return usage[name]
+	}
+	for _, sample := range cpu {
+		if m := metric(sample); m != nil {
+			m.CPU = milliCores(sample.Value)
+		}
+	}
+	for _, sample := range memory {
+		if m := metric(sample); m != nil {
+			m.Memory = int64(sample.Value)
+		}
+	}
+
+	result := make(nodemetrics.List, 0, len(usage))
+	for _, m := range usage {
+		result = append(result, *m)
+	}
+	slices.SortFunc(result, func(a, b nodemetrics.NodeMetric) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return result, nil
+}
+
+// evaluatedAt returns the evaluation time of the queries, which is shared by
+// all samples of an instant query.
+func evaluatedAt(vectors ...Vector) time.Time {
+	for _, vector := range vectors {
+		if len(vector) > 0 {
+			return vector[0].Timestamp
+		}
+	}
+	return time.Time{}
+}
+
+func milliCores(cores float64) int64 {
+	return int64(math.Round(cores * milliCoresInCore))
+}
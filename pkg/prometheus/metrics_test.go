@@ -0,0 +1,136 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trezorg/k8spodsmetrics/pkg/nodemetrics"
+	"github.com/trezorg/k8spodsmetrics/pkg/podmetrics"
+)
+
+func TestQueriesWithDefaults(t *testing.T) {
+	queries := Queries{Window: "1m", NodeCPU: "custom"}.withDefaults()
+	require.Equal(t, "1m", queries.Window)
+	require.Equal(t, DefaultPodCPUQuery, queries.PodCPU)
+	require.Equal(t, "custom", queries.NodeCPU)
+	require.Equal(t, strings.ReplaceAll(DefaultPodCPUQuery, WindowPlaceholder, "1m"), queries.render(queries.PodCPU))
+
+	require.NoError(t, Queries{}.Validate())
+	require.Error(t, Queries{Window: "one minute"}.Validate())
+}
+
+func TestPodMetrics(t *testing.T) {
+	queries := Queries{PodCPU: "pod_cpu[$window]", PodMemory: "pod_memory"}
+	srv := newFakePrometheus(t, map[string]string{
+		"pod_cpu[5m]": vectorResponse(
+			`{"metric":{"namespace":"default","pod":"web","container":"app"},"value":[1,"0.2504"]}`,
+			`{"metric":{"namespace":"default","pod":"web","container":"sidecar"},"value":[1,"0.01"]}`,
+			`{"metric":{"namespace":"kube-system","pod":"dns","container":"coredns"},"value":[1,"0.003"]}`,
+			`{"metric":{"namespace":"default","pod":"web"},"value":[1,"1"]}`,
+		),
+		"pod_memory": vectorResponse(
+			`{"metric":{"namespace":"default","pod":"web","container":"app"},"value":[1,"1048576"]}`,
+			`{"metric":{"namespace":"kube-system","pod":"dns","container":"coredns"},"value":[1,"2048"]}`,
+		),
+	})
+	client := NewClient(srv.URL)
+	evaluated := time.UnixMilli(1000)
+
+	t.Run("all namespaces", func(t *testing.T) {
+		metrics, err := client.PodMetrics(context.Background(), queries, podmetrics.MetricFilter{})
+		require.NoError(t, err)
+		require.Equal(t, podmetrics.PodMetricList{
+			{Name: "web", Namespace: "default", Timestamp: evaluated, Window: 5 * time.Minute, Containers: []podmetrics.ContainerMetric{
+				{Name: "app", Metric: podmetrics.Metric{CPU: 250, Memory: 1048576}},
+				{Name: "sidecar", Metric: podmetrics.Metric{CPU: 10}},
+			}},
+			{Name: "dns", Namespace: "kube-system", Timestamp: evaluated, Window: 5 * time.Minute, Containers: []podmetrics.ContainerMetric{
+				{Name: "coredns", Metric: podmetrics.Metric{CPU: 3, Memory: 2048}},
+			}},
+		}, metrics)
+	})
+
+	t.Run("namespace filter", func(t *testing.T) {
+		metrics, err := client.PodMetrics(context.Background(), queries, podmetrics.MetricFilter{Namespaces: []string{"kube-system"}})
+		require.NoError(t, err)
+		require.Len(t, metrics, 1)
+		require.Equal(t, "dns", metrics[0].Name)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		_, err := client.PodMetrics(context.Background(), Queries{PodCPU: "unknown"}, podmetrics.MetricFilter{})
+		require.ErrorContains(t, err, "unknown query unknown")
+	})
+}
+
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestSharedQueries(t *testing.T) {
+	queries := Queries{PodCPU: "pod_cpu", PodMemory: "pod_memory"}
+	srv := newFakePrometheus(t, map[string]string{
+		"pod_cpu":    vectorResponse(`{"metric":{"namespace":"a","pod":"web","container":"app"},"value":[1,"0.1"]}`),
+		"pod_memory": vectorResponse(`{"metric":{"namespace":"b","pod":"db","container":"app"},"value":[1,"1024"]}`),
+	})
+	transport := &countingTransport{}
+	client := NewClient(srv.URL)
+	client.HTTPClient = &http.Client{Transport: transport}
+	ctx := WithSharedQueries(context.Background())
+
+	for _, namespace := range []string{"a", "b", "c"} {
+		_, err := client.PodMetrics(ctx, queries, podmetrics.MetricFilter{Namespaces: []string{namespace}})
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(2), transport.requests.Load(), "one cpu and one memory query per request")
+
+	metrics, err := client.PodMetrics(ctx, queries, podmetrics.MetricFilter{Namespaces: []string{"b"}})
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	require.Equal(t, "db", metrics[0].Name)
+
+	_, err = client.PodMetrics(context.Background(), queries, podmetrics.MetricFilter{})
+	require.NoError(t, err)
+	require.Equal(t, int32(4), transport.requests.Load(), "reads are not shared without the context")
+}
+
+func TestNodeMetrics(t *testing.T) {
+	queries := Queries{Window: "2m", NodeCPU: "node_cpu[$window]", NodeMemory: "node_memory"}
+	srv := newFakePrometheus(t, map[string]string{
+		"node_cpu[2m]": vectorResponse(
+			`{"metric":{"node":"node-b"},"value":[1,"1.5"]}`,
+			`{"metric":{"node":"node-a"},"value":[1,"0.5"]}`,
+		),
+		"node_memory": vectorResponse(
+			`{"metric":{"node":"node-a"},"value":[1,"4096"]}`,
+			`{"metric":{"node":"node-b"},"value":[1,"8192"]}`,
+		),
+	})
+	client := NewClient(srv.URL)
+	evaluated := time.UnixMilli(1000)
+
+	t.Run("all nodes", func(t *testing.T) {
+		metrics, err := client.NodeMetrics(context.Background(), queries)
+		require.NoError(t, err)
+		require.Equal(t, nodemetrics.List{
+			{Name: "node-a", CPU: 500, Memory: 4096, Timestamp: evaluated, Window: 2 * time.Minute},
+			{Name: "node-b", CPU: 1500, Memory: 8192, Timestamp: evaluated, Window: 2 * time.Minute},
+		}, metrics)
+	})
+
+	t.Run("named nodes", func(t *testing.T) {
+		metrics, err := client.NodeMetrics(context.Background(), queries, "node-b")
+		require.NoError(t, err)
+		require.Equal(t, nodemetrics.List{{Name: "node-b", CPU: 1500, Memory: 8192, Timestamp: evaluated, Window: 2 * time.Minute}}, metrics)
+	})
+}
@@ -0,0 +1,108 @@
+package quotas
+
+import (
+	"context"
+	"math"
+
+	"github.com/trezorg/k8spodsmetrics/pkg/retry"
+	v1 "k8s.io/api/core/v1" //nolint:revive // it is used
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// Quota is the hard limit and the amount used of a quota resource.
+type Quota struct {
+	Hard int64 `json:"hard" yaml:"hard"`
+	Used int64 `json:"used" yaml:"used"`
+	// Percent is Used relative to Hard, rounded to one decimal.
+	Percent float64 `json:"percent" yaml:"percent"`
+}
+
+// NewQuota returns a quota with its utilisation percentage. A zero hard
+// limit with anything used counts as fully used.
+func NewQuota(hard, used int64) Quota {
+	return Quota{Hard: hard, Used: used, Percent: Percent(used, hard)}
+}
+
+// Percent returns value relative to limit, rounded to one decimal.
+func Percent(value, limit int64) float64 {
+	if limit <= 0 {
+		if value > 0 {
+			return 100
+		}
+		return 0
+	}
+	return math.Round(float64(value)*1000/float64(limit)) / 10
+}
+
+// NamespaceQuota is the effective quota of a namespace. When several
+// ResourceQuotas constrain the same resource the one with the lowest hard
+// limit wins. Nil fields are not constrained. CPU is in millicores.
+type NamespaceQuota struct {
+	RequestsCPU    *Quota `json:"requests_cpu,omitempty" yaml:"requests_cpu,omitempty"`
+	LimitsCPU      *Quota `json:"limits_cpu,omitempty" yaml:"limits_cpu,omitempty"`
+	RequestsMemory *Quota `json:"requests_memory,omitempty" yaml:"requests_memory,omitempty"`
+	LimitsMemory   *Quota `json:"limits_memory,omitempty" yaml:"limits_memory,omitempty"`
+	Pods           *Quota `json:"pods,omitempty" yaml:"pods,omitempty"`
+}
+
+// NamespaceQuotas maps namespace names to their effective quota.
+type NamespaceQuotas map[string]NamespaceQuota
+
+// Quotas lists ResourceQuotas of the namespace (all namespaces when empty).
+func Quotas(ctx context.Context, client corev1.CoreV1Interface, namespace string) (NamespaceQuotas, error) {
+	items, err := retry.List(ctx, metav1.ListOptions{},
+		func(ctx context.Context, opts metav1.ListOptions) ([]v1.ResourceQuota, string, error) {
+			quotas, err := client.ResourceQuotas(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+			return quotas.Items, quotas.Continue, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	result := NamespaceQuotas{}
+	for _, quota := range items {
+		result.add(quota)
+	}
+	return result, nil
+}
+
+func (q NamespaceQuotas) add(quota v1.ResourceQuota) {
+	current := q[quota.Namespace]
+	for name, hard := range quota.Status.Hard {
+		used := quota.Status.Used[name]
+		switch name {
+		case v1.ResourceRequestsCPU, v1.ResourceCPU:
+			current.RequestsCPU = restrictive(current.RequestsCPU, hard.MilliValue(), used.MilliValue())
+		case v1.ResourceLimitsCPU:
+			current.LimitsCPU = restrictive(current.LimitsCPU, hard.MilliValue(), used.MilliValue())
+		case v1.ResourceRequestsMemory, v1.ResourceMemory:
+			current.RequestsMemory = restrictive(current.RequestsMemory, value(hard), value(used))
+		case v1.ResourceLimitsMemory:
+			current.LimitsMemory = restrictive(current.LimitsMemory, value(hard), value(used))
+		case v1.ResourcePods:
+			current.Pods = restrictive(current.Pods, value(hard), value(used))
+		default:
+			// object counts and other quota resources are not reported
+		}
+	}
+	q[quota.Namespace] = current
+}
+
+func restrictive(current *Quota, hard, used int64) *Quota {
+	if current != nil && current.Hard <= hard {
+		return current
+	}
+	quota := NewQuota(hard, used)
+	return &quota
+}
+
+func value(quantity resource.Quantity) int64 {
+	if result, ok := quantity.AsInt64(); ok {
+		return result
+	}
+	return int64(quantity.AsApproximateFloat64())
+}
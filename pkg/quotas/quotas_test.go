@@ -0,0 +1,111 @@
+package quotas
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func resourceQuota(namespace, name string, hard, used v1.ResourceList) *v1.ResourceQuota {
+	return &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Status:     v1.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+}
+
+func TestQuotas(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("effective quota per namespace", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			resourceQuota("team-a", "compute",
+				v1.ResourceList{
+					v1.ResourceRequestsCPU:    resource.MustParse("4"),
+					v1.ResourceLimitsCPU:      resource.MustParse("8"),
+					v1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+					v1.ResourcePods:           resource.MustParse("20"),
+					v1.ResourceServices:       resource.MustParse("5"),
+				},
+				v1.ResourceList{
+					v1.ResourceRequestsCPU:    resource.MustParse("1500m"),
+					v1.ResourceLimitsCPU:      resource.MustParse("3"),
+					v1.ResourceRequestsMemory: resource.MustParse("2Gi"),
+					v1.ResourcePods:           resource.MustParse("6"),
+				},
+			),
+			resourceQuota("team-a", "strict",
+				v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+				v1.ResourceList{v1.ResourceCPU: resource.MustParse("1500m")},
+			),
+			resourceQuota("team-b", "memory",
+				v1.ResourceList{v1.ResourceLimitsMemory: resource.MustParse("1Gi")},
+				v1.ResourceList{},
+			),
+		)
+
+		result, err := Quotas(ctx, client.CoreV1(), "")
+		require.NoError(t, err)
+		require.Equal(t, NamespaceQuotas{
+			"team-a": {
+				RequestsCPU:    &Quota{Hard: 2000, Used: 1500, Percent: 75},
+				LimitsCPU:      &Quota{Hard: 8000, Used: 3000, Percent: 37.5},
+				RequestsMemory: &Quota{Hard: 8 * 1024 * 1024 * 1024, Used: 2 * 1024 * 1024 * 1024, Percent: 25},
+				Pods:           &Quota{Hard: 20, Used: 6, Percent: 30},
+			},
+			"team-b": {
+				LimitsMemory: &Quota{Hard: 1024 * 1024 * 1024},
+			},
+		}, result)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("list", "resourcequotas", func(ktesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("forbidden")
+		})
+
+		_, err := Quotas(ctx, client.CoreV1(), "team-a")
+		require.ErrorContains(t, err, "forbidden")
+	})
+
+	t.Run("follows pagination", func(t *testing.T) {
+		client := fake.NewSimpleClientset()
+		calls := 0
+		client.PrependReactor("list", "resourcequotas", func(action ktesting.Action) (bool, runtime.Object, error) {
+			calls++
+			listAction, ok := action.(ktesting.ListActionImpl)
+			require.True(t, ok)
+			if listAction.ListOptions.Continue == "" {
+				return true, &v1.ResourceQuotaList{
+					ListMeta: metav1.ListMeta{Continue: "next"},
+					Items: []v1.ResourceQuota{*resourceQuota("a", "q",
+						v1.ResourceList{v1.ResourcePods: resource.MustParse("1")}, nil)},
+				}, nil
+			}
+			return true, &v1.ResourceQuotaList{
+				Items: []v1.ResourceQuota{*resourceQuota("b", "q",
+					v1.ResourceList{v1.ResourcePods: resource.MustParse("2")}, nil)},
+			}, nil
+		})
+
+		result, err := Quotas(ctx, client.CoreV1(), "")
+		require.NoError(t, err)
+		require.Equal(t, 2, calls)
+		require.Len(t, result, 2)
+	})
+}
+
+func TestPercent(t *testing.T) {
+	require.InDelta(t, 33.3, Percent(1, 3), 0.001)
+	require.InDelta(t, 100.0, Percent(5, 5), 0.001)
+	require.InDelta(t, 0.0, Percent(0, 0), 0.001)
+	require.InDelta(t, 100.0, Percent(1, 0), 0.001)
+	require.Equal(t, Quota{Hard: 4, Used: 3, Percent: 75}, NewQuota(4, 3))
+}
@@ -0,0 +1,186 @@
+// Package retry retries Kubernetes API requests failing with transient errors
+// such as 429 Too Many Requests or a 503 from a restarting aggregated API.
+//
+// The client-go REST client retries a response carrying a Retry-After header
+// by itself, up to 10 times, before the error gets here. Retries of this
+// package come on top: a request answered with Retry-After every time is sent
+// at most (Retries+1)*11 times, other transient errors Retries+1 times. All of
+// them stay within the request timeout.
+package retry
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+const (
+	DefaultRetries  = 3
+	DefaultDelay    = 250 * time.Millisecond
+	DefaultMaxDelay = 5 * time.Second
+)
+
+// Policy retries a request with exponential backoff and jitter: the n-th retry
+// waits a random delay between half and all of Delay*2^(n-1), at most
+// MaxDelay. A Retry-After sent by the server replaces the backoff delay, also
+// capped at MaxDelay.
+type Policy struct {
+	// Retries is the number of retries after the first attempt, zero disables
+	// retries.
+	Retries  int
+	Delay    time.Duration
+	MaxDelay time.Duration
+}
+
+// DefaultPolicy returns the policy used when none is configured.
+func DefaultPolicy() Policy {
+	return Policy{Retries: DefaultRetries, Delay: DefaultDelay, MaxDelay: DefaultMaxDelay}
+}
+
+type policyKey struct{}
+
+// WithPolicy returns a context whose API requests are retried with policy.
+func WithPolicy(ctx context.Context, policy Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, policy)
+}
+
+// FromContext returns the policy of ctx. Requests of a context without a
+// policy are not retried.
+func FromContext(ctx context.Context) Policy {
+	policy, _ := ctx.Value(policyKey{}).(Policy)
+	return policy
+}
+
+// Do calls request until it succeeds, fails with an error that is not worth
+// retrying or the retries of the ctx policy run out.
+func Do[T any](ctx context.Context, request func(context.Context) (T, error)) (T, error) {
+	r := retrier{policy: FromContext(ctx)}
+	for {
+		result, err := request(ctx)
+		if err == nil || !Retryable(err) {
+			return result, err
+		}
+		if waitErr := r.wait(ctx, err); waitErr != nil {
+			return result, waitErr
+		}
+	}
+}
+
+// List pages through a list. A failed page is retried as Do retries a
+// request. A 410 Gone, sent for an expired continue token, restarts the list
+// from the first page and counts as a retry.
+func List[T any](
+	ctx context.Context,
+	opts metav1.ListOptions,
+	page func(context.Context, metav1.ListOptions) (items []T, continueToken string, err error),
+) ([]T, error) {
+	r := retrier{policy: FromContext(ctx)}
+	var result []T
+	for {
+		items, continueToken, err := page(ctx, opts)
+		if err != nil {
+			gone := IsGone(err)
+			if !gone && !Retryable(err) {
+				return nil, err
+			}
+			if waitErr := r.wait(ctx, err); waitErr != nil {
+				return nil, waitErr
+			}
+			if gone {
+				slog.Debug("list expired, restarting", "error", err)
+				result = nil
+				opts.Continue = ""
+			}
+			continue
+		}
+		result = append(result, items...)
+		if continueToken == "" {
+			return result, nil
+		}
+		opts.Continue = continueToken
+	}
+}
+
+// Retryable reports whether err is transient: throttling, an unavailable or
+// timed out server, a server asking to retry later or a dropped connection.
+func Retryable(err error) bool {
+	if _, ok := apierrors.SuggestsClientDelay(err); ok {
+		return true
+	}
+	switch {
+	case apierrors.IsTooManyRequests(err),
+		apierrors.IsServiceUnavailable(err),
+		apierrors.IsServerTimeout(err),
+		apierrors.IsTimeout(err):
+		return true
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	default:
+		return utilnet.IsConnectionReset(err) || utilnet.IsConnectionRefused(err) || utilnet.IsProbableEOF(err)
+	}
+}
+
+// IsGone reports whether err is a 410 Gone, the answer to a list continued
+// with an expired token.
+func IsGone(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
+
+type retrier struct {
+	policy  Policy
+	retries int
+}
+
+// wait sleeps before the next retry. It returns err when the retries ran out
+// and the context cause when ctx is done first.
+func (r *retrier) wait(ctx context.Context, err error) error {
+	if r.retries >= r.policy.Retries {
+		return err
+	}
+	r.retries++
+	delay := r.policy.delay(r.retries)
+	if IsGone(err) {
+		delay = 0
+	} else if seconds, ok := apierrors.SuggestsClientDelay(err); ok && seconds > 0 {
+		delay = min(time.Duration(seconds)*time.Second, r.policy.maxDelay())
+	}
+	slog.Debug("retrying request", "retry", r.retries, "delay", delay, "error", err)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (p Policy) maxDelay() time.Duration {
+	return cmp.Or(p.MaxDelay, DefaultMaxDelay)
+}
+
+// delay is the jittered backoff before the given retry, counted from one. A
+// zero MaxDelay stands for DefaultMaxDelay.
+func (p Policy) delay(retry int) time.Duration {
+	if p.Delay <= 0 {
+		return 0
+	}
+	maxDelay := p.maxDelay()
+	backoff := p.Delay
+	for i := 1; i < retry && backoff < maxDelay; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, maxDelay)
+	half := backoff / 2
+	return half + rand.N(backoff-half+1) //nolint:gosec // jitter needs no secure randomness
+}
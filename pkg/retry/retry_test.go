@@ -0,0 +1,232 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+var podsResource = schema.GroupResource{Resource: "pods"}
+
+type listOptionsGetter interface {
+	GetListOptions() metav1.ListOptions
+}
+
+// failingPods answers pod lists with errs first and then with the pages,
+// keyed by continue token.
+func failingPods(t *testing.T, pages map[string]*v1.PodList, errs ...error) (*fake.Clientset, *[]string) {
+	t.Helper()
+	client := fake.NewSimpleClientset()
+	var tokens []string
+	client.PrependReactor("list", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+		listAction, ok := action.(listOptionsGetter)
+		require.True(t, ok)
+		token := listAction.GetListOptions().Continue
+		tokens = append(tokens, token)
+		if len(errs) > 0 {
+			err := errs[0]
+			errs = errs[1:]
+			return true, nil, err
+		}
+		return true, pages[token], nil
+	})
+	return client, &tokens
+}
+
+func listPodNames(ctx context.Context, client *fake.Clientset) ([]string, error) {
+	return List(ctx, metav1.ListOptions{}, func(ctx context.Context, opts metav1.ListOptions) ([]string, string, error) {
+		pods, err := client.CoreV1().Pods("default").List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		names := make([]string, 0, len(pods.Items))
+		for _, pod := range pods.Items {
+			names = append(names, pod.Name)
+		}
+		return names, pods.Continue, nil
+	})
+}
+
+func podList(next string, names ...string) *v1.PodList {
+	list := &v1.PodList{ListMeta: metav1.ListMeta{Continue: next}}
+	for _, name := range names {
+		list.Items = append(list.Items, v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}})
+	}
+	return list
+}
+
+func testContext(t *testing.T, retries int) context.Context {
+	t.Helper()
+	return WithPolicy(t.Context(), Policy{Retries: retries, Delay: time.Millisecond, MaxDelay: 2 * time.Millisecond})
+}
+
+func TestList(t *testing.T) {
+	pages := map[string]*v1.PodList{
+		"":       podList("page-2", "pod-1"),
+		"page-2": podList("", "pod-2"),
+	}
+
+	t.Run("retries transient errors", func(t *testing.T) {
+		client, tokens := failingPods(t, pages,
+			apierrors.NewTooManyRequests("slow down", 0),
+			apierrors.NewServiceUnavailable("metrics-server is restarting"),
+		)
+
+		names, err := listPodNames(testContext(t, 3), client)
+		require.NoError(t, err)
+		require.Equal(t, []string{"pod-1", "pod-2"}, names)
+		require.Equal(t, []string{"", "", "", "page-2"}, *tokens)
+	})
+
+	t.Run("restarts the list when the continue token expired", func(t *testing.T) {
+		client, _ := failingPods(t, pages)
+		expired := true
+		client.PrependReactor("list", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+			listAction, ok := action.(listOptionsGetter)
+			require.True(t, ok)
+			if listAction.GetListOptions().Continue == "page-2" && expired {
+				expired = false
+				return true, nil, apierrors.NewResourceExpired("continue token expired")
+			}
+			return false, nil, nil
+		})
+
+		names, err := listPodNames(testContext(t, 1), client)
+		require.NoError(t, err)
+		require.Equal(t, []string{"pod-1", "pod-2"}, names)
+	})
+
+	t.Run("gives up when retries run out", func(t *testing.T) {
+		unavailable := apierrors.NewServiceUnavailable("metrics-server is restarting")
+		client, tokens := failingPods(t, pages, unavailable, unavailable, unavailable)
+
+		_, err := listPodNames(testContext(t, 2), client)
+		require.True(t, apierrors.IsServiceUnavailable(err))
+		require.Len(t, *tokens, 3)
+	})
+
+	t.Run("does not retry without a policy", func(t *testing.T) {
+		client, tokens := failingPods(t, pages, apierrors.NewTooManyRequests("slow down", 0))
+
+		_, err := listPodNames(t.Context(), client)
+		require.True(t, apierrors.IsTooManyRequests(err))
+		require.Len(t, *tokens, 1)
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		client, tokens := failingPods(t, pages, apierrors.NewForbidden(podsResource, "", errors.New("rbac")))
+
+		_, err := listPodNames(testContext(t, 3), client)
+		require.True(t, apierrors.IsForbidden(err))
+		require.Len(t, *tokens, 1)
+	})
+}
+
+func TestDoHonoursRetryAfter(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}})
+	var calls []time.Time
+	client.PrependReactor("get", "nodes", func(ktesting.Action) (bool, runtime.Object, error) {
+		calls = append(calls, time.Now())
+		if len(calls) == 1 {
+			return true, nil, apierrors.NewTooManyRequests("slow down", 1)
+		}
+		return false, nil, nil
+	})
+
+	ctx := WithPolicy(t.Context(), Policy{Retries: 1, Delay: time.Millisecond, MaxDelay: 2 * time.Second})
+	node, err := Do(ctx, func(ctx context.Context) (*v1.Node, error) {
+		return client.CoreV1().Nodes().Get(ctx, "node-a", metav1.GetOptions{})
+	})
+	require.NoError(t, err)
+	require.Equal(t, "node-a", node.Name)
+	require.Len(t, calls, 2)
+	require.GreaterOrEqual(t, calls[1].Sub(calls[0]), time.Second)
+}
+
+func TestDoCapsRetryAfter(t *testing.T) {
+	calls := 0
+	start := time.Now()
+	_, err := Do(testContext(t, 1), func(context.Context) (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, apierrors.NewTooManyRequests("slow down", 60)
+		}
+		return calls, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestDoStopsWithContext(t *testing.T) {
+	expectedErr := errors.New("request timed out")
+	ctx, cancel := context.WithTimeoutCause(
+		WithPolicy(t.Context(), Policy{Retries: 3, Delay: time.Minute, MaxDelay: time.Minute}),
+		50*time.Millisecond,
+		expectedErr,
+	)
+	defer cancel()
+
+	_, err := Do(ctx, func(context.Context) (int, error) {
+		return 0, apierrors.NewServiceUnavailable("unavailable")
+	})
+	require.ErrorIs(t, err, expectedErr)
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "too many requests", err: apierrors.NewTooManyRequests("slow down", 0), expected: true},
+		{name: "service unavailable", err: apierrors.NewServiceUnavailable("unavailable"), expected: true},
+		{name: "server timeout", err: apierrors.NewServerTimeout(podsResource, "list", 1), expected: true},
+		{name: "gateway timeout", err: apierrors.NewTimeoutError("timeout", 0), expected: true},
+		{name: "internal error", err: apierrors.NewInternalError(errors.New("boom")), expected: false},
+		{name: "not found", err: apierrors.NewNotFound(podsResource, "web"), expected: false},
+		{name: "gone", err: apierrors.NewResourceExpired("expired"), expected: false},
+		{name: "canceled", err: context.Canceled, expected: false},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, Retryable(tt.err))
+		})
+	}
+}
+
+func TestPolicyDelay(t *testing.T) {
+	policy := Policy{Retries: 10, Delay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		retry int
+		max   time.Duration
+	}{
+		{retry: 1, max: 100 * time.Millisecond},
+		{retry: 2, max: 200 * time.Millisecond},
+		{retry: 3, max: 400 * time.Millisecond},
+		{retry: 5, max: time.Second},
+		{retry: 10, max: time.Second},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			delay := policy.delay(tt.retry)
+			require.GreaterOrEqual(t, delay, tt.max/2)
+			require.LessOrEqual(t, delay, tt.max)
+		}
+	}
+	require.Zero(t, Policy{}.delay(1))
+}